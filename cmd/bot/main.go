@@ -2,87 +2,142 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
-	"strconv"
+	"strings"
 	"syscall"
-	"time"
 
-	"github.com/fatih/color"
+	"github.com/XSAM/otelsql"
 	log "github.com/go-pkgz/lgr"
 	tbapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/jmoiron/sqlx"
+	"github.com/mezk/tg-reminder/internal/pkg/access"
+	"github.com/mezk/tg-reminder/internal/pkg/admin"
+	"github.com/mezk/tg-reminder/internal/pkg/api"
 	"github.com/mezk/tg-reminder/internal/pkg/bot"
+	"github.com/mezk/tg-reminder/internal/pkg/channel"
+	"github.com/mezk/tg-reminder/internal/pkg/cli"
+	"github.com/mezk/tg-reminder/internal/pkg/config"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/lifecycle"
 	"github.com/mezk/tg-reminder/internal/pkg/listener"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
 	"github.com/mezk/tg-reminder/internal/pkg/notifier"
+	"github.com/mezk/tg-reminder/internal/pkg/outbox"
+	"github.com/mezk/tg-reminder/internal/pkg/profile"
 	"github.com/mezk/tg-reminder/internal/pkg/sender"
 	"github.com/mezk/tg-reminder/internal/pkg/storage"
 	"github.com/mezk/tg-reminder/internal/pkg/storage/backuper"
-)
-
-const (
-	envDBFile                 = "DB_FILE"                   // database file path
-	envMigrations             = "MIGRATIONS"                // migration directory for goose
-	envDebug                  = "DEBUG"                     // whether to print debug logs
-	envTelegramAPIToken       = "TELEGRAM_APITOKEN"         // Telegram API token, received from Botfather
-	envTelegramBotAPIEndpoint = "TELEGRAM_BOT_API_ENDPOINT" // Telegram API Bot endpoint
-	envBackupRetention        = "BACKUP_RETENTION"          // backup retention interval
-	envBackupInterval         = "BACKUP_INTERVAL"           // backup interval
-	envBackupDir              = "BACKUP_DIR"                // backup files directory
+	"github.com/mezk/tg-reminder/internal/pkg/tracing"
+	"github.com/mezk/tg-reminder/internal/pkg/web"
+	"github.com/mezk/tg-reminder/internal/pkg/webhook"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 var revision = "local"
 
 func main() {
+	// output of operator subcommands is for scripts, so neither revision nor logs are written to stdout
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		logging.SetupOutput(os.Stderr, logging.FormatText, false)
+		if err := cli.Run(context.Background(), os.Args[1:], os.Getenv, openStorage, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	fmt.Printf("tg-reminder %s\n", revision)
 
-	if err := execute(); err != nil {
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		if err := configCommand(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := execute(os.Args[1:]); err != nil {
 		log.Printf("[ERROR] %v", err)
 		os.Exit(1)
 	}
 }
 
-func execute() error {
-	dbFile := os.Getenv(envDBFile)
+// configCommand handles "config" subcommand, "config check" validates configuration and prints it with masked secrets.
+func configCommand(args []string, w io.Writer) error {
+	if len(args) == 0 || args[0] != "check" {
+		return errors.New("usage: tg-reminder config check [flags]")
+	}
 
-	migrationsDir := "/srv/db/migrations" // default location
-	if dir := os.Getenv(envMigrations); dir != "" {
-		migrationsDir = os.Getenv(envMigrations)
+	cfg, err := config.Load(args[1:], os.Getenv)
+	if err != nil {
+		return fmt.Errorf("config is invalid:\n%w", err)
 	}
 
-	debug, err := strconv.ParseBool(os.Getenv(envDebug))
+	out, err := cfg.YAML()
 	if err != nil {
-		return fmt.Errorf("fail to parse %s env variable: %w", envDebug, err)
+		return err
 	}
 
-	tgAPIToken := os.Getenv(envTelegramAPIToken)
-	masked := []string{tgAPIToken}
-	if err = setupLog(debug, masked...); err != nil {
+	fmt.Fprintf(w, "config is valid\n\n%s", out)
+	return nil
+}
+
+func execute(args []string) error {
+	cfg, err := config.Load(args, os.Getenv)
+	if err != nil {
+		return fmt.Errorf("invalid config:\n%w", err)
+	}
+
+	if err = setupLog(cfg.LogFormat, cfg.Debug, cfg.Telegram.APIToken, cfg.Channels.SMTP.Password, cfg.Channels.Push.Token); err != nil {
 		return fmt.Errorf("fail to setup logger: %w", err)
 	}
 
-	log.Printf("[INFO start bot [Revision: %s, DBFile: %s, MigrationsDir: %s, Debug: %t]", revision, dbFile, migrationsDir, debug)
+	log.Printf("[INFO] start bot [Revision: %s, DBFile: %s, MigrationsDir: %s, Debug: %t, LogFormat: %s]", revision, cfg.DBFile, cfg.Migrations, cfg.Debug, cfg.LogFormat)
+	log.Printf("[INFO] access mode %s, owner %d, allowed users %v", cfg.Access.Mode, cfg.Access.OwnerID, cfg.Access.AllowedUsers)
 
-	botAPIEndpoint := os.Getenv(envTelegramBotAPIEndpoint)
-	if botAPIEndpoint == "" {
-		botAPIEndpoint = tbapi.APIEndpoint
+	adminIDs := cfg.AdminIDs()
+	accessCfg := access.Config{
+		Mode:    cfg.Access.Mode,
+		OwnerID: cfg.Access.OwnerID,
+		// admins are always allowed to work with the bot
+		AllowedUserIDs: append(append([]int64(nil), cfg.Access.AllowedUsers...), adminIDs...),
 	}
 
-	botAPI, err := tbapi.NewBotAPIWithAPIEndpoint(tgAPIToken, botAPIEndpoint)
+	botAPI, err := tbapi.NewBotAPIWithAPIEndpoint(cfg.Telegram.APIToken, cfg.Telegram.APIEndpoint)
 	if err != nil {
 		return fmt.Errorf("can't connect to telegram bot api: %w", err)
 	}
-	botAPI.Debug = debug
+	botAPI.Debug = cfg.Debug
+
+	// command menu and profile are nice to have, the bot works without them
+	if err = profile.New(botAPI, accessCfg.OwnerID, adminIDs).Register(); err != nil {
+		log.Printf("[WARN] failed to register bot commands and profile: %v", err)
+	}
 
-	db, err := sqlx.Connect("sqlite", dbFile)
+	// spans are exported in background, so tracing is set up before anything is traced and flushed after everything is stopped
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		SampleRatio: cfg.Tracing.SampleRatio,
+		Version:     revision,
+	})
 	if err != nil {
-		return fmt.Errorf("can't connect to database: %w", err)
+		return fmt.Errorf("can't setup tracing: %w", err)
 	}
+	log.Printf("[INFO] tracing exporter %s", cfg.Tracing.Exporter)
 
-	store, err := storage.NewSqllite(db, migrationsDir)
+	db, err := openDB(cfg.DBFile)
+	if err != nil {
+		return err
+	}
+
+	store, err := storage.NewSqllite(db, cfg.Migrations)
 	if err != nil {
-		return fmt.Errorf("failed to connect to sqlite %s: %v", dbFile, err)
+		return fmt.Errorf("failed to connect to sqlite %s: %v", cfg.DBFile, err)
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -95,65 +150,173 @@ func execute() error {
 		cancel()
 	}()
 
-	if backupDir := os.Getenv(envBackupDir); backupDir != "" {
-		var backupRetentionInterval time.Duration
-		if backupRetentionInterval, err = time.ParseDuration(os.Getenv(envBackupRetention)); err != nil {
-			return fmt.Errorf("can't parse backup retention interval: %w", err)
-		}
+	tgMessageSender := sender.New(botAPI)
 
-		var backupInterval time.Duration
-		if backupInterval, err = time.ParseDuration(os.Getenv(envBackupInterval)); err != nil {
-			return fmt.Errorf("can't parse backup interval: %w", err)
-		}
+	// notifications are delivered by user's channels, Telegram messages and webhooks are always available
+	channels := map[domain.Channel]channel.Channel{domain.ChannelWebhook: channel.NewWebhook(cfg.Channels.Timeout)}
+	if cfg.Channels.SMTP.Addr != "" {
+		channels[domain.ChannelEmail] = channel.NewEmail(channel.EmailConfig{
+			Addr:     cfg.Channels.SMTP.Addr,
+			Username: cfg.Channels.SMTP.Username,
+			Password: cfg.Channels.SMTP.Password,
+			From:     cfg.Channels.SMTP.From,
+			Timeout:  cfg.Channels.Timeout,
+		})
+	}
+	if cfg.Channels.Push.URL != "" {
+		channels[domain.ChannelPush] = channel.NewPush(cfg.Channels.Push.URL, cfg.Channels.Push.Token, cfg.Channels.Timeout)
+	}
+	channelRouter := channel.NewRouter(tgMessageSender, channels)
+
+	// bot responses and reminders are delivered via outbox to survive Telegram outages and restarts
+	messageOutbox := outbox.New(channelRouter, store, outbox.Config{
+		Interval:    cfg.Outbox.Interval,
+		BatchSize:   cfg.Outbox.BatchSize,
+		MaxAttempts: cfg.Outbox.MaxAttempts,
+		MaxBackoff:  cfg.Outbox.MaxBackoff,
+	})
+
+	// reminder lifecycle events are enqueued by storage along with reminder changes and delivered to webhooks in background
+	webhookDispatcher := webhook.New(store, webhook.Config{
+		Interval:    cfg.Webhooks.Interval,
+		BatchSize:   cfg.Webhooks.BatchSize,
+		MaxAttempts: cfg.Webhooks.MaxAttempts,
+		MaxBackoff:  cfg.Webhooks.MaxBackoff,
+		Timeout:     cfg.Webhooks.Timeout,
+	})
 
+	notificationSender := notifier.New(messageOutbox, store, notifier.Config{
+		Interval:   cfg.Notifier.Interval,
+		BatchSize:  cfg.Notifier.BatchSize,
+		CatchUp:    cfg.CatchUp.Policy,
+		CatchUpAge: cfg.CatchUp.MaxAge,
+		Channels:   channelRouter.Channels(),
+	})
+
+	// login links are sent only if the dashboard is served
+	botCfg := bot.Config{Channels: channelRouter.Channels()}
+	if cfg.Web.Addr != "" {
+		botCfg.WebURL = cfg.Web.URL
+	}
+	reminderBot := bot.New(messageOutbox, store, notificationSender, botCfg)
+
+	// broadcasts are rate limited on their own, so they are sent directly
+	adminConsole, err := admin.New(ctx, reminderBot, messageOutbox, sender.NewRateLimited(tgMessageSender, cfg.Broadcast.Rate), store, adminIDs)
+	if err != nil {
+		return fmt.Errorf("failed to create admin console: %w", err)
+	}
+
+	accessCfg.BotName = botAPI.Self.UserName
+	accessGuard := access.New(adminConsole, messageOutbox, store, accessCfg)
+
+	tgUpdatesListener := listener.New(botAPI, accessGuard, store, listener.Config{
+		UpdatesTimeout: cfg.Telegram.UpdatesTimeout,
+		HandlerTimeout: cfg.Telegram.HandlerTimeout,
+		Workers:        cfg.Telegram.Workers,
+		QueueSize:      cfg.Telegram.QueueSize,
+	})
+
+	// components are stopped in order they are added: stop receiving updates and running broadcasts first,
+	// then let notifier, webhooks, outbox, api and backuper finish their work, the database is closed last
+	lc := lifecycle.New(cfg.ShutdownTimeout)
+	lc.Add("listener", tgUpdatesListener.Listen)
+	lc.Add("admin", func(ctx context.Context) error {
+		adminConsole.Run(ctx)
+		return nil
+	})
+	lc.Add("notifier", func(ctx context.Context) error {
+		notificationSender.Run(ctx)
+		return nil
+	})
+	lc.Add("webhooks", func(ctx context.Context) error {
+		webhookDispatcher.Run(ctx)
+		return nil
+	})
+	lc.Add("outbox", func(ctx context.Context) error {
+		messageOutbox.Run(ctx)
+		return nil
+	})
+
+	if cfg.API.Addr != "" {
+		apiServer := api.New(store, messageOutbox, api.Config{Addr: cfg.API.Addr})
+		lc.Add("api", apiServer.Run)
+	}
+
+	if cfg.Web.Addr != "" {
+		webServer := web.New(store, web.Config{Addr: cfg.Web.Addr, Secure: strings.HasPrefix(cfg.Web.URL, "https://")})
+		lc.Add("web", webServer.Run)
+	}
+
+	if cfg.Backup.Dir != "" {
 		var backup *backuper.Backuper
-		if backup, err = backuper.New(db, backupDir, backupInterval, backupRetentionInterval); err != nil {
+		if backup, err = backuper.New(db, cfg.Backup.Dir, cfg.Backup.Interval, cfg.Backup.Retention); err != nil {
 			return fmt.Errorf("can't create backuper: %w", err)
 		}
-		// backuper starts in background goroutine
-		go func() {
+		lc.Add("backuper", func(ctx context.Context) error {
 			backup.Run(ctx)
-		}()
+			return nil
+		})
 	}
 
-	tgMessageSender := sender.New(botAPI)
+	lc.AddCloser("database", db.Close)
+	lc.AddCloser("tracing", func() error { return shutdownTracing(context.Background()) })
 
-	reminderBot := bot.New(tgMessageSender, store)
+	// Run is a blocking call
+	return lc.Run(ctx)
+}
 
-	tgUpdatesListener := listener.New(botAPI, reminderBot)
+// openDB opens sqlite database, every SQL call is traced.
+func openDB(dbFile string) (*sqlx.DB, error) {
+	// updates are processed concurrently, so writers wait for the lock instead of failing with SQLITE_BUSY
+	sqlDB, err := otelsql.Open("sqlite", sqliteDSN(dbFile),
+		otelsql.WithAttributes(semconv.DBSystemSqlite),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitRows: true}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can't open database: %w", err)
+	}
 
-	notificationSender := notifier.New(tgMessageSender, store, 1*time.Minute)
-	// notifications sender starts in background goroutine
-	go func() {
-		notificationSender.Run(ctx)
-	}()
+	db := sqlx.NewDb(sqlDB, "sqlite")
+	if err = db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("can't connect to database: %w", err)
+	}
 
-	// Listen is a blocking call
-	return tgUpdatesListener.Listen(ctx)
+	return db, nil
 }
 
-func setupLog(dbg bool, secrets ...string) error {
-	logOpts := []log.Option{log.Msec, log.LevelBraces, log.StackTraceOnError}
-	if dbg {
-		logOpts = []log.Option{log.Debug, log.CallerFile, log.CallerFunc, log.Msec, log.LevelBraces, log.StackTraceOnError}
+// openStorage opens storage for operator subcommands, pending migrations are applied as on start of the bot.
+func openStorage(dbFile, migrationsDir string) (cli.Storage, func() error, error) {
+	if migrationsDir == "" {
+		migrationsDir = config.Default().Migrations
 	}
 
-	colorizer := log.Mapper{
-		ErrorFunc:  func(s string) string { return color.New(color.FgHiRed).Sprint(s) },
-		WarnFunc:   func(s string) string { return color.New(color.FgRed).Sprint(s) },
-		InfoFunc:   func(s string) string { return color.New(color.FgYellow).Sprint(s) },
-		DebugFunc:  func(s string) string { return color.New(color.FgWhite).Sprint(s) },
-		CallerFunc: func(s string) string { return color.New(color.FgBlue).Sprint(s) },
-		TimeFunc:   func(s string) string { return color.New(color.FgCyan).Sprint(s) },
+	db, err := openDB(dbFile)
+	if err != nil {
+		return nil, nil, err
 	}
-	logOpts = append(logOpts, log.Map(colorizer))
 
-	if len(secrets) > 0 {
-		logOpts = append(logOpts, log.Secret(secrets...))
+	store, err := storage.NewSqllite(db, migrationsDir)
+	if err != nil {
+		_ = db.Close()
+		return nil, nil, fmt.Errorf("failed to connect to sqlite %s: %w", dbFile, err)
 	}
 
-	log.SetupStdLogger(logOpts...)
-	log.Setup(logOpts...)
+	return store, db.Close, nil
+}
+
+// sqliteDSN returns data source name with busy timeout set for every connection of the pool.
+func sqliteDSN(dbFile string) string {
+	sep := "?"
+	if strings.Contains(dbFile, "?") {
+		sep = "&"
+	}
+	return dbFile + sep + "_pragma=busy_timeout(5000)"
+}
+
+// setupLog sets up logs in format, secrets like Telegram API token and SMTP password are masked.
+func setupLog(format logging.Format, dbg bool, secrets ...string) error {
+	logging.Setup(format, dbg, secrets...)
 
 	if err := tbapi.SetLogger(log.ToStdLogger(log.Default(), "DEBUG tbapi ----")); err != nil {
 		return err
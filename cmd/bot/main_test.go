@@ -1,7 +1,6 @@
 package main
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -24,9 +23,10 @@ import (
 )
 
 const (
-	testChatID   = 32467
-	testUserID   = 345757
+	testUserID   = 32467
+	testChatID   = testUserID // chat id of private chat is the id of user
 	testUserName = "JohnDoe"
+	testUpdateID = 8264
 )
 
 func Test_execute(t *testing.T) {
@@ -37,7 +37,7 @@ func Test_execute(t *testing.T) {
 		botID         = "/bot" + testAPIToken
 	)
 
-	t.Run("success: start bot, create db, make db backup, send getMe, getUpdates, sendMessage requests to Telegram", func(t *testing.T) {
+	t.Run("success: start bot, create db, make db backup, send getMe, setMyCommands, getUpdates, sendMessage requests to Telegram", func(t *testing.T) {
 		r := require.New(t)
 
 		// ARRANGE
@@ -56,6 +56,7 @@ func Test_execute(t *testing.T) {
 			testUserJSON, _    = json.Marshal(testUser)
 			testUpdatesJSON, _ = json.Marshal([]tbapi.Update{
 				{
+					UpdateID: testUpdateID,
 					Message: &tbapi.Message{
 						Chat: &tbapi.Chat{ID: testChatID},
 						Text: "/start",
@@ -70,6 +71,7 @@ func Test_execute(t *testing.T) {
 
 			hasUpdates  atomic.Bool
 			hasMessages atomic.Bool
+			registered  atomic.Int32
 		)
 		hasUpdates.Store(true)
 		hasMessages.Store(true)
@@ -81,6 +83,11 @@ func Test_execute(t *testing.T) {
 			case strings.HasSuffix(req.URL.Path, "getMe"):
 				a.Equal(botID+"/getMe", req.URL.Path)
 				writeTgServerResp(t, w, tbapi.APIResponse{Ok: true, Result: testUserJSON})
+			case strings.HasSuffix(req.URL.Path, "setMyCommands"),
+				strings.HasSuffix(req.URL.Path, "setMyDescription"),
+				strings.HasSuffix(req.URL.Path, "setMyShortDescription"):
+				registered.Add(1)
+				writeTgServerResp(t, w, tbapi.APIResponse{Ok: true, Result: json.RawMessage("true")})
 			case strings.HasSuffix(req.URL.Path, "getUpdates"):
 				a.Equal(botID+"/getUpdates", req.URL.Path)
 
@@ -119,14 +126,14 @@ func Test_execute(t *testing.T) {
 			}
 		}))
 
-		t.Setenv(envTelegramBotAPIEndpoint, tgAPIServer.URL+"/bot%s/%s") // https://api.telegram.org/bot%s/%s
-		t.Setenv(envMigrations, migrationsDir)
-		t.Setenv(envDebug, "false")
-		t.Setenv(envTelegramAPIToken, testAPIToken)
-		t.Setenv(envDBFile, dbFile)
-		t.Setenv(envBackupDir, dbBackupDir)
-		t.Setenv(envBackupInterval, "700ms") // shpuld be less then sigIntTimeout, we expect exactly 1 backup before SIGINT signal
-		t.Setenv(envBackupRetention, "2s")
+		// DEBUG is not set, it's optional
+		t.Setenv("TELEGRAM_BOT_API_ENDPOINT", tgAPIServer.URL+"/bot%s/%s") // https://api.telegram.org/bot%s/%s
+		t.Setenv("MIGRATIONS", migrationsDir)
+		t.Setenv("TELEGRAM_APITOKEN", testAPIToken)
+		t.Setenv("DB_FILE", dbFile)
+		t.Setenv("BACKUP_DIR", dbBackupDir)
+		t.Setenv("BACKUP_INTERVAL", "700ms") // shpuld be less then sigIntTimeout, we expect exactly 1 backup before SIGINT signal
+		t.Setenv("BACKUP_RETENTION", "2s")
 
 		t.Cleanup(func() {
 			f.Close()
@@ -145,12 +152,15 @@ func Test_execute(t *testing.T) {
 		}()
 
 		// ACT
-		err = execute()
+		err = execute(nil)
 
 		// ASSERT
-		r.ErrorIs(err, context.Canceled)
+		// graceful shutdown is not an error
+		r.NoError(err)
 		r.True(<-done)
 		checkDBStateAfterExecute(r, dbFile)
+		// commands of private and group chats, description and short description for two languages
+		r.EqualValues(8, registered.Load())
 
 		backupDirEntries, err := os.ReadDir(dbBackupDir)
 		r.NoError(err)
@@ -187,6 +197,18 @@ func checkDBStateAfterExecute(r *require.Assertions, dbFile string) {
 		"users",
 		"reminders",
 		"bot_states",
+		"invite_codes",
+		"checklist_items",
+		"user_settings",
+		"update_offset",
+		"processed_updates",
+		"outbox",
+		"reminder_events",
+		"api_tokens",
+		"web_sessions",
+		"webhooks",
+		"webhook_deliveries",
+		"maintenance",
 	}
 	r.EqualValues(exTables, tables)
 
@@ -201,4 +223,42 @@ func checkDBStateAfterExecute(r *require.Assertions, dbFile string) {
 	var state domain.BotState
 	r.NoError(db.Get(&state, getBotStateQuery, testUserID))
 	r.EqualValues(domain.BotStateNameStart, state.Name)
+
+	const getUpdateOffsetQuery = `SELECT update_id FROM update_offset;`
+	var updateID int
+	r.NoError(db.Get(&updateID, getUpdateOffsetQuery))
+	r.EqualValues(testUpdateID, updateID)
+}
+
+func Test_configCommand(t *testing.T) {
+	t.Run("success: config is valid", func(t *testing.T) {
+		t.Setenv("DB_FILE", "/srv/var/tg-reminder.db")
+		t.Setenv("TELEGRAM_APITOKEN", "secret-token")
+
+		var out strings.Builder
+		err := configCommand([]string{"check", "--notifier-interval", "30s"}, &out)
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "config is valid")
+		assert.Contains(t, out.String(), "interval: 30s")
+		assert.NotContains(t, out.String(), "secret-token")
+	})
+
+	t.Run("error: config is invalid", func(t *testing.T) {
+		t.Setenv("ACCESS_MODE", "invite")
+
+		var out strings.Builder
+		err := configCommand([]string{"check"}, &out)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "db_file is required")
+		assert.Contains(t, err.Error(), "telegram.api_token is required")
+		assert.Contains(t, err.Error(), "access.owner_id is required for invite access mode")
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("error: unknown subcommand", func(t *testing.T) {
+		err := configCommand([]string{"show"}, io.Discard)
+		assert.EqualError(t, err, "usage: tg-reminder config check [flags]")
+	})
 }
@@ -0,0 +1,234 @@
+package access
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+	"github.com/mezk/tg-reminder/internal/pkg/sender"
+	"github.com/mezk/tg-reminder/internal/pkg/storage"
+)
+
+// Mode - access mode, describes who is able to work with the bot.
+type Mode string
+
+const (
+	// ModeOpen - anyone can work with the bot.
+	ModeOpen Mode = "open"
+	// ModeAllowlist - only users from the allowlist and the owner can work with the bot.
+	ModeAllowlist Mode = "allowlist"
+	// ModeInvite - only users who registered with an invite code, users from the allowlist and the owner can work with the bot.
+	ModeInvite Mode = "invite"
+)
+
+// ParseMode parses access mode. Empty string is parsed as [ModeOpen].
+func ParseMode(s string) (Mode, error) {
+	switch Mode(s) {
+	case "", ModeOpen:
+		return ModeOpen, nil
+	case ModeAllowlist, ModeInvite:
+		return Mode(s), nil
+	default:
+		return "", fmt.Errorf("unknown access mode %q", s)
+	}
+}
+
+// UpdateReceiver - receiver of Telegram updates which is protected by [Guard].
+type UpdateReceiver interface {
+	OnMessage(ctx context.Context, message domain.TgMessage) error
+	OnCallbackQuery(ctx context.Context, callback domain.TgCallbackQuery) error
+}
+
+// ResponseSender - guard's response sender.
+type ResponseSender interface {
+	SendBotResponse(ctx context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error
+}
+
+// Storage - guard's persistent storage.
+type Storage interface {
+	GetUser(ctx context.Context, id int64) (domain.User, error)
+	SaveInviteCode(ctx context.Context, code domain.InviteCode) error
+	GetInviteCode(ctx context.Context, code string) (domain.InviteCode, error)
+}
+
+// Config - access control configuration.
+type Config struct {
+	Mode           Mode    // access mode
+	OwnerID        int64   // Telegram ID of the bot owner, owner is always allowed and is able to generate invite codes
+	AllowedUserIDs []int64 // Telegram IDs of users who are always allowed
+	BotName        string  // bot user name, used to build invite links
+}
+
+// Guard rejects updates from unauthorized and blocked users before they reach [UpdateReceiver].
+type Guard struct {
+	next           UpdateReceiver
+	responseSender ResponseSender
+	store          Storage
+	cfg            Config
+	allowed        map[int64]struct{}
+}
+
+// New creates a new [Guard].
+func New(next UpdateReceiver, responseSender ResponseSender, store Storage, cfg Config) *Guard {
+	allowed := make(map[int64]struct{}, len(cfg.AllowedUserIDs))
+	for _, id := range cfg.AllowedUserIDs {
+		allowed[id] = struct{}{}
+	}
+
+	return &Guard{
+		next:           next,
+		responseSender: responseSender,
+		store:          store,
+		cfg:            cfg,
+		allowed:        allowed,
+	}
+}
+
+// OnMessage checks whether message author is allowed to work with the bot and passes message to [UpdateReceiver].
+// Handles owner's invite command and invite codes sent with start command.
+func (g *Guard) OnMessage(ctx context.Context, message domain.TgMessage) error {
+	if message.Command() == domain.BotCommandInvite && g.isOwner(message.UserID) {
+		if !message.IsPrivate() {
+			return g.responseSender.SendBotResponse(ctx, sender.BotResponse{ChatID: message.ChatID, Text: domain.PrivateOnlyText})
+		}
+		return g.onInviteCommand(ctx, message)
+	}
+
+	verdict, err := g.check(ctx, message.UserID)
+	if err != nil {
+		return err
+	}
+
+	if verdict == verdictDenied && g.cfg.Mode == ModeInvite && message.Command() == domain.BotCommandStart && message.CommandArgs() != "" {
+		if _, err = g.store.GetInviteCode(ctx, message.CommandArgs()); err != nil {
+			if !errors.Is(err, storage.ErrInviteCodeNotFound) {
+				return err
+			}
+
+			logging.Printf(ctx, "[WARN] user %d (@%s) sent unknown invite code %s", message.UserID, message.UserName, domain.MaskInviteCode(message.CommandArgs()))
+
+			return g.responseSender.SendBotResponse(ctx, sender.BotResponse{
+				ChatID: message.ChatID,
+				Text:   fmt.Sprintf("Извините, приглашение недействительно или уже использовано %s\n\nПопросите владельца бота прислать новое приглашение.", domain.EmojiLocked),
+			})
+		}
+
+		// invite code is used when user is registered by start command
+		ctx = domain.ContextWithInviteCode(ctx, message.CommandArgs())
+		verdict = verdictAllowed
+	}
+
+	if verdict != verdictAllowed {
+		return g.deny(ctx, verdict, message.ChatID, message.UserID, message.UserName)
+	}
+
+	return g.next.OnMessage(ctx, message)
+}
+
+// OnCallbackQuery checks whether callback author is allowed to work with the bot and passes callback to [UpdateReceiver].
+func (g *Guard) OnCallbackQuery(ctx context.Context, callback domain.TgCallbackQuery) error {
+	verdict, err := g.check(ctx, callback.UserID)
+	if err != nil {
+		return err
+	}
+
+	if verdict != verdictAllowed {
+		return g.deny(ctx, verdict, callback.ChatID, callback.UserID, callback.UserName)
+	}
+
+	return g.next.OnCallbackQuery(ctx, callback)
+}
+
+func (g *Guard) isOwner(userID int64) bool {
+	return g.cfg.OwnerID != 0 && g.cfg.OwnerID == userID
+}
+
+// verdict - result of access check.
+type verdict int
+
+const (
+	verdictAllowed verdict = iota
+	verdictDenied
+	verdictBlocked
+)
+
+func (g *Guard) check(ctx context.Context, userID int64) (verdict, error) {
+	if g.isOwner(userID) {
+		return verdictAllowed, nil
+	}
+
+	registered := true
+	user, err := g.store.GetUser(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, storage.ErrUserNotFound) {
+			return verdictDenied, err
+		}
+		registered = false
+	}
+
+	if registered && user.Status == domain.UserStatusBlocked {
+		return verdictBlocked, nil
+	}
+
+	if _, ok := g.allowed[userID]; ok {
+		return verdictAllowed, nil
+	}
+
+	switch {
+	case g.cfg.Mode == ModeAllowlist:
+		return verdictDenied, nil
+	case g.cfg.Mode == ModeInvite && !registered:
+		// registered user has already used invite code
+		return verdictDenied, nil
+	default:
+		return verdictAllowed, nil
+	}
+}
+
+func (g *Guard) deny(ctx context.Context, v verdict, chatID, userID int64, userName string) error {
+	if v == verdictBlocked {
+		logging.Printf(ctx, "[WARN] access denied for blocked user %d (@%s) in chat %d", userID, userName, chatID)
+
+		return g.responseSender.SendBotResponse(ctx, sender.BotResponse{
+			ChatID: chatID,
+			Text:   fmt.Sprintf("Извините, ваш доступ к боту заблокирован администратором %s", domain.EmojiLocked),
+		})
+	}
+
+	logging.Printf(ctx, "[WARN] access denied for user %d (@%s) in chat %d, access mode %s", userID, userName, chatID, g.cfg.Mode)
+
+	return g.responseSender.SendBotResponse(ctx, sender.BotResponse{
+		ChatID: chatID,
+		Text:   fmt.Sprintf("Извините, это частный бот %s\n\nЧтобы начать работу, попросите владельца бота прислать вам приглашение.", domain.EmojiLocked),
+	})
+}
+
+func (g *Guard) onInviteCommand(ctx context.Context, message domain.TgMessage) error {
+	code, err := newInviteCode()
+	if err != nil {
+		return fmt.Errorf("failed to generate invite code: %w", err)
+	}
+
+	inviteCode := domain.InviteCode{Code: code, CreatedBy: message.UserID}
+	if err = g.store.SaveInviteCode(ctx, inviteCode); err != nil {
+		return err
+	}
+
+	return g.responseSender.SendBotResponse(ctx, sender.BotResponse{
+		ChatID: message.ChatID,
+		Text:   fmt.Sprintf("*Приглашение создано* %s\n\nОтправьте ссылку пользователю, ссылка одноразовая:\n`%s`", domain.EmojiTicket, inviteCode.InviteLink(g.cfg.BotName)),
+	}, sender.WithSecret())
+}
+
+var newInviteCode = func() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
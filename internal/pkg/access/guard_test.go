@@ -0,0 +1,363 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/sender"
+	"github.com/mezk/tg-reminder/internal/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testOwnerID   int64 = 1001
+	testAllowedID int64 = 2002
+	testUserID    int64 = 3003
+	testChatID    int64 = 4004
+)
+
+func TestParseMode(t *testing.T) {
+	t.Parallel()
+
+	for s, exp := range map[string]Mode{
+		"":          ModeOpen,
+		"open":      ModeOpen,
+		"allowlist": ModeAllowlist,
+		"invite":    ModeInvite,
+	} {
+		act, err := ParseMode(s)
+		require.NoError(t, err)
+		assert.Equal(t, exp, act)
+	}
+
+	_, err := ParseMode("foo")
+	require.EqualError(t, err, `unknown access mode "foo"`)
+}
+
+// nolint:paralleltest // test modifies package level function newInviteCode.
+func TestGuard_OnMessage(t *testing.T) {
+	const deniedText = "Извините, это частный бот 🔒\n\nЧтобы начать работу, попросите владельца бота прислать вам приглашение."
+
+	var dbError = errors.New("db error")
+
+	testCases := []struct {
+		name          string
+		mode          Mode
+		message       domain.TgMessage
+		setMocks      func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock)
+		expPassed     bool
+		expInviteCode string
+		expErr        string
+		expInvited    bool
+	}{
+		{
+			name:      "success: open mode, unknown user",
+			mode:      ModeOpen,
+			message:   domain.TgMessage{ChatID: testChatID, UserID: testUserID, Text: "/start"},
+			expPassed: true,
+		},
+		{
+			name:      "success: allowlist mode, allowed user",
+			mode:      ModeAllowlist,
+			message:   domain.TgMessage{ChatID: testChatID, UserID: testAllowedID, Text: "/start"},
+			expPassed: true,
+		},
+		{
+			name:      "success: allowlist mode, owner",
+			mode:      ModeAllowlist,
+			message:   domain.TgMessage{ChatID: testChatID, UserID: testOwnerID, Text: "/start"},
+			expPassed: true,
+		},
+		{
+			name:    "success: allowlist mode, unknown user is denied",
+			mode:    ModeAllowlist,
+			message: domain.TgMessage{ChatID: testChatID, UserID: testUserID, Text: "/start"},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, _ *StorageMock) {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{ChatID: testChatID, Text: deniedText}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name:    "success: open mode, blocked user is denied",
+			mode:    ModeOpen,
+			message: domain.TgMessage{ChatID: testChatID, UserID: testUserID, Text: "/my_reminders"},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetUserFunc = func(_ context.Context, _ int64) (domain.User, error) {
+					return domain.User{ID: testUserID, Status: domain.UserStatusBlocked}, nil
+				}
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{ChatID: testChatID, Text: "Извините, ваш доступ к боту заблокирован администратором 🔒"}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name:    "success: allowlist mode, blocked allowed user is denied",
+			mode:    ModeAllowlist,
+			message: domain.TgMessage{ChatID: testChatID, UserID: testAllowedID, Text: "/my_reminders"},
+			setMocks: func(_ *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetUserFunc = func(_ context.Context, _ int64) (domain.User, error) {
+					return domain.User{ID: testAllowedID, Status: domain.UserStatusBlocked}, nil
+				}
+				responseSender.SendBotResponseFunc = func(_ context.Context, _ sender.BotResponse, _ ...sender.BotResponseOption) error {
+					return nil
+				}
+			},
+		},
+		{
+			name:    "success: invite mode, registered user",
+			mode:    ModeInvite,
+			message: domain.TgMessage{ChatID: testChatID, UserID: testUserID, Text: "/my_reminders"},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetUserFunc = func(_ context.Context, id int64) (domain.User, error) {
+					a.Equal(testUserID, id)
+					return domain.User{ID: testUserID}, nil
+				}
+			},
+			expPassed: true,
+		},
+		{
+			name:    "success: invite mode, unknown user is denied",
+			mode:    ModeInvite,
+			message: domain.TgMessage{ChatID: testChatID, UserID: testUserID, Text: "/start"},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetUserFunc = func(_ context.Context, _ int64) (domain.User, error) {
+					return domain.User{}, fmt.Errorf("wrapped: %w", storage.ErrUserNotFound)
+				}
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{ChatID: testChatID, Text: deniedText}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name:    "success: invite mode, unknown user with valid invite code",
+			mode:    ModeInvite,
+			message: domain.TgMessage{ChatID: testChatID, UserID: testUserID, Text: "/start abc123"},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetUserFunc = func(_ context.Context, _ int64) (domain.User, error) {
+					return domain.User{}, storage.ErrUserNotFound
+				}
+				store.GetInviteCodeFunc = func(_ context.Context, code string) (domain.InviteCode, error) {
+					a.Equal("abc123", code)
+					return domain.InviteCode{Code: code, CreatedBy: testOwnerID}, nil
+				}
+			},
+			expPassed:     true,
+			expInviteCode: "abc123",
+		},
+		{
+			name:    "success: invite mode, unknown user with invalid invite code",
+			mode:    ModeInvite,
+			message: domain.TgMessage{ChatID: testChatID, UserID: testUserID, Text: "/start abc123"},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetUserFunc = func(_ context.Context, _ int64) (domain.User, error) {
+					return domain.User{}, storage.ErrUserNotFound
+				}
+				store.GetInviteCodeFunc = func(_ context.Context, _ string) (domain.InviteCode, error) {
+					return domain.InviteCode{}, storage.ErrInviteCodeNotFound
+				}
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID: testChatID,
+						Text:   "Извините, приглашение недействительно или уже использовано 🔒\n\nПопросите владельца бота прислать новое приглашение.",
+					}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name:    "success: owner generates invite",
+			mode:    ModeInvite,
+			message: domain.TgMessage{ChatID: testOwnerID, UserID: testOwnerID, Text: "/invite"},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.SaveInviteCodeFunc = func(_ context.Context, code domain.InviteCode) error {
+					a.Equal(domain.InviteCode{Code: "0123456789abcdef", CreatedBy: testOwnerID}, code)
+					return nil
+				}
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID: testOwnerID,
+						Text:   "*Приглашение создано* 🎟️\n\nОтправьте ссылку пользователю, ссылка одноразовая:\n`https://t.me/reminder_bot?start=0123456789abcdef`",
+					}, response)
+					return nil
+				}
+			},
+			expInvited: true,
+		},
+		{
+			name:    "success: invite command in group chat isn't handled",
+			mode:    ModeInvite,
+			message: domain.TgMessage{ChatID: testChatID, UserID: testOwnerID, Text: "/invite"},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, _ *StorageMock) {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{ChatID: testChatID, Text: "Эта команда работает только в личном чате с ботом 🔒"}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name:      "success: invite command from not owner is passed further",
+			mode:      ModeOpen,
+			message:   domain.TgMessage{ChatID: testChatID, UserID: testUserID, Text: "/invite"},
+			expPassed: true,
+		},
+		{
+			name:    "error: invite mode, can't get user",
+			mode:    ModeInvite,
+			message: domain.TgMessage{ChatID: testChatID, UserID: testUserID, Text: "/start"},
+			setMocks: func(_ *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetUserFunc = func(_ context.Context, _ int64) (domain.User, error) {
+					return domain.User{}, dbError
+				}
+			},
+			expErr: dbError.Error(),
+		},
+		{
+			name:    "error: invite mode, can't use invite code",
+			mode:    ModeInvite,
+			message: domain.TgMessage{ChatID: testChatID, UserID: testUserID, Text: "/start abc123"},
+			setMocks: func(_ *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetUserFunc = func(_ context.Context, _ int64) (domain.User, error) {
+					return domain.User{}, storage.ErrUserNotFound
+				}
+				store.GetInviteCodeFunc = func(_ context.Context, _ string) (domain.InviteCode, error) {
+					return domain.InviteCode{}, dbError
+				}
+			},
+			expErr: dbError.Error(),
+		},
+		{
+			name:    "error: can't save invite code",
+			mode:    ModeInvite,
+			message: domain.TgMessage{ChatID: testOwnerID, UserID: testOwnerID, Text: "/invite"},
+			setMocks: func(_ *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveInviteCodeFunc = func(_ context.Context, _ domain.InviteCode) error {
+					return dbError
+				}
+			},
+			expErr:     dbError.Error(),
+			expInvited: true,
+		},
+	}
+
+	tmpNewInviteCode := newInviteCode
+	defer func() {
+		newInviteCode = tmpNewInviteCode
+	}()
+	newInviteCode = func() (string, error) {
+		return "0123456789abcdef", nil
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+			senderMock := &ResponseSenderMock{}
+			storeMock := &StorageMock{
+				GetUserFunc: func(_ context.Context, _ int64) (domain.User, error) {
+					return domain.User{}, storage.ErrUserNotFound
+				},
+			}
+			if tc.setMocks != nil {
+				tc.setMocks(a, senderMock, storeMock)
+			}
+
+			receiverMock := &UpdateReceiverMock{
+				OnMessageFunc: func(ctx context.Context, message domain.TgMessage) error {
+					a.Equal(tc.message, message)
+					code, _ := domain.InviteCodeFromContext(ctx)
+					a.Equal(tc.expInviteCode, code)
+					return nil
+				},
+			}
+
+			guard := New(receiverMock, senderMock, storeMock, Config{
+				Mode:           tc.mode,
+				OwnerID:        testOwnerID,
+				AllowedUserIDs: []int64{testAllowedID},
+				BotName:        "reminder_bot",
+			})
+
+			actErr := guard.OnMessage(context.TODO(), tc.message)
+
+			if tc.expErr != "" {
+				a.EqualError(actErr, tc.expErr)
+			} else {
+				a.NoError(actErr)
+			}
+
+			a.Equal(tc.expPassed, len(receiverMock.OnMessageCalls()) == 1)
+			a.Equal(tc.expInvited, len(storeMock.SaveInviteCodeCalls()) == 1)
+		})
+	}
+}
+
+func TestGuard_OnCallbackQuery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success: allowed user", func(t *testing.T) {
+		t.Parallel()
+
+		callback := domain.TgCallbackQuery{ChatID: testChatID, UserID: testAllowedID, Data: "btn_edit_reminder"}
+		receiverMock := &UpdateReceiverMock{
+			OnCallbackQueryFunc: func(_ context.Context, actCallback domain.TgCallbackQuery) error {
+				assert.Equal(t, callback, actCallback)
+				return nil
+			},
+		}
+
+		storeMock := &StorageMock{
+			GetUserFunc: func(_ context.Context, _ int64) (domain.User, error) {
+				return domain.User{ID: testAllowedID, Status: domain.UserStatusActive}, nil
+			},
+		}
+
+		guard := New(receiverMock, nil, storeMock, Config{Mode: ModeAllowlist, AllowedUserIDs: []int64{testAllowedID}})
+
+		require.NoError(t, guard.OnCallbackQuery(context.TODO(), callback))
+		assert.Len(t, receiverMock.OnCallbackQueryCalls(), 1)
+	})
+
+	t.Run("success: unknown user is denied", func(t *testing.T) {
+		t.Parallel()
+
+		senderMock := &ResponseSenderMock{
+			SendBotResponseFunc: func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+				assert.Equal(t, testChatID, response.ChatID)
+				return nil
+			},
+		}
+		receiverMock := &UpdateReceiverMock{}
+		storeMock := &StorageMock{
+			GetUserFunc: func(_ context.Context, _ int64) (domain.User, error) {
+				return domain.User{}, storage.ErrUserNotFound
+			},
+		}
+
+		guard := New(receiverMock, senderMock, storeMock, Config{Mode: ModeAllowlist})
+
+		require.NoError(t, guard.OnCallbackQuery(context.TODO(), domain.TgCallbackQuery{ChatID: testChatID, UserID: testUserID}))
+		assert.Empty(t, receiverMock.OnCallbackQueryCalls())
+		assert.Len(t, senderMock.SendBotResponseCalls(), 1)
+	})
+
+	t.Run("error: can't get user", func(t *testing.T) {
+		t.Parallel()
+
+		storeMock := &StorageMock{
+			GetUserFunc: func(_ context.Context, _ int64) (domain.User, error) {
+				return domain.User{}, errors.New("db error")
+			},
+		}
+
+		guard := New(nil, nil, storeMock, Config{Mode: ModeInvite})
+
+		require.EqualError(t, guard.OnCallbackQuery(context.TODO(), domain.TgCallbackQuery{ChatID: testChatID, UserID: testUserID}), "db error")
+	})
+}
@@ -0,0 +1,366 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+	"github.com/mezk/tg-reminder/internal/pkg/sender"
+	"github.com/mezk/tg-reminder/internal/pkg/storage"
+)
+
+var timeNowUTC = func() time.Time {
+	return time.Now().UTC()
+}
+
+// UpdateReceiver - receiver of Telegram updates which are not handled by [Console].
+type UpdateReceiver interface {
+	OnMessage(ctx context.Context, message domain.TgMessage) error
+	OnCallbackQuery(ctx context.Context, callback domain.TgCallbackQuery) error
+}
+
+// ResponseSender - console's response sender.
+type ResponseSender interface {
+	SendBotResponse(ctx context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error
+}
+
+// Storage - console's persistent storage.
+type Storage interface {
+	GetStats(ctx context.Context) (domain.Stats, error)
+	GetUsers(ctx context.Context, limit int64) ([]domain.User, error)
+	GetUsersByStatus(ctx context.Context, status domain.UserStatus) ([]domain.User, error)
+	SetUserStatus(ctx context.Context, id int64, status domain.UserStatus, msgs ...domain.OutboxMessage) error
+	GetReminder(ctx context.Context, id int64) (domain.Reminder, error)
+	GetReminderEvents(ctx context.Context, reminderID int64) ([]domain.ReminderEvent, error)
+	GetMaintenance(ctx context.Context) (bool, error)
+	SetMaintenance(ctx context.Context, enabled bool) error
+}
+
+// usersListLimit - max number of users shown by /admin_users command.
+const usersListLimit = 50
+
+// Console - admin console. Handles admin commands and maintenance mode, passes other updates to [UpdateReceiver].
+type Console struct {
+	next            UpdateReceiver
+	responseSender  ResponseSender
+	broadcastSender ResponseSender
+	store           Storage
+	adminIDs        map[int64]struct{}
+
+	commands map[domain.BotCommand]commandHandler
+
+	// maintenance mode is stored, it's kept in memory to check every update without querying the database
+	maintenance atomic.Bool
+	broadcasts  sync.WaitGroup
+	// broadcastsCtx is cancelled on shutdown to stop running broadcasts
+	broadcastsCtx  context.Context
+	stopBroadcasts context.CancelFunc
+}
+
+type commandHandler func(ctx context.Context, message domain.TgMessage) error
+
+// New creates a new [Console], maintenance mode set before restart is loaded from store.
+// broadcastSender is used to send announcements to all users, it's supposed to be rate limited.
+func New(ctx context.Context, next UpdateReceiver, responseSender, broadcastSender ResponseSender, store Storage,
+	adminIDs []int64) (*Console, error) {
+	admins := make(map[int64]struct{}, len(adminIDs))
+	for _, id := range adminIDs {
+		admins[id] = struct{}{}
+	}
+
+	c := &Console{
+		next:            next,
+		responseSender:  responseSender,
+		broadcastSender: broadcastSender,
+		store:           store,
+		adminIDs:        admins,
+	}
+	c.broadcastsCtx, c.stopBroadcasts = context.WithCancel(context.Background())
+
+	maintenance, err := store.GetMaintenance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.maintenance.Store(maintenance)
+	if maintenance {
+		logging.Printf(ctx, "[WARN] maintenance mode is enabled")
+	}
+
+	handlers := map[domain.BotCommand]commandHandler{
+		domain.BotCommandAdminStats:    c.onStatsCommand,
+		domain.BotCommandAdminUsers:    c.onUsersCommand,
+		domain.BotCommandAdminReminder: c.onReminderCommand,
+		domain.BotCommandBroadcast:     c.onBroadcastCommand,
+		domain.BotCommandMaintenance:   c.onMaintenanceCommand,
+	}
+
+	// only registered admin commands are dispatched, owner commands are handled before the console
+	c.commands = make(map[domain.BotCommand]commandHandler, len(handlers))
+	for _, info := range domain.BotCommandsFor(domain.BotCommandAccessAdmin, true) {
+		if handler, ok := handlers[info.Command]; ok && info.Access == domain.BotCommandAccessAdmin {
+			c.commands[info.Command] = handler
+		}
+	}
+
+	return c, nil
+}
+
+// OnMessage - handles admin commands from admins. Replies with maintenance notice to other users in maintenance mode.
+func (c *Console) OnMessage(ctx context.Context, message domain.TgMessage) error {
+	if c.isAdmin(message.UserID) {
+		ctx = domain.ContextWithActor(ctx, domain.Actor{Type: domain.ActorAdmin, ID: message.UserID})
+
+		if handler, ok := c.commands[message.Command()]; ok {
+			if message.Command().IsPrivateOnly() && !message.IsPrivate() {
+				return c.responseSender.SendBotResponse(ctx, sender.BotResponse{ChatID: message.ChatID, Text: domain.PrivateOnlyText})
+			}
+			return handler(ctx, message)
+		}
+	} else if c.maintenance.Load() {
+		return c.sendMaintenanceNotice(ctx, message.ChatID)
+	}
+
+	return c.next.OnMessage(ctx, message)
+}
+
+// OnCallbackQuery - handles admin buttons from admins. Replies with maintenance notice to other users in maintenance mode.
+func (c *Console) OnCallbackQuery(ctx context.Context, callback domain.TgCallbackQuery) error {
+	if c.isAdmin(callback.UserID) {
+		ctx = domain.ContextWithActor(ctx, domain.Actor{Type: domain.ActorAdmin, ID: callback.UserID})
+
+		switch {
+		case strings.HasPrefix(callback.Data, domain.ButtonDataPrefixAdminBlockUser):
+			return c.onSetUserStatusButton(ctx, callback, domain.UserStatusBlocked)
+		case strings.HasPrefix(callback.Data, domain.ButtonDataPrefixAdminUnblockUser):
+			return c.onSetUserStatusButton(ctx, callback, domain.UserStatusActive)
+		}
+	} else if c.maintenance.Load() {
+		return c.sendMaintenanceNotice(ctx, callback.ChatID)
+	}
+
+	return c.next.OnCallbackQuery(ctx, callback)
+}
+
+// Run blocks until ctx is cancelled, then stops running broadcasts and waits until they are finished.
+func (c *Console) Run(ctx context.Context) {
+	<-ctx.Done()
+	c.stopBroadcasts()
+	c.Wait()
+}
+
+// Wait blocks until all running broadcasts are finished.
+func (c *Console) Wait() {
+	c.broadcasts.Wait()
+}
+
+func (c *Console) isAdmin(userID int64) bool {
+	_, ok := c.adminIDs[userID]
+	return ok
+}
+
+func (c *Console) sendMaintenanceNotice(ctx context.Context, chatID int64) error {
+	return c.responseSender.SendBotResponse(ctx, sender.BotResponse{
+		ChatID: chatID,
+		Text:   fmt.Sprintf("%s Бот на техническом обслуживании, пожалуйста, попробуйте позже.", domain.EmojiHammerAndWrench),
+	})
+}
+
+func (c *Console) onStatsCommand(ctx context.Context, message domain.TgMessage) error {
+	stats, err := c.store.GetStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.responseSender.SendBotResponse(ctx, sender.BotResponse{ChatID: message.ChatID, Text: stats.Format(timeNowUTC())})
+}
+
+func (c *Console) onUsersCommand(ctx context.Context, message domain.TgMessage) error {
+	users, err := c.store.GetUsers(ctx, usersListLimit)
+	if err != nil {
+		return err
+	}
+
+	if len(users) == 0 {
+		return c.responseSender.SendBotResponse(ctx, sender.BotResponse{ChatID: message.ChatID, Text: "*Пользователей нет*"})
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("*ПОЛЬЗОВАТЕЛИ* (последние %d)\n", usersListLimit))
+	for _, u := range users {
+		sb.WriteString(fmt.Sprintf("\n`%d` @%s — %s", u.ID, u.Name, u.Status))
+	}
+
+	return c.responseSender.SendBotResponse(ctx, sender.BotResponse{
+		ChatID: message.ChatID,
+		Text:   sb.String(),
+	}, sender.WithAdminUsersButtons(users))
+}
+
+func (c *Console) onSetUserStatusButton(ctx context.Context, callback domain.TgCallbackQuery, status domain.UserStatus) error {
+	userID, err := callback.SubjectUserID()
+	if err != nil {
+		return fmt.Errorf("can't parse userID: %w", err)
+	}
+
+	if c.isAdmin(userID) {
+		return c.responseSender.SendBotResponse(ctx, sender.BotResponse{
+			ChatID: callback.ChatID,
+			Text:   fmt.Sprintf("Нельзя изменить статус администратора %s", domain.EmojiProhibited),
+		})
+	}
+
+	responseMsg := fmt.Sprintf("Пользователь `%d` заблокирован %s", userID, domain.EmojiProhibited)
+	if status != domain.UserStatusBlocked {
+		responseMsg = fmt.Sprintf("Пользователь `%d` разблокирован %s", userID, domain.EmojiWhiteHeavyCheckMark)
+	}
+
+	if err = c.store.SetUserStatus(ctx, userID, status); err != nil {
+		switch {
+		case errors.Is(err, storage.ErrUserNotFound):
+			responseMsg = fmt.Sprintf("Пользователь `%d` не найден %s", userID, domain.EmojiThinkingFace)
+		default:
+			return err
+		}
+	}
+
+	logging.Printf(ctx, "[INFO] admin %d set user %d status to %s", callback.UserID, userID, status)
+
+	return c.responseSender.SendBotResponse(ctx, sender.BotResponse{ChatID: callback.ChatID, Text: responseMsg})
+}
+
+func (c *Console) onReminderCommand(ctx context.Context, message domain.TgMessage) error {
+	id, err := strconv.ParseInt(message.CommandArgs(), 10, 64)
+	if err != nil {
+		return c.responseSender.SendBotResponse(ctx, sender.BotResponse{
+			ChatID: message.ChatID,
+			Text:   fmt.Sprintf("Напишите номер напоминания после команды, например:\n%s 42", domain.BotCommandAdminReminder.Markdown()),
+		})
+	}
+
+	// history of removed reminder is kept, so it's shown even if reminder is not found
+	reminder, err := c.store.GetReminder(ctx, id)
+	found := err == nil
+	if err != nil && !errors.Is(err, storage.ErrReminderNotFound) {
+		return err
+	}
+	reminder.ID = id
+
+	events, err := c.store.GetReminderEvents(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !found && len(events) == 0 {
+		return c.responseSender.SendBotResponse(ctx, sender.BotResponse{
+			ChatID: message.ChatID,
+			Text:   fmt.Sprintf("Напоминание %d не найдено %s", id, domain.EmojiThinkingFace),
+		})
+	}
+
+	text := domain.FormatHistory(reminder, events, true)
+	if found {
+		text += fmt.Sprintf("\n\n*Пользователь:* `%d`\n*Чат:* `%d`\n*Статус:* `%s`\n*Время:* %s\n*Осталось попыток:* %d",
+			reminder.UserID, reminder.ChatID, reminder.Status, domain.MoscowTime(reminder.RemindAt).Format(domain.LayoutRemindAt), reminder.AttemptsLeft)
+	}
+
+	return c.responseSender.SendBotResponse(ctx, sender.BotResponse{ChatID: message.ChatID, Text: text})
+}
+
+func (c *Console) onBroadcastCommand(ctx context.Context, message domain.TgMessage) error {
+	text := message.CommandArgs()
+	if text == "" {
+		return c.responseSender.SendBotResponse(ctx, sender.BotResponse{
+			ChatID: message.ChatID,
+			Text:   fmt.Sprintf("Напишите текст объявления после команды, например:\n%s Завтра с 10:00 до 11:00 бот будет недоступен", domain.BotCommandBroadcast.Markdown()),
+		})
+	}
+
+	users, err := c.store.GetUsersByStatus(ctx, domain.UserStatusActive)
+	if err != nil {
+		return err
+	}
+
+	logging.Printf(ctx, "[INFO] admin %d started broadcast to %d users", message.UserID, len(users))
+
+	// broadcast can take a while because of the rate limit, so it's sent in background
+	// and outlives the update's context, it's stopped on shutdown by [Console.Run]
+	broadcastCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(c.broadcastsCtx, cancel)
+	c.broadcasts.Add(1)
+	go func() {
+		defer c.broadcasts.Done()
+		defer cancel()
+		defer stop()
+		c.broadcast(broadcastCtx, message.ChatID, users, text)
+	}()
+
+	return c.responseSender.SendBotResponse(ctx, sender.BotResponse{
+		ChatID: message.ChatID,
+		Text:   fmt.Sprintf("Рассылка начата %s\n\nПолучателей: %d", domain.EmojiLoudspeaker, len(users)),
+	})
+}
+
+func (c *Console) broadcast(ctx context.Context, adminChatID int64, users []domain.User, text string) {
+	var sent, failed int
+	for _, u := range users {
+		if ctx.Err() != nil {
+			break
+		}
+
+		// user id is a chat id of private chat with user
+		if err := c.broadcastSender.SendBotResponse(ctx, sender.BotResponse{
+			ChatID: u.ID,
+			Text:   fmt.Sprintf("%s *Объявление*\n\n%s", domain.EmojiLoudspeaker, text),
+		}); err != nil {
+			logging.Printf(ctx, "[WARN] failed to send broadcast to user %d: %v", u.ID, err)
+			failed++
+
+			// user won't receive reminders either, so reminders are disabled until user enables them again
+			if errors.Is(err, sender.ErrBotBlocked) || errors.Is(err, sender.ErrChatNotFound) {
+				if err = c.store.SetUserStatus(ctx, u.ID, domain.UserStatusInactive); err != nil {
+					logging.Printf(ctx, "[WARN] failed to deactivate user %d: %v", u.ID, err)
+				}
+			}
+			continue
+		}
+		sent++
+	}
+
+	text = fmt.Sprintf("Рассылка завершена %s\n\nОтправлено: %d\nОшибок: %d", domain.EmojiWhiteHeavyCheckMark, sent, failed)
+	if skipped := len(users) - sent - failed; skipped > 0 {
+		logging.Printf(ctx, "[WARN] broadcast is stopped on shutdown, sent %d, failed %d, skipped %d", sent, failed, skipped)
+		text = fmt.Sprintf("Рассылка прервана остановкой бота %s\n\nОтправлено: %d\nОшибок: %d\nНе отправлено: %d", domain.EmojiCrossMark, sent, failed, skipped)
+	} else {
+		logging.Printf(ctx, "[INFO] broadcast finished, sent %d, failed %d", sent, failed)
+	}
+
+	// report is sent even if broadcast is stopped, outbox is stopped after the console
+	if err := c.responseSender.SendBotResponse(context.WithoutCancel(ctx), sender.BotResponse{ChatID: adminChatID, Text: text}); err != nil {
+		logging.Printf(ctx, "[WARN] failed to send broadcast report: %v", err)
+	}
+}
+
+func (c *Console) onMaintenanceCommand(ctx context.Context, message domain.TgMessage) error {
+	enabled := !c.maintenance.Load()
+	if err := c.store.SetMaintenance(ctx, enabled); err != nil {
+		return err
+	}
+	c.maintenance.Store(enabled)
+
+	logging.Printf(ctx, "[WARN] admin %d set maintenance mode to %t", message.UserID, enabled)
+
+	text := fmt.Sprintf("*Режим обслуживания включён* %s\n\nПользователи получат уведомление вместо ответа на команды, режим сохраняется после перезапуска. Чтобы выключить режим, повторите команду %s",
+		domain.EmojiHammerAndWrench, domain.BotCommandMaintenance.Markdown())
+	if !enabled {
+		text = fmt.Sprintf("*Режим обслуживания выключен* %s", domain.EmojiWhiteHeavyCheckMark)
+	}
+
+	return c.responseSender.SendBotResponse(ctx, sender.BotResponse{ChatID: message.ChatID, Text: text})
+}
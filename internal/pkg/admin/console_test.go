@@ -0,0 +1,678 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/sender"
+	"github.com/mezk/tg-reminder/internal/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAdminID int64 = 1001
+	testUserID  int64 = 2002
+	testChatID  int64 = 3003
+)
+
+// nolint:paralleltest // test modifies package level function timeNowUTC.
+func TestConsole_OnMessage(t *testing.T) {
+	var dbError = errors.New("db error")
+
+	testCases := []struct {
+		name      string
+		message   domain.TgMessage
+		now       time.Time
+		setMocks  func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock)
+		expPassed bool
+		expErr    string
+	}{
+		{
+			name:    "success: stats cmd",
+			message: domain.TgMessage{ChatID: testAdminID, UserID: testAdminID, Text: "/admin_stats"},
+			now:     time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetStatsFunc = func(_ context.Context) (domain.Stats, error) {
+					return domain.Stats{
+						UsersActive:           3,
+						UsersInactive:         2,
+						UsersBlocked:          1,
+						RemindersPending:      10,
+						RemindersDone:         20,
+						RemindersExhausted:    5,
+						OldestOverdueRemindAt: time.Date(2024, 1, 1, 11, 58, 30, 0, time.UTC),
+					}, nil
+				}
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID: testAdminID,
+						Text:   "*Статистика* 📊\n\n*Пользователи:* 6\n\t• активные: 3\n\t• неактивные: 2\n\t• заблокированные: 1\n\n*Напоминания:*\n\t• ожидают: 10\n\t• выполнены: 20\n\t• попытки исчерпаны: 5\n\n*Задержка уведомлений:* 1m30s",
+					}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name:    "success: users cmd",
+			message: domain.TgMessage{ChatID: testAdminID, UserID: testAdminID, Text: "/admin_users"},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetUsersFunc = func(_ context.Context, limit int64) ([]domain.User, error) {
+					a.EqualValues(50, limit)
+					return []domain.User{
+						{ID: 1, Name: "foo", Status: domain.UserStatusActive},
+						{ID: 2, Name: "bar", Status: domain.UserStatusBlocked},
+					}, nil
+				}
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID: testAdminID,
+						Text:   "*ПОЛЬЗОВАТЕЛИ* (последние 50)\n\n`1` @foo — active\n`2` @bar — blocked",
+					}, response)
+					a.Len(opts, 1)
+					return nil
+				}
+			},
+		},
+		{
+			name:    "success: users cmd, no users",
+			message: domain.TgMessage{ChatID: testAdminID, UserID: testAdminID, Text: "/admin_users"},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetUsersFunc = func(_ context.Context, _ int64) ([]domain.User, error) {
+					return nil, nil
+				}
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{ChatID: testAdminID, Text: "*Пользователей нет*"}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name:    "success: broadcast cmd without text",
+			message: domain.TgMessage{ChatID: testAdminID, UserID: testAdminID, Text: "/broadcast"},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, _ *StorageMock) {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID: testAdminID,
+						Text:   "Напишите текст объявления после команды, например:\n/broadcast Завтра с 10:00 до 11:00 бот будет недоступен",
+					}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name:    "success: reminder cmd",
+			message: domain.TgMessage{ChatID: testAdminID, UserID: testAdminID, Text: "/admin_reminder 42"},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					a.EqualValues(42, id)
+					return domain.Reminder{
+						ID:           id,
+						ChatID:       testChatID,
+						UserID:       testUserID,
+						Text:         "Standup",
+						RemindAt:     time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC),
+						Status:       domain.ReminderStatusAttemptsExhausted,
+						AttemptsLeft: 0,
+					}, nil
+				}
+				store.GetReminderEventsFunc = func(_ context.Context, reminderID int64) ([]domain.ReminderEvent, error) {
+					a.EqualValues(42, reminderID)
+					return []domain.ReminderEvent{
+						{Type: domain.ReminderEventCreated, ActorType: domain.ActorUser, ActorID: testUserID, CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+						{Type: domain.ReminderEventExhausted, ActorType: domain.ActorNotifier, CreatedAt: time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)},
+					}, nil
+				}
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID: testAdminID,
+						Text:   "📜 *История напоминания «Standup»*\n\n\t• 1 янв. 15:00 — создано (пользователь 2002)\n\t• 2 янв. 15:00 — попытки закончились (бот)\n\n*Пользователь:* `2002`\n*Чат:* `3003`\n*Статус:* `attempts_exhausted`\n*Время:* 2024-01-02 15:00\n*Осталось попыток:* 0",
+					}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name:    "success: reminder cmd, reminder is removed",
+			message: domain.TgMessage{ChatID: testAdminID, UserID: testAdminID, Text: "/admin_reminder 42"},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					return domain.Reminder{}, fmt.Errorf("failed to get reminder %d: %w", id, storage.ErrReminderNotFound)
+				}
+				store.GetReminderEventsFunc = func(_ context.Context, _ int64) ([]domain.ReminderEvent, error) {
+					return []domain.ReminderEvent{
+						{Type: domain.ReminderEventRemoved, ActorType: domain.ActorAdmin, ActorID: testAdminID, CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+					}, nil
+				}
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID: testAdminID,
+						Text:   "📜 *История напоминания #42*\n\n\t• 1 янв. 15:00 — удалено (администратор 1001)",
+					}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name:    "success: reminder cmd, reminder is not found",
+			message: domain.TgMessage{ChatID: testAdminID, UserID: testAdminID, Text: "/admin_reminder 42"},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetReminderFunc = func(_ context.Context, _ int64) (domain.Reminder, error) {
+					return domain.Reminder{}, storage.ErrReminderNotFound
+				}
+				store.GetReminderEventsFunc = func(_ context.Context, _ int64) ([]domain.ReminderEvent, error) {
+					return nil, nil
+				}
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{ChatID: testAdminID, Text: "Напоминание 42 не найдено 🤔"}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name:    "success: reminder cmd without id",
+			message: domain.TgMessage{ChatID: testAdminID, UserID: testAdminID, Text: "/admin_reminder"},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, _ *StorageMock) {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID: testAdminID,
+						Text:   "Напишите номер напоминания после команды, например:\n/admin\\_reminder 42",
+					}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name:    "success: admin command in group chat isn't handled",
+			message: domain.TgMessage{ChatID: testChatID, UserID: testAdminID, Text: "/admin_users"},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, _ *StorageMock) {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{ChatID: testChatID, Text: "Эта команда работает только в личном чате с ботом 🔒"}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name:      "success: admin command from not admin is passed further",
+			message:   domain.TgMessage{ChatID: testChatID, UserID: testUserID, Text: "/admin_stats"},
+			expPassed: true,
+		},
+		{
+			name:      "success: other command from admin is passed further",
+			message:   domain.TgMessage{ChatID: testAdminID, UserID: testAdminID, Text: "/my_reminders"},
+			expPassed: true,
+		},
+		{
+			name:    "error: stats cmd, can't get stats",
+			message: domain.TgMessage{ChatID: testAdminID, UserID: testAdminID, Text: "/admin_stats"},
+			setMocks: func(_ *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetStatsFunc = func(_ context.Context) (domain.Stats, error) {
+					return domain.Stats{}, dbError
+				}
+			},
+			expErr: dbError.Error(),
+		},
+		{
+			name:    "error: users cmd, can't get users",
+			message: domain.TgMessage{ChatID: testAdminID, UserID: testAdminID, Text: "/admin_users"},
+			setMocks: func(_ *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetUsersFunc = func(_ context.Context, _ int64) ([]domain.User, error) {
+					return nil, dbError
+				}
+			},
+			expErr: dbError.Error(),
+		},
+		{
+			name:    "error: reminder cmd, can't get reminder",
+			message: domain.TgMessage{ChatID: testAdminID, UserID: testAdminID, Text: "/admin_reminder 42"},
+			setMocks: func(_ *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetReminderFunc = func(_ context.Context, _ int64) (domain.Reminder, error) {
+					return domain.Reminder{}, dbError
+				}
+			},
+			expErr: dbError.Error(),
+		},
+		{
+			name:    "error: reminder cmd, can't get events",
+			message: domain.TgMessage{ChatID: testAdminID, UserID: testAdminID, Text: "/admin_reminder 42"},
+			setMocks: func(_ *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					return domain.Reminder{ID: id}, nil
+				}
+				store.GetReminderEventsFunc = func(_ context.Context, _ int64) ([]domain.ReminderEvent, error) {
+					return nil, dbError
+				}
+			},
+			expErr: dbError.Error(),
+		},
+		{
+			name:    "error: broadcast cmd, can't get users",
+			message: domain.TgMessage{ChatID: testAdminID, UserID: testAdminID, Text: "/broadcast hello"},
+			setMocks: func(_ *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetUsersByStatusFunc = func(_ context.Context, _ domain.UserStatus) ([]domain.User, error) {
+					return nil, dbError
+				}
+			},
+			expErr: dbError.Error(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !tc.now.IsZero() {
+				tmpTimeNowUTC := timeNowUTC
+				defer func() {
+					timeNowUTC = tmpTimeNowUTC
+				}()
+				timeNowUTC = func() time.Time {
+					return tc.now
+				}
+			}
+
+			a := assert.New(t)
+			senderMock := &ResponseSenderMock{}
+			storeMock := &StorageMock{}
+			if tc.setMocks != nil {
+				tc.setMocks(a, senderMock, storeMock)
+			}
+
+			receiverMock := &UpdateReceiverMock{
+				OnMessageFunc: func(ctx context.Context, message domain.TgMessage) error {
+					a.Equal(tc.message, message)
+					if message.UserID == testAdminID {
+						a.Equal(domain.Actor{Type: domain.ActorAdmin, ID: testAdminID}, domain.ActorFromContext(ctx), "changes are made by admin")
+					}
+					return nil
+				},
+			}
+
+			console := newTestConsole(t, receiverMock, senderMock, nil, storeMock)
+
+			actErr := console.OnMessage(context.TODO(), tc.message)
+
+			if tc.expErr != "" {
+				a.EqualError(actErr, tc.expErr)
+			} else {
+				a.NoError(actErr)
+			}
+
+			a.Equal(tc.expPassed, len(receiverMock.OnMessageCalls()) == 1)
+		})
+	}
+}
+
+func TestConsole_OnCallbackQuery(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		callback  domain.TgCallbackQuery
+		setMocks  func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock)
+		expPassed bool
+		expErr    string
+	}{
+		{
+			name:     "success: block user",
+			callback: domain.TgCallbackQuery{ChatID: testChatID, UserID: testAdminID, Data: "btn_admin_block/2002"},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.SetUserStatusFunc = func(_ context.Context, id int64, status domain.UserStatus, _ ...domain.OutboxMessage) error {
+					a.Equal(testUserID, id)
+					a.Equal(domain.UserStatusBlocked, status)
+					return nil
+				}
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{ChatID: testChatID, Text: "Пользователь `2002` заблокирован 🚫"}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name:     "success: unblock user",
+			callback: domain.TgCallbackQuery{ChatID: testChatID, UserID: testAdminID, Data: "btn_admin_unblock/2002"},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.SetUserStatusFunc = func(_ context.Context, id int64, status domain.UserStatus, _ ...domain.OutboxMessage) error {
+					a.Equal(testUserID, id)
+					a.Equal(domain.UserStatusActive, status)
+					return nil
+				}
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{ChatID: testChatID, Text: "Пользователь `2002` разблокирован ✅"}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name:     "success: user not found",
+			callback: domain.TgCallbackQuery{ChatID: testChatID, UserID: testAdminID, Data: "btn_admin_block/2002"},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.SetUserStatusFunc = func(_ context.Context, _ int64, _ domain.UserStatus, _ ...domain.OutboxMessage) error {
+					return storage.ErrUserNotFound
+				}
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{ChatID: testChatID, Text: "Пользователь `2002` не найден 🤔"}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name:     "success: admin can't be blocked",
+			callback: domain.TgCallbackQuery{ChatID: testChatID, UserID: testAdminID, Data: "btn_admin_block/1001"},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, _ *StorageMock) {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{ChatID: testChatID, Text: "Нельзя изменить статус администратора 🚫"}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name:      "success: admin button from not admin is passed further",
+			callback:  domain.TgCallbackQuery{ChatID: testChatID, UserID: testUserID, Data: "btn_admin_block/1001"},
+			expPassed: true,
+		},
+		{
+			name:     "error: invalid user id",
+			callback: domain.TgCallbackQuery{ChatID: testChatID, UserID: testAdminID, Data: "btn_admin_block/foo"},
+			expErr:   `can't parse userID: strconv.ParseInt: parsing "foo": invalid syntax`,
+		},
+		{
+			name:     "error: can't set user status",
+			callback: domain.TgCallbackQuery{ChatID: testChatID, UserID: testAdminID, Data: "btn_admin_block/2002"},
+			setMocks: func(_ *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SetUserStatusFunc = func(_ context.Context, _ int64, _ domain.UserStatus, _ ...domain.OutboxMessage) error {
+					return errors.New("db error")
+				}
+			},
+			expErr: "db error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := assert.New(t)
+			senderMock := &ResponseSenderMock{}
+			storeMock := &StorageMock{}
+			if tc.setMocks != nil {
+				tc.setMocks(a, senderMock, storeMock)
+			}
+
+			receiverMock := &UpdateReceiverMock{
+				OnCallbackQueryFunc: func(_ context.Context, callback domain.TgCallbackQuery) error {
+					a.Equal(tc.callback, callback)
+					return nil
+				},
+			}
+
+			console := newTestConsole(t, receiverMock, senderMock, nil, storeMock)
+
+			actErr := console.OnCallbackQuery(context.TODO(), tc.callback)
+
+			if tc.expErr != "" {
+				a.EqualError(actErr, tc.expErr)
+			} else {
+				a.NoError(actErr)
+			}
+
+			a.Equal(tc.expPassed, len(receiverMock.OnCallbackQueryCalls()) == 1)
+		})
+	}
+}
+
+func TestConsole_Broadcast(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu           sync.Mutex
+		broadcasted  []int64
+		adminReplies []string
+	)
+
+	senderMock := &ResponseSenderMock{
+		SendBotResponseFunc: func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, testAdminID, response.ChatID)
+			adminReplies = append(adminReplies, response.Text)
+			return nil
+		},
+	}
+	broadcastSenderMock := &ResponseSenderMock{
+		SendBotResponseFunc: func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, "📢 *Объявление*\n\nЗавтра бот\nбудет недоступен", response.Text)
+			broadcasted = append(broadcasted, response.ChatID)
+			if response.ChatID == 2 {
+				return fmt.Errorf("can't send message to telegram: %w: Forbidden: bot was blocked by the user", sender.ErrBotBlocked)
+			}
+			if response.ChatID == 3 {
+				return errors.New("connection reset by peer")
+			}
+			return nil
+		},
+	}
+	storeMock := &StorageMock{
+		GetUsersByStatusFunc: func(_ context.Context, status domain.UserStatus) ([]domain.User, error) {
+			assert.Equal(t, domain.UserStatusActive, status)
+			return []domain.User{{ID: 1}, {ID: 2}, {ID: 3}}, nil
+		},
+		SetUserStatusFunc: func(_ context.Context, id int64, status domain.UserStatus, _ ...domain.OutboxMessage) error {
+			assert.EqualValues(t, 2, id, "only user who blocked the bot is deactivated")
+			assert.Equal(t, domain.UserStatusInactive, status)
+			return nil
+		},
+	}
+
+	console := newTestConsole(t, nil, senderMock, broadcastSenderMock, storeMock)
+
+	require.NoError(t, console.OnMessage(context.TODO(), domain.TgMessage{
+		ChatID: testAdminID,
+		UserID: testAdminID,
+		Text:   "/broadcast Завтра бот\nбудет недоступен",
+	}))
+	console.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []int64{1, 2, 3}, broadcasted)
+	assert.ElementsMatch(t, []string{
+		"Рассылка начата 📢\n\nПолучателей: 3",
+		"Рассылка завершена ✅\n\nОтправлено: 1\nОшибок: 2",
+	}, adminReplies)
+	assert.Len(t, storeMock.SetUserStatusCalls(), 1)
+}
+
+func TestConsole_Maintenance(t *testing.T) {
+	t.Parallel()
+
+	var replies []sender.BotResponse
+	senderMock := &ResponseSenderMock{
+		SendBotResponseFunc: func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+			replies = append(replies, response)
+			return nil
+		},
+	}
+	receiverMock := &UpdateReceiverMock{
+		OnMessageFunc: func(_ context.Context, _ domain.TgMessage) error {
+			return nil
+		},
+		OnCallbackQueryFunc: func(_ context.Context, _ domain.TgCallbackQuery) error {
+			return nil
+		},
+	}
+
+	storeMock := &StorageMock{
+		SetMaintenanceFunc: func(_ context.Context, _ bool) error {
+			return nil
+		},
+	}
+
+	console := newTestConsole(t, receiverMock, senderMock, nil, storeMock)
+
+	// enable maintenance mode
+	require.NoError(t, console.OnMessage(context.TODO(), domain.TgMessage{ChatID: testAdminID, UserID: testAdminID, Text: "/maintenance"}))
+
+	// user gets maintenance notice
+	require.NoError(t, console.OnMessage(context.TODO(), domain.TgMessage{ChatID: testChatID, UserID: testUserID, Text: "/my_reminders"}))
+	require.NoError(t, console.OnCallbackQuery(context.TODO(), domain.TgCallbackQuery{ChatID: testChatID, UserID: testUserID, Data: "btn_edit_reminder"}))
+	assert.Empty(t, receiverMock.OnMessageCalls())
+	assert.Empty(t, receiverMock.OnCallbackQueryCalls())
+
+	// admin still works with the bot
+	require.NoError(t, console.OnMessage(context.TODO(), domain.TgMessage{ChatID: testAdminID, UserID: testAdminID, Text: "/my_reminders"}))
+	assert.Len(t, receiverMock.OnMessageCalls(), 1)
+
+	// disable maintenance mode
+	require.NoError(t, console.OnMessage(context.TODO(), domain.TgMessage{ChatID: testAdminID, UserID: testAdminID, Text: "/maintenance"}))
+	require.NoError(t, console.OnMessage(context.TODO(), domain.TgMessage{ChatID: testChatID, UserID: testUserID, Text: "/my_reminders"}))
+	assert.Len(t, receiverMock.OnMessageCalls(), 2)
+
+	const notice = "🛠️ Бот на техническом обслуживании, пожалуйста, попробуйте позже."
+	assert.Equal(t, []sender.BotResponse{
+		{ChatID: testAdminID, Text: "*Режим обслуживания включён* 🛠️\n\nПользователи получат уведомление вместо ответа на команды, режим сохраняется после перезапуска. Чтобы выключить режим, повторите команду /maintenance"},
+		{ChatID: testChatID, Text: notice},
+		{ChatID: testChatID, Text: notice},
+		{ChatID: testAdminID, Text: "*Режим обслуживания выключен* ✅"},
+	}, replies)
+
+	// maintenance mode is stored, so it survives restart
+	require.Len(t, storeMock.SetMaintenanceCalls(), 2)
+	assert.True(t, storeMock.SetMaintenanceCalls()[0].Enabled)
+	assert.False(t, storeMock.SetMaintenanceCalls()[1].Enabled)
+}
+
+func TestConsole_Maintenance_loadedOnStart(t *testing.T) {
+	t.Parallel()
+
+	senderMock := &ResponseSenderMock{
+		SendBotResponseFunc: func(_ context.Context, _ sender.BotResponse, _ ...sender.BotResponseOption) error {
+			return nil
+		},
+	}
+	receiverMock := &UpdateReceiverMock{}
+	storeMock := &StorageMock{
+		GetMaintenanceFunc: func(_ context.Context) (bool, error) {
+			return true, nil
+		},
+	}
+
+	console := newTestConsole(t, receiverMock, senderMock, nil, storeMock)
+
+	require.NoError(t, console.OnMessage(context.TODO(), domain.TgMessage{ChatID: testChatID, UserID: testUserID, Text: "/my_reminders"}))
+	assert.Empty(t, receiverMock.OnMessageCalls())
+	require.Len(t, senderMock.SendBotResponseCalls(), 1)
+	assert.Equal(t, "🛠️ Бот на техническом обслуживании, пожалуйста, попробуйте позже.", senderMock.SendBotResponseCalls()[0].Response.Text)
+
+	storeMock.GetMaintenanceFunc = func(_ context.Context) (bool, error) {
+		return false, errors.New("db error")
+	}
+	_, err := New(context.TODO(), receiverMock, senderMock, nil, storeMock, []int64{testAdminID})
+	require.EqualError(t, err, "db error")
+}
+
+func TestConsole_Maintenance_storageError(t *testing.T) {
+	t.Parallel()
+
+	senderMock := &ResponseSenderMock{}
+	storeMock := &StorageMock{
+		SetMaintenanceFunc: func(_ context.Context, _ bool) error {
+			return errors.New("db error")
+		},
+	}
+
+	console := newTestConsole(t, nil, senderMock, nil, storeMock)
+
+	// mode isn't changed if it isn't stored
+	require.EqualError(t, console.OnMessage(context.TODO(), domain.TgMessage{ChatID: testAdminID, UserID: testAdminID, Text: "/maintenance"}), "db error")
+	assert.False(t, console.maintenance.Load())
+	assert.Empty(t, senderMock.SendBotResponseCalls())
+}
+
+// newTestConsole creates console of test admin, maintenance mode is disabled unless store says otherwise.
+func newTestConsole(t *testing.T, next UpdateReceiver, responseSender, broadcastSender ResponseSender, store *StorageMock) *Console {
+	t.Helper()
+
+	if store == nil {
+		store = &StorageMock{}
+	}
+	if store.GetMaintenanceFunc == nil {
+		store.GetMaintenanceFunc = func(_ context.Context) (bool, error) {
+			return false, nil
+		}
+	}
+
+	console, err := New(context.TODO(), next, responseSender, broadcastSender, store, []int64{testAdminID})
+	require.NoError(t, err)
+
+	return console
+}
+
+func TestConsole_Run(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu           sync.Mutex
+		adminReplies []string
+		started      = make(chan struct{})
+	)
+
+	senderMock := &ResponseSenderMock{
+		SendBotResponseFunc: func(ctx context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+			mu.Lock()
+			defer mu.Unlock()
+			assert.NoError(t, ctx.Err(), "report is sent on shutdown")
+			adminReplies = append(adminReplies, response.Text)
+			return nil
+		},
+	}
+	broadcastSenderMock := &ResponseSenderMock{
+		SendBotResponseFunc: func(ctx context.Context, _ sender.BotResponse, _ ...sender.BotResponseOption) error {
+			// rate limited sender waits until ctx is cancelled
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	storeMock := &StorageMock{
+		GetUsersByStatusFunc: func(_ context.Context, _ domain.UserStatus) ([]domain.User, error) {
+			return []domain.User{{ID: 1}, {ID: 2}, {ID: 3}}, nil
+		},
+	}
+
+	console := newTestConsole(t, nil, senderMock, broadcastSenderMock, storeMock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		console.Run(ctx)
+		close(done)
+	}()
+
+	require.NoError(t, console.OnMessage(context.TODO(), domain.TgMessage{
+		ChatID: testAdminID,
+		UserID: testAdminID,
+		Text:   "/broadcast hello",
+	}))
+	<-started
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("console didn't stop broadcast on shutdown")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{
+		"Рассылка начата 📢\n\nПолучателей: 3",
+		"Рассылка прервана остановкой бота ❌\n\nОтправлено: 0\nОшибок: 1\nНе отправлено: 2",
+	}, adminReplies)
+	assert.Len(t, broadcastSenderMock.SendBotResponseCalls(), 1)
+}
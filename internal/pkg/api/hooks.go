@@ -0,0 +1,90 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+	"github.com/mezk/tg-reminder/internal/pkg/sender"
+)
+
+// createHookReminderRequest - request of incoming webhook to create reminder.
+type createHookReminderRequest struct {
+	Text     string                  `json:"text"`
+	When     string                  `json:"when"` // time expression as in messages to the bot, e.g. "через 30 дней"
+	Priority domain.ReminderPriority `json:"priority"`
+}
+
+// createHookReminder creates reminder by incoming webhook and confirms it in chat of the token owner.
+func (s *Server) createHookReminder(w http.ResponseWriter, r *http.Request, token domain.APIToken) error {
+	var req createHookReminderRequest
+	if err := readJSON(w, r, &req); err != nil {
+		return err
+	}
+
+	when := strings.TrimSpace(req.When)
+	if when == "" {
+		return newError(http.StatusUnprocessableEntity, "when is required, e.g. \"через 30 дней\"")
+	}
+
+	now := timeNowUTC()
+
+	// time is parsed the same way as in the bot, time of today which has already passed is moved to tomorrow
+	remindAt, err := domain.ParseRemindAt(when, now)
+	if err != nil {
+		logging.Printf(r.Context(), "[WARN] failed to parse remindAt from %s: %s", when, err)
+		return newError(http.StatusUnprocessableEntity, "can't parse time %q", when)
+	}
+
+	if remindAt, _, err = domain.CorrectRemindAt(remindAt, now); err != nil {
+		if errors.Is(err, domain.ErrRemindAtInPast) {
+			return newError(http.StatusUnprocessableEntity, "%s", err)
+		}
+		return err
+	}
+
+	reminder := domain.Reminder{
+		ChatID:   token.ChatID,
+		UserID:   token.UserID,
+		Text:     strings.TrimSpace(req.Text),
+		RemindAt: remindAt.UTC(),
+		Status:   domain.ReminderStatusPending,
+		Priority: req.Priority,
+	}
+	if err = reminder.Validate(now); err != nil {
+		return newError(http.StatusUnprocessableEntity, "%s", err)
+	}
+
+	if reminder.Priority == "" {
+		reminder.Priority = domain.ReminderPriorityNormal
+	}
+	reminder.AttemptsLeft = reminder.Priority.Attempts()
+
+	id, err := s.store.SaveReminder(r.Context(), reminder, nil)
+	if err != nil {
+		return err
+	}
+
+	if reminder, err = s.store.GetReminder(r.Context(), id); err != nil {
+		return err
+	}
+
+	// reminder is already saved, so webhook isn't asked to retry if confirmation fails
+	if err = s.responseSender.SendBotResponse(r.Context(), sender.BotResponse{
+		ChatID: reminder.ChatID,
+		Text: fmt.Sprintf("*Напоминание создано вебхуком* %s\n\nНапомню о *%s*\n%s *%s*",
+			domain.EmojiHook,
+			reminder.Text,
+			domain.EmojiAlarmClock, domain.FormatRemindAt(reminder.RemindAt, now),
+		),
+	}); err != nil {
+		logging.Printf(r.Context(), "[ERROR] failed to confirm reminder %d created by webhook: %v", reminder.ID, err)
+	}
+
+	writeJSON(r.Context(), w, http.StatusCreated, newReminderJSON(reminder))
+
+	return nil
+}
@@ -0,0 +1,267 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/storage"
+)
+
+// errReminderNotFound - reminder doesn't exist or belongs to another user or chat.
+var errReminderNotFound = errors.New("reminder is not found")
+
+// reminderJSON - reminder representation in API.
+type reminderJSON struct {
+	ID           int64                   `json:"id"`
+	Text         string                  `json:"text"`
+	RemindAt     time.Time               `json:"remind_at"`
+	Status       domain.ReminderStatus   `json:"status"`
+	Priority     domain.ReminderPriority `json:"priority"`
+	AttemptsLeft byte                    `json:"attempts_left"`
+	Checklist    []checklistItemJSON     `json:"checklist"`
+	Channels     []domain.Channel        `json:"channels"` // empty if reminder is delivered by user's default channels
+	CreatedAt    time.Time               `json:"created_at"`
+	ModifiedAt   time.Time               `json:"modified_at"`
+}
+
+// checklistItemJSON - checklist item representation in API.
+type checklistItemJSON struct {
+	ID   int64  `json:"id"`
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+func newReminderJSON(r domain.Reminder) reminderJSON {
+	res := reminderJSON{
+		ID:           r.ID,
+		Text:         r.Text,
+		RemindAt:     r.RemindAt.UTC(),
+		Status:       r.Status,
+		Priority:     r.Priority,
+		AttemptsLeft: r.AttemptsLeft,
+		Checklist:    make([]checklistItemJSON, 0, len(r.Checklist)),
+		Channels:     append([]domain.Channel{}, r.Channels...),
+		CreatedAt:    r.CreatedAt.UTC(),
+		ModifiedAt:   r.ModifiedAt.UTC(),
+	}
+	for _, item := range r.Checklist {
+		res.Checklist = append(res.Checklist, checklistItemJSON{ID: item.ID, Text: item.Text, Done: item.Done})
+	}
+
+	return res
+}
+
+// createReminderRequest - request to create reminder.
+type createReminderRequest struct {
+	Text      string                  `json:"text"`
+	RemindAt  time.Time               `json:"remind_at"`
+	Priority  domain.ReminderPriority `json:"priority"`
+	Checklist []string                `json:"checklist"`
+	Channels  []string                `json:"channels"`
+}
+
+// updateReminderRequest - request to change reminder, only given fields are changed.
+type updateReminderRequest struct {
+	RemindAt *time.Time             `json:"remind_at"`
+	Status   *domain.ReminderStatus `json:"status"`
+	Channels *[]string              `json:"channels"`
+}
+
+// parseChannels parses channels of request, empty list means user's default channels.
+func parseChannels(names []string) (domain.Channels, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	channels, err := domain.ParseChannels(strings.Join(names, ","))
+	if err != nil {
+		return nil, newError(http.StatusUnprocessableEntity, "%s", err)
+	}
+
+	return channels, nil
+}
+
+func (s *Server) listReminders(w http.ResponseWriter, r *http.Request, token domain.APIToken) error {
+	var status domain.ReminderStatus
+	if q := r.URL.Query().Get("status"); q != "" {
+		var err error
+		if status, err = domain.ParseReminderStatus(q); err != nil {
+			return newError(http.StatusBadRequest, "%s", err)
+		}
+	}
+
+	reminders, err := s.store.GetUserReminders(r.Context(), token.UserID, token.ChatID, status)
+	if err != nil {
+		return err
+	}
+
+	res := struct {
+		Reminders []reminderJSON `json:"reminders"`
+	}{Reminders: make([]reminderJSON, 0, len(reminders))}
+	for _, reminder := range reminders {
+		res.Reminders = append(res.Reminders, newReminderJSON(reminder))
+	}
+
+	writeJSON(r.Context(), w, http.StatusOK, res)
+
+	return nil
+}
+
+func (s *Server) createReminder(w http.ResponseWriter, r *http.Request, token domain.APIToken) error {
+	var req createReminderRequest
+	if err := readJSON(w, r, &req); err != nil {
+		return err
+	}
+
+	reminder := domain.Reminder{
+		ChatID:   token.ChatID,
+		UserID:   token.UserID,
+		Text:     strings.TrimSpace(req.Text),
+		RemindAt: req.RemindAt.UTC(),
+		Status:   domain.ReminderStatusPending,
+		Priority: req.Priority,
+	}
+	if err := reminder.Validate(timeNowUTC()); err != nil {
+		return newError(http.StatusUnprocessableEntity, "%s", err)
+	}
+
+	if reminder.Priority == "" {
+		reminder.Priority = domain.ReminderPriorityNormal
+	}
+
+	channels, err := parseChannels(req.Channels)
+	if err != nil {
+		return err
+	}
+	reminder.Channels = channels
+	reminder.AttemptsLeft = reminder.Priority.Attempts()
+
+	for _, item := range req.Checklist {
+		if item = strings.TrimSpace(item); item != "" {
+			reminder.Checklist = append(reminder.Checklist, domain.ChecklistItem{Text: item})
+		}
+	}
+
+	id, err := s.store.SaveReminder(r.Context(), reminder, nil)
+	if err != nil {
+		return err
+	}
+
+	if reminder, err = s.store.GetReminder(r.Context(), id); err != nil {
+		return err
+	}
+
+	writeJSON(r.Context(), w, http.StatusCreated, newReminderJSON(reminder))
+
+	return nil
+}
+
+func (s *Server) getReminder(w http.ResponseWriter, r *http.Request, token domain.APIToken) error {
+	reminder, err := s.userReminder(r, token)
+	if err != nil {
+		return err
+	}
+
+	writeJSON(r.Context(), w, http.StatusOK, newReminderJSON(reminder))
+
+	return nil
+}
+
+func (s *Server) updateReminder(w http.ResponseWriter, r *http.Request, token domain.APIToken) error {
+	reminder, err := s.userReminder(r, token)
+	if err != nil {
+		return err
+	}
+
+	var req updateReminderRequest
+	if err = readJSON(w, r, &req); err != nil {
+		return err
+	}
+
+	// the whole request is validated before any change is saved
+	switch {
+	case req.RemindAt == nil && req.Status == nil && req.Channels == nil:
+		return newError(http.StatusBadRequest, "nothing to update, remind_at, status or channels is required")
+	case req.RemindAt != nil && req.Status != nil:
+		return newError(http.StatusBadRequest, "remind_at and status can't be changed together")
+	case req.RemindAt != nil:
+		// rescheduled reminder becomes pending again, like delayed one in the bot
+		if reminder.Status != domain.ReminderStatusPending && reminder.Status != domain.ReminderStatusAttemptsExhausted {
+			return newError(http.StatusConflict, "reminder with status %s can't be rescheduled", reminder.Status)
+		}
+		if !req.RemindAt.After(timeNowUTC()) {
+			return newError(http.StatusUnprocessableEntity, "%s", domain.ErrRemindAtInPast)
+		}
+	case req.Status != nil:
+		if *req.Status != domain.ReminderStatusDone {
+			return newError(http.StatusUnprocessableEntity, "status can be changed to %s only", domain.ReminderStatusDone)
+		}
+		// like the done button in the bot, only reminder which is still to be sent can be done
+		if reminder.Status != domain.ReminderStatusPending && reminder.Status != domain.ReminderStatusAttemptsExhausted {
+			return newError(http.StatusConflict, "reminder with status %s can't be done", reminder.Status)
+		}
+	}
+
+	patch := storage.ReminderPatch{Status: req.Status}
+	if req.RemindAt != nil {
+		remindAt := req.RemindAt.UTC()
+		patch.RemindAt = &remindAt
+	}
+	if req.Channels != nil {
+		channels, err := parseChannels(*req.Channels)
+		if err != nil {
+			return err
+		}
+		patch.Channels = &channels
+	}
+
+	if err = s.store.PatchReminder(r.Context(), reminder.ID, patch); err != nil {
+		return err
+	}
+
+	if reminder, err = s.store.GetReminder(r.Context(), reminder.ID); err != nil {
+		return err
+	}
+
+	writeJSON(r.Context(), w, http.StatusOK, newReminderJSON(reminder))
+
+	return nil
+}
+
+func (s *Server) removeReminder(w http.ResponseWriter, r *http.Request, token domain.APIToken) error {
+	reminder, err := s.userReminder(r, token)
+	if err != nil {
+		return err
+	}
+
+	if err = s.store.RemoveReminder(r.Context(), reminder.ID); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
+
+// userReminder returns reminder by id from request path. Reminders of other users and chats are not found.
+func (s *Server) userReminder(r *http.Request, token domain.APIToken) (domain.Reminder, error) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return domain.Reminder{}, newError(http.StatusBadRequest, "invalid reminder id %q", r.PathValue("id"))
+	}
+
+	reminder, err := s.store.GetReminder(r.Context(), id)
+	if err != nil {
+		return domain.Reminder{}, err
+	}
+
+	if reminder.UserID != token.UserID || reminder.ChatID != token.ChatID {
+		return domain.Reminder{}, newError(http.StatusNotFound, "%s", errReminderNotFound)
+	}
+
+	return reminder, nil
+}
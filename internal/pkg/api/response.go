@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+	"github.com/mezk/tg-reminder/internal/pkg/storage"
+)
+
+// maxRequestSize - max size of request body.
+const maxRequestSize = 64 << 10
+
+// apiError - error which is returned to client as is.
+type apiError struct {
+	status int
+	msg    string
+}
+
+func newError(status int, format string, args ...any) error {
+	return &apiError{status: status, msg: fmt.Sprintf(format, args...)}
+}
+
+func (e *apiError) Error() string {
+	return e.msg
+}
+
+// errorJSON - error representation in API.
+type errorJSON struct {
+	Error string `json:"error"`
+}
+
+// writeError writes error to client. Unexpected errors are logged and are hidden from client.
+func writeError(ctx context.Context, w http.ResponseWriter, err error) {
+	var apiErr *apiError
+	switch {
+	case errors.As(err, &apiErr):
+	case errors.Is(err, storage.ErrReminderNotFound):
+		apiErr = &apiError{status: http.StatusNotFound, msg: errReminderNotFound.Error()}
+	default:
+		logging.Printf(ctx, "[ERROR] failed to handle api request: %v", err)
+		apiErr = &apiError{status: http.StatusInternalServerError, msg: "internal error"}
+	}
+
+	writeJSON(ctx, w, apiErr.status, errorJSON{Error: apiErr.msg})
+}
+
+// writeJSON writes response to client, status is already sent if encoding fails, so error is only logged.
+func writeJSON(ctx context.Context, w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logging.Printf(ctx, "[WARN] failed to write api response: %v", err)
+	}
+}
+
+// readJSON decodes request body to v, unknown fields are not allowed to catch typos in requests.
+func readJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		return newError(http.StatusBadRequest, "invalid request body: %s", err)
+	}
+
+	return nil
+}
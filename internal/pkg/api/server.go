@@ -0,0 +1,198 @@
+// Package api implements REST API to manage reminders outside of Telegram.
+// Requests are authenticated with API tokens which users issue with /api_token bot command,
+// every token gives access to reminders of its user in the chat the token was issued in.
+// Incoming webhooks of CI or monitoring create reminders with hook tokens issued with /hook_token bot command.
+package api
+
+import (
+	"context"
+	_ "embed" // openapi description is embedded
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/go-pkgz/lgr"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+	"github.com/mezk/tg-reminder/internal/pkg/sender"
+	"github.com/mezk/tg-reminder/internal/pkg/storage"
+	"github.com/mezk/tg-reminder/internal/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var timeNowUTC = func() time.Time {
+	return time.Now().UTC()
+}
+
+//go:embed openapi.yaml
+var openAPI []byte
+
+// readHeaderTimeout - max time to read request headers, protects from slow clients.
+const readHeaderTimeout = 10 * time.Second
+
+// Storage - storage of reminders and API tokens.
+type Storage interface {
+	GetAPIToken(ctx context.Context, tokenHash string) (domain.APIToken, error)
+
+	GetUserReminders(ctx context.Context, userID, chatID int64, status domain.ReminderStatus) ([]domain.Reminder, error)
+	GetReminder(ctx context.Context, id int64) (domain.Reminder, error)
+	SaveReminder(ctx context.Context, reminder domain.Reminder, newMsgs func(id int64) ([]domain.OutboxMessage, error)) (int64, error)
+	PatchReminder(ctx context.Context, id int64, patch storage.ReminderPatch) error
+	RemoveReminder(ctx context.Context, id int64, msgs ...domain.OutboxMessage) error
+}
+
+// ResponseSender - sender of bot responses, reminders created by incoming webhooks are confirmed in chat.
+type ResponseSender interface {
+	SendBotResponse(ctx context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error
+}
+
+// Config - API server configuration.
+type Config struct {
+	Addr string // address to listen on, e.g. :8080
+}
+
+// Server - REST API server.
+type Server struct {
+	store          Storage
+	responseSender ResponseSender
+	cfg            Config
+	mux            *http.ServeMux
+}
+
+// handlerFunc - handler of authenticated API request, token is the token request is authenticated with.
+type handlerFunc func(w http.ResponseWriter, r *http.Request, token domain.APIToken) error
+
+// authenticateFunc returns token which request is authenticated with.
+type authenticateFunc func(ctx context.Context, r *http.Request) (domain.APIToken, error)
+
+// New creates new [Server].
+func New(store Storage, responseSender ResponseSender, cfg Config) *Server {
+	s := &Server{store: store, responseSender: responseSender, cfg: cfg, mux: http.NewServeMux()}
+
+	s.handle("GET /api/v1/reminders", "api.listReminders", s.authenticate, s.listReminders)
+	s.handle("POST /api/v1/reminders", "api.createReminder", s.authenticate, s.createReminder)
+	s.handle("GET /api/v1/reminders/{id}", "api.getReminder", s.authenticate, s.getReminder)
+	s.handle("PATCH /api/v1/reminders/{id}", "api.updateReminder", s.authenticate, s.updateReminder)
+	s.handle("DELETE /api/v1/reminders/{id}", "api.removeReminder", s.authenticate, s.removeReminder)
+
+	s.handle("POST /api/v1/hooks/{token}", "api.createHookReminder", s.authenticateHook, s.createHookReminder)
+
+	s.mux.HandleFunc("GET /api/v1/openapi.yaml", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write(openAPI)
+	})
+
+	return s
+}
+
+// Handler returns HTTP handler of the API.
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// Run serves API until ctx is cancelled, then waits for requests in progress to finish.
+func (s *Server) Run(ctx context.Context) error {
+	srv := &http.Server{Addr: s.cfg.Addr, Handler: s.mux, ReadHeaderTimeout: readHeaderTimeout}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	log.Printf("[INFO] api server listens on %s", s.cfg.Addr)
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("api server stopped: %w", err)
+	case <-ctx.Done():
+	}
+
+	if err := srv.Shutdown(context.Background()); err != nil {
+		return fmt.Errorf("failed to shutdown api server: %w", err)
+	}
+
+	return nil
+}
+
+// handle registers handler of request authenticated by authenticate. Every request gets its own correlation id and span,
+// changes of reminders are recorded as made by the owner of the token.
+func (s *Server) handle(pattern, spanName string, authenticate authenticateFunc, h handlerFunc) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		var err error
+
+		ctx := logging.ContextWithCorrelationID(r.Context(), logging.NewCorrelationID())
+		ctx, span := tracing.Start(ctx, spanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.URLPath(redactPath(r)),
+				attribute.String("correlation_id", logging.CorrelationID(ctx)),
+			),
+		)
+		defer func() { tracing.End(span, err) }()
+
+		w.Header().Set("X-Correlation-ID", logging.CorrelationID(ctx))
+
+		token, err := authenticate(ctx, r)
+		if err == nil {
+			span.SetAttributes(attribute.Int64("telegram.user_id", token.UserID), attribute.Int64("telegram.chat_id", token.ChatID))
+			ctx = logging.ContextWithUser(ctx, token.UserID, token.ChatID)
+			ctx = domain.ContextWithActor(ctx, domain.Actor{Type: domain.ActorUser, ID: token.UserID})
+			err = h(w, r.WithContext(ctx), token)
+		}
+
+		if err != nil {
+			writeError(ctx, w, err)
+			return
+		}
+
+		logging.Printf(ctx, "[DEBUG] api request %s %s is handled", r.Method, redactPath(r))
+	})
+}
+
+// authenticate returns API token from Authorization header of request.
+func (s *Server) authenticate(ctx context.Context, r *http.Request) (domain.APIToken, error) {
+	raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || strings.TrimSpace(raw) == "" {
+		return domain.APIToken{}, newError(http.StatusUnauthorized, "api token is required, get it with %s command", domain.BotCommandAPIToken)
+	}
+
+	token, err := s.store.GetAPIToken(ctx, domain.HashAPIToken(strings.TrimSpace(raw)))
+	if err != nil && !errors.Is(err, storage.ErrAPITokenNotFound) {
+		return domain.APIToken{}, err
+	}
+
+	// hook tokens are only able to create reminders by incoming webhook
+	if err != nil || token.Kind != domain.APITokenKindAPI {
+		return domain.APIToken{}, newError(http.StatusUnauthorized, "api token is invalid or revoked")
+	}
+
+	return token, nil
+}
+
+// authenticateHook returns hook token from path of incoming webhook request.
+func (s *Server) authenticateHook(ctx context.Context, r *http.Request) (domain.APIToken, error) {
+	token, err := s.store.GetAPIToken(ctx, domain.HashAPIToken(r.PathValue("token")))
+	if err != nil && !errors.Is(err, storage.ErrAPITokenNotFound) {
+		return domain.APIToken{}, err
+	}
+
+	if err != nil || token.Kind != domain.APITokenKindHook {
+		return domain.APIToken{}, newError(http.StatusUnauthorized, "hook token is invalid or revoked, get it with %s command", domain.BotCommandHookToken)
+	}
+
+	return token, nil
+}
+
+// redactPath returns path of request without hook token, it's a secret and isn't written to logs and traces.
+func redactPath(r *http.Request) string {
+	if token := r.PathValue("token"); token != "" {
+		return strings.Replace(r.URL.Path, token, "***", 1)
+	}
+
+	return r.URL.Path
+}
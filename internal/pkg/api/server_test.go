@@ -0,0 +1,701 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+	"github.com/mezk/tg-reminder/internal/pkg/sender"
+	"github.com/mezk/tg-reminder/internal/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nolint:paralleltest // test modifies package level function timeNowUTC.
+func TestServer(t *testing.T) {
+	const (
+		rawToken = "tgr_0123456789abcdef"
+		userID   = 1
+		chatID   = 2
+	)
+
+	var (
+		now        = time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+		remindAt   = now.Add(1 * time.Hour)
+		statusDone = domain.ReminderStatusDone
+		token      = domain.APIToken{TokenHash: domain.HashAPIToken(rawToken), Kind: domain.APITokenKindAPI, UserID: userID, ChatID: chatID}
+		reminder   = domain.Reminder{
+			ID:           42,
+			ChatID:       chatID,
+			UserID:       userID,
+			Text:         "buy milk",
+			CreatedAt:    now,
+			ModifiedAt:   now,
+			RemindAt:     remindAt,
+			Status:       domain.ReminderStatusPending,
+			AttemptsLeft: domain.DefaultAttemptsLeft,
+			Priority:     domain.ReminderPriorityNormal,
+			Checklist:    domain.Checklist{{ID: 7, ReminderID: 42, Text: "2%", Done: true}},
+		}
+		reminderJSON = `{"id":42,"text":"buy milk","remind_at":"2024-05-01T11:00:00Z","status":"pending","priority":"normal","attempts_left":10,` +
+			`"checklist":[{"id":7,"text":"2%","done":true}],"channels":[],"created_at":"2024-05-01T10:00:00Z","modified_at":"2024-05-01T10:00:00Z"}`
+	)
+
+	// getReminder returns reminder of the token owner by id
+	getReminder := func(_ context.Context, id int64) (domain.Reminder, error) {
+		if id != reminder.ID {
+			return domain.Reminder{}, storage.ErrReminderNotFound
+		}
+		return reminder, nil
+	}
+
+	testCases := []struct {
+		name      string
+		method    string
+		path      string
+		token     string
+		body      string
+		setMocks  func(a *assert.Assertions, store *StorageMock)
+		expStatus int
+		expBody   string
+	}{
+		{
+			name:      "error: token is missing",
+			method:    http.MethodGet,
+			path:      "/api/v1/reminders",
+			expStatus: http.StatusUnauthorized,
+			expBody:   `{"error":"api token is required, get it with /api_token command"}`,
+		},
+		{
+			name:      "error: token is revoked",
+			method:    http.MethodGet,
+			path:      "/api/v1/reminders",
+			token:     "tgr_revoked",
+			expStatus: http.StatusUnauthorized,
+			expBody:   `{"error":"api token is invalid or revoked"}`,
+		},
+		{
+			name:   "error: hook token is not an api token",
+			method: http.MethodGet,
+			path:   "/api/v1/reminders",
+			token:  "tgh_0123456789abcdef",
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetAPITokenFunc = func(context.Context, string) (domain.APIToken, error) {
+					return domain.APIToken{Kind: domain.APITokenKindHook, UserID: userID, ChatID: chatID}, nil
+				}
+			},
+			expStatus: http.StatusUnauthorized,
+			expBody:   `{"error":"api token is invalid or revoked"}`,
+		},
+		{
+			name:   "error: failed to get token",
+			method: http.MethodGet,
+			path:   "/api/v1/reminders",
+			token:  rawToken,
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetAPITokenFunc = func(context.Context, string) (domain.APIToken, error) {
+					return domain.APIToken{}, errors.New("db error")
+				}
+			},
+			expStatus: http.StatusInternalServerError,
+			expBody:   `{"error":"internal error"}`,
+		},
+		{
+			name:   "success: list reminders",
+			method: http.MethodGet,
+			path:   "/api/v1/reminders?status=pending",
+			token:  rawToken,
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetUserRemindersFunc = func(ctx context.Context, actUserID, actChatID int64, status domain.ReminderStatus) ([]domain.Reminder, error) {
+					a.EqualValues(userID, actUserID)
+					a.EqualValues(chatID, actChatID)
+					a.Equal(domain.ReminderStatusPending, status)
+					a.Equal(logging.Fields{CorrelationID: logging.CorrelationID(ctx), UserID: userID, ChatID: chatID}, logging.FieldsFromContext(ctx))
+					a.NotEmpty(logging.CorrelationID(ctx))
+					return []domain.Reminder{reminder}, nil
+				}
+			},
+			expStatus: http.StatusOK,
+			expBody:   `{"reminders":[` + reminderJSON + `]}`,
+		},
+		{
+			name:   "success: list reminders, no reminders",
+			method: http.MethodGet,
+			path:   "/api/v1/reminders",
+			token:  rawToken,
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetUserRemindersFunc = func(_ context.Context, _, _ int64, status domain.ReminderStatus) ([]domain.Reminder, error) {
+					a.Empty(status, "all statuses")
+					return nil, nil
+				}
+			},
+			expStatus: http.StatusOK,
+			expBody:   `{"reminders":[]}`,
+		},
+		{
+			name:      "error: list reminders, unknown status",
+			method:    http.MethodGet,
+			path:      "/api/v1/reminders?status=foo",
+			token:     rawToken,
+			expStatus: http.StatusBadRequest,
+			expBody:   `{"error":"unknown reminder status \"foo\""}`,
+		},
+		{
+			name:   "success: create reminder",
+			method: http.MethodPost,
+			path:   "/api/v1/reminders",
+			token:  rawToken,
+			body:   `{"text":" buy milk ","remind_at":"2024-05-01T14:00:00+03:00","priority":"high","checklist":["2%"," "]}`,
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.SaveReminderFunc = func(ctx context.Context, actReminder domain.Reminder, _ func(id int64) ([]domain.OutboxMessage, error)) (int64, error) {
+					a.Equal(domain.Reminder{
+						ChatID:       chatID,
+						UserID:       userID,
+						Text:         "buy milk",
+						RemindAt:     remindAt,
+						Status:       domain.ReminderStatusPending,
+						AttemptsLeft: domain.DefaultAttemptsLeft,
+						Priority:     domain.ReminderPriorityHigh,
+						Checklist:    domain.Checklist{{Text: "2%"}},
+					}, actReminder)
+					a.Equal(domain.Actor{Type: domain.ActorUser, ID: userID}, domain.ActorFromContext(ctx))
+					return reminder.ID, nil
+				}
+				store.GetReminderFunc = getReminder
+			},
+			expStatus: http.StatusCreated,
+			expBody:   reminderJSON,
+		},
+		{
+			name:   "success: create reminder with channels",
+			method: http.MethodPost,
+			path:   "/api/v1/reminders",
+			token:  rawToken,
+			body:   `{"text":"buy milk","remind_at":"2024-05-01T11:00:00Z","channels":["email","Push"]}`,
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.SaveReminderFunc = func(_ context.Context, actReminder domain.Reminder, _ func(id int64) ([]domain.OutboxMessage, error)) (int64, error) {
+					a.Equal(domain.Channels{domain.ChannelEmail, domain.ChannelPush}, actReminder.Channels)
+					return reminder.ID, nil
+				}
+				store.GetReminderFunc = func(context.Context, int64) (domain.Reminder, error) {
+					withChannels := reminder
+					withChannels.Checklist = nil
+					withChannels.Channels = domain.Channels{domain.ChannelEmail, domain.ChannelPush}
+					return withChannels, nil
+				}
+			},
+			expStatus: http.StatusCreated,
+			expBody: `{"id":42,"text":"buy milk","remind_at":"2024-05-01T11:00:00Z","status":"pending","priority":"normal","attempts_left":10,` +
+				`"checklist":[],"channels":["email","push"],"created_at":"2024-05-01T10:00:00Z","modified_at":"2024-05-01T10:00:00Z"}`,
+		},
+		{
+			name:      "error: create reminder, unknown channel",
+			method:    http.MethodPost,
+			path:      "/api/v1/reminders",
+			token:     rawToken,
+			body:      `{"text":"buy milk","remind_at":"2024-05-01T11:00:00Z","channels":["sms"]}`,
+			expStatus: http.StatusUnprocessableEntity,
+			expBody:   `{"error":"unknown channel \"sms\""}`,
+		},
+		{
+			name:      "error: create reminder, remind time in the past",
+			method:    http.MethodPost,
+			path:      "/api/v1/reminders",
+			token:     rawToken,
+			body:      `{"text":"buy milk","remind_at":"2024-05-01T09:00:00Z"}`,
+			expStatus: http.StatusUnprocessableEntity,
+			expBody:   `{"error":"remind at is in the past"}`,
+		},
+		{
+			name:      "error: create reminder, empty text",
+			method:    http.MethodPost,
+			path:      "/api/v1/reminders",
+			token:     rawToken,
+			body:      `{"text":" ","remind_at":"2024-05-01T11:00:00Z"}`,
+			expStatus: http.StatusUnprocessableEntity,
+			expBody:   `{"error":"reminder text is empty"}`,
+		},
+		{
+			name:      "error: create reminder, unknown priority",
+			method:    http.MethodPost,
+			path:      "/api/v1/reminders",
+			token:     rawToken,
+			body:      `{"text":"buy milk","remind_at":"2024-05-01T11:00:00Z","priority":"urgent"}`,
+			expStatus: http.StatusUnprocessableEntity,
+			expBody:   `{"error":"unknown reminder priority \"urgent\""}`,
+		},
+		{
+			name:      "error: create reminder, unknown field",
+			method:    http.MethodPost,
+			path:      "/api/v1/reminders",
+			token:     rawToken,
+			body:      `{"text":"buy milk","remindAt":"2024-05-01T11:00:00Z"}`,
+			expStatus: http.StatusBadRequest,
+			expBody:   `{"error":"invalid request body: json: unknown field \"remindAt\""}`,
+		},
+		{
+			name:   "success: get reminder",
+			method: http.MethodGet,
+			path:   "/api/v1/reminders/42",
+			token:  rawToken,
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+			},
+			expStatus: http.StatusOK,
+			expBody:   reminderJSON,
+		},
+		{
+			name:   "error: get reminder, not found",
+			method: http.MethodGet,
+			path:   "/api/v1/reminders/43",
+			token:  rawToken,
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+			},
+			expStatus: http.StatusNotFound,
+			expBody:   `{"error":"reminder is not found"}`,
+		},
+		{
+			name:   "error: get reminder of another chat",
+			method: http.MethodGet,
+			path:   "/api/v1/reminders/42",
+			token:  rawToken,
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = func(context.Context, int64) (domain.Reminder, error) {
+					other := reminder
+					other.ChatID = chatID + 1
+					return other, nil
+				}
+			},
+			expStatus: http.StatusNotFound,
+			expBody:   `{"error":"reminder is not found"}`,
+		},
+		{
+			name:      "error: get reminder, invalid id",
+			method:    http.MethodGet,
+			path:      "/api/v1/reminders/foo",
+			token:     rawToken,
+			expStatus: http.StatusBadRequest,
+			expBody:   `{"error":"invalid reminder id \"foo\""}`,
+		},
+		{
+			name:   "success: reschedule reminder",
+			method: http.MethodPatch,
+			path:   "/api/v1/reminders/42",
+			token:  rawToken,
+			body:   `{"remind_at":"2024-05-01T14:00:00+03:00"}`,
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+				store.PatchReminderFunc = func(_ context.Context, id int64, patch storage.ReminderPatch) error {
+					a.EqualValues(42, id)
+					a.Equal(storage.ReminderPatch{RemindAt: &remindAt}, patch)
+					return nil
+				}
+			},
+			expStatus: http.StatusOK,
+			expBody:   reminderJSON,
+		},
+		{
+			name:   "success: mark reminder as done",
+			method: http.MethodPatch,
+			path:   "/api/v1/reminders/42",
+			token:  rawToken,
+			body:   `{"status":"done"}`,
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+				store.PatchReminderFunc = func(_ context.Context, id int64, patch storage.ReminderPatch) error {
+					a.EqualValues(42, id)
+					a.Equal(storage.ReminderPatch{Status: &statusDone}, patch)
+					return nil
+				}
+			},
+			expStatus: http.StatusOK,
+			expBody:   reminderJSON,
+		},
+		{
+			name:   "success: change channels and mark as done",
+			method: http.MethodPatch,
+			path:   "/api/v1/reminders/42",
+			token:  rawToken,
+			body:   `{"status":"done","channels":["telegram"]}`,
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+				store.PatchReminderFunc = func(_ context.Context, id int64, patch storage.ReminderPatch) error {
+					a.EqualValues(42, id)
+					a.Equal(storage.ReminderPatch{
+						Status:   &statusDone,
+						Channels: &domain.Channels{domain.ChannelTelegram},
+					}, patch, "channels and status are changed together")
+					return nil
+				}
+			},
+			expStatus: http.StatusOK,
+			expBody:   reminderJSON,
+		},
+		{
+			name:   "success: reset channels to user's defaults",
+			method: http.MethodPatch,
+			path:   "/api/v1/reminders/42",
+			token:  rawToken,
+			body:   `{"channels":[]}`,
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+				store.PatchReminderFunc = func(_ context.Context, _ int64, patch storage.ReminderPatch) error {
+					if a.NotNil(patch.Channels) {
+						a.Nil(*patch.Channels)
+					}
+					a.Nil(patch.RemindAt)
+					a.Nil(patch.Status)
+					return nil
+				}
+			},
+			expStatus: http.StatusOK,
+			expBody:   reminderJSON,
+		},
+		{
+			name:   "error: change status to pending with channels, nothing is changed",
+			method: http.MethodPatch,
+			path:   "/api/v1/reminders/42",
+			token:  rawToken,
+			body:   `{"status":"pending","channels":["email"]}`,
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+			},
+			expStatus: http.StatusUnprocessableEntity,
+			expBody:   `{"error":"status can be changed to done only"}`,
+		},
+		{
+			name:   "error: reschedule done reminder",
+			method: http.MethodPatch,
+			path:   "/api/v1/reminders/42",
+			token:  rawToken,
+			body:   `{"remind_at":"2024-05-01T14:00:00+03:00"}`,
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = func(context.Context, int64) (domain.Reminder, error) {
+					done := reminder
+					done.Status = domain.ReminderStatusDone
+					return done, nil
+				}
+			},
+			expStatus: http.StatusConflict,
+			expBody:   `{"error":"reminder with status done can't be rescheduled"}`,
+		},
+		{
+			name:   "error: mark done reminder as done",
+			method: http.MethodPatch,
+			path:   "/api/v1/reminders/42",
+			token:  rawToken,
+			body:   `{"status":"done"}`,
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = func(context.Context, int64) (domain.Reminder, error) {
+					done := reminder
+					done.Status = domain.ReminderStatusDone
+					return done, nil
+				}
+			},
+			expStatus: http.StatusConflict,
+			expBody:   `{"error":"reminder with status done can't be done"}`,
+		},
+		{
+			name:   "error: mark missed reminder as done with channels, nothing is changed",
+			method: http.MethodPatch,
+			path:   "/api/v1/reminders/42",
+			token:  rawToken,
+			body:   `{"status":"done","channels":["email"]}`,
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = func(context.Context, int64) (domain.Reminder, error) {
+					missed := reminder
+					missed.Status = domain.ReminderStatusMissed
+					return missed, nil
+				}
+			},
+			expStatus: http.StatusConflict,
+			expBody:   `{"error":"reminder with status missed can't be done"}`,
+		},
+		{
+			name:   "error: change status to pending",
+			method: http.MethodPatch,
+			path:   "/api/v1/reminders/42",
+			token:  rawToken,
+			body:   `{"status":"pending"}`,
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+			},
+			expStatus: http.StatusUnprocessableEntity,
+			expBody:   `{"error":"status can be changed to done only"}`,
+		},
+		{
+			name:   "error: nothing to update",
+			method: http.MethodPatch,
+			path:   "/api/v1/reminders/42",
+			token:  rawToken,
+			body:   `{}`,
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+			},
+			expStatus: http.StatusBadRequest,
+			expBody:   `{"error":"nothing to update, remind_at, status or channels is required"}`,
+		},
+		{
+			name:   "success: remove reminder",
+			method: http.MethodDelete,
+			path:   "/api/v1/reminders/42",
+			token:  rawToken,
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+				store.RemoveReminderFunc = func(_ context.Context, id int64, _ ...domain.OutboxMessage) error {
+					a.EqualValues(42, id)
+					return nil
+				}
+			},
+			expStatus: http.StatusNoContent,
+		},
+		{
+			name:   "error: remove reminder, db error",
+			method: http.MethodDelete,
+			path:   "/api/v1/reminders/42",
+			token:  rawToken,
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+				store.RemoveReminderFunc = func(context.Context, int64, ...domain.OutboxMessage) error {
+					return errors.New("db error")
+				}
+			},
+			expStatus: http.StatusInternalServerError,
+			expBody:   `{"error":"internal error"}`,
+		},
+	}
+
+	tmpTimeNowUTC := timeNowUTC
+	defer func() {
+		timeNowUTC = tmpTimeNowUTC
+	}()
+	timeNowUTC = func() time.Time {
+		return now
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+			store := &StorageMock{
+				GetAPITokenFunc: func(_ context.Context, tokenHash string) (domain.APIToken, error) {
+					if tokenHash != token.TokenHash {
+						return domain.APIToken{}, storage.ErrAPITokenNotFound
+					}
+					return token, nil
+				},
+			}
+			if tc.setMocks != nil {
+				tc.setMocks(a, store)
+			}
+
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			rec := httptest.NewRecorder()
+
+			New(store, &ResponseSenderMock{}, Config{}).Handler().ServeHTTP(rec, req)
+
+			a.Equal(tc.expStatus, rec.Code)
+			a.Len(rec.Header().Get("X-Correlation-ID"), 16)
+			if tc.expBody == "" {
+				a.Empty(rec.Body.String())
+				return
+			}
+			a.Equal("application/json", rec.Header().Get("Content-Type"))
+			a.JSONEq(tc.expBody, rec.Body.String())
+		})
+	}
+}
+
+// nolint:paralleltest // test modifies package level function timeNowUTC.
+func TestServer_createHookReminder(t *testing.T) {
+	const (
+		rawToken = "tgh_0123456789abcdef"
+		userID   = 1
+		chatID   = 2
+	)
+
+	var (
+		now   = time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+		token = domain.APIToken{TokenHash: domain.HashAPIToken(rawToken), Kind: domain.APITokenKindHook, UserID: userID, ChatID: chatID}
+	)
+
+	testCases := []struct {
+		name      string
+		token     string
+		body      string
+		setMocks  func(a *assert.Assertions, store *StorageMock, responseSender *ResponseSenderMock)
+		expStatus int
+		expBody   string
+	}{
+		{
+			name:  "success: reminder is created and confirmed in chat",
+			token: rawToken,
+			body:  `{"text": "rotate cert", "when": "через 30 дней"}`,
+			setMocks: func(a *assert.Assertions, store *StorageMock, responseSender *ResponseSenderMock) {
+				var saved domain.Reminder
+				store.SaveReminderFunc = func(ctx context.Context, reminder domain.Reminder, _ func(id int64) ([]domain.OutboxMessage, error)) (int64, error) {
+					a.Equal(domain.Reminder{
+						ChatID:       chatID,
+						UserID:       userID,
+						Text:         "rotate cert",
+						RemindAt:     now.AddDate(0, 0, 30),
+						Status:       domain.ReminderStatusPending,
+						Priority:     domain.ReminderPriorityNormal,
+						AttemptsLeft: domain.DefaultAttemptsLeft,
+					}, reminder)
+					a.Equal(domain.Actor{Type: domain.ActorUser, ID: userID}, domain.ActorFromContext(ctx))
+					saved = reminder
+					return 42, nil
+				}
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					saved.ID, saved.CreatedAt, saved.ModifiedAt = id, now, now
+					return saved, nil
+				}
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID: chatID,
+						Text:   "*Напоминание создано вебхуком* 🪝\n\nНапомню о *rotate cert*\n⏰ *через 30 дней, пт 31 мая 13:00*",
+					}, response)
+					return nil
+				}
+			},
+			expStatus: http.StatusCreated,
+			expBody: `{"id":42,"text":"rotate cert","remind_at":"2024-05-31T10:00:00Z","status":"pending","priority":"normal","attempts_left":10,` +
+				`"checklist":[],"channels":[],"created_at":"2024-05-01T10:00:00Z","modified_at":"2024-05-01T10:00:00Z"}`,
+		},
+		{
+			name:  "success: confirmation failed, reminder is created anyway",
+			token: rawToken,
+			body:  `{"text": "deploy", "when": "2024-05-01 18:00", "priority": "high"}`,
+			setMocks: func(a *assert.Assertions, store *StorageMock, responseSender *ResponseSenderMock) {
+				store.SaveReminderFunc = func(_ context.Context, reminder domain.Reminder, _ func(id int64) ([]domain.OutboxMessage, error)) (int64, error) {
+					a.Equal(time.Date(2024, time.May, 1, 15, 0, 0, 0, time.UTC), reminder.RemindAt)
+					a.Equal(domain.ReminderPriorityHigh, reminder.Priority)
+					return 42, nil
+				}
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					return domain.Reminder{ID: id, ChatID: chatID, UserID: userID, Text: "deploy", RemindAt: time.Date(2024, time.May, 1, 15, 0, 0, 0, time.UTC),
+						Status: domain.ReminderStatusPending, Priority: domain.ReminderPriorityHigh, AttemptsLeft: 20, CreatedAt: now, ModifiedAt: now}, nil
+				}
+				responseSender.SendBotResponseFunc = func(context.Context, sender.BotResponse, ...sender.BotResponseOption) error {
+					return errors.New("db error")
+				}
+			},
+			expStatus: http.StatusCreated,
+			expBody: `{"id":42,"text":"deploy","remind_at":"2024-05-01T15:00:00Z","status":"pending","priority":"high","attempts_left":20,` +
+				`"checklist":[],"channels":[],"created_at":"2024-05-01T10:00:00Z","modified_at":"2024-05-01T10:00:00Z"}`,
+		},
+		{
+			name:      "error: token is invalid",
+			token:     "tgh_revoked",
+			body:      `{"text": "rotate cert", "when": "через 30 дней"}`,
+			expStatus: http.StatusUnauthorized,
+			expBody:   `{"error":"hook token is invalid or revoked, get it with /hook_token command"}`,
+		},
+		{
+			name:  "error: api token is not a hook token",
+			token: "tgr_0123456789abcdef",
+			body:  `{"text": "rotate cert", "when": "через 30 дней"}`,
+			setMocks: func(_ *assert.Assertions, store *StorageMock, _ *ResponseSenderMock) {
+				store.GetAPITokenFunc = func(context.Context, string) (domain.APIToken, error) {
+					return domain.APIToken{Kind: domain.APITokenKindAPI, UserID: userID, ChatID: chatID}, nil
+				}
+			},
+			expStatus: http.StatusUnauthorized,
+			expBody:   `{"error":"hook token is invalid or revoked, get it with /hook_token command"}`,
+		},
+		{
+			name:      "error: time is missing",
+			token:     rawToken,
+			body:      `{"text": "rotate cert"}`,
+			expStatus: http.StatusUnprocessableEntity,
+			expBody:   `{"error":"when is required, e.g. \"через 30 дней\""}`,
+		},
+		{
+			name:      "error: time can't be parsed",
+			token:     rawToken,
+			body:      `{"text": "rotate cert", "when": "когда-нибудь"}`,
+			expStatus: http.StatusUnprocessableEntity,
+			expBody:   `{"error":"can't parse time \"когда-нибудь\""}`,
+		},
+		{
+			name:      "error: time is in the past",
+			token:     rawToken,
+			body:      `{"text": "rotate cert", "when": "2024-04-30 10:00"}`,
+			expStatus: http.StatusUnprocessableEntity,
+			expBody:   `{"error":"remind at is in the past"}`,
+		},
+		{
+			name:      "error: text is empty",
+			token:     rawToken,
+			body:      `{"text": " ", "when": "через 30 дней"}`,
+			expStatus: http.StatusUnprocessableEntity,
+			expBody:   `{"error":"` + domain.ErrReminderTextEmpty.Error() + `"}`,
+		},
+	}
+
+	tmpTimeNowUTC := timeNowUTC
+	defer func() {
+		timeNowUTC = tmpTimeNowUTC
+	}()
+	timeNowUTC = func() time.Time {
+		return now
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+			store := &StorageMock{
+				GetAPITokenFunc: func(_ context.Context, tokenHash string) (domain.APIToken, error) {
+					if tokenHash != token.TokenHash {
+						return domain.APIToken{}, storage.ErrAPITokenNotFound
+					}
+					return token, nil
+				},
+			}
+			responseSender := &ResponseSenderMock{}
+			if tc.setMocks != nil {
+				tc.setMocks(a, store, responseSender)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/hooks/"+tc.token, strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+
+			New(store, responseSender, Config{}).Handler().ServeHTTP(rec, req)
+
+			a.Equal(tc.expStatus, rec.Code)
+			a.JSONEq(tc.expBody, rec.Body.String())
+		})
+	}
+}
+
+func Test_redactPath(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/hooks/tgh_secret", nil)
+	req.SetPathValue("token", "tgh_secret")
+	assert.Equal(t, "/api/v1/hooks/***", redactPath(req))
+
+	assert.Equal(t, "/api/v1/reminders/42", redactPath(httptest.NewRequest(http.MethodGet, "/api/v1/reminders/42", nil)))
+}
+
+func TestServer_OpenAPI(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	New(&StorageMock{}, &ResponseSenderMock{}, Config{}).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.yaml", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/yaml", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "openapi: 3.0.3")
+	assert.Contains(t, rec.Body.String(), "/reminders/{id}:")
+}
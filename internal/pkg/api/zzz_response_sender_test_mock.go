@@ -1,40 +1,43 @@
 // Code generated by moq; DO NOT EDIT.
 // github.com/matryer/moq
 
-package notifier
+package api
 
 import (
+	"context"
 	"github.com/mezk/tg-reminder/internal/pkg/sender"
 	"sync"
 )
 
-// Ensure, that BotResponseSenderMock does implement BotResponseSender.
+// Ensure, that ResponseSenderMock does implement ResponseSender.
 // If this is not the case, regenerate this file with moq.
-var _ BotResponseSender = &BotResponseSenderMock{}
+var _ ResponseSender = &ResponseSenderMock{}
 
-// BotResponseSenderMock is a mock implementation of BotResponseSender.
+// ResponseSenderMock is a mock implementation of ResponseSender.
 //
-//	func TestSomethingThatUsesBotResponseSender(t *testing.T) {
+//	func TestSomethingThatUsesResponseSender(t *testing.T) {
 //
-//		// make and configure a mocked BotResponseSender
-//		mockedBotResponseSender := &BotResponseSenderMock{
-//			SendBotResponseFunc: func(response sender.BotResponse, opts ...sender.BotResponseOption) error {
+//		// make and configure a mocked ResponseSender
+//		mockedResponseSender := &ResponseSenderMock{
+//			SendBotResponseFunc: func(ctx context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error {
 //				panic("mock out the SendBotResponse method")
 //			},
 //		}
 //
-//		// use mockedBotResponseSender in code that requires BotResponseSender
+//		// use mockedResponseSender in code that requires ResponseSender
 //		// and then make assertions.
 //
 //	}
-type BotResponseSenderMock struct {
+type ResponseSenderMock struct {
 	// SendBotResponseFunc mocks the SendBotResponse method.
-	SendBotResponseFunc func(response sender.BotResponse, opts ...sender.BotResponseOption) error
+	SendBotResponseFunc func(ctx context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error
 
 	// calls tracks calls to the methods.
 	calls struct {
 		// SendBotResponse holds details about calls to the SendBotResponse method.
 		SendBotResponse []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Response is the response argument value.
 			Response sender.BotResponse
 			// Opts is the opts argument value.
@@ -45,32 +48,36 @@ type BotResponseSenderMock struct {
 }
 
 // SendBotResponse calls SendBotResponseFunc.
-func (mock *BotResponseSenderMock) SendBotResponse(response sender.BotResponse, opts ...sender.BotResponseOption) error {
+func (mock *ResponseSenderMock) SendBotResponse(ctx context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error {
 	if mock.SendBotResponseFunc == nil {
-		panic("BotResponseSenderMock.SendBotResponseFunc: method is nil but BotResponseSender.SendBotResponse was just called")
+		panic("ResponseSenderMock.SendBotResponseFunc: method is nil but ResponseSender.SendBotResponse was just called")
 	}
 	callInfo := struct {
+		Ctx      context.Context
 		Response sender.BotResponse
 		Opts     []sender.BotResponseOption
 	}{
+		Ctx:      ctx,
 		Response: response,
 		Opts:     opts,
 	}
 	mock.lockSendBotResponse.Lock()
 	mock.calls.SendBotResponse = append(mock.calls.SendBotResponse, callInfo)
 	mock.lockSendBotResponse.Unlock()
-	return mock.SendBotResponseFunc(response, opts...)
+	return mock.SendBotResponseFunc(ctx, response, opts...)
 }
 
 // SendBotResponseCalls gets all the calls that were made to SendBotResponse.
 // Check the length with:
 //
-//	len(mockedBotResponseSender.SendBotResponseCalls())
-func (mock *BotResponseSenderMock) SendBotResponseCalls() []struct {
+//	len(mockedResponseSender.SendBotResponseCalls())
+func (mock *ResponseSenderMock) SendBotResponseCalls() []struct {
+	Ctx      context.Context
 	Response sender.BotResponse
 	Opts     []sender.BotResponseOption
 } {
 	var calls []struct {
+		Ctx      context.Context
 		Response sender.BotResponse
 		Opts     []sender.BotResponseOption
 	}
@@ -81,14 +88,14 @@ func (mock *BotResponseSenderMock) SendBotResponseCalls() []struct {
 }
 
 // ResetSendBotResponseCalls reset all the calls that were made to SendBotResponse.
-func (mock *BotResponseSenderMock) ResetSendBotResponseCalls() {
+func (mock *ResponseSenderMock) ResetSendBotResponseCalls() {
 	mock.lockSendBotResponse.Lock()
 	mock.calls.SendBotResponse = nil
 	mock.lockSendBotResponse.Unlock()
 }
 
 // ResetCalls reset all the calls that were made to all mocked methods.
-func (mock *BotResponseSenderMock) ResetCalls() {
+func (mock *ResponseSenderMock) ResetCalls() {
 	mock.lockSendBotResponse.Lock()
 	mock.calls.SendBotResponse = nil
 	mock.lockSendBotResponse.Unlock()
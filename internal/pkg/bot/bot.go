@@ -9,59 +9,127 @@ import (
 
 	"github.com/mezk/tg-reminder/internal/pkg/domain"
 	"github.com/mezk/tg-reminder/internal/pkg/sender"
+	"github.com/mezk/tg-reminder/internal/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-// ResponseSender - bot's response sender.
+// ResponseSender - bot's response sender. Response to a change of state isn't sent by it, the response is enqueued
+// by storage in the transaction of the change, sender is only woken up to deliver it right away.
 type ResponseSender interface {
-	SendBotResponse(response sender.BotResponse, opts ...sender.BotResponseOption) error
+	SendBotResponse(ctx context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error
+	Wakeup()
 }
 
 // Storage - bot's persistent storage.
 type Storage interface {
 	GetBotState(ctx context.Context, userID int64) (domain.BotState, error)
-	SaveBotState(ctx context.Context, state domain.BotState) error
+	SaveBotState(ctx context.Context, state domain.BotState, msgs ...domain.OutboxMessage) error
 
-	SaveUser(ctx context.Context, user domain.User) error
-	SetUserStatus(ctx context.Context, id int64, inactive domain.UserStatus) error
+	SaveUser(ctx context.Context, user domain.User, msgs ...domain.OutboxMessage) error
+	SetUserStatus(ctx context.Context, id int64, inactive domain.UserStatus, msgs ...domain.OutboxMessage) error
 
-	SaveReminder(ctx context.Context, reminder domain.Reminder) (int64, error)
+	GetUserSettings(ctx context.Context, userID int64) (domain.UserSettings, error)
+	SaveUserSettings(ctx context.Context, settings domain.UserSettings, msgs ...domain.OutboxMessage) error
+	SaveEmailVerification(ctx context.Context, settings domain.UserSettings, msg domain.OutboxMessage, msgs ...domain.OutboxMessage) error
+
+	SaveReminder(ctx context.Context, reminder domain.Reminder, newMsgs func(id int64) ([]domain.OutboxMessage, error)) (int64, error)
+	GetReminder(ctx context.Context, id int64) (domain.Reminder, error)
 	GetMyReminders(ctx context.Context, userID, chatID int64) ([]domain.Reminder, error)
-	RemoveReminder(ctx context.Context, id int64) error
-	SetReminderStatus(ctx context.Context, id int64, status domain.ReminderStatus) error
-	DelayReminder(ctx context.Context, id int64, remindAt time.Time) error
+	RemoveReminder(ctx context.Context, id int64, msgs ...domain.OutboxMessage) error
+	SetReminderStatus(ctx context.Context, id int64, status domain.ReminderStatus, msgs ...domain.OutboxMessage) error
+	DelayReminder(ctx context.Context, id int64, remindAt time.Time, msgs ...domain.OutboxMessage) error
+	SetReminderLeadTimes(ctx context.Context, id int64, leadTimes domain.LeadTimes, nextPreNoticeAt *time.Time, msgs ...domain.OutboxMessage) error
+	GetSummaryReminderIDs(ctx context.Context, userID, chatID int64, summaryKey string) ([]int64, error)
+	RescheduleMissedReminders(ctx context.Context, userID, chatID int64, ids []int64, remindAt time.Time,
+		newMsgs func(rescheduled int64) ([]domain.OutboxMessage, error)) (int64, error)
+	GetReminderEvents(ctx context.Context, reminderID int64) ([]domain.ReminderEvent, error)
+
+	AddChecklistItems(ctx context.Context, reminderID int64, items []string, msgs ...domain.OutboxMessage) error
+	GetChecklistItem(ctx context.Context, id int64) (domain.ChecklistItem, error)
+	ToggleChecklistItem(ctx context.Context, id int64, newMsgs func(reminder domain.Reminder) ([]domain.OutboxMessage, error)) (domain.Reminder, error)
+
+	SaveAPIToken(ctx context.Context, token domain.APIToken, msgs ...domain.OutboxMessage) error
+	SaveWebSession(ctx context.Context, session domain.WebSession, msgs ...domain.OutboxMessage) error
+
+	SaveWebhook(ctx context.Context, hook domain.Webhook, newMsgs func(id int64) ([]domain.OutboxMessage, error)) (int64, error)
+	GetWebhook(ctx context.Context, id int64) (domain.Webhook, error)
+	GetUserWebhooks(ctx context.Context, userID int64) ([]domain.Webhook, error)
+	RemoveWebhook(ctx context.Context, id int64, msgs ...domain.OutboxMessage) error
+	GetWebhookDeliveries(ctx context.Context, webhookID int64, limit int64) ([]domain.WebhookDelivery, error)
+}
+
+// Notifier - notifier of reminders.
+type Notifier interface {
+	CatchUp(ctx context.Context, userID int64) error
+}
+
+// commandHandler - handler of a bot command.
+type commandHandler func(ctx context.Context, message domain.TgMessage) error
+
+// Config - bot configuration.
+type Config struct {
+	WebURL   string          // public URL of the web dashboard, it's disabled if empty
+	Channels domain.Channels // channels notifications can be delivered by, email is verified only if it's configured
 }
 
 // Bot - bot implementation.
 type Bot struct {
 	responseSender ResponseSender
 	store          Storage
+	notifier       Notifier
+	cfg            Config
+	commands       map[domain.BotCommand]commandHandler
 }
 
 // New - creates a new [Bot].
-func New(responseSender ResponseSender, store Storage) *Bot {
-	return &Bot{responseSender: responseSender, store: store}
+func New(responseSender ResponseSender, store Storage, notifier Notifier, cfg Config) *Bot {
+	b := &Bot{responseSender: responseSender, store: store, notifier: notifier, cfg: cfg}
+
+	handlers := map[domain.BotCommand]commandHandler{
+		domain.BotCommandStart:            b.onStartCommand,
+		domain.BotCommandHelp:             b.onHelpCommand,
+		domain.BotCommandCreateReminder:   b.onCreateReminderCommand,
+		domain.BotCommandMyReminders:      b.onMyRemindersCommand,
+		domain.BotCommandEnableReminders:  b.onEnableRemindersCommand,
+		domain.BotCommandDisableReminders: b.onDisableRemindersCommand,
+		domain.BotCommandSettings:         b.onSettingsCommand,
+		domain.BotCommandAPIToken:         b.onAPITokenCommand,
+		domain.BotCommandHookToken:        b.onHookTokenCommand,
+		domain.BotCommandWeb:              b.onWebCommand,
+		domain.BotCommandWebhooks:         b.onWebhooksCommand,
+	}
+
+	// only registered user commands are dispatched, admin commands are handled before the bot
+	b.commands = make(map[domain.BotCommand]commandHandler, len(handlers))
+	for _, c := range domain.BotCommandsFor(domain.BotCommandAccessUser, true) {
+		if handler, ok := handlers[c.Command]; ok {
+			b.commands[c.Command] = handler
+		}
+	}
+
+	return b
 }
 
 // OnMessage - bot's reaction on a message from a user.
-// Message can contain command.
-func (b *Bot) OnMessage(ctx context.Context, message domain.TgMessage) error {
+// Message can contain command. Handling is traced as one span with the command or the state of the bot.
+func (b *Bot) OnMessage(ctx context.Context, message domain.TgMessage) (err error) {
+	// changes of reminders are recorded as made by user, unless admin console marked update as admin's
+	ctx = domain.ContextWithDefaultActor(ctx, domain.Actor{Type: domain.ActorUser, ID: message.UserID})
+
+	ctx, span := tracing.Start(ctx, "bot.OnMessage")
+	defer func() { tracing.End(span, err) }()
+
 	if message.IsCommand() {
-		switch message.Text {
-		case domain.BotCommandStart.String():
-			return b.onStartCommand(ctx, message)
-		case domain.BotCommandHelp.String():
-			return b.onHelpCommand(ctx, message)
-		case domain.BotCommandCreateReminder.String():
-			return b.onCreateReminderCommand(ctx, message)
-		case domain.BotCommandMyReminders.String():
-			return b.onMyRemindersCommand(ctx, message)
-		case domain.BotCommandEnableReminders.String():
-			return b.onEnableRemindersCommand(ctx, message)
-		case domain.BotCommandDisableReminders.String():
-			return b.onDisableRemindersCommand(ctx, message)
-		default:
-			return b.sendUnsupportedResponse(message.ChatID)
+		span.SetAttributes(attribute.String("bot.command", string(message.Command())))
+
+		handler, ok := b.commands[message.Command()]
+		if !ok {
+			return b.sendUnsupportedResponse(ctx, message.ChatID)
+		}
+		if message.Command().IsPrivateOnly() && !message.IsPrivate() {
+			return b.responseSender.SendBotResponse(ctx, sender.BotResponse{ChatID: message.ChatID, Text: domain.PrivateOnlyText})
 		}
+		return handler(ctx, message)
 	}
 
 	state, err := b.store.GetBotState(ctx, message.UserID)
@@ -69,49 +137,98 @@ func (b *Bot) OnMessage(ctx context.Context, message domain.TgMessage) error {
 		return err
 	}
 
+	span.SetAttributes(attribute.String("bot.state", string(state.Name)))
+
 	switch state.Name {
 	case domain.BotStateNameCreateReminder:
 		return b.onEnterReminderTextUserMessage(ctx, message)
-	case domain.BotStateNameEnterReminAt:
+	case domain.BotStateNameEnterReminAt, domain.BotStateNameConfirmRemindAt:
 		return b.onEnterRemindAtUserMessage(ctx, message)
 	case domain.BotStateNameEditReminder:
 		// TODO: implement edit reminder feature
 		return errors.ErrUnsupported
 	case domain.BotStateNameRemoveReminder:
 		return b.onRemoveReminderUserMessage(ctx, message)
+	case domain.BotStateNameReminderHistory:
+		return b.onReminderHistoryUserMessage(ctx, message)
+	case domain.BotStateNameAddChecklistItems:
+		return b.onEnterChecklistItemsUserMessage(ctx, message)
+	case domain.BotStateNameSettings:
+		return b.onSettingsUserMessage(ctx, message)
 	default:
-		return b.sendUnsupportedResponse(message.ChatID)
+		return b.sendUnsupportedResponse(ctx, message.ChatID)
 	}
 }
 
 // OnCallbackQuery - bot's reaction on a callback. For example, button click.
-func (b *Bot) OnCallbackQuery(ctx context.Context, callback domain.TgCallbackQuery) error {
+// Handling is traced as one span with the clicked button, see [buttonName].
+func (b *Bot) OnCallbackQuery(ctx context.Context, callback domain.TgCallbackQuery) (err error) {
+	ctx = domain.ContextWithDefaultActor(ctx, domain.Actor{Type: domain.ActorUser, ID: callback.UserID})
+
+	ctx, span := tracing.Start(ctx, "bot.OnCallbackQuery")
+	defer func() { tracing.End(span, err) }()
+
 	if callback.IsButtonClick() {
+		span.SetAttributes(attribute.String("bot.button", buttonName(callback.Data)))
+
 		switch {
 		case strings.HasPrefix(callback.Data, domain.ButtonDataPrefixReminderDone):
 			return b.onDoneReminderButton(ctx, callback)
 		case strings.HasPrefix(callback.Data, domain.ButtonDataRemoveReminder):
 			return b.onRemoveReminderButton(ctx, callback)
+		case strings.HasPrefix(callback.Data, domain.ButtonDataReminderHistory):
+			return b.onReminderHistoryButton(ctx, callback)
+		case strings.HasPrefix(callback.Data, domain.ButtonDataNoop):
+			return nil
+		case strings.HasPrefix(callback.Data, domain.ButtonDataPrefixRemindAtCalendar),
+			strings.HasPrefix(callback.Data, domain.ButtonDataPrefixRemindAtDate),
+			strings.HasPrefix(callback.Data, domain.ButtonDataPrefixRemindAtHour):
+			return b.onRemindAtPickerButton(ctx, callback)
 		case callback.IsRemindAtButtonClick():
 			return b.onRemindAtButton(ctx, callback)
+		case strings.HasPrefix(callback.Data, domain.ButtonDataConfirmRemindAt):
+			return b.onConfirmRemindAtButton(ctx, callback)
+		case strings.HasPrefix(callback.Data, domain.ButtonDataPrefixShiftRemindAt):
+			return b.onShiftRemindAtButton(ctx, callback)
+		case strings.HasPrefix(callback.Data, domain.ButtonDataReenterRemindAt):
+			return b.onReenterRemindAtButton(ctx, callback)
 		case strings.HasPrefix(callback.Data, domain.ButtonDataPrefixDelayReminder):
 			return b.onDelayReminderButton(ctx, callback)
 		case strings.HasPrefix(callback.Data, domain.ButtonDataEditReminder):
 			return b.onEditReminderButton(ctx, callback)
+		case strings.HasPrefix(callback.Data, domain.ButtonDataPrefixReminderPriority):
+			return b.onReminderPriorityButton(ctx, callback)
+		case strings.HasPrefix(callback.Data, domain.ButtonDataPrefixLeadTime):
+			return b.onLeadTimeButton(ctx, callback)
+		case strings.HasPrefix(callback.Data, domain.ButtonDataPrefixAddChecklistItems):
+			return b.onAddChecklistItemsButton(ctx, callback)
+		case strings.HasPrefix(callback.Data, domain.ButtonDataPrefixToggleChecklistItem):
+			return b.onToggleChecklistItemButton(ctx, callback)
+		case strings.HasPrefix(callback.Data, domain.ButtonDataPrefixCatchUp):
+			return b.onCatchUpButton(ctx, callback)
+		case strings.HasPrefix(callback.Data, domain.ButtonDataCatchUpSkip):
+			return b.onCatchUpSkipButton(ctx, callback)
 		default:
-			return b.sendUnsupportedResponse(callback.ChatID)
+			return b.sendUnsupportedResponse(ctx, callback.ChatID)
 		}
 	}
 
-	return b.sendUnsupportedResponse(callback.ChatID)
+	return b.sendUnsupportedResponse(ctx, callback.ChatID)
+}
+
+// buttonName returns button of callback data without its arguments, e.g. "btn_delay_reminder" for
+// "btn_delay_reminder/42/1h", so traces of clicks of the same button are grouped together.
+func buttonName(data string) string {
+	name, _, _ := strings.Cut(data, "/")
+	return name
 }
 
 var timeNowUTC = func() time.Time {
 	return time.Now().UTC()
 }
 
-func (b *Bot) sendUnsupportedResponse(chatID int64) error {
-	return b.responseSender.SendBotResponse(sender.BotResponse{
+func (b *Bot) sendUnsupportedResponse(ctx context.Context, chatID int64) error {
+	return b.responseSender.SendBotResponse(ctx, sender.BotResponse{
 		ChatID: chatID,
 		Text:   fmt.Sprintf("Я не понимаю о чём речь %s Пожалуйста, воспользуйтесь командой %s.", domain.EmojiThinkingFace, domain.BotCommandHelp),
 	})
@@ -123,32 +240,75 @@ func (b *Bot) createReminder(ctx context.Context, userID, chatID int64, remindAt
 		return err
 	}
 
-	if botState.Name != domain.BotStateNameEnterReminAt {
-		return fmt.Errorf("can't create reminder: invalid bot state: expected [%s], acttual [%s]", domain.BotStateNameEnterReminAt, botState.Name)
+	if botState.Name != domain.BotStateNameEnterReminAt && botState.Name != domain.BotStateNameConfirmRemindAt {
+		return fmt.Errorf("can't create reminder: invalid bot state: expected [%s] or [%s], acttual [%s]", domain.BotStateNameEnterReminAt, domain.BotStateNameConfirmRemindAt, botState.Name)
 	}
 
+	priority := botState.ReminderPriority()
 	remidner := domain.Reminder{
 		ChatID:       chatID,
 		UserID:       userID,
 		Text:         botState.ReminderText(),
 		RemindAt:     remindAt.UTC(),
 		Status:       domain.ReminderStatusPending,
-		AttemptsLeft: domain.DefaultAttemptsLeft,
+		AttemptsLeft: priority.Attempts(),
+		Priority:     priority,
 	}
+	for _, item := range botState.ReminderChecklist() {
+		remidner.Checklist = append(remidner.Checklist, domain.ChecklistItem{Text: item})
+	}
+
+	// response is built from id of the saved reminder and enqueued along with it
+	remidner.ID, err = b.store.SaveReminder(ctx, remidner, func(id int64) ([]domain.OutboxMessage, error) {
+		text := fmt.Sprintf(`*%s* я напомню вам о *%s* %s`, domain.MoscowTime(remidner.RemindAt).Format(domain.LayoutRemindAt), remidner.Text, domain.EmojiWhiteHeavyCheckMark)
+		if priority != domain.ReminderPriorityNormal {
+			text += fmt.Sprintf("\n\n%s приоритет", priority.Label())
+		}
+		if len(remidner.Checklist) > 0 {
+			text += fmt.Sprintf("\n\n%s Пунктов в списке: %d", domain.EmojiClipboard, len(remidner.Checklist))
+		}
+		text += "\n\nЕсли нужно, я напомню заранее, выберите когда:"
 
-	if _, err = b.store.SaveReminder(ctx, remidner); err != nil {
+		return newResponses(sender.BotResponse{ChatID: chatID, Text: text},
+			sender.WithLeadTimesButtons(id),
+			sender.WithAddChecklistItemsButton(id),
+		)
+	})
+	if err != nil {
 		return err
 	}
+	b.responseSender.Wakeup()
 
 	botState.Context = nil
 	// go to start state
 	botState.Name = domain.BotStateNameStart
-	if err = b.store.SaveBotState(ctx, botState); err != nil {
+	return b.store.SaveBotState(ctx, botState)
+}
+
+// saveWithResponse saves a change of state along with bot response: save enqueues the response in the transaction
+// of the change, so the change is never saved without its response and vice versa. Delivery starts right away.
+// Responses which don't change anything are sent by [ResponseSender.SendBotResponse].
+func (b *Bot) saveWithResponse(save func(msg domain.OutboxMessage) error, resp sender.BotResponse, opts ...sender.BotResponseOption) error {
+	msg, err := sender.NewOutboxMessage(resp, opts...)
+	if err != nil {
 		return err
 	}
 
-	return b.responseSender.SendBotResponse(sender.BotResponse{
-		ChatID: chatID,
-		Text:   fmt.Sprintf(`*%s* я напомню вам о *%s* %s`, domain.MoscowTime(remidner.RemindAt).Format(domain.LayoutRemindAt), remidner.Text, domain.EmojiWhiteHeavyCheckMark),
-	})
+	if err = save(msg); err != nil {
+		return err
+	}
+
+	b.responseSender.Wakeup()
+
+	return nil
+}
+
+// newResponses renders bot response to messages, which are enqueued by storage along with a change built by it.
+func newResponses(resp sender.BotResponse, opts ...sender.BotResponseOption) ([]domain.OutboxMessage, error) {
+	msg, err := sender.NewOutboxMessage(resp, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return []domain.OutboxMessage{msg}, nil
 }
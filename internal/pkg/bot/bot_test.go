@@ -2,7 +2,9 @@ package bot
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -38,25 +40,21 @@ func TestBot_OnCallbackQuery(t *testing.T) {
 				UserName: expUserName,
 				Data:     "btn_reminder_done/12345",
 			},
-			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
 					a.Equal(domain.BotState{
 						UserID: expUserID,
 						Name:   domain.BotStateNameStart,
 					}, botState)
 					return nil
 				}
-				store.SetReminderStatusFunc = func(_ context.Context, id int64, status domain.ReminderStatus) error {
+				store.SetReminderStatusFunc = func(_ context.Context, id int64, status domain.ReminderStatus, msgs ...domain.OutboxMessage) error {
 					a.EqualValues(12345, id)
 					a.Equal(domain.ReminderStatusDone, status)
-					return nil
-				}
-
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, _ ...sender.BotResponseOption) error {
-					a.Equal(sender.BotResponse{
+					a.Equal(sender.Render(sender.BotResponse{
 						ChatID: expChatID,
 						Text:   "Я пометил напоминание как выполненное ✅",
-					}, response)
+					}), enqueuedMessage(a, msgs))
 					return nil
 				}
 			},
@@ -69,19 +67,111 @@ func TestBot_OnCallbackQuery(t *testing.T) {
 				UserName: expUserName,
 				Data:     "btn_remove_reminder",
 			},
-			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
 					a.Equal(domain.BotState{
 						UserID: expUserID,
 						Name:   domain.BotStateNameRemoveReminder,
 					}, botState)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "Напишите номер #️⃣ напоминания для удаления.",
+					}), enqueuedMessage(a, msgs))
 					return nil
 				}
-
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, _ ...sender.BotResponseOption) error {
-					a.Equal(sender.BotResponse{
+			},
+		},
+		{
+			name: "success: catch up button",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_catch_up/0123456789abcdef/1h",
+			},
+			now: time.Date(2024, 1, 1, 11, 1, 1, 0, time.UTC),
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetSummaryReminderIDsFunc = func(_ context.Context, userID, chatID int64, summaryKey string) ([]int64, error) {
+					a.Equal(expUserID, userID)
+					a.Equal(expChatID, chatID)
+					a.Equal("0123456789abcdef", summaryKey)
+					return []int64{1, 2, 3}, nil
+				}
+				store.RescheduleMissedRemindersFunc = func(_ context.Context, userID, chatID int64, ids []int64, remindAt time.Time, newMsgs func(rescheduled int64) ([]domain.OutboxMessage, error)) (int64, error) {
+					a.Equal(expUserID, userID)
+					a.Equal(expChatID, chatID)
+					a.Equal([]int64{1, 2, 3}, ids)
+					a.Equal(time.Date(2024, 1, 1, 12, 1, 0, 0, time.UTC), remindAt)
+					msgs, err := newMsgs(3)
+					a.NoError(err)
+					a.Equal(sender.Render(sender.BotResponse{
 						ChatID: expChatID,
-						Text:   "Напишите номер #️⃣ напоминания для удаления.",
+						Text:   "*Я отложил пропущенные напоминания* 🔄\n\nНапомню о них (3) *2024-01-01 15:01* ⏰",
+					}), enqueuedMessage(a, msgs))
+					return 3, nil
+				}
+			},
+		},
+		{
+			name: "success: catch up button, no missed reminders",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_catch_up/0123456789abcdef/1h",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetSummaryReminderIDsFunc = func(_ context.Context, _, _ int64, _ string) ([]int64, error) {
+					return nil, nil
+				}
+				store.RescheduleMissedRemindersFunc = func(_ context.Context, _, _ int64, ids []int64, _ time.Time, newMsgs func(rescheduled int64) ([]domain.OutboxMessage, error)) (int64, error) {
+					a.Empty(ids)
+					msgs, err := newMsgs(0)
+					a.NoError(err)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "Пропущенных напоминаний больше нет ✅",
+					}), enqueuedMessage(a, msgs))
+					return 0, nil
+				}
+			},
+		},
+		{
+			name: "success: catch up button of summary without key reschedules nothing",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_catch_up/1h",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.RescheduleMissedRemindersFunc = func(_ context.Context, _, _ int64, ids []int64, _ time.Time, newMsgs func(rescheduled int64) ([]domain.OutboxMessage, error)) (int64, error) {
+					a.Empty(ids)
+					msgs, err := newMsgs(0)
+					a.NoError(err)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "Пропущенных напоминаний больше нет ✅",
+					}), enqueuedMessage(a, msgs))
+					return 0, nil
+				}
+			},
+		},
+		{
+			name: "success: catch up skip button",
+			message: domain.TgCallbackQuery{
+				ChatID:    expChatID,
+				UserID:    expUserID,
+				UserName:  expUserName,
+				MessageID: 42,
+				Data:      "btn_catch_up_skip",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID:        expChatID,
+						EditMessageID: 42,
+						Text:          "Пропущенные напоминания не будут отправлены ❌",
 					}, response)
 					return nil
 				}
@@ -96,26 +186,21 @@ func TestBot_OnCallbackQuery(t *testing.T) {
 				Data:     "btn_delay_reminder/12345/1h",
 			},
 			now: time.Date(2024, 1, 1, 11, 1, 1, 0, time.UTC),
-			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
 					a.Equal(domain.BotState{
 						UserID: expUserID,
 						Name:   domain.BotStateNameStart,
 					}, botState)
 					return nil
 				}
-				store.DelayReminderFunc = func(_ context.Context, id int64, remindAt time.Time) error {
+				store.DelayReminderFunc = func(_ context.Context, id int64, remindAt time.Time, msgs ...domain.OutboxMessage) error {
 					a.EqualValues(12345, id)
 					a.Equal(time.Date(2024, 1, 1, 12, 1, 0, 0, time.UTC), remindAt)
-					return nil
-				}
-
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, _ ...sender.BotResponseOption) error {
-					a.EqualValues(expChatID, response.ChatID)
-					a.Equal(sender.BotResponse{
+					a.Equal(sender.Render(sender.BotResponse{
 						ChatID: expChatID,
 						Text:   "*Я отложил напоминание* 🔄\n\nНапомню позже *2024-01-01 15:01* ⏰",
-					}, response)
+					}), enqueuedMessage(a, msgs))
 					return nil
 				}
 			},
@@ -129,7 +214,7 @@ func TestBot_OnCallbackQuery(t *testing.T) {
 				UserName: expUserName,
 				Data:     "btn_remind_at/time/20:30",
 			},
-			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
 				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
 					a.EqualValues(expUserID, userID)
 					return domain.BotState{
@@ -140,7 +225,7 @@ func TestBot_OnCallbackQuery(t *testing.T) {
 						},
 					}, nil
 				}
-				store.SaveReminderFunc = func(_ context.Context, reminder domain.Reminder) (int64, error) {
+				store.SaveReminderFunc = func(_ context.Context, reminder domain.Reminder, newMsgs func(id int64) ([]domain.OutboxMessage, error)) (int64, error) {
 					a.Equal(domain.Reminder{
 						ChatID:       expChatID,
 						UserID:       expUserID,
@@ -148,571 +233,2370 @@ func TestBot_OnCallbackQuery(t *testing.T) {
 						RemindAt:     time.Date(2024, 1, 1, 17, 30, 0, 0, time.UTC),
 						Status:       domain.ReminderStatusPending,
 						AttemptsLeft: 10,
+						Priority:     domain.ReminderPriorityNormal,
 					}, reminder)
+					msgs, err := newMsgs(1)
+					a.NoError(err)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "*2024-01-01 20:30* я напомню вам о *FooBarBaz* ✅\n\nЕсли нужно, я напомню заранее, выберите когда:",
+					}, sender.WithLeadTimesButtons(1), sender.WithAddChecklistItemsButton(1)), enqueuedMessage(a, msgs))
 					return 1, nil
 				}
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
 					a.Equal(domain.BotState{
 						UserID: expUserID,
 						Name:   domain.BotStateNameStart,
 					}, botState)
 					return nil
 				}
-
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, _ ...sender.BotResponseOption) error {
-					a.Equal(sender.BotResponse{
-						ChatID: expChatID,
-						Text:   "*2024-01-01 20:30* я напомню вам о *FooBarBaz* ✅",
-					}, response)
-					return nil
-				}
 			},
 		},
 		{
-			name: "success: edit reminder button",
+			name: "success: reminder priority button",
+			now:  time.Date(2024, 1, 1, 1, 1, 1, 0, time.UTC),
 			message: domain.TgCallbackQuery{
 				ChatID:   expChatID,
 				UserID:   expUserID,
 				UserName: expUserName,
-				Data:     "btn_edit_reminder",
+				Data:     "btn_reminder_priority/high",
 			},
-			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{
+						UserID:  expUserID,
+						Name:    domain.BotStateNameEnterReminAt,
+						Context: &domain.BotStateContext{ReminderText: "FooBarBaz"},
+					}, nil
+				}
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
 					a.Equal(domain.BotState{
 						UserID: expUserID,
-						Name:   domain.BotStateNameEditReminder,
+						Name:   domain.BotStateNameEnterReminAt,
+						Context: &domain.BotStateContext{
+							ReminderText:     "FooBarBaz",
+							ReminderPriority: domain.ReminderPriorityHigh,
+						},
 					}, botState)
-					return nil
-				}
-
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, _ ...sender.BotResponseOption) error {
-					a.Equal(sender.BotResponse{
-						ChatID: expChatID,
-						Text:   "Напишите номер #️⃣ напоминания для редактирования.",
-					}, response)
+					msg := enqueuedMessage(a, msgs)
+					a.True(strings.HasPrefix(msg.Text, "*🔺 Высокий приоритет*\n\n*Когда напомнить ❓"))
+					a.NotNil(msg.ReplyMarkup)
 					return nil
 				}
 			},
 		},
-
-		// error
 		{
-			name: "error: done reminder button, can't set reminder status",
+			name: "success: lead time button, add lead time",
+			now:  time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
 			message: domain.TgCallbackQuery{
 				ChatID:   expChatID,
 				UserID:   expUserID,
 				UserName: expUserName,
-				Data:     "btn_reminder_done/12345",
+				Data:     "btn_lead_time/12345/30m0s",
 			},
-			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
-					return nil
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					a.EqualValues(12345, id)
+					return domain.Reminder{
+						ID:        12345,
+						UserID:    expUserID,
+						ChatID:    expChatID,
+						Text:      "Meeting",
+						RemindAt:  time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC),
+						Status:    domain.ReminderStatusPending,
+						LeadTimes: domain.LeadTimes{24 * time.Hour},
+					}, nil
 				}
-				store.SetReminderStatusFunc = func(_ context.Context, id int64, status domain.ReminderStatus) error {
-					return dbError
+				store.SetReminderLeadTimesFunc = func(_ context.Context, id int64, leadTimes domain.LeadTimes, nextPreNoticeAt *time.Time, msgs ...domain.OutboxMessage) error {
+					a.EqualValues(12345, id)
+					a.Equal(domain.LeadTimes{24 * time.Hour, 30 * time.Minute}, leadTimes)
+					a.Equal(time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC), *nextPreNoticeAt)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "Я заранее напомню о *Meeting* за 1 дн., за 30 мин. 🔔",
+					}), enqueuedMessage(a, msgs))
+					return nil
 				}
 			},
-			expErr: dbError.Error(),
 		},
 		{
-			name: "error: done reminder button, can't get reminder id",
+			name: "success: lead time button, remove last lead time",
+			now:  time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
 			message: domain.TgCallbackQuery{
 				ChatID:   expChatID,
 				UserID:   expUserID,
 				UserName: expUserName,
-				Data:     "btn_reminder_done/foobarbaz",
+				Data:     "btn_lead_time/12345/30m0s",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					return domain.Reminder{
+						ID:        12345,
+						UserID:    expUserID,
+						ChatID:    expChatID,
+						Text:      "Meeting",
+						RemindAt:  time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC),
+						Status:    domain.ReminderStatusPending,
+						LeadTimes: domain.LeadTimes{30 * time.Minute},
+					}, nil
+				}
+				store.SetReminderLeadTimesFunc = func(_ context.Context, id int64, leadTimes domain.LeadTimes, nextPreNoticeAt *time.Time, msgs ...domain.OutboxMessage) error {
+					a.Empty(leadTimes)
+					a.Nil(nextPreNoticeAt)
+					msg := enqueuedMessage(a, msgs)
+					a.Equal("Заранее напоминать о *Meeting* не буду 🔕", msg.Text)
+					return nil
+				}
 			},
-			expErr: `can'p parse reminderID: strconv.ParseInt: parsing "foobarbaz": invalid syntax`,
 		},
 		{
-			name: "error: done reminder button, can't save bot status",
+			name: "success: lead time button, too late",
+			now:  time.Date(2024, 1, 1, 14, 45, 0, 0, time.UTC),
 			message: domain.TgCallbackQuery{
 				ChatID:   expChatID,
 				UserID:   expUserID,
 				UserName: expUserName,
-				Data:     "btn_reminder_done/12345",
+				Data:     "btn_lead_time/12345/1h0m0s",
 			},
 			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
-					return dbError
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					return domain.Reminder{
+						ID:       12345,
+						UserID:   expUserID,
+						ChatID:   expChatID,
+						Text:     "Meeting",
+						RemindAt: time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC),
+						Status:   domain.ReminderStatusPending,
+					}, nil
 				}
-				store.SetReminderStatusFunc = func(_ context.Context, id int64, status domain.ReminderStatus) error {
+
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal("Уже поздно напоминать за 1 ч. 😞", response.Text)
 					return nil
 				}
 			},
-			expErr: dbError.Error(),
 		},
 		{
-			name: "error: remove reminder button, can't save bot state",
+			name: "success: lead time button, reminder is done",
 			message: domain.TgCallbackQuery{
 				ChatID:   expChatID,
 				UserID:   expUserID,
 				UserName: expUserName,
-				Data:     "btn_remove_reminder",
+				Data:     "btn_lead_time/12345/1h0m0s",
 			},
 			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
-					return dbError
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					return domain.Reminder{ID: 12345, Status: domain.ReminderStatusDone}, nil
+				}
+
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal("Напоминание 12345 не найдено 🤔", response.Text)
+					return nil
 				}
 			},
-			expErr: dbError.Error(),
 		},
 		{
-			name: "error: delay reminder button, can't parse reminder id",
+			name: "success: lead time button, reminder of other user",
+			now:  time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
 			message: domain.TgCallbackQuery{
 				ChatID:   expChatID,
 				UserID:   expUserID,
 				UserName: expUserName,
-				Data:     "btn_delay_reminder/foobar/1h",
+				Data:     "btn_lead_time/12345/30m0s",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					return domain.Reminder{
+						ID:       12345,
+						UserID:   expUserID + 1,
+						ChatID:   expChatID,
+						Text:     "Meeting",
+						RemindAt: time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC),
+						Status:   domain.ReminderStatusPending,
+					}, nil
+				}
+
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal("Напоминание 12345 не найдено 🤔", response.Text)
+					return nil
+				}
 			},
-			expErr: `can't parse reminderID: strconv.ParseInt: parsing "foobar": invalid syntax`,
 		},
 		{
-			name: "success: delay reminder button",
+			name: "success: add checklist items button",
 			message: domain.TgCallbackQuery{
 				ChatID:   expChatID,
 				UserID:   expUserID,
 				UserName: expUserName,
-				Data:     "btn_delay_reminder/12345/fooa1h",
+				Data:     "btn_checklist_add/12345",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					a.EqualValues(12345, id)
+					return domain.Reminder{ID: 12345, UserID: expUserID, ChatID: expChatID, Status: domain.ReminderStatusPending}, nil
+				}
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.BotState{
+						UserID:  expUserID,
+						Name:    domain.BotStateNameAddChecklistItems,
+						Context: &domain.BotStateContext{ReminderID: 12345},
+					}, botState)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "Напишите пункты списка 📋, каждый с новой строки.",
+					}), enqueuedMessage(a, msgs))
+					return nil
+				}
 			},
-			expErr: `can't parse delay: failed to parse remindAt duration: time: invalid duration "fooa1h"`,
 		},
 		{
-			name: "success: delay reminder button, can't delay reminder",
+			name: "success: toggle checklist item button",
 			message: domain.TgCallbackQuery{
-				ChatID:   expChatID,
-				UserID:   expUserID,
-				UserName: expUserName,
-				Data:     "btn_delay_reminder/12345/1h",
+				ChatID:    expChatID,
+				UserID:    expUserID,
+				UserName:  expUserName,
+				MessageID: 777,
+				Data:      "btn_checklist_item/1",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetChecklistItemFunc = func(_ context.Context, id int64) (domain.ChecklistItem, error) {
+					a.EqualValues(1, id)
+					return domain.ChecklistItem{ID: 1, ReminderID: 12345, Text: "milk"}, nil
+				}
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					a.EqualValues(12345, id)
+					return domain.Reminder{
+						ID:     12345,
+						UserID: expUserID,
+						ChatID: expChatID,
+						Text:   "Shopping",
+						Status: domain.ReminderStatusPending,
+						Checklist: domain.Checklist{
+							{ID: 1, ReminderID: 12345, Text: "milk"},
+							{ID: 2, ReminderID: 12345, Text: "bread"},
+						},
+					}, nil
+				}
+				store.ToggleChecklistItemFunc = func(_ context.Context, id int64, newMsgs func(reminder domain.Reminder) ([]domain.OutboxMessage, error)) (domain.Reminder, error) {
+					a.EqualValues(1, id)
+					toggled := domain.Reminder{
+						ID:     12345,
+						UserID: expUserID,
+						ChatID: expChatID,
+						Text:   "Shopping",
+						Status: domain.ReminderStatusPending,
+						Checklist: domain.Checklist{
+							{ID: 1, ReminderID: 12345, Text: "milk", Done: true},
+							{ID: 2, ReminderID: 12345, Text: "bread"},
+						},
+					}
+					msgs, err := newMsgs(toggled)
+					a.NoError(err)
+					msg := enqueuedMessage(a, msgs)
+					a.EqualValues(777, msg.EditMessageID)
+					a.Contains(msg.Text, "📋 Выполнено 1/2")
+					a.NotNil(msg.ReplyMarkup)
+					return toggled, nil
+				}
 			},
-			now: time.Date(2024, 1, 1, 11, 1, 1, 0, time.UTC),
-			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.DelayReminderFunc = func(_ context.Context, id int64, remindAt time.Time) error {
-					return dbError
+		},
+		{
+			name: "success: toggle checklist item button, all items are done",
+			message: domain.TgCallbackQuery{
+				ChatID:    expChatID,
+				UserID:    expUserID,
+				UserName:  expUserName,
+				MessageID: 777,
+				Data:      "btn_checklist_item/2",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetChecklistItemFunc = func(_ context.Context, id int64) (domain.ChecklistItem, error) {
+					return domain.ChecklistItem{ID: 2, ReminderID: 12345, Text: "bread"}, nil
+				}
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					return domain.Reminder{
+						ID:     12345,
+						UserID: expUserID,
+						ChatID: expChatID,
+						Text:   "Shopping",
+						Status: domain.ReminderStatusPending,
+						Checklist: domain.Checklist{
+							{ID: 1, ReminderID: 12345, Text: "milk", Done: true},
+							{ID: 2, ReminderID: 12345, Text: "bread"},
+						},
+					}, nil
+				}
+				store.ToggleChecklistItemFunc = func(_ context.Context, id int64, newMsgs func(reminder domain.Reminder) ([]domain.OutboxMessage, error)) (domain.Reminder, error) {
+					a.EqualValues(2, id)
+					toggled := domain.Reminder{
+						ID:     12345,
+						UserID: expUserID,
+						ChatID: expChatID,
+						Text:   "Shopping",
+						Status: domain.ReminderStatusDone,
+						Checklist: domain.Checklist{
+							{ID: 1, ReminderID: 12345, Text: "milk", Done: true},
+							{ID: 2, ReminderID: 12345, Text: "bread", Done: true},
+						},
+					}
+					msgs, err := newMsgs(toggled)
+					a.NoError(err)
+					msg := enqueuedMessage(a, msgs)
+					a.EqualValues(777, msg.EditMessageID)
+					a.True(strings.HasSuffix(msg.Text, "\n\nВсе пункты выполнены, я пометил напоминание как выполненное ✅"))
+					a.Nil(msg.ReplyMarkup)
+					return toggled, nil
 				}
 			},
-			expErr: dbError.Error(),
 		},
 		{
-			name: "success: delay reminder button, can't save bot state",
+			name: "success: toggle checklist item button, reminder is done",
 			message: domain.TgCallbackQuery{
-				ChatID:   expChatID,
-				UserID:   expUserID,
-				UserName: expUserName,
-				Data:     "btn_delay_reminder/12345/1h",
+				ChatID:    expChatID,
+				UserID:    expUserID,
+				UserName:  expUserName,
+				MessageID: 777,
+				Data:      "btn_checklist_item/2",
 			},
-			now: time.Date(2024, 1, 1, 11, 1, 1, 0, time.UTC),
 			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
-					return dbError
+				store.GetChecklistItemFunc = func(_ context.Context, id int64) (domain.ChecklistItem, error) {
+					return domain.ChecklistItem{ID: 2, ReminderID: 12345, Text: "bread", Done: true}, nil
+				}
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					return domain.Reminder{ID: 12345, UserID: expUserID, ChatID: expChatID, Text: "Shopping", Status: domain.ReminderStatusDone}, nil
 				}
-				store.DelayReminderFunc = func(_ context.Context, id int64, remindAt time.Time) error {
+
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "Напоминание *Shopping* уже неактивно, список нельзя изменить 🤔",
+					}, response)
+					a.Empty(opts)
 					return nil
 				}
 			},
-			expErr: dbError.Error(),
 		},
 		{
-			name: "error: delay reminder button, can't parse interval",
+			name: "success: toggle checklist item button, item is not found",
 			message: domain.TgCallbackQuery{
-				ChatID:   expChatID,
-				UserID:   expUserID,
-				UserName: expUserName,
-				Data:     "btn_delay_reminder/12345/fooBarBaz",
+				ChatID: expChatID,
+				UserID: expUserID,
+				Data:   "btn_checklist_item/2",
 			},
-			now:    time.Date(2024, 1, 1, 11, 1, 1, 0, time.UTC),
-			expErr: `can't parse delay: failed to parse remindAt duration: time: invalid duration "fooBarBaz"`,
-		},
-		{
-			name: "error: remind at button",
-			now:  time.Date(2024, 1, 1, 1, 1, 1, 0, time.UTC),
-			message: domain.TgCallbackQuery{
-				ChatID:   expChatID,
-				UserID:   expUserID,
-				UserName: expUserName,
-				Data:     "btn_remind_at/time/foo20:30",
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetChecklistItemFunc = func(_ context.Context, id int64) (domain.ChecklistItem, error) {
+					return domain.ChecklistItem{}, storage.ErrChecklistItemNotFound
+				}
+
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "Пункт списка не найден 🤔",
+					}, response)
+					return nil
+				}
 			},
-			expErr: `failed to parse remindAt time: parsing time "foo20:30" as "15:04": cannot parse "foo20:30" as "15"`,
 		},
 		{
-			name: "error: edit reminder button, can't save bot state",
+			name: "success: toggle checklist item button, reminder of other user",
 			message: domain.TgCallbackQuery{
-				ChatID:   expChatID,
-				UserID:   expUserID,
-				UserName: expUserName,
-				Data:     "btn_edit_reminder",
+				ChatID: expChatID,
+				UserID: expUserID,
+				Data:   "btn_checklist_item/2",
 			},
 			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
-					return dbError
+				store.GetChecklistItemFunc = func(_ context.Context, id int64) (domain.ChecklistItem, error) {
+					return domain.ChecklistItem{ID: 2, ReminderID: 12345, Text: "bread"}, nil
+				}
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					return domain.Reminder{ID: 12345, UserID: expUserID + 1, ChatID: expChatID, Status: domain.ReminderStatusPending}, nil
+				}
+
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "Пункт списка не найден 🤔",
+					}, response)
+					return nil
 				}
 			},
-			expErr: dbError.Error(),
 		},
 		{
-			name: "error: unknown button",
+			name: "success: add checklist items button, reminder of other user",
 			message: domain.TgCallbackQuery{
-				ChatID:   expChatID,
-				UserID:   expUserID,
-				UserName: expUserName,
-				Data:     "btn_foo",
+				ChatID: expChatID,
+				UserID: expUserID,
+				Data:   "btn_checklist_add/12345",
 			},
 			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, _ ...sender.BotResponseOption) error {
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					return domain.Reminder{ID: 12345, UserID: expUserID, ChatID: expChatID + 1, Status: domain.ReminderStatusPending}, nil
+				}
+
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
 					a.Equal(sender.BotResponse{
 						ChatID: expChatID,
-						Text:   "Я не понимаю о чём речь 🤔 Пожалуйста, воспользуйтесь командой /help.",
+						Text:   "Напоминание 12345 не найдено 🤔",
 					}, response)
 					return nil
 				}
 			},
 		},
-	}
+		{
+			name: "success: confirm remind at button",
+			now:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_remind_at_confirm",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				remindAt := time.Date(2024, 1, 1, 1, 1, 0, 0, time.UTC)
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameConfirmRemindAt,
+						Context: &domain.BotStateContext{
+							ReminderText:     "FooBarBaz",
+							ReminderRemindAt: &remindAt,
+						},
+					}, nil
+				}
 
-	for _, tc := range testCases {
-		// nolint:paralleltest // test modifies package level function timeNowUTC.
-		t.Run(tc.name, func(t *testing.T) {
-			if !tc.now.IsZero() {
-				tmpTimeNowUTC := timeNowUTC
-				defer func() {
-					timeNowUTC = tmpTimeNowUTC
-				}()
-				timeNowUTC = func() time.Time {
-					return tc.now
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					a.Equal(domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameStart,
+					}, botState)
+					return nil
 				}
-			}
 
-			a := assert.New(t)
-			senderMock := &ResponseSenderMock{}
-			storeMock := &StorageMock{}
-			if tc.setMocks != nil {
-				tc.setMocks(a, senderMock, storeMock)
-			}
+				store.SaveReminderFunc = func(_ context.Context, reminder domain.Reminder, newMsgs func(id int64) ([]domain.OutboxMessage, error)) (int64, error) {
+					a.Equal(domain.Reminder{
+						ChatID:       expChatID,
+						UserID:       expUserID,
+						Text:         "FooBarBaz",
+						RemindAt:     remindAt,
+						Status:       domain.ReminderStatusPending,
+						AttemptsLeft: 10,
+						Priority:     domain.ReminderPriorityNormal,
+					}, reminder)
+					msgs, err := newMsgs(1)
+					a.NoError(err)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "*2024-01-01 04:01* я напомню вам о *FooBarBaz* ✅\n\nЕсли нужно, я напомню заранее, выберите когда:",
+					}, sender.WithLeadTimesButtons(1), sender.WithAddChecklistItemsButton(1)), enqueuedMessage(a, msgs))
+					return 1, nil
+				}
+			},
+		},
+		{
+			name: "success: confirm remind at button, low priority",
+			now:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_remind_at_confirm",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				remindAt := time.Date(2024, 1, 1, 1, 1, 0, 0, time.UTC)
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameConfirmRemindAt,
+						Context: &domain.BotStateContext{
+							ReminderText:     "FooBarBaz",
+							ReminderPriority: domain.ReminderPriorityLow,
+							ReminderRemindAt: &remindAt,
+						},
+					}, nil
+				}
 
-			botImpl := New(senderMock, storeMock)
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					return nil
+				}
 
-			actErr := botImpl.OnCallbackQuery(context.TODO(), tc.message)
+				store.SaveReminderFunc = func(_ context.Context, reminder domain.Reminder, newMsgs func(id int64) ([]domain.OutboxMessage, error)) (int64, error) {
+					a.Equal(domain.Reminder{
+						ChatID:       expChatID,
+						UserID:       expUserID,
+						Text:         "FooBarBaz",
+						RemindAt:     remindAt,
+						Status:       domain.ReminderStatusPending,
+						AttemptsLeft: 1,
+						Priority:     domain.ReminderPriorityLow,
+					}, reminder)
+					msgs, err := newMsgs(1)
+					a.NoError(err)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "*2024-01-01 04:01* я напомню вам о *FooBarBaz* ✅\n\n🔽 Низкий приоритет\n\nЕсли нужно, я напомню заранее, выберите когда:",
+					}, sender.WithLeadTimesButtons(1), sender.WithAddChecklistItemsButton(1)), enqueuedMessage(a, msgs))
+					return 1, nil
+				}
+			},
+		},
+		{
+			name: "success: confirm remind at button, checklist",
+			now:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_remind_at_confirm",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				remindAt := time.Date(2024, 1, 1, 1, 1, 0, 0, time.UTC)
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameConfirmRemindAt,
+						Context: &domain.BotStateContext{
+							ReminderText:      "Shopping",
+							ReminderChecklist: []string{"milk", "bread"},
+							ReminderRemindAt:  &remindAt,
+						},
+					}, nil
+				}
 
-			if tc.expErr != "" {
-				a.EqualError(actErr, tc.expErr)
-			} else {
-				a.NoError(actErr)
-			}
-		})
-	}
-}
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					return nil
+				}
 
-// nolint:paralleltest // test modifies package level function timeNowUTC.
-func TestBot_OnMessage(t *testing.T) {
-	const (
-		expChatID   int64 = 43548
-		expUserID   int64 = 546567
-		expUserName       = "johndoe"
-	)
+				store.SaveReminderFunc = func(_ context.Context, reminder domain.Reminder, newMsgs func(id int64) ([]domain.OutboxMessage, error)) (int64, error) {
+					a.Equal(domain.Checklist{{Text: "milk"}, {Text: "bread"}}, reminder.Checklist)
+					msgs, err := newMsgs(1)
+					a.NoError(err)
+					msg := enqueuedMessage(a, msgs)
+					a.Equal("*2024-01-01 04:01* я напомню вам о *Shopping* ✅\n\n📋 Пунктов в списке: 2\n\nЕсли нужно, я напомню заранее, выберите когда:", msg.Text)
+					a.NotNil(msg.ReplyMarkup)
+					return 1, nil
+				}
+			},
+		},
+		{
+			name: "success: confirm remind at button, time has passed",
+			now:  time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC),
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_remind_at_confirm",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				remindAt := time.Date(2024, 1, 1, 1, 1, 0, 0, time.UTC)
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameConfirmRemindAt,
+						Context: &domain.BotStateContext{
+							ReminderText:     "FooBarBaz",
+							ReminderRemindAt: &remindAt,
+						},
+					}, nil
+				}
 
-	var dbError = errors.New("db error")
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.BotState{
+						UserID:  expUserID,
+						Name:    domain.BotStateNameEnterReminAt,
+						Context: &domain.BotStateContext{ReminderText: "FooBarBaz"},
+					}, botState)
+					msg := enqueuedMessage(a, msgs)
+					a.Equal("🤔 Время *2024-01-01 04:01* уже прошло, а напоминание должно быть в будущем. Пожалуйста, введите другое время или выберите опцию ниже.", msg.Text)
+					a.NotNil(msg.ReplyMarkup)
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: shift remind at button",
+			now:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			message: domain.TgCallbackQuery{
+				ChatID:    expChatID,
+				UserID:    expUserID,
+				UserName:  expUserName,
+				MessageID: 777,
+				Data:      "btn_remind_at_shift/24h",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				remindAt := time.Date(2024, 1, 1, 1, 1, 0, 0, time.UTC)
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameConfirmRemindAt,
+						Context: &domain.BotStateContext{
+							ReminderText:     "FooBarBaz",
+							ReminderRemindAt: &remindAt,
+						},
+					}, nil
+				}
+
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
+					shifted, ok := botState.ReminderRemindAt()
+					a.True(ok)
+					a.Equal(time.Date(2024, 1, 2, 1, 1, 0, 0, time.UTC), shifted)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID:        expChatID,
+						EditMessageID: 777,
+						Text:          "*Проверьте время напоминания*\n\nНапомню о *FooBarBaz*\n⏰ *завтра, вт 2 янв. 04:01*\n\nЕсли всё верно, нажмите «Подтвердить». Время можно сдвинуть кнопками ниже.",
+					}, sender.WithRemindAtConfirmButtons()), enqueuedMessage(a, msgs))
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: shift remind at button, can't shift to the past",
+			now:  time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC),
+			message: domain.TgCallbackQuery{
+				ChatID: expChatID,
+				UserID: expUserID,
+				Data:   "btn_remind_at_shift/-1h",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				remindAt := time.Date(2024, 1, 1, 1, 1, 0, 0, time.UTC)
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{
+						UserID:  expUserID,
+						Name:    domain.BotStateNameConfirmRemindAt,
+						Context: &domain.BotStateContext{ReminderRemindAt: &remindAt},
+					}, nil
+				}
+
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "Нельзя перенести напоминание в прошлое 🤔",
+					}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: reenter remind at button",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_remind_at_reenter",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				remindAt := time.Date(2024, 1, 1, 1, 1, 0, 0, time.UTC)
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameConfirmRemindAt,
+						Context: &domain.BotStateContext{
+							ReminderText:     "FooBarBaz",
+							ReminderRemindAt: &remindAt,
+						},
+					}, nil
+				}
+
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.BotState{
+						UserID:  expUserID,
+						Name:    domain.BotStateNameEnterReminAt,
+						Context: &domain.BotStateContext{ReminderText: "FooBarBaz"},
+					}, botState)
+					msg := enqueuedMessage(a, msgs)
+					a.True(strings.HasPrefix(msg.Text, "*Когда напомнить ❓"))
+					a.NotNil(msg.ReplyMarkup)
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: calendar button",
+			now:  time.Date(2024, 10, 17, 7, 0, 0, 0, time.UTC),
+			message: domain.TgCallbackQuery{
+				ChatID:    expChatID,
+				UserID:    expUserID,
+				MessageID: 777,
+				Data:      "btn_remind_at/calendar/",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID:        expChatID,
+						EditMessageID: 777,
+						Text:          "*Выберите дату* 📅",
+					}, response)
+					a.Len(opts, 1)
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: calendar date button",
+			message: domain.TgCallbackQuery{
+				ChatID:    expChatID,
+				UserID:    expUserID,
+				MessageID: 777,
+				Data:      "btn_remind_at/date/2024-10-20",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID:        expChatID,
+						EditMessageID: 777,
+						Text:          "*Выберите час* ⏰\n\n20.10.2024",
+					}, response)
+					a.Len(opts, 1)
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: hour picker button",
+			message: domain.TgCallbackQuery{
+				ChatID:    expChatID,
+				UserID:    expUserID,
+				MessageID: 777,
+				Data:      "btn_remind_at/hour/2024-10-20T10",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error {
+					a.Equal("*Выберите время* ⏰\n\n20.10.2024", response.Text)
+					a.Len(opts, 1)
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: minute picker button",
+			now:  time.Date(2024, 10, 17, 7, 0, 0, 0, time.UTC),
+			message: domain.TgCallbackQuery{
+				ChatID: expChatID,
+				UserID: expUserID,
+				Data:   "btn_remind_at/minute/2024-10-20T10:05",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{
+						UserID:  expUserID,
+						Name:    domain.BotStateNameEnterReminAt,
+						Context: &domain.BotStateContext{ReminderText: "FooBarBaz"},
+					}, nil
+				}
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					return nil
+				}
+				store.SaveReminderFunc = func(_ context.Context, reminder domain.Reminder, newMsgs func(id int64) ([]domain.OutboxMessage, error)) (int64, error) {
+					a.Equal(time.Date(2024, 10, 20, 7, 5, 0, 0, time.UTC), reminder.RemindAt)
+					msgs, err := newMsgs(1)
+					a.NoError(err)
+					msg := enqueuedMessage(a, msgs)
+					a.Equal("*2024-10-20 10:05* я напомню вам о *FooBarBaz* ✅\n\nЕсли нужно, я напомню заранее, выберите когда:", msg.Text)
+					return 1, nil
+				}
+			},
+		},
+		{
+			name: "success: minute picker button, time is in the past",
+			now:  time.Date(2024, 10, 20, 8, 0, 0, 0, time.UTC),
+			message: domain.TgCallbackQuery{
+				ChatID: expChatID,
+				UserID: expUserID,
+				Data:   "btn_remind_at/minute/2024-10-20T10:05",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error {
+					a.Equal("🤔 Время *2024-10-20 10:05* уже прошло, а напоминание должно быть в будущем. Пожалуйста, введите другое время или выберите опцию ниже.", response.Text)
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: noop button",
+			message: domain.TgCallbackQuery{
+				ChatID: expChatID,
+				UserID: expUserID,
+				Data:   "btn_noop",
+			},
+		},
+		{
+			name: "success: edit reminder button",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_edit_reminder",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameEditReminder,
+					}, botState)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "Напишите номер #️⃣ напоминания для редактирования.",
+					}), enqueuedMessage(a, msgs))
+					return nil
+				}
+			},
+		},
+
+		// error
+		{
+			name: "error: done reminder button, can't set reminder status, response is discarded along with it",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_reminder_done/12345",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					return nil
+				}
+				store.SetReminderStatusFunc = func(_ context.Context, id int64, status domain.ReminderStatus, msgs ...domain.OutboxMessage) error {
+					// response is a part of the failed write
+					a.Len(msgs, 1)
+					return dbError
+				}
+				responseSender.WakeupFunc = func() {
+					a.Fail("sender is woken up though nothing is enqueued")
+				}
+			},
+			expErr: dbError.Error(),
+		},
+		{
+			name: "error: done reminder button, can't get reminder id",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_reminder_done/foobarbaz",
+			},
+			expErr: `can'p parse reminderID: strconv.ParseInt: parsing "foobarbaz": invalid syntax`,
+		},
+		{
+			name: "error: done reminder button, can't save bot status",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_reminder_done/12345",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					return dbError
+				}
+				store.SetReminderStatusFunc = func(_ context.Context, id int64, status domain.ReminderStatus, _ ...domain.OutboxMessage) error {
+					return nil
+				}
+			},
+			expErr: dbError.Error(),
+		},
+		{
+			name: "error: remove reminder button, can't save bot state",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_remove_reminder",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					return dbError
+				}
+			},
+			expErr: dbError.Error(),
+		},
+		{
+			name: "success: reminder history button",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_reminder_history",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameReminderHistory,
+					}, botState)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "Напишите номер #️⃣ напоминания, историю которого хотите посмотреть.",
+					}), enqueuedMessage(a, msgs))
+					return nil
+				}
+			},
+		},
+		{
+			name: "error: delay reminder button, can't parse reminder id",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_delay_reminder/foobar/1h",
+			},
+			expErr: `can't parse reminderID: strconv.ParseInt: parsing "foobar": invalid syntax`,
+		},
+		{
+			name: "success: delay reminder button",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_delay_reminder/12345/fooa1h",
+			},
+			expErr: `can't parse delay: failed to parse snooze option: unknown quick option "fooa1h"`,
+		},
+		{
+			name: "success: delay reminder button, can't delay reminder",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_delay_reminder/12345/1h",
+			},
+			now: time.Date(2024, 1, 1, 11, 1, 1, 0, time.UTC),
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.DelayReminderFunc = func(_ context.Context, id int64, remindAt time.Time, _ ...domain.OutboxMessage) error {
+					return dbError
+				}
+			},
+			expErr: dbError.Error(),
+		},
+		{
+			name: "success: delay reminder button, can't save bot state",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_delay_reminder/12345/1h",
+			},
+			now: time.Date(2024, 1, 1, 11, 1, 1, 0, time.UTC),
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					return dbError
+				}
+				store.DelayReminderFunc = func(_ context.Context, id int64, remindAt time.Time, _ ...domain.OutboxMessage) error {
+					return nil
+				}
+			},
+			expErr: dbError.Error(),
+		},
+		{
+			name: "error: delay reminder button, can't parse interval",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_delay_reminder/12345/fooBarBaz",
+			},
+			now:    time.Date(2024, 1, 1, 11, 1, 1, 0, time.UTC),
+			expErr: `can't parse delay: failed to parse snooze option: unknown quick option "fooBarBaz"`,
+		},
+		{
+			name: "error: remind at button",
+			now:  time.Date(2024, 1, 1, 1, 1, 1, 0, time.UTC),
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_remind_at/time/foo20:30",
+			},
+			expErr: `failed to parse remindAt time: parsing time "foo20:30" as "15:04": cannot parse "foo20:30" as "15"`,
+		},
+		{
+			name: "error: reminder priority button, unknown priority",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_reminder_priority/urgent",
+			},
+			expErr: `can't parse priority: unknown reminder priority "urgent"`,
+		},
+		{
+			name: "error: reminder priority button, invalid bot state",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_reminder_priority/low",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{UserID: expUserID, Name: domain.BotStateNameStart}, nil
+				}
+			},
+			expErr: "can't set reminder priority: invalid bot state: expected [enter_remind_at], actual [start]",
+		},
+		{
+			name: "error: lead time button, can't parse lead time",
+			message: domain.TgCallbackQuery{
+				ChatID: expChatID,
+				UserID: expUserID,
+				Data:   "btn_lead_time/12345/foo",
+			},
+			expErr: `can't parse lead time: time: invalid duration "foo"`,
+		},
+		{
+			name: "error: lead time button, can't get reminder",
+			message: domain.TgCallbackQuery{
+				ChatID: expChatID,
+				UserID: expUserID,
+				Data:   "btn_lead_time/12345/1h0m0s",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					return domain.Reminder{}, dbError
+				}
+			},
+			expErr: dbError.Error(),
+		},
+		{
+			name: "error: toggle checklist item button, db error",
+			message: domain.TgCallbackQuery{
+				ChatID: expChatID,
+				UserID: expUserID,
+				Data:   "btn_checklist_item/2",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetChecklistItemFunc = func(_ context.Context, id int64) (domain.ChecklistItem, error) {
+					return domain.ChecklistItem{ID: 2, ReminderID: 12345, Text: "bread"}, nil
+				}
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					return domain.Reminder{ID: 12345, UserID: expUserID, ChatID: expChatID, Status: domain.ReminderStatusPending}, nil
+				}
+				store.ToggleChecklistItemFunc = func(_ context.Context, id int64, newMsgs func(reminder domain.Reminder) ([]domain.OutboxMessage, error)) (domain.Reminder, error) {
+					return domain.Reminder{}, dbError
+				}
+			},
+			expErr: dbError.Error(),
+		},
+		{
+			name: "error: confirm remind at button, invalid bot state",
+			message: domain.TgCallbackQuery{
+				ChatID: expChatID,
+				UserID: expUserID,
+				Data:   "btn_remind_at_confirm",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{UserID: expUserID, Name: domain.BotStateNameStart}, nil
+				}
+			},
+			expErr: "can't confirm remindAt: invalid bot state: expected [confirm_remind_at], actual [start]",
+		},
+		{
+			name: "error: shift remind at button, can't parse shift",
+			message: domain.TgCallbackQuery{
+				ChatID: expChatID,
+				UserID: expUserID,
+				Data:   "btn_remind_at_shift/foo",
+			},
+			expErr: `can't parse remindAt shift: time: invalid duration "foo"`,
+		},
+		{
+			name: "error: calendar button, can't parse month",
+			message: domain.TgCallbackQuery{
+				ChatID: expChatID,
+				UserID: expUserID,
+				Data:   "btn_remind_at/calendar/foo",
+			},
+			expErr: `can't parse picker time: parsing time "foo" as "2006-01": cannot parse "foo" as "2006"`,
+		},
+		{
+			name: "error: edit reminder button, can't save bot state",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_edit_reminder",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					return dbError
+				}
+			},
+			expErr: dbError.Error(),
+		},
+		{
+			name: "error: unknown button",
+			message: domain.TgCallbackQuery{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Data:     "btn_foo",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "Я не понимаю о чём речь 🤔 Пожалуйста, воспользуйтесь командой /help.",
+					}, response)
+					return nil
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		// nolint:paralleltest // test modifies package level function timeNowUTC.
+		t.Run(tc.name, func(t *testing.T) {
+			if !tc.now.IsZero() {
+				tmpTimeNowUTC := timeNowUTC
+				defer func() {
+					timeNowUTC = tmpTimeNowUTC
+				}()
+				timeNowUTC = func() time.Time {
+					return tc.now
+				}
+			}
+
+			a := assert.New(t)
+			senderMock := &ResponseSenderMock{}
+			storeMock := &StorageMock{}
+			if tc.setMocks != nil {
+				tc.setMocks(a, senderMock, storeMock)
+			}
+			if storeMock.GetUserSettingsFunc == nil {
+				// user hasn't changed settings, defaults are used
+				storeMock.GetUserSettingsFunc = func(ctx context.Context, userID int64) (domain.UserSettings, error) {
+					return domain.UserSettings{UserID: userID}, nil
+				}
+			}
+			if senderMock.WakeupFunc == nil {
+				// responses to changes of state are enqueued by storage, sender is only woken up to deliver them
+				senderMock.WakeupFunc = func() {}
+			}
+
+			botImpl := New(senderMock, storeMock, &NotifierMock{}, Config{})
+
+			actErr := botImpl.OnCallbackQuery(context.TODO(), tc.message)
+
+			if tc.expErr != "" {
+				a.EqualError(actErr, tc.expErr)
+			} else {
+				a.NoError(actErr)
+			}
+			if senderMock.SendBotResponseFunc != nil {
+				a.NotEmpty(senderMock.SendBotResponseCalls(), "expected response isn't sent")
+			}
+		})
+	}
+}
+
+// nolint:paralleltest // test modifies package level functions timeNowUTC and token generators.
+func TestBot_OnMessage(t *testing.T) {
+	const (
+		expChatID   int64 = 43548
+		expUserID   int64 = 546567
+		expUserName       = "johndoe"
+	)
+
+	var dbError = errors.New("db error")
+
+	testCases := []struct {
+		name       string
+		message    domain.TgMessage
+		now        time.Time
+		webURL     string
+		channels   domain.Channels
+		setMocks   func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock)
+		catchUpErr error
+		expErr     string
+	}{
+		// commands
+		{
+			name: "success: start cmd",
+			message: domain.TgMessage{
+				ChatID:   expUserID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/start",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveUserFunc = func(_ context.Context, user domain.User, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.User{
+						ID:     expUserID,
+						Name:   expUserName,
+						Status: domain.UserStatusActive,
+					}, user)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expUserID,
+						Text:   "*Привет,* @johndoe 👋\n\nТеперь вы можете со мной работать.\nДля справки 💁 используйте команду /help",
+					}), enqueuedMessage(a, msgs))
+					return nil
+				}
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					a.Equal(domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameStart,
+					}, botState)
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: start cmd with invite code",
+			message: domain.TgMessage{
+				ChatID:   expUserID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/start 0123456789abcdef",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveUserFunc = func(_ context.Context, user domain.User, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.User{
+						ID:     expUserID,
+						Name:   expUserName,
+						Status: domain.UserStatusActive,
+					}, user)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expUserID,
+						Text:   "*Привет,* @johndoe 👋\n\nТеперь вы можете со мной работать.\nДля справки 💁 используйте команду /help",
+					}), enqueuedMessage(a, msgs))
+					return nil
+				}
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					a.Equal(domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameStart,
+					}, botState)
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: help cmd",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/help",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameHelp,
+					}, botState)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "\n*Список доступных команд*\n\t• /help — справка 💁\n\t• /start — начать работу с ботом ▶️\n\t• /create\\_reminder — создать напоминание 📝\n\t• /enable\\_reminders — включить напоминания 🔔\n\t• /disable\\_reminders — выключить напоминания 🔕\n\t• /my\\_reminders — мои напоминания 🗒️\n\t• /settings — настройки кнопок ⚙️\n\t• /api\\_token — токен для API 🔑\n\t• /hook\\_token — токен входящего вебхука 🪝\n\t• /web — веб-интерфейс 🌐\n\t• /webhooks — вебхуки событий 🔗",
+					}), enqueuedMessage(a, msgs))
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: create reminder cmd",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/create_reminder",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameCreateReminder,
+					}, botState)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "О чём напомнить❓",
+					}), enqueuedMessage(a, msgs))
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: my reminders cmd",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/my_reminders",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetMyRemindersFunc = func(_ context.Context, userID int64, chatID int64) ([]domain.Reminder, error) {
+					a.Equal(expUserID, userID)
+					a.Equal(expChatID, chatID)
+					return []domain.Reminder{
+						{
+							ID:       12,
+							ChatID:   expChatID,
+							UserID:   expUserID,
+							Text:     "Напоминание 1",
+							RemindAt: time.Date(2024, 1, 1, 1, 1, 1, 0, time.UTC),
+						},
+					}, nil
+				}
+
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameMyReminders,
+					}, botState)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "*СПИСОК НАПОМИНАНИЙ*\n\n✅ *Напоминание 1*\n⏰ 1 янв. 04:01\n#️⃣ 12\n\n",
+					}, sender.WithMyRemindersListEditButtons()), enqueuedMessage(a, msgs))
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: enable reminders cmd",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/enable_reminders",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					a.Equal(domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameEnableReminders,
+					}, botState)
+					return nil
+				}
+				store.SetUserStatusFunc = func(_ context.Context, id int64, status domain.UserStatus, msgs ...domain.OutboxMessage) error {
+					a.Equal(expUserID, id)
+					a.Equal(domain.UserStatusActive, status)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "*Уведомления включены* 🔔\n\nДля отключения уведомлений используйте команду /disable\\_reminders",
+					}), enqueuedMessage(a, msgs))
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: disable reminders cmd",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/disable_reminders",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					a.Equal(domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameDisableReminders,
+					}, botState)
+					return nil
+				}
+				store.SetUserStatusFunc = func(_ context.Context, id int64, status domain.UserStatus, msgs ...domain.OutboxMessage) error {
+					a.Equal(expUserID, id)
+					a.Equal(domain.UserStatusInactive, status)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "*Уведомления отключены* 🔕\n\nДля включения уведомлений воспользуйтесь командой /enable\\_reminders",
+					}), enqueuedMessage(a, msgs))
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: api token cmd",
+			message: domain.TgMessage{
+				ChatID:   expUserID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/api_token",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveAPITokenFunc = func(_ context.Context, token domain.APIToken, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.APIToken{
+						TokenHash: domain.HashAPIToken("tgr_0123456789abcdef"),
+						UserID:    expUserID,
+						ChatID:    expUserID,
+					}, token)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expUserID,
+						Text:   "*Токен для API создан* 🔑\n\n`tgr_0123456789abcdef`\n\nТокен показывается один раз, сохраните его. Предыдущий токен больше не действует.",
+					}), enqueuedMessage(a, msgs))
+					return nil
+				}
+			},
+		},
+		{
+			name: "error: api token cmd, failed to save token",
+			message: domain.TgMessage{
+				ChatID:   expUserID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/api_token",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.SaveAPITokenFunc = func(_ context.Context, token domain.APIToken, _ ...domain.OutboxMessage) error {
+					return errors.New("db error")
+				}
+			},
+			expErr: "db error",
+		},
+		{
+			name: "success: hook token cmd",
+			message: domain.TgMessage{
+				ChatID:   expUserID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/hook_token",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveAPITokenFunc = func(_ context.Context, token domain.APIToken, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.APIToken{
+						TokenHash: domain.HashAPIToken("tgh_0123456789abcdef"),
+						Kind:      domain.APITokenKindHook,
+						UserID:    expUserID,
+						ChatID:    expUserID,
+					}, token)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expUserID,
+						Text: "*Токен входящего вебхука создан* 🪝\n\n`tgh_0123456789abcdef`\n\n" +
+							"Чтобы создать напоминание, отправьте POST-запрос на `/api/v1/hooks/<токен>` с JSON " +
+							"`{\"text\": \"Обновить сертификат\", \"when\": \"через 30 дней\"}`. " +
+							"Время понимается так же, как в сообщениях боту.\n\n" +
+							"Токен позволяет только создавать напоминания и показывается один раз, сохраните его. " +
+							"Предыдущий токен больше не действует.",
+					}), enqueuedMessage(a, msgs))
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: api_token cmd in group chat isn't handled",
+			message: domain.TgMessage{
+				ChatID:   -expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/api_token",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, _ *StorageMock) {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{ChatID: -expChatID, Text: "Эта команда работает только в личном чате с ботом 🔒"}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: web cmd in group chat isn't handled",
+			message: domain.TgMessage{
+				ChatID:   -expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/web@reminder_bot",
+			},
+			webURL: "https://reminder.example.com/",
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, _ *StorageMock) {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{ChatID: -expChatID, Text: "Эта команда работает только в личном чате с ботом 🔒"}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: web cmd",
+			message: domain.TgMessage{
+				ChatID:   expUserID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/web",
+			},
+			now:    time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC),
+			webURL: "https://reminder.example.com/",
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveWebSessionFunc = func(_ context.Context, session domain.WebSession, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.WebSession{
+						TokenHash: domain.HashAPIToken("0123456789abcdef"),
+						Kind:      domain.WebSessionKindLogin,
+						UserID:    expUserID,
+						ChatID:    expUserID,
+						ExpiresAt: time.Date(2024, time.May, 1, 10, 15, 0, 0, time.UTC),
+					}, session)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expUserID,
+						Text: "*Вход в веб-интерфейс* 🌐\n\n[Открыть напоминания](https://reminder.example.com/login?token=0123456789abcdef)\n\n" +
+							"Ссылка одноразовая, срок действия — 15 мин.",
+					}), enqueuedMessage(a, msgs))
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: web cmd, web dashboard is disabled",
+			message: domain.TgMessage{
+				ChatID:   expUserID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/web",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, _ *StorageMock) {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{ChatID: expUserID, Text: "Веб-интерфейс не включён ❌"}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name: "error: web cmd, failed to save login link",
+			message: domain.TgMessage{
+				ChatID:   expUserID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/web",
+			},
+			webURL: "https://reminder.example.com",
+			setMocks: func(_ *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveWebSessionFunc = func(context.Context, domain.WebSession, ...domain.OutboxMessage) error {
+					return errors.New("db error")
+				}
+			},
+			expErr: "db error",
+		},
+		{
+			name: "success: webhooks cmd, list webhooks",
+			message: domain.TgMessage{
+				ChatID:   expUserID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/webhooks",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetUserWebhooksFunc = func(_ context.Context, userID int64) ([]domain.Webhook, error) {
+					a.Equal(expUserID, userID)
+					return []domain.Webhook{
+						{ID: 3, UserID: expUserID, URL: "https://example.com/hook"},
+						{ID: 4, UserID: expUserID, URL: "https://example.com/done", Events: domain.WebhookEvents{domain.WebhookEventCompleted}},
+					}, nil
+				}
+
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID: expUserID,
+						Text: "*Вебхуки* 🔗\n\n\t• #3 `https://example.com/hook` — все события" +
+							"\n\t• #4 `https://example.com/done` — reminder.completed\n\n" + webhooksUsage,
+					}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: webhooks cmd, add webhook",
+			message: domain.TgMessage{
+				ChatID:   expUserID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/webhooks add https://example.com/hook completed, exhausted",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveWebhookFunc = func(_ context.Context, hook domain.Webhook, newMsgs func(id int64) ([]domain.OutboxMessage, error)) (int64, error) {
+					a.Equal(domain.Webhook{
+						UserID: expUserID,
+						URL:    "https://example.com/hook",
+						Secret: "whsec_0123456789abcdef",
+						Events: domain.WebhookEvents{domain.WebhookEventCompleted, domain.WebhookEventExhausted},
+					}, hook)
+					msgs, err := newMsgs(3)
+					a.NoError(err)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expUserID,
+						Text: "*Вебхук #3 добавлен* 🔗\n\nСобытия: reminder.completed,reminder.exhausted\nСекрет для проверки подписи:\n`whsec_0123456789abcdef`\n\n" +
+							"Подпись передаётся в заголовке X-Reminder-Signature как sha256=<hex> от строки \"<X-Reminder-Timestamp>.<тело запроса>\". " +
+							"Секрет показывается один раз, сохраните его.",
+					}), enqueuedMessage(a, msgs))
+					return 3, nil
+				}
+			},
+		},
+		{
+			name: "success: webhooks cmd, invalid webhook url",
+			message: domain.TgMessage{
+				ChatID:   expUserID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/webhooks add ftp://example.com",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, _ *StorageMock) {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{ChatID: expUserID, Text: "🤔 Не удалось понять команду.\n\n" + webhooksUsage}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: webhooks cmd, remove webhook",
+			message: domain.TgMessage{
+				ChatID:   expUserID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/webhooks remove 3",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetWebhookFunc = func(_ context.Context, id int64) (domain.Webhook, error) {
+					return domain.Webhook{ID: id, UserID: expUserID, URL: "https://example.com/hook"}, nil
+				}
+				store.RemoveWebhookFunc = func(_ context.Context, id int64, msgs ...domain.OutboxMessage) error {
+					a.EqualValues(3, id)
+					a.Equal(sender.Render(sender.BotResponse{ChatID: expUserID, Text: "Вебхук #3 удалён ✅"}), enqueuedMessage(a, msgs))
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: webhooks cmd, webhook of other user is not removed",
+			message: domain.TgMessage{
+				ChatID:   expUserID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/webhooks remove 3",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetWebhookFunc = func(_ context.Context, id int64) (domain.Webhook, error) {
+					return domain.Webhook{ID: id, UserID: expUserID + 1, URL: "https://example.com/hook"}, nil
+				}
+
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{ChatID: expUserID, Text: "Вебхук #3 не найден ❌"}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: webhooks cmd, delivery log",
+			message: domain.TgMessage{
+				ChatID:   expUserID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/webhooks log 3",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetWebhookFunc = func(_ context.Context, id int64) (domain.Webhook, error) {
+					return domain.Webhook{ID: id, UserID: expUserID, URL: "https://example.com/hook"}, nil
+				}
+				store.GetWebhookDeliveriesFunc = func(_ context.Context, webhookID int64, limit int64) ([]domain.WebhookDelivery, error) {
+					a.EqualValues(3, webhookID)
+					a.EqualValues(10, limit)
+					return []domain.WebhookDelivery{
+						{
+							ID: 2, WebhookID: 3, Event: domain.WebhookEventFired, Status: domain.WebhookDeliveryStatusDead, Attempts: 10,
+							LastError: "unexpected response status 502", CreatedAt: time.Date(2024, time.May, 1, 15, 0, 0, 0, time.UTC),
+						},
+						{
+							ID: 1, WebhookID: 3, Event: domain.WebhookEventCreated, Status: domain.WebhookDeliveryStatusDelivered, Attempts: 1,
+							ResponseStatus: 204, CreatedAt: time.Date(2024, time.May, 1, 14, 0, 0, 0, time.UTC),
+						},
+					}, nil
+				}
+
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal(sender.BotResponse{
+						ChatID: expUserID,
+						Text: "📜 *Журнал доставки вебхука #3*\n" +
+							"\n\t• 1 мая 18:00 — reminder.fired — не доставлено, попыток 10: `unexpected response status 502`" +
+							"\n\t• 1 мая 17:00 — reminder.created — доставлено, ответ 204",
+					}, response)
+					return nil
+				}
+			},
+		},
+		{
+			name: "error: webhooks cmd, failed to save webhook",
+			message: domain.TgMessage{
+				ChatID:   expUserID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/webhooks add https://example.com/hook",
+			},
+			setMocks: func(_ *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveWebhookFunc = func(context.Context, domain.Webhook, func(int64) ([]domain.OutboxMessage, error)) (int64, error) {
+					return 0, errors.New("db error")
+				}
+			},
+			expErr: "db error",
+		},
+		{
+			name: "success: settings cmd, show settings",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/settings",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetUserSettingsFunc = func(_ context.Context, userID int64) (domain.UserSettings, error) {
+					a.Equal(expUserID, userID)
+					return domain.UserSettings{UserID: userID, SnoozeOptions: domain.QuickOptions{"15m", domain.QuickOptionNextMonday}}, nil
+				}
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameSettings,
+					}, botState)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "*Настройки кнопок* ⚙️\n\nБыстрый выбор времени: *11:30, 14:30, 19:30, 20:30, 30 мин., 1 ч. 20 мин., 1 дн., 1 мес.*\nОтложить напоминание: *15 мин., В понедельник*\n\n*Каналы уведомлений*\n\nПо умолчанию: *Telegram*\nПочта: не задан\nВебхук: не задан\nPush: не задан\n\n" + settingsUsage,
+					}), enqueuedMessage(a, msgs))
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: settings cmd, change quick options",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/settings quick 09:00, 2h,Tomorrow_Evening weekend",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetUserSettingsFunc = func(_ context.Context, userID int64) (domain.UserSettings, error) {
+					return domain.UserSettings{UserID: userID, SnoozeOptions: domain.QuickOptions{"15m"}}, nil
+				}
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					return nil
+				}
+				store.SaveUserSettingsFunc = func(_ context.Context, settings domain.UserSettings, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.UserSettings{
+						UserID:        expUserID,
+						QuickOptions:  domain.QuickOptions{"09:00", "2h", domain.QuickOptionTomorrowEvening, domain.QuickOptionWeekend},
+						SnoozeOptions: domain.QuickOptions{"15m"},
+					}, settings)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "Настройки сохранены ✅\n\n*Настройки кнопок* ⚙️\n\nБыстрый выбор времени: *09:00, 2 ч., Завтра вечером, В выходные*\nОтложить напоминание: *15 мин.*\n\n*Каналы уведомлений*\n\nПо умолчанию: *Telegram*\nПочта: не задан\nВебхук: не задан\nPush: не задан\n\n" + settingsUsage,
+					}), enqueuedMessage(a, msgs))
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: settings cmd, change snooze options",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/settings snooze 10m 1h 2d",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					return nil
+				}
+				store.SaveUserSettingsFunc = func(_ context.Context, settings domain.UserSettings, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.UserSettings{
+						UserID:        expUserID,
+						SnoozeOptions: domain.QuickOptions{"10m", "1h", "2d"},
+					}, settings)
+					msg := enqueuedMessage(a, msgs)
+					a.Contains(msg.Text, "Отложить напоминание: *10 мин., 1 ч., 2 дн.*")
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: settings cmd, reset",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/settings reset",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetUserSettingsFunc = func(_ context.Context, userID int64) (domain.UserSettings, error) {
+					return domain.UserSettings{UserID: userID, QuickOptions: domain.QuickOptions{"1h"}, SnoozeOptions: domain.QuickOptions{"15m"}}, nil
+				}
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					return nil
+				}
+				store.SaveUserSettingsFunc = func(_ context.Context, settings domain.UserSettings, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.UserSettings{UserID: expUserID}, settings)
+					msg := enqueuedMessage(a, msgs)
+					a.Contains(msg.Text, "Быстрый выбор времени: *11:30, 14:30, 19:30, 20:30, 30 мин., 1 ч. 20 мин., 1 дн., 1 мес.*")
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: settings cmd, set email address, code is sent",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/settings email User@Example.com",
+			},
+			channels: domain.Channels{domain.ChannelTelegram, domain.ChannelEmail},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					return nil
+				}
+				store.SaveEmailVerificationFunc = func(_ context.Context, settings domain.UserSettings, email domain.OutboxMessage, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.UserSettings{UserID: expUserID, Email: "User@Example.com", EmailCode: "012345"}, settings)
+					a.Equal(domain.ChannelEmail, email.Channel)
+					a.Equal(expChatID, email.ChatID)
+					a.True(email.Secret)
 
-	testCases := []struct {
-		name     string
-		message  domain.TgMessage
-		now      time.Time
-		setMocks func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock)
-		expErr   string
-	}{
-		// commands
+					var n domain.Notification
+					a.NoError(json.Unmarshal([]byte(email.Payload), &n))
+					a.Equal(domain.NewEmailVerification(expUserID, "User@Example.com", "012345"), n)
+					msg := enqueuedMessage(a, msgs)
+					a.Contains(msg.Text, "На адрес `User@Example.com` отправлен код подтверждения")
+					a.Contains(msg.Text, "Почта: `User@Example.com` (не подтверждён)\nВебхук: не задан")
+					return nil
+				}
+			},
+		},
 		{
-			name: "success: start cmd",
+			name: "success: settings cmd, set email address, email isn't configured",
 			message: domain.TgMessage{
 				ChatID:   expChatID,
 				UserID:   expUserID,
 				UserName: expUserName,
-				Text:     "/start",
+				Text:     "/settings email User@Example.com",
+			},
+			channels: domain.Channels{domain.ChannelTelegram},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					return nil
+				}
+				store.SaveUserSettingsFunc = func(_ context.Context, settings domain.UserSettings, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.UserSettings{UserID: expUserID, Email: "User@Example.com"}, settings)
+					msg := enqueuedMessage(a, msgs)
+					a.Contains(msg.Text, "отправка почты не настроена на сервере")
+					a.Contains(msg.Text, "Почта: `User@Example.com` (не подтверждён)\nВебхук: не задан")
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: settings cmd, verify email",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/settings verify 012345",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetUserSettingsFunc = func(_ context.Context, userID int64) (domain.UserSettings, error) {
+					return domain.UserSettings{UserID: userID, Email: "user@example.com", EmailCode: "012345"}, nil
+				}
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					return nil
+				}
+				store.SaveUserSettingsFunc = func(_ context.Context, settings domain.UserSettings, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.UserSettings{UserID: expUserID, Email: "user@example.com", EmailVerified: true}, settings)
+					msg := enqueuedMessage(a, msgs)
+					a.Contains(msg.Text, "Адрес `user@example.com` подтверждён")
+					a.Contains(msg.Text, "Почта: `user@example.com`\nВебхук: не задан")
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: settings cmd, verify email, wrong code resets it",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/settings verify 999999",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetUserSettingsFunc = func(_ context.Context, userID int64) (domain.UserSettings, error) {
+					return domain.UserSettings{UserID: userID, Email: "user@example.com", EmailCode: "012345"}, nil
+				}
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					return nil
+				}
+				store.SaveUserSettingsFunc = func(_ context.Context, settings domain.UserSettings, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.UserSettings{UserID: expUserID, Email: "user@example.com"}, settings)
+					msg := enqueuedMessage(a, msgs)
+					a.Equal("Неверный код подтверждения ❌\n\nЧтобы получить новый код, отправьте /settings email <адрес> ещё раз.", msg.Text)
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: settings cmd, change channels",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/settings channels telegram, push",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetUserSettingsFunc = func(_ context.Context, userID int64) (domain.UserSettings, error) {
+					return domain.UserSettings{UserID: userID, PushTopic: "my_topic"}, nil
+				}
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					return nil
+				}
+				store.SaveUserSettingsFunc = func(_ context.Context, settings domain.UserSettings, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.UserSettings{
+						UserID:    expUserID,
+						Channels:  domain.Channels{domain.ChannelTelegram, domain.ChannelPush},
+						PushTopic: "my_topic",
+					}, settings)
+					msg := enqueuedMessage(a, msgs)
+					a.Contains(msg.Text, "По умолчанию: *Telegram, Push*")
+					a.Contains(msg.Text, "Push: `my_topic`")
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: settings cmd, webhook url, secret is sent",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/settings webhook https://example.com/hook",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetUserSettingsFunc = func(_ context.Context, userID int64) (domain.UserSettings, error) {
+					return domain.UserSettings{UserID: userID, WebhookURL: "https://example.com/hook", WebhookSecret: "whsec_old"}, nil
+				}
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					return nil
+				}
+				store.SaveUserSettingsFunc = func(_ context.Context, settings domain.UserSettings, msgs ...domain.OutboxMessage) error {
+					// the same url gets new secret too
+					a.Equal(domain.UserSettings{UserID: expUserID, WebhookURL: "https://example.com/hook", WebhookSecret: "whsec_0123456789abcdef"}, settings)
+					msg := enqueuedMessage(a, msgs)
+					a.Contains(msg.Text, "Секрет для проверки подписи вебхука:\n`whsec_0123456789abcdef`")
+					a.Contains(msg.Text, "Вебхук: `https://example.com/hook`")
+					a.True(msgs[0].Secret)
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: settings cmd, invalid webhook url",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/settings webhook example.com",
 			},
 			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveUserFunc = func(_ context.Context, user domain.User) error {
-					a.Equal(domain.User{
-						ID:     expUserID,
-						Name:   expUserName,
-						Status: domain.UserStatusActive,
-					}, user)
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
 					return nil
 				}
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
-					a.Equal(domain.BotState{
-						UserID: expUserID,
-						Name:   domain.BotStateNameStart,
-					}, botState)
+
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
+					a.Equal("🤔 Не удалось понять настройки из запроса.\n\n"+settingsUsage, response.Text)
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: settings cmd, invalid options",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/settings quick 25:00",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
 					return nil
 				}
 
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, _ ...sender.BotResponseOption) error {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
 					a.Equal(sender.BotResponse{
 						ChatID: expChatID,
-						Text:   "*Привет,* @johndoe 👋\n\nТеперь вы можете со мной работать.\nДля справки 💁 используйте команду /help",
+						Text:   "🤔 Не удалось понять настройки из запроса.\n\n" + settingsUsage,
 					}, response)
 					return nil
 				}
 			},
 		},
 		{
-			name: "success: help cmd",
+			name: "error: settings cmd, can't get user settings",
 			message: domain.TgMessage{
 				ChatID:   expChatID,
 				UserID:   expUserID,
 				UserName: expUserName,
-				Text:     "/help",
+				Text:     "/settings",
 			},
 			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
-					a.Equal(domain.BotState{
-						UserID: expUserID,
-						Name:   domain.BotStateNameHelp,
-					}, botState)
+				store.GetUserSettingsFunc = func(_ context.Context, userID int64) (domain.UserSettings, error) {
+					return domain.UserSettings{}, errors.New("unexpected error")
+				}
+			},
+			expErr: "unexpected error",
+		},
+		{
+			name: "error: settings cmd, can't save user settings, response is discarded along with them",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/settings reset",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
 					return nil
 				}
+				store.SaveUserSettingsFunc = func(_ context.Context, settings domain.UserSettings, msgs ...domain.OutboxMessage) error {
+					// response is a part of the failed write
+					a.Len(msgs, 1)
+					return errors.New("unexpected error")
+				}
+				responseSender.WakeupFunc = func() {
+					a.Fail("sender is woken up though nothing is enqueued")
+				}
+			},
+			expErr: "unexpected error",
+		},
+		{
+			name: "success: my reminders cmd, no reminders found",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/my_reminders",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetMyRemindersFunc = func(_ context.Context, userID int64, chatID int64) ([]domain.Reminder, error) {
+					return nil, nil
+				}
 
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, _ ...sender.BotResponseOption) error {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
 					a.Equal(sender.BotResponse{
 						ChatID: expChatID,
-						Text:   "\n*Список доступных команд*\n\t• /help — cправка 💁\n\t• /start — начать работу с ботом ▶️\n\t• /create\\_reminder — создать напоминание 📝\n\t• /enable\\_reminders — включить напоминания 🔔\n\t• /disable\\_reminders — выключить напоминания 🔕\n\t• /my\\_reminders — мои напоминания 🗒️",
+						Text:   "*У вас нет напоминаний* 😞\n\nЧтобы добавить напоминание используйте команду /create\\_reminder",
 					}, response)
 					return nil
 				}
 			},
 		},
+
+		// messages from user
 		{
-			name: "success: create reminder cmd",
+			name: "success: msg with reminder text",
+			now:  time.Date(2024, 1, 1, 1, 1, 0, 0, time.UTC),
 			message: domain.TgMessage{
 				ChatID:   expChatID,
 				UserID:   expUserID,
 				UserName: expUserName,
-				Text:     "/create_reminder",
+				Text:     "Punishment lawyer blank arrives luis deviant failing, grocery feb.",
 			},
-			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					a.Equal(expUserID, userID)
+					return domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameCreateReminder,
+					}, nil
+				}
+
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
 					a.Equal(domain.BotState{
+						UserID:  expUserID,
+						Name:    domain.BotStateNameEnterReminAt,
+						Context: &domain.BotStateContext{ReminderText: "Punishment lawyer blank arrives luis deviant failing, grocery feb."},
+					}, botState)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "*Когда напомнить ❓\n\n*Текущая дата и время (Москва)\u00a0⏰\n*2024-01-01 04:01*\n\n*Приоритет* можно выбрать кнопками ниже или указать при вводе текста: \"!\" — высокий, \"!!\" — критический.\n\n*Вы можете использовать следующие форматы:*\n\n- в 19:00\n- завтра\n- завтра в 19:00\n- в среду в 15:00\n- через час\n- через 2 часа\n- 30.01.2024 в 11:00\n- через месяц\n- 2024-08-29 11:30\n\n*Введите дату и время напоминания или выберите опцию ниже:*",
+					}, sender.WithReminderDatesButtons(nil)), enqueuedMessage(a, msgs))
+					return nil
+				}
+			},
+		},
+		{
+			name: "error: msg with reminder text, can't get user settings",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "Take pills",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{UserID: expUserID, Name: domain.BotStateNameCreateReminder}, nil
+				}
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					return nil
+				}
+				store.GetUserSettingsFunc = func(_ context.Context, userID int64) (domain.UserSettings, error) {
+					a.Equal(expUserID, userID)
+					return domain.UserSettings{}, errors.New("unexpected error")
+				}
+			},
+			expErr: "unexpected error",
+		},
+		{
+			name: "success: msg with critical reminder text",
+			now:  time.Date(2024, 1, 1, 1, 1, 0, 0, time.UTC),
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "!! Take pills",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{
 						UserID: expUserID,
 						Name:   domain.BotStateNameCreateReminder,
+					}, nil
+				}
+
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameEnterReminAt,
+						Context: &domain.BotStateContext{
+							ReminderText:     "Take pills",
+							ReminderPriority: domain.ReminderPriorityCritical,
+						},
 					}, botState)
+					msg := enqueuedMessage(a, msgs)
+					a.True(strings.HasPrefix(msg.Text, "*🚨 Критический приоритет*\n\n*Когда напомнить ❓"))
+					a.NotNil(msg.ReplyMarkup)
 					return nil
 				}
+			},
+		},
+		{
+			name: "success: msg with remind_at",
+			now:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "2024-01-01 04:01",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameEnterReminAt,
+						Context: &domain.BotStateContext{
+							ReminderText: "FooBarBaz",
+						},
+					}, nil
+				}
 
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, _ ...sender.BotResponseOption) error {
-					a.Equal(sender.BotResponse{
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
+					remindAt := time.Date(2024, 1, 1, 1, 1, 0, 0, time.UTC)
+					a.Equal(domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameConfirmRemindAt,
+						Context: &domain.BotStateContext{
+							ReminderText:     "FooBarBaz",
+							ReminderRemindAt: &remindAt,
+						},
+					}, botState)
+					a.Equal(sender.Render(sender.BotResponse{
 						ChatID: expChatID,
-						Text:   "О чём напомнить❓",
-					}, response)
+						Text:   "*Проверьте время напоминания*\n\nНапомню о *FooBarBaz*\n⏰ *через 1 час 1 минуту, пн 1 янв. 04:01*\n\nЕсли всё верно, нажмите «Подтвердить». Время можно сдвинуть кнопками ниже.",
+					}, sender.WithRemindAtConfirmButtons()), enqueuedMessage(a, msgs))
+					return nil
+				}
+			},
+		},
+		{
+			name: "success: msg with remind_at, passed time of today is moved to tomorrow",
+			now:  time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "2024-01-01 04:01",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{
+						UserID:  expUserID,
+						Name:    domain.BotStateNameConfirmRemindAt,
+						Context: &domain.BotStateContext{ReminderText: "FooBarBaz"},
+					}, nil
+				}
+
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
+					remindAt, ok := botState.ReminderRemindAt()
+					a.True(ok)
+					a.Equal(time.Date(2024, 1, 2, 1, 1, 0, 0, time.UTC), remindAt)
+					msg := enqueuedMessage(a, msgs)
+					a.Equal("Сегодня это время уже прошло, поэтому я перенёс напоминание на завтра 🔄\n\n*Проверьте время напоминания*\n\nНапомню о *FooBarBaz*\n⏰ *через 15 часов 1 минуту, вт 2 янв. 04:01*\n\nЕсли всё верно, нажмите «Подтвердить». Время можно сдвинуть кнопками ниже.", msg.Text)
 					return nil
 				}
 			},
 		},
 		{
-			name: "success: my reminders cmd",
+			name: "success: msg with remind_at in the past",
+			now:  time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
 			message: domain.TgMessage{
 				ChatID:   expChatID,
 				UserID:   expUserID,
 				UserName: expUserName,
-				Text:     "/my_reminders",
+				Text:     "2024-01-01 04:01",
 			},
 			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.GetMyRemindersFunc = func(_ context.Context, userID int64, chatID int64) ([]domain.Reminder, error) {
-					a.Equal(expUserID, userID)
-					a.Equal(expChatID, chatID)
-					return []domain.Reminder{
-						{
-							ID:       12,
-							ChatID:   expChatID,
-							UserID:   expUserID,
-							Text:     "Напоминание 1",
-							RemindAt: time.Date(2024, 1, 1, 1, 1, 1, 0, time.UTC),
-						},
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{
+						UserID:  expUserID,
+						Name:    domain.BotStateNameEnterReminAt,
+						Context: &domain.BotStateContext{ReminderText: "FooBarBaz"},
 					}, nil
 				}
 
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
-					a.Equal(domain.BotState{
-						UserID: expUserID,
-						Name:   domain.BotStateNameMyReminders,
-					}, botState)
-					return nil
-				}
-
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, _ ...sender.BotResponseOption) error {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error {
 					a.Equal(sender.BotResponse{
 						ChatID: expChatID,
-						Text:   "*СПИСОК НАПОМИНАНИЙ*\n\n✅ *Напоминание 1*\n⏰ 1 янв. 04:01\n#️⃣ 12\n\n",
+						Text:   "🤔 Время *2024-01-01 04:01* уже прошло, а напоминание должно быть в будущем. Пожалуйста, введите другое время или выберите опцию ниже.",
 					}, response)
+					a.Len(opts, 1)
 					return nil
 				}
 			},
 		},
 		{
-			name: "success: enable reminders cmd",
+			name: "success: msg with checklist items",
 			message: domain.TgMessage{
 				ChatID:   expChatID,
 				UserID:   expUserID,
 				UserName: expUserName,
-				Text:     "/enable_reminders",
+				Text:     "eggs\ncheese",
 			},
-			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
-					a.Equal(domain.BotState{
-						UserID: expUserID,
-						Name:   domain.BotStateNameEnableReminders,
-					}, botState)
-					return nil
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{
+						UserID:  expUserID,
+						Name:    domain.BotStateNameAddChecklistItems,
+						Context: &domain.BotStateContext{ReminderID: 12345},
+					}, nil
 				}
-				store.SetUserStatusFunc = func(_ context.Context, id int64, status domain.UserStatus) error {
-					a.Equal(expUserID, id)
-					a.Equal(domain.UserStatusActive, status)
+
+				store.AddChecklistItemsFunc = func(_ context.Context, reminderID int64, items []string, msgs ...domain.OutboxMessage) error {
+					a.EqualValues(12345, reminderID)
+					a.Equal([]string{"eggs", "cheese"}, items)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "Добавлено пунктов: 2 ✅",
+					}), enqueuedMessage(a, msgs))
 					return nil
 				}
 
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, _ ...sender.BotResponseOption) error {
-					a.Equal(sender.BotResponse{
-						ChatID: expChatID,
-						Text:   "*Уведомления включены* 🔔\n\nДля отключения уведомлений используйте команду /disable\\_reminders",
-					}, response)
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
+					a.Equal(domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameStart,
+					}, botState)
 					return nil
 				}
 			},
 		},
 		{
-			name: "success: disable reminders cmd",
+			name: "success: msg with settings",
 			message: domain.TgMessage{
 				ChatID:   expChatID,
 				UserID:   expUserID,
 				UserName: expUserName,
-				Text:     "/disable_reminders",
+				Text:     "snooze 10m 1h",
 			},
-			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
-					a.Equal(domain.BotState{
-						UserID: expUserID,
-						Name:   domain.BotStateNameDisableReminders,
-					}, botState)
-					return nil
-				}
-				store.SetUserStatusFunc = func(_ context.Context, id int64, status domain.UserStatus) error {
-					a.Equal(expUserID, id)
-					a.Equal(domain.UserStatusInactive, status)
-					return nil
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{UserID: expUserID, Name: domain.BotStateNameSettings}, nil
 				}
-
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, _ ...sender.BotResponseOption) error {
-					a.Equal(sender.BotResponse{
-						ChatID: expChatID,
-						Text:   "*Уведомления отключены* 🔕\n\nДля включения уведомлений воспользуйтесь командой /enable\\_reminders",
-					}, response)
+				store.GetUserSettingsFunc = func(_ context.Context, userID int64) (domain.UserSettings, error) {
+					a.Equal(expUserID, userID)
+					return domain.UserSettings{UserID: userID}, nil
+				}
+				store.SaveUserSettingsFunc = func(_ context.Context, settings domain.UserSettings, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.UserSettings{
+						UserID:        expUserID,
+						SnoozeOptions: domain.QuickOptions{"10m", "1h"},
+					}, settings)
+					msg := enqueuedMessage(a, msgs)
+					a.True(strings.HasPrefix(msg.Text, "Настройки сохранены ✅"))
+					a.Contains(msg.Text, "Отложить напоминание: *10 мин., 1 ч.*")
 					return nil
 				}
 			},
 		},
 		{
-			name: "success: my reminders cmd, no reminders found",
+			name: "success: msg with invalid settings",
 			message: domain.TgMessage{
 				ChatID:   expChatID,
 				UserID:   expUserID,
 				UserName: expUserName,
-				Text:     "/my_reminders",
+				Text:     "snooze 1000w",
 			},
 			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.GetMyRemindersFunc = func(_ context.Context, userID int64, chatID int64) ([]domain.Reminder, error) {
-					return nil, nil
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{UserID: expUserID, Name: domain.BotStateNameSettings}, nil
+				}
+				store.GetUserSettingsFunc = func(_ context.Context, userID int64) (domain.UserSettings, error) {
+					return domain.UserSettings{UserID: userID}, nil
 				}
 
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, _ ...sender.BotResponseOption) error {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
 					a.Equal(sender.BotResponse{
 						ChatID: expChatID,
-						Text:   "*У вас нет напоминаний* 😞\n\nЧтобы добавить напоминание используйте команду /create\\_reminder",
+						Text:   "🤔 Не удалось понять настройки из запроса.\n\n" + settingsUsage,
 					}, response)
 					return nil
 				}
 			},
 		},
-
-		// messages from user
 		{
-			name: "success: msg with reminder text",
-			now:  time.Date(2024, 1, 1, 1, 1, 0, 0, time.UTC),
+			name: "success: msg without checklist items",
 			message: domain.TgMessage{
 				ChatID:   expChatID,
 				UserID:   expUserID,
 				UserName: expUserName,
-				Text:     "Punishment lawyer blank arrives luis deviant failing, grocery feb.",
+				Text:     " \n - ",
 			},
 			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
 				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
-					a.Equal(expUserID, userID)
 					return domain.BotState{
-						UserID: expUserID,
-						Name:   domain.BotStateNameCreateReminder,
-					}, nil
-				}
-
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
-					a.Equal(domain.BotState{
 						UserID:  expUserID,
-						Name:    domain.BotStateNameEnterReminAt,
-						Context: &domain.BotStateContext{ReminderText: "Punishment lawyer blank arrives luis deviant failing, grocery feb."},
-					}, botState)
-					return nil
+						Name:    domain.BotStateNameAddChecklistItems,
+						Context: &domain.BotStateContext{ReminderID: 12345},
+					}, nil
 				}
 
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, opts ...sender.BotResponseOption) error {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
 					a.Equal(sender.BotResponse{
 						ChatID: expChatID,
-						Text:   "*Когда напомнить ❓\n\n*Текущая дата и время (Москва)\u00a0⏰\n*2024-01-01 04:01*\n\n*Вы можете использовать следующие форматы:*\n\n- в 19:00\n- завтра\n- завтра в 19:00\n- в среду в 15:00\n- через час\n- через 2 часа\n- 30.01.2024 в 11:00\n- через месяц\n- 2024-08-29 11:30\n\n*Введите дату и время напоминания или выберите опцию ниже:*",
+						Text:   "Напишите пункты списка 📋, каждый с новой строки.",
 					}, response)
-					a.Len(opts, 1)
 					return nil
 				}
 			},
 		},
 		{
-			name: "success: msg with remind_at",
+			name: "success: msg with checklist items, reminder is not found",
 			message: domain.TgMessage{
 				ChatID:   expChatID,
 				UserID:   expUserID,
 				UserName: expUserName,
-				Text:     "2024-01-01 04:01",
+				Text:     "eggs",
 			},
-			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
 				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
 					return domain.BotState{
-						UserID: expUserID,
-						Name:   domain.BotStateNameEnterReminAt,
-						Context: &domain.BotStateContext{
-							ReminderText: "FooBarBaz",
-						},
+						UserID:  expUserID,
+						Name:    domain.BotStateNameAddChecklistItems,
+						Context: &domain.BotStateContext{ReminderID: 12345},
 					}, nil
 				}
 
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
-					a.Equal(domain.BotState{
-						UserID: expUserID,
-						Name:   domain.BotStateNameStart,
-					}, botState)
-					return nil
-				}
-
-				store.SaveReminderFunc = func(_ context.Context, reminder domain.Reminder) (int64, error) {
-					a.Equal(domain.Reminder{
-						ChatID:       expChatID,
-						UserID:       expUserID,
-						Text:         "FooBarBaz",
-						RemindAt:     time.Date(2024, 1, 1, 1, 1, 0, 0, time.UTC),
-						Status:       domain.ReminderStatusPending,
-						AttemptsLeft: 10,
-					}, reminder)
-					return 1, nil
+				store.AddChecklistItemsFunc = func(_ context.Context, reminderID int64, items []string, _ ...domain.OutboxMessage) error {
+					return storage.ErrReminderNotFound
 				}
 
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, _ ...sender.BotResponseOption) error {
-					a.Equal(sender.BotResponse{
-						ChatID: expChatID,
-						Text:   "*2024-01-01 04:01* я напомню вам о *FooBarBaz* ✅",
-					}, response)
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
+					msg := enqueuedMessage(a, msgs)
+					a.Equal("Напоминание 12345 не найдено 🤔", msg.Text)
 					return nil
 				}
 			},
@@ -725,7 +2609,7 @@ func TestBot_OnMessage(t *testing.T) {
 				UserName: expUserName,
 				Text:     "12345",
 			},
-			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
 				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
 					return domain.BotState{
 						UserID: expUserID,
@@ -733,26 +2617,22 @@ func TestBot_OnMessage(t *testing.T) {
 					}, nil
 				}
 
-				store.RemoveReminderFunc = func(ctx context.Context, id int64) error {
+				store.RemoveReminderFunc = func(ctx context.Context, id int64, msgs ...domain.OutboxMessage) error {
 					a.EqualValues(12345, id)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "Напоминание 12345 удалено ❌",
+					}), enqueuedMessage(a, msgs))
 					return nil
 				}
 
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
 					a.Equal(domain.BotState{
 						UserID: expUserID,
 						Name:   domain.BotStateNameStart,
 					}, botState)
 					return nil
 				}
-
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, _ ...sender.BotResponseOption) error {
-					a.Equal(sender.BotResponse{
-						ChatID: expChatID,
-						Text:   "Напоминание 12345 удалено ❌",
-					}, response)
-					return nil
-				}
 			},
 		},
 		{
@@ -772,7 +2652,7 @@ func TestBot_OnMessage(t *testing.T) {
 					}, nil
 				}
 
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, _ ...sender.BotResponseOption) error {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, _ ...sender.BotResponseOption) error {
 					a.Equal(sender.BotResponse{
 						ChatID: expChatID,
 						Text:   "Я не понимаю о чём речь 🤔 Пожалуйста, воспользуйтесь командой /help.",
@@ -786,28 +2666,24 @@ func TestBot_OnMessage(t *testing.T) {
 		{
 			name: "error: start cmd, user already exists",
 			message: domain.TgMessage{
-				ChatID:   expChatID,
+				ChatID:   expUserID,
 				UserID:   expUserID,
 				UserName: expUserName,
 				Text:     "/start",
 			},
-			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveUserFunc = func(_ context.Context, user domain.User) error {
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.SaveUserFunc = func(_ context.Context, user domain.User, _ ...domain.OutboxMessage) error {
 					return storage.ErrUserAlreadyExists
 				}
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
 					a.Equal(domain.BotState{
 						UserID: expUserID,
 						Name:   domain.BotStateNameStart,
 					}, botState)
-					return nil
-				}
-
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, _ ...sender.BotResponseOption) error {
-					a.Equal(sender.BotResponse{
-						ChatID: expChatID,
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expUserID,
 						Text:   "@johndoe, ранее мы уже начали общение, предлагаю продолжить 👋",
-					}, response)
+					}), enqueuedMessage(a, msgs))
 					return nil
 				}
 			},
@@ -815,13 +2691,13 @@ func TestBot_OnMessage(t *testing.T) {
 		{
 			name: "error: start cmd, save user db error",
 			message: domain.TgMessage{
-				ChatID:   expChatID,
+				ChatID:   expUserID,
 				UserID:   expUserID,
 				UserName: expUserName,
 				Text:     "/start",
 			},
 			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveUserFunc = func(_ context.Context, user domain.User) error {
+				store.SaveUserFunc = func(_ context.Context, user domain.User, _ ...domain.OutboxMessage) error {
 					return errors.New("db error")
 				}
 			},
@@ -830,16 +2706,16 @@ func TestBot_OnMessage(t *testing.T) {
 		{
 			name: "error: start cmd, user already exists, save bot state error",
 			message: domain.TgMessage{
-				ChatID:   expChatID,
+				ChatID:   expUserID,
 				UserID:   expUserID,
 				UserName: expUserName,
 				Text:     "/start",
 			},
 			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveUserFunc = func(_ context.Context, user domain.User) error {
+				store.SaveUserFunc = func(_ context.Context, user domain.User, _ ...domain.OutboxMessage) error {
 					return storage.ErrUserAlreadyExists
 				}
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
 					return errors.New("db error")
 				}
 			},
@@ -854,7 +2730,7 @@ func TestBot_OnMessage(t *testing.T) {
 				Text:     "/help",
 			},
 			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
 					return errors.New("db error")
 				}
 			},
@@ -869,7 +2745,7 @@ func TestBot_OnMessage(t *testing.T) {
 				Text:     "/create_reminder",
 			},
 			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
 					return errors.New("db error")
 				}
 			},
@@ -890,6 +2766,17 @@ func TestBot_OnMessage(t *testing.T) {
 			},
 			expErr: `db error`,
 		},
+		{
+			name: "errors: enable reminders cmd, can't catch up missed reminders",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "/enable_reminders",
+			},
+			catchUpErr: dbError,
+			expErr:     `db error`,
+		},
 		{
 			name: "errors: enable reminders cmd, set user status error",
 			message: domain.TgMessage{
@@ -899,7 +2786,7 @@ func TestBot_OnMessage(t *testing.T) {
 				Text:     "/enable_reminders",
 			},
 			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
-				store.SetUserStatusFunc = func(_ context.Context, id int64, status domain.UserStatus) error {
+				store.SetUserStatusFunc = func(_ context.Context, id int64, status domain.UserStatus, _ ...domain.OutboxMessage) error {
 					return errors.New("db error")
 				}
 			},
@@ -923,7 +2810,7 @@ func TestBot_OnMessage(t *testing.T) {
 					}, nil
 				}
 
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
 					return dbError
 				}
 			},
@@ -948,7 +2835,7 @@ func TestBot_OnMessage(t *testing.T) {
 					}, nil
 				}
 
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, opts ...sender.BotResponseOption) error {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error {
 					a.Len(opts, 1)
 					a.Equal(sender.BotResponse{
 						ChatID: expChatID,
@@ -984,7 +2871,7 @@ func TestBot_OnMessage(t *testing.T) {
 				UserName: expUserName,
 				Text:     "12345",
 			},
-			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
 				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
 					return domain.BotState{
 						UserID: expUserID,
@@ -992,23 +2879,19 @@ func TestBot_OnMessage(t *testing.T) {
 					}, nil
 				}
 
-				store.RemoveReminderFunc = func(ctx context.Context, id int64) error {
+				store.RemoveReminderFunc = func(ctx context.Context, id int64, _ ...domain.OutboxMessage) error {
 					return storage.ErrReminderNotFound
 				}
 
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
 					a.Equal(domain.BotState{
 						UserID: expUserID,
 						Name:   domain.BotStateNameStart,
 					}, botState)
-					return nil
-				}
-
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, _ ...sender.BotResponseOption) error {
-					a.Equal(sender.BotResponse{
+					a.Equal(sender.Render(sender.BotResponse{
 						ChatID: expChatID,
 						Text:   "Напоминание 12345 не найдено 🤔",
-					}, response)
+					}), enqueuedMessage(a, msgs))
 					return nil
 				}
 			},
@@ -1029,7 +2912,7 @@ func TestBot_OnMessage(t *testing.T) {
 					}, nil
 				}
 
-				store.RemoveReminderFunc = func(ctx context.Context, id int64) error {
+				store.RemoveReminderFunc = func(ctx context.Context, id int64, _ ...domain.OutboxMessage) error {
 					return dbError
 				}
 			},
@@ -1051,17 +2934,115 @@ func TestBot_OnMessage(t *testing.T) {
 					}, nil
 				}
 
-				store.RemoveReminderFunc = func(ctx context.Context, id int64) error {
+				store.RemoveReminderFunc = func(ctx context.Context, id int64, _ ...domain.OutboxMessage) error {
 					a.EqualValues(12345, id)
 					return nil
 				}
 
-				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState) error {
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, _ ...domain.OutboxMessage) error {
 					return dbError
 				}
 			},
 			expErr: dbError.Error(),
 		},
+		{
+			name: "success: msg with reminder id to show history",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "12345",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameReminderHistory,
+					}, nil
+				}
+
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					a.EqualValues(12345, id)
+					return domain.Reminder{ID: id, UserID: expUserID, Text: "Standup"}, nil
+				}
+
+				store.GetReminderEventsFunc = func(ctx context.Context, reminderID int64) ([]domain.ReminderEvent, error) {
+					a.EqualValues(12345, reminderID)
+					a.Equal(domain.Actor{Type: domain.ActorUser, ID: expUserID}, domain.ActorFromContext(ctx))
+					return []domain.ReminderEvent{
+						{Type: domain.ReminderEventCreated, ActorType: domain.ActorUser, ActorID: expUserID, CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+						{Type: domain.ReminderEventNotified, ActorType: domain.ActorNotifier, Attempt: 1, CreatedAt: time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)},
+					}, nil
+				}
+
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
+					a.Equal(domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameStart,
+					}, botState)
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "📜 *История напоминания «Standup»*\n\n\t• 1 янв. 15:00 — создано (пользователь)\n\t• 2 янв. 15:00 — отправлено, попытка 1 (бот)",
+					}), enqueuedMessage(a, msgs))
+					return nil
+				}
+			},
+		},
+		{
+			name: "error: msg with reminder id to show history, reminder of other user",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "12345",
+			},
+			setMocks: func(a *assert.Assertions, _ *ResponseSenderMock, store *StorageMock) {
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameReminderHistory,
+					}, nil
+				}
+
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					return domain.Reminder{ID: id, UserID: expUserID + 1, Text: "Standup"}, nil
+				}
+
+				store.SaveBotStateFunc = func(_ context.Context, botState domain.BotState, msgs ...domain.OutboxMessage) error {
+					a.Equal(sender.Render(sender.BotResponse{
+						ChatID: expChatID,
+						Text:   "Напоминание 12345 не найдено 🤔",
+					}), enqueuedMessage(a, msgs))
+					return nil
+				}
+			},
+		},
+		{
+			name: "error: msg with reminder id to show history, can't get events",
+			message: domain.TgMessage{
+				ChatID:   expChatID,
+				UserID:   expUserID,
+				UserName: expUserName,
+				Text:     "12345",
+			},
+			setMocks: func(a *assert.Assertions, responseSender *ResponseSenderMock, store *StorageMock) {
+				store.GetBotStateFunc = func(_ context.Context, userID int64) (domain.BotState, error) {
+					return domain.BotState{
+						UserID: expUserID,
+						Name:   domain.BotStateNameReminderHistory,
+					}, nil
+				}
+
+				store.GetReminderFunc = func(_ context.Context, id int64) (domain.Reminder, error) {
+					return domain.Reminder{ID: id, UserID: expUserID, Text: "Standup"}, nil
+				}
+
+				store.GetReminderEventsFunc = func(_ context.Context, _ int64) ([]domain.ReminderEvent, error) {
+					return nil, dbError
+				}
+			},
+			expErr: dbError.Error(),
+		},
 		{
 			name: "error: can't get bot state",
 			message: domain.TgMessage{
@@ -1093,7 +3074,7 @@ func TestBot_OnMessage(t *testing.T) {
 					}, nil
 				}
 
-				responseSender.SendBotResponseFunc = func(response sender.BotResponse, opts ...sender.BotResponseOption) error {
+				responseSender.SendBotResponseFunc = func(_ context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error {
 					a.Equal(sender.BotResponse{
 						ChatID: expChatID,
 						Text:   "Я не понимаю о чём речь 🤔 Пожалуйста, воспользуйтесь командой /help.",
@@ -1122,6 +3103,46 @@ func TestBot_OnMessage(t *testing.T) {
 		},
 	}
 
+	tmpNewAPIToken := newAPIToken
+	defer func() {
+		newAPIToken = tmpNewAPIToken
+	}()
+	newAPIToken = func() (string, error) {
+		return "tgr_0123456789abcdef", nil
+	}
+
+	tmpNewWebToken := newWebToken
+	defer func() {
+		newWebToken = tmpNewWebToken
+	}()
+	newWebToken = func() (string, error) {
+		return "0123456789abcdef", nil
+	}
+
+	tmpNewHookToken := newHookToken
+	defer func() {
+		newHookToken = tmpNewHookToken
+	}()
+	newHookToken = func() (string, error) {
+		return "tgh_0123456789abcdef", nil
+	}
+
+	tmpNewEmailCode := newEmailCode
+	defer func() {
+		newEmailCode = tmpNewEmailCode
+	}()
+	newEmailCode = func() (string, error) {
+		return "012345", nil
+	}
+
+	tmpNewWebhookSecret := newWebhookSecret
+	defer func() {
+		newWebhookSecret = tmpNewWebhookSecret
+	}()
+	newWebhookSecret = func() (string, error) {
+		return "whsec_0123456789abcdef", nil
+	}
+
 	for _, tc := range testCases {
 		// nolint:paralleltest // test modifies package level function timeNowUTC.
 		t.Run(tc.name, func(t *testing.T) {
@@ -1141,8 +3162,25 @@ func TestBot_OnMessage(t *testing.T) {
 			if tc.setMocks != nil {
 				tc.setMocks(a, senderMock, storeMock)
 			}
+			if storeMock.GetUserSettingsFunc == nil {
+				// user hasn't changed settings, defaults are used
+				storeMock.GetUserSettingsFunc = func(ctx context.Context, userID int64) (domain.UserSettings, error) {
+					return domain.UserSettings{UserID: userID}, nil
+				}
+			}
+			if senderMock.WakeupFunc == nil {
+				// responses to changes of state are enqueued by storage, sender is only woken up to deliver them
+				senderMock.WakeupFunc = func() {}
+			}
 
-			botImpl := New(senderMock, storeMock)
+			notifierMock := &NotifierMock{
+				CatchUpFunc: func(_ context.Context, userID int64) error {
+					a.Equal(tc.message.UserID, userID)
+					return tc.catchUpErr
+				},
+			}
+
+			botImpl := New(senderMock, storeMock, notifierMock, Config{WebURL: tc.webURL, Channels: tc.channels})
 
 			actErr := botImpl.OnMessage(context.TODO(), tc.message)
 
@@ -1151,6 +3189,41 @@ func TestBot_OnMessage(t *testing.T) {
 			} else {
 				a.NoError(actErr)
 			}
+			if senderMock.SendBotResponseFunc != nil {
+				a.NotEmpty(senderMock.SendBotResponseCalls(), "expected response isn't sent")
+			}
 		})
 	}
 }
+
+func TestNew_CommandsMatchRegistry(t *testing.T) {
+	t.Parallel()
+
+	botImpl := New(&ResponseSenderMock{}, &StorageMock{}, &NotifierMock{}, Config{})
+
+	// every user command of the registry is dispatched and nothing else is
+	registered := domain.BotCommandsFor(domain.BotCommandAccessUser, true)
+	assert.Len(t, botImpl.commands, len(registered))
+	for _, c := range registered {
+		assert.Contains(t, botImpl.commands, c.Command)
+	}
+}
+
+func Test_buttonName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "btn_delay_reminder", buttonName("btn_delay_reminder/42/1h"))
+	assert.Equal(t, "btn_catch_up_skip", buttonName("btn_catch_up_skip"))
+}
+
+// enqueuedMessage decodes the only bot response enqueued by storage along with a change of state.
+func enqueuedMessage(a *assert.Assertions, msgs []domain.OutboxMessage) sender.Message {
+	if !a.Len(msgs, 1) {
+		return sender.Message{}
+	}
+
+	msg, err := sender.DecodeOutboxMessage(msgs[0])
+	a.NoError(err)
+
+	return msg
+}
@@ -2,11 +2,14 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mezk/tg-reminder/internal/pkg/domain"
 	"github.com/mezk/tg-reminder/internal/pkg/sender"
+	"github.com/mezk/tg-reminder/internal/pkg/storage"
 )
 
 func (b *Bot) onDoneReminderButton(ctx context.Context, callback domain.TgCallbackQuery) error {
@@ -15,29 +18,35 @@ func (b *Bot) onDoneReminderButton(ctx context.Context, callback domain.TgCallba
 		return fmt.Errorf("can'p parse reminderID: %w", err)
 	}
 
-	if err = b.store.SetReminderStatus(ctx, reminderID, domain.ReminderStatusDone); err != nil {
+	err = b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SetReminderStatus(ctx, reminderID, domain.ReminderStatusDone, msg)
+	}, sender.BotResponse{ChatID: callback.ChatID, Text: fmt.Sprintf("Я пометил напоминание как выполненное %s", domain.EmojiWhiteHeavyCheckMark)})
+	if err != nil {
 		return err
 	}
 
 	// go to start state
-	if err = b.store.SaveBotState(ctx, domain.BotState{UserID: callback.UserID, Name: domain.BotStateNameStart}); err != nil {
-		return err
-	}
-
-	return b.responseSender.SendBotResponse(sender.BotResponse{ChatID: callback.ChatID, Text: fmt.Sprintf("Я пометил напоминание как выполненное %s", domain.EmojiWhiteHeavyCheckMark)})
+	return b.store.SaveBotState(ctx, domain.BotState{UserID: callback.UserID, Name: domain.BotStateNameStart})
 }
 
 func (b *Bot) onRemoveReminderButton(ctx context.Context, callback domain.TgCallbackQuery) error {
-	if err := b.store.SaveBotState(ctx, domain.BotState{UserID: callback.UserID, Name: domain.BotStateNameRemoveReminder}); err != nil {
-		return err
-	}
-
-	return b.responseSender.SendBotResponse(sender.BotResponse{
+	return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SaveBotState(ctx, domain.BotState{UserID: callback.UserID, Name: domain.BotStateNameRemoveReminder}, msg)
+	}, sender.BotResponse{
 		ChatID: callback.ChatID,
 		Text:   fmt.Sprintf("Напишите номер %s напоминания для удаления.", domain.EmojiKeycapHash),
 	})
 }
 
+func (b *Bot) onReminderHistoryButton(ctx context.Context, callback domain.TgCallbackQuery) error {
+	return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SaveBotState(ctx, domain.BotState{UserID: callback.UserID, Name: domain.BotStateNameReminderHistory}, msg)
+	}, sender.BotResponse{
+		ChatID: callback.ChatID,
+		Text:   fmt.Sprintf("Напишите номер %s напоминания, историю которого хотите посмотреть.", domain.EmojiKeycapHash),
+	})
+}
+
 func (b *Bot) onDelayReminderButton(ctx context.Context, callback domain.TgCallbackQuery) error {
 	reminderID, err := callback.ReminderID()
 	if err != nil {
@@ -49,18 +58,64 @@ func (b *Bot) onDelayReminderButton(ctx context.Context, callback domain.TgCallb
 		return fmt.Errorf("can't parse delay: %w", err)
 	}
 
-	if err = b.store.DelayReminder(ctx, reminderID, remindAt.In(time.UTC)); err != nil {
+	err = b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.DelayReminder(ctx, reminderID, remindAt.In(time.UTC), msg)
+	}, sender.BotResponse{
+		ChatID: callback.ChatID,
+		Text:   fmt.Sprintf("*Я отложил напоминание* %s\n\nНапомню позже *%s* %s", domain.EmojiCounterclockwiseArrowsButton, remindAt.Format(domain.LayoutRemindAt), domain.EmojiAlarmClock),
+	})
+	if err != nil {
 		return err
 	}
 
 	// go to start state
-	if err = b.store.SaveBotState(ctx, domain.BotState{UserID: callback.UserID, Name: domain.BotStateNameStart}); err != nil {
+	return b.store.SaveBotState(ctx, domain.BotState{UserID: callback.UserID, Name: domain.BotStateNameStart})
+}
+
+func (b *Bot) onCatchUpButton(ctx context.Context, callback domain.TgCallbackQuery) error {
+	remindAt, err := callback.RemindAt(timeNowUTC())
+	if err != nil {
+		return fmt.Errorf("can't parse catch up option: %w", err)
+	}
+
+	// buttons of summaries sent before summaries got keys reschedule nothing
+	var ids []int64
+	if summaryKey, keyErr := callback.SummaryKey(); keyErr == nil {
+		if ids, err = b.store.GetSummaryReminderIDs(ctx, callback.UserID, callback.ChatID, summaryKey); err != nil {
+			return err
+		}
+	}
+
+	_, err = b.store.RescheduleMissedReminders(ctx, callback.UserID, callback.ChatID, ids, remindAt.In(time.UTC),
+		func(rescheduled int64) ([]domain.OutboxMessage, error) {
+			if rescheduled == 0 {
+				return newResponses(sender.BotResponse{
+					ChatID: callback.ChatID,
+					Text:   fmt.Sprintf("Пропущенных напоминаний больше нет %s", domain.EmojiWhiteHeavyCheckMark),
+				})
+			}
+
+			return newResponses(sender.BotResponse{
+				ChatID: callback.ChatID,
+				Text: fmt.Sprintf("*Я отложил пропущенные напоминания* %s\n\nНапомню о них (%d) *%s* %s",
+					domain.EmojiCounterclockwiseArrowsButton, rescheduled, remindAt.Format(domain.LayoutRemindAt), domain.EmojiAlarmClock),
+			})
+		})
+	if err != nil {
 		return err
 	}
 
-	return b.responseSender.SendBotResponse(sender.BotResponse{
-		ChatID: callback.ChatID,
-		Text:   fmt.Sprintf("*Я отложил напоминание* %s\n\nНапомню позже *%s* %s", domain.EmojiCounterclockwiseArrowsButton, remindAt.Format(domain.LayoutRemindAt), domain.EmojiAlarmClock),
+	b.responseSender.Wakeup()
+
+	return nil
+}
+
+func (b *Bot) onCatchUpSkipButton(ctx context.Context, callback domain.TgCallbackQuery) error {
+	// missed reminders stay missed, only buttons are removed
+	return b.responseSender.SendBotResponse(ctx, sender.BotResponse{
+		ChatID:        callback.ChatID,
+		EditMessageID: callback.MessageID,
+		Text:          fmt.Sprintf("Пропущенные напоминания не будут отправлены %s", domain.EmojiCrossMark),
 	})
 }
 
@@ -70,15 +125,300 @@ func (b *Bot) onRemindAtButton(ctx context.Context, callback domain.TgCallbackQu
 		return err
 	}
 
+	// time picked in calendar can be in the past
+	if !remindAt.After(timeNowUTC()) {
+		return b.sendRemindAtInPast(ctx, callback.UserID, callback.ChatID, remindAt)
+	}
+
 	return b.createReminder(ctx, callback.UserID, callback.ChatID, remindAt)
 }
 
-func (b *Bot) onEditReminderButton(ctx context.Context, callback domain.TgCallbackQuery) error {
-	if err := b.store.SaveBotState(ctx, domain.BotState{UserID: callback.UserID, Name: domain.BotStateNameEditReminder}); err != nil {
+// onRemindAtPickerButton shows the next step of picker in the same message: calendar, hours of picked date or minutes of picked hour.
+func (b *Bot) onRemindAtPickerButton(ctx context.Context, callback domain.TgCallbackQuery) error {
+	now := timeNowUTC()
+
+	picked, err := callback.PickerTime(now)
+	if err != nil {
+		return fmt.Errorf("can't parse picker time: %w", err)
+	}
+
+	resp := sender.BotResponse{ChatID: callback.ChatID, EditMessageID: callback.MessageID}
+
+	var opt sender.BotResponseOption
+	switch {
+	case strings.HasPrefix(callback.Data, domain.ButtonDataPrefixRemindAtCalendar):
+		resp.Text = fmt.Sprintf("*Выберите дату* %s", domain.EmojiCalendar)
+		opt = sender.WithCalendarButtons(picked, domain.MoscowTime(now))
+	case strings.HasPrefix(callback.Data, domain.ButtonDataPrefixRemindAtDate):
+		resp.Text = fmt.Sprintf("*Выберите час* %s\n\n%s", domain.EmojiAlarmClock, picked.Format(layoutPickerDate))
+		opt = sender.WithHourPickerButtons(picked)
+	default:
+		resp.Text = fmt.Sprintf("*Выберите время* %s\n\n%s", domain.EmojiAlarmClock, picked.Format(layoutPickerDate))
+		opt = sender.WithMinutePickerButtons(picked)
+	}
+
+	return b.responseSender.SendBotResponse(ctx, resp, opt)
+}
+
+const layoutPickerDate = "02.01.2006"
+
+func (b *Bot) onConfirmRemindAtButton(ctx context.Context, callback domain.TgCallbackQuery) error {
+	botState, err := b.store.GetBotState(ctx, callback.UserID)
+	if err != nil {
+		return err
+	}
+
+	if botState.Name != domain.BotStateNameConfirmRemindAt {
+		return fmt.Errorf("can't confirm remindAt: invalid bot state: expected [%s], actual [%s]", domain.BotStateNameConfirmRemindAt, botState.Name)
+	}
+
+	remindAt, _ := botState.ReminderRemindAt()
+	if !remindAt.After(timeNowUTC()) {
+		// time has passed while user was thinking, ask to enter it again
+		botState.Name = domain.BotStateNameEnterReminAt
+		botState.Context.ReminderRemindAt = nil
+
+		resp, opt, err := b.remindAtInPastResponse(ctx, callback.UserID, callback.ChatID, remindAt)
+		if err != nil {
+			return err
+		}
+
+		return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+			return b.store.SaveBotState(ctx, botState, msg)
+		}, resp, opt)
+	}
+
+	return b.createReminder(ctx, callback.UserID, callback.ChatID, remindAt)
+}
+
+func (b *Bot) onShiftRemindAtButton(ctx context.Context, callback domain.TgCallbackQuery) error {
+	shift, err := callback.RemindAtShift()
+	if err != nil {
+		return fmt.Errorf("can't parse remindAt shift: %w", err)
+	}
+
+	botState, err := b.store.GetBotState(ctx, callback.UserID)
+	if err != nil {
 		return err
 	}
 
-	return b.responseSender.SendBotResponse(sender.BotResponse{
+	if botState.Name != domain.BotStateNameConfirmRemindAt {
+		return fmt.Errorf("can't shift remindAt: invalid bot state: expected [%s], actual [%s]", domain.BotStateNameConfirmRemindAt, botState.Name)
+	}
+
+	remindAt, _ := botState.ReminderRemindAt()
+	remindAt = remindAt.Add(shift)
+
+	if !remindAt.After(timeNowUTC()) {
+		return b.responseSender.SendBotResponse(ctx, sender.BotResponse{
+			ChatID: callback.ChatID,
+			Text:   fmt.Sprintf("Нельзя перенести напоминание в прошлое %s", domain.EmojiThinkingFace),
+		})
+	}
+
+	botState.SetReminderRemindAt(remindAt)
+
+	return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SaveBotState(ctx, botState, msg)
+	}, remindAtPreview(sender.BotResponse{ChatID: callback.ChatID, EditMessageID: callback.MessageID}, botState, ""), sender.WithRemindAtConfirmButtons())
+}
+
+func (b *Bot) onReenterRemindAtButton(ctx context.Context, callback domain.TgCallbackQuery) error {
+	botState, err := b.store.GetBotState(ctx, callback.UserID)
+	if err != nil {
+		return err
+	}
+
+	if botState.Name != domain.BotStateNameConfirmRemindAt {
+		return fmt.Errorf("can't reenter remindAt: invalid bot state: expected [%s], actual [%s]", domain.BotStateNameConfirmRemindAt, botState.Name)
+	}
+
+	botState.Name = domain.BotStateNameEnterReminAt
+	botState.Context.ReminderRemindAt = nil
+
+	return b.saveEnterRemindAt(ctx, botState, callback.ChatID)
+}
+
+func (b *Bot) onReminderPriorityButton(ctx context.Context, callback domain.TgCallbackQuery) error {
+	priority, err := callback.ReminderPriority()
+	if err != nil {
+		return fmt.Errorf("can't parse priority: %w", err)
+	}
+
+	botState, err := b.store.GetBotState(ctx, callback.UserID)
+	if err != nil {
+		return err
+	}
+
+	if botState.Name != domain.BotStateNameEnterReminAt {
+		return fmt.Errorf("can't set reminder priority: invalid bot state: expected [%s], actual [%s]", domain.BotStateNameEnterReminAt, botState.Name)
+	}
+
+	botState.SetReminderPriority(priority)
+
+	return b.saveEnterRemindAt(ctx, botState, callback.ChatID)
+}
+
+func (b *Bot) onLeadTimeButton(ctx context.Context, callback domain.TgCallbackQuery) error {
+	reminderID, err := callback.ReminderID()
+	if err != nil {
+		return fmt.Errorf("can't parse reminderID: %w", err)
+	}
+
+	leadTime, err := callback.LeadTime()
+	if err != nil {
+		return fmt.Errorf("can't parse lead time: %w", err)
+	}
+
+	reminder, err := b.store.GetReminder(ctx, reminderID)
+	if err != nil && !errors.Is(err, storage.ErrReminderNotFound) {
+		return err
+	}
+
+	// reminders of other users are reported as not found
+	if errors.Is(err, storage.ErrReminderNotFound) || !reminder.IsOwnedBy(callback.UserID, callback.ChatID) || reminder.Status != domain.ReminderStatusPending {
+		return b.responseSender.SendBotResponse(ctx, sender.BotResponse{
+			ChatID: callback.ChatID,
+			Text:   fmt.Sprintf("Напоминание %d не найдено %s", reminderID, domain.EmojiThinkingFace),
+		})
+	}
+
+	now := timeNowUTC()
+	if !reminder.LeadTimes.Contains(leadTime) && !reminder.RemindAt.Add(-leadTime).After(now) {
+		return b.responseSender.SendBotResponse(ctx, sender.BotResponse{
+			ChatID: callback.ChatID,
+			Text:   fmt.Sprintf("Уже поздно напоминать за %s %s", domain.FormatDuration(leadTime), domain.EmojiDisappointedFace),
+		})
+	}
+
+	leadTimes := reminder.LeadTimes.Toggle(leadTime)
+
+	var nextPreNoticeAt *time.Time
+	if next, ok := leadTimes.NextNoticeAt(reminder.RemindAt, now); ok {
+		nextPreNoticeAt = &next
+	}
+
+	text := fmt.Sprintf("Заранее напоминать о *%s* не буду %s", reminder.Text, domain.EmojiBellWithSlash)
+	if len(leadTimes) > 0 {
+		text = fmt.Sprintf("Я заранее напомню о *%s* %s %s", reminder.Text, leadTimes.Format(), domain.EmojiBell)
+	}
+
+	return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SetReminderLeadTimes(ctx, reminderID, leadTimes, nextPreNoticeAt, msg)
+	}, sender.BotResponse{ChatID: callback.ChatID, Text: text})
+}
+
+func (b *Bot) onAddChecklistItemsButton(ctx context.Context, callback domain.TgCallbackQuery) error {
+	reminderID, err := callback.ReminderID()
+	if err != nil {
+		return fmt.Errorf("can't parse reminderID: %w", err)
+	}
+
+	reminder, err := b.store.GetReminder(ctx, reminderID)
+	if err != nil && !errors.Is(err, storage.ErrReminderNotFound) {
+		return err
+	}
+
+	// reminders of other users are reported as not found
+	if errors.Is(err, storage.ErrReminderNotFound) || !reminder.IsOwnedBy(callback.UserID, callback.ChatID) {
+		return b.responseSender.SendBotResponse(ctx, sender.BotResponse{
+			ChatID: callback.ChatID,
+			Text:   fmt.Sprintf("Напоминание %d не найдено %s", reminderID, domain.EmojiThinkingFace),
+		})
+	}
+
+	state := domain.BotState{UserID: callback.UserID, Name: domain.BotStateNameAddChecklistItems}
+	state.SetReminderID(reminderID)
+
+	return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SaveBotState(ctx, state, msg)
+	}, sender.BotResponse{
+		ChatID: callback.ChatID,
+		Text:   checklistItemsUsage,
+	})
+}
+
+// checklistItemsUsage - instruction how to add checklist items.
+var checklistItemsUsage = fmt.Sprintf("Напишите пункты списка %s, каждый с новой строки.", domain.EmojiClipboard)
+
+func (b *Bot) onToggleChecklistItemButton(ctx context.Context, callback domain.TgCallbackQuery) error {
+	itemID, err := callback.ChecklistItemID()
+	if err != nil {
+		return fmt.Errorf("can't parse checklist item id: %w", err)
+	}
+
+	notFound := sender.BotResponse{
+		ChatID: callback.ChatID,
+		Text:   fmt.Sprintf("Пункт списка не найден %s", domain.EmojiThinkingFace),
+	}
+
+	item, err := b.store.GetChecklistItem(ctx, itemID)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrChecklistItemNotFound):
+			return b.responseSender.SendBotResponse(ctx, notFound)
+		default:
+			return err
+		}
+	}
+
+	reminder, err := b.store.GetReminder(ctx, item.ReminderID)
+	if err != nil {
+		return err
+	}
+
+	// items of other users' reminders are reported as not found
+	if !reminder.IsOwnedBy(callback.UserID, callback.ChatID) {
+		return b.responseSender.SendBotResponse(ctx, notFound)
+	}
+
+	// checklist of done, removed or missed reminder is read only
+	if reminder.Status != domain.ReminderStatusPending {
+		return b.responseSender.SendBotResponse(ctx, sender.BotResponse{
+			ChatID: callback.ChatID,
+			Text:   fmt.Sprintf("Напоминание *%s* уже неактивно, список нельзя изменить %s", reminder.Text, domain.EmojiThinkingFace),
+		})
+	}
+
+	settings, err := b.store.GetUserSettings(ctx, reminder.UserID)
+	if err != nil {
+		return err
+	}
+
+	// reminder is done by storage when all items are checked, message is edited by toggled checklist
+	_, err = b.store.ToggleChecklistItem(ctx, itemID, func(reminder domain.Reminder) ([]domain.OutboxMessage, error) {
+		if reminder.Status != domain.ReminderStatusDone {
+			return newResponses(sender.BotResponse{
+				ChatID:        callback.ChatID,
+				EditMessageID: callback.MessageID,
+				Text:          reminder.FormatNotify(),
+			}, sender.WithChecklistButtons(reminder.Checklist), sender.WithReminderDoneButton(reminder.ID, settings.SnoozeOptions))
+		}
+
+		return newResponses(sender.BotResponse{
+			ChatID:        callback.ChatID,
+			EditMessageID: callback.MessageID,
+			Text:          fmt.Sprintf("%s\n\nВсе пункты выполнены, я пометил напоминание как выполненное %s", reminder.FormatNotify(), domain.EmojiWhiteHeavyCheckMark),
+		})
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrChecklistItemNotFound):
+			return b.responseSender.SendBotResponse(ctx, notFound)
+		default:
+			return err
+		}
+	}
+
+	b.responseSender.Wakeup()
+
+	return nil
+}
+
+func (b *Bot) onEditReminderButton(ctx context.Context, callback domain.TgCallbackQuery) error {
+	return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SaveBotState(ctx, domain.BotState{UserID: callback.UserID, Name: domain.BotStateNameEditReminder}, msg)
+	}, sender.BotResponse{
 		ChatID: callback.ChatID,
 		Text:   fmt.Sprintf("Напишите номер %s напоминания для редактирования.", domain.EmojiKeycapHash),
 	})
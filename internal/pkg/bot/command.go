@@ -2,10 +2,19 @@ package bot
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+
+	"github.com/mezk/tg-reminder/internal/pkg/delivery"
 	"github.com/mezk/tg-reminder/internal/pkg/domain"
 	"github.com/mezk/tg-reminder/internal/pkg/sender"
 	"github.com/mezk/tg-reminder/internal/pkg/storage"
@@ -18,65 +27,49 @@ func (b *Bot) onStartCommand(ctx context.Context, message domain.TgMessage) erro
 		Status: domain.UserStatusActive,
 	}
 
-	if err := b.store.SaveUser(ctx, user); err != nil {
-		switch {
-		case errors.Is(err, storage.ErrUserAlreadyExists):
-			// client already registered
-			if err = b.store.SaveBotState(ctx, domain.BotState{UserID: message.UserID, Name: domain.BotStateNameStart}); err != nil {
-				return err
-			}
-			return b.responseSender.SendBotResponse(sender.BotResponse{
-				ChatID: message.ChatID,
-				Text:   fmt.Sprintf("@%s, ранее мы уже начали общение, предлагаю продолжить %s", user.Name, domain.EmojiWavingHand),
-			})
-		default:
-			return err
-		}
-	}
-
-	if err := b.store.SaveBotState(ctx, domain.BotState{UserID: message.UserID, Name: domain.BotStateNameStart}); err != nil {
-		return err
-	}
+	start := domain.BotState{UserID: message.UserID, Name: domain.BotStateNameStart}
 
-	return b.responseSender.SendBotResponse(sender.BotResponse{
+	err := b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SaveUser(ctx, user, msg)
+	}, sender.BotResponse{
 		ChatID: message.ChatID,
 		Text:   fmt.Sprintf("*Привет,* @%s %s\n\nТеперь вы можете со мной работать.\nДля справки %s используйте команду %s", user.Name, domain.EmojiWavingHand, domain.EmojiPersonTippingHand, domain.BotCommandHelp.Markdown()),
 	})
+	switch {
+	case errors.Is(err, storage.ErrUserAlreadyExists):
+		// client already registered
+		return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+			return b.store.SaveBotState(ctx, start, msg)
+		}, sender.BotResponse{
+			ChatID: message.ChatID,
+			Text:   fmt.Sprintf("@%s, ранее мы уже начали общение, предлагаю продолжить %s", user.Name, domain.EmojiWavingHand),
+		})
+	case err != nil:
+		return err
+	}
+
+	return b.store.SaveBotState(ctx, start)
 }
 
 func (b *Bot) onHelpCommand(ctx context.Context, message domain.TgMessage) error {
-	if err := b.store.SaveBotState(ctx, domain.BotState{UserID: message.UserID, Name: domain.BotStateNameHelp}); err != nil {
-		return err
+	var sb strings.Builder
+	sb.WriteString("\n*Список доступных команд*")
+	for _, c := range domain.BotCommandsFor(domain.BotCommandAccessUser, true) {
+		sb.WriteString(fmt.Sprintf("\n\t• %s — %s %s", c.Command.Markdown(), c.Description(domain.LanguageDefault), c.Emoji))
 	}
 
-	var helpMsg = fmt.Sprintf(`
-*Список доступных команд*
-	• %s — cправка %s
-	• %s — начать работу с ботом %s
-	• %s — создать напоминание %s
-	• %s — включить напоминания %s
-	• %s — выключить напоминания %s
-	• %s — мои напоминания %s`,
-		domain.BotCommandHelp.Markdown(), domain.EmojiPersonTippingHand,
-		domain.BotCommandStart.Markdown(), domain.EmojiPlayButton,
-		domain.BotCommandCreateReminder.Markdown(), domain.EmojiMemo,
-		domain.BotCommandEnableReminders.Markdown(), domain.EmojiBell,
-		domain.BotCommandDisableReminders.Markdown(), domain.EmojiBellWithSlash,
-		domain.BotCommandMyReminders.Markdown(), domain.EmojiSpiralNotepad,
-	)
-
-	return b.responseSender.SendBotResponse(sender.BotResponse{
+	return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SaveBotState(ctx, domain.BotState{UserID: message.UserID, Name: domain.BotStateNameHelp}, msg)
+	}, sender.BotResponse{
 		ChatID: message.ChatID,
-		Text:   helpMsg,
+		Text:   sb.String(),
 	})
 }
 
 func (b *Bot) onCreateReminderCommand(ctx context.Context, message domain.TgMessage) error {
-	if err := b.store.SaveBotState(ctx, domain.BotState{UserID: message.UserID, Name: domain.BotStateNameCreateReminder}); err != nil {
-		return err
-	}
-
-	return b.responseSender.SendBotResponse(sender.BotResponse{ChatID: message.ChatID, Text: fmt.Sprintf("О чём напомнить%s", domain.EmojiQuestionMark)})
+	return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SaveBotState(ctx, domain.BotState{UserID: message.UserID, Name: domain.BotStateNameCreateReminder}, msg)
+	}, sender.BotResponse{ChatID: message.ChatID, Text: fmt.Sprintf("О чём напомнить%s", domain.EmojiQuestionMark)})
 }
 
 func (b *Bot) onMyRemindersCommand(ctx context.Context, message domain.TgMessage) error {
@@ -86,7 +79,7 @@ func (b *Bot) onMyRemindersCommand(ctx context.Context, message domain.TgMessage
 	}
 
 	if len(reminders) == 0 {
-		return b.responseSender.SendBotResponse(sender.BotResponse{
+		return b.responseSender.SendBotResponse(ctx, sender.BotResponse{
 			ChatID: message.ChatID,
 			Text:   fmt.Sprintf("*У вас нет напоминаний* %s\n\nЧтобы добавить напоминание используйте команду %s", domain.EmojiDisappointedFace, domain.BotCommandCreateReminder.Markdown()),
 		})
@@ -103,42 +96,492 @@ func (b *Bot) onMyRemindersCommand(ctx context.Context, message domain.TgMessage
 		sb.WriteString(doubleNewLine)
 	}
 
-	if err = b.store.SaveBotState(ctx, domain.BotState{UserID: message.UserID, Name: domain.BotStateNameMyReminders}); err != nil {
-		return err
-	}
-
-	return b.responseSender.SendBotResponse(sender.BotResponse{
+	return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SaveBotState(ctx, domain.BotState{UserID: message.UserID, Name: domain.BotStateNameMyReminders}, msg)
+	}, sender.BotResponse{
 		ChatID: message.ChatID,
 		Text:   sb.String(),
 	}, sender.WithMyRemindersListEditButtons())
 }
 
 func (b *Bot) onEnableRemindersCommand(ctx context.Context, message domain.TgMessage) error {
-	if err := b.store.SetUserStatus(ctx, message.UserID, domain.UserStatusActive); err != nil {
+	// reminders missed while reminders were disabled are caught up before notifier is able to send them
+	if err := b.notifier.CatchUp(ctx, message.UserID); err != nil {
 		return err
 	}
 
-	if err := b.store.SaveBotState(ctx, domain.BotState{UserID: message.UserID, Name: domain.BotStateNameEnableReminders}); err != nil {
+	err := b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SetUserStatus(ctx, message.UserID, domain.UserStatusActive, msg)
+	}, sender.BotResponse{
+		ChatID: message.ChatID,
+		Text:   fmt.Sprintf("*Уведомления включены* %s\n\nДля отключения уведомлений используйте команду %s", domain.EmojiBell, domain.BotCommandDisableReminders.Markdown()),
+	})
+	if err != nil {
 		return err
 	}
 
-	return b.responseSender.SendBotResponse(sender.BotResponse{
+	return b.store.SaveBotState(ctx, domain.BotState{UserID: message.UserID, Name: domain.BotStateNameEnableReminders})
+}
+
+func (b *Bot) onDisableRemindersCommand(ctx context.Context, message domain.TgMessage) error {
+	err := b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SetUserStatus(ctx, message.UserID, domain.UserStatusInactive, msg)
+	}, sender.BotResponse{
 		ChatID: message.ChatID,
-		Text:   fmt.Sprintf("*Уведомления включены* %s\n\nДля отключения уведомлений используйте команду %s", domain.EmojiBell, domain.BotCommandDisableReminders.Markdown()),
+		Text:   fmt.Sprintf("*Уведомления отключены* %s\n\nДля включения уведомлений воспользуйтесь командой %s", domain.EmojiBellWithSlash, domain.BotCommandEnableReminders.Markdown()),
 	})
+	if err != nil {
+		return err
+	}
+
+	return b.store.SaveBotState(ctx, domain.BotState{UserID: message.UserID, Name: domain.BotStateNameDisableReminders})
 }
 
-func (b *Bot) onDisableRemindersCommand(ctx context.Context, message domain.TgMessage) error {
-	if err := b.store.SetUserStatus(ctx, message.UserID, domain.UserStatusInactive); err != nil {
+// settingsUsage - instruction how to change quick time and snooze buttons and notification channels.
+var settingsUsage = fmt.Sprintf(`Чтобы изменить кнопки, отправьте:
+	• %[1]s quick 09:00, 30m, 2h, tomorrow\_morning
+	• %[1]s snooze 15m, 1h, 1d, next\_monday
+	• %[1]s reset — вернуть кнопки по умолчанию
+
+Кнопка может быть временем суток (11:30), длительностью (30m, 3h, 2d, 1w) или одним из вариантов: tomorrow\_morning, tomorrow\_evening, next\_monday, weekend. Не больше %[2]d кнопок в наборе.
+
+Чтобы получать напоминания не только в Telegram, отправьте:
+	• %[1]s email user@example.com — на адрес придёт код подтверждения
+	• %[1]s verify 123456 — подтвердить адрес кодом из письма
+	• %[1]s webhook https://example.com/hook — в ответ придёт новый секрет подписи запросов
+	• %[1]s push my\_topic — тема на push-сервере
+	• %[1]s channels telegram, email, webhook, push — каналы по умолчанию
+
+Команда без адреса удаляет адрес канала.`,
+	domain.BotCommandSettings.Markdown(), domain.MaxQuickOptions,
+)
+
+func (b *Bot) onSettingsCommand(ctx context.Context, message domain.TgMessage) error {
+	settings, err := b.store.GetUserSettings(ctx, message.UserID)
+	if err != nil {
 		return err
 	}
 
-	if err := b.store.SaveBotState(ctx, domain.BotState{UserID: message.UserID, Name: domain.BotStateNameDisableReminders}); err != nil {
+	state := domain.BotState{UserID: message.UserID, Name: domain.BotStateNameSettings}
+
+	args := message.CommandArgs()
+	if args == "" {
+		return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+			return b.store.SaveBotState(ctx, state, msg)
+		}, settingsResponse(message.ChatID, settings, ""))
+	}
+
+	if err = b.store.SaveBotState(ctx, state); err != nil {
 		return err
 	}
 
-	return b.responseSender.SendBotResponse(sender.BotResponse{
+	return b.changeSettings(ctx, message, settings, args)
+}
+
+// changeSettings changes one setting described by args, e.g. "quick 09:00, 30m", and sends updated settings.
+func (b *Bot) changeSettings(ctx context.Context, message domain.TgMessage, settings domain.UserSettings, args string) (err error) {
+	name, value, _ := strings.Cut(strings.TrimSpace(args), " ")
+	switch strings.ToLower(name) {
+	case "quick":
+		settings.QuickOptions, err = domain.ParseQuickOptions(value)
+	case "snooze":
+		settings.SnoozeOptions, err = domain.ParseQuickOptions(value)
+	case "reset":
+		settings.QuickOptions, settings.SnoozeOptions = nil, nil
+	case "channels":
+		settings.Channels, err = domain.ParseChannels(value)
+	case string(domain.ChannelEmail), string(domain.ChannelWebhook), string(domain.ChannelPush):
+		err = settings.SetChannelAddress(domain.Channel(strings.ToLower(name)), strings.TrimSpace(value))
+	case "verify":
+		return b.verifyEmail(ctx, message, settings, strings.TrimSpace(value))
+	default:
+		err = fmt.Errorf("unknown setting %q", name)
+	}
+
+	if err != nil {
+		logging.Printf(ctx, "[WARN] failed to parse settings from %s: %s", message.Text, err)
+
+		return b.responseSender.SendBotResponse(ctx, sender.BotResponse{
+			ChatID: message.ChatID,
+			Text:   fmt.Sprintf("%s Не удалось понять настройки из запроса.\n\n%s", domain.EmojiThinkingFace, settingsUsage),
+		})
+	}
+
+	if settings.Email != "" && !settings.EmailVerified && strings.EqualFold(name, string(domain.ChannelEmail)) {
+		return b.sendEmailCode(ctx, message, settings)
+	}
+
+	if settings.WebhookURL != "" && strings.EqualFold(name, string(domain.ChannelWebhook)) {
+		return b.sendWebhookSecret(ctx, message, settings)
+	}
+
+	return b.saveUserSettings(ctx, settings, settingsResponse(message.ChatID, settings, fmt.Sprintf("Настройки сохранены %s\n\n", domain.EmojiWhiteHeavyCheckMark)))
+}
+
+// saveUserSettings saves settings of user along with the response.
+func (b *Bot) saveUserSettings(ctx context.Context, settings domain.UserSettings, resp sender.BotResponse, opts ...sender.BotResponseOption) error {
+	return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SaveUserSettings(ctx, settings, msg)
+	}, resp, opts...)
+}
+
+// sendEmailCode saves unverified email of user and sends code to it. Email is used for notifications only
+// after user sends the code back, so the bot can't be used to send emails to addresses of other people.
+func (b *Bot) sendEmailCode(ctx context.Context, message domain.TgMessage, settings domain.UserSettings) error {
+	if !slices.Contains(b.cfg.Channels, domain.ChannelEmail) {
+		return b.saveUserSettings(ctx, settings, settingsResponse(message.ChatID, settings, fmt.Sprintf("Настройки сохранены, но отправка почты не настроена на сервере, "+
+			"адрес не может быть подтверждён %s\n\n", domain.EmojiCrossMark)))
+	}
+
+	code, err := newEmailCode()
+	if err != nil {
+		return fmt.Errorf("failed to generate email verification code: %w", err)
+	}
+	settings.EmailCode = code
+
+	payload, err := json.Marshal(domain.NewEmailVerification(message.UserID, settings.Email, code))
+	if err != nil {
+		return fmt.Errorf("failed to marshal email verification: %w", err)
+	}
+
+	// code is erased from outbox if it isn't delivered
+	email := domain.OutboxMessage{ChatID: message.ChatID, Channel: domain.ChannelEmail, Payload: string(payload), Secret: true}
+
+	return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SaveEmailVerification(ctx, settings, email, msg)
+	}, settingsResponse(message.ChatID, settings, fmt.Sprintf("На адрес `%s` отправлен код подтверждения %s\n\n"+
+		"Чтобы получать напоминания на почту, отправьте %s verify <код>\n\n", settings.Email, domain.EmojiKey, domain.BotCommandSettings.Markdown())))
+}
+
+// sendWebhookSecret saves webhook URL of user with new secret and sends the secret. Secret is shown once,
+// so setting the same URL again is the way to get a new one.
+func (b *Bot) sendWebhookSecret(ctx context.Context, message domain.TgMessage, settings domain.UserSettings) (err error) {
+	if settings.WebhookSecret, err = newWebhookSecret(); err != nil {
+		return fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	return b.saveUserSettings(ctx, settings, settingsResponse(message.ChatID, settings, fmt.Sprintf("Настройки сохранены %s\n\nСекрет для проверки подписи вебхука:\n`%s`\n\n"+
+		"Подпись передаётся в заголовке %s как sha256=<hex> от строки \"<%s>.<тело запроса>\". "+
+		"Секрет показывается один раз, сохраните его.\n\n",
+		domain.EmojiWhiteHeavyCheckMark, settings.WebhookSecret, delivery.HeaderSignature, delivery.HeaderTimestamp)), sender.WithSecret())
+}
+
+var newEmailCode = func() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// verifyEmail marks email of user verified if code matches the code sent to it.
+func (b *Bot) verifyEmail(ctx context.Context, message domain.TgMessage, settings domain.UserSettings, code string) error {
+	verifyErr := settings.VerifyEmail(code)
+
+	// wrong attempt resets the code too
+	if verifyErr != nil {
+		logging.Printf(ctx, "[WARN] user %d failed to verify email: %v", message.UserID, verifyErr)
+
+		return b.saveUserSettings(ctx, settings, sender.BotResponse{
+			ChatID: message.ChatID,
+			Text: fmt.Sprintf("Неверный код подтверждения %s\n\nЧтобы получить новый код, отправьте %s email <адрес> ещё раз.",
+				domain.EmojiCrossMark, domain.BotCommandSettings.Markdown()),
+		})
+	}
+
+	return b.saveUserSettings(ctx, settings, settingsResponse(message.ChatID, settings, fmt.Sprintf("Адрес `%s` подтверждён %s\n\n", settings.Email, domain.EmojiWhiteHeavyCheckMark)))
+}
+
+// formatChannels formats default channels of user and addresses of channels.
+func formatChannels(settings domain.UserSettings) string {
+	var sb strings.Builder
+	sb.WriteString("*Каналы уведомлений*\n\nПо умолчанию: *")
+	sb.WriteString(settings.DefaultChannels().Format())
+	sb.WriteString("*")
+
+	for _, ch := range domain.AllChannels {
+		if ch == domain.ChannelTelegram {
+			continue
+		}
+
+		address := "не задан"
+		if a := settings.ChannelAddress(ch); a != "" {
+			address = "`" + a + "`"
+		} else if ch == domain.ChannelEmail && settings.Email != "" {
+			address = "`" + settings.Email + "` (не подтверждён)"
+		} else if ch == domain.ChannelWebhook && settings.WebhookURL != "" {
+			address = "`" + settings.WebhookURL + "` (нет секрета)"
+		}
+		sb.WriteString(fmt.Sprintf("\n%s: %s", ch.Label(), address))
+	}
+
+	return sb.String()
+}
+
+// settingsResponse returns settings of user with note about the latest change.
+func settingsResponse(chatID int64, settings domain.UserSettings, note string) sender.BotResponse {
+	return sender.BotResponse{
+		ChatID: chatID,
+		Text: fmt.Sprintf("%s*Настройки кнопок* %s\n\nБыстрый выбор времени: *%s*\nОтложить напоминание: *%s*\n\n%s\n\n%s",
+			note,
+			domain.EmojiGear,
+			settings.ReminderQuickOptions().Format(),
+			settings.ReminderSnoozeOptions().Format(),
+			formatChannels(settings),
+			settingsUsage,
+		),
+	}
+}
+
+func (b *Bot) onAPITokenCommand(ctx context.Context, message domain.TgMessage) error {
+	token, err := newAPIToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate api token: %w", err)
+	}
+
+	apiToken := domain.APIToken{TokenHash: domain.HashAPIToken(token), UserID: message.UserID, ChatID: message.ChatID}
+	return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SaveAPIToken(ctx, apiToken, msg)
+	}, sender.BotResponse{
 		ChatID: message.ChatID,
-		Text:   fmt.Sprintf("*Уведомления отключены* %s\n\nДля включения уведомлений воспользуйтесь командой %s", domain.EmojiBellWithSlash, domain.BotCommandEnableReminders.Markdown()),
+		Text: fmt.Sprintf("*Токен для API создан* %s\n\n`%s`\n\nТокен показывается один раз, сохраните его. "+
+			"Предыдущий токен больше не действует.", domain.EmojiKey, token),
+	}, sender.WithSecret())
+}
+
+var newAPIToken = func() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return domain.APITokenPrefix + hex.EncodeToString(b), nil
+}
+
+func (b *Bot) onHookTokenCommand(ctx context.Context, message domain.TgMessage) error {
+	token, err := newHookToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate hook token: %w", err)
+	}
+
+	hookToken := domain.APIToken{
+		TokenHash: domain.HashAPIToken(token),
+		Kind:      domain.APITokenKindHook,
+		UserID:    message.UserID,
+		ChatID:    message.ChatID,
+	}
+	return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SaveAPIToken(ctx, hookToken, msg)
+	}, sender.BotResponse{
+		ChatID: message.ChatID,
+		Text: fmt.Sprintf("*Токен входящего вебхука создан* %s\n\n`%s`\n\n"+
+			"Чтобы создать напоминание, отправьте POST-запрос на `/api/v1/hooks/<токен>` с JSON "+
+			"`{\"text\": \"Обновить сертификат\", \"when\": \"через 30 дней\"}`. "+
+			"Время понимается так же, как в сообщениях боту.\n\n"+
+			"Токен позволяет только создавать напоминания и показывается один раз, сохраните его. "+
+			"Предыдущий токен больше не действует.", domain.EmojiHook, token),
+	}, sender.WithSecret())
+}
+
+var newHookToken = func() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return domain.HookTokenPrefix + hex.EncodeToString(b), nil
+}
+
+func (b *Bot) onWebCommand(ctx context.Context, message domain.TgMessage) error {
+	if b.cfg.WebURL == "" {
+		return b.responseSender.SendBotResponse(ctx, sender.BotResponse{
+			ChatID: message.ChatID,
+			Text:   "Веб-интерфейс не включён " + domain.EmojiCrossMark,
+		})
+	}
+
+	token, err := newWebToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate web login token: %w", err)
+	}
+
+	login := domain.WebSession{
+		TokenHash: domain.HashAPIToken(token),
+		Kind:      domain.WebSessionKindLogin,
+		UserID:    message.UserID,
+		ChatID:    message.ChatID,
+		ExpiresAt: timeNowUTC().Add(domain.WebLoginTTL),
+	}
+	return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SaveWebSession(ctx, login, msg)
+	}, sender.BotResponse{
+		ChatID: message.ChatID,
+		Text: fmt.Sprintf("*Вход в веб-интерфейс* %s\n\n[Открыть напоминания](%s/login?token=%s)\n\n"+
+			"Ссылка одноразовая, срок действия — %s", domain.EmojiGlobe, strings.TrimSuffix(b.cfg.WebURL, "/"), token, domain.FormatDuration(domain.WebLoginTTL)),
+	}, sender.WithSecret())
+}
+
+var newWebToken = func() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// webhookLogSize - number of the latest deliveries shown in delivery log of webhook.
+const webhookLogSize = 10
+
+var webhooksUsage = fmt.Sprintf(`Вебхук получает события напоминаний POST-запросом с JSON, подписанным HMAC-SHA256 секретом вебхука:
+	• %[1]s add https://example.com/hook — все события
+	• %[1]s add https://example.com/hook completed, exhausted — только выбранные события
+	• %[1]s remove 3 — удалить вебхук
+	• %[1]s log 3 — журнал доставки
+
+События: created, fired, delayed, completed, exhausted.`,
+	domain.BotCommandWebhooks.Markdown(),
+)
+
+func (b *Bot) onWebhooksCommand(ctx context.Context, message domain.TgMessage) error {
+	args := message.CommandArgs()
+	if args == "" {
+		return b.sendWebhooks(ctx, message)
+	}
+
+	action, value, _ := strings.Cut(args, " ")
+	value = strings.TrimSpace(value)
+
+	switch strings.ToLower(action) {
+	case "add":
+		return b.addWebhook(ctx, message, value)
+	case "remove":
+		hook, err := b.userWebhook(ctx, message, value)
+		if err != nil || hook.ID == 0 {
+			return err
+		}
+
+		return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+			return b.store.RemoveWebhook(ctx, hook.ID, msg)
+		}, sender.BotResponse{
+			ChatID: message.ChatID,
+			Text:   fmt.Sprintf("Вебхук #%d удалён %s", hook.ID, domain.EmojiWhiteHeavyCheckMark),
+		})
+	case "log":
+		hook, err := b.userWebhook(ctx, message, value)
+		if err != nil || hook.ID == 0 {
+			return err
+		}
+
+		deliveries, err := b.store.GetWebhookDeliveries(ctx, hook.ID, webhookLogSize)
+		if err != nil {
+			return err
+		}
+
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("%s *Журнал доставки вебхука #%d*\n", domain.EmojiScroll, hook.ID))
+		if len(deliveries) == 0 {
+			sb.WriteString("\nСобытий ещё не было")
+		}
+		for _, d := range deliveries {
+			sb.WriteString("\n\t• ")
+			sb.WriteString(d.Format())
+		}
+
+		return b.responseSender.SendBotResponse(ctx, sender.BotResponse{ChatID: message.ChatID, Text: sb.String()})
+	default:
+		return b.sendWebhooksUsage(ctx, message.ChatID, fmt.Sprintf("unknown action %q", action), message)
+	}
+}
+
+// addWebhook registers webhook of user from args "<url> [events]", the secret of webhook is shown once.
+func (b *Bot) addWebhook(ctx context.Context, message domain.TgMessage, args string) error {
+	address, eventsArg, _ := strings.Cut(args, " ")
+
+	hook := domain.Webhook{UserID: message.UserID, URL: address}
+	err := domain.ValidateWebhookURL(address)
+	if err == nil && strings.TrimSpace(eventsArg) != "" {
+		hook.Events, err = domain.ParseWebhookEvents(eventsArg)
+	}
+	if err != nil {
+		return b.sendWebhooksUsage(ctx, message.ChatID, err.Error(), message)
+	}
+
+	if hook.Secret, err = newWebhookSecret(); err != nil {
+		return fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	// response is built from id of the saved webhook and enqueued along with it
+	_, err = b.store.SaveWebhook(ctx, hook, func(id int64) ([]domain.OutboxMessage, error) {
+		return newResponses(sender.BotResponse{
+			ChatID: message.ChatID,
+			Text: fmt.Sprintf("*Вебхук #%d добавлен* %s\n\nСобытия: %s\nСекрет для проверки подписи:\n`%s`\n\n"+
+				"Подпись передаётся в заголовке %s как sha256=<hex> от строки \"<%s>.<тело запроса>\". "+
+				"Секрет показывается один раз, сохраните его.",
+				id, domain.EmojiLink, hook.FormatEvents(), hook.Secret, delivery.HeaderSignature, delivery.HeaderTimestamp),
+		}, sender.WithSecret())
+	})
+	if err != nil {
+		return err
+	}
+
+	b.responseSender.Wakeup()
+
+	return nil
+}
+
+// userWebhook returns webhook of user by id from arg. Zero webhook is returned if it's not found,
+// user is notified about it.
+func (b *Bot) userWebhook(ctx context.Context, message domain.TgMessage, arg string) (domain.Webhook, error) {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return domain.Webhook{}, b.sendWebhooksUsage(ctx, message.ChatID, fmt.Sprintf("invalid webhook id %q", arg), message)
+	}
+
+	hook, err := b.store.GetWebhook(ctx, id)
+	if err != nil && !errors.Is(err, storage.ErrWebhookNotFound) {
+		return domain.Webhook{}, err
+	}
+
+	// webhooks of other users and of all users are not shown
+	if errors.Is(err, storage.ErrWebhookNotFound) || hook.UserID != message.UserID {
+		return domain.Webhook{}, b.responseSender.SendBotResponse(ctx, sender.BotResponse{
+			ChatID: message.ChatID,
+			Text:   fmt.Sprintf("Вебхук #%d не найден %s", id, domain.EmojiCrossMark),
+		})
+	}
+
+	return hook, nil
+}
+
+func (b *Bot) sendWebhooks(ctx context.Context, message domain.TgMessage) error {
+	hooks, err := b.store.GetUserWebhooks(ctx, message.UserID)
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("*Вебхуки* %s\n", domain.EmojiLink))
+	if len(hooks) == 0 {
+		sb.WriteString("\nВебхуков пока нет")
+	}
+	for _, hook := range hooks {
+		sb.WriteString(fmt.Sprintf("\n\t• #%d `%s` — %s", hook.ID, hook.URL, hook.FormatEvents()))
+	}
+	sb.WriteString("\n\n")
+	sb.WriteString(webhooksUsage)
+
+	return b.responseSender.SendBotResponse(ctx, sender.BotResponse{ChatID: message.ChatID, Text: sb.String()})
+}
+
+func (b *Bot) sendWebhooksUsage(ctx context.Context, chatID int64, reason string, message domain.TgMessage) error {
+	logging.Printf(ctx, "[WARN] failed to parse webhooks command %s: %s", message.Text, reason)
+
+	return b.responseSender.SendBotResponse(ctx, sender.BotResponse{
+		ChatID: chatID,
+		Text:   fmt.Sprintf("%s Не удалось понять команду.\n\n%s", domain.EmojiThinkingFace, webhooksUsage),
 	})
 }
+
+var newWebhookSecret = domain.NewWebhookSecret
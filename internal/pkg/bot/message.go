@@ -5,9 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
-	log "github.com/go-pkgz/lgr"
 	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
 	"github.com/mezk/tg-reminder/internal/pkg/sender"
 	"github.com/mezk/tg-reminder/internal/pkg/storage"
 )
@@ -26,41 +27,138 @@ const enterRemindAtFormats = `*Вы можете использовать сле
 
 *Введите дату и время напоминания или выберите опцию ниже:*`
 
+const enterRemindAtPriority = `*Приоритет* можно выбрать кнопками ниже или указать при вводе текста: "!" — высокий, "!!" — критический.`
+
 func (b *Bot) onEnterReminderTextUserMessage(ctx context.Context, message domain.TgMessage) error {
 	state := domain.BotState{
 		UserID: message.UserID,
 		Name:   domain.BotStateNameEnterReminAt,
 	}
-	state.SetReminderText(message.Text)
 
-	if err := b.store.SaveBotState(ctx, state); err != nil {
+	priority, text := domain.SplitReminderPriority(message.Text)
+	title, checklist := domain.SplitChecklist(text)
+	state.SetReminderText(title)
+	if priority != domain.ReminderPriorityNormal {
+		state.SetReminderPriority(priority)
+	}
+	if len(checklist) > 0 {
+		state.SetReminderChecklist(checklist)
+	}
+
+	return b.saveEnterRemindAt(ctx, state, message.ChatID)
+}
+
+// saveEnterRemindAt saves state of bot, which waits for remindAt of reminder, along with the request to enter it.
+func (b *Bot) saveEnterRemindAt(ctx context.Context, state domain.BotState, chatID int64) error {
+	settings, err := b.store.GetUserSettings(ctx, state.UserID)
+	if err != nil {
 		return err
 	}
 
-	text := fmt.Sprintf("*Когда напомнить %s\n\n*Текущая дата и время (Москва)%s%s\n*%s*\n\n%s",
+	priority := state.ReminderPriority()
+
+	var priorityText string
+	if priority != domain.ReminderPriorityNormal {
+		priorityText = fmt.Sprintf("*%s приоритет*\n\n", priority.Label())
+	}
+
+	text := fmt.Sprintf("%s*Когда напомнить %s\n\n*Текущая дата и время (Москва)%s%s\n*%s*\n\n%s\n\n%s",
+		priorityText,
 		domain.EmojiQuestionMark,
 		domain.NoBreakSpace, domain.EmojiAlarmClock,
 		domain.MoscowTime(timeNowUTC()).Format(domain.LayoutRemindAt),
+		enterRemindAtPriority,
 		enterRemindAtFormats,
 	)
 
-	return b.responseSender.SendBotResponse(sender.BotResponse{ChatID: message.ChatID, Text: text}, sender.WithReminderDatesButtons())
+	return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SaveBotState(ctx, state, msg)
+	}, sender.BotResponse{ChatID: chatID, Text: text}, sender.WithReminderDatesButtons(settings.QuickOptions))
 }
 
 func (b *Bot) onEnterRemindAtUserMessage(ctx context.Context, message domain.TgMessage) error {
-	remindAt, err := message.RemindAt(timeNowUTC())
+	now := timeNowUTC()
+
+	remindAt, err := message.RemindAt(now)
 	if err != nil {
-		log.Printf("[WARN] failed to parse remindAt from %s: %s", message.Text, err)
+		logging.Printf(ctx, "[WARN] failed to parse remindAt from %s: %s", message.Text, err)
+
+		settings, err := b.store.GetUserSettings(ctx, message.UserID)
+		if err != nil {
+			return err
+		}
 
 		text := fmt.Sprintf("%s Не удалось понять время из запроса, пожалуйста, попытайтесь его изменить. Время должно быть в будущем.\n\n%s",
 			domain.EmojiThinkingFace,
 			enterRemindAtFormats,
 		)
 
-		return b.responseSender.SendBotResponse(sender.BotResponse{ChatID: message.ChatID, Text: text}, sender.WithReminderDatesButtons())
+		return b.responseSender.SendBotResponse(ctx, sender.BotResponse{ChatID: message.ChatID, Text: text}, sender.WithReminderDatesButtons(settings.QuickOptions))
 	}
 
-	return b.createReminder(ctx, message.UserID, message.ChatID, remindAt)
+	correctedRemindAt, corrected, err := domain.CorrectRemindAt(remindAt, now)
+	if err != nil {
+		if errors.Is(err, domain.ErrRemindAtInPast) {
+			return b.sendRemindAtInPast(ctx, message.UserID, message.ChatID, remindAt)
+		}
+		return err
+	}
+	remindAt = correctedRemindAt
+
+	state, err := b.store.GetBotState(ctx, message.UserID)
+	if err != nil {
+		return err
+	}
+
+	// wait for user to confirm parsed remindAt
+	state.Name = domain.BotStateNameConfirmRemindAt
+	state.SetReminderRemindAt(remindAt)
+
+	var note string
+	if corrected {
+		note = fmt.Sprintf("Сегодня это время уже прошло, поэтому я перенёс напоминание на завтра %s\n\n", domain.EmojiCounterclockwiseArrowsButton)
+	}
+
+	return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SaveBotState(ctx, state, msg)
+	}, remindAtPreview(sender.BotResponse{ChatID: message.ChatID}, state, note), sender.WithRemindAtConfirmButtons())
+}
+
+// remindAtPreview returns parsed remindAt for user to confirm it. Response with EditMessageID updates existing preview.
+func remindAtPreview(resp sender.BotResponse, state domain.BotState, note string) sender.BotResponse {
+	remindAt, _ := state.ReminderRemindAt()
+
+	resp.Text = fmt.Sprintf("%s*Проверьте время напоминания*\n\nНапомню о *%s*\n%s *%s*\n\nЕсли всё верно, нажмите «Подтвердить». Время можно сдвинуть кнопками ниже.",
+		note,
+		state.ReminderText(),
+		domain.EmojiAlarmClock, domain.FormatRemindAt(remindAt, timeNowUTC()),
+	)
+
+	return resp
+}
+
+func (b *Bot) sendRemindAtInPast(ctx context.Context, userID, chatID int64, remindAt time.Time) error {
+	resp, opt, err := b.remindAtInPastResponse(ctx, userID, chatID, remindAt)
+	if err != nil {
+		return err
+	}
+
+	return b.responseSender.SendBotResponse(ctx, resp, opt)
+}
+
+// remindAtInPastResponse returns request to enter remindAt again, because the entered one has passed.
+func (b *Bot) remindAtInPastResponse(ctx context.Context, userID, chatID int64, remindAt time.Time) (sender.BotResponse, sender.BotResponseOption, error) {
+	settings, err := b.store.GetUserSettings(ctx, userID)
+	if err != nil {
+		return sender.BotResponse{}, nil, err
+	}
+
+	text := fmt.Sprintf("%s Время *%s* уже прошло, а напоминание должно быть в будущем. Пожалуйста, введите другое время или выберите опцию ниже.",
+		domain.EmojiThinkingFace,
+		domain.MoscowTime(remindAt).Format(domain.LayoutRemindAt),
+	)
+
+	return sender.BotResponse{ChatID: chatID, Text: text}, sender.WithReminderDatesButtons(settings.QuickOptions), nil
 }
 
 func (b *Bot) onRemoveReminderUserMessage(ctx context.Context, message domain.TgMessage) error {
@@ -69,21 +167,83 @@ func (b *Bot) onRemoveReminderUserMessage(ctx context.Context, message domain.Tg
 		return fmt.Errorf("failed to parse reminder id %s: %w", message.Text, err)
 	}
 
-	responseMsg := fmt.Sprintf("Напоминание %d удалено %s", reminderID, domain.EmojiCrossMark)
+	return b.saveWithStartState(ctx, message, func(msg domain.OutboxMessage) error {
+		return b.store.RemoveReminder(ctx, reminderID, msg)
+	}, fmt.Sprintf("Напоминание %d удалено %s", reminderID, domain.EmojiCrossMark), reminderID)
+}
+
+func (b *Bot) onReminderHistoryUserMessage(ctx context.Context, message domain.TgMessage) error {
+	reminderID, err := strconv.ParseInt(message.Text, 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse reminder id %s: %w", message.Text, err)
+	}
 
-	if err = b.store.RemoveReminder(ctx, reminderID); err != nil {
-		switch {
-		case errors.Is(err, storage.ErrReminderNotFound):
-			responseMsg = fmt.Sprintf("Напоминание %d не найдено %s", reminderID, domain.EmojiThinkingFace)
-		default:
+	responseMsg := fmt.Sprintf("Напоминание %d не найдено %s", reminderID, domain.EmojiThinkingFace)
+
+	reminder, err := b.store.GetReminder(ctx, reminderID)
+	switch {
+	case errors.Is(err, storage.ErrReminderNotFound):
+	case err != nil:
+		return err
+	case reminder.UserID == message.UserID: // history of reminders of other users isn't shown
+		var events []domain.ReminderEvent
+		if events, err = b.store.GetReminderEvents(ctx, reminderID); err != nil {
 			return err
 		}
+		responseMsg = domain.FormatHistory(reminder, events, false)
 	}
 
 	// go to start state
-	if err = b.store.SaveBotState(ctx, domain.BotState{UserID: message.UserID, Name: domain.BotStateNameStart}); err != nil {
+	return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+		return b.store.SaveBotState(ctx, domain.BotState{UserID: message.UserID, Name: domain.BotStateNameStart}, msg)
+	}, sender.BotResponse{ChatID: message.ChatID, Text: responseMsg})
+}
+
+func (b *Bot) onEnterChecklistItemsUserMessage(ctx context.Context, message domain.TgMessage) error {
+	state, err := b.store.GetBotState(ctx, message.UserID)
+	if err != nil {
+		return err
+	}
+
+	reminderID := state.ReminderID()
+	items := domain.ParseChecklistItems(message.Text)
+	if len(items) == 0 {
+		// stay in the state until user sends items
+		return b.responseSender.SendBotResponse(ctx, sender.BotResponse{ChatID: message.ChatID, Text: checklistItemsUsage})
+	}
+
+	return b.saveWithStartState(ctx, message, func(msg domain.OutboxMessage) error {
+		return b.store.AddChecklistItems(ctx, reminderID, items, msg)
+	}, fmt.Sprintf("Добавлено пунктов: %d %s", len(items), domain.EmojiWhiteHeavyCheckMark), reminderID)
+}
+
+// saveWithStartState saves a change of reminder along with response text and brings bot to start state.
+// If the reminder isn't found, bot goes to start state along with the response about it.
+func (b *Bot) saveWithStartState(ctx context.Context, message domain.TgMessage, save func(msg domain.OutboxMessage) error,
+	responseMsg string, reminderID int64) error {
+	start := domain.BotState{UserID: message.UserID, Name: domain.BotStateNameStart}
+
+	err := b.saveWithResponse(save, sender.BotResponse{ChatID: message.ChatID, Text: responseMsg})
+	switch {
+	case errors.Is(err, storage.ErrReminderNotFound):
+		return b.saveWithResponse(func(msg domain.OutboxMessage) error {
+			return b.store.SaveBotState(ctx, start, msg)
+		}, sender.BotResponse{ChatID: message.ChatID, Text: fmt.Sprintf("Напоминание %d не найдено %s", reminderID, domain.EmojiThinkingFace)})
+	case err != nil:
+		return err
+	}
+
+	// go to start state
+	return b.store.SaveBotState(ctx, start)
+}
+
+// onSettingsUserMessage changes settings sent after /settings command without "/settings" prefix.
+func (b *Bot) onSettingsUserMessage(ctx context.Context, message domain.TgMessage) error {
+	settings, err := b.store.GetUserSettings(ctx, message.UserID)
+	if err != nil {
 		return err
 	}
 
-	return b.responseSender.SendBotResponse(sender.BotResponse{ChatID: message.ChatID, Text: responseMsg})
+	// bot stays in settings state, so user is able to change several settings one by one
+	return b.changeSettings(ctx, message, settings, message.Text)
 }
@@ -0,0 +1,95 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package bot
+
+import (
+	"context"
+	"sync"
+)
+
+// Ensure, that NotifierMock does implement Notifier.
+// If this is not the case, regenerate this file with moq.
+var _ Notifier = &NotifierMock{}
+
+// NotifierMock is a mock implementation of Notifier.
+//
+//	func TestSomethingThatUsesNotifier(t *testing.T) {
+//
+//		// make and configure a mocked Notifier
+//		mockedNotifier := &NotifierMock{
+//			CatchUpFunc: func(ctx context.Context, userID int64) error {
+//				panic("mock out the CatchUp method")
+//			},
+//		}
+//
+//		// use mockedNotifier in code that requires Notifier
+//		// and then make assertions.
+//
+//	}
+type NotifierMock struct {
+	// CatchUpFunc mocks the CatchUp method.
+	CatchUpFunc func(ctx context.Context, userID int64) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// CatchUp holds details about calls to the CatchUp method.
+		CatchUp []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+		}
+	}
+	lockCatchUp sync.RWMutex
+}
+
+// CatchUp calls CatchUpFunc.
+func (mock *NotifierMock) CatchUp(ctx context.Context, userID int64) error {
+	if mock.CatchUpFunc == nil {
+		panic("NotifierMock.CatchUpFunc: method is nil but Notifier.CatchUp was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockCatchUp.Lock()
+	mock.calls.CatchUp = append(mock.calls.CatchUp, callInfo)
+	mock.lockCatchUp.Unlock()
+	return mock.CatchUpFunc(ctx, userID)
+}
+
+// CatchUpCalls gets all the calls that were made to CatchUp.
+// Check the length with:
+//
+//	len(mockedNotifier.CatchUpCalls())
+func (mock *NotifierMock) CatchUpCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+	}
+	mock.lockCatchUp.RLock()
+	calls = mock.calls.CatchUp
+	mock.lockCatchUp.RUnlock()
+	return calls
+}
+
+// ResetCatchUpCalls reset all the calls that were made to CatchUp.
+func (mock *NotifierMock) ResetCatchUpCalls() {
+	mock.lockCatchUp.Lock()
+	mock.calls.CatchUp = nil
+	mock.lockCatchUp.Unlock()
+}
+
+// ResetCalls reset all the calls that were made to all mocked methods.
+func (mock *NotifierMock) ResetCalls() {
+	mock.lockCatchUp.Lock()
+	mock.calls.CatchUp = nil
+	mock.lockCatchUp.Unlock()
+}
@@ -4,6 +4,7 @@
 package bot
 
 import (
+	"context"
 	"github.com/mezk/tg-reminder/internal/pkg/sender"
 	"sync"
 )
@@ -18,9 +19,12 @@ var _ ResponseSender = &ResponseSenderMock{}
 //
 //		// make and configure a mocked ResponseSender
 //		mockedResponseSender := &ResponseSenderMock{
-//			SendBotResponseFunc: func(response sender.BotResponse, opts ...sender.BotResponseOption) error {
+//			SendBotResponseFunc: func(ctx context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error {
 //				panic("mock out the SendBotResponse method")
 //			},
+//			WakeupFunc: func()  {
+//				panic("mock out the Wakeup method")
+//			},
 //		}
 //
 //		// use mockedResponseSender in code that requires ResponseSender
@@ -29,37 +33,48 @@ var _ ResponseSender = &ResponseSenderMock{}
 //	}
 type ResponseSenderMock struct {
 	// SendBotResponseFunc mocks the SendBotResponse method.
-	SendBotResponseFunc func(response sender.BotResponse, opts ...sender.BotResponseOption) error
+	SendBotResponseFunc func(ctx context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error
+
+	// WakeupFunc mocks the Wakeup method.
+	WakeupFunc func()
 
 	// calls tracks calls to the methods.
 	calls struct {
 		// SendBotResponse holds details about calls to the SendBotResponse method.
 		SendBotResponse []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Response is the response argument value.
 			Response sender.BotResponse
 			// Opts is the opts argument value.
 			Opts []sender.BotResponseOption
 		}
+		// Wakeup holds details about calls to the Wakeup method.
+		Wakeup []struct {
+		}
 	}
 	lockSendBotResponse sync.RWMutex
+	lockWakeup          sync.RWMutex
 }
 
 // SendBotResponse calls SendBotResponseFunc.
-func (mock *ResponseSenderMock) SendBotResponse(response sender.BotResponse, opts ...sender.BotResponseOption) error {
+func (mock *ResponseSenderMock) SendBotResponse(ctx context.Context, response sender.BotResponse, opts ...sender.BotResponseOption) error {
 	if mock.SendBotResponseFunc == nil {
 		panic("ResponseSenderMock.SendBotResponseFunc: method is nil but ResponseSender.SendBotResponse was just called")
 	}
 	callInfo := struct {
+		Ctx      context.Context
 		Response sender.BotResponse
 		Opts     []sender.BotResponseOption
 	}{
+		Ctx:      ctx,
 		Response: response,
 		Opts:     opts,
 	}
 	mock.lockSendBotResponse.Lock()
 	mock.calls.SendBotResponse = append(mock.calls.SendBotResponse, callInfo)
 	mock.lockSendBotResponse.Unlock()
-	return mock.SendBotResponseFunc(response, opts...)
+	return mock.SendBotResponseFunc(ctx, response, opts...)
 }
 
 // SendBotResponseCalls gets all the calls that were made to SendBotResponse.
@@ -67,10 +82,12 @@ func (mock *ResponseSenderMock) SendBotResponse(response sender.BotResponse, opt
 //
 //	len(mockedResponseSender.SendBotResponseCalls())
 func (mock *ResponseSenderMock) SendBotResponseCalls() []struct {
+	Ctx      context.Context
 	Response sender.BotResponse
 	Opts     []sender.BotResponseOption
 } {
 	var calls []struct {
+		Ctx      context.Context
 		Response sender.BotResponse
 		Opts     []sender.BotResponseOption
 	}
@@ -87,9 +104,47 @@ func (mock *ResponseSenderMock) ResetSendBotResponseCalls() {
 	mock.lockSendBotResponse.Unlock()
 }
 
+// Wakeup calls WakeupFunc.
+func (mock *ResponseSenderMock) Wakeup() {
+	if mock.WakeupFunc == nil {
+		panic("ResponseSenderMock.WakeupFunc: method is nil but ResponseSender.Wakeup was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockWakeup.Lock()
+	mock.calls.Wakeup = append(mock.calls.Wakeup, callInfo)
+	mock.lockWakeup.Unlock()
+	mock.WakeupFunc()
+}
+
+// WakeupCalls gets all the calls that were made to Wakeup.
+// Check the length with:
+//
+//	len(mockedResponseSender.WakeupCalls())
+func (mock *ResponseSenderMock) WakeupCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockWakeup.RLock()
+	calls = mock.calls.Wakeup
+	mock.lockWakeup.RUnlock()
+	return calls
+}
+
+// ResetWakeupCalls reset all the calls that were made to Wakeup.
+func (mock *ResponseSenderMock) ResetWakeupCalls() {
+	mock.lockWakeup.Lock()
+	mock.calls.Wakeup = nil
+	mock.lockWakeup.Unlock()
+}
+
 // ResetCalls reset all the calls that were made to all mocked methods.
 func (mock *ResponseSenderMock) ResetCalls() {
 	mock.lockSendBotResponse.Lock()
 	mock.calls.SendBotResponse = nil
 	mock.lockSendBotResponse.Unlock()
+
+	mock.lockWakeup.Lock()
+	mock.calls.Wakeup = nil
+	mock.lockWakeup.Unlock()
 }
@@ -20,33 +20,87 @@ var _ Storage = &StorageMock{}
 //
 //		// make and configure a mocked Storage
 //		mockedStorage := &StorageMock{
-//			DelayReminderFunc: func(ctx context.Context, id int64, remindAt time.Time) error {
+//			AddChecklistItemsFunc: func(ctx context.Context, reminderID int64, items []string, msgs ...domain.OutboxMessage) error {
+//				panic("mock out the AddChecklistItems method")
+//			},
+//			DelayReminderFunc: func(ctx context.Context, id int64, remindAt time.Time, msgs ...domain.OutboxMessage) error {
 //				panic("mock out the DelayReminder method")
 //			},
 //			GetBotStateFunc: func(ctx context.Context, userID int64) (domain.BotState, error) {
 //				panic("mock out the GetBotState method")
 //			},
+//			GetChecklistItemFunc: func(ctx context.Context, id int64) (domain.ChecklistItem, error) {
+//				panic("mock out the GetChecklistItem method")
+//			},
 //			GetMyRemindersFunc: func(ctx context.Context, userID int64, chatID int64) ([]domain.Reminder, error) {
 //				panic("mock out the GetMyReminders method")
 //			},
-//			RemoveReminderFunc: func(ctx context.Context, id int64) error {
+//			GetReminderFunc: func(ctx context.Context, id int64) (domain.Reminder, error) {
+//				panic("mock out the GetReminder method")
+//			},
+//			GetReminderEventsFunc: func(ctx context.Context, reminderID int64) ([]domain.ReminderEvent, error) {
+//				panic("mock out the GetReminderEvents method")
+//			},
+//			GetSummaryReminderIDsFunc: func(ctx context.Context, userID int64, chatID int64, summaryKey string) ([]int64, error) {
+//				panic("mock out the GetSummaryReminderIDs method")
+//			},
+//			GetUserSettingsFunc: func(ctx context.Context, userID int64) (domain.UserSettings, error) {
+//				panic("mock out the GetUserSettings method")
+//			},
+//			GetUserWebhooksFunc: func(ctx context.Context, userID int64) ([]domain.Webhook, error) {
+//				panic("mock out the GetUserWebhooks method")
+//			},
+//			GetWebhookFunc: func(ctx context.Context, id int64) (domain.Webhook, error) {
+//				panic("mock out the GetWebhook method")
+//			},
+//			GetWebhookDeliveriesFunc: func(ctx context.Context, webhookID int64, limit int64) ([]domain.WebhookDelivery, error) {
+//				panic("mock out the GetWebhookDeliveries method")
+//			},
+//			RemoveReminderFunc: func(ctx context.Context, id int64, msgs ...domain.OutboxMessage) error {
 //				panic("mock out the RemoveReminder method")
 //			},
-//			SaveBotStateFunc: func(ctx context.Context, state domain.BotState) error {
+//			RemoveWebhookFunc: func(ctx context.Context, id int64, msgs ...domain.OutboxMessage) error {
+//				panic("mock out the RemoveWebhook method")
+//			},
+//			RescheduleMissedRemindersFunc: func(ctx context.Context, userID int64, chatID int64, ids []int64, remindAt time.Time, newMsgs func(rescheduled int64) ([]domain.OutboxMessage, error)) (int64, error) {
+//				panic("mock out the RescheduleMissedReminders method")
+//			},
+//			SaveAPITokenFunc: func(ctx context.Context, token domain.APIToken, msgs ...domain.OutboxMessage) error {
+//				panic("mock out the SaveAPIToken method")
+//			},
+//			SaveBotStateFunc: func(ctx context.Context, state domain.BotState, msgs ...domain.OutboxMessage) error {
 //				panic("mock out the SaveBotState method")
 //			},
-//			SaveReminderFunc: func(ctx context.Context, reminder domain.Reminder) (int64, error) {
+//			SaveEmailVerificationFunc: func(ctx context.Context, settings domain.UserSettings, msg domain.OutboxMessage, msgs ...domain.OutboxMessage) error {
+//				panic("mock out the SaveEmailVerification method")
+//			},
+//			SaveReminderFunc: func(ctx context.Context, reminder domain.Reminder, newMsgs func(id int64) ([]domain.OutboxMessage, error)) (int64, error) {
 //				panic("mock out the SaveReminder method")
 //			},
-//			SaveUserFunc: func(ctx context.Context, user domain.User) error {
+//			SaveUserFunc: func(ctx context.Context, user domain.User, msgs ...domain.OutboxMessage) error {
 //				panic("mock out the SaveUser method")
 //			},
-//			SetReminderStatusFunc: func(ctx context.Context, id int64, status domain.ReminderStatus) error {
+//			SaveUserSettingsFunc: func(ctx context.Context, settings domain.UserSettings, msgs ...domain.OutboxMessage) error {
+//				panic("mock out the SaveUserSettings method")
+//			},
+//			SaveWebSessionFunc: func(ctx context.Context, session domain.WebSession, msgs ...domain.OutboxMessage) error {
+//				panic("mock out the SaveWebSession method")
+//			},
+//			SaveWebhookFunc: func(ctx context.Context, hook domain.Webhook, newMsgs func(id int64) ([]domain.OutboxMessage, error)) (int64, error) {
+//				panic("mock out the SaveWebhook method")
+//			},
+//			SetReminderLeadTimesFunc: func(ctx context.Context, id int64, leadTimes domain.LeadTimes, nextPreNoticeAt *time.Time, msgs ...domain.OutboxMessage) error {
+//				panic("mock out the SetReminderLeadTimes method")
+//			},
+//			SetReminderStatusFunc: func(ctx context.Context, id int64, status domain.ReminderStatus, msgs ...domain.OutboxMessage) error {
 //				panic("mock out the SetReminderStatus method")
 //			},
-//			SetUserStatusFunc: func(ctx context.Context, id int64, inactive domain.UserStatus) error {
+//			SetUserStatusFunc: func(ctx context.Context, id int64, inactive domain.UserStatus, msgs ...domain.OutboxMessage) error {
 //				panic("mock out the SetUserStatus method")
 //			},
+//			ToggleChecklistItemFunc: func(ctx context.Context, id int64, newMsgs func(reminder domain.Reminder) ([]domain.OutboxMessage, error)) (domain.Reminder, error) {
+//				panic("mock out the ToggleChecklistItem method")
+//			},
 //		}
 //
 //		// use mockedStorage in code that requires Storage
@@ -54,35 +108,100 @@ var _ Storage = &StorageMock{}
 //
 //	}
 type StorageMock struct {
+	// AddChecklistItemsFunc mocks the AddChecklistItems method.
+	AddChecklistItemsFunc func(ctx context.Context, reminderID int64, items []string, msgs ...domain.OutboxMessage) error
+
 	// DelayReminderFunc mocks the DelayReminder method.
-	DelayReminderFunc func(ctx context.Context, id int64, remindAt time.Time) error
+	DelayReminderFunc func(ctx context.Context, id int64, remindAt time.Time, msgs ...domain.OutboxMessage) error
 
 	// GetBotStateFunc mocks the GetBotState method.
 	GetBotStateFunc func(ctx context.Context, userID int64) (domain.BotState, error)
 
+	// GetChecklistItemFunc mocks the GetChecklistItem method.
+	GetChecklistItemFunc func(ctx context.Context, id int64) (domain.ChecklistItem, error)
+
 	// GetMyRemindersFunc mocks the GetMyReminders method.
 	GetMyRemindersFunc func(ctx context.Context, userID int64, chatID int64) ([]domain.Reminder, error)
 
+	// GetReminderFunc mocks the GetReminder method.
+	GetReminderFunc func(ctx context.Context, id int64) (domain.Reminder, error)
+
+	// GetReminderEventsFunc mocks the GetReminderEvents method.
+	GetReminderEventsFunc func(ctx context.Context, reminderID int64) ([]domain.ReminderEvent, error)
+
+	// GetSummaryReminderIDsFunc mocks the GetSummaryReminderIDs method.
+	GetSummaryReminderIDsFunc func(ctx context.Context, userID int64, chatID int64, summaryKey string) ([]int64, error)
+
+	// GetUserSettingsFunc mocks the GetUserSettings method.
+	GetUserSettingsFunc func(ctx context.Context, userID int64) (domain.UserSettings, error)
+
+	// GetUserWebhooksFunc mocks the GetUserWebhooks method.
+	GetUserWebhooksFunc func(ctx context.Context, userID int64) ([]domain.Webhook, error)
+
+	// GetWebhookFunc mocks the GetWebhook method.
+	GetWebhookFunc func(ctx context.Context, id int64) (domain.Webhook, error)
+
+	// GetWebhookDeliveriesFunc mocks the GetWebhookDeliveries method.
+	GetWebhookDeliveriesFunc func(ctx context.Context, webhookID int64, limit int64) ([]domain.WebhookDelivery, error)
+
 	// RemoveReminderFunc mocks the RemoveReminder method.
-	RemoveReminderFunc func(ctx context.Context, id int64) error
+	RemoveReminderFunc func(ctx context.Context, id int64, msgs ...domain.OutboxMessage) error
+
+	// RemoveWebhookFunc mocks the RemoveWebhook method.
+	RemoveWebhookFunc func(ctx context.Context, id int64, msgs ...domain.OutboxMessage) error
+
+	// RescheduleMissedRemindersFunc mocks the RescheduleMissedReminders method.
+	RescheduleMissedRemindersFunc func(ctx context.Context, userID int64, chatID int64, ids []int64, remindAt time.Time, newMsgs func(rescheduled int64) ([]domain.OutboxMessage, error)) (int64, error)
+
+	// SaveAPITokenFunc mocks the SaveAPIToken method.
+	SaveAPITokenFunc func(ctx context.Context, token domain.APIToken, msgs ...domain.OutboxMessage) error
 
 	// SaveBotStateFunc mocks the SaveBotState method.
-	SaveBotStateFunc func(ctx context.Context, state domain.BotState) error
+	SaveBotStateFunc func(ctx context.Context, state domain.BotState, msgs ...domain.OutboxMessage) error
+
+	// SaveEmailVerificationFunc mocks the SaveEmailVerification method.
+	SaveEmailVerificationFunc func(ctx context.Context, settings domain.UserSettings, msg domain.OutboxMessage, msgs ...domain.OutboxMessage) error
 
 	// SaveReminderFunc mocks the SaveReminder method.
-	SaveReminderFunc func(ctx context.Context, reminder domain.Reminder) (int64, error)
+	SaveReminderFunc func(ctx context.Context, reminder domain.Reminder, newMsgs func(id int64) ([]domain.OutboxMessage, error)) (int64, error)
 
 	// SaveUserFunc mocks the SaveUser method.
-	SaveUserFunc func(ctx context.Context, user domain.User) error
+	SaveUserFunc func(ctx context.Context, user domain.User, msgs ...domain.OutboxMessage) error
+
+	// SaveUserSettingsFunc mocks the SaveUserSettings method.
+	SaveUserSettingsFunc func(ctx context.Context, settings domain.UserSettings, msgs ...domain.OutboxMessage) error
+
+	// SaveWebSessionFunc mocks the SaveWebSession method.
+	SaveWebSessionFunc func(ctx context.Context, session domain.WebSession, msgs ...domain.OutboxMessage) error
+
+	// SaveWebhookFunc mocks the SaveWebhook method.
+	SaveWebhookFunc func(ctx context.Context, hook domain.Webhook, newMsgs func(id int64) ([]domain.OutboxMessage, error)) (int64, error)
+
+	// SetReminderLeadTimesFunc mocks the SetReminderLeadTimes method.
+	SetReminderLeadTimesFunc func(ctx context.Context, id int64, leadTimes domain.LeadTimes, nextPreNoticeAt *time.Time, msgs ...domain.OutboxMessage) error
 
 	// SetReminderStatusFunc mocks the SetReminderStatus method.
-	SetReminderStatusFunc func(ctx context.Context, id int64, status domain.ReminderStatus) error
+	SetReminderStatusFunc func(ctx context.Context, id int64, status domain.ReminderStatus, msgs ...domain.OutboxMessage) error
 
 	// SetUserStatusFunc mocks the SetUserStatus method.
-	SetUserStatusFunc func(ctx context.Context, id int64, inactive domain.UserStatus) error
+	SetUserStatusFunc func(ctx context.Context, id int64, inactive domain.UserStatus, msgs ...domain.OutboxMessage) error
+
+	// ToggleChecklistItemFunc mocks the ToggleChecklistItem method.
+	ToggleChecklistItemFunc func(ctx context.Context, id int64, newMsgs func(reminder domain.Reminder) ([]domain.OutboxMessage, error)) (domain.Reminder, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// AddChecklistItems holds details about calls to the AddChecklistItems method.
+		AddChecklistItems []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ReminderID is the reminderID argument value.
+			ReminderID int64
+			// Items is the items argument value.
+			Items []string
+			// Msgs is the msgs argument value.
+			Msgs []domain.OutboxMessage
+		}
 		// DelayReminder holds details about calls to the DelayReminder method.
 		DelayReminder []struct {
 			// Ctx is the ctx argument value.
@@ -91,6 +210,8 @@ type StorageMock struct {
 			ID int64
 			// RemindAt is the remindAt argument value.
 			RemindAt time.Time
+			// Msgs is the msgs argument value.
+			Msgs []domain.OutboxMessage
 		}
 		// GetBotState holds details about calls to the GetBotState method.
 		GetBotState []struct {
@@ -99,6 +220,13 @@ type StorageMock struct {
 			// UserID is the userID argument value.
 			UserID int64
 		}
+		// GetChecklistItem holds details about calls to the GetChecklistItem method.
+		GetChecklistItem []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
 		// GetMyReminders holds details about calls to the GetMyReminders method.
 		GetMyReminders []struct {
 			// Ctx is the ctx argument value.
@@ -108,12 +236,102 @@ type StorageMock struct {
 			// ChatID is the chatID argument value.
 			ChatID int64
 		}
+		// GetReminder holds details about calls to the GetReminder method.
+		GetReminder []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+		// GetReminderEvents holds details about calls to the GetReminderEvents method.
+		GetReminderEvents []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ReminderID is the reminderID argument value.
+			ReminderID int64
+		}
+		// GetSummaryReminderIDs holds details about calls to the GetSummaryReminderIDs method.
+		GetSummaryReminderIDs []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// ChatID is the chatID argument value.
+			ChatID int64
+			// SummaryKey is the summaryKey argument value.
+			SummaryKey string
+		}
+		// GetUserSettings holds details about calls to the GetUserSettings method.
+		GetUserSettings []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// GetUserWebhooks holds details about calls to the GetUserWebhooks method.
+		GetUserWebhooks []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// GetWebhook holds details about calls to the GetWebhook method.
+		GetWebhook []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+		}
+		// GetWebhookDeliveries holds details about calls to the GetWebhookDeliveries method.
+		GetWebhookDeliveries []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// WebhookID is the webhookID argument value.
+			WebhookID int64
+			// Limit is the limit argument value.
+			Limit int64
+		}
 		// RemoveReminder holds details about calls to the RemoveReminder method.
 		RemoveReminder []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
 			// ID is the id argument value.
 			ID int64
+			// Msgs is the msgs argument value.
+			Msgs []domain.OutboxMessage
+		}
+		// RemoveWebhook holds details about calls to the RemoveWebhook method.
+		RemoveWebhook []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+			// Msgs is the msgs argument value.
+			Msgs []domain.OutboxMessage
+		}
+		// RescheduleMissedReminders holds details about calls to the RescheduleMissedReminders method.
+		RescheduleMissedReminders []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// ChatID is the chatID argument value.
+			ChatID int64
+			// Ids is the ids argument value.
+			Ids []int64
+			// RemindAt is the remindAt argument value.
+			RemindAt time.Time
+			// NewMsgs is the newMsgs argument value.
+			NewMsgs func(rescheduled int64) ([]domain.OutboxMessage, error)
+		}
+		// SaveAPIToken holds details about calls to the SaveAPIToken method.
+		SaveAPIToken []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Token is the token argument value.
+			Token domain.APIToken
+			// Msgs is the msgs argument value.
+			Msgs []domain.OutboxMessage
 		}
 		// SaveBotState holds details about calls to the SaveBotState method.
 		SaveBotState []struct {
@@ -121,6 +339,19 @@ type StorageMock struct {
 			Ctx context.Context
 			// State is the state argument value.
 			State domain.BotState
+			// Msgs is the msgs argument value.
+			Msgs []domain.OutboxMessage
+		}
+		// SaveEmailVerification holds details about calls to the SaveEmailVerification method.
+		SaveEmailVerification []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Settings is the settings argument value.
+			Settings domain.UserSettings
+			// Msg is the msg argument value.
+			Msg domain.OutboxMessage
+			// Msgs is the msgs argument value.
+			Msgs []domain.OutboxMessage
 		}
 		// SaveReminder holds details about calls to the SaveReminder method.
 		SaveReminder []struct {
@@ -128,6 +359,8 @@ type StorageMock struct {
 			Ctx context.Context
 			// Reminder is the reminder argument value.
 			Reminder domain.Reminder
+			// NewMsgs is the newMsgs argument value.
+			NewMsgs func(id int64) ([]domain.OutboxMessage, error)
 		}
 		// SaveUser holds details about calls to the SaveUser method.
 		SaveUser []struct {
@@ -135,6 +368,48 @@ type StorageMock struct {
 			Ctx context.Context
 			// User is the user argument value.
 			User domain.User
+			// Msgs is the msgs argument value.
+			Msgs []domain.OutboxMessage
+		}
+		// SaveUserSettings holds details about calls to the SaveUserSettings method.
+		SaveUserSettings []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Settings is the settings argument value.
+			Settings domain.UserSettings
+			// Msgs is the msgs argument value.
+			Msgs []domain.OutboxMessage
+		}
+		// SaveWebSession holds details about calls to the SaveWebSession method.
+		SaveWebSession []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Session is the session argument value.
+			Session domain.WebSession
+			// Msgs is the msgs argument value.
+			Msgs []domain.OutboxMessage
+		}
+		// SaveWebhook holds details about calls to the SaveWebhook method.
+		SaveWebhook []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Hook is the hook argument value.
+			Hook domain.Webhook
+			// NewMsgs is the newMsgs argument value.
+			NewMsgs func(id int64) ([]domain.OutboxMessage, error)
+		}
+		// SetReminderLeadTimes holds details about calls to the SetReminderLeadTimes method.
+		SetReminderLeadTimes []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+			// LeadTimes is the leadTimes argument value.
+			LeadTimes domain.LeadTimes
+			// NextPreNoticeAt is the nextPreNoticeAt argument value.
+			NextPreNoticeAt *time.Time
+			// Msgs is the msgs argument value.
+			Msgs []domain.OutboxMessage
 		}
 		// SetReminderStatus holds details about calls to the SetReminderStatus method.
 		SetReminderStatus []struct {
@@ -144,6 +419,8 @@ type StorageMock struct {
 			ID int64
 			// Status is the status argument value.
 			Status domain.ReminderStatus
+			// Msgs is the msgs argument value.
+			Msgs []domain.OutboxMessage
 		}
 		// SetUserStatus holds details about calls to the SetUserStatus method.
 		SetUserStatus []struct {
@@ -153,21 +430,101 @@ type StorageMock struct {
 			ID int64
 			// Inactive is the inactive argument value.
 			Inactive domain.UserStatus
+			// Msgs is the msgs argument value.
+			Msgs []domain.OutboxMessage
+		}
+		// ToggleChecklistItem holds details about calls to the ToggleChecklistItem method.
+		ToggleChecklistItem []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+			// NewMsgs is the newMsgs argument value.
+			NewMsgs func(reminder domain.Reminder) ([]domain.OutboxMessage, error)
 		}
 	}
-	lockDelayReminder     sync.RWMutex
-	lockGetBotState       sync.RWMutex
-	lockGetMyReminders    sync.RWMutex
-	lockRemoveReminder    sync.RWMutex
-	lockSaveBotState      sync.RWMutex
-	lockSaveReminder      sync.RWMutex
-	lockSaveUser          sync.RWMutex
-	lockSetReminderStatus sync.RWMutex
-	lockSetUserStatus     sync.RWMutex
+	lockAddChecklistItems         sync.RWMutex
+	lockDelayReminder             sync.RWMutex
+	lockGetBotState               sync.RWMutex
+	lockGetChecklistItem          sync.RWMutex
+	lockGetMyReminders            sync.RWMutex
+	lockGetReminder               sync.RWMutex
+	lockGetReminderEvents         sync.RWMutex
+	lockGetSummaryReminderIDs     sync.RWMutex
+	lockGetUserSettings           sync.RWMutex
+	lockGetUserWebhooks           sync.RWMutex
+	lockGetWebhook                sync.RWMutex
+	lockGetWebhookDeliveries      sync.RWMutex
+	lockRemoveReminder            sync.RWMutex
+	lockRemoveWebhook             sync.RWMutex
+	lockRescheduleMissedReminders sync.RWMutex
+	lockSaveAPIToken              sync.RWMutex
+	lockSaveBotState              sync.RWMutex
+	lockSaveEmailVerification     sync.RWMutex
+	lockSaveReminder              sync.RWMutex
+	lockSaveUser                  sync.RWMutex
+	lockSaveUserSettings          sync.RWMutex
+	lockSaveWebSession            sync.RWMutex
+	lockSaveWebhook               sync.RWMutex
+	lockSetReminderLeadTimes      sync.RWMutex
+	lockSetReminderStatus         sync.RWMutex
+	lockSetUserStatus             sync.RWMutex
+	lockToggleChecklistItem       sync.RWMutex
+}
+
+// AddChecklistItems calls AddChecklistItemsFunc.
+func (mock *StorageMock) AddChecklistItems(ctx context.Context, reminderID int64, items []string, msgs ...domain.OutboxMessage) error {
+	if mock.AddChecklistItemsFunc == nil {
+		panic("StorageMock.AddChecklistItemsFunc: method is nil but Storage.AddChecklistItems was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		ReminderID int64
+		Items      []string
+		Msgs       []domain.OutboxMessage
+	}{
+		Ctx:        ctx,
+		ReminderID: reminderID,
+		Items:      items,
+		Msgs:       msgs,
+	}
+	mock.lockAddChecklistItems.Lock()
+	mock.calls.AddChecklistItems = append(mock.calls.AddChecklistItems, callInfo)
+	mock.lockAddChecklistItems.Unlock()
+	return mock.AddChecklistItemsFunc(ctx, reminderID, items, msgs...)
+}
+
+// AddChecklistItemsCalls gets all the calls that were made to AddChecklistItems.
+// Check the length with:
+//
+//	len(mockedStorage.AddChecklistItemsCalls())
+func (mock *StorageMock) AddChecklistItemsCalls() []struct {
+	Ctx        context.Context
+	ReminderID int64
+	Items      []string
+	Msgs       []domain.OutboxMessage
+} {
+	var calls []struct {
+		Ctx        context.Context
+		ReminderID int64
+		Items      []string
+		Msgs       []domain.OutboxMessage
+	}
+	mock.lockAddChecklistItems.RLock()
+	calls = mock.calls.AddChecklistItems
+	mock.lockAddChecklistItems.RUnlock()
+	return calls
+}
+
+// ResetAddChecklistItemsCalls reset all the calls that were made to AddChecklistItems.
+func (mock *StorageMock) ResetAddChecklistItemsCalls() {
+	mock.lockAddChecklistItems.Lock()
+	mock.calls.AddChecklistItems = nil
+	mock.lockAddChecklistItems.Unlock()
 }
 
 // DelayReminder calls DelayReminderFunc.
-func (mock *StorageMock) DelayReminder(ctx context.Context, id int64, remindAt time.Time) error {
+func (mock *StorageMock) DelayReminder(ctx context.Context, id int64, remindAt time.Time, msgs ...domain.OutboxMessage) error {
 	if mock.DelayReminderFunc == nil {
 		panic("StorageMock.DelayReminderFunc: method is nil but Storage.DelayReminder was just called")
 	}
@@ -175,15 +532,17 @@ func (mock *StorageMock) DelayReminder(ctx context.Context, id int64, remindAt t
 		Ctx      context.Context
 		ID       int64
 		RemindAt time.Time
+		Msgs     []domain.OutboxMessage
 	}{
 		Ctx:      ctx,
 		ID:       id,
 		RemindAt: remindAt,
+		Msgs:     msgs,
 	}
 	mock.lockDelayReminder.Lock()
 	mock.calls.DelayReminder = append(mock.calls.DelayReminder, callInfo)
 	mock.lockDelayReminder.Unlock()
-	return mock.DelayReminderFunc(ctx, id, remindAt)
+	return mock.DelayReminderFunc(ctx, id, remindAt, msgs...)
 }
 
 // DelayReminderCalls gets all the calls that were made to DelayReminder.
@@ -194,11 +553,13 @@ func (mock *StorageMock) DelayReminderCalls() []struct {
 	Ctx      context.Context
 	ID       int64
 	RemindAt time.Time
+	Msgs     []domain.OutboxMessage
 } {
 	var calls []struct {
 		Ctx      context.Context
 		ID       int64
 		RemindAt time.Time
+		Msgs     []domain.OutboxMessage
 	}
 	mock.lockDelayReminder.RLock()
 	calls = mock.calls.DelayReminder
@@ -256,6 +617,49 @@ func (mock *StorageMock) ResetGetBotStateCalls() {
 	mock.lockGetBotState.Unlock()
 }
 
+// GetChecklistItem calls GetChecklistItemFunc.
+func (mock *StorageMock) GetChecklistItem(ctx context.Context, id int64) (domain.ChecklistItem, error) {
+	if mock.GetChecklistItemFunc == nil {
+		panic("StorageMock.GetChecklistItemFunc: method is nil but Storage.GetChecklistItem was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetChecklistItem.Lock()
+	mock.calls.GetChecklistItem = append(mock.calls.GetChecklistItem, callInfo)
+	mock.lockGetChecklistItem.Unlock()
+	return mock.GetChecklistItemFunc(ctx, id)
+}
+
+// GetChecklistItemCalls gets all the calls that were made to GetChecklistItem.
+// Check the length with:
+//
+//	len(mockedStorage.GetChecklistItemCalls())
+func (mock *StorageMock) GetChecklistItemCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockGetChecklistItem.RLock()
+	calls = mock.calls.GetChecklistItem
+	mock.lockGetChecklistItem.RUnlock()
+	return calls
+}
+
+// ResetGetChecklistItemCalls reset all the calls that were made to GetChecklistItem.
+func (mock *StorageMock) ResetGetChecklistItemCalls() {
+	mock.lockGetChecklistItem.Lock()
+	mock.calls.GetChecklistItem = nil
+	mock.lockGetChecklistItem.Unlock()
+}
+
 // GetMyReminders calls GetMyRemindersFunc.
 func (mock *StorageMock) GetMyReminders(ctx context.Context, userID int64, chatID int64) ([]domain.Reminder, error) {
 	if mock.GetMyRemindersFunc == nil {
@@ -303,10 +707,233 @@ func (mock *StorageMock) ResetGetMyRemindersCalls() {
 	mock.lockGetMyReminders.Unlock()
 }
 
-// RemoveReminder calls RemoveReminderFunc.
-func (mock *StorageMock) RemoveReminder(ctx context.Context, id int64) error {
-	if mock.RemoveReminderFunc == nil {
-		panic("StorageMock.RemoveReminderFunc: method is nil but Storage.RemoveReminder was just called")
+// GetReminder calls GetReminderFunc.
+func (mock *StorageMock) GetReminder(ctx context.Context, id int64) (domain.Reminder, error) {
+	if mock.GetReminderFunc == nil {
+		panic("StorageMock.GetReminderFunc: method is nil but Storage.GetReminder was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int64
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetReminder.Lock()
+	mock.calls.GetReminder = append(mock.calls.GetReminder, callInfo)
+	mock.lockGetReminder.Unlock()
+	return mock.GetReminderFunc(ctx, id)
+}
+
+// GetReminderCalls gets all the calls that were made to GetReminder.
+// Check the length with:
+//
+//	len(mockedStorage.GetReminderCalls())
+func (mock *StorageMock) GetReminderCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockGetReminder.RLock()
+	calls = mock.calls.GetReminder
+	mock.lockGetReminder.RUnlock()
+	return calls
+}
+
+// ResetGetReminderCalls reset all the calls that were made to GetReminder.
+func (mock *StorageMock) ResetGetReminderCalls() {
+	mock.lockGetReminder.Lock()
+	mock.calls.GetReminder = nil
+	mock.lockGetReminder.Unlock()
+}
+
+// GetReminderEvents calls GetReminderEventsFunc.
+func (mock *StorageMock) GetReminderEvents(ctx context.Context, reminderID int64) ([]domain.ReminderEvent, error) {
+	if mock.GetReminderEventsFunc == nil {
+		panic("StorageMock.GetReminderEventsFunc: method is nil but Storage.GetReminderEvents was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		ReminderID int64
+	}{
+		Ctx:        ctx,
+		ReminderID: reminderID,
+	}
+	mock.lockGetReminderEvents.Lock()
+	mock.calls.GetReminderEvents = append(mock.calls.GetReminderEvents, callInfo)
+	mock.lockGetReminderEvents.Unlock()
+	return mock.GetReminderEventsFunc(ctx, reminderID)
+}
+
+// GetReminderEventsCalls gets all the calls that were made to GetReminderEvents.
+// Check the length with:
+//
+//	len(mockedStorage.GetReminderEventsCalls())
+func (mock *StorageMock) GetReminderEventsCalls() []struct {
+	Ctx        context.Context
+	ReminderID int64
+} {
+	var calls []struct {
+		Ctx        context.Context
+		ReminderID int64
+	}
+	mock.lockGetReminderEvents.RLock()
+	calls = mock.calls.GetReminderEvents
+	mock.lockGetReminderEvents.RUnlock()
+	return calls
+}
+
+// ResetGetReminderEventsCalls reset all the calls that were made to GetReminderEvents.
+func (mock *StorageMock) ResetGetReminderEventsCalls() {
+	mock.lockGetReminderEvents.Lock()
+	mock.calls.GetReminderEvents = nil
+	mock.lockGetReminderEvents.Unlock()
+}
+
+// GetSummaryReminderIDs calls GetSummaryReminderIDsFunc.
+func (mock *StorageMock) GetSummaryReminderIDs(ctx context.Context, userID int64, chatID int64, summaryKey string) ([]int64, error) {
+	if mock.GetSummaryReminderIDsFunc == nil {
+		panic("StorageMock.GetSummaryReminderIDsFunc: method is nil but Storage.GetSummaryReminderIDs was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		UserID     int64
+		ChatID     int64
+		SummaryKey string
+	}{
+		Ctx:        ctx,
+		UserID:     userID,
+		ChatID:     chatID,
+		SummaryKey: summaryKey,
+	}
+	mock.lockGetSummaryReminderIDs.Lock()
+	mock.calls.GetSummaryReminderIDs = append(mock.calls.GetSummaryReminderIDs, callInfo)
+	mock.lockGetSummaryReminderIDs.Unlock()
+	return mock.GetSummaryReminderIDsFunc(ctx, userID, chatID, summaryKey)
+}
+
+// GetSummaryReminderIDsCalls gets all the calls that were made to GetSummaryReminderIDs.
+// Check the length with:
+//
+//	len(mockedStorage.GetSummaryReminderIDsCalls())
+func (mock *StorageMock) GetSummaryReminderIDsCalls() []struct {
+	Ctx        context.Context
+	UserID     int64
+	ChatID     int64
+	SummaryKey string
+} {
+	var calls []struct {
+		Ctx        context.Context
+		UserID     int64
+		ChatID     int64
+		SummaryKey string
+	}
+	mock.lockGetSummaryReminderIDs.RLock()
+	calls = mock.calls.GetSummaryReminderIDs
+	mock.lockGetSummaryReminderIDs.RUnlock()
+	return calls
+}
+
+// ResetGetSummaryReminderIDsCalls reset all the calls that were made to GetSummaryReminderIDs.
+func (mock *StorageMock) ResetGetSummaryReminderIDsCalls() {
+	mock.lockGetSummaryReminderIDs.Lock()
+	mock.calls.GetSummaryReminderIDs = nil
+	mock.lockGetSummaryReminderIDs.Unlock()
+}
+
+// GetUserSettings calls GetUserSettingsFunc.
+func (mock *StorageMock) GetUserSettings(ctx context.Context, userID int64) (domain.UserSettings, error) {
+	if mock.GetUserSettingsFunc == nil {
+		panic("StorageMock.GetUserSettingsFunc: method is nil but Storage.GetUserSettings was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockGetUserSettings.Lock()
+	mock.calls.GetUserSettings = append(mock.calls.GetUserSettings, callInfo)
+	mock.lockGetUserSettings.Unlock()
+	return mock.GetUserSettingsFunc(ctx, userID)
+}
+
+// GetUserSettingsCalls gets all the calls that were made to GetUserSettings.
+// Check the length with:
+//
+//	len(mockedStorage.GetUserSettingsCalls())
+func (mock *StorageMock) GetUserSettingsCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+	}
+	mock.lockGetUserSettings.RLock()
+	calls = mock.calls.GetUserSettings
+	mock.lockGetUserSettings.RUnlock()
+	return calls
+}
+
+// ResetGetUserSettingsCalls reset all the calls that were made to GetUserSettings.
+func (mock *StorageMock) ResetGetUserSettingsCalls() {
+	mock.lockGetUserSettings.Lock()
+	mock.calls.GetUserSettings = nil
+	mock.lockGetUserSettings.Unlock()
+}
+
+// GetUserWebhooks calls GetUserWebhooksFunc.
+func (mock *StorageMock) GetUserWebhooks(ctx context.Context, userID int64) ([]domain.Webhook, error) {
+	if mock.GetUserWebhooksFunc == nil {
+		panic("StorageMock.GetUserWebhooksFunc: method is nil but Storage.GetUserWebhooks was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockGetUserWebhooks.Lock()
+	mock.calls.GetUserWebhooks = append(mock.calls.GetUserWebhooks, callInfo)
+	mock.lockGetUserWebhooks.Unlock()
+	return mock.GetUserWebhooksFunc(ctx, userID)
+}
+
+// GetUserWebhooksCalls gets all the calls that were made to GetUserWebhooks.
+// Check the length with:
+//
+//	len(mockedStorage.GetUserWebhooksCalls())
+func (mock *StorageMock) GetUserWebhooksCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+	}
+	mock.lockGetUserWebhooks.RLock()
+	calls = mock.calls.GetUserWebhooks
+	mock.lockGetUserWebhooks.RUnlock()
+	return calls
+}
+
+// ResetGetUserWebhooksCalls reset all the calls that were made to GetUserWebhooks.
+func (mock *StorageMock) ResetGetUserWebhooksCalls() {
+	mock.lockGetUserWebhooks.Lock()
+	mock.calls.GetUserWebhooks = nil
+	mock.lockGetUserWebhooks.Unlock()
+}
+
+// GetWebhook calls GetWebhookFunc.
+func (mock *StorageMock) GetWebhook(ctx context.Context, id int64) (domain.Webhook, error) {
+	if mock.GetWebhookFunc == nil {
+		panic("StorageMock.GetWebhookFunc: method is nil but Storage.GetWebhook was just called")
 	}
 	callInfo := struct {
 		Ctx context.Context
@@ -315,10 +942,102 @@ func (mock *StorageMock) RemoveReminder(ctx context.Context, id int64) error {
 		Ctx: ctx,
 		ID:  id,
 	}
+	mock.lockGetWebhook.Lock()
+	mock.calls.GetWebhook = append(mock.calls.GetWebhook, callInfo)
+	mock.lockGetWebhook.Unlock()
+	return mock.GetWebhookFunc(ctx, id)
+}
+
+// GetWebhookCalls gets all the calls that were made to GetWebhook.
+// Check the length with:
+//
+//	len(mockedStorage.GetWebhookCalls())
+func (mock *StorageMock) GetWebhookCalls() []struct {
+	Ctx context.Context
+	ID  int64
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int64
+	}
+	mock.lockGetWebhook.RLock()
+	calls = mock.calls.GetWebhook
+	mock.lockGetWebhook.RUnlock()
+	return calls
+}
+
+// ResetGetWebhookCalls reset all the calls that were made to GetWebhook.
+func (mock *StorageMock) ResetGetWebhookCalls() {
+	mock.lockGetWebhook.Lock()
+	mock.calls.GetWebhook = nil
+	mock.lockGetWebhook.Unlock()
+}
+
+// GetWebhookDeliveries calls GetWebhookDeliveriesFunc.
+func (mock *StorageMock) GetWebhookDeliveries(ctx context.Context, webhookID int64, limit int64) ([]domain.WebhookDelivery, error) {
+	if mock.GetWebhookDeliveriesFunc == nil {
+		panic("StorageMock.GetWebhookDeliveriesFunc: method is nil but Storage.GetWebhookDeliveries was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		WebhookID int64
+		Limit     int64
+	}{
+		Ctx:       ctx,
+		WebhookID: webhookID,
+		Limit:     limit,
+	}
+	mock.lockGetWebhookDeliveries.Lock()
+	mock.calls.GetWebhookDeliveries = append(mock.calls.GetWebhookDeliveries, callInfo)
+	mock.lockGetWebhookDeliveries.Unlock()
+	return mock.GetWebhookDeliveriesFunc(ctx, webhookID, limit)
+}
+
+// GetWebhookDeliveriesCalls gets all the calls that were made to GetWebhookDeliveries.
+// Check the length with:
+//
+//	len(mockedStorage.GetWebhookDeliveriesCalls())
+func (mock *StorageMock) GetWebhookDeliveriesCalls() []struct {
+	Ctx       context.Context
+	WebhookID int64
+	Limit     int64
+} {
+	var calls []struct {
+		Ctx       context.Context
+		WebhookID int64
+		Limit     int64
+	}
+	mock.lockGetWebhookDeliveries.RLock()
+	calls = mock.calls.GetWebhookDeliveries
+	mock.lockGetWebhookDeliveries.RUnlock()
+	return calls
+}
+
+// ResetGetWebhookDeliveriesCalls reset all the calls that were made to GetWebhookDeliveries.
+func (mock *StorageMock) ResetGetWebhookDeliveriesCalls() {
+	mock.lockGetWebhookDeliveries.Lock()
+	mock.calls.GetWebhookDeliveries = nil
+	mock.lockGetWebhookDeliveries.Unlock()
+}
+
+// RemoveReminder calls RemoveReminderFunc.
+func (mock *StorageMock) RemoveReminder(ctx context.Context, id int64, msgs ...domain.OutboxMessage) error {
+	if mock.RemoveReminderFunc == nil {
+		panic("StorageMock.RemoveReminderFunc: method is nil but Storage.RemoveReminder was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		ID   int64
+		Msgs []domain.OutboxMessage
+	}{
+		Ctx:  ctx,
+		ID:   id,
+		Msgs: msgs,
+	}
 	mock.lockRemoveReminder.Lock()
 	mock.calls.RemoveReminder = append(mock.calls.RemoveReminder, callInfo)
 	mock.lockRemoveReminder.Unlock()
-	return mock.RemoveReminderFunc(ctx, id)
+	return mock.RemoveReminderFunc(ctx, id, msgs...)
 }
 
 // RemoveReminderCalls gets all the calls that were made to RemoveReminder.
@@ -326,12 +1045,14 @@ func (mock *StorageMock) RemoveReminder(ctx context.Context, id int64) error {
 //
 //	len(mockedStorage.RemoveReminderCalls())
 func (mock *StorageMock) RemoveReminderCalls() []struct {
-	Ctx context.Context
-	ID  int64
+	Ctx  context.Context
+	ID   int64
+	Msgs []domain.OutboxMessage
 } {
 	var calls []struct {
-		Ctx context.Context
-		ID  int64
+		Ctx  context.Context
+		ID   int64
+		Msgs []domain.OutboxMessage
 	}
 	mock.lockRemoveReminder.RLock()
 	calls = mock.calls.RemoveReminder
@@ -346,22 +1067,177 @@ func (mock *StorageMock) ResetRemoveReminderCalls() {
 	mock.lockRemoveReminder.Unlock()
 }
 
+// RemoveWebhook calls RemoveWebhookFunc.
+func (mock *StorageMock) RemoveWebhook(ctx context.Context, id int64, msgs ...domain.OutboxMessage) error {
+	if mock.RemoveWebhookFunc == nil {
+		panic("StorageMock.RemoveWebhookFunc: method is nil but Storage.RemoveWebhook was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		ID   int64
+		Msgs []domain.OutboxMessage
+	}{
+		Ctx:  ctx,
+		ID:   id,
+		Msgs: msgs,
+	}
+	mock.lockRemoveWebhook.Lock()
+	mock.calls.RemoveWebhook = append(mock.calls.RemoveWebhook, callInfo)
+	mock.lockRemoveWebhook.Unlock()
+	return mock.RemoveWebhookFunc(ctx, id, msgs...)
+}
+
+// RemoveWebhookCalls gets all the calls that were made to RemoveWebhook.
+// Check the length with:
+//
+//	len(mockedStorage.RemoveWebhookCalls())
+func (mock *StorageMock) RemoveWebhookCalls() []struct {
+	Ctx  context.Context
+	ID   int64
+	Msgs []domain.OutboxMessage
+} {
+	var calls []struct {
+		Ctx  context.Context
+		ID   int64
+		Msgs []domain.OutboxMessage
+	}
+	mock.lockRemoveWebhook.RLock()
+	calls = mock.calls.RemoveWebhook
+	mock.lockRemoveWebhook.RUnlock()
+	return calls
+}
+
+// ResetRemoveWebhookCalls reset all the calls that were made to RemoveWebhook.
+func (mock *StorageMock) ResetRemoveWebhookCalls() {
+	mock.lockRemoveWebhook.Lock()
+	mock.calls.RemoveWebhook = nil
+	mock.lockRemoveWebhook.Unlock()
+}
+
+// RescheduleMissedReminders calls RescheduleMissedRemindersFunc.
+func (mock *StorageMock) RescheduleMissedReminders(ctx context.Context, userID int64, chatID int64, ids []int64, remindAt time.Time, newMsgs func(rescheduled int64) ([]domain.OutboxMessage, error)) (int64, error) {
+	if mock.RescheduleMissedRemindersFunc == nil {
+		panic("StorageMock.RescheduleMissedRemindersFunc: method is nil but Storage.RescheduleMissedReminders was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		UserID   int64
+		ChatID   int64
+		Ids      []int64
+		RemindAt time.Time
+		NewMsgs  func(rescheduled int64) ([]domain.OutboxMessage, error)
+	}{
+		Ctx:      ctx,
+		UserID:   userID,
+		ChatID:   chatID,
+		Ids:      ids,
+		RemindAt: remindAt,
+		NewMsgs:  newMsgs,
+	}
+	mock.lockRescheduleMissedReminders.Lock()
+	mock.calls.RescheduleMissedReminders = append(mock.calls.RescheduleMissedReminders, callInfo)
+	mock.lockRescheduleMissedReminders.Unlock()
+	return mock.RescheduleMissedRemindersFunc(ctx, userID, chatID, ids, remindAt, newMsgs)
+}
+
+// RescheduleMissedRemindersCalls gets all the calls that were made to RescheduleMissedReminders.
+// Check the length with:
+//
+//	len(mockedStorage.RescheduleMissedRemindersCalls())
+func (mock *StorageMock) RescheduleMissedRemindersCalls() []struct {
+	Ctx      context.Context
+	UserID   int64
+	ChatID   int64
+	Ids      []int64
+	RemindAt time.Time
+	NewMsgs  func(rescheduled int64) ([]domain.OutboxMessage, error)
+} {
+	var calls []struct {
+		Ctx      context.Context
+		UserID   int64
+		ChatID   int64
+		Ids      []int64
+		RemindAt time.Time
+		NewMsgs  func(rescheduled int64) ([]domain.OutboxMessage, error)
+	}
+	mock.lockRescheduleMissedReminders.RLock()
+	calls = mock.calls.RescheduleMissedReminders
+	mock.lockRescheduleMissedReminders.RUnlock()
+	return calls
+}
+
+// ResetRescheduleMissedRemindersCalls reset all the calls that were made to RescheduleMissedReminders.
+func (mock *StorageMock) ResetRescheduleMissedRemindersCalls() {
+	mock.lockRescheduleMissedReminders.Lock()
+	mock.calls.RescheduleMissedReminders = nil
+	mock.lockRescheduleMissedReminders.Unlock()
+}
+
+// SaveAPIToken calls SaveAPITokenFunc.
+func (mock *StorageMock) SaveAPIToken(ctx context.Context, token domain.APIToken, msgs ...domain.OutboxMessage) error {
+	if mock.SaveAPITokenFunc == nil {
+		panic("StorageMock.SaveAPITokenFunc: method is nil but Storage.SaveAPIToken was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Token domain.APIToken
+		Msgs  []domain.OutboxMessage
+	}{
+		Ctx:   ctx,
+		Token: token,
+		Msgs:  msgs,
+	}
+	mock.lockSaveAPIToken.Lock()
+	mock.calls.SaveAPIToken = append(mock.calls.SaveAPIToken, callInfo)
+	mock.lockSaveAPIToken.Unlock()
+	return mock.SaveAPITokenFunc(ctx, token, msgs...)
+}
+
+// SaveAPITokenCalls gets all the calls that were made to SaveAPIToken.
+// Check the length with:
+//
+//	len(mockedStorage.SaveAPITokenCalls())
+func (mock *StorageMock) SaveAPITokenCalls() []struct {
+	Ctx   context.Context
+	Token domain.APIToken
+	Msgs  []domain.OutboxMessage
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Token domain.APIToken
+		Msgs  []domain.OutboxMessage
+	}
+	mock.lockSaveAPIToken.RLock()
+	calls = mock.calls.SaveAPIToken
+	mock.lockSaveAPIToken.RUnlock()
+	return calls
+}
+
+// ResetSaveAPITokenCalls reset all the calls that were made to SaveAPIToken.
+func (mock *StorageMock) ResetSaveAPITokenCalls() {
+	mock.lockSaveAPIToken.Lock()
+	mock.calls.SaveAPIToken = nil
+	mock.lockSaveAPIToken.Unlock()
+}
+
 // SaveBotState calls SaveBotStateFunc.
-func (mock *StorageMock) SaveBotState(ctx context.Context, state domain.BotState) error {
+func (mock *StorageMock) SaveBotState(ctx context.Context, state domain.BotState, msgs ...domain.OutboxMessage) error {
 	if mock.SaveBotStateFunc == nil {
 		panic("StorageMock.SaveBotStateFunc: method is nil but Storage.SaveBotState was just called")
 	}
 	callInfo := struct {
 		Ctx   context.Context
 		State domain.BotState
+		Msgs  []domain.OutboxMessage
 	}{
 		Ctx:   ctx,
 		State: state,
+		Msgs:  msgs,
 	}
 	mock.lockSaveBotState.Lock()
 	mock.calls.SaveBotState = append(mock.calls.SaveBotState, callInfo)
 	mock.lockSaveBotState.Unlock()
-	return mock.SaveBotStateFunc(ctx, state)
+	return mock.SaveBotStateFunc(ctx, state, msgs...)
 }
 
 // SaveBotStateCalls gets all the calls that were made to SaveBotState.
@@ -371,10 +1247,12 @@ func (mock *StorageMock) SaveBotState(ctx context.Context, state domain.BotState
 func (mock *StorageMock) SaveBotStateCalls() []struct {
 	Ctx   context.Context
 	State domain.BotState
+	Msgs  []domain.OutboxMessage
 } {
 	var calls []struct {
 		Ctx   context.Context
 		State domain.BotState
+		Msgs  []domain.OutboxMessage
 	}
 	mock.lockSaveBotState.RLock()
 	calls = mock.calls.SaveBotState
@@ -389,22 +1267,75 @@ func (mock *StorageMock) ResetSaveBotStateCalls() {
 	mock.lockSaveBotState.Unlock()
 }
 
+// SaveEmailVerification calls SaveEmailVerificationFunc.
+func (mock *StorageMock) SaveEmailVerification(ctx context.Context, settings domain.UserSettings, msg domain.OutboxMessage, msgs ...domain.OutboxMessage) error {
+	if mock.SaveEmailVerificationFunc == nil {
+		panic("StorageMock.SaveEmailVerificationFunc: method is nil but Storage.SaveEmailVerification was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Settings domain.UserSettings
+		Msg      domain.OutboxMessage
+		Msgs     []domain.OutboxMessage
+	}{
+		Ctx:      ctx,
+		Settings: settings,
+		Msg:      msg,
+		Msgs:     msgs,
+	}
+	mock.lockSaveEmailVerification.Lock()
+	mock.calls.SaveEmailVerification = append(mock.calls.SaveEmailVerification, callInfo)
+	mock.lockSaveEmailVerification.Unlock()
+	return mock.SaveEmailVerificationFunc(ctx, settings, msg, msgs...)
+}
+
+// SaveEmailVerificationCalls gets all the calls that were made to SaveEmailVerification.
+// Check the length with:
+//
+//	len(mockedStorage.SaveEmailVerificationCalls())
+func (mock *StorageMock) SaveEmailVerificationCalls() []struct {
+	Ctx      context.Context
+	Settings domain.UserSettings
+	Msg      domain.OutboxMessage
+	Msgs     []domain.OutboxMessage
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Settings domain.UserSettings
+		Msg      domain.OutboxMessage
+		Msgs     []domain.OutboxMessage
+	}
+	mock.lockSaveEmailVerification.RLock()
+	calls = mock.calls.SaveEmailVerification
+	mock.lockSaveEmailVerification.RUnlock()
+	return calls
+}
+
+// ResetSaveEmailVerificationCalls reset all the calls that were made to SaveEmailVerification.
+func (mock *StorageMock) ResetSaveEmailVerificationCalls() {
+	mock.lockSaveEmailVerification.Lock()
+	mock.calls.SaveEmailVerification = nil
+	mock.lockSaveEmailVerification.Unlock()
+}
+
 // SaveReminder calls SaveReminderFunc.
-func (mock *StorageMock) SaveReminder(ctx context.Context, reminder domain.Reminder) (int64, error) {
+func (mock *StorageMock) SaveReminder(ctx context.Context, reminder domain.Reminder, newMsgs func(id int64) ([]domain.OutboxMessage, error)) (int64, error) {
 	if mock.SaveReminderFunc == nil {
 		panic("StorageMock.SaveReminderFunc: method is nil but Storage.SaveReminder was just called")
 	}
 	callInfo := struct {
 		Ctx      context.Context
 		Reminder domain.Reminder
+		NewMsgs  func(id int64) ([]domain.OutboxMessage, error)
 	}{
 		Ctx:      ctx,
 		Reminder: reminder,
+		NewMsgs:  newMsgs,
 	}
 	mock.lockSaveReminder.Lock()
 	mock.calls.SaveReminder = append(mock.calls.SaveReminder, callInfo)
 	mock.lockSaveReminder.Unlock()
-	return mock.SaveReminderFunc(ctx, reminder)
+	return mock.SaveReminderFunc(ctx, reminder, newMsgs)
 }
 
 // SaveReminderCalls gets all the calls that were made to SaveReminder.
@@ -414,10 +1345,12 @@ func (mock *StorageMock) SaveReminder(ctx context.Context, reminder domain.Remin
 func (mock *StorageMock) SaveReminderCalls() []struct {
 	Ctx      context.Context
 	Reminder domain.Reminder
+	NewMsgs  func(id int64) ([]domain.OutboxMessage, error)
 } {
 	var calls []struct {
 		Ctx      context.Context
 		Reminder domain.Reminder
+		NewMsgs  func(id int64) ([]domain.OutboxMessage, error)
 	}
 	mock.lockSaveReminder.RLock()
 	calls = mock.calls.SaveReminder
@@ -433,21 +1366,23 @@ func (mock *StorageMock) ResetSaveReminderCalls() {
 }
 
 // SaveUser calls SaveUserFunc.
-func (mock *StorageMock) SaveUser(ctx context.Context, user domain.User) error {
+func (mock *StorageMock) SaveUser(ctx context.Context, user domain.User, msgs ...domain.OutboxMessage) error {
 	if mock.SaveUserFunc == nil {
 		panic("StorageMock.SaveUserFunc: method is nil but Storage.SaveUser was just called")
 	}
 	callInfo := struct {
 		Ctx  context.Context
 		User domain.User
+		Msgs []domain.OutboxMessage
 	}{
 		Ctx:  ctx,
 		User: user,
+		Msgs: msgs,
 	}
 	mock.lockSaveUser.Lock()
 	mock.calls.SaveUser = append(mock.calls.SaveUser, callInfo)
 	mock.lockSaveUser.Unlock()
-	return mock.SaveUserFunc(ctx, user)
+	return mock.SaveUserFunc(ctx, user, msgs...)
 }
 
 // SaveUserCalls gets all the calls that were made to SaveUser.
@@ -457,10 +1392,12 @@ func (mock *StorageMock) SaveUser(ctx context.Context, user domain.User) error {
 func (mock *StorageMock) SaveUserCalls() []struct {
 	Ctx  context.Context
 	User domain.User
+	Msgs []domain.OutboxMessage
 } {
 	var calls []struct {
 		Ctx  context.Context
 		User domain.User
+		Msgs []domain.OutboxMessage
 	}
 	mock.lockSaveUser.RLock()
 	calls = mock.calls.SaveUser
@@ -475,8 +1412,204 @@ func (mock *StorageMock) ResetSaveUserCalls() {
 	mock.lockSaveUser.Unlock()
 }
 
+// SaveUserSettings calls SaveUserSettingsFunc.
+func (mock *StorageMock) SaveUserSettings(ctx context.Context, settings domain.UserSettings, msgs ...domain.OutboxMessage) error {
+	if mock.SaveUserSettingsFunc == nil {
+		panic("StorageMock.SaveUserSettingsFunc: method is nil but Storage.SaveUserSettings was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Settings domain.UserSettings
+		Msgs     []domain.OutboxMessage
+	}{
+		Ctx:      ctx,
+		Settings: settings,
+		Msgs:     msgs,
+	}
+	mock.lockSaveUserSettings.Lock()
+	mock.calls.SaveUserSettings = append(mock.calls.SaveUserSettings, callInfo)
+	mock.lockSaveUserSettings.Unlock()
+	return mock.SaveUserSettingsFunc(ctx, settings, msgs...)
+}
+
+// SaveUserSettingsCalls gets all the calls that were made to SaveUserSettings.
+// Check the length with:
+//
+//	len(mockedStorage.SaveUserSettingsCalls())
+func (mock *StorageMock) SaveUserSettingsCalls() []struct {
+	Ctx      context.Context
+	Settings domain.UserSettings
+	Msgs     []domain.OutboxMessage
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Settings domain.UserSettings
+		Msgs     []domain.OutboxMessage
+	}
+	mock.lockSaveUserSettings.RLock()
+	calls = mock.calls.SaveUserSettings
+	mock.lockSaveUserSettings.RUnlock()
+	return calls
+}
+
+// ResetSaveUserSettingsCalls reset all the calls that were made to SaveUserSettings.
+func (mock *StorageMock) ResetSaveUserSettingsCalls() {
+	mock.lockSaveUserSettings.Lock()
+	mock.calls.SaveUserSettings = nil
+	mock.lockSaveUserSettings.Unlock()
+}
+
+// SaveWebSession calls SaveWebSessionFunc.
+func (mock *StorageMock) SaveWebSession(ctx context.Context, session domain.WebSession, msgs ...domain.OutboxMessage) error {
+	if mock.SaveWebSessionFunc == nil {
+		panic("StorageMock.SaveWebSessionFunc: method is nil but Storage.SaveWebSession was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Session domain.WebSession
+		Msgs    []domain.OutboxMessage
+	}{
+		Ctx:     ctx,
+		Session: session,
+		Msgs:    msgs,
+	}
+	mock.lockSaveWebSession.Lock()
+	mock.calls.SaveWebSession = append(mock.calls.SaveWebSession, callInfo)
+	mock.lockSaveWebSession.Unlock()
+	return mock.SaveWebSessionFunc(ctx, session, msgs...)
+}
+
+// SaveWebSessionCalls gets all the calls that were made to SaveWebSession.
+// Check the length with:
+//
+//	len(mockedStorage.SaveWebSessionCalls())
+func (mock *StorageMock) SaveWebSessionCalls() []struct {
+	Ctx     context.Context
+	Session domain.WebSession
+	Msgs    []domain.OutboxMessage
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Session domain.WebSession
+		Msgs    []domain.OutboxMessage
+	}
+	mock.lockSaveWebSession.RLock()
+	calls = mock.calls.SaveWebSession
+	mock.lockSaveWebSession.RUnlock()
+	return calls
+}
+
+// ResetSaveWebSessionCalls reset all the calls that were made to SaveWebSession.
+func (mock *StorageMock) ResetSaveWebSessionCalls() {
+	mock.lockSaveWebSession.Lock()
+	mock.calls.SaveWebSession = nil
+	mock.lockSaveWebSession.Unlock()
+}
+
+// SaveWebhook calls SaveWebhookFunc.
+func (mock *StorageMock) SaveWebhook(ctx context.Context, hook domain.Webhook, newMsgs func(id int64) ([]domain.OutboxMessage, error)) (int64, error) {
+	if mock.SaveWebhookFunc == nil {
+		panic("StorageMock.SaveWebhookFunc: method is nil but Storage.SaveWebhook was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Hook    domain.Webhook
+		NewMsgs func(id int64) ([]domain.OutboxMessage, error)
+	}{
+		Ctx:     ctx,
+		Hook:    hook,
+		NewMsgs: newMsgs,
+	}
+	mock.lockSaveWebhook.Lock()
+	mock.calls.SaveWebhook = append(mock.calls.SaveWebhook, callInfo)
+	mock.lockSaveWebhook.Unlock()
+	return mock.SaveWebhookFunc(ctx, hook, newMsgs)
+}
+
+// SaveWebhookCalls gets all the calls that were made to SaveWebhook.
+// Check the length with:
+//
+//	len(mockedStorage.SaveWebhookCalls())
+func (mock *StorageMock) SaveWebhookCalls() []struct {
+	Ctx     context.Context
+	Hook    domain.Webhook
+	NewMsgs func(id int64) ([]domain.OutboxMessage, error)
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Hook    domain.Webhook
+		NewMsgs func(id int64) ([]domain.OutboxMessage, error)
+	}
+	mock.lockSaveWebhook.RLock()
+	calls = mock.calls.SaveWebhook
+	mock.lockSaveWebhook.RUnlock()
+	return calls
+}
+
+// ResetSaveWebhookCalls reset all the calls that were made to SaveWebhook.
+func (mock *StorageMock) ResetSaveWebhookCalls() {
+	mock.lockSaveWebhook.Lock()
+	mock.calls.SaveWebhook = nil
+	mock.lockSaveWebhook.Unlock()
+}
+
+// SetReminderLeadTimes calls SetReminderLeadTimesFunc.
+func (mock *StorageMock) SetReminderLeadTimes(ctx context.Context, id int64, leadTimes domain.LeadTimes, nextPreNoticeAt *time.Time, msgs ...domain.OutboxMessage) error {
+	if mock.SetReminderLeadTimesFunc == nil {
+		panic("StorageMock.SetReminderLeadTimesFunc: method is nil but Storage.SetReminderLeadTimes was just called")
+	}
+	callInfo := struct {
+		Ctx             context.Context
+		ID              int64
+		LeadTimes       domain.LeadTimes
+		NextPreNoticeAt *time.Time
+		Msgs            []domain.OutboxMessage
+	}{
+		Ctx:             ctx,
+		ID:              id,
+		LeadTimes:       leadTimes,
+		NextPreNoticeAt: nextPreNoticeAt,
+		Msgs:            msgs,
+	}
+	mock.lockSetReminderLeadTimes.Lock()
+	mock.calls.SetReminderLeadTimes = append(mock.calls.SetReminderLeadTimes, callInfo)
+	mock.lockSetReminderLeadTimes.Unlock()
+	return mock.SetReminderLeadTimesFunc(ctx, id, leadTimes, nextPreNoticeAt, msgs...)
+}
+
+// SetReminderLeadTimesCalls gets all the calls that were made to SetReminderLeadTimes.
+// Check the length with:
+//
+//	len(mockedStorage.SetReminderLeadTimesCalls())
+func (mock *StorageMock) SetReminderLeadTimesCalls() []struct {
+	Ctx             context.Context
+	ID              int64
+	LeadTimes       domain.LeadTimes
+	NextPreNoticeAt *time.Time
+	Msgs            []domain.OutboxMessage
+} {
+	var calls []struct {
+		Ctx             context.Context
+		ID              int64
+		LeadTimes       domain.LeadTimes
+		NextPreNoticeAt *time.Time
+		Msgs            []domain.OutboxMessage
+	}
+	mock.lockSetReminderLeadTimes.RLock()
+	calls = mock.calls.SetReminderLeadTimes
+	mock.lockSetReminderLeadTimes.RUnlock()
+	return calls
+}
+
+// ResetSetReminderLeadTimesCalls reset all the calls that were made to SetReminderLeadTimes.
+func (mock *StorageMock) ResetSetReminderLeadTimesCalls() {
+	mock.lockSetReminderLeadTimes.Lock()
+	mock.calls.SetReminderLeadTimes = nil
+	mock.lockSetReminderLeadTimes.Unlock()
+}
+
 // SetReminderStatus calls SetReminderStatusFunc.
-func (mock *StorageMock) SetReminderStatus(ctx context.Context, id int64, status domain.ReminderStatus) error {
+func (mock *StorageMock) SetReminderStatus(ctx context.Context, id int64, status domain.ReminderStatus, msgs ...domain.OutboxMessage) error {
 	if mock.SetReminderStatusFunc == nil {
 		panic("StorageMock.SetReminderStatusFunc: method is nil but Storage.SetReminderStatus was just called")
 	}
@@ -484,15 +1617,17 @@ func (mock *StorageMock) SetReminderStatus(ctx context.Context, id int64, status
 		Ctx    context.Context
 		ID     int64
 		Status domain.ReminderStatus
+		Msgs   []domain.OutboxMessage
 	}{
 		Ctx:    ctx,
 		ID:     id,
 		Status: status,
+		Msgs:   msgs,
 	}
 	mock.lockSetReminderStatus.Lock()
 	mock.calls.SetReminderStatus = append(mock.calls.SetReminderStatus, callInfo)
 	mock.lockSetReminderStatus.Unlock()
-	return mock.SetReminderStatusFunc(ctx, id, status)
+	return mock.SetReminderStatusFunc(ctx, id, status, msgs...)
 }
 
 // SetReminderStatusCalls gets all the calls that were made to SetReminderStatus.
@@ -503,11 +1638,13 @@ func (mock *StorageMock) SetReminderStatusCalls() []struct {
 	Ctx    context.Context
 	ID     int64
 	Status domain.ReminderStatus
+	Msgs   []domain.OutboxMessage
 } {
 	var calls []struct {
 		Ctx    context.Context
 		ID     int64
 		Status domain.ReminderStatus
+		Msgs   []domain.OutboxMessage
 	}
 	mock.lockSetReminderStatus.RLock()
 	calls = mock.calls.SetReminderStatus
@@ -523,7 +1660,7 @@ func (mock *StorageMock) ResetSetReminderStatusCalls() {
 }
 
 // SetUserStatus calls SetUserStatusFunc.
-func (mock *StorageMock) SetUserStatus(ctx context.Context, id int64, inactive domain.UserStatus) error {
+func (mock *StorageMock) SetUserStatus(ctx context.Context, id int64, inactive domain.UserStatus, msgs ...domain.OutboxMessage) error {
 	if mock.SetUserStatusFunc == nil {
 		panic("StorageMock.SetUserStatusFunc: method is nil but Storage.SetUserStatus was just called")
 	}
@@ -531,15 +1668,17 @@ func (mock *StorageMock) SetUserStatus(ctx context.Context, id int64, inactive d
 		Ctx      context.Context
 		ID       int64
 		Inactive domain.UserStatus
+		Msgs     []domain.OutboxMessage
 	}{
 		Ctx:      ctx,
 		ID:       id,
 		Inactive: inactive,
+		Msgs:     msgs,
 	}
 	mock.lockSetUserStatus.Lock()
 	mock.calls.SetUserStatus = append(mock.calls.SetUserStatus, callInfo)
 	mock.lockSetUserStatus.Unlock()
-	return mock.SetUserStatusFunc(ctx, id, inactive)
+	return mock.SetUserStatusFunc(ctx, id, inactive, msgs...)
 }
 
 // SetUserStatusCalls gets all the calls that were made to SetUserStatus.
@@ -550,11 +1689,13 @@ func (mock *StorageMock) SetUserStatusCalls() []struct {
 	Ctx      context.Context
 	ID       int64
 	Inactive domain.UserStatus
+	Msgs     []domain.OutboxMessage
 } {
 	var calls []struct {
 		Ctx      context.Context
 		ID       int64
 		Inactive domain.UserStatus
+		Msgs     []domain.OutboxMessage
 	}
 	mock.lockSetUserStatus.RLock()
 	calls = mock.calls.SetUserStatus
@@ -569,8 +1710,59 @@ func (mock *StorageMock) ResetSetUserStatusCalls() {
 	mock.lockSetUserStatus.Unlock()
 }
 
+// ToggleChecklistItem calls ToggleChecklistItemFunc.
+func (mock *StorageMock) ToggleChecklistItem(ctx context.Context, id int64, newMsgs func(reminder domain.Reminder) ([]domain.OutboxMessage, error)) (domain.Reminder, error) {
+	if mock.ToggleChecklistItemFunc == nil {
+		panic("StorageMock.ToggleChecklistItemFunc: method is nil but Storage.ToggleChecklistItem was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		ID      int64
+		NewMsgs func(reminder domain.Reminder) ([]domain.OutboxMessage, error)
+	}{
+		Ctx:     ctx,
+		ID:      id,
+		NewMsgs: newMsgs,
+	}
+	mock.lockToggleChecklistItem.Lock()
+	mock.calls.ToggleChecklistItem = append(mock.calls.ToggleChecklistItem, callInfo)
+	mock.lockToggleChecklistItem.Unlock()
+	return mock.ToggleChecklistItemFunc(ctx, id, newMsgs)
+}
+
+// ToggleChecklistItemCalls gets all the calls that were made to ToggleChecklistItem.
+// Check the length with:
+//
+//	len(mockedStorage.ToggleChecklistItemCalls())
+func (mock *StorageMock) ToggleChecklistItemCalls() []struct {
+	Ctx     context.Context
+	ID      int64
+	NewMsgs func(reminder domain.Reminder) ([]domain.OutboxMessage, error)
+} {
+	var calls []struct {
+		Ctx     context.Context
+		ID      int64
+		NewMsgs func(reminder domain.Reminder) ([]domain.OutboxMessage, error)
+	}
+	mock.lockToggleChecklistItem.RLock()
+	calls = mock.calls.ToggleChecklistItem
+	mock.lockToggleChecklistItem.RUnlock()
+	return calls
+}
+
+// ResetToggleChecklistItemCalls reset all the calls that were made to ToggleChecklistItem.
+func (mock *StorageMock) ResetToggleChecklistItemCalls() {
+	mock.lockToggleChecklistItem.Lock()
+	mock.calls.ToggleChecklistItem = nil
+	mock.lockToggleChecklistItem.Unlock()
+}
+
 // ResetCalls reset all the calls that were made to all mocked methods.
 func (mock *StorageMock) ResetCalls() {
+	mock.lockAddChecklistItems.Lock()
+	mock.calls.AddChecklistItems = nil
+	mock.lockAddChecklistItems.Unlock()
+
 	mock.lockDelayReminder.Lock()
 	mock.calls.DelayReminder = nil
 	mock.lockDelayReminder.Unlock()
@@ -579,18 +1771,66 @@ func (mock *StorageMock) ResetCalls() {
 	mock.calls.GetBotState = nil
 	mock.lockGetBotState.Unlock()
 
+	mock.lockGetChecklistItem.Lock()
+	mock.calls.GetChecklistItem = nil
+	mock.lockGetChecklistItem.Unlock()
+
 	mock.lockGetMyReminders.Lock()
 	mock.calls.GetMyReminders = nil
 	mock.lockGetMyReminders.Unlock()
 
+	mock.lockGetReminder.Lock()
+	mock.calls.GetReminder = nil
+	mock.lockGetReminder.Unlock()
+
+	mock.lockGetReminderEvents.Lock()
+	mock.calls.GetReminderEvents = nil
+	mock.lockGetReminderEvents.Unlock()
+
+	mock.lockGetSummaryReminderIDs.Lock()
+	mock.calls.GetSummaryReminderIDs = nil
+	mock.lockGetSummaryReminderIDs.Unlock()
+
+	mock.lockGetUserSettings.Lock()
+	mock.calls.GetUserSettings = nil
+	mock.lockGetUserSettings.Unlock()
+
+	mock.lockGetUserWebhooks.Lock()
+	mock.calls.GetUserWebhooks = nil
+	mock.lockGetUserWebhooks.Unlock()
+
+	mock.lockGetWebhook.Lock()
+	mock.calls.GetWebhook = nil
+	mock.lockGetWebhook.Unlock()
+
+	mock.lockGetWebhookDeliveries.Lock()
+	mock.calls.GetWebhookDeliveries = nil
+	mock.lockGetWebhookDeliveries.Unlock()
+
 	mock.lockRemoveReminder.Lock()
 	mock.calls.RemoveReminder = nil
 	mock.lockRemoveReminder.Unlock()
 
+	mock.lockRemoveWebhook.Lock()
+	mock.calls.RemoveWebhook = nil
+	mock.lockRemoveWebhook.Unlock()
+
+	mock.lockRescheduleMissedReminders.Lock()
+	mock.calls.RescheduleMissedReminders = nil
+	mock.lockRescheduleMissedReminders.Unlock()
+
+	mock.lockSaveAPIToken.Lock()
+	mock.calls.SaveAPIToken = nil
+	mock.lockSaveAPIToken.Unlock()
+
 	mock.lockSaveBotState.Lock()
 	mock.calls.SaveBotState = nil
 	mock.lockSaveBotState.Unlock()
 
+	mock.lockSaveEmailVerification.Lock()
+	mock.calls.SaveEmailVerification = nil
+	mock.lockSaveEmailVerification.Unlock()
+
 	mock.lockSaveReminder.Lock()
 	mock.calls.SaveReminder = nil
 	mock.lockSaveReminder.Unlock()
@@ -599,6 +1839,22 @@ func (mock *StorageMock) ResetCalls() {
 	mock.calls.SaveUser = nil
 	mock.lockSaveUser.Unlock()
 
+	mock.lockSaveUserSettings.Lock()
+	mock.calls.SaveUserSettings = nil
+	mock.lockSaveUserSettings.Unlock()
+
+	mock.lockSaveWebSession.Lock()
+	mock.calls.SaveWebSession = nil
+	mock.lockSaveWebSession.Unlock()
+
+	mock.lockSaveWebhook.Lock()
+	mock.calls.SaveWebhook = nil
+	mock.lockSaveWebhook.Unlock()
+
+	mock.lockSetReminderLeadTimes.Lock()
+	mock.calls.SetReminderLeadTimes = nil
+	mock.lockSetReminderLeadTimes.Unlock()
+
 	mock.lockSetReminderStatus.Lock()
 	mock.calls.SetReminderStatus = nil
 	mock.lockSetReminderStatus.Unlock()
@@ -606,4 +1862,8 @@ func (mock *StorageMock) ResetCalls() {
 	mock.lockSetUserStatus.Lock()
 	mock.calls.SetUserStatus = nil
 	mock.lockSetUserStatus.Unlock()
+
+	mock.lockToggleChecklistItem.Lock()
+	mock.calls.ToggleChecklistItem = nil
+	mock.lockToggleChecklistItem.Unlock()
 }
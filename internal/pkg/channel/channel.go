@@ -0,0 +1,81 @@
+// Package channel implements delivery of notifications by channels other than Telegram: email, webhooks and push.
+// [Router] dispatches outbox messages to Telegram or to the channel the message was enqueued for.
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var timeNowUTC = func() time.Time {
+	return time.Now().UTC()
+}
+
+// Channel - delivers notification to its recipient, see [domain.Notification.To].
+type Channel interface {
+	Send(ctx context.Context, n domain.Notification) error
+}
+
+// TelegramSender - sender of messages to Telegram.
+type TelegramSender interface {
+	SendOutboxMessage(ctx context.Context, msg domain.OutboxMessage) error
+}
+
+// Router delivers outbox messages by their channels.
+type Router struct {
+	telegram TelegramSender
+	channels map[domain.Channel]Channel
+}
+
+// NewRouter creates new [Router]. Telegram messages are sent by telegram, other messages by channels.
+func NewRouter(telegram TelegramSender, channels map[domain.Channel]Channel) *Router {
+	return &Router{telegram: telegram, channels: channels}
+}
+
+// Channels returns channels messages can be delivered by, Telegram is always available.
+func (r *Router) Channels() domain.Channels {
+	res := domain.Channels{domain.ChannelTelegram}
+	for _, ch := range domain.AllChannels {
+		if _, ok := r.channels[ch]; ok {
+			res = append(res, ch)
+		}
+	}
+
+	return res
+}
+
+// SendOutboxMessage - sends outbox message by its channel, messages without channel are sent to Telegram.
+func (r *Router) SendOutboxMessage(ctx context.Context, msg domain.OutboxMessage) (err error) {
+	if msg.Channel == "" || msg.Channel == domain.ChannelTelegram {
+		return r.telegram.SendOutboxMessage(ctx, msg)
+	}
+
+	ch, ok := r.channels[msg.Channel]
+	if !ok {
+		return fmt.Errorf("channel %s is not configured", msg.Channel)
+	}
+
+	var n domain.Notification
+	if err = json.Unmarshal([]byte(msg.Payload), &n); err != nil {
+		return fmt.Errorf("failed to unmarshal %s notification of outbox message %d: %w", msg.Channel, msg.ID, err)
+	}
+
+	ctx, span := tracing.Start(ctx, "channel."+string(msg.Channel), trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.Int64("reminder.id", n.ReminderID),
+		attribute.Bool("notification.pre_notice", n.PreNotice),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	if err = ch.Send(ctx, n); err != nil {
+		return fmt.Errorf("failed to send %s notification of reminder %d: %w", msg.Channel, n.ReminderID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_SendOutboxMessage(t *testing.T) {
+	t.Parallel()
+
+	notification := domain.Notification{To: "user@example.com", ReminderID: 42, Title: "Напоминание", Message: "Встреча"}
+	payload, err := json.Marshal(notification)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		msg         domain.OutboxMessage
+		sendErr     error
+		expErr      string
+		expTelegram int
+		expEmail    int
+	}{
+		{name: "success: telegram", msg: domain.OutboxMessage{ID: 1, Channel: domain.ChannelTelegram, Payload: "{}"}, expTelegram: 1},
+		{name: "success: message without channel is sent to telegram", msg: domain.OutboxMessage{ID: 1, Payload: "{}"}, expTelegram: 1},
+		{name: "success: email", msg: domain.OutboxMessage{ID: 1, Channel: domain.ChannelEmail, Payload: string(payload)}, expEmail: 1},
+		{
+			name:     "error: channel failed",
+			msg:      domain.OutboxMessage{ID: 1, Channel: domain.ChannelEmail, Payload: string(payload)},
+			sendErr:  errors.New("connection refused"),
+			expErr:   "failed to send email notification of reminder 42: connection refused",
+			expEmail: 1,
+		},
+		{
+			name:   "error: channel is not configured",
+			msg:    domain.OutboxMessage{ID: 1, Channel: domain.ChannelPush, Payload: string(payload)},
+			expErr: "channel push is not configured",
+		},
+		{
+			name:   "error: invalid payload",
+			msg:    domain.OutboxMessage{ID: 1, Channel: domain.ChannelEmail, Payload: "foo"},
+			expErr: "failed to unmarshal email notification of outbox message 1: invalid character 'o' in literal false (expecting 'a')",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			telegramMock := &TelegramSenderMock{
+				SendOutboxMessageFunc: func(ctx context.Context, msg domain.OutboxMessage) error {
+					assert.Equal(t, tc.msg, msg)
+					return nil
+				},
+			}
+			emailMock := &ChannelMock{
+				SendFunc: func(ctx context.Context, n domain.Notification) error {
+					assert.Equal(t, notification, n)
+					return tc.sendErr
+				},
+			}
+
+			router := NewRouter(telegramMock, map[domain.Channel]Channel{domain.ChannelEmail: emailMock})
+			err := router.SendOutboxMessage(context.TODO(), tc.msg)
+
+			if tc.expErr != "" {
+				require.EqualError(t, err, tc.expErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Len(t, telegramMock.SendOutboxMessageCalls(), tc.expTelegram)
+			assert.Len(t, emailMock.SendCalls(), tc.expEmail)
+		})
+	}
+}
+
+func TestRouter_Channels(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, domain.Channels{domain.ChannelTelegram}, NewRouter(nil, nil).Channels())
+
+	router := NewRouter(nil, map[domain.Channel]Channel{
+		domain.ChannelPush:    &ChannelMock{},
+		domain.ChannelWebhook: &ChannelMock{},
+	})
+	assert.Equal(t, domain.Channels{domain.ChannelTelegram, domain.ChannelWebhook, domain.ChannelPush}, router.Channels())
+}
@@ -0,0 +1,128 @@
+package channel
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+)
+
+// EmailConfig - SMTP server configuration.
+type EmailConfig struct {
+	Addr     string        // SMTP server address, host:port
+	Username string        // username for PLAIN auth, no auth if empty
+	Password string        // password for PLAIN auth
+	From     string        // sender address
+	Timeout  time.Duration // max time to send one email
+}
+
+// Email sends notifications as plain text emails via SMTP. STARTTLS is used if server supports it.
+type Email struct {
+	cfg EmailConfig
+}
+
+// NewEmail creates new [Email].
+func NewEmail(cfg EmailConfig) *Email {
+	return &Email{cfg: cfg}
+}
+
+// Send sends notification to its email address.
+func (e *Email) Send(ctx context.Context, n domain.Notification) error {
+	host, _, err := net.SplitHostPort(e.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid smtp address %q: %w", e.cfg.Addr, err)
+	}
+
+	if e.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.cfg.Timeout)
+		defer cancel()
+	}
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", e.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer c.Close()
+
+	if err = e.send(c, host, n); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// send sends email in smtp session, the same way [smtp.SendMail] does.
+func (e *Email) send(c *smtp.Client, host string, n domain.Notification) error {
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+
+	if e.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(e.cfg.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(n.To); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(e.message(n, timeNowUTC())); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+// message renders email of notification, body is base64 encoded to keep lines short.
+func (e *Email) message(n domain.Notification, now time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", e.cfg.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", n.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", n.Title))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+	buf.WriteString("\r\n")
+
+	body := base64.StdEncoding.EncodeToString([]byte(n.Message))
+	for len(body) > 76 {
+		buf.WriteString(body[:76])
+		buf.WriteString("\r\n")
+		body = body[76:]
+	}
+	buf.WriteString(body)
+	buf.WriteString("\r\n")
+
+	return buf.Bytes()
+}
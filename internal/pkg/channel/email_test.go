@@ -0,0 +1,178 @@
+package channel
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"mime"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmail_Send(t *testing.T) {
+	t.Parallel()
+
+	n := domain.Notification{To: "user@example.com", ReminderID: 1, Title: "Напоминание", Message: "Встреча\n\nСегодня в 15:30"}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newFakeSMTPServer(t, nil)
+
+		err := NewEmail(EmailConfig{Addr: srv.addr(), From: "bot@example.com", Timeout: time.Second}).Send(context.TODO(), n)
+
+		require.NoError(t, err)
+		srv.wait()
+		assert.Equal(t, []string{"EHLO localhost", "MAIL FROM:<bot@example.com> BODY=8BITMIME", "RCPT TO:<user@example.com>", "DATA", "QUIT"}, srv.commands)
+
+		headers, body, ok := strings.Cut(srv.data, "\r\n\r\n")
+		require.True(t, ok)
+		assert.Contains(t, headers, "From: bot@example.com\r\n")
+		assert.Contains(t, headers, "To: user@example.com\r\n")
+		msg, err := mail.ReadMessage(strings.NewReader(srv.data))
+		require.NoError(t, err)
+		subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+		require.NoError(t, err)
+		assert.Equal(t, "Напоминание", subject)
+		assert.Contains(t, headers, "Content-Type: text/plain; charset=utf-8\r\n")
+
+		decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(body, "\r\n", ""))
+		require.NoError(t, err)
+		assert.Equal(t, n.Message, string(decoded))
+	})
+
+	t.Run("success: plain auth", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newFakeSMTPServer(t, nil)
+
+		err := NewEmail(EmailConfig{Addr: srv.addr(), Username: "bot", Password: "secret", From: "bot@example.com", Timeout: time.Second}).Send(context.TODO(), n)
+
+		require.NoError(t, err)
+		srv.wait()
+		assert.Contains(t, srv.commands, "AUTH PLAIN "+base64.StdEncoding.EncodeToString([]byte("\x00bot\x00secret")))
+	})
+
+	t.Run("error: recipient is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		srv := newFakeSMTPServer(t, map[string]string{"RCPT": "550 mailbox unavailable"})
+
+		err := NewEmail(EmailConfig{Addr: srv.addr(), From: "bot@example.com", Timeout: time.Second}).Send(context.TODO(), n)
+
+		require.EqualError(t, err, `failed to send email: 550 "mailbox unavailable"`)
+	})
+
+	t.Run("error: server is not available", func(t *testing.T) {
+		t.Parallel()
+
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := l.Addr().String()
+		require.NoError(t, l.Close())
+
+		err = NewEmail(EmailConfig{Addr: addr, From: "bot@example.com", Timeout: time.Second}).Send(context.TODO(), n)
+
+		require.ErrorContains(t, err, "failed to connect to smtp server")
+	})
+}
+
+// fakeSMTPServer - local stand-in of SMTP server, accepts one session and records its commands and data.
+// Replies can be overridden by command name.
+type fakeSMTPServer struct {
+	t        *testing.T
+	l        net.Listener
+	replies  map[string]string
+	done     sync.WaitGroup
+	commands []string
+	data     string
+}
+
+func newFakeSMTPServer(t *testing.T, replies map[string]string) *fakeSMTPServer {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	srv := &fakeSMTPServer{t: t, l: l, replies: replies}
+	srv.done.Add(1)
+	go srv.serve()
+
+	return srv
+}
+
+func (s *fakeSMTPServer) addr() string {
+	return s.l.Addr().String()
+}
+
+// wait waits for the session to finish, commands and data are available after it.
+func (s *fakeSMTPServer) wait() {
+	s.done.Wait()
+}
+
+func (s *fakeSMTPServer) serve() {
+	defer s.done.Done()
+
+	conn, err := s.l.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		s.commands = append(s.commands, line)
+
+		name, _, _ := strings.Cut(line, " ")
+		if custom, ok := s.replies[name]; ok {
+			reply(custom)
+			continue
+		}
+
+		switch name {
+		case "EHLO":
+			reply("250-localhost")
+			reply("250-8BITMIME")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			reply("235 2.7.0 Authentication successful")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.data = data.String()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
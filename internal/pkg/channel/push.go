@@ -0,0 +1,65 @@
+package channel
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/delivery"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+)
+
+// Push publishes notifications to user's topic of ntfy-style server: message is the body of POST request
+// to server URL joined with topic, title, priority and tags are passed in headers.
+// Non-ASCII title is encoded according to RFC 2047, ntfy decodes it.
+type Push struct {
+	serverURL string
+	token     string
+	client    *http.Client
+}
+
+// NewPush creates new [Push]. Token is sent as bearer token if set, timeout limits every request.
+func NewPush(serverURL, token string, timeout time.Duration) *Push {
+	return &Push{serverURL: strings.TrimSuffix(serverURL, "/"), token: token, client: &http.Client{Timeout: timeout}}
+}
+
+// Send publishes notification to its topic, any response status except 2xx is an error, see [delivery.Post].
+func (p *Push) Send(ctx context.Context, n domain.Notification) error {
+	header := http.Header{}
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+	header.Set("Title", mime.BEncoding.Encode("utf-8", n.Title))
+	header.Set("Priority", pushPriority(n.Priority))
+	header.Set("Tags", "alarm_clock")
+	if p.token != "" {
+		header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	_, err := delivery.Post(ctx, p.client, delivery.Request{
+		URL:    p.serverURL + "/" + url.PathEscape(n.To),
+		Body:   []byte(n.Message),
+		Header: header,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish push: %w", err)
+	}
+
+	return nil
+}
+
+// pushPriority returns ntfy priority of reminder priority, from 1 (min) to 5 (max).
+func pushPriority(p domain.ReminderPriority) string {
+	switch p {
+	case domain.ReminderPriorityLow:
+		return "2"
+	case domain.ReminderPriorityHigh:
+		return "4"
+	case domain.ReminderPriorityCritical:
+		return "5"
+	default:
+		return "3"
+	}
+}
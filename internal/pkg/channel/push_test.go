@@ -0,0 +1,73 @@
+package channel
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPush_Send(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		token       string
+		priority    domain.ReminderPriority
+		status      int
+		expPriority string
+		expAuth     string
+		expErr      string
+	}{
+		{name: "success", priority: domain.ReminderPriorityNormal, status: http.StatusOK, expPriority: "3"},
+		{name: "success: critical priority with token", token: "tk_secret", priority: domain.ReminderPriorityCritical, status: http.StatusOK, expPriority: "5", expAuth: "Bearer tk_secret"},
+		{name: "success: low priority", priority: domain.ReminderPriorityLow, status: http.StatusOK, expPriority: "2"},
+		{name: "error: forbidden", priority: domain.ReminderPriorityHigh, status: http.StatusForbidden, expPriority: "4", expErr: "failed to publish push: message is rejected: unexpected response status 403: forbidden"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/my_reminders", r.URL.Path)
+				assert.Equal(t, tc.expPriority, r.Header.Get("Priority"))
+				assert.Equal(t, tc.expAuth, r.Header.Get("Authorization"))
+
+				title, err := new(mime.WordDecoder).DecodeHeader(r.Header.Get("Title"))
+				assert.NoError(t, err)
+				assert.Equal(t, "Напоминание", title)
+
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, "Встреча\n\nСегодня в 15:30", string(body))
+
+				if tc.status != http.StatusOK {
+					http.Error(w, "forbidden", tc.status)
+				}
+			}))
+			defer srv.Close()
+
+			err := NewPush(srv.URL+"/", tc.token, time.Second).Send(context.TODO(), domain.Notification{
+				To:       "my_reminders",
+				Title:    "Напоминание",
+				Message:  "Встреча\n\nСегодня в 15:30",
+				Priority: tc.priority,
+			})
+
+			if tc.expErr != "" {
+				require.EqualError(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
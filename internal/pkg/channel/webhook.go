@@ -0,0 +1,70 @@
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/delivery"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+)
+
+// webhook events
+const (
+	eventReminder  = "reminder"
+	eventPreNotice = "pre_notice"
+)
+
+// WebhookPayload - body of webhook request.
+type WebhookPayload struct {
+	Event      string                  `json:"event"`
+	ReminderID int64                   `json:"reminder_id"`
+	UserID     int64                   `json:"user_id"`
+	ChatID     int64                   `json:"chat_id"`
+	Title      string                  `json:"title"`
+	Message    string                  `json:"message"`
+	Priority   domain.ReminderPriority `json:"priority"`
+	RemindAt   time.Time               `json:"remind_at"`
+}
+
+// Webhook sends notifications as signed JSON POST requests to user's URL.
+type Webhook struct {
+	client *http.Client
+}
+
+// NewWebhook creates new [Webhook], timeout limits every request.
+// URL is set by user, so requests to addresses of the bot's own network are refused, see [delivery.NewClient].
+func NewWebhook(timeout time.Duration) *Webhook {
+	return &Webhook{client: delivery.NewClient(timeout)}
+}
+
+// Send posts notification to its URL signed with its secret, any response status except 2xx is an error,
+// see [delivery.Post].
+func (w *Webhook) Send(ctx context.Context, n domain.Notification) error {
+	payload := WebhookPayload{
+		Event:      eventReminder,
+		ReminderID: n.ReminderID,
+		UserID:     n.UserID,
+		ChatID:     n.ChatID,
+		Title:      n.Title,
+		Message:    n.Message,
+		Priority:   n.Priority,
+		RemindAt:   n.RemindAt,
+	}
+	if n.PreNotice {
+		payload.Event = eventPreNotice
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	if _, err = delivery.Post(ctx, w.client, delivery.Request{URL: n.To, Body: body, Secret: n.Secret}); err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+
+	return nil
+}
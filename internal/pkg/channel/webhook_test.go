@@ -0,0 +1,120 @@
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/delivery"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/sender"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhook_Send(t *testing.T) {
+	t.Parallel()
+
+	remindAt := time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		var payload WebhookPayload
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/hook", r.URL.Path)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "tg-reminder", r.Header.Get("User-Agent"))
+
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(body, &payload))
+
+			ts, err := strconv.ParseInt(r.Header.Get(delivery.HeaderTimestamp), 10, 64)
+			assert.NoError(t, err)
+			expSignature := domain.SignWebhookPayload("whsec_test", time.Unix(ts, 0), body)
+			assert.Equal(t, "sha256="+expSignature, r.Header.Get(delivery.HeaderSignature))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		err := newTestWebhook(time.Second).Send(context.TODO(), domain.Notification{
+			To:         srv.URL + "/hook",
+			ReminderID: 1,
+			UserID:     2,
+			ChatID:     3,
+			Title:      "Скоро напоминание",
+			Message:    "Встреча",
+			Priority:   domain.ReminderPriorityHigh,
+			RemindAt:   remindAt,
+			PreNotice:  true,
+			Secret:     "whsec_test",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, WebhookPayload{
+			Event:      "pre_notice",
+			ReminderID: 1,
+			UserID:     2,
+			ChatID:     3,
+			Title:      "Скоро напоминание",
+			Message:    "Встреча",
+			Priority:   domain.ReminderPriorityHigh,
+			RemindAt:   remindAt,
+		}, payload)
+	})
+
+	t.Run("error: unexpected status", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		err := newTestWebhook(time.Second).Send(context.TODO(), domain.Notification{To: srv.URL})
+
+		require.EqualError(t, err, "failed to post webhook: unexpected response status 500: boom")
+	})
+
+	t.Run("error: too many requests", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer srv.Close()
+
+		err := newTestWebhook(time.Second).Send(context.TODO(), domain.Notification{To: srv.URL})
+
+		var retryErr *sender.RetryAfterError
+		require.True(t, errors.As(err, &retryErr))
+		assert.Equal(t, 30*time.Second, retryErr.After)
+	})
+
+	t.Run("error: timeout", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}))
+		defer srv.Close()
+
+		err := newTestWebhook(50*time.Millisecond).Send(context.TODO(), domain.Notification{To: srv.URL})
+
+		require.ErrorContains(t, err, "failed to post webhook")
+	})
+}
+
+// newTestWebhook returns webhook which is allowed to post to local test servers.
+func newTestWebhook(timeout time.Duration) *Webhook {
+	return &Webhook{client: &http.Client{Timeout: timeout}}
+}
@@ -0,0 +1,241 @@
+// Package cli implements subcommands for operators which work with the database of the bot directly,
+// e.g. to find a reminder of a user or to unstick a dialog, without hand-written SQL against the database file.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/storage"
+)
+
+var timeNowUTC = func() time.Time {
+	return time.Now().UTC()
+}
+
+// Storage - storage of the bot.
+type Storage interface {
+	GetUser(ctx context.Context, id int64) (domain.User, error)
+	GetUsers(ctx context.Context, limit int64) ([]domain.User, error)
+	GetUsersByStatus(ctx context.Context, status domain.UserStatus) ([]domain.User, error)
+	SetUserStatus(ctx context.Context, id int64, status domain.UserStatus, msgs ...domain.OutboxMessage) error
+
+	FindReminders(ctx context.Context, filter storage.ReminderFilter) ([]domain.Reminder, error)
+	GetReminder(ctx context.Context, id int64) (domain.Reminder, error)
+	GetReminderEvents(ctx context.Context, reminderID int64) ([]domain.ReminderEvent, error)
+	DelayReminder(ctx context.Context, id int64, remindAt time.Time, msgs ...domain.OutboxMessage) error
+	RemoveReminder(ctx context.Context, id int64, msgs ...domain.OutboxMessage) error
+
+	SaveBotState(ctx context.Context, state domain.BotState, msgs ...domain.OutboxMessage) error
+
+	GetWebhooks(ctx context.Context) ([]domain.Webhook, error)
+	GetUserWebhooks(ctx context.Context, userID int64) ([]domain.Webhook, error)
+	GetWebhook(ctx context.Context, id int64) (domain.Webhook, error)
+	SaveWebhook(ctx context.Context, hook domain.Webhook, newMsgs func(id int64) ([]domain.OutboxMessage, error)) (int64, error)
+	RemoveWebhook(ctx context.Context, id int64, msgs ...domain.OutboxMessage) error
+	GetWebhookDeliveries(ctx context.Context, webhookID int64, limit int64) ([]domain.WebhookDelivery, error)
+
+	GetStats(ctx context.Context) (domain.Stats, error)
+	GetDBStats(ctx context.Context) (domain.DBStats, error)
+}
+
+// Opener opens storage of database file, returned function closes it. Empty migrationsDir means the default one.
+type Opener func(dbFile, migrationsDir string) (Storage, func() error, error)
+
+// Format - output format of subcommands.
+type Format string
+
+const (
+	// FormatTable - human-readable table.
+	FormatTable Format = "table"
+	// FormatJSON - JSON for scripts.
+	FormatJSON Format = "json"
+)
+
+// ParseFormat parses output format. Empty string is parsed as [FormatTable].
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatTable:
+		return FormatTable, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q", s)
+	}
+}
+
+// command - subcommand, run parses args of subcommand and runs it.
+type command struct {
+	usage string
+	run   func(c *CLI, ctx context.Context, args []string) error
+}
+
+var commands = map[string]command{
+	"users list":           {usage: "users list [--status active|inactive|blocked] [--limit n]", run: (*CLI).usersList},
+	"users disable":        {usage: "users disable <user id>", run: (*CLI).usersDisable},
+	"users enable":         {usage: "users enable <user id>", run: (*CLI).usersEnable},
+	"reminders list":       {usage: "reminders list [--user id] [--chat id] [--status status] [--limit n]", run: (*CLI).remindersList},
+	"reminders show":       {usage: "reminders show <reminder id>", run: (*CLI).remindersShow},
+	"reminders reschedule": {usage: "reminders reschedule <reminder id> <RFC 3339 time or duration from now, e.g. 2h>", run: (*CLI).remindersReschedule},
+	"reminders delete":     {usage: "reminders delete <reminder id>", run: (*CLI).remindersDelete},
+	"bot-state reset":      {usage: "bot-state reset <user id>", run: (*CLI).botStateReset},
+	"webhooks list":        {usage: "webhooks list [--user id]", run: (*CLI).webhooksList},
+	"webhooks add":         {usage: "webhooks add <url> [--user id] [--events list]", run: (*CLI).webhooksAdd},
+	"webhooks remove":      {usage: "webhooks remove <webhook id>", run: (*CLI).webhooksRemove},
+	"webhooks deliveries":  {usage: "webhooks deliveries <webhook id> [--limit n]", run: (*CLI).webhooksDeliveries},
+	"db stats":             {usage: "db stats", run: (*CLI).dbStats},
+}
+
+// IsCommand reports whether name is a group of subcommands handled by [Run], e.g. "users".
+func IsCommand(name string) bool {
+	for key := range commands {
+		if group, _, _ := strings.Cut(key, " "); group == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Usage returns usage of all subcommands.
+func Usage() string {
+	usages := make([]string, 0, len(commands))
+	for _, c := range commands {
+		usages = append(usages, "  tg-reminder "+c.usage+" [--db-file path] [--migrations dir] [--format table|json]")
+	}
+	sort.Strings(usages)
+
+	return "usage:\n" + strings.Join(usages, "\n")
+}
+
+// CLI - runner of subcommands.
+type CLI struct {
+	getenv func(string) string
+	open   Opener
+	out    io.Writer
+	usage  string
+
+	store  Storage
+	close  func() error
+	format Format
+}
+
+// Run runs subcommand, args start with the name of subcommand, e.g. "users list --status active".
+// Database file and migrations directory are taken from DB_FILE and MIGRATIONS env variables unless flags are set.
+func Run(ctx context.Context, args []string, getenv func(string) string, open Opener, out io.Writer) (err error) {
+	if len(args) < 2 {
+		return errors.New(Usage())
+	}
+
+	cmd, ok := commands[args[0]+" "+args[1]]
+	if !ok {
+		return fmt.Errorf("unknown command %q\n%s", args[0]+" "+args[1], Usage())
+	}
+
+	c := &CLI{getenv: getenv, open: open, out: out, usage: cmd.usage}
+	defer func() {
+		if c.close != nil {
+			err = errors.Join(err, c.close())
+		}
+	}()
+
+	// changes made by subcommands are recorded in history of reminders as made by operator
+	ctx = domain.ContextWithActor(ctx, domain.Actor{Type: domain.ActorOperator})
+
+	return cmd.run(c, ctx, args[2:])
+}
+
+// flagSet returns flag set of subcommand with common flags.
+func (c *CLI) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.out)
+	fs.String("db-file", c.getenv("DB_FILE"), "database file path (env DB_FILE)")
+	fs.String("migrations", c.getenv("MIGRATIONS"), "migration directory for goose (env MIGRATIONS)")
+	fs.String("format", string(FormatTable), "output format: table or json")
+
+	return fs
+}
+
+// parse parses args of subcommand and opens storage. Flags are allowed after positional args,
+// exactly nArgs positional args are expected.
+func (c *CLI) parse(fs *flag.FlagSet, args []string, nArgs int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(positional) != nArgs {
+		return nil, fmt.Errorf("usage: tg-reminder %s", c.usage)
+	}
+
+	var err error
+	if c.format, err = ParseFormat(fs.Lookup("format").Value.String()); err != nil {
+		return nil, err
+	}
+
+	dbFile, migrations := fs.Lookup("db-file").Value.String(), fs.Lookup("migrations").Value.String()
+	if dbFile == "" {
+		return nil, errors.New("database file is required, set DB_FILE env variable or --db-file flag")
+	}
+
+	if c.store, c.close, err = c.open(dbFile, migrations); err != nil {
+		return nil, err
+	}
+
+	return positional, nil
+}
+
+// print writes v as JSON or as table written by table.
+func (c *CLI) print(v any, table func(w io.Writer)) error {
+	if c.format == FormatJSON {
+		enc := json.NewEncoder(c.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	tw := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	table(tw)
+
+	return tw.Flush()
+}
+
+// printf writes message for table format only, it's a confirmation of change for a human.
+func (c *CLI) printf(format string, args ...any) {
+	if c.format == FormatTable {
+		fmt.Fprintf(c.out, format, args...)
+	}
+}
+
+func parseID(s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("invalid id %q", s)
+	}
+
+	return id, nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
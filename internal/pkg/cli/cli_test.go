@@ -0,0 +1,499 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nolint:paralleltest // test modifies package level functions timeNowUTC and newWebhookSecret.
+func TestRun(t *testing.T) {
+	var (
+		now      = time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+		remindAt = now.Add(1 * time.Hour)
+		user     = domain.User{ID: 1, Name: "bob", Status: domain.UserStatusActive, CreatedAt: now, ModifiedAt: now}
+		reminder = domain.Reminder{
+			ID:           42,
+			ChatID:       2,
+			UserID:       1,
+			Text:         "buy milk",
+			CreatedAt:    now,
+			ModifiedAt:   now,
+			RemindAt:     remindAt,
+			Status:       domain.ReminderStatusPending,
+			AttemptsLeft: domain.DefaultAttemptsLeft,
+			Priority:     domain.ReminderPriorityNormal,
+		}
+		reminderJSON = `{"id":42,"user_id":1,"chat_id":2,"text":"buy milk","remind_at":"2024-05-01T11:00:00Z","status":"pending",` +
+			`"priority":"normal","attempts_left":10,"created_at":"2024-05-01T10:00:00Z","modified_at":"2024-05-01T10:00:00Z"}`
+	)
+
+	getReminder := func(_ context.Context, id int64) (domain.Reminder, error) {
+		if id != reminder.ID {
+			return domain.Reminder{}, storage.ErrReminderNotFound
+		}
+		return reminder, nil
+	}
+
+	testCases := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		setMocks func(a *assert.Assertions, store *StorageMock)
+		expOut   string
+		expJSON  string
+		expErr   string
+	}{
+		{
+			name:   "error: no subcommand",
+			args:   []string{"users"},
+			expErr: "usage:",
+		},
+		{
+			name:   "error: unknown subcommand",
+			args:   []string{"users", "remove", "1"},
+			expErr: `unknown command "users remove"`,
+		},
+		{
+			name:   "error: db file isn't set",
+			args:   []string{"users", "list"},
+			env:    map[string]string{},
+			expErr: "database file is required, set DB_FILE env variable or --db-file flag",
+		},
+		{
+			name:   "error: wrong number of args",
+			args:   []string{"reminders", "show"},
+			expErr: "usage: tg-reminder reminders show <reminder id>",
+		},
+		{
+			name:   "error: invalid id",
+			args:   []string{"reminders", "show", "abc"},
+			expErr: `invalid id "abc"`,
+		},
+		{
+			name:   "error: unknown format",
+			args:   []string{"db", "stats", "--format", "xml"},
+			expErr: `unknown output format "xml"`,
+		},
+		{
+			name: "success: users list",
+			args: []string{"users", "list", "--limit", "10"},
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetUsersFunc = func(_ context.Context, limit int64) ([]domain.User, error) {
+					a.EqualValues(10, limit)
+					return []domain.User{user}, nil
+				}
+			},
+			expOut: "ID  NAME  STATUS  CREATED AT\n" +
+				"1   bob   active  2024-05-01T10:00:00Z\n",
+		},
+		{
+			name: "success: users list by status, json",
+			args: []string{"users", "list", "--status", "active", "--limit", "1", "--format", "json"},
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetUsersByStatusFunc = func(_ context.Context, status domain.UserStatus) ([]domain.User, error) {
+					a.Equal(domain.UserStatusActive, status)
+					return []domain.User{user, {ID: 3, Status: domain.UserStatusActive}}, nil
+				}
+			},
+			expJSON: `[{"id":1,"name":"bob","status":"active","created_at":"2024-05-01T10:00:00Z","modified_at":"2024-05-01T10:00:00Z"}]`,
+		},
+		{
+			name:   "error: users list, unknown status",
+			args:   []string{"users", "list", "--status", "deleted"},
+			expErr: `unknown user status "deleted"`,
+		},
+		{
+			name: "success: users disable",
+			args: []string{"users", "disable", "1"},
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.SetUserStatusFunc = func(_ context.Context, id int64, status domain.UserStatus, _ ...domain.OutboxMessage) error {
+					a.EqualValues(1, id)
+					a.Equal(domain.UserStatusBlocked, status)
+					return nil
+				}
+				store.GetUserFunc = func(context.Context, int64) (domain.User, error) {
+					u := user
+					u.Status = domain.UserStatusBlocked
+					return u, nil
+				}
+			},
+			expOut: "user 1 is blocked\n\n" +
+				"ID  NAME  STATUS   CREATED AT\n" +
+				"1   bob   blocked  2024-05-01T10:00:00Z\n",
+		},
+		{
+			name: "error: users enable, user not found",
+			args: []string{"users", "enable", "5"},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.SetUserStatusFunc = func(context.Context, int64, domain.UserStatus, ...domain.OutboxMessage) error {
+					return storage.ErrUserNotFound
+				}
+			},
+			expErr: storage.ErrUserNotFound.Error(),
+		},
+		{
+			name: "success: reminders list with filters, flags after positional args are allowed",
+			args: []string{"reminders", "list", "--user", "1", "--chat", "2", "--status", "pending", "--format", "json"},
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.FindRemindersFunc = func(_ context.Context, filter storage.ReminderFilter) ([]domain.Reminder, error) {
+					a.Equal(storage.ReminderFilter{UserID: 1, ChatID: 2, Status: domain.ReminderStatusPending, Limit: defaultLimit}, filter)
+					return []domain.Reminder{reminder}, nil
+				}
+			},
+			expJSON: `[` + reminderJSON + `]`,
+		},
+		{
+			name: "success: reminders list, long text is cut",
+			args: []string{"reminders", "list"},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.FindRemindersFunc = func(context.Context, storage.ReminderFilter) ([]domain.Reminder, error) {
+					r := reminder
+					r.Text = "call\nmom and ask her about the recipe of the apple pie"
+					return []domain.Reminder{r}, nil
+				}
+			},
+			expOut: "ID  USER  CHAT  STATUS   REMIND AT             TEXT\n" +
+				"42  1     2     pending  2024-05-01T11:00:00Z  call mom and ask her about the recipe o…\n",
+		},
+		{
+			name: "success: reminders show",
+			args: []string{"reminders", "show", "42", "--format", "json"},
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+				store.GetReminderEventsFunc = func(_ context.Context, reminderID int64) ([]domain.ReminderEvent, error) {
+					a.EqualValues(42, reminderID)
+					return []domain.ReminderEvent{
+						{Type: domain.ReminderEventCreated, ActorType: domain.ActorUser, ActorID: 1, RemindAtTo: &remindAt, CreatedAt: now},
+					}, nil
+				}
+			},
+			expJSON: `{"reminder":` + reminderJSON + `,"history":[{"type":"created","actor":"user","actor_id":1,` +
+				`"remind_at_to":"2024-05-01T11:00:00Z","created_at":"2024-05-01T10:00:00Z"}]}`,
+		},
+		{
+			name: "error: reminders show, not found",
+			args: []string{"reminders", "show", "1"},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+			},
+			expErr: storage.ErrReminderNotFound.Error(),
+		},
+		{
+			name: "success: reminders reschedule by duration",
+			args: []string{"reminders", "reschedule", "42", "2h", "--format", "json"},
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+				store.DelayReminderFunc = func(ctx context.Context, id int64, actRemindAt time.Time, _ ...domain.OutboxMessage) error {
+					a.EqualValues(42, id)
+					a.Equal(now.Add(2*time.Hour), actRemindAt)
+					a.Equal(domain.Actor{Type: domain.ActorOperator}, domain.ActorFromContext(ctx))
+					return nil
+				}
+			},
+			expJSON: reminderJSON,
+		},
+		{
+			name: "success: reminders reschedule by time",
+			args: []string{"reminders", "reschedule", "42", "2024-05-02T09:00:00+03:00"},
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+				store.DelayReminderFunc = func(_ context.Context, _ int64, actRemindAt time.Time, _ ...domain.OutboxMessage) error {
+					a.Equal(time.Date(2024, time.May, 2, 6, 0, 0, 0, time.UTC), actRemindAt)
+					return nil
+				}
+			},
+			expOut: "reminder 42 is rescheduled to 2024-05-01T11:00:00Z\n\n" +
+				"ID:             42\n" +
+				"User:           1\n" +
+				"Chat:           2\n" +
+				"Text:           buy milk\n" +
+				"Remind at:      2024-05-01T11:00:00Z\n" +
+				"Status:         pending\n" +
+				"Priority:       normal\n" +
+				"Attempts left:  10\n" +
+				"Created at:     2024-05-01T10:00:00Z\n" +
+				"Modified at:    2024-05-01T10:00:00Z\n",
+		},
+		{
+			name: "error: reminders reschedule to the past",
+			args: []string{"reminders", "reschedule", "42", "2024-05-01T09:00:00Z"},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+			},
+			expErr: domain.ErrRemindAtInPast.Error(),
+		},
+		{
+			name: "error: reminders reschedule, invalid time",
+			args: []string{"reminders", "reschedule", "42", "tomorrow"},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+			},
+			expErr: `invalid remind time "tomorrow", RFC 3339 time or duration is expected`,
+		},
+		{
+			name: "error: reminders reschedule, reminder is done",
+			args: []string{"reminders", "reschedule", "42", "2h"},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = func(context.Context, int64) (domain.Reminder, error) {
+					r := reminder
+					r.Status = domain.ReminderStatusDone
+					return r, nil
+				}
+			},
+			expErr: "reminder 42 with status done can't be rescheduled",
+		},
+		{
+			name: "success: reminders delete",
+			args: []string{"reminders", "delete", "42", "--format", "json"},
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+				store.RemoveReminderFunc = func(ctx context.Context, id int64, _ ...domain.OutboxMessage) error {
+					a.EqualValues(42, id)
+					a.Equal(domain.Actor{Type: domain.ActorOperator}, domain.ActorFromContext(ctx))
+					return nil
+				}
+			},
+			expJSON: reminderJSON,
+		},
+		{
+			name: "error: reminders delete, failed to remove",
+			args: []string{"reminders", "delete", "42"},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+				store.RemoveReminderFunc = func(context.Context, int64, ...domain.OutboxMessage) error {
+					return errors.New("db error")
+				}
+			},
+			expErr: "db error",
+		},
+		{
+			name: "success: bot-state reset",
+			args: []string{"bot-state", "reset", "1"},
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetUserFunc = func(context.Context, int64) (domain.User, error) {
+					return user, nil
+				}
+				store.SaveBotStateFunc = func(_ context.Context, state domain.BotState, _ ...domain.OutboxMessage) error {
+					a.Equal(domain.BotState{UserID: 1, Name: domain.BotStateNameStart}, state)
+					return nil
+				}
+			},
+			expOut: "bot state of user 1 is reset\n\n" +
+				"User:   1\n" +
+				"State:  " + string(domain.BotStateNameStart) + "\n",
+		},
+		{
+			name: "error: bot-state reset, user not found",
+			args: []string{"bot-state", "reset", "5"},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetUserFunc = func(context.Context, int64) (domain.User, error) {
+					return domain.User{}, storage.ErrUserNotFound
+				}
+			},
+			expErr: storage.ErrUserNotFound.Error(),
+		},
+		{
+			name: "success: webhooks list",
+			args: []string{"webhooks", "list"},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetWebhooksFunc = func(context.Context) ([]domain.Webhook, error) {
+					return []domain.Webhook{
+						{ID: 1, URL: "https://example.com/all", CreatedAt: now},
+						{ID: 2, UserID: 1, URL: "https://example.com/bob", Events: domain.WebhookEvents{domain.WebhookEventCompleted}, CreatedAt: now},
+					}, nil
+				}
+			},
+			expOut: "ID  USER  URL                      EVENTS              CREATED AT\n" +
+				"1   all   https://example.com/all  all                 2024-05-01T10:00:00Z\n" +
+				"2   1     https://example.com/bob  reminder.completed  2024-05-01T10:00:00Z\n",
+		},
+		{
+			name: "success: webhooks list of user, json",
+			args: []string{"webhooks", "list", "--user", "1", "--format", "json"},
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetUserWebhooksFunc = func(_ context.Context, userID int64) ([]domain.Webhook, error) {
+					a.EqualValues(1, userID)
+					return []domain.Webhook{{ID: 2, UserID: 1, URL: "https://example.com/bob", Secret: "whsec_bob", CreatedAt: now}}, nil
+				}
+			},
+			expJSON: `[{"id":2,"user_id":1,"url":"https://example.com/bob","created_at":"2024-05-01T10:00:00Z"}]`,
+		},
+		{
+			name: "success: webhooks add for all users",
+			args: []string{"webhooks", "add", "https://example.com/hook", "--events", "fired,exhausted", "--format", "json"},
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.SaveWebhookFunc = func(_ context.Context, hook domain.Webhook, _ func(id int64) ([]domain.OutboxMessage, error)) (int64, error) {
+					a.Equal(domain.Webhook{
+						URL:    "https://example.com/hook",
+						Secret: "whsec_0123456789abcdef",
+						Events: domain.WebhookEvents{domain.WebhookEventFired, domain.WebhookEventExhausted},
+					}, hook)
+					return 3, nil
+				}
+			},
+			expJSON: `{"id":3,"user_id":0,"url":"https://example.com/hook","events":["reminder.fired","reminder.exhausted"],` +
+				`"created_at":"0001-01-01T00:00:00Z","secret":"whsec_0123456789abcdef"}`,
+		},
+		{
+			name: "success: webhooks add for user",
+			args: []string{"webhooks", "add", "https://example.com/hook", "--user", "1"},
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetUserFunc = func(_ context.Context, id int64) (domain.User, error) {
+					a.EqualValues(1, id)
+					return user, nil
+				}
+				store.SaveWebhookFunc = func(_ context.Context, hook domain.Webhook, _ func(id int64) ([]domain.OutboxMessage, error)) (int64, error) {
+					a.EqualValues(1, hook.UserID)
+					return 3, nil
+				}
+			},
+			expOut: "webhook 3 is added, the secret is shown once\n\n" +
+				"ID:      3\n" +
+				"User:    1\n" +
+				"URL:     https://example.com/hook\n" +
+				"Events:  all\n" +
+				"Secret:  whsec_0123456789abcdef\n",
+		},
+		{
+			name:   "error: webhooks add, invalid url",
+			args:   []string{"webhooks", "add", "example.com"},
+			expErr: `invalid webhook url "example.com"`,
+		},
+		{
+			name:   "error: webhooks add, unknown event",
+			args:   []string{"webhooks", "add", "https://example.com/hook", "--events", "deleted"},
+			expErr: `unknown webhook event "deleted"`,
+		},
+		{
+			name: "success: webhooks remove",
+			args: []string{"webhooks", "remove", "3"},
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetWebhookFunc = func(_ context.Context, id int64) (domain.Webhook, error) {
+					return domain.Webhook{ID: id, URL: "https://example.com/hook", CreatedAt: now}, nil
+				}
+				store.RemoveWebhookFunc = func(_ context.Context, id int64, _ ...domain.OutboxMessage) error {
+					a.EqualValues(3, id)
+					return nil
+				}
+			},
+			expOut: "webhook 3 is removed\n\n" +
+				"ID  USER  URL                       EVENTS  CREATED AT\n" +
+				"3   all   https://example.com/hook  all     2024-05-01T10:00:00Z\n",
+		},
+		{
+			name: "error: webhooks remove, not found",
+			args: []string{"webhooks", "remove", "3"},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetWebhookFunc = func(context.Context, int64) (domain.Webhook, error) {
+					return domain.Webhook{}, storage.ErrWebhookNotFound
+				}
+			},
+			expErr: storage.ErrWebhookNotFound.Error(),
+		},
+		{
+			name: "success: webhooks deliveries",
+			args: []string{"webhooks", "deliveries", "3", "--limit", "2"},
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetWebhookFunc = func(_ context.Context, id int64) (domain.Webhook, error) {
+					return domain.Webhook{ID: id, URL: "https://example.com/hook"}, nil
+				}
+				store.GetWebhookDeliveriesFunc = func(_ context.Context, webhookID int64, limit int64) ([]domain.WebhookDelivery, error) {
+					a.EqualValues(3, webhookID)
+					a.EqualValues(2, limit)
+					return []domain.WebhookDelivery{
+						{
+							ID: 5, WebhookID: 3, Event: domain.WebhookEventFired, Status: domain.WebhookDeliveryStatusPending, Attempts: 2,
+							NextAttemptAt: remindAt, ResponseStatus: 502, LastError: "unexpected response status 502", CreatedAt: now,
+						},
+						{
+							ID: 4, WebhookID: 3, Event: domain.WebhookEventCreated, Status: domain.WebhookDeliveryStatusDelivered, Attempts: 1,
+							NextAttemptAt: now, ResponseStatus: 204, CreatedAt: now,
+						},
+					}, nil
+				}
+			},
+			expOut: "ID  EVENT             STATUS     ATTEMPTS  RESPONSE  CREATED AT            NEXT ATTEMPT AT       ERROR\n" +
+				"5   reminder.fired    pending    2         502       2024-05-01T10:00:00Z  2024-05-01T11:00:00Z  unexpected response status 502\n" +
+				"4   reminder.created  delivered  1         204       2024-05-01T10:00:00Z  -                     \n",
+		},
+		{
+			name: "success: db stats",
+			args: []string{"db", "stats", "--format", "json"},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetStatsFunc = func(context.Context) (domain.Stats, error) {
+					return domain.Stats{UsersActive: 2, UsersBlocked: 1, RemindersPending: 3, RemindersDone: 4}, nil
+				}
+				store.GetDBStatsFunc = func(context.Context) (domain.DBStats, error) {
+					return domain.DBStats{SizeBytes: 4096, Tables: []domain.TableSize{{Name: "users", Rows: 3}}}, nil
+				}
+			},
+			expJSON: `{"size_bytes":4096,"users_active":2,"users_inactive":0,"users_blocked":1,"reminders_pending":3,"reminders_done":4,` +
+				`"reminders_exhausted":0,"notifier_lag_seconds":0,"tables":[{"name":"users","rows":3}]}`,
+		},
+	}
+
+	timeNowUTC = func() time.Time { return now }
+	defer func() { timeNowUTC = func() time.Time { return time.Now().UTC() } }()
+
+	tmpNewWebhookSecret := newWebhookSecret
+	defer func() { newWebhookSecret = tmpNewWebhookSecret }()
+	newWebhookSecret = func() (string, error) { return "whsec_0123456789abcdef", nil }
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+			store := &StorageMock{}
+			if tc.setMocks != nil {
+				tc.setMocks(a, store)
+			}
+
+			env := tc.env
+			if env == nil {
+				env = map[string]string{"DB_FILE": "bot.db"}
+			}
+
+			var closed bool
+			open := func(dbFile, migrationsDir string) (Storage, func() error, error) {
+				a.Equal("bot.db", dbFile)
+				a.Empty(migrationsDir)
+				return store, func() error { closed = true; return nil }, nil
+			}
+
+			out := &bytes.Buffer{}
+			err := Run(context.TODO(), tc.args, func(key string) string { return env[key] }, open, out)
+			if tc.expErr != "" {
+				require.Error(t, err)
+				a.Contains(err.Error(), tc.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+			a.True(closed, "storage must be closed")
+			if tc.expJSON != "" {
+				a.JSONEq(tc.expJSON, out.String())
+				return
+			}
+			a.Equal(tc.expOut, out.String())
+		})
+	}
+}
+
+func TestIsCommand(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsCommand("users"))
+	assert.True(t, IsCommand("reminders"))
+	assert.True(t, IsCommand("bot-state"))
+	assert.True(t, IsCommand("webhooks"))
+	assert.True(t, IsCommand("db"))
+	assert.False(t, IsCommand("config"))
+	assert.False(t, IsCommand("--debug"))
+}
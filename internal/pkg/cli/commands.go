@@ -0,0 +1,669 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/storage"
+)
+
+// defaultLimit - default max number of listed users and reminders.
+const defaultLimit = 50
+
+// maxTextWidth - reminder text is cut in tables to keep rows on one line.
+const maxTextWidth = 40
+
+type userView struct {
+	ID         int64             `json:"id"`
+	Name       string            `json:"name"`
+	Status     domain.UserStatus `json:"status"`
+	CreatedAt  time.Time         `json:"created_at"`
+	ModifiedAt time.Time         `json:"modified_at"`
+}
+
+func newUserView(u domain.User) userView {
+	return userView{ID: u.ID, Name: u.Name, Status: u.Status, CreatedAt: u.CreatedAt.UTC(), ModifiedAt: u.ModifiedAt.UTC()}
+}
+
+type reminderView struct {
+	ID           int64                   `json:"id"`
+	UserID       int64                   `json:"user_id"`
+	ChatID       int64                   `json:"chat_id"`
+	Text         string                  `json:"text"`
+	RemindAt     time.Time               `json:"remind_at"`
+	Status       domain.ReminderStatus   `json:"status"`
+	Priority     domain.ReminderPriority `json:"priority"`
+	AttemptsLeft byte                    `json:"attempts_left"`
+	Checklist    []checklistItemView     `json:"checklist,omitempty"`
+	CreatedAt    time.Time               `json:"created_at"`
+	ModifiedAt   time.Time               `json:"modified_at"`
+}
+
+type checklistItemView struct {
+	ID   int64  `json:"id"`
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+func newReminderView(r domain.Reminder) reminderView {
+	v := reminderView{
+		ID:           r.ID,
+		UserID:       r.UserID,
+		ChatID:       r.ChatID,
+		Text:         r.Text,
+		RemindAt:     r.RemindAt.UTC(),
+		Status:       r.Status,
+		Priority:     r.Priority,
+		AttemptsLeft: r.AttemptsLeft,
+		CreatedAt:    r.CreatedAt.UTC(),
+		ModifiedAt:   r.ModifiedAt.UTC(),
+	}
+	for _, item := range r.Checklist {
+		v.Checklist = append(v.Checklist, checklistItemView{ID: item.ID, Text: item.Text, Done: item.Done})
+	}
+
+	return v
+}
+
+type eventView struct {
+	Type         domain.ReminderEventType `json:"type"`
+	Actor        domain.ActorType         `json:"actor"`
+	ActorID      int64                    `json:"actor_id,omitempty"`
+	Attempt      int                      `json:"attempt,omitempty"`
+	RemindAtFrom *time.Time               `json:"remind_at_from,omitempty"`
+	RemindAtTo   *time.Time               `json:"remind_at_to,omitempty"`
+	CreatedAt    time.Time                `json:"created_at"`
+}
+
+func newEventView(e domain.ReminderEvent) eventView {
+	return eventView{
+		Type:         e.Type,
+		Actor:        e.ActorType,
+		ActorID:      e.ActorID,
+		Attempt:      e.Attempt,
+		RemindAtFrom: e.RemindAtFrom,
+		RemindAtTo:   e.RemindAtTo,
+		CreatedAt:    e.CreatedAt.UTC(),
+	}
+}
+
+type webhookView struct {
+	ID        int64                `json:"id"`
+	UserID    int64                `json:"user_id"`
+	URL       string               `json:"url"`
+	Events    domain.WebhookEvents `json:"events,omitempty"` // all events if empty
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+func newWebhookView(w domain.Webhook) webhookView {
+	return webhookView{ID: w.ID, UserID: w.UserID, URL: w.URL, Events: w.Events, CreatedAt: w.CreatedAt.UTC()}
+}
+
+type webhookDeliveryView struct {
+	ID             int64                        `json:"id"`
+	Event          domain.WebhookEvent          `json:"event"`
+	Status         domain.WebhookDeliveryStatus `json:"status"`
+	Attempts       int                          `json:"attempts"`
+	NextAttemptAt  time.Time                    `json:"next_attempt_at"`
+	ResponseStatus int                          `json:"response_status,omitempty"`
+	LastError      string                       `json:"last_error,omitempty"`
+	CorrelationID  string                       `json:"correlation_id,omitempty"`
+	CreatedAt      time.Time                    `json:"created_at"`
+}
+
+func newWebhookDeliveryView(d domain.WebhookDelivery) webhookDeliveryView {
+	return webhookDeliveryView{
+		ID:             d.ID,
+		Event:          d.Event,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt.UTC(),
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		CorrelationID:  d.CorrelationID,
+		CreatedAt:      d.CreatedAt.UTC(),
+	}
+}
+
+func (c *CLI) usersList(ctx context.Context, args []string) error {
+	fs := c.flagSet("users list")
+	statusFlag := fs.String("status", "", "show only users with status: active, inactive or blocked")
+	limit := fs.Int64("limit", defaultLimit, "max number of users, the most recently registered first")
+	if _, err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	var (
+		users []domain.User
+		err   error
+	)
+	if *statusFlag == "" {
+		users, err = c.store.GetUsers(ctx, *limit)
+	} else {
+		var status domain.UserStatus
+		if status, err = domain.ParseUserStatus(*statusFlag); err != nil {
+			return err
+		}
+		if users, err = c.store.GetUsersByStatus(ctx, status); err == nil && int64(len(users)) > *limit {
+			users = users[:*limit]
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	views := make([]userView, 0, len(users))
+	for _, u := range users {
+		views = append(views, newUserView(u))
+	}
+
+	return c.print(views, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tNAME\tSTATUS\tCREATED AT")
+		for _, u := range views {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", u.ID, u.Name, u.Status, formatTime(u.CreatedAt))
+		}
+	})
+}
+
+func (c *CLI) usersDisable(ctx context.Context, args []string) error {
+	return c.setUserStatus(ctx, c.flagSet("users disable"), args, domain.UserStatusBlocked)
+}
+
+func (c *CLI) usersEnable(ctx context.Context, args []string) error {
+	return c.setUserStatus(ctx, c.flagSet("users enable"), args, domain.UserStatusActive)
+}
+
+// setUserStatus sets status of user, blocked users are not able to work with the bot.
+func (c *CLI) setUserStatus(ctx context.Context, fs *flag.FlagSet, args []string, status domain.UserStatus) error {
+	positional, err := c.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	userID, err := parseID(positional[0])
+	if err != nil {
+		return err
+	}
+
+	if err = c.store.SetUserStatus(ctx, userID, status); err != nil {
+		return err
+	}
+
+	user, err := c.store.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	c.printf("user %d is %s\n\n", user.ID, user.Status)
+
+	view := newUserView(user)
+	return c.print(view, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tNAME\tSTATUS\tCREATED AT")
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", view.ID, view.Name, view.Status, formatTime(view.CreatedAt))
+	})
+}
+
+func (c *CLI) remindersList(ctx context.Context, args []string) error {
+	fs := c.flagSet("reminders list")
+	userID := fs.Int64("user", 0, "show only reminders of user")
+	chatID := fs.Int64("chat", 0, "show only reminders of chat")
+	statusFlag := fs.String("status", "", "show only reminders with status")
+	limit := fs.Int64("limit", defaultLimit, "max number of reminders, the latest remind time first")
+	if _, err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	filter := storage.ReminderFilter{UserID: *userID, ChatID: *chatID, Limit: *limit}
+	if *statusFlag != "" {
+		var err error
+		if filter.Status, err = domain.ParseReminderStatus(*statusFlag); err != nil {
+			return err
+		}
+	}
+
+	reminders, err := c.store.FindReminders(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	views := make([]reminderView, 0, len(reminders))
+	for _, r := range reminders {
+		views = append(views, newReminderView(r))
+	}
+
+	return c.print(views, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tUSER\tCHAT\tSTATUS\tREMIND AT\tTEXT")
+		for _, r := range views {
+			fmt.Fprintf(w, "%d\t%d\t%d\t%s\t%s\t%s\n", r.ID, r.UserID, r.ChatID, r.Status, formatTime(r.RemindAt), cut(r.Text, maxTextWidth))
+		}
+	})
+}
+
+func (c *CLI) remindersShow(ctx context.Context, args []string) error {
+	reminder, err := c.parseReminder(ctx, c.flagSet("reminders show"), args, 1)
+	if err != nil {
+		return err
+	}
+
+	events, err := c.store.GetReminderEvents(ctx, reminder.ID)
+	if err != nil {
+		return err
+	}
+
+	res := struct {
+		Reminder reminderView `json:"reminder"`
+		History  []eventView  `json:"history"`
+	}{Reminder: newReminderView(reminder), History: make([]eventView, 0, len(events))}
+	for _, e := range events {
+		res.History = append(res.History, newEventView(e))
+	}
+
+	return c.print(res, func(w io.Writer) {
+		printReminder(w, res.Reminder)
+
+		fmt.Fprintln(w, "\nHISTORY")
+		fmt.Fprintln(w, "AT\tEVENT\tACTOR\tDETAILS")
+		for _, e := range res.History {
+			actor := string(e.Actor)
+			if e.ActorID != 0 {
+				actor = fmt.Sprintf("%s %d", e.Actor, e.ActorID)
+			}
+
+			var details string
+			switch {
+			case e.Attempt != 0:
+				details = fmt.Sprintf("attempt %d", e.Attempt)
+			case e.RemindAtFrom != nil && e.RemindAtTo != nil:
+				details = fmt.Sprintf("%s -> %s", formatTime(*e.RemindAtFrom), formatTime(*e.RemindAtTo))
+			case e.RemindAtTo != nil:
+				details = formatTime(*e.RemindAtTo)
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", formatTime(e.CreatedAt), e.Type, actor, details)
+		}
+	})
+}
+
+func (c *CLI) remindersReschedule(ctx context.Context, args []string) error {
+	fs := c.flagSet("reminders reschedule")
+	positional, err := c.parse(fs, args, 2)
+	if err != nil {
+		return err
+	}
+
+	reminder, err := c.getReminder(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	remindAt, err := parseRemindAt(positional[1], timeNowUTC())
+	if err != nil {
+		return err
+	}
+
+	// the same restriction as for delay of reminder in the bot
+	if reminder.Status != domain.ReminderStatusPending && reminder.Status != domain.ReminderStatusAttemptsExhausted {
+		return fmt.Errorf("reminder %d with status %s can't be rescheduled", reminder.ID, reminder.Status)
+	}
+
+	if err = c.store.DelayReminder(ctx, reminder.ID, remindAt); err != nil {
+		return err
+	}
+
+	if reminder, err = c.store.GetReminder(ctx, reminder.ID); err != nil {
+		return err
+	}
+
+	c.printf("reminder %d is rescheduled to %s\n\n", reminder.ID, formatTime(reminder.RemindAt))
+
+	view := newReminderView(reminder)
+	return c.print(view, func(w io.Writer) { printReminder(w, view) })
+}
+
+func (c *CLI) remindersDelete(ctx context.Context, args []string) error {
+	reminder, err := c.parseReminder(ctx, c.flagSet("reminders delete"), args, 1)
+	if err != nil {
+		return err
+	}
+
+	if err = c.store.RemoveReminder(ctx, reminder.ID); err != nil {
+		return err
+	}
+
+	c.printf("reminder %d is deleted\n\n", reminder.ID)
+
+	view := newReminderView(reminder)
+	return c.print(view, func(w io.Writer) { printReminder(w, view) })
+}
+
+func (c *CLI) botStateReset(ctx context.Context, args []string) error {
+	positional, err := c.parse(c.flagSet("bot-state reset"), args, 1)
+	if err != nil {
+		return err
+	}
+
+	userID, err := parseID(positional[0])
+	if err != nil {
+		return err
+	}
+
+	user, err := c.store.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// the dialog is started over as after /start command
+	state := domain.BotState{UserID: user.ID, Name: domain.BotStateNameStart}
+	if err = c.store.SaveBotState(ctx, state); err != nil {
+		return err
+	}
+
+	c.printf("bot state of user %d is reset\n\n", user.ID)
+
+	res := struct {
+		UserID int64               `json:"user_id"`
+		State  domain.BotStateName `json:"state"`
+	}{UserID: state.UserID, State: state.Name}
+
+	return c.print(res, func(w io.Writer) {
+		fmt.Fprintf(w, "User:\t%d\nState:\t%s\n", res.UserID, res.State)
+	})
+}
+
+func (c *CLI) webhooksList(ctx context.Context, args []string) error {
+	fs := c.flagSet("webhooks list")
+	userID := fs.Int64("user", 0, "show only webhooks of user, webhooks of all users are shown otherwise")
+	if _, err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	var (
+		hooks []domain.Webhook
+		err   error
+	)
+	if *userID == 0 {
+		hooks, err = c.store.GetWebhooks(ctx)
+	} else {
+		hooks, err = c.store.GetUserWebhooks(ctx, *userID)
+	}
+	if err != nil {
+		return err
+	}
+
+	views := make([]webhookView, 0, len(hooks))
+	for _, hook := range hooks {
+		views = append(views, newWebhookView(hook))
+	}
+
+	return c.print(views, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tUSER\tURL\tEVENTS\tCREATED AT")
+		for _, hook := range views {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", hook.ID, formatWebhookUser(hook.UserID), hook.URL, formatWebhookEvents(hook.Events), formatTime(hook.CreatedAt))
+		}
+	})
+}
+
+func (c *CLI) webhooksAdd(ctx context.Context, args []string) error {
+	fs := c.flagSet("webhooks add")
+	userID := fs.Int64("user", 0, "user whose reminder events are delivered, 0 for events of all users")
+	eventsFlag := fs.String("events", "", "comma separated events to deliver, all events if empty")
+	positional, err := c.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	hook := domain.Webhook{UserID: *userID, URL: positional[0]}
+	if err = domain.ValidateWebhookURL(hook.URL); err != nil {
+		return err
+	}
+
+	if *eventsFlag != "" {
+		if hook.Events, err = domain.ParseWebhookEvents(*eventsFlag); err != nil {
+			return err
+		}
+	}
+
+	if *userID != 0 {
+		if _, err = c.store.GetUser(ctx, *userID); err != nil {
+			return err
+		}
+	}
+
+	if hook.Secret, err = newWebhookSecret(); err != nil {
+		return fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	if hook.ID, err = c.store.SaveWebhook(ctx, hook, nil); err != nil {
+		return err
+	}
+
+	c.printf("webhook %d is added, the secret is shown once\n\n", hook.ID)
+
+	res := struct {
+		webhookView
+		Secret string `json:"secret"`
+	}{webhookView: newWebhookView(hook), Secret: hook.Secret}
+
+	return c.print(res, func(w io.Writer) {
+		fmt.Fprintf(w, "ID:\t%d\n", res.ID)
+		fmt.Fprintf(w, "User:\t%s\n", formatWebhookUser(res.UserID))
+		fmt.Fprintf(w, "URL:\t%s\n", res.URL)
+		fmt.Fprintf(w, "Events:\t%s\n", formatWebhookEvents(res.Events))
+		fmt.Fprintf(w, "Secret:\t%s\n", res.Secret)
+	})
+}
+
+func (c *CLI) webhooksRemove(ctx context.Context, args []string) error {
+	hook, err := c.parseWebhook(ctx, c.flagSet("webhooks remove"), args)
+	if err != nil {
+		return err
+	}
+
+	if err = c.store.RemoveWebhook(ctx, hook.ID); err != nil {
+		return err
+	}
+
+	c.printf("webhook %d is removed\n\n", hook.ID)
+
+	view := newWebhookView(hook)
+	return c.print(view, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tUSER\tURL\tEVENTS\tCREATED AT")
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", view.ID, formatWebhookUser(view.UserID), view.URL, formatWebhookEvents(view.Events), formatTime(view.CreatedAt))
+	})
+}
+
+func (c *CLI) webhooksDeliveries(ctx context.Context, args []string) error {
+	fs := c.flagSet("webhooks deliveries")
+	limit := fs.Int64("limit", defaultLimit, "max number of deliveries, the latest first")
+	hook, err := c.parseWebhook(ctx, fs, args)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := c.store.GetWebhookDeliveries(ctx, hook.ID, *limit)
+	if err != nil {
+		return err
+	}
+
+	views := make([]webhookDeliveryView, 0, len(deliveries))
+	for _, d := range deliveries {
+		views = append(views, newWebhookDeliveryView(d))
+	}
+
+	return c.print(views, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tEVENT\tSTATUS\tATTEMPTS\tRESPONSE\tCREATED AT\tNEXT ATTEMPT AT\tERROR")
+		for _, d := range views {
+			nextAttemptAt := "-"
+			if d.Status == domain.WebhookDeliveryStatusPending {
+				nextAttemptAt = formatTime(d.NextAttemptAt)
+			}
+
+			fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\t%s\t%s\t%s\n",
+				d.ID, d.Event, d.Status, d.Attempts, d.ResponseStatus, formatTime(d.CreatedAt), nextAttemptAt, cut(d.LastError, maxTextWidth))
+		}
+	})
+}
+
+func (c *CLI) dbStats(ctx context.Context, args []string) error {
+	if _, err := c.parse(c.flagSet("db stats"), args, 0); err != nil {
+		return err
+	}
+
+	stats, err := c.store.GetStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	dbStats, err := c.store.GetDBStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	type tableView struct {
+		Name string `json:"name"`
+		Rows int64  `json:"rows"`
+	}
+
+	res := struct {
+		SizeBytes          int64       `json:"size_bytes"`
+		UsersActive        int64       `json:"users_active"`
+		UsersInactive      int64       `json:"users_inactive"`
+		UsersBlocked       int64       `json:"users_blocked"`
+		RemindersPending   int64       `json:"reminders_pending"`
+		RemindersDone      int64       `json:"reminders_done"`
+		RemindersExhausted int64       `json:"reminders_exhausted"`
+		NotifierLagSeconds float64     `json:"notifier_lag_seconds"`
+		Tables             []tableView `json:"tables"`
+	}{
+		SizeBytes:          dbStats.SizeBytes,
+		UsersActive:        stats.UsersActive,
+		UsersInactive:      stats.UsersInactive,
+		UsersBlocked:       stats.UsersBlocked,
+		RemindersPending:   stats.RemindersPending,
+		RemindersDone:      stats.RemindersDone,
+		RemindersExhausted: stats.RemindersExhausted,
+		NotifierLagSeconds: stats.NotifierLag(timeNowUTC()).Seconds(),
+		Tables:             make([]tableView, 0, len(dbStats.Tables)),
+	}
+	for _, t := range dbStats.Tables {
+		res.Tables = append(res.Tables, tableView{Name: t.Name, Rows: t.Rows})
+	}
+
+	return c.print(res, func(w io.Writer) {
+		fmt.Fprintf(w, "Size:\t%d bytes\n", res.SizeBytes)
+		fmt.Fprintf(w, "Users:\t%d active, %d inactive, %d blocked\n", res.UsersActive, res.UsersInactive, res.UsersBlocked)
+		fmt.Fprintf(w, "Reminders:\t%d pending, %d done, %d exhausted\n", res.RemindersPending, res.RemindersDone, res.RemindersExhausted)
+		fmt.Fprintf(w, "Notifier lag:\t%s\n", stats.NotifierLag(timeNowUTC()))
+
+		fmt.Fprintln(w, "\nTABLE\tROWS")
+		for _, t := range res.Tables {
+			fmt.Fprintf(w, "%s\t%d\n", t.Name, t.Rows)
+		}
+	})
+}
+
+// parseReminder parses args of subcommand with reminder id as the first positional arg and returns the reminder.
+func (c *CLI) parseReminder(ctx context.Context, fs *flag.FlagSet, args []string, nArgs int) (domain.Reminder, error) {
+	positional, err := c.parse(fs, args, nArgs)
+	if err != nil {
+		return domain.Reminder{}, err
+	}
+
+	return c.getReminder(ctx, positional[0])
+}
+
+func (c *CLI) getReminder(ctx context.Context, s string) (domain.Reminder, error) {
+	id, err := parseID(s)
+	if err != nil {
+		return domain.Reminder{}, err
+	}
+
+	return c.store.GetReminder(ctx, id)
+}
+
+// parseWebhook parses args of subcommand with webhook id as the only positional arg and returns the webhook.
+func (c *CLI) parseWebhook(ctx context.Context, fs *flag.FlagSet, args []string) (domain.Webhook, error) {
+	positional, err := c.parse(fs, args, 1)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	id, err := parseID(positional[0])
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	return c.store.GetWebhook(ctx, id)
+}
+
+var newWebhookSecret = domain.NewWebhookSecret
+
+// parseRemindAt parses new remind time, it's either RFC 3339 time or duration from now, e.g. 2h30m.
+func parseRemindAt(s string, now time.Time) (time.Time, error) {
+	remindAt, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		d, durErr := time.ParseDuration(s)
+		if durErr != nil {
+			return time.Time{}, fmt.Errorf("invalid remind time %q, RFC 3339 time or duration is expected", s)
+		}
+		remindAt = now.Add(d)
+	}
+
+	if !remindAt.After(now) {
+		return time.Time{}, domain.ErrRemindAtInPast
+	}
+
+	return remindAt.UTC(), nil
+}
+
+func printReminder(w io.Writer, r reminderView) {
+	fmt.Fprintf(w, "ID:\t%d\n", r.ID)
+	fmt.Fprintf(w, "User:\t%d\n", r.UserID)
+	fmt.Fprintf(w, "Chat:\t%d\n", r.ChatID)
+	fmt.Fprintf(w, "Text:\t%s\n", r.Text)
+	fmt.Fprintf(w, "Remind at:\t%s\n", formatTime(r.RemindAt))
+	fmt.Fprintf(w, "Status:\t%s\n", r.Status)
+	fmt.Fprintf(w, "Priority:\t%s\n", r.Priority)
+	fmt.Fprintf(w, "Attempts left:\t%d\n", r.AttemptsLeft)
+	fmt.Fprintf(w, "Created at:\t%s\n", formatTime(r.CreatedAt))
+	fmt.Fprintf(w, "Modified at:\t%s\n", formatTime(r.ModifiedAt))
+	for _, item := range r.Checklist {
+		mark := " "
+		if item.Done {
+			mark = "x"
+		}
+		fmt.Fprintf(w, "Checklist:\t[%s] %s\n", mark, item.Text)
+	}
+}
+
+func formatWebhookUser(userID int64) string {
+	if userID == 0 {
+		return "all"
+	}
+
+	return strconv.FormatInt(userID, 10)
+}
+
+func formatWebhookEvents(events domain.WebhookEvents) string {
+	if len(events) == 0 {
+		return "all"
+	}
+
+	return events.String()
+}
+
+// cut cuts s to n runes, text of reminder is shown in one line.
+func cut(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n-1]) + "…"
+	}
+
+	return s
+}
@@ -0,0 +1,509 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	tbapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/mezk/tg-reminder/internal/pkg/access"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+	"github.com/mezk/tg-reminder/internal/pkg/notifier"
+	"github.com/mezk/tg-reminder/internal/pkg/tracing"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvConfigFile - env variable with path to config file, --config flag has priority over it.
+const EnvConfigFile = "CONFIG_FILE"
+
+// maxBroadcastRate - Telegram allows about 30 messages per second.
+const maxBroadcastRate = 30
+
+// Config - application configuration.
+type Config struct {
+	DBFile          string         `yaml:"db_file" toml:"db_file"`
+	Migrations      string         `yaml:"migrations" toml:"migrations"`
+	Debug           bool           `yaml:"debug" toml:"debug"`
+	LogFormat       logging.Format `yaml:"log_format" toml:"log_format"`
+	ShutdownTimeout time.Duration  `yaml:"shutdown_timeout" toml:"shutdown_timeout"` // how long to wait for work in progress on shutdown
+	Telegram        Telegram       `yaml:"telegram" toml:"telegram"`
+	Backup          Backup         `yaml:"backup" toml:"backup"`
+	Access          Access         `yaml:"access" toml:"access"`
+	Notifier        Notifier       `yaml:"notifier" toml:"notifier"`
+	Outbox          Outbox         `yaml:"outbox" toml:"outbox"`
+	CatchUp         CatchUp        `yaml:"catch_up" toml:"catch_up"`
+	Broadcast       Broadcast      `yaml:"broadcast" toml:"broadcast"`
+	Tracing         Tracing        `yaml:"tracing" toml:"tracing"`
+	API             API            `yaml:"api" toml:"api"`
+	Web             Web            `yaml:"web" toml:"web"`
+	Channels        Channels       `yaml:"channels" toml:"channels"`
+	Webhooks        Webhooks       `yaml:"webhooks" toml:"webhooks"`
+}
+
+// Telegram - Telegram Bot API configuration.
+type Telegram struct {
+	APIToken       string        `yaml:"api_token" toml:"api_token"`
+	APIEndpoint    string        `yaml:"api_endpoint" toml:"api_endpoint"`
+	UpdatesTimeout time.Duration `yaml:"updates_timeout" toml:"updates_timeout"` // long polling timeout of getUpdates
+	HandlerTimeout time.Duration `yaml:"handler_timeout" toml:"handler_timeout"` // timeout to process one update
+	Workers        int           `yaml:"workers" toml:"workers"`                 // number of updates processed concurrently
+	QueueSize      int           `yaml:"queue_size" toml:"queue_size"`           // max number of updates waiting for every worker
+}
+
+// Backup - db backups configuration, backups are disabled if Dir is empty.
+type Backup struct {
+	Dir       string        `yaml:"dir" toml:"dir"`
+	Interval  time.Duration `yaml:"interval" toml:"interval"`
+	Retention time.Duration `yaml:"retention" toml:"retention"`
+}
+
+// Access - access control configuration.
+type Access struct {
+	Mode         access.Mode `yaml:"mode" toml:"mode"`
+	OwnerID      int64       `yaml:"owner_id" toml:"owner_id"`
+	AllowedUsers []int64     `yaml:"allowed_users" toml:"allowed_users"`
+	AdminUsers   []int64     `yaml:"admin_users" toml:"admin_users"`
+}
+
+// Notifier - reminders notifier configuration.
+type Notifier struct {
+	Interval  time.Duration `yaml:"interval" toml:"interval"`     // how often to check for reminders to send
+	BatchSize int64         `yaml:"batch_size" toml:"batch_size"` // max number of reminders sent per check
+}
+
+// Outbox - outgoing messages queue configuration.
+type Outbox struct {
+	Interval    time.Duration `yaml:"interval" toml:"interval"`         // how often to check for messages to retry
+	BatchSize   int64         `yaml:"batch_size" toml:"batch_size"`     // max number of messages delivered per check
+	MaxAttempts int           `yaml:"max_attempts" toml:"max_attempts"` // number of delivery attempts before message is dead
+	MaxBackoff  time.Duration `yaml:"max_backoff" toml:"max_backoff"`   // max delay between delivery attempts
+}
+
+// Webhooks - delivery of reminder lifecycle events to webhooks.
+type Webhooks struct {
+	Interval    time.Duration `yaml:"interval" toml:"interval"`         // how often to check for events to deliver
+	BatchSize   int64         `yaml:"batch_size" toml:"batch_size"`     // max number of events delivered per check
+	MaxAttempts int           `yaml:"max_attempts" toml:"max_attempts"` // number of delivery attempts before event is dead
+	MaxBackoff  time.Duration `yaml:"max_backoff" toml:"max_backoff"`   // max delay between delivery attempts
+	Timeout     time.Duration `yaml:"timeout" toml:"timeout"`           // max time of one request to webhook
+}
+
+// CatchUp - policy for reminders missed while the bot was down or user disabled reminders.
+type CatchUp struct {
+	Policy notifier.CatchUpPolicy `yaml:"policy" toml:"policy"`
+	MaxAge time.Duration          `yaml:"max_age" toml:"max_age"` // reminders overdue for more than this are missed
+}
+
+// Broadcast - admin announcements configuration.
+type Broadcast struct {
+	Rate int `yaml:"rate" toml:"rate"` // max number of messages sent per second
+}
+
+// Tracing - OpenTelemetry tracing configuration, tracing is disabled with none exporter.
+type Tracing struct {
+	Exporter    tracing.Exporter `yaml:"exporter" toml:"exporter"`
+	Endpoint    string           `yaml:"endpoint" toml:"endpoint"`         // URL of OTLP collector
+	SampleRatio float64          `yaml:"sample_ratio" toml:"sample_ratio"` // share of traces which are recorded
+}
+
+// API - REST API configuration, API is disabled if Addr is empty.
+type API struct {
+	Addr string `yaml:"addr" toml:"addr"` // address to listen on, e.g. :8080
+}
+
+// Web - web dashboard configuration, dashboard is disabled if Addr is empty.
+type Web struct {
+	Addr string `yaml:"addr" toml:"addr"` // address to listen on, e.g. :8081
+	URL  string `yaml:"url" toml:"url"`   // public URL of the dashboard, login links sent by /web command point to it
+}
+
+// Channels - notification channels besides Telegram. Webhooks are always enabled,
+// email is enabled if SMTP server is set, push - if push server is set.
+type Channels struct {
+	Timeout time.Duration `yaml:"timeout" toml:"timeout"` // max time to deliver one notification
+	SMTP    SMTP          `yaml:"smtp" toml:"smtp"`
+	Push    Push          `yaml:"push" toml:"push"`
+}
+
+// SMTP - SMTP server to send emails with.
+type SMTP struct {
+	Addr     string `yaml:"addr" toml:"addr"`         // host:port, e.g. smtp.example.com:587
+	Username string `yaml:"username" toml:"username"` // no auth if empty
+	Password string `yaml:"password" toml:"password"`
+	From     string `yaml:"from" toml:"from"` // sender address
+}
+
+// Push - ntfy-style push server, users subscribe to their topics on it.
+type Push struct {
+	URL   string `yaml:"url" toml:"url"`     // server URL, e.g. https://ntfy.sh
+	Token string `yaml:"token" toml:"token"` // access token of the server, optional
+}
+
+// Default returns configuration with default values.
+func Default() Config {
+	return Config{
+		Migrations:      "/srv/db/migrations",
+		LogFormat:       logging.FormatText,
+		ShutdownTimeout: 30 * time.Second,
+		Telegram: Telegram{
+			APIEndpoint:    tbapi.APIEndpoint,
+			UpdatesTimeout: 60 * time.Second,
+			HandlerTimeout: 5 * time.Second,
+			Workers:        8,
+			QueueSize:      16,
+		},
+		Access:    Access{Mode: access.ModeOpen},
+		Notifier:  Notifier{Interval: time.Minute, BatchSize: 100},
+		Outbox:    Outbox{Interval: time.Second, BatchSize: 50, MaxAttempts: 10, MaxBackoff: time.Hour},
+		CatchUp:   CatchUp{Policy: notifier.CatchUpAll, MaxAge: time.Hour},
+		Broadcast: Broadcast{Rate: 20},
+		Tracing:   Tracing{Exporter: tracing.ExporterNone, Endpoint: "http://localhost:4318", SampleRatio: 1},
+		Channels:  Channels{Timeout: 10 * time.Second},
+		Webhooks:  Webhooks{Interval: 5 * time.Second, BatchSize: 50, MaxAttempts: 10, MaxBackoff: time.Hour, Timeout: 10 * time.Second},
+	}
+}
+
+// Load loads configuration. Default values are overridden by config file, then by env variables and then by flags.
+// Config file is set by --config flag or [EnvConfigFile] env variable.
+// Every env variable can be read from file set by env variable with "_FILE" suffix, e.g. TELEGRAM_APITOKEN_FILE.
+// Errors of all fields are returned at once.
+func Load(args []string, getenv func(string) string) (Config, error) {
+	fs := flag.NewFlagSet("tg-reminder", flag.ContinueOnError)
+	configFile := fs.String("config", "", "config file path, YAML or TOML")
+
+	flagValues := make(map[string]*string, len(fields))
+	for _, f := range fields {
+		flagValues[f.flag] = fs.String(f.flag, "", fmt.Sprintf("%s (env %s)", f.usage, f.env))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	cfg := Default()
+
+	path := *configFile
+	if path == "" {
+		path = getenv(EnvConfigFile)
+	}
+	if path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+
+	var errs []error
+	for _, f := range fields {
+		value, ok, err := lookupEnv(getenv, f.env)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		if err = f.set(&cfg, value); err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse %s env variable: %w", f.env, err))
+		}
+	}
+
+	fs.Visit(func(fl *flag.Flag) {
+		for _, f := range fields {
+			if f.flag != fl.Name {
+				continue
+			}
+
+			if err := f.set(&cfg, *flagValues[f.flag]); err != nil {
+				errs = append(errs, fmt.Errorf("failed to parse --%s flag: %w", f.flag, err))
+			}
+		}
+	})
+
+	if len(errs) > 0 {
+		return Config{}, errors.Join(errs...)
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// Validate validates all fields of configuration, returns all found errors joined.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.DBFile != "", "db_file is required")
+	check(c.Migrations != "", "migrations is required")
+	check(c.ShutdownTimeout > 0, "shutdown_timeout must be positive, got %s", c.ShutdownTimeout)
+	if _, err := logging.ParseFormat(string(c.LogFormat)); err != nil {
+		errs = append(errs, fmt.Errorf("log_format: %w", err))
+	}
+
+	check(c.Telegram.APIToken != "", "telegram.api_token is required")
+	check(c.Telegram.APIEndpoint != "", "telegram.api_endpoint is required")
+	check(c.Telegram.UpdatesTimeout >= 0, "telegram.updates_timeout must not be negative, got %s", c.Telegram.UpdatesTimeout)
+	check(c.Telegram.HandlerTimeout > 0, "telegram.handler_timeout must be positive, got %s", c.Telegram.HandlerTimeout)
+	check(c.Telegram.Workers > 0, "telegram.workers must be positive, got %d", c.Telegram.Workers)
+	check(c.Telegram.QueueSize > 0, "telegram.queue_size must be positive, got %d", c.Telegram.QueueSize)
+
+	if c.Backup.Dir != "" {
+		check(c.Backup.Interval > 0, "backup.interval must be positive when backup.dir is set, got %s", c.Backup.Interval)
+		check(c.Backup.Retention > 0, "backup.retention must be positive when backup.dir is set, got %s", c.Backup.Retention)
+	}
+
+	if _, err := access.ParseMode(string(c.Access.Mode)); err != nil {
+		errs = append(errs, fmt.Errorf("access.mode: %w", err))
+	}
+	check(c.Access.Mode != access.ModeInvite || c.Access.OwnerID != 0, "access.owner_id is required for %s access mode", access.ModeInvite)
+
+	check(c.Notifier.Interval >= time.Second, "notifier.interval must be at least 1s, got %s", c.Notifier.Interval)
+	check(c.Notifier.BatchSize > 0, "notifier.batch_size must be positive, got %d", c.Notifier.BatchSize)
+
+	check(c.Outbox.Interval > 0, "outbox.interval must be positive, got %s", c.Outbox.Interval)
+	check(c.Outbox.BatchSize > 0, "outbox.batch_size must be positive, got %d", c.Outbox.BatchSize)
+	check(c.Outbox.MaxAttempts > 0, "outbox.max_attempts must be positive, got %d", c.Outbox.MaxAttempts)
+	check(c.Outbox.MaxBackoff > 0, "outbox.max_backoff must be positive, got %s", c.Outbox.MaxBackoff)
+
+	if _, err := notifier.ParseCatchUpPolicy(string(c.CatchUp.Policy)); err != nil {
+		errs = append(errs, fmt.Errorf("catch_up.policy: %w", err))
+	}
+	check(c.CatchUp.MaxAge > 0, "catch_up.max_age must be positive, got %s", c.CatchUp.MaxAge)
+
+	check(c.Broadcast.Rate > 0 && c.Broadcast.Rate <= maxBroadcastRate, "broadcast.rate must be between 1 and %d, got %d", maxBroadcastRate, c.Broadcast.Rate)
+
+	if _, err := tracing.ParseExporter(string(c.Tracing.Exporter)); err != nil {
+		errs = append(errs, fmt.Errorf("tracing.exporter: %w", err))
+	}
+	check(c.Tracing.Exporter != tracing.ExporterOTLP || c.Tracing.Endpoint != "", "tracing.endpoint is required for %s exporter", tracing.ExporterOTLP)
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+
+	if c.API.Addr != "" {
+		if _, _, err := net.SplitHostPort(c.API.Addr); err != nil {
+			errs = append(errs, fmt.Errorf("api.addr: %w", err))
+		}
+	}
+
+	if c.Web.Addr != "" {
+		if _, _, err := net.SplitHostPort(c.Web.Addr); err != nil {
+			errs = append(errs, fmt.Errorf("web.addr: %w", err))
+		}
+		u, err := url.Parse(c.Web.URL)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"web.url must be absolute http or https URL when web.addr is set, got %q", c.Web.URL)
+	}
+
+	check(c.Channels.Timeout > 0, "channels.timeout must be positive, got %s", c.Channels.Timeout)
+	if c.Channels.SMTP.Addr != "" {
+		if _, _, err := net.SplitHostPort(c.Channels.SMTP.Addr); err != nil {
+			errs = append(errs, fmt.Errorf("channels.smtp.addr: %w", err))
+		}
+		_, err := mail.ParseAddress(c.Channels.SMTP.From)
+		check(err == nil, "channels.smtp.from must be email address when channels.smtp.addr is set, got %q", c.Channels.SMTP.From)
+	}
+	if c.Channels.Push.URL != "" {
+		u, err := url.Parse(c.Channels.Push.URL)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"channels.push.url must be absolute http or https URL, got %q", c.Channels.Push.URL)
+	}
+
+	check(c.Webhooks.Interval > 0, "webhooks.interval must be positive, got %s", c.Webhooks.Interval)
+	check(c.Webhooks.BatchSize > 0, "webhooks.batch_size must be positive, got %d", c.Webhooks.BatchSize)
+	check(c.Webhooks.MaxAttempts > 0, "webhooks.max_attempts must be positive, got %d", c.Webhooks.MaxAttempts)
+	check(c.Webhooks.MaxBackoff > 0, "webhooks.max_backoff must be positive, got %s", c.Webhooks.MaxBackoff)
+	check(c.Webhooks.Timeout > 0, "webhooks.timeout must be positive, got %s", c.Webhooks.Timeout)
+
+	return errors.Join(errs...)
+}
+
+// AdminIDs returns Telegram IDs of admins, the owner is an admin as well.
+func (c Config) AdminIDs() []int64 {
+	ids := append([]int64(nil), c.Access.AdminUsers...)
+	if c.Access.OwnerID != 0 {
+		ids = append(ids, c.Access.OwnerID)
+	}
+
+	return ids
+}
+
+// YAML returns configuration in YAML format, secrets are masked.
+func (c Config) YAML() (string, error) {
+	for _, secret := range []*string{&c.Telegram.APIToken, &c.Channels.SMTP.Password, &c.Channels.Push.Token} {
+		if *secret != "" {
+			*secret = "********"
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return "", fmt.Errorf("failed to encode config to yaml: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// loadFile loads config file, format is detected by file extension. Unknown fields are not allowed.
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err = dec.Decode(cfg); err != nil {
+			return fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	case ".toml":
+		md, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("failed to parse config file %s: unknown fields %v", path, undecoded)
+		}
+	default:
+		return fmt.Errorf("unsupported config file format %q, expected .yaml, .yml or .toml", ext)
+	}
+
+	return nil
+}
+
+// lookupEnv returns value of env variable or content of file set by env variable with "_FILE" suffix.
+// Empty env variable is considered as not set.
+func lookupEnv(getenv func(string) string, name string) (string, bool, error) {
+	value, file := getenv(name), getenv(name+"_FILE")
+
+	switch {
+	case value != "" && file != "":
+		return "", false, fmt.Errorf("only one of %s and %s_FILE env variables can be set", name, name)
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read %s_FILE: %w", name, err)
+		}
+		return strings.TrimSpace(string(data)), true, nil
+	case value != "":
+		return value, true, nil
+	default:
+		return "", false, nil
+	}
+}
+
+// field - config field which can be set by env variable and flag.
+type field struct {
+	env   string
+	flag  string
+	usage string
+	set   func(c *Config, value string) error
+}
+
+var fields = []field{
+	{env: "DB_FILE", flag: "db-file", usage: "database file path", set: setter(func(c *Config) *string { return &c.DBFile }, parseString)},
+	{env: "MIGRATIONS", flag: "migrations", usage: "migration directory for goose", set: setter(func(c *Config) *string { return &c.Migrations }, parseString)},
+	{env: "DEBUG", flag: "debug", usage: "whether to print debug logs", set: setter(func(c *Config) *bool { return &c.Debug }, strconv.ParseBool)},
+	{env: "LOG_FORMAT", flag: "log-format", usage: "log format: text or json", set: setter(func(c *Config) *logging.Format { return &c.LogFormat }, logging.ParseFormat)},
+	{env: "SHUTDOWN_TIMEOUT", flag: "shutdown-timeout", usage: "how long to wait for work in progress on shutdown", set: setter(func(c *Config) *time.Duration { return &c.ShutdownTimeout }, time.ParseDuration)},
+	{env: "TELEGRAM_APITOKEN", flag: "telegram-api-token", usage: "Telegram API token, received from Botfather", set: setter(func(c *Config) *string { return &c.Telegram.APIToken }, parseString)},
+	{env: "TELEGRAM_BOT_API_ENDPOINT", flag: "telegram-api-endpoint", usage: "Telegram API Bot endpoint", set: setter(func(c *Config) *string { return &c.Telegram.APIEndpoint }, parseString)},
+	{env: "TELEGRAM_UPDATES_TIMEOUT", flag: "telegram-updates-timeout", usage: "long polling timeout of Telegram updates", set: setter(func(c *Config) *time.Duration { return &c.Telegram.UpdatesTimeout }, time.ParseDuration)},
+	{env: "TELEGRAM_HANDLER_TIMEOUT", flag: "telegram-handler-timeout", usage: "timeout to process one Telegram update", set: setter(func(c *Config) *time.Duration { return &c.Telegram.HandlerTimeout }, time.ParseDuration)},
+	{env: "TELEGRAM_WORKERS", flag: "telegram-workers", usage: "number of Telegram updates processed concurrently", set: setter(func(c *Config) *int { return &c.Telegram.Workers }, strconv.Atoi)},
+	{env: "TELEGRAM_QUEUE_SIZE", flag: "telegram-queue-size", usage: "max number of Telegram updates waiting for every worker", set: setter(func(c *Config) *int { return &c.Telegram.QueueSize }, strconv.Atoi)},
+	{env: "BACKUP_DIR", flag: "backup-dir", usage: "directory where to place db backups", set: setter(func(c *Config) *string { return &c.Backup.Dir }, parseString)},
+	{env: "BACKUP_INTERVAL", flag: "backup-interval", usage: "how often to make db backups", set: setter(func(c *Config) *time.Duration { return &c.Backup.Interval }, time.ParseDuration)},
+	{env: "BACKUP_RETENTION", flag: "backup-retention", usage: "retention period for old db backups", set: setter(func(c *Config) *time.Duration { return &c.Backup.Retention }, time.ParseDuration)},
+	{env: "ACCESS_MODE", flag: "access-mode", usage: "access mode: open, allowlist or invite", set: setter(func(c *Config) *access.Mode { return &c.Access.Mode }, access.ParseMode)},
+	{env: "ACCESS_ALLOWED_USERS", flag: "access-allowed-users", usage: "comma separated Telegram IDs of users who are always allowed", set: setter(func(c *Config) *[]int64 { return &c.Access.AllowedUsers }, ParseIDs)},
+	{env: "OWNER_ID", flag: "owner-id", usage: "Telegram ID of the bot owner", set: setter(func(c *Config) *int64 { return &c.Access.OwnerID }, parseInt64)},
+	{env: "ADMIN_USERS", flag: "admin-users", usage: "comma separated Telegram IDs of bot admins", set: setter(func(c *Config) *[]int64 { return &c.Access.AdminUsers }, ParseIDs)},
+	{env: "NOTIFIER_INTERVAL", flag: "notifier-interval", usage: "how often to check for reminders to send", set: setter(func(c *Config) *time.Duration { return &c.Notifier.Interval }, time.ParseDuration)},
+	{env: "NOTIFIER_BATCH_SIZE", flag: "notifier-batch-size", usage: "max number of reminders sent per check", set: setter(func(c *Config) *int64 { return &c.Notifier.BatchSize }, parseInt64)},
+	{env: "OUTBOX_INTERVAL", flag: "outbox-interval", usage: "how often to check for outgoing messages to retry", set: setter(func(c *Config) *time.Duration { return &c.Outbox.Interval }, time.ParseDuration)},
+	{env: "OUTBOX_BATCH_SIZE", flag: "outbox-batch-size", usage: "max number of outgoing messages delivered per check", set: setter(func(c *Config) *int64 { return &c.Outbox.BatchSize }, parseInt64)},
+	{env: "OUTBOX_MAX_ATTEMPTS", flag: "outbox-max-attempts", usage: "number of delivery attempts before outgoing message is dead", set: setter(func(c *Config) *int { return &c.Outbox.MaxAttempts }, strconv.Atoi)},
+	{env: "OUTBOX_MAX_BACKOFF", flag: "outbox-max-backoff", usage: "max delay between delivery attempts of outgoing message", set: setter(func(c *Config) *time.Duration { return &c.Outbox.MaxBackoff }, time.ParseDuration)},
+	{env: "CATCH_UP_POLICY", flag: "catch-up-policy", usage: "what to do with missed reminders: all, summary or skip", set: setter(func(c *Config) *notifier.CatchUpPolicy { return &c.CatchUp.Policy }, notifier.ParseCatchUpPolicy)},
+	{env: "CATCH_UP_MAX_AGE", flag: "catch-up-max-age", usage: "reminders overdue for more than this are missed", set: setter(func(c *Config) *time.Duration { return &c.CatchUp.MaxAge }, time.ParseDuration)},
+	{env: "BROADCAST_RATE", flag: "broadcast-rate", usage: "max number of broadcast messages sent per second", set: setter(func(c *Config) *int { return &c.Broadcast.Rate }, strconv.Atoi)},
+	{env: "TRACING_EXPORTER", flag: "tracing-exporter", usage: "tracing exporter: none, stdout or otlp", set: setter(func(c *Config) *tracing.Exporter { return &c.Tracing.Exporter }, tracing.ParseExporter)},
+	{env: "TRACING_ENDPOINT", flag: "tracing-endpoint", usage: "URL of OTLP collector", set: setter(func(c *Config) *string { return &c.Tracing.Endpoint }, parseString)},
+	{env: "TRACING_SAMPLE_RATIO", flag: "tracing-sample-ratio", usage: "share of traces which are recorded, from 0 to 1", set: setter(func(c *Config) *float64 { return &c.Tracing.SampleRatio }, parseFloat64)},
+	{env: "API_ADDR", flag: "api-addr", usage: "address of REST API server, e.g. :8080, API is disabled if empty", set: setter(func(c *Config) *string { return &c.API.Addr }, parseString)},
+	{env: "WEB_ADDR", flag: "web-addr", usage: "address of web dashboard server, e.g. :8081, dashboard is disabled if empty", set: setter(func(c *Config) *string { return &c.Web.Addr }, parseString)},
+	{env: "WEB_URL", flag: "web-url", usage: "public URL of web dashboard, e.g. https://reminder.example.com", set: setter(func(c *Config) *string { return &c.Web.URL }, parseString)},
+	{env: "CHANNELS_TIMEOUT", flag: "channels-timeout", usage: "max time to deliver one notification by email, webhook or push", set: setter(func(c *Config) *time.Duration { return &c.Channels.Timeout }, time.ParseDuration)},
+	{env: "SMTP_ADDR", flag: "smtp-addr", usage: "SMTP server host:port, email notifications are disabled if empty", set: setter(func(c *Config) *string { return &c.Channels.SMTP.Addr }, parseString)},
+	{env: "SMTP_USERNAME", flag: "smtp-username", usage: "SMTP username, no auth if empty", set: setter(func(c *Config) *string { return &c.Channels.SMTP.Username }, parseString)},
+	{env: "SMTP_PASSWORD", flag: "smtp-password", usage: "SMTP password", set: setter(func(c *Config) *string { return &c.Channels.SMTP.Password }, parseString)},
+	{env: "SMTP_FROM", flag: "smtp-from", usage: "sender address of email notifications", set: setter(func(c *Config) *string { return &c.Channels.SMTP.From }, parseString)},
+	{env: "PUSH_URL", flag: "push-url", usage: "URL of ntfy-style push server, e.g. https://ntfy.sh, push notifications are disabled if empty", set: setter(func(c *Config) *string { return &c.Channels.Push.URL }, parseString)},
+	{env: "PUSH_TOKEN", flag: "push-token", usage: "access token of push server", set: setter(func(c *Config) *string { return &c.Channels.Push.Token }, parseString)},
+	{env: "WEBHOOKS_INTERVAL", flag: "webhooks-interval", usage: "how often to check for reminder events to deliver to webhooks", set: setter(func(c *Config) *time.Duration { return &c.Webhooks.Interval }, time.ParseDuration)},
+	{env: "WEBHOOKS_BATCH_SIZE", flag: "webhooks-batch-size", usage: "max number of reminder events delivered to webhooks per check", set: setter(func(c *Config) *int64 { return &c.Webhooks.BatchSize }, parseInt64)},
+	{env: "WEBHOOKS_MAX_ATTEMPTS", flag: "webhooks-max-attempts", usage: "number of delivery attempts before reminder event is dead", set: setter(func(c *Config) *int { return &c.Webhooks.MaxAttempts }, strconv.Atoi)},
+	{env: "WEBHOOKS_MAX_BACKOFF", flag: "webhooks-max-backoff", usage: "max delay between delivery attempts of reminder event", set: setter(func(c *Config) *time.Duration { return &c.Webhooks.MaxBackoff }, time.ParseDuration)},
+	{env: "WEBHOOKS_TIMEOUT", flag: "webhooks-timeout", usage: "max time of one request to webhook", set: setter(func(c *Config) *time.Duration { return &c.Webhooks.Timeout }, time.ParseDuration)},
+}
+
+// setter returns function to parse value and to set it to config field.
+func setter[T any](field func(c *Config) *T, parse func(string) (T, error)) func(c *Config, value string) error {
+	return func(c *Config, value string) error {
+		v, err := parse(value)
+		if err != nil {
+			return err
+		}
+
+		*field(c) = v
+		return nil
+	}
+}
+
+func parseString(s string) (string, error) {
+	return s, nil
+}
+
+func parseInt64(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
+
+func parseFloat64(s string) (float64, error) {
+	return strconv.ParseFloat(s, 64)
+}
+
+// ParseIDs parses comma separated list of Telegram IDs.
+func ParseIDs(s string) ([]int64, error) {
+	var ids []int64
+
+	for _, field := range strings.Split(s, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
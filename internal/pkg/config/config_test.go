@@ -0,0 +1,322 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/access"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+	"github.com/mezk/tg-reminder/internal/pkg/notifier"
+	"github.com/mezk/tg-reminder/internal/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testYAML = `
+db_file: /srv/var/tg-reminder.db
+debug: true
+log_format: json
+shutdown_timeout: 10s
+telegram:
+  api_token: yaml-token
+  updates_timeout: 30s
+backup:
+  dir: /srv/var/backup
+  interval: 24h
+  retention: 168h
+access:
+  mode: allowlist
+  owner_id: 1
+  allowed_users: [2, 3]
+  admin_users: [4]
+notifier:
+  interval: 30s
+  batch_size: 50
+outbox:
+  max_attempts: 5
+  max_backoff: 10m
+catch_up:
+  policy: summary
+  max_age: 3h
+broadcast:
+  rate: 10
+tracing:
+  exporter: otlp
+  endpoint: http://collector:4318
+  sample_ratio: 0.5
+api:
+  addr: :8080
+web:
+  addr: :8081
+  url: https://reminder.example.com
+channels:
+  timeout: 5s
+  smtp:
+    addr: smtp.example.com:587
+    username: bot
+    password: yaml-password
+    from: bot@example.com
+  push:
+    url: https://ntfy.sh
+webhooks:
+  max_attempts: 5
+  timeout: 3s
+`
+
+const testTOML = `
+db_file = "/srv/var/tg-reminder.db"
+
+[telegram]
+api_token = "toml-token"
+handler_timeout = "10s"
+workers = 2
+
+[access]
+mode = "invite"
+owner_id = 1
+
+[notifier]
+interval = "2m"
+
+[outbox]
+interval = "5s"
+`
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	yamlFile := writeFile("config.yml", testYAML)
+	tomlFile := writeFile("config.toml", testTOML)
+	tokenFile := writeFile("token", "file-token\n")
+	unknownFieldFile := writeFile("unknown.yaml", "db_file: foo\nfoo: bar\n")
+	unknownTOMLFieldFile := writeFile("unknown.toml", "db_file = \"foo\"\nfoo = \"bar\"\n")
+	jsonFile := writeFile("config.json", "{}")
+
+	required := map[string]string{"DB_FILE": "/srv/var/tg-reminder.db", "TELEGRAM_APITOKEN": "env-token"}
+
+	testCases := []struct {
+		name   string
+		args   []string
+		env    map[string]string
+		expCfg func(c *Config)
+		expErr string
+	}{
+		{
+			name: "success: defaults, DEBUG is not set",
+			env:  required,
+			expCfg: func(c *Config) {
+				c.DBFile = "/srv/var/tg-reminder.db"
+				c.Telegram.APIToken = "env-token"
+			},
+		},
+		{
+			name: "success: yaml file",
+			args: []string{"--config", yamlFile},
+			expCfg: func(c *Config) {
+				c.DBFile = "/srv/var/tg-reminder.db"
+				c.Debug = true
+				c.LogFormat = logging.FormatJSON
+				c.ShutdownTimeout = 10 * time.Second
+				c.Telegram.APIToken = "yaml-token"
+				c.Telegram.UpdatesTimeout = 30 * time.Second
+				c.Backup = Backup{Dir: "/srv/var/backup", Interval: 24 * time.Hour, Retention: 168 * time.Hour}
+				c.Access = Access{Mode: access.ModeAllowlist, OwnerID: 1, AllowedUsers: []int64{2, 3}, AdminUsers: []int64{4}}
+				c.Notifier = Notifier{Interval: 30 * time.Second, BatchSize: 50}
+				c.Outbox = Outbox{Interval: time.Second, BatchSize: 50, MaxAttempts: 5, MaxBackoff: 10 * time.Minute}
+				c.CatchUp = CatchUp{Policy: notifier.CatchUpSummary, MaxAge: 3 * time.Hour}
+				c.Broadcast.Rate = 10
+				c.Tracing = Tracing{Exporter: tracing.ExporterOTLP, Endpoint: "http://collector:4318", SampleRatio: 0.5}
+				c.API.Addr = ":8080"
+				c.Web = Web{Addr: ":8081", URL: "https://reminder.example.com"}
+				c.Channels = Channels{
+					Timeout: 5 * time.Second,
+					SMTP:    SMTP{Addr: "smtp.example.com:587", Username: "bot", Password: "yaml-password", From: "bot@example.com"},
+					Push:    Push{URL: "https://ntfy.sh"},
+				}
+				c.Webhooks.MaxAttempts = 5
+				c.Webhooks.Timeout = 3 * time.Second
+			},
+		},
+		{
+			name: "success: toml file set by env",
+			env:  map[string]string{"CONFIG_FILE": tomlFile},
+			expCfg: func(c *Config) {
+				c.DBFile = "/srv/var/tg-reminder.db"
+				c.Telegram.APIToken = "toml-token"
+				c.Telegram.HandlerTimeout = 10 * time.Second
+				c.Telegram.Workers = 2
+				c.Access.Mode = access.ModeInvite
+				c.Access.OwnerID = 1
+				c.Notifier.Interval = 2 * time.Minute
+				c.Outbox.Interval = 5 * time.Second
+			},
+		},
+		{
+			name: "success: env overrides file, flag overrides env",
+			args: []string{"--config", yamlFile, "--notifier-batch-size", "20", "--debug=false"},
+			env: map[string]string{
+				"TELEGRAM_APITOKEN":     "env-token",
+				"NOTIFIER_BATCH_SIZE":   "30",
+				"OUTBOX_MAX_ATTEMPTS":   "3",
+				"WEBHOOKS_MAX_ATTEMPTS": "7",
+				"CATCH_UP_POLICY":       "skip",
+				"ACCESS_ALLOWED_USERS":  "5, 6",
+				"ACCESS_MODE":           "open",
+				"TRACING_EXPORTER":      "stdout",
+				"API_ADDR":              "127.0.0.1:9090",
+				"WEB_URL":               "http://127.0.0.1:9091",
+				"SMTP_PASSWORD":         "env-password",
+				"PUSH_TOKEN":            "env-token",
+			},
+			expCfg: func(c *Config) {
+				c.DBFile = "/srv/var/tg-reminder.db"
+				c.LogFormat = logging.FormatJSON
+				c.ShutdownTimeout = 10 * time.Second
+				c.Telegram.APIToken = "env-token"
+				c.Telegram.UpdatesTimeout = 30 * time.Second
+				c.Backup = Backup{Dir: "/srv/var/backup", Interval: 24 * time.Hour, Retention: 168 * time.Hour}
+				c.Access = Access{Mode: access.ModeOpen, OwnerID: 1, AllowedUsers: []int64{5, 6}, AdminUsers: []int64{4}}
+				c.Notifier = Notifier{Interval: 30 * time.Second, BatchSize: 20}
+				c.Outbox = Outbox{Interval: time.Second, BatchSize: 50, MaxAttempts: 3, MaxBackoff: 10 * time.Minute}
+				c.CatchUp = CatchUp{Policy: notifier.CatchUpSkip, MaxAge: 3 * time.Hour}
+				c.Broadcast.Rate = 10
+				c.Tracing = Tracing{Exporter: tracing.ExporterStdout, Endpoint: "http://collector:4318", SampleRatio: 0.5}
+				c.API.Addr = "127.0.0.1:9090"
+				c.Web = Web{Addr: ":8081", URL: "http://127.0.0.1:9091"}
+				c.Channels = Channels{
+					Timeout: 5 * time.Second,
+					SMTP:    SMTP{Addr: "smtp.example.com:587", Username: "bot", Password: "env-password", From: "bot@example.com"},
+					Push:    Push{URL: "https://ntfy.sh", Token: "env-token"},
+				}
+				c.Webhooks.MaxAttempts = 7
+				c.Webhooks.Timeout = 3 * time.Second
+			},
+		},
+		{
+			name: "success: secret from file",
+			env:  map[string]string{"DB_FILE": "/srv/var/tg-reminder.db", "TELEGRAM_APITOKEN_FILE": tokenFile},
+			expCfg: func(c *Config) {
+				c.DBFile = "/srv/var/tg-reminder.db"
+				c.Telegram.APIToken = "file-token"
+			},
+		},
+		{
+			name:   "error: env variable and file are both set",
+			env:    map[string]string{"DB_FILE": "foo", "TELEGRAM_APITOKEN": "token", "TELEGRAM_APITOKEN_FILE": tokenFile},
+			expErr: "only one of TELEGRAM_APITOKEN and TELEGRAM_APITOKEN_FILE env variables can be set",
+		},
+		{
+			name:   "error: can't read secret file",
+			env:    map[string]string{"TELEGRAM_APITOKEN_FILE": filepath.Join(dir, "unknown")},
+			expErr: "failed to read TELEGRAM_APITOKEN_FILE: open " + filepath.Join(dir, "unknown") + ": no such file or directory",
+		},
+		{
+			name:   "error: invalid env variables and flags are aggregated",
+			args:   []string{"--notifier-interval", "foo"},
+			env:    map[string]string{"DEBUG": "maybe", "LOG_FORMAT": "xml", "OWNER_ID": "bar", "ACCESS_MODE": "closed"},
+			expErr: "failed to parse DEBUG env variable: strconv.ParseBool: parsing \"maybe\": invalid syntax\nfailed to parse LOG_FORMAT env variable: unknown log format \"xml\"\nfailed to parse ACCESS_MODE env variable: unknown access mode \"closed\"\nfailed to parse OWNER_ID env variable: strconv.ParseInt: parsing \"bar\": invalid syntax\nfailed to parse --notifier-interval flag: time: invalid duration \"foo\"",
+		},
+		{
+			name:   "error: validation errors are aggregated",
+			args:   []string{"--backup-dir", "/tmp", "--broadcast-rate", "100", "--notifier-interval", "10ms", "--access-mode", "invite", "--shutdown-timeout", "0s", "--telegram-queue-size", "0", "--outbox-max-attempts", "0", "--catch-up-max-age", "0s", "--tracing-exporter", "otlp", "--tracing-endpoint", "", "--tracing-sample-ratio", "2", "--api-addr", "8080", "--web-addr", ":8081", "--web-url", "/dashboard", "--channels-timeout", "0s", "--smtp-addr", "smtp.example.com", "--push-url", "ntfy.sh", "--webhooks-timeout", "0s"},
+			expErr: "db_file is required\nshutdown_timeout must be positive, got 0s\ntelegram.api_token is required\ntelegram.queue_size must be positive, got 0\nbackup.interval must be positive when backup.dir is set, got 0s\nbackup.retention must be positive when backup.dir is set, got 0s\naccess.owner_id is required for invite access mode\nnotifier.interval must be at least 1s, got 10ms\noutbox.max_attempts must be positive, got 0\ncatch_up.max_age must be positive, got 0s\nbroadcast.rate must be between 1 and 30, got 100\ntracing.endpoint is required for otlp exporter\ntracing.sample_ratio must be between 0 and 1, got 2\napi.addr: address 8080: missing port in address\nweb.url must be absolute http or https URL when web.addr is set, got \"/dashboard\"\nchannels.timeout must be positive, got 0s\nchannels.smtp.addr: address smtp.example.com: missing port in address\nchannels.smtp.from must be email address when channels.smtp.addr is set, got \"\"\nchannels.push.url must be absolute http or https URL, got \"ntfy.sh\"\nwebhooks.timeout must be positive, got 0s",
+		},
+		{
+			name:   "error: unknown field in yaml file",
+			args:   []string{"--config", unknownFieldFile},
+			expErr: "failed to parse config file " + unknownFieldFile + ": yaml: unmarshal errors:\n  line 2: field foo not found in type config.Config",
+		},
+		{
+			name:   "error: unknown field in toml file",
+			args:   []string{"--config", unknownTOMLFieldFile},
+			expErr: "failed to parse config file " + unknownTOMLFieldFile + ": unknown fields [foo]",
+		},
+		{
+			name:   "error: unsupported config file format",
+			args:   []string{"--config", jsonFile},
+			expErr: `unsupported config file format ".json", expected .yaml, .yml or .toml`,
+		},
+		{
+			name:   "error: unknown flag",
+			args:   []string{"--foo"},
+			expErr: "flag provided but not defined: -foo",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			getenv := func(key string) string {
+				return tc.env[key]
+			}
+
+			cfg, err := Load(tc.args, getenv)
+
+			if tc.expErr != "" {
+				assert.EqualError(t, err, tc.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			expCfg := Default()
+			tc.expCfg(&expCfg)
+			assert.Equal(t, expCfg, cfg)
+		})
+	}
+}
+
+func TestConfig_AdminIDs(t *testing.T) {
+	t.Parallel()
+
+	cfg := Default()
+	assert.Empty(t, cfg.AdminIDs())
+
+	cfg.Access.AdminUsers = []int64{1, 2}
+	cfg.Access.OwnerID = 3
+	assert.Equal(t, []int64{1, 2, 3}, cfg.AdminIDs())
+	assert.Equal(t, []int64{1, 2}, cfg.Access.AdminUsers)
+}
+
+func TestConfig_YAML(t *testing.T) {
+	t.Parallel()
+
+	cfg := Default()
+	cfg.Telegram.APIToken = "secret"
+	cfg.Channels.SMTP.Password = "secret"
+	cfg.Channels.Push.Token = "secret"
+
+	out, err := cfg.YAML()
+	require.NoError(t, err)
+	assert.Contains(t, out, "api_token: '********'")
+	assert.Contains(t, out, "password: '********'")
+	assert.Contains(t, out, "token: '********'")
+	assert.Contains(t, out, "interval: 1m0s")
+	assert.NotContains(t, out, "secret")
+	assert.Equal(t, "secret", cfg.Telegram.APIToken)
+}
+
+func TestParseIDs(t *testing.T) {
+	t.Parallel()
+
+	ids, err := ParseIDs(" 1, 2,,3 ")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+
+	ids, err = ParseIDs("")
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+
+	_, err = ParseIDs("1,foo")
+	assert.Error(t, err)
+}
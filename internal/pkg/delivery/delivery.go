@@ -0,0 +1,170 @@
+// Package delivery implements HTTP POST requests to webhooks and push servers and the retry policy of failed deliveries.
+// Outbox, notification channels and webhook dispatcher deliver messages with it, so they fail and retry alike.
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/sender"
+)
+
+var timeNowUTC = func() time.Time {
+	return time.Now().UTC()
+}
+
+// Headers of signed request, see [Request.Secret].
+const (
+	HeaderTimestamp = "X-Reminder-Timestamp"
+	HeaderSignature = "X-Reminder-Signature"
+)
+
+// userAgent - User-Agent header of requests.
+const userAgent = "tg-reminder"
+
+// minBackoff - delay before the first retry, every next retry waits twice as long.
+const minBackoff = 5 * time.Second
+
+// maxErrorBody - max number of bytes of response body included in error.
+const maxErrorBody = 512
+
+// errLocalAddress - host resolves to address of the bot's own network.
+var errLocalAddress = errors.New("local address is not allowed")
+
+// Request - POST request to deliver.
+type Request struct {
+	URL  string
+	Body []byte
+	// Content-Type is application/json if it isn't set
+	Header http.Header
+	// body is signed with secret if it's set, see [domain.SignWebhookPayload]
+	Secret string
+}
+
+// Post sends request by client, returns response status, 0 if there is no response. Any status except 2xx is an error.
+// Response 429 with Retry-After header is returned as [sender.RetryAfterError], other client errors except 408
+// and requests to local addresses are wrapped with [sender.ErrPermanent], they aren't retried.
+// Signed request has the time of signature in [HeaderTimestamp] and signature in [HeaderSignature] as "sha256=<hex>".
+func Post(ctx context.Context, client *http.Client, r Request) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for k, v := range r.Header {
+		req.Header[k] = v
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	if r.Secret != "" {
+		now := timeNowUTC()
+		req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+		req.Header.Set(HeaderSignature, "sha256="+domain.SignWebhookPayload(r.Secret, now, r.Body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(err, errLocalAddress) {
+			return 0, fmt.Errorf("%w: failed to send request: %w", sender.ErrPermanent, err)
+		}
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode, checkResponse(resp)
+}
+
+// checkResponse returns error if response status isn't 2xx, see [Post].
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	err := fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	if body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody)); len(bytes.TrimSpace(body)) > 0 {
+		err = fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		if seconds, parseErr := strconv.Atoi(resp.Header.Get("Retry-After")); parseErr == nil && seconds > 0 {
+			return &sender.RetryAfterError{After: time.Duration(seconds) * time.Second, Err: err}
+		}
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusRequestTimeout:
+		return fmt.Errorf("%w: %w", sender.ErrPermanent, err)
+	}
+
+	return err
+}
+
+// NewClient returns client for URLs set by users, timeout limits every request.
+// Connections to addresses which aren't public are refused after DNS resolution, so URL of user can't be used
+// to reach services of the bot's network. Redirects aren't followed and proxy isn't used for the same reason.
+func NewClient(timeout time.Duration) *http.Client {
+	return newClient(timeout, domain.IsPublicAddr)
+}
+
+// newClient returns client which connects only to addresses allowed by isAllowed, see [NewClient].
+func newClient(timeout time.Duration, isAllowed func(netip.Addr) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !isAllowed(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", errLocalAddress, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// RetryDelay returns delay before the next attempt after err. Delay grows exponentially with number of attempts
+// up to maxBackoff, but it's never shorter than the delay requested with [sender.RetryAfterError].
+func RetryDelay(attempts int, maxBackoff time.Duration, err error) time.Duration {
+	delay := backoff(attempts, maxBackoff)
+
+	var retryErr *sender.RetryAfterError
+	if errors.As(err, &retryErr) && retryErr.After > delay {
+		delay = retryErr.After
+	}
+
+	return delay
+}
+
+// backoff returns delay before the next attempt, it grows exponentially up to maxBackoff.
+func backoff(attempts int, maxBackoff time.Duration) time.Duration {
+	delay := minBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxBackoff)
+}
@@ -0,0 +1,210 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/sender"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPost(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tmpTimeNowUTC := timeNowUTC
+	defer func() { timeNowUTC = tmpTimeNowUTC }()
+	timeNowUTC = func() time.Time { return now }
+
+	// test servers listen on loopback
+	client := newClient(time.Second, func(netip.Addr) bool { return true })
+
+	t.Run("success: signed request", func(t *testing.T) {
+		const payload = `{"event":"reminder"}`
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, payload, string(body))
+
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "tg-reminder", r.Header.Get("User-Agent"))
+			assert.Equal(t, "reminder", r.Header.Get("X-Reminder-Event"))
+			assert.Equal(t, "1717243200", r.Header.Get(HeaderTimestamp))
+			assert.Equal(t, "sha256="+domain.SignWebhookPayload("whsec_test", now, []byte(payload)), r.Header.Get(HeaderSignature))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		status, err := Post(context.TODO(), client, Request{
+			URL:    srv.URL,
+			Body:   []byte(payload),
+			Header: http.Header{"X-Reminder-Event": {"reminder"}},
+			Secret: "whsec_test",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, status)
+	})
+
+	t.Run("success: unsigned request with own content type", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "text/plain", r.Header.Get("Content-Type"))
+			assert.Empty(t, r.Header.Get(HeaderTimestamp))
+			assert.Empty(t, r.Header.Get(HeaderSignature))
+		}))
+		defer srv.Close()
+
+		status, err := Post(context.TODO(), client, Request{URL: srv.URL, Header: http.Header{"Content-Type": {"text/plain"}}})
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, status)
+	})
+
+	t.Run("error: unexpected status", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		status, err := Post(context.TODO(), client, Request{URL: srv.URL})
+
+		require.EqualError(t, err, "unexpected response status 500: boom")
+		assert.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("error: timeout", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}))
+		defer srv.Close()
+
+		status, err := Post(context.TODO(), newClient(50*time.Millisecond, func(netip.Addr) bool { return true }), Request{URL: srv.URL})
+
+		require.ErrorContains(t, err, "failed to send request")
+		assert.False(t, errors.Is(err, sender.ErrPermanent))
+		assert.Zero(t, status)
+	})
+
+	t.Run("error: local address", func(t *testing.T) {
+		var called bool
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer srv.Close()
+
+		// address is checked when connection is dialed, so host names resolved to loopback are refused too
+		status, err := Post(context.TODO(), NewClient(time.Second), Request{URL: srv.URL})
+
+		require.ErrorIs(t, err, sender.ErrPermanent)
+		require.ErrorIs(t, err, errLocalAddress)
+		assert.Zero(t, status)
+		assert.False(t, called)
+	})
+
+	t.Run("error: redirect isn't followed", func(t *testing.T) {
+		var redirected bool
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/internal" {
+				redirected = true
+				return
+			}
+			http.Redirect(w, r, "/internal", http.StatusTemporaryRedirect)
+		}))
+		defer srv.Close()
+
+		status, err := Post(context.TODO(), client, Request{URL: srv.URL})
+
+		require.ErrorContains(t, err, "unexpected response status 307")
+		assert.Equal(t, http.StatusTemporaryRedirect, status)
+		assert.False(t, redirected)
+	})
+}
+
+func Test_checkResponse(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		status        int
+		body          string
+		retryAfter    string
+		expErr        string
+		expPermanent  bool
+		expRetryAfter time.Duration
+	}{
+		{name: "success: ok", status: http.StatusOK},
+		{name: "success: no content", status: http.StatusNoContent},
+		{name: "error: server error is retried", status: http.StatusBadGateway, body: "boom\n", expErr: "unexpected response status 502: boom"},
+		{name: "error: server error without body", status: http.StatusBadGateway, expErr: "unexpected response status 502"},
+		{name: "error: timeout is retried", status: http.StatusRequestTimeout, body: "boom\n", expErr: "unexpected response status 408: boom"},
+		{
+			name: "error: too many requests with retry after", status: http.StatusTooManyRequests, body: "boom\n", retryAfter: "30",
+			expErr: "retry after 30s: unexpected response status 429: boom", expRetryAfter: 30 * time.Second,
+		},
+		{name: "error: too many requests without retry after", status: http.StatusTooManyRequests, body: "boom\n", expErr: "unexpected response status 429: boom"},
+		{name: "error: bad request is permanent", status: http.StatusBadRequest, body: "boom\n", expErr: "message is rejected: unexpected response status 400: boom", expPermanent: true},
+		{name: "error: not found is permanent", status: http.StatusNotFound, body: "boom\n", expErr: "message is rejected: unexpected response status 404: boom", expPermanent: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := &http.Response{StatusCode: tc.status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(tc.body))}
+			if tc.retryAfter != "" {
+				resp.Header.Set("Retry-After", tc.retryAfter)
+			}
+
+			err := checkResponse(resp)
+
+			if tc.expErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.expErr)
+			assert.Equal(t, tc.expPermanent, errors.Is(err, sender.ErrPermanent))
+
+			var retryErr *sender.RetryAfterError
+			if tc.expRetryAfter > 0 {
+				require.ErrorAs(t, err, &retryErr)
+				assert.Equal(t, tc.expRetryAfter, retryErr.After)
+			} else {
+				assert.False(t, errors.As(err, &retryErr))
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	t.Parallel()
+
+	err := errors.New("boom")
+	retryErr := &sender.RetryAfterError{After: 10 * time.Minute, Err: err}
+
+	assert.Equal(t, 5*time.Second, RetryDelay(1, time.Hour, err))
+	assert.Equal(t, 20*time.Second, RetryDelay(3, time.Hour, fmt.Errorf("wrapped: %w", err)))
+	assert.Equal(t, 10*time.Minute, RetryDelay(1, time.Hour, fmt.Errorf("wrapped: %w", retryErr)))
+	// delay requested by server isn't limited by max backoff
+	assert.Equal(t, 10*time.Minute, RetryDelay(1, time.Minute, retryErr))
+	assert.Equal(t, 40*time.Second, RetryDelay(4, time.Hour, &sender.RetryAfterError{After: time.Second, Err: err}))
+}
+
+func Test_backoff(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 5*time.Second, backoff(1, time.Hour))
+	assert.Equal(t, 10*time.Second, backoff(2, time.Hour))
+	assert.Equal(t, 40*time.Second, backoff(4, time.Hour))
+	assert.Equal(t, time.Hour, backoff(20, time.Hour))
+	assert.Equal(t, time.Second, backoff(1, time.Second))
+}
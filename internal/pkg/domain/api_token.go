@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+const (
+	// APITokenPrefix - prefix of API tokens, it makes leaked tokens easy to find.
+	APITokenPrefix = "tgr_"
+	// HookTokenPrefix - prefix of incoming webhook tokens.
+	HookTokenPrefix = "tgh_"
+)
+
+// APITokenKind - kind of API token.
+type APITokenKind string
+
+const (
+	// APITokenKindAPI - token of REST API, it gives full access to reminders of user in chat.
+	APITokenKindAPI APITokenKind = "api"
+	// APITokenKindHook - token of incoming webhook, it's only able to create reminders.
+	// It's a part of webhook URL, so it's safe to pass it to CI or monitoring.
+	APITokenKindHook APITokenKind = "hook"
+)
+
+// APIToken - token to work with reminders of user in chat via REST API. Only hash of token is stored.
+// User has one token of every kind.
+type APIToken struct {
+	TokenHash string       `db:"token_hash"`
+	Kind      APITokenKind `db:"kind"`
+	UserID    int64        `db:"user_id"`
+	ChatID    int64        `db:"chat_id"`
+	CreatedAt time.Time    `db:"created_at"`
+}
+
+// String implements [fmt.Stringer], token hash is not printed.
+func (t APIToken) String() string {
+	return fmt.Sprintf("[Kind: %s, UserID: %d, ChatID: %d]", t.Kind, t.UserID, t.ChatID)
+}
+
+// HashAPIToken returns hash of token to store and to look it up.
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashAPIToken(t *testing.T) {
+	t.Parallel()
+
+	hash := HashAPIToken("tgr_0123456789abcdef")
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, HashAPIToken("tgr_0123456789abcdef"))
+	assert.NotEqual(t, hash, HashAPIToken("tgr_0123456789abcdee"))
+
+	assert.Equal(t, "[Kind: hook, UserID: 1, ChatID: 2]", APIToken{TokenHash: hash, Kind: APITokenKindHook, UserID: 1, ChatID: 2}.String())
+}
@@ -55,6 +55,75 @@ func (s *BotState) SetReminderText(text string) {
 	s.Context.ReminderText = text
 }
 
+// SetReminderPriority associate reminder priority with current bot state.
+func (s *BotState) SetReminderPriority(priority ReminderPriority) {
+	if s == nil {
+		return
+	}
+
+	if s.Context == nil {
+		s.Context = &BotStateContext{}
+	}
+
+	s.Context.ReminderPriority = priority
+}
+
+// ReminderPriority returns reminder priority associated with current bot state.
+// Returns [ReminderPriorityNormal] if priority is not set.
+func (s BotState) ReminderPriority() ReminderPriority {
+	if s.Context == nil || s.Context.ReminderPriority == "" {
+		return ReminderPriorityNormal
+	}
+
+	return s.Context.ReminderPriority
+}
+
+// SetReminderChecklist associate checklist items of reminder being created with current bot state.
+func (s *BotState) SetReminderChecklist(items []string) {
+	if s == nil {
+		return
+	}
+
+	if s.Context == nil {
+		s.Context = &BotStateContext{}
+	}
+
+	s.Context.ReminderChecklist = items
+}
+
+// ReminderChecklist returns checklist items of reminder being created associated with current bot state.
+func (s BotState) ReminderChecklist() []string {
+	if s.Context == nil {
+		return nil
+	}
+
+	return s.Context.ReminderChecklist
+}
+
+// SetReminderRemindAt associate remindAt of reminder being created with current bot state. RemindAt is stored in UTC.
+func (s *BotState) SetReminderRemindAt(remindAt time.Time) {
+	if s == nil {
+		return
+	}
+
+	if s.Context == nil {
+		s.Context = &BotStateContext{}
+	}
+
+	remindAt = remindAt.UTC()
+	s.Context.ReminderRemindAt = &remindAt
+}
+
+// ReminderRemindAt returns remindAt of reminder being created associated with current bot state.
+// Returns false if remindAt is not set.
+func (s BotState) ReminderRemindAt() (time.Time, bool) {
+	if s.Context == nil || s.Context.ReminderRemindAt == nil {
+		return time.Time{}, false
+	}
+
+	return *s.Context.ReminderRemindAt, true
+}
+
 // ReminderText returns reminder text associated with current bot state.
 func (s BotState) ReminderText() string {
 	if s.Context == nil {
@@ -78,20 +147,31 @@ const (
 	BotStateNameEditReminder BotStateName = "edit_reminder"
 	// BotStateNameRemoveReminder - user clicked on remode reminder button.
 	BotStateNameRemoveReminder BotStateName = "remove_reminder"
+	// BotStateNameReminderHistory - user clicked on reminder history button.
+	BotStateNameReminderHistory BotStateName = "reminder_history"
 	// BotStateNameMyReminders - user sent /my_remidners command.
 	BotStateNameMyReminders BotStateName = "my_reminders"
 	// BotStateNameEnableReminders - user sent /enable_reminders command.
 	BotStateNameEnableReminders BotStateName = "enable_reminders"
 	// BotStateNameDisableReminders - user sent /disable_reminders command.
 	BotStateNameDisableReminders BotStateName = "disable_reminders"
+	// BotStateNameSettings - user sent /settings command.
+	BotStateNameSettings BotStateName = "settings"
 	// BotStateNameEnterReminAt - bot is waiting on user entering remindAt.
 	BotStateNameEnterReminAt BotStateName = "enter_remind_at"
+	// BotStateNameConfirmRemindAt - bot is waiting on user confirming parsed remindAt.
+	BotStateNameConfirmRemindAt BotStateName = "confirm_remind_at"
+	// BotStateNameAddChecklistItems - bot is waiting on user entering checklist items of reminder.
+	BotStateNameAddChecklistItems BotStateName = "add_checklist_items"
 )
 
 // BotStateContext is a metadata associated with c\urrent bot state.
 type BotStateContext struct {
-	ReminderID   int64  `json:"reminder_id,omitempty"`
-	ReminderText string `json:"reminder_text,omitempty"`
+	ReminderID        int64            `json:"reminder_id,omitempty"`
+	ReminderText      string           `json:"reminder_text,omitempty"`
+	ReminderPriority  ReminderPriority `json:"reminder_priority,omitempty"`
+	ReminderChecklist []string         `json:"reminder_checklist,omitempty"`
+	ReminderRemindAt  *time.Time       `json:"reminder_remind_at,omitempty"`
 }
 
 // Scan implements [sql.Scanner].
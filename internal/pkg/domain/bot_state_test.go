@@ -2,6 +2,7 @@ package domain
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -49,6 +50,41 @@ func TestBotState_SetReminderText(t *testing.T) {
 	assert.Equal(t, "FooBar", state.Context.ReminderText)
 }
 
+func TestBotState_ReminderPriority(t *testing.T) {
+	t.Parallel()
+	state := BotState{}
+	assert.Equal(t, ReminderPriorityNormal, state.ReminderPriority())
+
+	state.SetReminderPriority(ReminderPriorityCritical)
+	assert.Equal(t, ReminderPriorityCritical, state.ReminderPriority())
+
+	var nilState *BotState
+	assert.NotPanics(t, func() {
+		nilState.SetReminderPriority(ReminderPriorityLow)
+	})
+}
+
+func TestBotState_ReminderChecklist(t *testing.T) {
+	t.Parallel()
+	state := BotState{}
+	assert.Empty(t, state.ReminderChecklist())
+
+	state.SetReminderChecklist([]string{"foo", "bar"})
+	assert.Equal(t, []string{"foo", "bar"}, state.ReminderChecklist())
+}
+
+func TestBotState_ReminderRemindAt(t *testing.T) {
+	t.Parallel()
+	state := BotState{}
+	_, ok := state.ReminderRemindAt()
+	assert.False(t, ok)
+
+	state.SetReminderRemindAt(time.Date(2024, 1, 1, 3, 0, 0, 0, MoscowTime(time.Time{}).Location()))
+	remindAt, ok := state.ReminderRemindAt()
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), remindAt)
+}
+
 func TestBotState_String(t *testing.T) {
 	t.Parallel()
 	assert.Equal(t, "[UserID: 1, Name: Angelos Casados]", BotState{
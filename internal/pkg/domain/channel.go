@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Channel - way notifications of reminders are delivered to user.
+type Channel string
+
+const (
+	// ChannelTelegram - message in the chat reminder was created in, the default channel.
+	ChannelTelegram Channel = "telegram"
+	// ChannelEmail - email sent via SMTP to user's address.
+	ChannelEmail Channel = "email"
+	// ChannelWebhook - JSON POST request to user's URL.
+	ChannelWebhook Channel = "webhook"
+	// ChannelPush - push notification published to user's topic of ntfy-style server.
+	ChannelPush Channel = "push"
+)
+
+// AllChannels - all supported channels.
+var AllChannels = Channels{ChannelTelegram, ChannelEmail, ChannelWebhook, ChannelPush}
+
+// ParseChannel parses channel name.
+func ParseChannel(s string) (Channel, error) {
+	ch := Channel(strings.ToLower(strings.TrimSpace(s)))
+	if !slices.Contains(AllChannels, ch) {
+		return "", fmt.Errorf("unknown channel %q", s)
+	}
+
+	return ch, nil
+}
+
+// Label returns russian label of channel.
+func (c Channel) Label() string {
+	switch c {
+	case ChannelTelegram:
+		return "Telegram"
+	case ChannelEmail:
+		return "Почта"
+	case ChannelWebhook:
+		return "Вебхук"
+	case ChannelPush:
+		return "Push"
+	default:
+		return string(c)
+	}
+}
+
+// Channels - set of channels.
+type Channels []Channel
+
+// ParseChannels parses comma or whitespace separated channels, duplicates are skipped.
+func ParseChannels(s string) (Channels, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n'
+	})
+
+	if len(fields) == 0 {
+		return nil, errors.New("no channels")
+	}
+
+	channels := make(Channels, 0, len(fields))
+	for _, field := range fields {
+		ch, err := ParseChannel(field)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(channels, ch) {
+			channels = append(channels, ch)
+		}
+	}
+
+	return channels, nil
+}
+
+// Or returns channels if they're not empty, otherwise returns defaults.
+func (c Channels) Or(defaults Channels) Channels {
+	if len(c) == 0 {
+		return defaults
+	}
+
+	return c
+}
+
+// Format formats channels as labels separated by comma.
+func (c Channels) Format() string {
+	labels := make([]string, 0, len(c))
+	for _, ch := range c {
+		labels = append(labels, ch.Label())
+	}
+
+	return strings.Join(labels, ", ")
+}
+
+// String implements [fmt.Stringer].
+func (c Channels) String() string {
+	fields := make([]string, 0, len(c))
+	for _, ch := range c {
+		fields = append(fields, string(ch))
+	}
+
+	return strings.Join(fields, ",")
+}
+
+// Scan implements [sql.Scanner]. Channels are stored as comma separated values.
+func (c *Channels) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case nil:
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("unsupported channels type %T", value)
+	}
+
+	*c = nil
+	if s == "" {
+		return nil
+	}
+
+	for _, field := range strings.Split(s, ",") {
+		*c = append(*c, Channel(field))
+	}
+
+	return nil
+}
+
+// Value implements [driver.Valuer].
+func (c Channels) Value() (driver.Value, error) {
+	return c.String(), nil
+}
+
+// Notification - notification of reminder delivered by channel other than Telegram.
+// It's stored as payload of outbox message, so every channel is retried independently.
+type Notification struct {
+	// address of recipient: email address, webhook URL or push topic
+	To         string           `json:"to"`
+	ReminderID int64            `json:"reminder_id"`
+	UserID     int64            `json:"user_id"`
+	ChatID     int64            `json:"chat_id"`
+	Title      string           `json:"title"`
+	Message    string           `json:"message"`
+	Priority   ReminderPriority `json:"priority"`
+	RemindAt   time.Time        `json:"remind_at"`
+	// set for advance notice, which is sent before reminder time
+	PreNotice bool `json:"pre_notice"`
+	// secret to sign request with, set for webhook only
+	Secret string `json:"secret,omitempty"`
+}
+
+// NewNotification returns notification of reminder to deliver to address to.
+// Advance notice is built if preNotice is set, now is used to format time left before reminder.
+func NewNotification(r Reminder, to string, preNotice bool, now time.Time) Notification {
+	n := Notification{
+		To:         to,
+		ReminderID: r.ID,
+		UserID:     r.UserID,
+		ChatID:     r.ChatID,
+		Title:      "Напоминание",
+		Priority:   r.Priority,
+		RemindAt:   r.RemindAt,
+		PreNotice:  preNotice,
+	}
+
+	remindAt := MoscowTime(r.RemindAt).Format(layoutTimeOnly)
+	if preNotice {
+		n.Title = "Скоро напоминание"
+		n.Message = fmt.Sprintf("%s\n\nЧерез %s, в %s", r.Text, FormatDuration(r.RemindAt.Sub(now)), remindAt)
+	} else {
+		n.Message = fmt.Sprintf("%s\n\nСегодня в %s", r.Text, remindAt)
+	}
+
+	if len(r.Checklist) > 0 {
+		n.Message += fmt.Sprintf("\nВыполнено %s", r.Checklist.FormatProgress())
+	}
+
+	return n
+}
@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChannels(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		input  string
+		expRes Channels
+		expErr string
+	}{
+		{name: "one", input: "email", expRes: Channels{ChannelEmail}},
+		{name: "many, duplicates are skipped", input: "Telegram, push webhook,push", expRes: Channels{ChannelTelegram, ChannelPush, ChannelWebhook}},
+		{name: "empty", input: " , ", expErr: "no channels"},
+		{name: "unknown", input: "email, sms", expErr: `unknown channel "sms"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := ParseChannels(tc.input)
+			if tc.expErr != "" {
+				require.EqualError(t, err, tc.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expRes, res)
+		})
+	}
+}
+
+func TestChannels_ScanValue(t *testing.T) {
+	t.Parallel()
+
+	value, err := Channels{ChannelTelegram, ChannelEmail}.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "telegram,email", value)
+
+	var c Channels
+	require.NoError(t, c.Scan("telegram,email"))
+	assert.Equal(t, Channels{ChannelTelegram, ChannelEmail}, c)
+	assert.Equal(t, "Telegram, Почта", c.Format())
+
+	require.NoError(t, c.Scan([]byte("")))
+	assert.Nil(t, c)
+
+	require.EqualError(t, c.Scan(1), "unsupported channels type int")
+}
+
+func TestUserSettings_ReminderChannels(t *testing.T) {
+	t.Parallel()
+
+	settings := UserSettings{UserID: 1}
+	assert.Equal(t, Channels{ChannelTelegram}, settings.ReminderChannels(Reminder{}))
+
+	settings.Channels = Channels{ChannelEmail}
+	assert.Equal(t, Channels{ChannelEmail}, settings.ReminderChannels(Reminder{}))
+	assert.Equal(t, Channels{ChannelPush}, settings.ReminderChannels(Reminder{Channels: Channels{ChannelPush}}))
+}
+
+func TestUserSettings_SetChannelAddress(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		channel    Channel
+		address    string
+		expAddress string
+		expErr     string
+	}{
+		{name: "email", channel: ChannelEmail, address: "User <user@example.com>", expAddress: "user@example.com"},
+		{name: "email, invalid", channel: ChannelEmail, address: "user", expErr: `invalid email address "user": mail: missing '@' or angle-addr`},
+		{name: "webhook", channel: ChannelWebhook, address: "https://example.com/hook?key=1", expAddress: "https://example.com/hook?key=1"},
+		{name: "webhook, not http", channel: ChannelWebhook, address: "ftp://example.com", expErr: `invalid webhook url "ftp://example.com": absolute http or https url is expected`},
+		{name: "webhook, localhost", channel: ChannelWebhook, address: "http://localhost:8080/hook", expErr: `invalid webhook url "http://localhost:8080/hook": local address is not allowed`},
+		{name: "webhook, private ip", channel: ChannelWebhook, address: "http://192.168.1.10/hook", expErr: `invalid webhook url "http://192.168.1.10/hook": local address is not allowed`},
+		{name: "push", channel: ChannelPush, address: "my_reminders-1", expAddress: "my_reminders-1"},
+		{name: "push, invalid topic", channel: ChannelPush, address: "a/b", expErr: `invalid push topic "a/b": only letters, digits, '-' and '_' are allowed`},
+		{name: "remove address", channel: ChannelEmail, address: ""},
+		{name: "telegram has no address", channel: ChannelTelegram, address: "foo", expErr: "channel has no address"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			settings := UserSettings{Email: "old@example.com", EmailVerified: true, WebhookURL: "https://example.com/old", WebhookSecret: "whsec_old"}
+			err := settings.SetChannelAddress(tc.channel, tc.address)
+			if tc.expErr != "" {
+				require.EqualError(t, err, tc.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+			if tc.channel == ChannelEmail {
+				assert.Equal(t, tc.expAddress, settings.Email)
+				assert.False(t, settings.EmailVerified, "new email isn't verified")
+				assert.Empty(t, settings.ChannelAddress(tc.channel))
+				return
+			}
+			if tc.channel == ChannelWebhook {
+				assert.Equal(t, tc.expAddress, settings.WebhookURL)
+				assert.Empty(t, settings.WebhookSecret, "new webhook has no secret")
+				assert.Empty(t, settings.ChannelAddress(tc.channel))
+
+				settings.WebhookSecret = "whsec_new"
+			}
+			assert.Equal(t, tc.expAddress, settings.ChannelAddress(tc.channel))
+		})
+	}
+}
+
+func TestUserSettings_SetChannelAddress_sameWebhook(t *testing.T) {
+	t.Parallel()
+
+	settings := UserSettings{WebhookURL: "https://example.com/hook", WebhookSecret: "whsec_test"}
+	require.NoError(t, settings.SetChannelAddress(ChannelWebhook, "https://example.com/hook"))
+
+	assert.Equal(t, "whsec_test", settings.WebhookSecret)
+	assert.Equal(t, "https://example.com/hook", settings.ChannelAddress(ChannelWebhook))
+}
+
+func TestUserSettings_VerifyEmail(t *testing.T) {
+	t.Parallel()
+
+	settings := UserSettings{Email: "user@example.com", EmailCode: "012345"}
+	require.ErrorIs(t, settings.VerifyEmail("999999"), ErrEmailCodeInvalid)
+	require.ErrorIs(t, settings.VerifyEmail("012345"), ErrEmailCodeInvalid, "code is reset after wrong attempt")
+	assert.Empty(t, settings.ChannelAddress(ChannelEmail))
+
+	settings.EmailCode = "012345"
+	require.NoError(t, settings.VerifyEmail("012345"))
+	assert.Equal(t, "user@example.com", settings.ChannelAddress(ChannelEmail))
+	assert.Empty(t, settings.EmailCode)
+
+	require.NoError(t, settings.SetChannelAddress(ChannelEmail, "user@example.com"))
+	assert.True(t, settings.EmailVerified, "the same address stays verified")
+
+	require.NoError(t, settings.SetChannelAddress(ChannelEmail, "other@example.com"))
+	assert.False(t, settings.EmailVerified)
+	require.ErrorIs(t, settings.VerifyEmail(""), ErrEmailCodeInvalid, "no code is sent")
+}
+
+func TestNewNotification(t *testing.T) {
+	t.Parallel()
+
+	remindAt := time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC)
+	r := Reminder{
+		ID:        1,
+		UserID:    2,
+		ChatID:    3,
+		Text:      "Meeting",
+		RemindAt:  remindAt,
+		Priority:  ReminderPriorityHigh,
+		Checklist: Checklist{{Text: "agenda", Done: true}, {Text: "slides"}},
+	}
+
+	n := NewNotification(r, "user@example.com", false, remindAt)
+	assert.Equal(t, Notification{
+		To:         "user@example.com",
+		ReminderID: 1,
+		UserID:     2,
+		ChatID:     3,
+		Title:      "Напоминание",
+		Message:    "Meeting\n\nСегодня в 15:30\nВыполнено 1/2",
+		Priority:   ReminderPriorityHigh,
+		RemindAt:   remindAt,
+	}, n)
+
+	n = NewNotification(r, "topic", true, remindAt.Add(-time.Hour))
+	assert.Equal(t, "Скоро напоминание", n.Title)
+	assert.Equal(t, "Meeting\n\nЧерез 1 ч., в 15:30\nВыполнено 1/2", n.Message)
+	assert.True(t, n.PreNotice)
+}
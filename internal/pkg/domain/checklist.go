@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChecklistItem - item of reminder's checklist.
+type ChecklistItem struct {
+	ID         int64  `db:"id"`
+	ReminderID int64  `db:"reminder_id"`
+	Position   int64  `db:"position"`
+	Text       string `db:"text"`
+	Done       bool   `db:"done"`
+}
+
+func (i ChecklistItem) String() string {
+	return fmt.Sprintf("[ID: %d, ReminderID: %d, Done: %t, Text: %s]", i.ID, i.ReminderID, i.Done, i.Text)
+}
+
+// Checklist - reminder's checklist items ordered by position.
+type Checklist []ChecklistItem
+
+// Texts returns texts of checklist items.
+func (c Checklist) Texts() []string {
+	texts := make([]string, 0, len(c))
+	for _, item := range c {
+		texts = append(texts, item.Text)
+	}
+
+	return texts
+}
+
+// Progress returns number of done items and total number of items.
+func (c Checklist) Progress() (done, total int) {
+	for _, item := range c {
+		if item.Done {
+			done++
+		}
+	}
+
+	return done, len(c)
+}
+
+// IsDone returns true if checklist is not empty and all its items are done.
+func (c Checklist) IsDone() bool {
+	done, total := c.Progress()
+	return total > 0 && done == total
+}
+
+// FormatProgress returns checklist progress like "2/5".
+func (c Checklist) FormatProgress() string {
+	done, total := c.Progress()
+	return fmt.Sprintf("%d/%d", done, total)
+}
+
+// SplitChecklist splits multiline reminder text into a title (the first line) and checklist items (the rest lines).
+// List markers like "-", "*", "•" are cut from items. Returns no items for single line text.
+func SplitChecklist(text string) (string, []string) {
+	lines := strings.Split(text, "\n")
+
+	title := strings.TrimSpace(lines[0])
+	items := ParseChecklistItems(strings.Join(lines[1:], "\n"))
+
+	return title, items
+}
+
+// ParseChecklistItems parses checklist items, one item per non-empty line.
+func ParseChecklistItems(text string) []string {
+	var items []string
+	for _, line := range strings.Split(text, "\n") {
+		item := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*•"))
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
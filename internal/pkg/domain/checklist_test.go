@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecklist_Progress(t *testing.T) {
+	t.Parallel()
+
+	checklist := Checklist{{Text: "foo", Done: true}, {Text: "bar"}}
+	assert.Equal(t, "1/2", checklist.FormatProgress())
+	assert.False(t, checklist.IsDone())
+	assert.Equal(t, []string{"foo", "bar"}, checklist.Texts())
+
+	checklist[1].Done = true
+	assert.Equal(t, "2/2", checklist.FormatProgress())
+	assert.True(t, checklist.IsDone())
+
+	assert.False(t, Checklist{}.IsDone())
+}
+
+func TestSplitChecklist(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		text     string
+		expTitle string
+		expItems []string
+	}{
+		{text: "Buy milk", expTitle: "Buy milk"},
+		{text: "Shopping\nmilk\nbread", expTitle: "Shopping", expItems: []string{"milk", "bread"}},
+		{text: "Shopping\n- milk\n\n * bread \n• eggs", expTitle: "Shopping", expItems: []string{"milk", "bread", "eggs"}},
+	}
+
+	for _, tc := range testCases {
+		title, items := SplitChecklist(tc.text)
+		assert.Equal(t, tc.expTitle, title)
+		assert.Equal(t, tc.expItems, items)
+	}
+}
+
+func TestChecklistItem_String(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "[ID: 1, ReminderID: 2, Done: true, Text: foo]", ChecklistItem{ID: 1, ReminderID: 2, Text: "foo", Done: true}.String())
+}
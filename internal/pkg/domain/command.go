@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"fmt"
 	"strings"
 )
 
@@ -20,6 +21,28 @@ const (
 	BotCommandEnableReminders BotCommand = "/enable_reminders"
 	// BotCommandDisableReminders - is a command to enable all reminders for user. User status will be chanhed to [domain.UserStatusActive].
 	BotCommandDisableReminders BotCommand = "/disable_reminders"
+	// BotCommandSettings is a command to show and change user's quick time and snooze buttons.
+	BotCommandSettings BotCommand = "/settings"
+	// BotCommandAPIToken is a command to issue a token for REST API, the previous token of user is revoked.
+	BotCommandAPIToken BotCommand = "/api_token"
+	// BotCommandHookToken is a command to issue a token of incoming webhook which creates reminders, the previous token of user is revoked.
+	BotCommandHookToken BotCommand = "/hook_token"
+	// BotCommandWeb is a command to get a one-time login link to the web dashboard.
+	BotCommandWeb BotCommand = "/web"
+	// BotCommandWebhooks is a command to register webhooks which receive reminder lifecycle events and to see their delivery log.
+	BotCommandWebhooks BotCommand = "/webhooks"
+	// BotCommandInvite is a command to generate an invite link. Available for the bot owner only.
+	BotCommandInvite BotCommand = "/invite"
+	// BotCommandAdminStats is a command to show bot statistics. Available for admins only.
+	BotCommandAdminStats BotCommand = "/admin_stats"
+	// BotCommandAdminUsers is a command to list users and block or unblock them. Available for admins only.
+	BotCommandAdminUsers BotCommand = "/admin_users"
+	// BotCommandAdminReminder is a command to show reminder details and history of its changes. Available for admins only.
+	BotCommandAdminReminder BotCommand = "/admin_reminder"
+	// BotCommandBroadcast is a command to send an announcement to all active users. Available for admins only.
+	BotCommandBroadcast BotCommand = "/broadcast"
+	// BotCommandMaintenance is a command to toggle maintenance mode. Available for admins only.
+	BotCommandMaintenance BotCommand = "/maintenance"
 )
 
 // String implememts [fmt.Stringer].
@@ -31,3 +54,182 @@ func (c BotCommand) String() string {
 func (c BotCommand) Markdown() string {
 	return strings.Replace(string(c), "_", "\\_", -1)
 }
+
+// Language - language code of bot's texts registered in Telegram.
+type Language string
+
+const (
+	// LanguageDefault - texts for users whose language has no dedicated texts. Bot talks in russian.
+	LanguageDefault Language = ""
+	// LanguageEnglish - texts for users with english interface.
+	LanguageEnglish Language = "en"
+)
+
+// Languages - languages of bot's texts registered in Telegram.
+var Languages = []Language{LanguageDefault, LanguageEnglish}
+
+// BotCommandAccess - who can use a bot command.
+type BotCommandAccess int
+
+const (
+	// BotCommandAccessUser - command is available for all users.
+	BotCommandAccessUser BotCommandAccess = iota
+	// BotCommandAccessAdmin - command is available for admins only.
+	BotCommandAccessAdmin
+	// BotCommandAccessOwner - command is available for the bot owner only.
+	BotCommandAccessOwner
+)
+
+// BotCommandInfo - description of a bot command in the command registry.
+type BotCommandInfo struct {
+	Command      BotCommand
+	Emoji        string
+	Descriptions map[Language]string // description of command per language, [LanguageDefault] one is shown in /help
+	Access       BotCommandAccess
+	PrivateOnly  bool // command is not shown in the menu of group chats
+}
+
+// Description returns description of command in language, falls back to [LanguageDefault].
+func (i BotCommandInfo) Description(lang Language) string {
+	if d, ok := i.Descriptions[lang]; ok {
+		return d
+	}
+
+	return i.Descriptions[LanguageDefault]
+}
+
+// BotCommands - registry of all bot commands in order they're shown to users.
+// Command menu, /help text and dispatch of commands are built from it.
+var BotCommands = []BotCommandInfo{
+	{
+		Command:      BotCommandHelp,
+		Emoji:        EmojiPersonTippingHand,
+		Descriptions: map[Language]string{LanguageDefault: "справка", LanguageEnglish: "help"},
+	},
+	{
+		Command:      BotCommandStart,
+		Emoji:        EmojiPlayButton,
+		Descriptions: map[Language]string{LanguageDefault: "начать работу с ботом", LanguageEnglish: "start working with the bot"},
+		PrivateOnly:  true,
+	},
+	{
+		Command:      BotCommandCreateReminder,
+		Emoji:        EmojiMemo,
+		Descriptions: map[Language]string{LanguageDefault: "создать напоминание", LanguageEnglish: "create a reminder"},
+	},
+	{
+		Command:      BotCommandEnableReminders,
+		Emoji:        EmojiBell,
+		Descriptions: map[Language]string{LanguageDefault: "включить напоминания", LanguageEnglish: "enable reminders"},
+	},
+	{
+		Command:      BotCommandDisableReminders,
+		Emoji:        EmojiBellWithSlash,
+		Descriptions: map[Language]string{LanguageDefault: "выключить напоминания", LanguageEnglish: "disable reminders"},
+	},
+	{
+		Command:      BotCommandMyReminders,
+		Emoji:        EmojiSpiralNotepad,
+		Descriptions: map[Language]string{LanguageDefault: "мои напоминания", LanguageEnglish: "my reminders"},
+	},
+	{
+		Command:      BotCommandSettings,
+		Emoji:        EmojiGear,
+		Descriptions: map[Language]string{LanguageDefault: "настройки кнопок", LanguageEnglish: "button settings"},
+	},
+	{
+		Command:      BotCommandAPIToken,
+		Emoji:        EmojiKey,
+		Descriptions: map[Language]string{LanguageDefault: "токен для API", LanguageEnglish: "API token"},
+		PrivateOnly:  true,
+	},
+	{
+		Command:      BotCommandHookToken,
+		Emoji:        EmojiHook,
+		Descriptions: map[Language]string{LanguageDefault: "токен входящего вебхука", LanguageEnglish: "incoming webhook token"},
+		PrivateOnly:  true,
+	},
+	{
+		Command:      BotCommandWeb,
+		Emoji:        EmojiGlobe,
+		Descriptions: map[Language]string{LanguageDefault: "веб-интерфейс", LanguageEnglish: "web dashboard"},
+		PrivateOnly:  true,
+	},
+	{
+		Command:      BotCommandWebhooks,
+		Emoji:        EmojiLink,
+		Descriptions: map[Language]string{LanguageDefault: "вебхуки событий", LanguageEnglish: "event webhooks"},
+		PrivateOnly:  true,
+	},
+	{
+		Command:      BotCommandInvite,
+		Emoji:        EmojiTicket,
+		Descriptions: map[Language]string{LanguageDefault: "создать приглашение", LanguageEnglish: "create an invite link"},
+		Access:       BotCommandAccessOwner,
+		PrivateOnly:  true,
+	},
+	{
+		Command:      BotCommandAdminStats,
+		Emoji:        EmojiBarChart,
+		Descriptions: map[Language]string{LanguageDefault: "статистика бота", LanguageEnglish: "bot statistics"},
+		Access:       BotCommandAccessAdmin,
+		PrivateOnly:  true,
+	},
+	{
+		Command:      BotCommandAdminUsers,
+		Emoji:        EmojiProhibited,
+		Descriptions: map[Language]string{LanguageDefault: "пользователи бота", LanguageEnglish: "bot users"},
+		Access:       BotCommandAccessAdmin,
+		PrivateOnly:  true,
+	},
+	{
+		Command:      BotCommandAdminReminder,
+		Emoji:        EmojiScroll,
+		Descriptions: map[Language]string{LanguageDefault: "история напоминания", LanguageEnglish: "reminder history"},
+		Access:       BotCommandAccessAdmin,
+		PrivateOnly:  true,
+	},
+	{
+		Command:      BotCommandBroadcast,
+		Emoji:        EmojiLoudspeaker,
+		Descriptions: map[Language]string{LanguageDefault: "объявление всем пользователям", LanguageEnglish: "announcement to all users"},
+		Access:       BotCommandAccessAdmin,
+		PrivateOnly:  true,
+	},
+	{
+		Command:      BotCommandMaintenance,
+		Emoji:        EmojiHammerAndWrench,
+		Descriptions: map[Language]string{LanguageDefault: "режим обслуживания", LanguageEnglish: "maintenance mode"},
+		Access:       BotCommandAccessAdmin,
+		PrivateOnly:  true,
+	},
+}
+
+// PrivateOnlyText - reply to command for private chats only sent to group chat.
+var PrivateOnlyText = fmt.Sprintf("Эта команда работает только в личном чате с ботом %s", EmojiLocked)
+
+// IsPrivateOnly reports whether command is registered as command for private chats only.
+// Such commands show secrets or change settings of user, so they aren't handled in group chats.
+func (c BotCommand) IsPrivateOnly() bool {
+	for _, info := range BotCommands {
+		if info.Command == c {
+			return info.PrivateOnly
+		}
+	}
+
+	return false
+}
+
+// BotCommandsFor returns registered commands available with access, e.g. admin has user and admin commands.
+// Commands for private chats only are skipped if private is false.
+func BotCommandsFor(access BotCommandAccess, private bool) []BotCommandInfo {
+	var commands []BotCommandInfo
+	for _, c := range BotCommands {
+		if c.Access > access || (c.PrivateOnly && !private) {
+			continue
+		}
+		commands = append(commands, c)
+	}
+
+	return commands
+}
@@ -21,4 +21,66 @@ func TestBotCommand_String(t *testing.T) {
 	a.Equal("/my_reminders", BotCommandMyReminders.String())
 	a.Equal("/enable_reminders", BotCommandEnableReminders.String())
 	a.Equal("/disable_reminders", BotCommandDisableReminders.String())
+	a.Equal("/invite", BotCommandInvite.String())
+	a.Equal("/api_token", BotCommandAPIToken.String())
+	a.Equal("/hook_token", BotCommandHookToken.String())
+	a.Equal("/web", BotCommandWeb.String())
+	a.Equal("/webhooks", BotCommandWebhooks.String())
+}
+
+func TestBotCommand_IsPrivateOnly(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	a.True(BotCommandAPIToken.IsPrivateOnly())
+	a.True(BotCommandWeb.IsPrivateOnly())
+	a.True(BotCommandInvite.IsPrivateOnly())
+	a.False(BotCommandMyReminders.IsPrivateOnly())
+	a.False(BotCommand("/foo").IsPrivateOnly())
+}
+
+func TestBotCommandsFor(t *testing.T) {
+	t.Parallel()
+
+	commands := func(infos []BotCommandInfo) []BotCommand {
+		var res []BotCommand
+		for _, info := range infos {
+			res = append(res, info.Command)
+		}
+		return res
+	}
+
+	a := assert.New(t)
+	a.Equal([]BotCommand{
+		BotCommandHelp, BotCommandStart, BotCommandCreateReminder, BotCommandEnableReminders,
+		BotCommandDisableReminders, BotCommandMyReminders, BotCommandSettings, BotCommandAPIToken, BotCommandHookToken,
+		BotCommandWeb, BotCommandWebhooks,
+	}, commands(BotCommandsFor(BotCommandAccessUser, true)))
+	a.Equal([]BotCommand{
+		BotCommandHelp, BotCommandCreateReminder, BotCommandEnableReminders,
+		BotCommandDisableReminders, BotCommandMyReminders, BotCommandSettings,
+	}, commands(BotCommandsFor(BotCommandAccessOwner, false)))
+	a.Equal([]BotCommand{
+		BotCommandHelp, BotCommandStart, BotCommandCreateReminder, BotCommandEnableReminders,
+		BotCommandDisableReminders, BotCommandMyReminders, BotCommandSettings, BotCommandAPIToken, BotCommandHookToken,
+		BotCommandWeb, BotCommandWebhooks, BotCommandAdminStats, BotCommandAdminUsers, BotCommandAdminReminder, BotCommandBroadcast, BotCommandMaintenance,
+	}, commands(BotCommandsFor(BotCommandAccessAdmin, true)))
+	a.Len(BotCommandsFor(BotCommandAccessOwner, true), len(BotCommands))
+}
+
+func TestBotCommands(t *testing.T) {
+	t.Parallel()
+
+	seen := make(map[BotCommand]bool)
+	for _, c := range BotCommands {
+		assert.False(t, seen[c.Command], "duplicated command %s", c.Command)
+		seen[c.Command] = true
+
+		assert.NotEmpty(t, c.Emoji, c.Command)
+		for _, lang := range Languages {
+			assert.NotEmpty(t, c.Descriptions[lang], "command %s has no description in language %q", c.Command, lang)
+		}
+	}
+
+	assert.Equal(t, "справка", BotCommands[0].Description("de"))
+	assert.Equal(t, "help", BotCommands[0].Description(LanguageEnglish))
 }
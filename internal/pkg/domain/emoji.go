@@ -35,6 +35,44 @@ const (
 	EmojiDisappointedFace = "\U0001f61e"
 	// EmojiThinkingFace - thinking face
 	EmojiThinkingFace = "\U0001f914"
+	// EmojiLocked - locked
+	EmojiLocked = "\U0001f512"
+	// EmojiTicket - admission tickets
+	EmojiTicket = "\U0001f39f\ufe0f"
+	// EmojiBarChart - bar chart
+	EmojiBarChart = "\U0001f4ca"
+	// EmojiProhibited - prohibited
+	EmojiProhibited = "\U0001f6ab"
+	// EmojiLoudspeaker - loudspeaker
+	EmojiLoudspeaker = "\U0001f4e2"
+	// EmojiHammerAndWrench - hammer and wrench
+	EmojiHammerAndWrench = "\U0001f6e0\ufe0f"
+	// EmojiDownwardsButton - downwards button
+	EmojiDownwardsButton = "\U0001f53d"
+	// EmojiRedTrianglePointedUp - red triangle pointed up
+	EmojiRedTrianglePointedUp = "\U0001f53a"
+	// EmojiPoliceCarLight - police car light
+	EmojiPoliceCarLight = "\U0001f6a8"
+	// EmojiClipboard - clipboard
+	EmojiClipboard = "\U0001f4cb"
+	// EmojiWhiteLargeSquare - white large square
+	EmojiWhiteLargeSquare = "\u2b1c"
+	// EmojiCalendar - calendar
+	EmojiCalendar = "\U0001f4c5"
+	// EmojiPlus - plus
+	EmojiPlus = "\u2795"
+	// EmojiGear - gear
+	EmojiGear = "\u2699\ufe0f"
+	// EmojiScroll - scroll
+	EmojiScroll = "\U0001f4dc"
+	// EmojiKey - key
+	EmojiKey = "\U0001f511"
+	// EmojiGlobe - globe with meridians
+	EmojiGlobe = "\U0001f310"
+	// EmojiLink - link
+	EmojiLink = "\U0001f517"
+	// EmojiHook - hook
+	EmojiHook = "\U0001fa9d"
 )
 
 // NoBreakSpace - no-break space
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// inviteCodeLogPrefixLen - number of invite code characters which are safe to log.
+const inviteCodeLogPrefixLen = 4
+
+// InviteCode is a one-time code which allows a user to start working with the bot in invite access mode.
+type InviteCode struct {
+	Code      string    `db:"code"`
+	CreatedBy int64     `db:"created_by"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// String implements [fmt.Stringer]. Code is masked, see [MaskInviteCode].
+func (c InviteCode) String() string {
+	return fmt.Sprintf("[Code: %s, CreatedBy: %d]", MaskInviteCode(c.Code), c.CreatedBy)
+}
+
+// InviteLink returns Telegram deep link which sends "/start <code>" to the bot with name botName.
+func (c InviteCode) InviteLink(botName string) string {
+	return fmt.Sprintf("https://t.me/%s?start=%s", botName, c.Code)
+}
+
+// MaskInviteCode returns invite code prefix which is safe to write to logs.
+func MaskInviteCode(code string) string {
+	if len(code) <= inviteCodeLogPrefixLen {
+		return "***"
+	}
+
+	return code[:inviteCodeLogPrefixLen] + "***"
+}
+
+type inviteCodeCtxKey struct{}
+
+// ContextWithInviteCode returns copy of ctx with invite code, the code is used when user is registered with the context.
+func ContextWithInviteCode(ctx context.Context, code string) context.Context {
+	return context.WithValue(ctx, inviteCodeCtxKey{}, code)
+}
+
+// InviteCodeFromContext returns invite code set by [ContextWithInviteCode].
+func InviteCodeFromContext(ctx context.Context) (string, bool) {
+	code, ok := ctx.Value(inviteCodeCtxKey{}).(string)
+	return code, ok
+}
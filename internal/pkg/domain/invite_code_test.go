@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInviteCode_InviteLink(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "https://t.me/reminder_bot?start=abc", InviteCode{Code: "abc"}.InviteLink("reminder_bot"))
+}
+
+func TestMaskInviteCode(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "0123***", MaskInviteCode("0123456789abcdef"))
+	assert.Equal(t, "***", MaskInviteCode("abc"))
+	assert.Equal(t, "[Code: 0123***, CreatedBy: 1]", InviteCode{Code: "0123456789abcdef", CreatedBy: 1}.String())
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LeadTimes - offsets before reminder time at which advance notices are sent.
+// Advance notice doesn't wait for "done" from user and doesn't consume reminder attempts.
+type LeadTimes []time.Duration
+
+// Contains returns true if lead times contain offset d.
+func (l LeadTimes) Contains(d time.Duration) bool {
+	return slices.Contains(l, d)
+}
+
+// Toggle adds offset d to lead times if it's absent and removes it otherwise.
+// Returns new lead times sorted from the longest to the shortest offset.
+func (l LeadTimes) Toggle(d time.Duration) LeadTimes {
+	res := slices.DeleteFunc(slices.Clone(l), func(el time.Duration) bool { return el == d })
+	if len(res) == len(l) {
+		res = append(res, d)
+	}
+
+	slices.SortFunc(res, func(a, b time.Duration) int { return int(b - a) })
+
+	if len(res) == 0 {
+		return nil
+	}
+
+	return res
+}
+
+// NextNoticeAt returns the earliest time of advance notice for reminder which fires at remindAt, which is after time after.
+// Returns false if there are no more advance notices.
+func (l LeadTimes) NextNoticeAt(remindAt, after time.Time) (time.Time, bool) {
+	var (
+		next  time.Time
+		found bool
+	)
+
+	for _, d := range l {
+		noticeAt := remindAt.Add(-d)
+		if !noticeAt.After(after) {
+			continue
+		}
+
+		if !found || noticeAt.Before(next) {
+			next = noticeAt
+			found = true
+		}
+	}
+
+	return next, found
+}
+
+// Format returns lead times in Russian, e.g. "за 1 дн., за 30 мин.".
+func (l LeadTimes) Format() string {
+	labels := make([]string, 0, len(l))
+	for _, d := range l {
+		labels = append(labels, "за "+FormatDuration(d))
+	}
+
+	return strings.Join(labels, ", ")
+}
+
+// Scan implements [sql.Scanner]. Lead times are stored as comma separated durations.
+func (l *LeadTimes) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case nil:
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("unsupported lead times type %T", value)
+	}
+
+	*l = nil
+	if s == "" {
+		return nil
+	}
+
+	for _, field := range strings.Split(s, ",") {
+		d, err := time.ParseDuration(field)
+		if err != nil {
+			return fmt.Errorf("failed to parse lead time %q: %w", field, err)
+		}
+		*l = append(*l, d)
+	}
+
+	return nil
+}
+
+// Value implements [driver.Valuer].
+func (l LeadTimes) Value() (driver.Value, error) {
+	fields := make([]string, 0, len(l))
+	for _, d := range l {
+		fields = append(fields, d.String())
+	}
+
+	return strings.Join(fields, ","), nil
+}
+
+// FormatDuration formats duration in Russian with minute precision, e.g. "1 дн. 2 ч. 30 мин.".
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Minute {
+		return "1 мин."
+	}
+
+	var (
+		days    = d / (24 * time.Hour)
+		hours   = d % (24 * time.Hour) / time.Hour
+		minutes = d % time.Hour / time.Minute
+		parts   []string
+	)
+
+	if days > 0 {
+		parts = append(parts, strconv.FormatInt(int64(days), 10)+" дн.")
+	}
+	if hours > 0 {
+		parts = append(parts, strconv.FormatInt(int64(hours), 10)+" ч.")
+	}
+	if minutes > 0 {
+		parts = append(parts, strconv.FormatInt(int64(minutes), 10)+" мин.")
+	}
+
+	return strings.Join(parts, " ")
+}
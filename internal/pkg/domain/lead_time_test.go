@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeadTimes_Toggle(t *testing.T) {
+	t.Parallel()
+
+	var l LeadTimes
+	l = l.Toggle(30 * time.Minute)
+	assert.Equal(t, LeadTimes{30 * time.Minute}, l)
+
+	l = l.Toggle(24 * time.Hour)
+	assert.Equal(t, LeadTimes{24 * time.Hour, 30 * time.Minute}, l)
+	assert.True(t, l.Contains(24*time.Hour))
+
+	l = l.Toggle(30 * time.Minute)
+	assert.Equal(t, LeadTimes{24 * time.Hour}, l)
+	assert.False(t, l.Contains(30*time.Minute))
+
+	assert.Nil(t, l.Toggle(24*time.Hour))
+}
+
+func TestLeadTimes_NextNoticeAt(t *testing.T) {
+	t.Parallel()
+
+	var (
+		remindAt = time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+		l        = LeadTimes{24 * time.Hour, time.Hour, 30 * time.Minute}
+	)
+
+	next, ok := l.NextNoticeAt(remindAt, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC), next)
+
+	// notice which time has come is not returned
+	next, ok = l.NextNoticeAt(remindAt, time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 1, 2, 14, 0, 0, 0, time.UTC), next)
+
+	_, ok = l.NextNoticeAt(remindAt, time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC))
+	assert.False(t, ok)
+
+	_, ok = LeadTimes(nil).NextNoticeAt(remindAt, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+}
+
+func TestLeadTimes_Format(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "за 1 дн., за 30 мин.", LeadTimes{24 * time.Hour, 30 * time.Minute}.Format())
+	assert.Empty(t, LeadTimes(nil).Format())
+}
+
+func TestLeadTimes_ScanValue(t *testing.T) {
+	t.Parallel()
+
+	value, err := LeadTimes{24 * time.Hour, 30 * time.Minute}.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "24h0m0s,30m0s", value)
+
+	var l LeadTimes
+	require.NoError(t, l.Scan("24h0m0s,30m0s"))
+	assert.Equal(t, LeadTimes{24 * time.Hour, 30 * time.Minute}, l)
+
+	require.NoError(t, l.Scan([]byte("")))
+	assert.Nil(t, l)
+
+	require.NoError(t, l.Scan(nil))
+	assert.Nil(t, l)
+
+	require.EqualError(t, l.Scan("foo"), `failed to parse lead time "foo": time: invalid duration "foo"`)
+	require.EqualError(t, l.Scan(1), "unsupported lead times type int")
+}
+
+func TestFormatDuration(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		d      time.Duration
+		expRes string
+	}{
+		{d: 10 * time.Second, expRes: "1 мин."},
+		{d: 29*time.Minute + 50*time.Second, expRes: "30 мин."},
+		{d: time.Hour, expRes: "1 ч."},
+		{d: 90 * time.Minute, expRes: "1 ч. 30 мин."},
+		{d: 24 * time.Hour, expRes: "1 дн."},
+		{d: 26*time.Hour + 5*time.Minute, expRes: "1 дн. 2 ч. 5 мин."},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expRes, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expRes, FormatDuration(tc.d))
+		})
+	}
+}
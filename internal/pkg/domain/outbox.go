@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// OutboxStatus - status of outgoing message.
+type OutboxStatus string
+
+const (
+	// OutboxStatusPending - message is waiting to be delivered.
+	OutboxStatusPending OutboxStatus = "pending"
+	// OutboxStatusDead - message wasn't delivered within max attempts and won't be delivered anymore.
+	OutboxStatusDead OutboxStatus = "dead"
+)
+
+// OutboxMessage - outgoing message stored in the database until it's delivered by its channel.
+// Payload is the message rendered by sender for Telegram or JSON encoded [Notification] for other channels,
+// delivered messages are removed from the outbox. Payload of secret message, e.g. with API token, is erased
+// when message is dead, so secrets aren't kept in the database.
+type OutboxMessage struct {
+	ID            int64        `db:"id"`
+	ChatID        int64        `db:"chat_id"`
+	Channel       Channel      `db:"channel"`
+	Payload       string       `db:"payload"`
+	Status        OutboxStatus `db:"status"`
+	Attempts      int          `db:"attempts"`
+	NextAttemptAt time.Time    `db:"next_attempt_at"`
+	LastError     string       `db:"last_error"`
+	CorrelationID string       `db:"correlation_id"`
+	Secret        bool         `db:"secret"`
+	CreatedAt     time.Time    `db:"created_at"`
+	ModifiedAt    time.Time    `db:"modified_at"`
+}
+
+func (m OutboxMessage) String() string {
+	return fmt.Sprintf("[ID: %d, ChatID: %d, Channel: %s, Status: %s, Attempts: %d, NextAttemptAt: %s]", m.ID, m.ChatID, m.Channel, m.Status, m.Attempts, m.NextAttemptAt)
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReminderPriority - priority of a reminder, changes delivery behavior.
+type ReminderPriority string
+
+const (
+	// ReminderPriorityLow - reminder is sent silently and only once.
+	ReminderPriorityLow ReminderPriority = "low"
+	// ReminderPriorityNormal - default priority.
+	ReminderPriorityNormal ReminderPriority = "normal"
+	// ReminderPriorityHigh - reminder is re-sent more often than normal one.
+	ReminderPriorityHigh ReminderPriority = "high"
+	// ReminderPriorityCritical - reminder is re-sent aggressively and notification message is pinned.
+	ReminderPriorityCritical ReminderPriority = "critical"
+)
+
+// ParseReminderPriority parses reminder priority. Empty string is parsed as [ReminderPriorityNormal].
+func ParseReminderPriority(s string) (ReminderPriority, error) {
+	switch p := ReminderPriority(s); p {
+	case "":
+		return ReminderPriorityNormal, nil
+	case ReminderPriorityLow, ReminderPriorityNormal, ReminderPriorityHigh, ReminderPriorityCritical:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown reminder priority %q", s)
+	}
+}
+
+// SplitReminderPriority cuts priority prefix from reminder text: "!!" is for critical priority, "!" is for high priority.
+// Returns [ReminderPriorityNormal] if text has no priority prefix.
+func SplitReminderPriority(text string) (ReminderPriority, string) {
+	switch {
+	case strings.HasPrefix(text, "!!"):
+		return ReminderPriorityCritical, strings.TrimSpace(strings.TrimLeft(text, "!"))
+	case strings.HasPrefix(text, "!"):
+		return ReminderPriorityHigh, strings.TrimSpace(strings.TrimPrefix(text, "!"))
+	default:
+		return ReminderPriorityNormal, text
+	}
+}
+
+// Attempts returns number of attempts to deliver a reminder with priority.
+func (p ReminderPriority) Attempts() byte {
+	switch p {
+	case ReminderPriorityLow:
+		return 1
+	case ReminderPriorityCritical:
+		return 2 * DefaultAttemptsLeft
+	default:
+		return DefaultAttemptsLeft
+	}
+}
+
+// RenotifyInterval returns interval to wait for "done" from user before sending reminder again.
+// Returns 0 if reminder with priority must not be re-sent.
+func (p ReminderPriority) RenotifyInterval() time.Duration {
+	switch p {
+	case ReminderPriorityLow:
+		return 0
+	case ReminderPriorityHigh:
+		return 10 * time.Minute
+	case ReminderPriorityCritical:
+		return 5 * time.Minute
+	default:
+		return 15 * time.Minute
+	}
+}
+
+// Emoji returns emoji which marks priority. Normal priority has no emoji.
+func (p ReminderPriority) Emoji() string {
+	switch p {
+	case ReminderPriorityLow:
+		return EmojiDownwardsButton
+	case ReminderPriorityHigh:
+		return EmojiRedTrianglePointedUp
+	case ReminderPriorityCritical:
+		return EmojiPoliceCarLight
+	default:
+		return ""
+	}
+}
+
+// Label returns priority name in Russian with emoji.
+func (p ReminderPriority) Label() string {
+	switch p {
+	case ReminderPriorityLow:
+		return p.Emoji() + " Низкий"
+	case ReminderPriorityHigh:
+		return p.Emoji() + " Высокий"
+	case ReminderPriorityCritical:
+		return p.Emoji() + " Критический"
+	default:
+		return "Обычный"
+	}
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReminderPriority(t *testing.T) {
+	t.Parallel()
+
+	for s, exp := range map[string]ReminderPriority{
+		"":         ReminderPriorityNormal,
+		"low":      ReminderPriorityLow,
+		"normal":   ReminderPriorityNormal,
+		"high":     ReminderPriorityHigh,
+		"critical": ReminderPriorityCritical,
+	} {
+		act, err := ParseReminderPriority(s)
+		require.NoError(t, err)
+		assert.Equal(t, exp, act)
+	}
+
+	_, err := ParseReminderPriority("urgent")
+	require.EqualError(t, err, `unknown reminder priority "urgent"`)
+}
+
+func TestSplitReminderPriority(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		text        string
+		expPriority ReminderPriority
+		expText     string
+	}{
+		{text: "buy milk", expPriority: ReminderPriorityNormal, expText: "buy milk"},
+		{text: "!buy milk", expPriority: ReminderPriorityHigh, expText: "buy milk"},
+		{text: "! buy milk", expPriority: ReminderPriorityHigh, expText: "buy milk"},
+		{text: "!! buy milk", expPriority: ReminderPriorityCritical, expText: "buy milk"},
+		{text: "!!!buy milk", expPriority: ReminderPriorityCritical, expText: "buy milk"},
+		{text: "buy milk!", expPriority: ReminderPriorityNormal, expText: "buy milk!"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.text, func(t *testing.T) {
+			t.Parallel()
+
+			priority, text := SplitReminderPriority(tc.text)
+			assert.Equal(t, tc.expPriority, priority)
+			assert.Equal(t, tc.expText, text)
+		})
+	}
+}
+
+func TestReminderPriority_Attempts(t *testing.T) {
+	t.Parallel()
+	assert.EqualValues(t, 1, ReminderPriorityLow.Attempts())
+	assert.EqualValues(t, 10, ReminderPriorityNormal.Attempts())
+	assert.EqualValues(t, 10, ReminderPriorityHigh.Attempts())
+	assert.EqualValues(t, 20, ReminderPriorityCritical.Attempts())
+}
+
+func TestReminderPriority_RenotifyInterval(t *testing.T) {
+	t.Parallel()
+	assert.Zero(t, ReminderPriorityLow.RenotifyInterval())
+	assert.Equal(t, 15*time.Minute, ReminderPriorityNormal.RenotifyInterval())
+	assert.Equal(t, 15*time.Minute, ReminderPriority("").RenotifyInterval())
+	assert.Equal(t, 10*time.Minute, ReminderPriorityHigh.RenotifyInterval())
+	assert.Equal(t, 5*time.Minute, ReminderPriorityCritical.RenotifyInterval())
+}
+
+func TestReminderPriority_Label(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "🔽 Низкий", ReminderPriorityLow.Label())
+	assert.Equal(t, "Обычный", ReminderPriorityNormal.Label())
+	assert.Equal(t, "🔺 Высокий", ReminderPriorityHigh.Label())
+	assert.Equal(t, "🚨 Критический", ReminderPriorityCritical.Label())
+}
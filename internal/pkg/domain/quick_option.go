@@ -0,0 +1,252 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QuickOption - a reminder time option of a button to create or to snooze reminder in one click.
+// It's one of:
+//   - time of day, e.g. "11:30", the nearest one in the future is used;
+//   - duration from now, e.g. "30m", "3h", "2d" or "1w";
+//   - semantic option, e.g. "tomorrow_morning" or "next_monday".
+type QuickOption string
+
+// Semantic quick options.
+const (
+	QuickOptionTomorrowMorning QuickOption = "tomorrow_morning"
+	QuickOptionTomorrowEvening QuickOption = "tomorrow_evening"
+	QuickOptionNextMonday      QuickOption = "next_monday"
+	QuickOptionWeekend         QuickOption = "weekend"
+)
+
+// semanticQuickOptions - labels and time of day of semantic quick options.
+var semanticQuickOptions = map[QuickOption]struct {
+	label        string
+	hour, minute int
+}{
+	QuickOptionTomorrowMorning: {label: "Завтра утром", hour: 9},
+	QuickOptionTomorrowEvening: {label: "Завтра вечером", hour: 19},
+	QuickOptionNextMonday:      {label: "В понедельник", hour: 9},
+	QuickOptionWeekend:         {label: "В выходные", hour: 10},
+}
+
+// MaxQuickOptions - max number of quick options in a set.
+const MaxQuickOptions = 12
+
+// Default quick options.
+var (
+	// DefaultQuickOptions - default options of buttons to create reminder.
+	DefaultQuickOptions = QuickOptions{"11:30", "14:30", "19:30", "20:30", "30m", "80m", "1d", "730h"}
+	// DefaultSnoozeOptions - default options of buttons to snooze reminder.
+	DefaultSnoozeOptions = QuickOptions{"30m", "80m", "3h", "1d", "1w", "730h"}
+)
+
+const (
+	hoursInDay   = 24
+	hoursInWeek  = 7 * hoursInDay
+	hoursInMonth = 730
+)
+
+// maxQuickDuration - max duration of quick option, longer durations are rejected.
+const maxQuickDuration = 12 * hoursInMonth * time.Hour
+
+// ParseQuickOption parses and validates quick option.
+func ParseQuickOption(s string) (QuickOption, error) {
+	option := QuickOption(strings.ToLower(strings.TrimSpace(s)))
+
+	if _, ok := semanticQuickOptions[option]; ok {
+		return option, nil
+	}
+
+	if _, err := time.Parse(layoutTimeOnly, string(option)); err == nil {
+		return option, nil
+	}
+
+	d, err := parseQuickDuration(string(option))
+	if err != nil || d < time.Minute {
+		return "", fmt.Errorf("unknown quick option %q", s)
+	}
+
+	return option, nil
+}
+
+// parseQuickDuration parses duration, additionally to [time.ParseDuration] supports days ("2d") and weeks ("1w").
+// Durations longer than [maxQuickDuration] are rejected.
+func parseQuickDuration(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": hoursInDay * time.Hour, "w": hoursInWeek * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil {
+				return 0, err
+			}
+			if count > int(maxQuickDuration/unit) {
+				return 0, fmt.Errorf("duration %q is longer than %s", s, maxQuickDuration)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d > maxQuickDuration {
+		return 0, fmt.Errorf("duration %q is longer than %s", s, maxQuickDuration)
+	}
+
+	return d, nil
+}
+
+// Label returns text of button with quick option, it's always derived from the option value.
+func (o QuickOption) Label() string {
+	if semantic, ok := semanticQuickOptions[o]; ok {
+		return semantic.label
+	}
+
+	if _, err := time.Parse(layoutTimeOnly, string(o)); err == nil {
+		return string(o)
+	}
+
+	d, err := parseQuickDuration(string(o))
+	if err != nil {
+		return string(o)
+	}
+
+	hours := int(d / time.Hour)
+	switch {
+	case d%time.Hour != 0 || hours == 0:
+		return FormatDuration(d)
+	case hours%hoursInMonth == 0:
+		return fmt.Sprintf("%d мес.", hours/hoursInMonth)
+	case hours%hoursInWeek == 0:
+		return fmt.Sprintf("%d нед.", hours/hoursInWeek)
+	default:
+		return FormatDuration(d)
+	}
+}
+
+// RemindAt resolves quick option to time in Moscow, truncated to minutes.
+func (o QuickOption) RemindAt(now time.Time) (time.Time, error) {
+	now = MoscowTime(now)
+
+	if semantic, ok := semanticQuickOptions[o]; ok {
+		day := now.AddDate(0, 0, 1)
+		switch o {
+		case QuickOptionNextMonday:
+			day = now.AddDate(0, 0, 7-(int(now.Weekday())+6)%7)
+		case QuickOptionWeekend:
+			day = now.AddDate(0, 0, (int(time.Saturday)-int(now.Weekday())+6)%7+1)
+		}
+
+		return time.Date(day.Year(), day.Month(), day.Day(), semantic.hour, semantic.minute, 0, 0, now.Location()), nil
+	}
+
+	if t, err := time.Parse(layoutTimeOnly, string(o)); err == nil {
+		remindAt := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !remindAt.After(now) {
+			remindAt = remindAt.AddDate(0, 0, 1)
+		}
+
+		return remindAt, nil
+	}
+
+	d, err := parseQuickDuration(string(o))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unknown quick option %q", o)
+	}
+
+	return now.Add(d).Truncate(time.Minute), nil
+}
+
+// QuickOptions - set of quick options.
+type QuickOptions []QuickOption
+
+// ParseQuickOptions parses comma or whitespace separated quick options.
+func ParseQuickOptions(s string) (QuickOptions, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n'
+	})
+
+	if len(fields) == 0 {
+		return nil, errors.New("no quick options")
+	}
+
+	if len(fields) > MaxQuickOptions {
+		return nil, fmt.Errorf("too many quick options: %d, max is %d", len(fields), MaxQuickOptions)
+	}
+
+	options := make(QuickOptions, 0, len(fields))
+	for _, field := range fields {
+		option, err := ParseQuickOption(field)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, option)
+	}
+
+	return options, nil
+}
+
+// Or returns options if they're not empty, otherwise returns defaults.
+func (o QuickOptions) Or(defaults QuickOptions) QuickOptions {
+	if len(o) == 0 {
+		return defaults
+	}
+
+	return o
+}
+
+// Format formats quick options as labels separated by comma.
+func (o QuickOptions) Format() string {
+	labels := make([]string, 0, len(o))
+	for _, option := range o {
+		labels = append(labels, option.Label())
+	}
+
+	return strings.Join(labels, ", ")
+}
+
+// String implements [fmt.Stringer].
+func (o QuickOptions) String() string {
+	fields := make([]string, 0, len(o))
+	for _, option := range o {
+		fields = append(fields, string(option))
+	}
+
+	return strings.Join(fields, ",")
+}
+
+// Scan implements [sql.Scanner]. Quick options are stored as comma separated values.
+func (o *QuickOptions) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case nil:
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("unsupported quick options type %T", value)
+	}
+
+	*o = nil
+	if s == "" {
+		return nil
+	}
+
+	for _, field := range strings.Split(s, ",") {
+		*o = append(*o, QuickOption(field))
+	}
+
+	return nil
+}
+
+// Value implements [driver.Valuer].
+func (o QuickOptions) Value() (driver.Value, error) {
+	return o.String(), nil
+}
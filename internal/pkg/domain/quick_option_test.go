@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuickOption(t *testing.T) {
+	t.Parallel()
+
+	for s, exp := range map[string]QuickOption{
+		"11:30":            "11:30",
+		" 30m ":            "30m",
+		"1H30M":            "1h30m",
+		"2d":               "2d",
+		"1w":               "1w",
+		"52w":              "52w",
+		"365d":             "365d",
+		"Tomorrow_Morning": QuickOptionTomorrowMorning,
+		"tomorrow_evening": QuickOptionTomorrowEvening,
+		"next_monday":      QuickOptionNextMonday,
+		"weekend":          QuickOptionWeekend,
+	} {
+		act, err := ParseQuickOption(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, exp, act)
+	}
+
+	for _, s := range []string{"", "foo", "25:00", "30s", "-1h", "0d", "xd", "53w", "366d", "9000h", "9223372036854775807d", "106751991167w"} {
+		_, err := ParseQuickOption(s)
+		require.EqualError(t, err, `unknown quick option "`+s+`"`)
+	}
+}
+
+// TestQuickOption_LabelMatchesValue checks that label of every quick option describes the time it resolves to.
+func TestQuickOption_LabelMatchesValue(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 3, 9, 0, 0, 0, time.UTC) // Wednesday, 12:00 MSK
+
+	testCases := []struct {
+		option      QuickOption
+		expLabel    string
+		expRemindAt time.Time
+	}{
+		{option: "11:30", expLabel: "11:30", expRemindAt: time.Date(2024, 1, 4, 11, 30, 0, 0, locationMSK)},
+		{option: "19:30", expLabel: "19:30", expRemindAt: time.Date(2024, 1, 3, 19, 30, 0, 0, locationMSK)},
+		{option: "15m", expLabel: "15 мин.", expRemindAt: time.Date(2024, 1, 3, 12, 15, 0, 0, locationMSK)},
+		{option: "30m", expLabel: "30 мин.", expRemindAt: time.Date(2024, 1, 3, 12, 30, 0, 0, locationMSK)},
+		{option: "80m", expLabel: "1 ч. 20 мин.", expRemindAt: time.Date(2024, 1, 3, 13, 20, 0, 0, locationMSK)},
+		{option: "1h", expLabel: "1 ч.", expRemindAt: time.Date(2024, 1, 3, 13, 0, 0, 0, locationMSK)},
+		{option: "3h", expLabel: "3 ч.", expRemindAt: time.Date(2024, 1, 3, 15, 0, 0, 0, locationMSK)},
+		{option: "1d", expLabel: "1 дн.", expRemindAt: time.Date(2024, 1, 4, 12, 0, 0, 0, locationMSK)},
+		{option: "24h", expLabel: "1 дн.", expRemindAt: time.Date(2024, 1, 4, 12, 0, 0, 0, locationMSK)},
+		{option: "1w", expLabel: "1 нед.", expRemindAt: time.Date(2024, 1, 10, 12, 0, 0, 0, locationMSK)},
+		{option: "730h", expLabel: "1 мес.", expRemindAt: time.Date(2024, 2, 2, 22, 0, 0, 0, locationMSK)},
+		{option: QuickOptionTomorrowMorning, expLabel: "Завтра утром", expRemindAt: time.Date(2024, 1, 4, 9, 0, 0, 0, locationMSK)},
+		{option: QuickOptionTomorrowEvening, expLabel: "Завтра вечером", expRemindAt: time.Date(2024, 1, 4, 19, 0, 0, 0, locationMSK)},
+		{option: QuickOptionNextMonday, expLabel: "В понедельник", expRemindAt: time.Date(2024, 1, 8, 9, 0, 0, 0, locationMSK)},
+		{option: QuickOptionWeekend, expLabel: "В выходные", expRemindAt: time.Date(2024, 1, 6, 10, 0, 0, 0, locationMSK)},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expLabel, tc.option.Label(), tc.option)
+
+		remindAt, err := tc.option.RemindAt(now)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expRemindAt, remindAt, tc.option)
+	}
+
+	// default options are valid and have labels derived from their values
+	assert.Equal(t, "11:30, 14:30, 19:30, 20:30, 30 мин., 1 ч. 20 мин., 1 дн., 1 мес.", DefaultQuickOptions.Format())
+	assert.Equal(t, "30 мин., 1 ч. 20 мин., 3 ч., 1 дн., 1 нед., 1 мес.", DefaultSnoozeOptions.Format())
+	for _, option := range append(DefaultQuickOptions, DefaultSnoozeOptions...) {
+		_, err := ParseQuickOption(string(option))
+		require.NoError(t, err, option)
+	}
+}
+
+func TestQuickOption_RemindAt_Weekdays(t *testing.T) {
+	t.Parallel()
+
+	// from Monday 1st to Sunday 7th of January 2024
+	for day := 1; day <= 7; day++ {
+		now := time.Date(2024, 1, day, 9, 0, 0, 0, time.UTC)
+
+		monday, err := QuickOptionNextMonday.RemindAt(now)
+		require.NoError(t, err)
+		assert.Equal(t, time.Monday, monday.Weekday())
+		assert.True(t, monday.After(now))
+		assert.LessOrEqual(t, monday.Sub(now), 7*24*time.Hour)
+
+		weekend, err := QuickOptionWeekend.RemindAt(now)
+		require.NoError(t, err)
+		assert.Equal(t, time.Saturday, weekend.Weekday())
+		assert.True(t, weekend.After(now))
+		assert.LessOrEqual(t, weekend.Sub(now), 7*24*time.Hour)
+	}
+}
+
+func TestParseQuickOptions(t *testing.T) {
+	t.Parallel()
+
+	options, err := ParseQuickOptions("11:30, 30m\ntomorrow_morning 1w")
+	require.NoError(t, err)
+	assert.Equal(t, QuickOptions{"11:30", "30m", QuickOptionTomorrowMorning, "1w"}, options)
+	assert.Equal(t, "11:30, 30 мин., Завтра утром, 1 нед.", options.Format())
+
+	_, err = ParseQuickOptions(" , ")
+	require.EqualError(t, err, "no quick options")
+
+	_, err = ParseQuickOptions("1m 2m 3m 4m 5m 6m 7m 8m 9m 10m 11m 12m 13m")
+	require.EqualError(t, err, "too many quick options: 13, max is 12")
+
+	_, err = ParseQuickOptions("30m foo")
+	require.EqualError(t, err, `unknown quick option "foo"`)
+}
+
+func TestQuickOptions_ScanValue(t *testing.T) {
+	t.Parallel()
+
+	options := QuickOptions{"11:30", "30m", QuickOptionWeekend}
+
+	value, err := options.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "11:30,30m,weekend", value)
+
+	var scanned QuickOptions
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, options, scanned)
+
+	require.NoError(t, scanned.Scan(nil))
+	assert.Nil(t, scanned)
+
+	require.Error(t, scanned.Scan(1))
+}
+
+func TestUserSettings(t *testing.T) {
+	t.Parallel()
+
+	settings := UserSettings{UserID: 1}
+	assert.Equal(t, DefaultQuickOptions, settings.ReminderQuickOptions())
+	assert.Equal(t, DefaultSnoozeOptions, settings.ReminderSnoozeOptions())
+
+	settings.SnoozeOptions = QuickOptions{"15m"}
+	assert.Equal(t, QuickOptions{"15m"}, settings.ReminderSnoozeOptions())
+	assert.Equal(t, "[UserID: 1, QuickOptions: , SnoozeOptions: 15m, Channels: ]", settings.String())
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrRemindAtInPast - remindAt is not in the future and can't be corrected.
+var ErrRemindAtInPast = errors.New("remind at is in the past")
+
+// CorrectRemindAt validates that remindAt is in the future.
+// Time of today which has already passed is moved to tomorrow, in that case corrected is true.
+// Returns [ErrRemindAtInPast] for other times in the past.
+func CorrectRemindAt(remindAt, now time.Time) (corrected time.Time, isCorrected bool, err error) {
+	if remindAt.After(now) {
+		return remindAt, false, nil
+	}
+
+	nYear, nMonth, nDay := MoscowTime(now).Date()
+	rYear, rMonth, rDay := MoscowTime(remindAt).Date()
+	if nYear == rYear && nMonth == rMonth && nDay == rDay {
+		return remindAt.Add(24 * time.Hour), true, nil
+	}
+
+	return time.Time{}, false, ErrRemindAtInPast
+}
+
+// FormatRemindAt formats remindAt in relative and absolute form, e.g. "через 3 дня, пт 20 окт. 10:00".
+// Year is shown only if it differs from the current one.
+func FormatRemindAt(remindAt, now time.Time) string {
+	var (
+		remindAtMSK = MoscowTime(remindAt)
+		nowMSK      = MoscowTime(now)
+	)
+
+	absolute := fmt.Sprintf("%s %d %s", getRussianWeekday(remindAtMSK.Weekday()), remindAtMSK.Day(), getRussianMonth(remindAtMSK.Month()))
+	if remindAtMSK.Year() != nowMSK.Year() {
+		absolute += " " + strconv.Itoa(remindAtMSK.Year())
+	}
+
+	return fmt.Sprintf("%s, %s %s", formatRelative(remindAtMSK, nowMSK), absolute, remindAtMSK.Format(layoutTimeOnly))
+}
+
+// formatRelative formats time left to remindAt: minutes and hours if it's less than a day, calendar days otherwise.
+func formatRelative(remindAt, now time.Time) string {
+	d := remindAt.Sub(now).Round(time.Minute)
+
+	if d < time.Hour {
+		minutes := int(d.Minutes())
+		if minutes < 1 {
+			minutes = 1
+		}
+
+		return fmt.Sprintf("через %d %s", minutes, pluralRu(minutes, "минуту", "минуты", "минут"))
+	}
+
+	if d < 24*time.Hour {
+		hours, minutes := int(d.Hours()), int(d.Minutes())%60
+
+		text := fmt.Sprintf("через %d %s", hours, pluralRu(hours, "час", "часа", "часов"))
+		if minutes > 0 {
+			text += fmt.Sprintf(" %d %s", minutes, pluralRu(minutes, "минуту", "минуты", "минут"))
+		}
+
+		return text
+	}
+
+	nYear, nMonth, nDay := now.Date()
+	rYear, rMonth, rDay := remindAt.Date()
+	days := int(time.Date(rYear, rMonth, rDay, 0, 0, 0, 0, time.UTC).Sub(time.Date(nYear, nMonth, nDay, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+
+	if days == 1 {
+		return "завтра"
+	}
+
+	return fmt.Sprintf("через %d %s", days, pluralRu(days, "день", "дня", "дней"))
+}
+
+// pluralRu returns russian plural form of noun for number n.
+func pluralRu(n int, one, few, many string) string {
+	n %= 100
+	if n >= 11 && n <= 14 {
+		return many
+	}
+
+	switch n % 10 {
+	case 1:
+		return one
+	case 2, 3, 4:
+		return few
+	default:
+		return many
+	}
+}
+
+func getRussianWeekday(d time.Weekday) string {
+	switch d {
+	case time.Monday:
+		return "пн"
+	case time.Tuesday:
+		return "вт"
+	case time.Wednesday:
+		return "ср"
+	case time.Thursday:
+		return "чт"
+	case time.Friday:
+		return "пт"
+	case time.Saturday:
+		return "сб"
+	case time.Sunday:
+		return "вс"
+	default:
+		return ""
+	}
+}
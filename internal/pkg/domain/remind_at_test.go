@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorrectRemindAt(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC) // 13:00 MSK
+
+	remindAt, corrected, err := CorrectRemindAt(now.Add(time.Minute), now)
+	require.NoError(t, err)
+	assert.False(t, corrected)
+	assert.Equal(t, now.Add(time.Minute), remindAt)
+
+	remindAt, corrected, err = CorrectRemindAt(now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	assert.True(t, corrected)
+	assert.Equal(t, now.Add(23*time.Hour), remindAt)
+
+	_, _, err = CorrectRemindAt(now.Add(-24*time.Hour), now)
+	require.ErrorIs(t, err, ErrRemindAtInPast)
+}
+
+func TestFormatRemindAt(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 10, 17, 7, 0, 0, 0, time.UTC) // Thursday, 10:00 MSK
+
+	testCases := []struct {
+		remindAt time.Time
+		expRes   string
+	}{
+		{remindAt: now.Add(30 * time.Second), expRes: "через 1 минуту, чт 17 окт. 10:00"},
+		{remindAt: now.Add(22 * time.Minute), expRes: "через 22 минуты, чт 17 окт. 10:22"},
+		{remindAt: now.Add(2 * time.Hour), expRes: "через 2 часа, чт 17 окт. 12:00"},
+		{remindAt: now.Add(5*time.Hour + 11*time.Minute), expRes: "через 5 часов 11 минут, чт 17 окт. 15:11"},
+		{remindAt: now.Add(26 * time.Hour), expRes: "завтра, пт 18 окт. 12:00"},
+		{remindAt: now.Add(72 * time.Hour), expRes: "через 3 дня, вс 20 окт. 10:00"},
+		{remindAt: now.Add(21 * 24 * time.Hour), expRes: "через 21 день, чт 7 нояб. 10:00"},
+		{remindAt: time.Date(2025, 1, 10, 7, 0, 0, 0, time.UTC), expRes: "через 85 дней, пт 10 янв. 2025 10:00"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expRes, FormatRemindAt(tc.remindAt, now))
+	}
+}
+
+func Test_pluralRu(t *testing.T) {
+	t.Parallel()
+
+	for n, exp := range map[int]string{1: "день", 2: "дня", 5: "дней", 11: "дней", 12: "дней", 21: "день", 22: "дня", 111: "дней"} {
+		assert.Equal(t, exp, pluralRu(n, "день", "дня", "дней"), n)
+	}
+}
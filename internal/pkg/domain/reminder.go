@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -12,24 +13,40 @@ const DefaultAttemptsLeft = 10
 
 // Reminder - reminder representation.
 type Reminder struct {
-	ID           int64          `db:"id"`
-	ChatID       int64          `db:"chat_id"`
-	UserID       int64          `db:"user_id"`
-	Text         string         `db:"text"`
-	CreatedAt    time.Time      `db:"created_at"`
-	ModifiedAt   time.Time      `db:"modified_at"`
-	RemindAt     time.Time      `db:"remind_at"`
-	Status       ReminderStatus `db:"status"`
-	AttemptsLeft byte           `db:"attempts_left"`
+	ID           int64            `db:"id"`
+	ChatID       int64            `db:"chat_id"`
+	UserID       int64            `db:"user_id"`
+	Text         string           `db:"text"`
+	CreatedAt    time.Time        `db:"created_at"`
+	ModifiedAt   time.Time        `db:"modified_at"`
+	RemindAt     time.Time        `db:"remind_at"`
+	Status       ReminderStatus   `db:"status"`
+	AttemptsLeft byte             `db:"attempts_left"`
+	Priority     ReminderPriority `db:"priority"`
+	LeadTimes    LeadTimes        `db:"lead_times"`
+	// channels to deliver reminder by, user's default channels are used if empty
+	Channels Channels `db:"channels"`
+	// time to send the next advance notice at, nil if there are no more advance notices
+	NextPreNoticeAt *time.Time `db:"next_pre_notice_at"`
+	// key of summary the missed reminder is listed in, see [MissedSummary]
+	SummaryKey *string `db:"summary_key"`
+	// checklist items are stored in a separate table
+	Checklist Checklist `db:"-"`
 }
 
 func (r Reminder) String() string {
 	return fmt.Sprintf("[ID: %d, UserID: %d, ChatID: %d, Status: %s, RemindAt: %s, AttemptsLeft: %d, Data: %s]", r.ID, r.UserID, r.ChatID, r.Status, r.RemindAt, r.AttemptsLeft, r.Text)
 }
 
+// IsOwnedBy reports whether reminder was created by user with userID in chat with chatID.
+func (r Reminder) IsOwnedBy(userID, chatID int64) bool {
+	return r.UserID == userID && r.ChatID == chatID
+}
+
 const layoutTimeOnly = "15:04"
 
 // FormatList - format reminder info to send to user as an entity of reminders list.
+// Priority is shown only if it differs from normal, checklist progress is shown only if reminder has checklist.
 func (r Reminder) FormatList(now time.Time) string {
 	var (
 		remindAtMSK         = MoscowTime(r.RemindAt)
@@ -61,6 +78,19 @@ func (r Reminder) FormatList(now time.Time) string {
 		sb.WriteString(timeOnly)
 	}
 
+	if r.Priority != "" && r.Priority != ReminderPriorityNormal {
+		sb.WriteString("\n")
+		sb.WriteString(r.Priority.Label())
+		sb.WriteString(" приоритет")
+	}
+
+	if len(r.Checklist) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(EmojiClipboard)
+		sb.WriteRune(' ')
+		sb.WriteString(r.Checklist.FormatProgress())
+	}
+
 	sb.WriteString("\n")
 	sb.WriteString(EmojiKeycapHash)
 	sb.WriteRune(' ')
@@ -69,16 +99,69 @@ func (r Reminder) FormatList(now time.Time) string {
 	return sb.String()
 }
 
+// FormatPreNotify - format reminder info to send to user as advance notice.
+func (r Reminder) FormatPreNotify(now time.Time) string {
+	return fmt.Sprintf("%s *Скоро напоминание*\n\n*%s*\n\nЧерез %s, в %s%s%s",
+		EmojiBell,
+		r.Text,
+		FormatDuration(r.RemindAt.Sub(now)),
+		MoscowTime(r.RemindAt).Format(layoutTimeOnly), NoBreakSpace, EmojiAlarmClock,
+	)
+}
+
 // FormatNotify - format reminder info to send to user as notification.
+// Priority is shown only if it differs from normal, checklist progress is shown only if reminder has checklist.
 func (r Reminder) FormatNotify() string {
-	return fmt.Sprintf("%[1]s*НАПОМИНАНИЕ*%[1]s\n\n*%[2]s*\n\nСегодня %[3]s%[4]s%[5]s\n\nЧтобы отложить напоминание используйте кнопки%[6]s%[7]s, расположенные ниже.",
+	var priority string
+	if r.Priority != "" && r.Priority != ReminderPriorityNormal {
+		priority = fmt.Sprintf("%s приоритет\n\n", r.Priority.Label())
+	}
+
+	var checklist string
+	if len(r.Checklist) > 0 {
+		checklist = fmt.Sprintf("%s Выполнено %s\n\n", EmojiClipboard, r.Checklist.FormatProgress())
+	}
+
+	return fmt.Sprintf("%[1]s*НАПОМИНАНИЕ*%[1]s\n\n*%[2]s*\n\n%[9]s%[8]sСегодня %[3]s%[4]s%[5]s\n\nЧтобы отложить напоминание используйте кнопки%[6]s%[7]s, расположенные ниже.",
 		EmojiDoubleExclamationMark,
 		strings.ToUpper(r.Text),
 		MoscowTime(r.RemindAt).Format(layoutTimeOnly), NoBreakSpace, EmojiAlarmClock,
 		NoBreakSpace, EmojiCounterclockwiseArrowsButton,
+		priority,
+		checklist,
 	)
 }
 
+// missedListLimit - max number of reminders listed in summary of missed reminders.
+const missedListLimit = 10
+
+// FormatMissed - format summary of reminders missed while the bot was down or reminders were disabled.
+func FormatMissed(reminders []Reminder) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s *Пропущенные напоминания: %d*\n", EmojiAlarmClock, len(reminders)))
+
+	for i, r := range reminders {
+		if i == missedListLimit {
+			sb.WriteString(fmt.Sprintf("\n\t• и ещё %d", len(reminders)-missedListLimit))
+			break
+		}
+
+		remindAtMSK := MoscowTime(r.RemindAt)
+		sb.WriteString(fmt.Sprintf("\n\t• *%s* — %d %s %s", r.Text, remindAtMSK.Day(), getRussianMonth(remindAtMSK.Month()), remindAtMSK.Format(layoutTimeOnly)))
+	}
+
+	sb.WriteString(fmt.Sprintf("\n\nКогда напомнить о них снова%s", EmojiQuestionMark))
+
+	return sb.String()
+}
+
+// MissedSummary - summary of missed reminders enqueued to user. Key is stored with the reminders and passed
+// in data of the summary buttons, so the buttons reschedule only reminders listed in the summary.
+type MissedSummary struct {
+	Key     string
+	Message OutboxMessage
+}
+
 // ReminderStatus - status of a remidner.
 type ReminderStatus string
 
@@ -89,8 +172,43 @@ const (
 	ReminderStatusDone ReminderStatus = "done"
 	// ReminderStatusAttemptsExhausted describes the situation in which all attempts to receive 'done' from user are finished.
 	ReminderStatusAttemptsExhausted ReminderStatus = "attempts_exhausted"
+	// ReminderStatusChatNotFound - reminder can't be sent because chat is deleted or bot is removed from it.
+	ReminderStatusChatNotFound ReminderStatus = "chat_not_found"
+	// ReminderStatusMissed - reminder wasn't sent in time because the bot was down or user disabled reminders.
+	// Missed reminders are sent again only if user reschedules them.
+	ReminderStatusMissed ReminderStatus = "missed"
 )
 
+// ParseReminderStatus parses reminder status.
+func ParseReminderStatus(s string) (ReminderStatus, error) {
+	switch status := ReminderStatus(s); status {
+	case ReminderStatusPending, ReminderStatusDone, ReminderStatusAttemptsExhausted, ReminderStatusChatNotFound, ReminderStatusMissed:
+		return status, nil
+	default:
+		return "", fmt.Errorf("unknown reminder status %q", s)
+	}
+}
+
+// ErrReminderTextEmpty - reminder has no text.
+var ErrReminderTextEmpty = errors.New("reminder text is empty")
+
+// Validate checks that new reminder can be created: it has text, known priority and remind time after now.
+func (r Reminder) Validate(now time.Time) error {
+	if strings.TrimSpace(r.Text) == "" {
+		return ErrReminderTextEmpty
+	}
+
+	if _, err := ParseReminderPriority(string(r.Priority)); err != nil {
+		return err
+	}
+
+	if !r.RemindAt.After(now) {
+		return ErrRemindAtInPast
+	}
+
+	return nil
+}
+
 func getRussianMonth(m time.Month) string {
 	switch m {
 	case time.January:
@@ -0,0 +1,210 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReminderEventType - type of reminder state transition.
+type ReminderEventType string
+
+const (
+	// ReminderEventCreated - reminder was created.
+	ReminderEventCreated ReminderEventType = "created"
+	// ReminderEventNotified - reminder was sent to user, event contains attempt number.
+	ReminderEventNotified ReminderEventType = "notified"
+	// ReminderEventDelayed - reminder was delayed, event contains previous and new remind time.
+	ReminderEventDelayed ReminderEventType = "delayed"
+	// ReminderEventDone - user marked reminder as done.
+	ReminderEventDone ReminderEventType = "done"
+	// ReminderEventExhausted - all attempts to receive 'done' from user are finished.
+	ReminderEventExhausted ReminderEventType = "exhausted"
+	// ReminderEventRemoved - reminder was removed.
+	ReminderEventRemoved ReminderEventType = "removed"
+	// ReminderEventMissed - reminder was missed because the bot was down or user disabled reminders.
+	ReminderEventMissed ReminderEventType = "missed"
+	// ReminderEventChatNotFound - reminder was stopped because chat is not available to the bot anymore.
+	ReminderEventChatNotFound ReminderEventType = "chat_not_found"
+	// ReminderEventEdited - text of reminder was changed.
+	ReminderEventEdited ReminderEventType = "edited"
+)
+
+// ReminderEventTypeByStatus returns type of event which moves reminder to status.
+// Pending status has no event, reminder becomes pending again only when it's delayed.
+func ReminderEventTypeByStatus(status ReminderStatus) (ReminderEventType, bool) {
+	switch status {
+	case ReminderStatusDone:
+		return ReminderEventDone, true
+	case ReminderStatusAttemptsExhausted:
+		return ReminderEventExhausted, true
+	case ReminderStatusMissed:
+		return ReminderEventMissed, true
+	case ReminderStatusChatNotFound:
+		return ReminderEventChatNotFound, true
+	default:
+		return "", false
+	}
+}
+
+// ActorType - type of the one who changed reminder.
+type ActorType string
+
+const (
+	// ActorUser - reminder was changed by user in the bot.
+	ActorUser ActorType = "user"
+	// ActorNotifier - reminder was changed by notifier.
+	ActorNotifier ActorType = "notifier"
+	// ActorAdmin - reminder was changed by admin with admin command.
+	ActorAdmin ActorType = "admin"
+	// ActorSystem - reminder was changed by the bot itself, e.g. when delivery of message failed.
+	ActorSystem ActorType = "system"
+	// ActorOperator - reminder was changed by operator with CLI subcommand.
+	ActorOperator ActorType = "operator"
+)
+
+// Label returns russian label of actor type.
+func (t ActorType) Label() string {
+	switch t {
+	case ActorUser:
+		return "пользователь"
+	case ActorNotifier:
+		return "бот"
+	case ActorAdmin:
+		return "администратор"
+	case ActorOperator:
+		return "оператор"
+	default:
+		return "система"
+	}
+}
+
+// Actor - the one who changed reminder. ID is telegram user id for user and admin, zero otherwise.
+type Actor struct {
+	Type ActorType
+	ID   int64
+}
+
+type actorCtxKey struct{}
+
+// ContextWithActor returns copy of ctx with actor, changes of reminders made with the context are recorded as made by actor.
+func ContextWithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorCtxKey{}, actor)
+}
+
+// ContextWithDefaultActor returns copy of ctx with actor if ctx has no actor yet.
+func ContextWithDefaultActor(ctx context.Context, actor Actor) context.Context {
+	if _, ok := ctx.Value(actorCtxKey{}).(Actor); ok {
+		return ctx
+	}
+
+	return ContextWithActor(ctx, actor)
+}
+
+// ActorFromContext returns actor set by [ContextWithActor], [ActorSystem] if actor isn't set.
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorCtxKey{}).(Actor); ok {
+		return actor
+	}
+
+	return Actor{Type: ActorSystem}
+}
+
+// ReminderEvent - reminder state transition.
+type ReminderEvent struct {
+	ID         int64             `db:"id"`
+	ReminderID int64             `db:"reminder_id"`
+	Type       ReminderEventType `db:"type"`
+	ActorType  ActorType         `db:"actor"`
+	ActorID    int64             `db:"actor_id"`
+	// attempt number for notified event
+	Attempt int `db:"attempt"`
+	// remind time before and after the transition, set for created and delayed events
+	RemindAtFrom *time.Time `db:"remind_at_from"`
+	RemindAtTo   *time.Time `db:"remind_at_to"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+func (e ReminderEvent) String() string {
+	return fmt.Sprintf("[ID: %d, ReminderID: %d, Type: %s, Actor: %s/%d, Attempt: %d]", e.ID, e.ReminderID, e.Type, e.ActorType, e.ActorID, e.Attempt)
+}
+
+// Format - format event to send to user as an entity of reminder history. Actor id is shown only if withActorID is set.
+func (e ReminderEvent) Format(withActorID bool) string {
+	var sb strings.Builder
+	sb.WriteString(formatEventTime(e.CreatedAt))
+	sb.WriteString(" — ")
+
+	switch e.Type {
+	case ReminderEventCreated:
+		sb.WriteString("создано")
+		if e.RemindAtTo != nil {
+			sb.WriteString(" на ")
+			sb.WriteString(formatEventTime(*e.RemindAtTo))
+		}
+	case ReminderEventNotified:
+		sb.WriteString(fmt.Sprintf("отправлено, попытка %d", e.Attempt))
+	case ReminderEventDelayed:
+		sb.WriteString("отложено")
+		if e.RemindAtFrom != nil {
+			sb.WriteString(" с ")
+			sb.WriteString(formatEventTime(*e.RemindAtFrom))
+		}
+		if e.RemindAtTo != nil {
+			sb.WriteString(" на ")
+			sb.WriteString(formatEventTime(*e.RemindAtTo))
+		}
+	case ReminderEventDone:
+		sb.WriteString("выполнено")
+	case ReminderEventExhausted:
+		sb.WriteString("попытки закончились")
+	case ReminderEventRemoved:
+		sb.WriteString("удалено")
+	case ReminderEventMissed:
+		sb.WriteString("пропущено")
+	case ReminderEventChatNotFound:
+		sb.WriteString("чат недоступен")
+	case ReminderEventEdited:
+		sb.WriteString("текст изменён")
+	default:
+		sb.WriteString(string(e.Type))
+	}
+
+	sb.WriteString(" (")
+	sb.WriteString(e.ActorType.Label())
+	if withActorID && e.ActorID != 0 {
+		sb.WriteString(fmt.Sprintf(" %d", e.ActorID))
+	}
+	sb.WriteString(")")
+
+	return sb.String()
+}
+
+// FormatHistory - format reminder history to send to user. Actor ids are shown only if withActorID is set.
+// Reminder is titled by id if it has no text, e.g. it's already removed.
+func FormatHistory(reminder Reminder, events []ReminderEvent, withActorID bool) string {
+	var sb strings.Builder
+	title := fmt.Sprintf("#%d", reminder.ID)
+	if reminder.Text != "" {
+		title = "«" + reminder.Text + "»"
+	}
+	sb.WriteString(fmt.Sprintf("%s *История напоминания %s*\n", EmojiScroll, title))
+
+	if len(events) == 0 {
+		sb.WriteString("\nИстория пуста")
+		return sb.String()
+	}
+
+	for _, e := range events {
+		sb.WriteString("\n\t• ")
+		sb.WriteString(e.Format(withActorID))
+	}
+
+	return sb.String()
+}
+
+func formatEventTime(t time.Time) string {
+	tMSK := MoscowTime(t)
+	return fmt.Sprintf("%d %s %s", tMSK.Day(), getRussianMonth(tMSK.Month()), tMSK.Format(layoutTimeOnly))
+}
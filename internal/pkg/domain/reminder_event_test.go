@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActorFromContext(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, Actor{Type: ActorSystem}, ActorFromContext(context.TODO()))
+
+	adminCtx := ContextWithActor(context.TODO(), Actor{Type: ActorAdmin, ID: 1})
+	assert.Equal(t, Actor{Type: ActorAdmin, ID: 1}, ActorFromContext(adminCtx))
+	assert.Equal(t, Actor{Type: ActorAdmin, ID: 1}, ActorFromContext(ContextWithDefaultActor(adminCtx, Actor{Type: ActorUser, ID: 1})))
+	assert.Equal(t, Actor{Type: ActorUser, ID: 2}, ActorFromContext(ContextWithDefaultActor(context.TODO(), Actor{Type: ActorUser, ID: 2})))
+}
+
+func TestFormatHistory(t *testing.T) {
+	t.Parallel()
+
+	var (
+		at       = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		from, to = at.Add(time.Hour), at.Add(24 * time.Hour)
+	)
+
+	events := []ReminderEvent{
+		{Type: ReminderEventCreated, ActorType: ActorUser, ActorID: 1, RemindAtTo: &from, CreatedAt: at},
+		{Type: ReminderEventNotified, ActorType: ActorNotifier, Attempt: 1, CreatedAt: at},
+		{Type: ReminderEventDelayed, ActorType: ActorUser, ActorID: 1, RemindAtFrom: &from, RemindAtTo: &to, CreatedAt: at},
+		{Type: ReminderEventEdited, ActorType: ActorUser, ActorID: 1, CreatedAt: at},
+		{Type: ReminderEventChatNotFound, ActorType: ActorSystem, CreatedAt: at},
+		{Type: ReminderEventRemoved, ActorType: ActorOperator, CreatedAt: at},
+	}
+
+	assert.Equal(t, "📜 *История напоминания «Standup»*\n\n"+
+		"\t• 1 янв. 15:00 — создано на 1 янв. 16:00 (пользователь)\n"+
+		"\t• 1 янв. 15:00 — отправлено, попытка 1 (бот)\n"+
+		"\t• 1 янв. 15:00 — отложено с 1 янв. 16:00 на 2 янв. 15:00 (пользователь)\n"+
+		"\t• 1 янв. 15:00 — текст изменён (пользователь)\n"+
+		"\t• 1 янв. 15:00 — чат недоступен (система)\n"+
+		"\t• 1 янв. 15:00 — удалено (оператор)",
+		FormatHistory(Reminder{ID: 1, Text: "Standup"}, events, false))
+	assert.Equal(t, "📜 *История напоминания #1*\n\n\t• 1 янв. 15:00 — создано на 1 янв. 16:00 (пользователь 1)",
+		FormatHistory(Reminder{ID: 1}, events[:1], true))
+	assert.Equal(t, "📜 *История напоминания «Standup»*\n\nИстория пуста", FormatHistory(Reminder{ID: 1, Text: "Standup"}, nil, false))
+}
+
+func TestReminderEventTypeByStatus(t *testing.T) {
+	t.Parallel()
+
+	eventType, ok := ReminderEventTypeByStatus(ReminderStatusDone)
+	assert.True(t, ok)
+	assert.Equal(t, ReminderEventDone, eventType)
+
+	_, ok = ReminderEventTypeByStatus(ReminderStatusPending)
+	assert.False(t, ok)
+}
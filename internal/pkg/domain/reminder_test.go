@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -31,6 +32,14 @@ func TestReminder_String(t *testing.T) {
 	assert.EqualValues(t, "done", ReminderStatusDone)
 }
 
+func TestReminder_IsOwnedBy(t *testing.T) {
+	t.Parallel()
+	reminder := Reminder{UserID: 1, ChatID: 2}
+	assert.True(t, reminder.IsOwnedBy(1, 2))
+	assert.False(t, reminder.IsOwnedBy(3, 2))
+	assert.False(t, reminder.IsOwnedBy(1, 3))
+}
+
 func Test_getRussianMonth(t *testing.T) {
 	t.Parallel()
 	a := assert.New(t)
@@ -54,6 +63,33 @@ func TestReminder_FormatNotify(t *testing.T) {
 	require.Equal(t, "‼️*НАПОМИНАНИЕ*‼️\n\n*DO SOME THING*\n\nСегодня 02:30 ⏰\n\nЧтобы отложить напоминание используйте кнопки\u00a0🔄, расположенные ниже.", r.FormatNotify())
 }
 
+func TestReminder_FormatNotify_Checklist(t *testing.T) {
+	t.Parallel()
+	r := Reminder{Text: "buy", Checklist: Checklist{{Text: "milk", Done: true}, {Text: "bread"}}}
+	require.Equal(t, "‼️*НАПОМИНАНИЕ*‼️\n\n*BUY*\n\n📋 Выполнено 1/2\n\nСегодня 02:30\u00a0⏰\n\nЧтобы отложить напоминание используйте кнопки\u00a0🔄, расположенные ниже.", r.FormatNotify())
+}
+
+func TestReminder_FormatPreNotify(t *testing.T) {
+	t.Parallel()
+	r := Reminder{Text: "Meeting", RemindAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	require.Equal(t, "🔔 *Скоро напоминание*\n\n*Meeting*\n\nЧерез 1 ч. 30 мин., в 15:00\u00a0⏰", r.FormatPreNotify(time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)))
+}
+
+func Test_FormatMissed(t *testing.T) {
+	t.Parallel()
+
+	reminders := []Reminder{
+		{Text: "Meeting", RemindAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{Text: "Call mom", RemindAt: time.Date(2024, 1, 2, 7, 30, 0, 0, time.UTC)},
+	}
+	require.Equal(t, "⏰ *Пропущенные напоминания: 2*\n\n\t• *Meeting* — 1 янв. 15:00\n\t• *Call mom* — 2 янв. 10:30\n\nКогда напомнить о них снова❓", FormatMissed(reminders))
+
+	for i := 0; i < 10; i++ {
+		reminders = append(reminders, Reminder{Text: "foo"})
+	}
+	require.True(t, strings.HasSuffix(FormatMissed(reminders), "\n\t• и ещё 2\n\nКогда напомнить о них снова❓"))
+}
+
 func TestReminder_FormatList(t *testing.T) {
 	t.Parallel()
 
@@ -88,6 +124,39 @@ func TestReminder_FormatList(t *testing.T) {
 			},
 			expRes: "✅ *Foo bar baz*\n⏰ 2 янв. 03:00\n#️⃣ 1",
 		},
+		{
+			name: "high priority",
+			now:  jan1,
+			reminder: Reminder{
+				ID:       1,
+				Text:     "Foo bar baz",
+				RemindAt: jan2,
+				Priority: ReminderPriorityHigh,
+			},
+			expRes: "✅ *Foo bar baz*\n⏰ 2 янв. 03:00\n🔺 Высокий приоритет\n#️⃣ 1",
+		},
+		{
+			name: "normal priority",
+			now:  jan1,
+			reminder: Reminder{
+				ID:       1,
+				Text:     "Foo bar baz",
+				RemindAt: jan2,
+				Priority: ReminderPriorityNormal,
+			},
+			expRes: "✅ *Foo bar baz*\n⏰ 2 янв. 03:00\n#️⃣ 1",
+		},
+		{
+			name: "checklist",
+			now:  jan1,
+			reminder: Reminder{
+				ID:        1,
+				Text:      "Foo bar baz",
+				RemindAt:  jan2,
+				Checklist: Checklist{{Text: "foo", Done: true}, {Text: "bar"}, {Text: "baz"}},
+			},
+			expRes: "✅ *Foo bar baz*\n⏰ 2 янв. 03:00\n📋 1/3\n#️⃣ 1",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -98,3 +167,56 @@ func TestReminder_FormatList(t *testing.T) {
 		})
 	}
 }
+
+func TestParseReminderStatus(t *testing.T) {
+	t.Parallel()
+
+	for _, exp := range []ReminderStatus{
+		ReminderStatusPending,
+		ReminderStatusDone,
+		ReminderStatusAttemptsExhausted,
+		ReminderStatusChatNotFound,
+		ReminderStatusMissed,
+	} {
+		act, err := ParseReminderStatus(string(exp))
+		require.NoError(t, err)
+		assert.Equal(t, exp, act)
+	}
+
+	_, err := ParseReminderStatus("")
+	require.EqualError(t, err, `unknown reminder status ""`)
+	_, err = ParseReminderStatus("removed")
+	require.EqualError(t, err, `unknown reminder status "removed"`)
+}
+
+func TestReminder_Validate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		reminder Reminder
+		expErr   string
+	}{
+		{name: "success", reminder: Reminder{Text: "buy milk", RemindAt: now.Add(time.Minute)}},
+		{name: "success: priority", reminder: Reminder{Text: "buy milk", RemindAt: now.Add(time.Minute), Priority: ReminderPriorityHigh}},
+		{name: "error: empty text", reminder: Reminder{Text: " ", RemindAt: now.Add(time.Minute)}, expErr: ErrReminderTextEmpty.Error()},
+		{name: "error: unknown priority", reminder: Reminder{Text: "buy milk", RemindAt: now.Add(time.Minute), Priority: "urgent"}, expErr: `unknown reminder priority "urgent"`},
+		{name: "error: remind at is now", reminder: Reminder{Text: "buy milk", RemindAt: now}, expErr: ErrRemindAtInPast.Error()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.reminder.Validate(now)
+			if tc.expErr != "" {
+				assert.EqualError(t, err, tc.expErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
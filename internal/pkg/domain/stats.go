@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Stats - bot statistics for admins.
+type Stats struct {
+	UsersActive        int64
+	UsersInactive      int64
+	UsersBlocked       int64
+	RemindersPending   int64
+	RemindersDone      int64
+	RemindersExhausted int64
+	// OldestOverdueRemindAt is a remindAt of the oldest reminder which should have been already sent by notifier.
+	// Zero if notifier has no overdue reminders.
+	OldestOverdueRemindAt time.Time
+}
+
+// NotifierLag returns how late notifier is with sending reminders.
+func (s Stats) NotifierLag(now time.Time) time.Duration {
+	if s.OldestOverdueRemindAt.IsZero() || s.OldestOverdueRemindAt.After(now) {
+		return 0
+	}
+
+	return now.Sub(s.OldestOverdueRemindAt).Truncate(time.Second)
+}
+
+// Format - format statistics to send to admin.
+func (s Stats) Format(now time.Time) string {
+	return fmt.Sprintf(`*Статистика* %s
+
+*Пользователи:* %d
+	• активные: %d
+	• неактивные: %d
+	• заблокированные: %d
+
+*Напоминания:*
+	• ожидают: %d
+	• выполнены: %d
+	• попытки исчерпаны: %d
+
+*Задержка уведомлений:* %s`,
+		EmojiBarChart,
+		s.UsersActive+s.UsersInactive+s.UsersBlocked, s.UsersActive, s.UsersInactive, s.UsersBlocked,
+		s.RemindersPending, s.RemindersDone, s.RemindersExhausted,
+		s.NotifierLag(now),
+	)
+}
+
+// DBStats - database statistics for operators.
+type DBStats struct {
+	SizeBytes int64
+	Tables    []TableSize
+}
+
+// TableSize - number of rows in a database table.
+type TableSize struct {
+	Name string `db:"name"`
+	Rows int64  `db:"rows"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats_NotifierLag(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Zero(t, Stats{}.NotifierLag(now))
+	assert.Zero(t, Stats{OldestOverdueRemindAt: now.Add(1 * time.Minute)}.NotifierLag(now))
+	assert.Equal(t, 90*time.Second, Stats{OldestOverdueRemindAt: now.Add(-90*time.Second - 300*time.Millisecond)}.NotifierLag(now))
+}
+
+func TestStats_Format(t *testing.T) {
+	t.Parallel()
+
+	stats := Stats{UsersActive: 1, RemindersPending: 2}
+	assert.Equal(t, "*Статистика* 📊\n\n*Пользователи:* 1\n\t• активные: 1\n\t• неактивные: 0\n\t• заблокированные: 0\n\n*Напоминания:*\n\t• ожидают: 2\n\t• выполнены: 0\n\t• попытки исчерпаны: 0\n\n*Задержка уведомлений:* 0s", stats.Format(time.Now()))
+}
@@ -10,10 +10,11 @@ import (
 // TgCallbackQuery represents an incoming callback query from a callback button in
 // an inline keyboard. See [github.com/go-telegram-bot-api/telegram-bot-api/v5.CallbackQuery].
 type TgCallbackQuery struct {
-	ChatID   int64
-	UserID   int64
-	UserName string
-	Data     string
+	ChatID    int64
+	UserID    int64
+	UserName  string
+	MessageID int64 // id of message with the button
+	Data      string
 }
 
 const (
@@ -21,15 +22,53 @@ const (
 	ButtonDataPrefixRemindAtTime = "btn_remind_at/time/"
 	// ButtonDataPrefixRemindAtDuration - button prefix for [domain.TgCallbackQuery] data which contains remindAt formatted as duration.
 	ButtonDataPrefixRemindAtDuration = "btn_remind_at/duration/"
+	// ButtonDataPrefixRemindAtOption - button prefix for [domain.TgCallbackQuery] data which contains [domain.QuickOption] to set remindAt.
+	ButtonDataPrefixRemindAtOption = "btn_remind_at/option/"
+	// ButtonDataPrefixRemindAtCalendar - button prefix for [domain.TgCallbackQuery] data which contains month to show in calendar picker.
+	// Empty month means the current one.
+	ButtonDataPrefixRemindAtCalendar = "btn_remind_at/calendar/"
+	// ButtonDataPrefixRemindAtDate - button prefix for [domain.TgCallbackQuery] data which contains date picked in calendar.
+	ButtonDataPrefixRemindAtDate = "btn_remind_at/date/"
+	// ButtonDataPrefixRemindAtHour - button prefix for [domain.TgCallbackQuery] data which contains date and hour picked in hour picker.
+	ButtonDataPrefixRemindAtHour = "btn_remind_at/hour/"
+	// ButtonDataPrefixRemindAtMinute - button prefix for [domain.TgCallbackQuery] data which contains remindAt picked in minute picker.
+	ButtonDataPrefixRemindAtMinute = "btn_remind_at/minute/"
 	// ButtonDataPrefixReminderDone - button prefix for [domain.TgCallbackQuery] data which contains id of reminder to mark it as done.
 	ButtonDataPrefixReminderDone = "btn_reminder_done/"
-	// ButtonDataPrefixDelayReminder - button prefix for [domain.TgCallbackQuery] data which contains duration to delay reminder.
+	// ButtonDataPrefixDelayReminder - button prefix for [domain.TgCallbackQuery] data which contains reminder id and [domain.QuickOption] to delay reminder.
 	ButtonDataPrefixDelayReminder = "btn_delay_reminder/"
+	// ButtonDataPrefixReminderPriority - button prefix for [domain.TgCallbackQuery] data which contains priority of reminder being created.
+	ButtonDataPrefixReminderPriority = "btn_reminder_priority/"
+	// ButtonDataPrefixLeadTime - button prefix for [domain.TgCallbackQuery] data which contains reminder id and lead time to toggle.
+	ButtonDataPrefixLeadTime = "btn_lead_time/"
+	// ButtonDataPrefixAddChecklistItems - button prefix for [domain.TgCallbackQuery] data which contains id of reminder to add checklist items to.
+	ButtonDataPrefixAddChecklistItems = "btn_checklist_add/"
+	// ButtonDataPrefixToggleChecklistItem - button prefix for [domain.TgCallbackQuery] data which contains id of checklist item to check or uncheck.
+	ButtonDataPrefixToggleChecklistItem = "btn_checklist_item/"
+	// ButtonDataPrefixShiftRemindAt - button prefix for [domain.TgCallbackQuery] data which contains duration to shift remindAt of reminder being created.
+	ButtonDataPrefixShiftRemindAt = "btn_remind_at_shift/"
+	// ButtonDataPrefixCatchUp - button prefix for [domain.TgCallbackQuery] data which contains key of [domain.MissedSummary]
+	// and [domain.QuickOption] to reschedule missed reminders.
+	ButtonDataPrefixCatchUp = "btn_catch_up/"
+	// ButtonDataPrefixAdminBlockUser - button prefix for [domain.TgCallbackQuery] data which contains id of user to block.
+	ButtonDataPrefixAdminBlockUser = "btn_admin_block/"
+	// ButtonDataPrefixAdminUnblockUser - button prefix for [domain.TgCallbackQuery] data which contains id of user to unblock.
+	ButtonDataPrefixAdminUnblockUser = "btn_admin_unblock/"
 
 	// ButtonDataEditReminder - [domain.TgCallbackQuery] data for edit reminder button.
 	ButtonDataEditReminder = "btn_edit_reminder"
 	// ButtonDataRemoveReminder - [domain.TgCallbackQuery] data for remove reminder button.
 	ButtonDataRemoveReminder = "btn_remove_reminder"
+	// ButtonDataReminderHistory - [domain.TgCallbackQuery] data for reminder history button.
+	ButtonDataReminderHistory = "btn_reminder_history"
+	// ButtonDataNoop - [domain.TgCallbackQuery] data for button which does nothing, e.g. weekday in calendar.
+	ButtonDataNoop = "btn_noop"
+	// ButtonDataConfirmRemindAt - [domain.TgCallbackQuery] data for button to confirm remindAt of reminder being created.
+	ButtonDataConfirmRemindAt = "btn_remind_at_confirm"
+	// ButtonDataCatchUpSkip - [domain.TgCallbackQuery] data for button to skip missed reminders.
+	ButtonDataCatchUpSkip = "btn_catch_up_skip"
+	// ButtonDataReenterRemindAt - [domain.TgCallbackQuery] data for button to enter remindAt of reminder being created again.
+	ButtonDataReenterRemindAt = "btn_remind_at_reenter"
 )
 
 // IsButtonClick returns true, if callback query is a known button click.
@@ -45,10 +84,27 @@ func (q TgCallbackQuery) String() string {
 // LayoutRemindAt is the layout to format and parse [domain.Reminder] RemindAt.
 const LayoutRemindAt = "2006-01-02 15:04"
 
+// Layouts of date and time in picker buttons data.
+const (
+	LayoutPickerMonth  = "2006-01"
+	LayoutPickerDate   = "2006-01-02"
+	LayoutPickerHour   = "2006-01-02T15"
+	LayoutPickerMinute = "2006-01-02T15:04"
+)
+
 // RemindAt extracts date and time when reminder should be sent to user.
 func (q TgCallbackQuery) RemindAt(now time.Time) (time.Time, error) {
 	now = MoscowTime(now)
 
+	if minuteSuffix, ok := strings.CutPrefix(q.Data, ButtonDataPrefixRemindAtMinute); ok {
+		remindAt, err := time.ParseInLocation(LayoutPickerMinute, minuteSuffix, now.Location())
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse remindAt minute: %w", err)
+		}
+
+		return remindAt, nil
+	}
+
 	if timeSuffix, ok := strings.CutPrefix(q.Data, ButtonDataPrefixRemindAtTime); ok {
 		remindAtTime, err := time.Parse("15:04", timeSuffix)
 		if err != nil {
@@ -74,20 +130,64 @@ func (q TgCallbackQuery) RemindAt(now time.Time) (time.Time, error) {
 		return now.Add(remindAtDuration).Truncate(1 * time.Minute), nil
 	}
 
+	if optionSuffix, ok := strings.CutPrefix(q.Data, ButtonDataPrefixRemindAtOption); ok {
+		option, err := ParseQuickOption(optionSuffix)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse remindAt option: %w", err)
+		}
+
+		return option.RemindAt(now)
+	}
+
+	if suffix, ok := strings.CutPrefix(q.Data, ButtonDataPrefixCatchUp); ok {
+		// buttons of summaries sent before summaries got keys contain option only
+		fields := strings.Split(suffix, "/")
+		option, err := ParseQuickOption(fields[len(fields)-1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse catch up option: %w", err)
+		}
+
+		return option.RemindAt(now)
+	}
+
 	if suffix, ok := strings.CutPrefix(q.Data, ButtonDataPrefixDelayReminder); ok {
 		if fields := strings.Split(suffix, "/"); len(fields) == 2 {
-			remindAtDuration, err := time.ParseDuration(fields[1])
+			option, err := ParseQuickOption(fields[1])
 			if err != nil {
-				return time.Time{}, fmt.Errorf("failed to parse remindAt duration: %w", err)
+				return time.Time{}, fmt.Errorf("failed to parse snooze option: %w", err)
 			}
 
-			return now.Add(remindAtDuration).Truncate(1 * time.Minute), nil
+			return option.RemindAt(now)
 		}
 	}
 
 	return time.Time{}, fmt.Errorf("unknown remindAt format: %s", q.Data)
 }
 
+// PickerTime extracts intermediate selection of calendar, hour or minute picker in Moscow time:
+// the first day of month to show in calendar, picked date or picked hour.
+func (q TgCallbackQuery) PickerTime(now time.Time) (time.Time, error) {
+	now = MoscowTime(now)
+
+	if monthSuffix, ok := strings.CutPrefix(q.Data, ButtonDataPrefixRemindAtCalendar); ok {
+		if monthSuffix == "" {
+			return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()), nil
+		}
+
+		return time.ParseInLocation(LayoutPickerMonth, monthSuffix, now.Location())
+	}
+
+	if dateSuffix, ok := strings.CutPrefix(q.Data, ButtonDataPrefixRemindAtDate); ok {
+		return time.ParseInLocation(LayoutPickerDate, dateSuffix, now.Location())
+	}
+
+	if hourSuffix, ok := strings.CutPrefix(q.Data, ButtonDataPrefixRemindAtHour); ok {
+		return time.ParseInLocation(LayoutPickerHour, hourSuffix, now.Location())
+	}
+
+	return time.Time{}, fmt.Errorf("unknown picker format: %s", q.Data)
+}
+
 // ReminderID extracts reminder id.
 func (q TgCallbackQuery) ReminderID() (int64, error) {
 	if idSuffix, ok := strings.CutPrefix(q.Data, ButtonDataPrefixReminderDone); ok {
@@ -100,11 +200,85 @@ func (q TgCallbackQuery) ReminderID() (int64, error) {
 		}
 	}
 
+	if suffix, ok := strings.CutPrefix(q.Data, ButtonDataPrefixLeadTime); ok {
+		if fields := strings.Split(suffix, "/"); len(fields) == 2 {
+			return strconv.ParseInt(fields[0], 10, 64)
+		}
+	}
+
+	if idSuffix, ok := strings.CutPrefix(q.Data, ButtonDataPrefixAddChecklistItems); ok {
+		return strconv.ParseInt(idSuffix, 10, 64)
+	}
+
 	return 0, fmt.Errorf("unknown reminder id format: %s", q.Data)
 }
 
+// ChecklistItemID extracts checklist item id.
+func (q TgCallbackQuery) ChecklistItemID() (int64, error) {
+	if idSuffix, ok := strings.CutPrefix(q.Data, ButtonDataPrefixToggleChecklistItem); ok {
+		return strconv.ParseInt(idSuffix, 10, 64)
+	}
+
+	return 0, fmt.Errorf("unknown checklist item id format: %s", q.Data)
+}
+
+// SummaryKey extracts key of [MissedSummary].
+func (q TgCallbackQuery) SummaryKey() (string, error) {
+	if suffix, ok := strings.CutPrefix(q.Data, ButtonDataPrefixCatchUp); ok {
+		if fields := strings.Split(suffix, "/"); len(fields) == 2 && fields[0] != "" {
+			return fields[0], nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown summary key format: %s", q.Data)
+}
+
+// RemindAtShift extracts duration to shift remindAt, it can be negative.
+func (q TgCallbackQuery) RemindAtShift() (time.Duration, error) {
+	if suffix, ok := strings.CutPrefix(q.Data, ButtonDataPrefixShiftRemindAt); ok {
+		return time.ParseDuration(suffix)
+	}
+
+	return 0, fmt.Errorf("unknown remindAt shift format: %s", q.Data)
+}
+
+// LeadTime extracts lead time of advance notice.
+func (q TgCallbackQuery) LeadTime() (time.Duration, error) {
+	if suffix, ok := strings.CutPrefix(q.Data, ButtonDataPrefixLeadTime); ok {
+		if fields := strings.Split(suffix, "/"); len(fields) == 2 {
+			return time.ParseDuration(fields[1])
+		}
+	}
+
+	return 0, fmt.Errorf("unknown lead time format: %s", q.Data)
+}
+
+// ReminderPriority extracts priority of reminder being created.
+func (q TgCallbackQuery) ReminderPriority() (ReminderPriority, error) {
+	if prioritySuffix, ok := strings.CutPrefix(q.Data, ButtonDataPrefixReminderPriority); ok && prioritySuffix != "" {
+		return ParseReminderPriority(prioritySuffix)
+	}
+
+	return "", fmt.Errorf("unknown reminder priority format: %s", q.Data)
+}
+
+// SubjectUserID extracts id of user which is a subject of admin button click.
+func (q TgCallbackQuery) SubjectUserID() (int64, error) {
+	if idSuffix, ok := strings.CutPrefix(q.Data, ButtonDataPrefixAdminBlockUser); ok {
+		return strconv.ParseInt(idSuffix, 10, 64)
+	}
+
+	if idSuffix, ok := strings.CutPrefix(q.Data, ButtonDataPrefixAdminUnblockUser); ok {
+		return strconv.ParseInt(idSuffix, 10, 64)
+	}
+
+	return 0, fmt.Errorf("unknown user id format: %s", q.Data)
+}
+
 // IsRemindAtButtonClick returns true is callback is a button click to set remindAt.
 func (q TgCallbackQuery) IsRemindAtButtonClick() bool {
 	return strings.HasPrefix(q.Data, ButtonDataPrefixRemindAtTime) ||
-		strings.HasPrefix(q.Data, ButtonDataPrefixRemindAtDuration)
+		strings.HasPrefix(q.Data, ButtonDataPrefixRemindAtDuration) ||
+		strings.HasPrefix(q.Data, ButtonDataPrefixRemindAtOption) ||
+		strings.HasPrefix(q.Data, ButtonDataPrefixRemindAtMinute)
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTgCallbackQuery_IsButtonClick(t *testing.T) {
@@ -17,6 +18,9 @@ func TestTgCallbackQuery_IsRemindAtButtonClick(t *testing.T) {
 	t.Parallel()
 	assert.True(t, TgCallbackQuery{Data: "btn_remind_at/time/"}.IsRemindAtButtonClick())
 	assert.True(t, TgCallbackQuery{Data: "btn_remind_at/duration/"}.IsRemindAtButtonClick())
+	assert.True(t, TgCallbackQuery{Data: "btn_remind_at/minute/"}.IsRemindAtButtonClick())
+	assert.True(t, TgCallbackQuery{Data: "btn_remind_at/option/"}.IsRemindAtButtonClick())
+	assert.False(t, TgCallbackQuery{Data: "btn_remind_at/calendar/"}.IsRemindAtButtonClick())
 	assert.False(t, TgCallbackQuery{Data: "btn_reminder_done"}.IsRemindAtButtonClick())
 }
 
@@ -54,6 +58,34 @@ func TestTgCallbackQuery_RemindAt(t *testing.T) {
 			query:  TgCallbackQuery{Data: "btn_delay_reminder/1234/1h"},
 			expRes: time.Date(2024, 1, 1, 16, 0, 0, 0, locationMSK),
 		},
+		{
+			name:   "success: remind at minute",
+			now:    time.Date(2024, 1, 1, 12, 0, 1, 0, time.UTC),
+			query:  TgCallbackQuery{Data: "btn_remind_at/minute/2024-10-20T10:05"},
+			expRes: time.Date(2024, 10, 20, 10, 5, 0, 0, locationMSK),
+		},
+		{
+			name:   "error: remind at minute, can't parse",
+			query:  TgCallbackQuery{Data: "btn_remind_at/minute/foo"},
+			expErr: `failed to parse remindAt minute: parsing time "foo" as "2006-01-02T15:04": cannot parse "foo" as "2006"`,
+		},
+		{
+			name:   "success: remind at option",
+			now:    time.Date(2024, 1, 1, 12, 0, 1, 0, time.UTC),
+			query:  TgCallbackQuery{Data: "btn_remind_at/option/tomorrow_morning"},
+			expRes: time.Date(2024, 1, 2, 9, 0, 0, 0, locationMSK),
+		},
+		{
+			name:   "success: delay reminder with semantic option",
+			now:    time.Date(2024, 1, 1, 12, 0, 1, 0, time.UTC),
+			query:  TgCallbackQuery{Data: "btn_delay_reminder/1234/next_monday"},
+			expRes: time.Date(2024, 1, 8, 9, 0, 0, 0, locationMSK),
+		},
+		{
+			name:   "error: remind at option, can't parse",
+			query:  TgCallbackQuery{Data: "btn_remind_at/option/foo"},
+			expErr: `failed to parse remindAt option: unknown quick option "foo"`,
+		},
 		{
 			name:   "error: remind at time, can't parse",
 			query:  TgCallbackQuery{Data: "btn_remind_at/time/aaaaa"},
@@ -64,10 +96,27 @@ func TestTgCallbackQuery_RemindAt(t *testing.T) {
 			query:  TgCallbackQuery{Data: "btn_remind_at/duration/aaaaa"},
 			expErr: `failed to parse remindAt duration: time: invalid duration "aaaaa"`,
 		},
+		{
+			name:   "success: catch up",
+			now:    time.Date(2024, 1, 1, 12, 0, 1, 0, time.UTC),
+			query:  TgCallbackQuery{Data: "btn_catch_up/3h"},
+			expRes: time.Date(2024, 1, 1, 18, 0, 0, 0, locationMSK),
+		},
+		{
+			name:   "success: catch up with summary key",
+			now:    time.Date(2024, 1, 1, 12, 0, 1, 0, time.UTC),
+			query:  TgCallbackQuery{Data: "btn_catch_up/0123456789abcdef/3h"},
+			expRes: time.Date(2024, 1, 1, 18, 0, 0, 0, locationMSK),
+		},
+		{
+			name:   "error: catch up, can't parse",
+			query:  TgCallbackQuery{Data: "btn_catch_up/foo"},
+			expErr: `failed to parse catch up option: unknown quick option "foo"`,
+		},
 		{
 			name:   "error: delay reminder, can't parse",
 			query:  TgCallbackQuery{Data: "btn_delay_reminder/1234/121r4gfsg"},
-			expErr: `failed to parse remindAt duration: time: unknown unit "r" in duration "121r4gfsg"`,
+			expErr: `failed to parse snooze option: unknown quick option "121r4gfsg"`,
 		},
 	}
 
@@ -86,6 +135,29 @@ func TestTgCallbackQuery_RemindAt(t *testing.T) {
 	}
 }
 
+func TestTgCallbackQuery_PickerTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 10, 17, 22, 0, 0, 0, time.UTC) // already 18th in Moscow
+
+	for data, exp := range map[string]time.Time{
+		"btn_remind_at/calendar/":          time.Date(2024, 10, 1, 0, 0, 0, 0, locationMSK),
+		"btn_remind_at/calendar/2024-12":   time.Date(2024, 12, 1, 0, 0, 0, 0, locationMSK),
+		"btn_remind_at/date/2024-10-20":    time.Date(2024, 10, 20, 0, 0, 0, 0, locationMSK),
+		"btn_remind_at/hour/2024-10-20T13": time.Date(2024, 10, 20, 13, 0, 0, 0, locationMSK),
+	} {
+		act, err := TgCallbackQuery{Data: data}.PickerTime(now)
+		require.NoError(t, err)
+		assert.Equal(t, exp, act, data)
+	}
+
+	_, err := TgCallbackQuery{Data: "btn_remind_at/date/foo"}.PickerTime(now)
+	require.Error(t, err)
+
+	_, err = TgCallbackQuery{Data: "foo"}.PickerTime(now)
+	require.EqualError(t, err, "unknown picker format: foo")
+}
+
 func TestTgCallbackQuery_ReminderID(t *testing.T) {
 	t.Parallel()
 
@@ -133,6 +205,89 @@ func TestTgCallbackQuery_ReminderID(t *testing.T) {
 	}
 }
 
+func TestTgCallbackQuery_SubjectUserID(t *testing.T) {
+	t.Parallel()
+
+	id, err := TgCallbackQuery{Data: "btn_admin_block/1234"}.SubjectUserID()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1234, id)
+
+	id, err = TgCallbackQuery{Data: "btn_admin_unblock/4321"}.SubjectUserID()
+	require.NoError(t, err)
+	assert.EqualValues(t, 4321, id)
+
+	_, err = TgCallbackQuery{Data: "foo"}.SubjectUserID()
+	require.EqualError(t, err, "unknown user id format: foo")
+}
+
+func TestTgCallbackQuery_ReminderPriority(t *testing.T) {
+	t.Parallel()
+
+	priority, err := TgCallbackQuery{Data: "btn_reminder_priority/critical"}.ReminderPriority()
+	require.NoError(t, err)
+	assert.Equal(t, ReminderPriorityCritical, priority)
+
+	_, err = TgCallbackQuery{Data: "btn_reminder_priority/urgent"}.ReminderPriority()
+	require.EqualError(t, err, `unknown reminder priority "urgent"`)
+
+	_, err = TgCallbackQuery{Data: "foo"}.ReminderPriority()
+	require.EqualError(t, err, "unknown reminder priority format: foo")
+}
+
+func TestTgCallbackQuery_LeadTime(t *testing.T) {
+	t.Parallel()
+
+	callback := TgCallbackQuery{Data: "btn_lead_time/1234/30m0s"}
+
+	id, err := callback.ReminderID()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1234, id)
+
+	leadTime, err := callback.LeadTime()
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, leadTime)
+
+	_, err = TgCallbackQuery{Data: "foo"}.LeadTime()
+	require.EqualError(t, err, "unknown lead time format: foo")
+}
+
+func TestTgCallbackQuery_SummaryKey(t *testing.T) {
+	t.Parallel()
+
+	key, err := TgCallbackQuery{Data: "btn_catch_up/0123456789abcdef/3h"}.SummaryKey()
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789abcdef", key)
+
+	_, err = TgCallbackQuery{Data: "btn_catch_up/3h"}.SummaryKey()
+	require.EqualError(t, err, "unknown summary key format: btn_catch_up/3h")
+}
+
+func TestTgCallbackQuery_ChecklistItemID(t *testing.T) {
+	t.Parallel()
+
+	id, err := TgCallbackQuery{Data: "btn_checklist_item/42"}.ChecklistItemID()
+	require.NoError(t, err)
+	assert.EqualValues(t, 42, id)
+
+	id, err = TgCallbackQuery{Data: "btn_checklist_add/1234"}.ReminderID()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1234, id)
+
+	_, err = TgCallbackQuery{Data: "foo"}.ChecklistItemID()
+	require.EqualError(t, err, "unknown checklist item id format: foo")
+}
+
+func TestTgCallbackQuery_RemindAtShift(t *testing.T) {
+	t.Parallel()
+
+	shift, err := TgCallbackQuery{Data: "btn_remind_at_shift/-24h"}.RemindAtShift()
+	require.NoError(t, err)
+	assert.Equal(t, -24*time.Hour, shift)
+
+	_, err = TgCallbackQuery{Data: "foo"}.RemindAtShift()
+	require.EqualError(t, err, "unknown remindAt shift format: foo")
+}
+
 func TestTgCallbackQuery_String(t *testing.T) {
 	t.Parallel()
 	query := TgCallbackQuery{
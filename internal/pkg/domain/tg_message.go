@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode"
 
 	log "github.com/go-pkgz/lgr"
 	"github.com/markusmobius/go-dateparser"
@@ -24,26 +25,69 @@ func (m TgMessage) IsCommand() bool {
 	return strings.HasPrefix(m.Text, "/")
 }
 
+// Command returns bot command without arguments and bot name suffix, e.g. "/start" for "/start@foo_bot abc".
+// Returns empty command if message is not a command.
+func (m TgMessage) Command() BotCommand {
+	if !m.IsCommand() {
+		return ""
+	}
+
+	cmd, _ := m.splitCommand()
+	cmd, _, _ = strings.Cut(cmd, "@")
+
+	return BotCommand(cmd)
+}
+
+// CommandArgs returns command arguments, e.g. "abc" for "/start abc".
+func (m TgMessage) CommandArgs() string {
+	if !m.IsCommand() {
+		return ""
+	}
+
+	_, args := m.splitCommand()
+
+	return strings.TrimSpace(args)
+}
+
+// IsPrivate returns true if message is sent to private chat with the bot, its chat id is the id of user.
+func (m TgMessage) IsPrivate() bool {
+	return m.ChatID == m.UserID
+}
+
+// splitCommand splits message text by the first whitespace, arguments can be multiline.
+func (m TgMessage) splitCommand() (cmd, args string) {
+	if i := strings.IndexFunc(m.Text, unicode.IsSpace); i >= 0 {
+		return m.Text[:i], m.Text[i:]
+	}
+
+	return m.Text, ""
+}
+
 // String implements [fmt.Stringer].
 func (m TgMessage) String() string {
 	return fmt.Sprintf("[ChatID: %d, UserID: %d, UserName: %s, Text: %s]", m.ChatID, m.UserID, m.UserName, m.Text)
 }
 
-// RemindAt extracts date and time when reminder should be sent to user.
+// RemindAt extracts date and time when reminder should be sent to user, see [ParseRemindAt].
 func (m TgMessage) RemindAt(now time.Time) (time.Time, error) {
+	return ParseRemindAt(m.Text, now)
+}
+
+// ParseRemindAt parses date and time of reminder from text, e.g. "2024-05-01 18:00", "завтра в 15:00" or "через 30 дней".
+func ParseRemindAt(text string, now time.Time) (time.Time, error) {
 	// TODO: parse remindAt in user timezone, now default is MSK
 	now = MoscowTime(now)
 
-	remindAt, err := time.ParseInLocation(LayoutRemindAt, m.Text, now.Location())
+	remindAt, err := time.ParseInLocation(LayoutRemindAt, text, now.Location())
 	if err != nil {
-		log.Printf("[DEBUG] can't parse (time.ParseInLocation) remindAt %s: %s\n", m.Text, err)
+		log.Printf("[DEBUG] can't parse (time.ParseInLocation) remindAt %s: %s\n", text, err)
 
 		var remindAtDate date.Date
 		if remindAtDate, err = dateparser.Parse(&dateparser.Configuration{
 			CurrentTime:         now,
 			Locales:             []string{"ru"},
 			PreferredDateSource: dateparser.Future,
-		}, m.Text); err != nil {
+		}, text); err != nil {
 			return time.Time{}, fmt.Errorf("can't parse (go-dateparser) remindAt: %w", err)
 		}
 
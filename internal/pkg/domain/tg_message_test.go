@@ -14,6 +14,32 @@ func TestTgMessage_IsCommand(t *testing.T) {
 	assert.False(t, TgMessage{Text: "bar"}.IsCommand())
 }
 
+func TestTgMessage_Command(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	a.Equal(BotCommandStart, TgMessage{Text: "/start"}.Command())
+	a.Equal(BotCommandStart, TgMessage{Text: "/start abc"}.Command())
+	a.Equal(BotCommandStart, TgMessage{Text: "/start@reminder_bot abc"}.Command())
+	a.Equal(BotCommandBroadcast, TgMessage{Text: "/broadcast\nabc"}.Command())
+	a.Empty(TgMessage{Text: "start"}.Command())
+}
+
+func TestTgMessage_CommandArgs(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+	a.Empty(TgMessage{Text: "/start"}.CommandArgs())
+	a.Equal("abc", TgMessage{Text: "/start abc"}.CommandArgs())
+	a.Equal("abc def", TgMessage{Text: "/start@reminder_bot  abc def"}.CommandArgs())
+	a.Equal("abc\ndef", TgMessage{Text: "/broadcast\nabc\ndef"}.CommandArgs())
+	a.Empty(TgMessage{Text: "start abc"}.CommandArgs())
+}
+
+func TestTgMessage_IsPrivate(t *testing.T) {
+	t.Parallel()
+	assert.True(t, TgMessage{ChatID: 42, UserID: 42}.IsPrivate())
+	assert.False(t, TgMessage{ChatID: -42, UserID: 42}.IsPrivate())
+}
+
 func TestTgMessage_RemindAt(t *testing.T) {
 	t.Parallel()
 
@@ -76,6 +102,12 @@ func TestTgMessage_RemindAt(t *testing.T) {
 			msg:    TgMessage{Text: "30.01.2024 в 11:00"},
 			expRes: time.Date(2024, 1, 30, 11, 0, 0, 0, locationMSK),
 		},
+		{
+			name:   "success: relative Russian date (in 30 days)",
+			now:    time.Date(2024, 8, 18, 10, 0, 0, 0, locationMSK),
+			msg:    TgMessage{Text: "через 30 дней"},
+			expRes: time.Date(2024, 9, 17, 10, 0, 0, 0, locationMSK),
+		},
 		{
 			name:   "success: relative Russian date (in 1 month)",
 			now:    time.Date(2024, 8, 18, 0, 0, 0, 0, locationMSK),
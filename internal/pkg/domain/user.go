@@ -22,8 +22,20 @@ const (
 	UserStatusActive UserStatus = "active"
 	// UserStatusInactive  - user is not active and is not able to receive reminders.
 	UserStatusInactive UserStatus = "inactive"
+	// UserStatusBlocked - user is blocked by admin and is not able to work with the bot.
+	UserStatusBlocked UserStatus = "blocked"
 )
 
+// ParseUserStatus parses user status.
+func ParseUserStatus(s string) (UserStatus, error) {
+	switch status := UserStatus(s); status {
+	case UserStatusActive, UserStatusInactive, UserStatusBlocked:
+		return status, nil
+	default:
+		return "", fmt.Errorf("unknown user status %q", s)
+	}
+}
+
 // String implements [fmt.Stringer].
 func (u User) String() string {
 	return fmt.Sprintf("[ID: %d, Name: %s, Status: %s]", u.ID, u.Name, u.Status)
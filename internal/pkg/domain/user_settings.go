@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"time"
+)
+
+// UserSettings - user's personal settings. Empty quick options mean defaults.
+// Channels are the default channels of user's reminders, addresses of channels other than Telegram are set by user.
+// Email is used only after user verifies it with the code sent to it, see [UserSettings.VerifyEmail].
+// Webhook is used only with secret to sign its requests, the secret is shown to user when webhook URL is set.
+type UserSettings struct {
+	UserID        int64        `db:"user_id"`
+	QuickOptions  QuickOptions `db:"quick_options"`
+	SnoozeOptions QuickOptions `db:"snooze_options"`
+	Channels      Channels     `db:"channels"`
+	Email         string       `db:"email"`
+	EmailVerified bool         `db:"email_verified"`
+	// code sent to unverified email, empty if it isn't sent or is already used
+	EmailCode  string `db:"email_code"`
+	WebhookURL string `db:"webhook_url"`
+	// secret to sign webhook requests, empty if it isn't generated for webhook URL yet
+	WebhookSecret string    `db:"webhook_secret"`
+	PushTopic     string    `db:"push_topic"`
+	CreatedAt     time.Time `db:"created_at"`
+	ModifiedAt    time.Time `db:"modified_at"`
+}
+
+func (s UserSettings) String() string {
+	return fmt.Sprintf("[UserID: %d, QuickOptions: %s, SnoozeOptions: %s, Channels: %s]", s.UserID, s.QuickOptions, s.SnoozeOptions, s.Channels)
+}
+
+// ReminderQuickOptions returns options of buttons to create reminder, defaults if user hasn't set them.
+func (s UserSettings) ReminderQuickOptions() QuickOptions {
+	return s.QuickOptions.Or(DefaultQuickOptions)
+}
+
+// ReminderSnoozeOptions returns options of buttons to snooze reminder, defaults if user hasn't set them.
+func (s UserSettings) ReminderSnoozeOptions() QuickOptions {
+	return s.SnoozeOptions.Or(DefaultSnoozeOptions)
+}
+
+// DefaultChannels returns channels of reminders which have no own channels, Telegram if user hasn't set them.
+func (s UserSettings) DefaultChannels() Channels {
+	return s.Channels.Or(Channels{ChannelTelegram})
+}
+
+// ReminderChannels returns channels to deliver reminder by: reminder's own channels or user's defaults.
+func (s UserSettings) ReminderChannels(r Reminder) Channels {
+	return r.Channels.Or(s.DefaultChannels())
+}
+
+// ChannelAddress returns user's address of channel, empty if it isn't set, email isn't verified
+// or webhook has no secret. Telegram needs no address.
+func (s UserSettings) ChannelAddress(ch Channel) string {
+	switch ch {
+	case ChannelEmail:
+		if !s.EmailVerified {
+			return ""
+		}
+		return s.Email
+	case ChannelWebhook:
+		if s.WebhookSecret == "" {
+			return ""
+		}
+		return s.WebhookURL
+	case ChannelPush:
+		return s.PushTopic
+	default:
+		return ""
+	}
+}
+
+// pushTopicRe - allowed push topic names, the same as ntfy allows.
+var pushTopicRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// SetChannelAddress validates and sets user's address of channel, empty address removes it.
+// New email isn't verified until user sends back the code sent to it, new webhook URL needs new secret.
+func (s *UserSettings) SetChannelAddress(ch Channel, address string) error {
+	switch ch {
+	case ChannelEmail:
+		if address != "" {
+			addr, err := mail.ParseAddress(address)
+			if err != nil {
+				return fmt.Errorf("invalid email address %q: %w", address, err)
+			}
+			address = addr.Address
+		}
+		if address != s.Email {
+			s.EmailVerified, s.EmailCode = false, ""
+		}
+		s.Email = address
+	case ChannelWebhook:
+		if address != "" {
+			if err := ValidateWebhookURL(address); err != nil {
+				return err
+			}
+		}
+		if address != s.WebhookURL {
+			s.WebhookSecret = ""
+		}
+		s.WebhookURL = address
+	case ChannelPush:
+		if address != "" && !pushTopicRe.MatchString(address) {
+			return fmt.Errorf("invalid push topic %q: only letters, digits, '-' and '_' are allowed", address)
+		}
+		s.PushTopic = address
+	default:
+		return errors.New("channel has no address")
+	}
+
+	return nil
+}
+
+// ErrEmailCodeInvalid - code doesn't match the code sent to user's email or no code was sent.
+var ErrEmailCodeInvalid = errors.New("email verification code is invalid")
+
+// VerifyEmail marks user's email verified if code matches the code sent to it.
+// Code can be used once, it's reset after a wrong attempt too, so it can't be guessed.
+func (s *UserSettings) VerifyEmail(code string) error {
+	expected := s.EmailCode
+	s.EmailCode = ""
+
+	if expected == "" || s.Email == "" || subtle.ConstantTimeCompare([]byte(code), []byte(expected)) != 1 {
+		return ErrEmailCodeInvalid
+	}
+
+	s.EmailVerified = true
+
+	return nil
+}
+
+// NewEmailVerification returns notification with code to verify email of user.
+func NewEmailVerification(userID int64, to, code string) Notification {
+	return Notification{
+		To:     to,
+		UserID: userID,
+		ChatID: userID,
+		Title:  "Подтверждение адреса",
+		Message: fmt.Sprintf("Код подтверждения: %[1]s\n\nЧтобы получать напоминания на этот адрес, отправьте боту команду "+
+			"/settings verify %[1]s\n\nЕсли вы не указывали этот адрес, просто проигнорируйте письмо.", code),
+	}
+}
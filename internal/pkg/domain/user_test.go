@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMoscowTime(t *testing.T) {
@@ -24,4 +25,18 @@ func TestUser_String(t *testing.T) {
 
 	assert.EqualValues(t, "active", UserStatusActive)
 	assert.EqualValues(t, "inactive", UserStatusInactive)
+	assert.EqualValues(t, "blocked", UserStatusBlocked)
+}
+
+func TestParseUserStatus(t *testing.T) {
+	t.Parallel()
+
+	for _, exp := range []UserStatus{UserStatusActive, UserStatusInactive, UserStatusBlocked} {
+		act, err := ParseUserStatus(string(exp))
+		require.NoError(t, err)
+		assert.Equal(t, exp, act)
+	}
+
+	_, err := ParseUserStatus("deleted")
+	require.EqualError(t, err, `unknown user status "deleted"`)
 }
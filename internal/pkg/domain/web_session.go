@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// WebLoginTTL - lifetime of one-time login link to the web dashboard sent by /web command.
+	WebLoginTTL = 15 * time.Minute
+	// WebSessionTTL - lifetime of browser session in the web dashboard.
+	WebSessionTTL = 30 * 24 * time.Hour
+)
+
+// WebSessionKind - kind of web session.
+type WebSessionKind string
+
+const (
+	// WebSessionKindLogin - one-time login link, it's exchanged to [WebSessionKindBrowser] session on first use.
+	WebSessionKindLogin WebSessionKind = "login"
+	// WebSessionKindBrowser - session of browser, its token is stored in cookie.
+	WebSessionKindBrowser WebSessionKind = "browser"
+)
+
+// WebSession - session of user in the web dashboard, it gives access to reminders of user in chat.
+// Only hash of token is stored, see [HashAPIToken].
+type WebSession struct {
+	TokenHash string         `db:"token_hash"`
+	Kind      WebSessionKind `db:"kind"`
+	UserID    int64          `db:"user_id"`
+	ChatID    int64          `db:"chat_id"`
+	ExpiresAt time.Time      `db:"expires_at"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+// String implements [fmt.Stringer], token hash is not printed.
+func (s WebSession) String() string {
+	return fmt.Sprintf("[Kind: %s, UserID: %d, ChatID: %d, ExpiresAt: %s]", s.Kind, s.UserID, s.ChatID, s.ExpiresAt.Format(time.RFC3339))
+}
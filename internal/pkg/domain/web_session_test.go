@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebSession_String(t *testing.T) {
+	t.Parallel()
+
+	session := WebSession{
+		TokenHash: HashAPIToken("token"),
+		Kind:      WebSessionKindLogin,
+		UserID:    1,
+		ChatID:    2,
+		ExpiresAt: time.Date(2024, time.May, 1, 10, 15, 0, 0, time.UTC),
+	}
+	assert.Equal(t, "[Kind: login, UserID: 1, ChatID: 2, ExpiresAt: 2024-05-01T10:15:00Z]", session.String())
+}
@@ -0,0 +1,361 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookEvent - reminder lifecycle event delivered to webhooks.
+type WebhookEvent string
+
+const (
+	// WebhookEventCreated - reminder was created.
+	WebhookEventCreated WebhookEvent = "reminder.created"
+	// WebhookEventFired - reminder was sent to user.
+	WebhookEventFired WebhookEvent = "reminder.fired"
+	// WebhookEventDelayed - reminder was delayed.
+	WebhookEventDelayed WebhookEvent = "reminder.delayed"
+	// WebhookEventCompleted - reminder was marked as done.
+	WebhookEventCompleted WebhookEvent = "reminder.completed"
+	// WebhookEventExhausted - all attempts to receive 'done' from user are finished.
+	WebhookEventExhausted WebhookEvent = "reminder.exhausted"
+)
+
+// AllWebhookEvents - all events webhooks can subscribe to.
+var AllWebhookEvents = WebhookEvents{WebhookEventCreated, WebhookEventFired, WebhookEventDelayed, WebhookEventCompleted, WebhookEventExhausted}
+
+// WebhookEventByReminderEvent returns webhook event of reminder event. Not every reminder event is delivered to webhooks.
+func WebhookEventByReminderEvent(eventType ReminderEventType) (WebhookEvent, bool) {
+	switch eventType {
+	case ReminderEventCreated:
+		return WebhookEventCreated, true
+	case ReminderEventNotified:
+		return WebhookEventFired, true
+	case ReminderEventDelayed:
+		return WebhookEventDelayed, true
+	case ReminderEventDone:
+		return WebhookEventCompleted, true
+	case ReminderEventExhausted:
+		return WebhookEventExhausted, true
+	default:
+		return "", false
+	}
+}
+
+// ParseWebhookEvent parses webhook event, the "reminder." prefix can be omitted, e.g. "completed".
+func ParseWebhookEvent(s string) (WebhookEvent, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if !strings.HasPrefix(name, "reminder.") {
+		name = "reminder." + name
+	}
+
+	event := WebhookEvent(name)
+	if !slices.Contains(AllWebhookEvents, event) {
+		return "", fmt.Errorf("unknown webhook event %q", s)
+	}
+
+	return event, nil
+}
+
+// WebhookEvents - set of webhook events.
+type WebhookEvents []WebhookEvent
+
+// ParseWebhookEvents parses comma or whitespace separated events, duplicates are skipped.
+func ParseWebhookEvents(s string) (WebhookEvents, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n'
+	})
+
+	if len(fields) == 0 {
+		return nil, errors.New("no webhook events")
+	}
+
+	events := make(WebhookEvents, 0, len(fields))
+	for _, field := range fields {
+		event, err := ParseWebhookEvent(field)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// String implements [fmt.Stringer].
+func (e WebhookEvents) String() string {
+	fields := make([]string, 0, len(e))
+	for _, event := range e {
+		fields = append(fields, string(event))
+	}
+
+	return strings.Join(fields, ",")
+}
+
+// Scan implements [sql.Scanner]. Events are stored as comma separated values.
+func (e *WebhookEvents) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case nil:
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("unsupported webhook events type %T", value)
+	}
+
+	*e = nil
+	if s == "" {
+		return nil
+	}
+
+	for _, field := range strings.Split(s, ",") {
+		*e = append(*e, WebhookEvent(field))
+	}
+
+	return nil
+}
+
+// Value implements [driver.Valuer].
+func (e WebhookEvents) Value() (driver.Value, error) {
+	return e.String(), nil
+}
+
+// WebhookSecretPrefix - prefix of webhook secrets, it makes leaked secrets easy to find.
+const WebhookSecretPrefix = "whsec_"
+
+// NewWebhookSecret returns new random secret to sign webhook requests with, see [SignWebhookPayload].
+func NewWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return WebhookSecretPrefix + hex.EncodeToString(b), nil
+}
+
+// Webhook - URL which receives signed reminder lifecycle events.
+// Webhook of user receives events of user's reminders, webhook without user is registered by operator
+// and receives events of all reminders.
+type Webhook struct {
+	ID     int64  `db:"id"`
+	UserID int64  `db:"user_id"` // 0 for webhook of all users
+	URL    string `db:"url"`
+	// key of HMAC signature of payloads, it's stored as is because payloads are signed with it
+	Secret string `db:"secret"`
+	// events webhook is subscribed to, all events if empty
+	Events     WebhookEvents `db:"events"`
+	CreatedAt  time.Time     `db:"created_at"`
+	ModifiedAt time.Time     `db:"modified_at"`
+}
+
+// String implements [fmt.Stringer], secret is not printed.
+func (w Webhook) String() string {
+	return fmt.Sprintf("[ID: %d, UserID: %d, URL: %s, Events: %s]", w.ID, w.UserID, w.URL, w.Events)
+}
+
+// SubscribedTo reports whether webhook receives event.
+func (w Webhook) SubscribedTo(event WebhookEvent) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, event)
+}
+
+// FormatEvents formats events webhook is subscribed to.
+func (w Webhook) FormatEvents() string {
+	if len(w.Events) == 0 {
+		return "все события"
+	}
+
+	return w.Events.String()
+}
+
+// ValidateWebhookURL returns error if address is not an absolute http or https URL or its host is an address
+// of the bot's own network, e.g. localhost or private IP, see [IsPublicAddr]. Host names are checked again after
+// DNS resolution when webhook is called.
+func ValidateWebhookURL(address string) error {
+	u, err := url.Parse(address)
+	if err != nil {
+		return fmt.Errorf("invalid webhook url %q: %w", address, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook url %q: absolute http or https url is expected", address)
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("invalid webhook url %q: local address is not allowed", address)
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && !IsPublicAddr(ip) {
+		return fmt.Errorf("invalid webhook url %q: local address is not allowed", address)
+	}
+
+	return nil
+}
+
+// sharedAddressSpace - carrier-grade NAT addresses, they are not routed in the internet like private ones.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// IsPublicAddr reports whether ip is an internet address webhooks are allowed to be sent to.
+// Loopback, private, link-local, multicast and unspecified addresses are not public.
+func IsPublicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+
+	return ip.IsValid() &&
+		!ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// SignWebhookPayload returns hex encoded HMAC-SHA256 signature of payload sent at timestamp.
+// Timestamp is signed too, so receiver can reject replayed payloads.
+func SignWebhookPayload(secret string, timestamp time.Time, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookDeliveryStatus - status of webhook delivery.
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryStatusPending - delivery is waiting for the next attempt.
+	WebhookDeliveryStatusPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryStatusDelivered - webhook responded with 2xx status.
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryStatusDead - delivery failed within max attempts and won't be retried anymore.
+	WebhookDeliveryStatusDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery - event sent to webhook. Deliveries are kept after they're finished as a delivery log.
+type WebhookDelivery struct {
+	ID            int64                 `db:"id"`
+	WebhookID     int64                 `db:"webhook_id"`
+	Event         WebhookEvent          `db:"event"`
+	Payload       string                `db:"payload"` // JSON encoded [WebhookPayload]
+	Status        WebhookDeliveryStatus `db:"status"`
+	Attempts      int                   `db:"attempts"`
+	NextAttemptAt time.Time             `db:"next_attempt_at"`
+	// HTTP status of the last response, 0 if no response was received
+	ResponseStatus int       `db:"response_status"`
+	LastError      string    `db:"last_error"`
+	CorrelationID  string    `db:"correlation_id"`
+	CreatedAt      time.Time `db:"created_at"`
+	ModifiedAt     time.Time `db:"modified_at"`
+	// webhook is joined to due deliveries only
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
+
+// Format - format delivery to send to user as an entry of delivery log.
+func (d WebhookDelivery) Format() string {
+	var sb strings.Builder
+	sb.WriteString(formatEventTime(d.CreatedAt))
+	sb.WriteString(" — ")
+	sb.WriteString(string(d.Event))
+	sb.WriteString(" — ")
+
+	switch {
+	case d.Status == WebhookDeliveryStatusDelivered:
+		sb.WriteString(fmt.Sprintf("доставлено, ответ %d", d.ResponseStatus))
+	case d.Status == WebhookDeliveryStatusPending && d.Attempts == 0:
+		sb.WriteString("ожидает отправки")
+	case d.Status == WebhookDeliveryStatusPending:
+		sb.WriteString(fmt.Sprintf("ошибка, попыток %d, повтор в %s", d.Attempts, formatEventTime(d.NextAttemptAt)))
+	default:
+		sb.WriteString(fmt.Sprintf("не доставлено, попыток %d", d.Attempts))
+	}
+
+	if d.Status != WebhookDeliveryStatusDelivered && d.LastError != "" {
+		sb.WriteString(": `")
+		sb.WriteString(strings.ReplaceAll(d.LastError, "`", "'"))
+		sb.WriteString("`")
+	}
+
+	return sb.String()
+}
+
+func (d WebhookDelivery) String() string {
+	return fmt.Sprintf("[ID: %d, WebhookID: %d, Event: %s, Status: %s, Attempts: %d, NextAttemptAt: %s]", d.ID, d.WebhookID, d.Event, d.Status, d.Attempts, d.NextAttemptAt)
+}
+
+// WebhookPayload - body of webhook request.
+type WebhookPayload struct {
+	// id of reminder event, the same event can be delivered more than once, so receiver can skip duplicates by it
+	EventID    int64        `json:"event_id"`
+	Event      WebhookEvent `json:"event"`
+	OccurredAt time.Time    `json:"occurred_at"`
+	Actor      ActorType    `json:"actor"`
+	ActorID    int64        `json:"actor_id,omitempty"`
+	// attempt number for fired event
+	Attempt int `json:"attempt,omitempty"`
+	// remind time before the transition for delayed event
+	RemindAtFrom *time.Time             `json:"remind_at_from,omitempty"`
+	Reminder     WebhookPayloadReminder `json:"reminder"`
+}
+
+// WebhookPayloadReminder - reminder as it was after the event.
+type WebhookPayloadReminder struct {
+	ID        int64                         `json:"id"`
+	UserID    int64                         `json:"user_id"`
+	ChatID    int64                         `json:"chat_id"`
+	Text      string                        `json:"text"`
+	RemindAt  time.Time                     `json:"remind_at"`
+	Status    ReminderStatus                `json:"status"`
+	Priority  ReminderPriority              `json:"priority"`
+	Checklist []WebhookPayloadChecklistItem `json:"checklist"`
+}
+
+// WebhookPayloadChecklistItem - checklist item of reminder.
+type WebhookPayloadChecklistItem struct {
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+// NewWebhookPayload returns payload of reminder event e, r is the reminder after the event.
+func NewWebhookPayload(event WebhookEvent, e ReminderEvent, r Reminder) WebhookPayload {
+	p := WebhookPayload{
+		EventID:      e.ID,
+		Event:        event,
+		OccurredAt:   e.CreatedAt.UTC(),
+		Actor:        e.ActorType,
+		ActorID:      e.ActorID,
+		Attempt:      e.Attempt,
+		RemindAtFrom: e.RemindAtFrom,
+		Reminder: WebhookPayloadReminder{
+			ID:        r.ID,
+			UserID:    r.UserID,
+			ChatID:    r.ChatID,
+			Text:      r.Text,
+			RemindAt:  r.RemindAt.UTC(),
+			Status:    r.Status,
+			Priority:  r.Priority,
+			Checklist: make([]WebhookPayloadChecklistItem, 0, len(r.Checklist)),
+		},
+	}
+	for _, item := range r.Checklist {
+		p.Reminder.Checklist = append(p.Reminder.Checklist, WebhookPayloadChecklistItem{Text: item.Text, Done: item.Done})
+	}
+
+	return p
+}
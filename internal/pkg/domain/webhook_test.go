@@ -0,0 +1,215 @@
+package domain
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWebhookEvents(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		input  string
+		expRes WebhookEvents
+		expErr string
+	}{
+		{name: "one", input: "reminder.completed", expRes: WebhookEvents{WebhookEventCompleted}},
+		{
+			name:   "many without prefix, duplicates are skipped",
+			input:  "Created, fired reminder.created,exhausted",
+			expRes: WebhookEvents{WebhookEventCreated, WebhookEventFired, WebhookEventExhausted},
+		},
+		{name: "empty", input: " , ", expErr: "no webhook events"},
+		{name: "unknown", input: "created, removed", expErr: `unknown webhook event "removed"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := ParseWebhookEvents(tc.input)
+			if tc.expErr != "" {
+				require.EqualError(t, err, tc.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expRes, res)
+		})
+	}
+}
+
+func TestWebhook_SubscribedTo(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, Webhook{}.SubscribedTo(WebhookEventFired))
+	assert.True(t, Webhook{Events: WebhookEvents{WebhookEventFired, WebhookEventCompleted}}.SubscribedTo(WebhookEventCompleted))
+	assert.False(t, Webhook{Events: WebhookEvents{WebhookEventFired}}.SubscribedTo(WebhookEventCreated))
+}
+
+func TestWebhookEventByReminderEvent(t *testing.T) {
+	t.Parallel()
+
+	event, ok := WebhookEventByReminderEvent(ReminderEventDone)
+	assert.True(t, ok)
+	assert.Equal(t, WebhookEventCompleted, event)
+
+	_, ok = WebhookEventByReminderEvent(ReminderEventRemoved)
+	assert.False(t, ok)
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		address string
+		expErr  bool
+	}{
+		{address: "https://example.com/hook"},
+		{address: "http://8.8.8.8:8080/hook"},
+		{address: "example.com/hook", expErr: true},
+		{address: "http://localhost/hook", expErr: true},
+		{address: "http://api.localhost./hook", expErr: true},
+		{address: "http://127.0.0.1:8080/hook", expErr: true},
+		{address: "http://10.0.0.1/hook", expErr: true},
+		{address: "http://169.254.169.254/latest/meta-data", expErr: true},
+		{address: "http://[::1]/hook", expErr: true},
+		{address: "http://[::ffff:127.0.0.1]/hook", expErr: true},
+		{address: "http://0.0.0.0/hook", expErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.address, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateWebhookURL(tc.address)
+			if tc.expErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsPublicAddr(netip.MustParseAddr("93.184.216.34")))
+	assert.True(t, IsPublicAddr(netip.MustParseAddr("2606:2800:220:1:248:1893:25c8:1946")))
+	assert.False(t, IsPublicAddr(netip.MustParseAddr("172.16.5.4")))
+	assert.False(t, IsPublicAddr(netip.MustParseAddr("100.64.0.1")))
+	assert.False(t, IsPublicAddr(netip.MustParseAddr("fe80::1")))
+	assert.False(t, IsPublicAddr(netip.MustParseAddr("fd00::1")))
+	assert.False(t, IsPublicAddr(netip.Addr{}))
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	t.Parallel()
+
+	// echo -n '1714575600.{"event":"reminder.fired"}' | openssl dgst -sha256 -hmac whsec_test
+	signature := SignWebhookPayload("whsec_test", time.Unix(1714575600, 0), []byte(`{"event":"reminder.fired"}`))
+	assert.Equal(t, "a76cca0699da47a71dda22df2480626992bc7b7e4e2b02433ad462da11a3ef1e", signature)
+}
+
+func TestNewWebhookSecret(t *testing.T) {
+	t.Parallel()
+
+	secret, err := NewWebhookSecret()
+	require.NoError(t, err)
+	assert.Regexp(t, `^whsec_[0-9a-f]{48}$`, secret)
+
+	other, err := NewWebhookSecret()
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+}
+
+func TestNewWebhookPayload(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2024, 5, 1, 15, 0, 0, 0, time.UTC)
+	remindAt := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	remindAtFrom := time.Date(2024, 5, 1, 16, 0, 0, 0, time.UTC)
+
+	payload := NewWebhookPayload(
+		WebhookEventDelayed,
+		ReminderEvent{ID: 7, ReminderID: 42, Type: ReminderEventDelayed, ActorType: ActorUser, ActorID: 1, RemindAtFrom: &remindAtFrom, CreatedAt: createdAt},
+		Reminder{
+			ID:        42,
+			UserID:    1,
+			ChatID:    2,
+			Text:      "deploy",
+			RemindAt:  remindAt,
+			Status:    ReminderStatusPending,
+			Priority:  ReminderPriorityHigh,
+			Checklist: Checklist{{Text: "build", Done: true}, {Text: "release"}},
+		},
+	)
+
+	assert.Equal(t, WebhookPayload{
+		EventID:      7,
+		Event:        WebhookEventDelayed,
+		OccurredAt:   createdAt,
+		Actor:        ActorUser,
+		ActorID:      1,
+		RemindAtFrom: &remindAtFrom,
+		Reminder: WebhookPayloadReminder{
+			ID:        42,
+			UserID:    1,
+			ChatID:    2,
+			Text:      "deploy",
+			RemindAt:  remindAt,
+			Status:    ReminderStatusPending,
+			Priority:  ReminderPriorityHigh,
+			Checklist: []WebhookPayloadChecklistItem{{Text: "build", Done: true}, {Text: "release"}},
+		},
+	}, payload)
+}
+
+func TestWebhookDelivery_Format(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2024, 5, 1, 15, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		delivery WebhookDelivery
+		exp      string
+	}{
+		{
+			name:     "delivered",
+			delivery: WebhookDelivery{Event: WebhookEventCompleted, Status: WebhookDeliveryStatusDelivered, Attempts: 1, ResponseStatus: 204, CreatedAt: createdAt},
+			exp:      "1 мая 18:00 — reminder.completed — доставлено, ответ 204",
+		},
+		{
+			name:     "waits for the first attempt",
+			delivery: WebhookDelivery{Event: WebhookEventCreated, Status: WebhookDeliveryStatusPending, CreatedAt: createdAt},
+			exp:      "1 мая 18:00 — reminder.created — ожидает отправки",
+		},
+		{
+			name: "waits for retry",
+			delivery: WebhookDelivery{
+				Event: WebhookEventFired, Status: WebhookDeliveryStatusPending, Attempts: 2, CreatedAt: createdAt,
+				NextAttemptAt: createdAt.Add(10 * time.Second), LastError: "unexpected response status 500: `oops`",
+			},
+			exp: "1 мая 18:00 — reminder.fired — ошибка, попыток 2, повтор в 1 мая 18:00: `unexpected response status 500: 'oops'`",
+		},
+		{
+			name:     "dead",
+			delivery: WebhookDelivery{Event: WebhookEventExhausted, Status: WebhookDeliveryStatusDead, Attempts: 10, CreatedAt: createdAt, LastError: "connection refused"},
+			exp:      "1 мая 18:00 — reminder.exhausted — не доставлено, попыток 10: `connection refused`",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.exp, tc.delivery.Format())
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/go-pkgz/lgr"
+)
+
+// component - long-running part of the application.
+type component struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// closer - resource which is closed after all components are stopped.
+type closer struct {
+	name  string
+	close func() error
+}
+
+// Manager starts components and stops them in order they were added.
+// Every component is stopped by cancellation of its own context and is awaited to finish work in progress.
+type Manager struct {
+	shutdownTimeout time.Duration
+	components      []component
+	closers         []closer
+}
+
+// New creates [Manager]. All components must stop and resources must be closed within shutdownTimeout.
+func New(shutdownTimeout time.Duration) *Manager {
+	return &Manager{shutdownTimeout: shutdownTimeout}
+}
+
+// Add adds component. Run must block until ctx is cancelled and must return after work in progress is done.
+func (m *Manager) Add(name string, run func(ctx context.Context) error) {
+	m.components = append(m.components, component{name: name, run: run})
+}
+
+// AddCloser adds resource which is closed after all components are stopped, e.g. database.
+func (m *Manager) AddCloser(name string, close func() error) {
+	m.closers = append(m.closers, closer{name: name, close: close})
+}
+
+// Run starts all components and blocks until ctx is cancelled or any component stops.
+// Then components are stopped one by one in order they were added, and resources are closed.
+// Returns nil on graceful shutdown, otherwise returns errors of failed component and of shutdown.
+func (m *Manager) Run(ctx context.Context) error {
+	type result struct {
+		idx int
+		err error
+	}
+
+	var (
+		cancels = make([]context.CancelFunc, len(m.components))
+		dones   = make([]chan error, len(m.components))
+		stopped = make(chan result, len(m.components))
+	)
+
+	for i, c := range m.components {
+		var cctx context.Context
+		cctx, cancels[i] = context.WithCancel(context.Background())
+		dones[i] = make(chan error, 1)
+
+		go func(i int, c component) {
+			err := c.run(cctx)
+			dones[i] <- err
+			stopped <- result{idx: i, err: err}
+		}(i, c)
+
+		log.Printf("[INFO] lifecycle: %s started", c.name)
+	}
+
+	var errs []error
+
+	select {
+	case <-ctx.Done():
+		log.Printf("[INFO] lifecycle: shutting down, timeout %s", m.shutdownTimeout)
+	case res := <-stopped:
+		name := m.components[res.idx].name
+		// component stopped on its own, the others can't work without it
+		log.Printf("[WARN] lifecycle: %s stopped unexpectedly, shutting down: %v", name, res.err)
+		errs = append(errs, fmt.Errorf("%s stopped: %w", name, res.err))
+	}
+
+	deadline := time.NewTimer(m.shutdownTimeout)
+	defer deadline.Stop()
+
+	for i, c := range m.components {
+		cancels[i]()
+
+		select {
+		case err := <-dones[i]:
+			if err != nil && !errors.Is(err, context.Canceled) && !isReported(errs, err) {
+				errs = append(errs, fmt.Errorf("%s stopped: %w", c.name, err))
+			}
+			log.Printf("[INFO] lifecycle: %s stopped", c.name)
+		case <-deadline.C:
+			return errors.Join(append(errs, fmt.Errorf("%s didn't stop within shutdown timeout %s", c.name, m.shutdownTimeout))...)
+		}
+	}
+
+	for _, c := range m.closers {
+		if err := c.close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close %s: %w", c.name, err))
+			continue
+		}
+		log.Printf("[INFO] lifecycle: %s closed", c.name)
+	}
+
+	return errors.Join(errs...)
+}
+
+// isReported checks whether error of component, which stopped unexpectedly, is already reported.
+func isReported(errs []error, err error) bool {
+	for _, e := range errs {
+		if errors.Is(e, err) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,138 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder records order of stop and close events.
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+// blocking returns component which blocks until ctx is cancelled, then finishes work in progress within workTime.
+func (r *recorder) blocking(name string, workTime time.Duration) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(workTime)
+		r.add(name + " stopped")
+		return ctx.Err()
+	}
+}
+
+func (r *recorder) closer(name string, err error) func() error {
+	return func() error {
+		r.add(name + " closed")
+		return err
+	}
+}
+
+func TestManager_Run(t *testing.T) {
+	t.Parallel()
+
+	errFailed := errors.New("failed")
+
+	testCases := []struct {
+		name      string
+		timeout   time.Duration
+		setup     func(m *Manager, r *recorder)
+		expEvents []string
+		expErr    string
+	}{
+		{
+			name:    "success: components are stopped in order, then resources are closed",
+			timeout: time.Second,
+			setup: func(m *Manager, r *recorder) {
+				m.Add("listener", r.blocking("listener", 50*time.Millisecond))
+				m.Add("notifier", r.blocking("notifier", 0))
+				m.Add("backuper", r.blocking("backuper", 20*time.Millisecond))
+				m.AddCloser("database", r.closer("database", nil))
+			},
+			expEvents: []string{"listener stopped", "notifier stopped", "backuper stopped", "database closed"},
+		},
+		{
+			name:    "error: component stopped unexpectedly, the others are stopped",
+			timeout: time.Second,
+			setup: func(m *Manager, r *recorder) {
+				m.Add("listener", func(context.Context) error {
+					time.Sleep(10 * time.Millisecond)
+					r.add("listener stopped")
+					return errFailed
+				})
+				m.Add("notifier", r.blocking("notifier", 0))
+				m.AddCloser("database", r.closer("database", nil))
+			},
+			expEvents: []string{"listener stopped", "notifier stopped", "database closed"},
+			expErr:    "listener stopped: failed",
+		},
+		{
+			name:    "error: component didn't stop within shutdown timeout, resources are not closed",
+			timeout: 50 * time.Millisecond,
+			setup: func(m *Manager, r *recorder) {
+				m.Add("listener", r.blocking("listener", 0))
+				m.Add("notifier", r.blocking("notifier", time.Second))
+				m.AddCloser("database", r.closer("database", nil))
+			},
+			expEvents: []string{"listener stopped"},
+			expErr:    "notifier didn't stop within shutdown timeout 50ms",
+		},
+		{
+			name:    "error: component failed on shutdown, resource failed to close",
+			timeout: time.Second,
+			setup: func(m *Manager, r *recorder) {
+				m.Add("listener", func(ctx context.Context) error {
+					<-ctx.Done()
+					r.add("listener stopped")
+					return errFailed
+				})
+				m.AddCloser("database", r.closer("database", errFailed))
+			},
+			expEvents: []string{"listener stopped", "database closed"},
+			expErr:    "listener stopped: failed\nfailed to close database: failed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &recorder{}
+			m := New(tc.timeout)
+			tc.setup(m, r)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				cancel()
+			}()
+
+			err := m.Run(ctx)
+
+			if tc.expErr != "" {
+				assert.EqualError(t, err, tc.expErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.expEvents, r.get())
+		})
+	}
+}
@@ -10,11 +10,16 @@ import (
 	log "github.com/go-pkgz/lgr"
 	tbapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+	"github.com/mezk/tg-reminder/internal/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // BotAPI - subset of Telegram bot API methods.
 type BotAPI interface {
 	GetUpdatesChan(config tbapi.UpdateConfig) tbapi.UpdatesChannel
+	StopReceivingUpdates()
 }
 
 // UpdateReceiver - receiver of Telegram updates.
@@ -23,27 +28,75 @@ type UpdateReceiver interface {
 	OnCallbackQuery(ctx context.Context, callback domain.TgCallbackQuery) error
 }
 
+// Storage - storage of processed updates.
+type Storage interface {
+	GetUpdateOffset(ctx context.Context) (int, error)
+	SaveUpdateOffset(ctx context.Context, updateID int) error
+	MarkUpdateProcessed(ctx context.Context, updateID int) (bool, error)
+}
+
+// Config - listener configuration.
+type Config struct {
+	UpdatesTimeout time.Duration // long polling timeout of getUpdates, 0 means short polling
+	HandlerTimeout time.Duration // timeout to process one update
+	Workers        int           // number of updates processed concurrently, at least 1
+	QueueSize      int           // max number of updates waiting for every worker, at least 1
+}
+
 // Listener - listener which listens to updates from Telegram.
 type Listener struct {
 	botAPI         BotAPI
 	updateReceiver UpdateReceiver
+	store          Storage
+	cfg            Config
 }
 
 // New creates a new [Listener].
-func New(botAPI BotAPI, updateReceiver UpdateReceiver) *Listener {
-	return &Listener{botAPI: botAPI, updateReceiver: updateReceiver}
+func New(botAPI BotAPI, updateReceiver UpdateReceiver, store Storage, cfg Config) *Listener {
+	return &Listener{botAPI: botAPI, updateReceiver: updateReceiver, store: store, cfg: cfg}
 }
 
-// Listen - listens to Telegram updates in a loop.
-// Blocks until ctx.Err.
+// Listen - listens to Telegram updates in a loop and processes them by workers.
+// Updates of the same user in the same chat are processed in order they were received.
+// Listening is resumed from the last processed update, so updates aren't lost after restart.
+// Updates are handled at most once: offset is moved past update which failed to be handled, it isn't retried.
+// Blocks until ctx.Err. Updates being processed and queued are finished before return.
 func (l *Listener) Listen(ctx context.Context) error {
-	log.Printf("[INFO] start telegram updates Listener")
+	offset, err := l.store.GetUpdateOffset(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get update offset: %w", err)
+	}
+
+	log.Printf("[INFO] start telegram updates Listener, workers %d, queue size %d, last processed update %d",
+		max(l.cfg.Workers, 1), max(l.cfg.QueueSize, 1), offset)
 
 	cfg := tbapi.NewUpdate(0)
-	cfg.Timeout = 60
+	if offset > 0 {
+		cfg.Offset = offset + 1
+	}
+	cfg.Timeout = int(l.cfg.UpdatesTimeout.Seconds())
 
 	updates := l.botAPI.GetUpdatesChan(cfg)
 
+	tracker := newOffsetTracker()
+	// shutdown must not interrupt handlers in the middle, they're limited by handler timeout
+	workers := newPool(context.WithoutCancel(ctx), l.cfg.Workers, l.cfg.QueueSize, func(ctx context.Context, update tbapi.Update) {
+		updateCtx := updateContext(ctx, update)
+		if err := l.processUpdate(updateCtx, update); err != nil {
+			logging.Printf(updateCtx, "[WARN] failed to process update %d: %v", update.UpdateID, err)
+		}
+
+		if offset, moved := tracker.complete(update.UpdateID); moved {
+			if err := l.store.SaveUpdateOffset(ctx, offset); err != nil {
+				log.Printf("[WARN] failed to save update offset: %v", err)
+			}
+		}
+	})
+	defer func() {
+		l.botAPI.StopReceivingUpdates()
+		workers.stop()
+	}()
+
 	for {
 		select {
 
@@ -55,29 +108,59 @@ func (l *Listener) Listen(ctx context.Context) error {
 				return fmt.Errorf("telegram updates chan closed")
 			}
 
-			if err := l.processUpdate(ctx, update); err != nil {
-				log.Printf("[WARN] failed to process update: %v", err)
-				continue
-			}
+			tracker.add(update.UpdateID)
+			workers.dispatch(update)
 		}
 	}
 }
 
-func (l *Listener) processUpdate(ctx context.Context, update tbapi.Update) error {
+// updateContext returns ctx with new correlation id and user of update.
+// All logs written while the update is handled, including delivery of responses, share the correlation id.
+func updateContext(ctx context.Context, update tbapi.Update) context.Context {
+	ctx = logging.ContextWithCorrelationID(ctx, logging.NewCorrelationID())
+	if from, chat := update.SentFrom(), update.FromChat(); from != nil && chat != nil {
+		ctx = logging.ContextWithUser(ctx, from.ID, chat.ID)
+	}
+
+	return ctx
+}
+
+// processUpdate handles update, ctx must be created by [updateContext].
+func (l *Listener) processUpdate(ctx context.Context, update tbapi.Update) (err error) {
 	if update.Message == nil && update.CallbackQuery == nil {
 		return nil
 	}
 
+	fields := logging.FieldsFromContext(ctx)
+	ctx, span := tracing.Start(ctx, "listener.processUpdate", trace.WithAttributes(
+		attribute.Int("telegram.update_id", update.UpdateID),
+		attribute.Int64("telegram.user_id", fields.UserID),
+		attribute.Int64("telegram.chat_id", fields.ChatID),
+		attribute.String("correlation_id", fields.CorrelationID),
+	))
+	defer func() { tracing.End(span, err) }()
+
 	msgJSON, err := json.Marshal(update.Message)
 	if err != nil {
 		return fmt.Errorf("failed to marshal update.Message to json: %w", err)
 	}
 
-	log.Printf("[DEBUG] process update.Message: %s", string(msgJSON))
+	logging.Printf(ctx, "[DEBUG] process update %d, update.Message: %s", update.UpdateID, string(msgJSON))
 
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, l.cfg.HandlerTimeout)
 	defer cancel()
 
+	// update is marked before it's handled: if the bot crashes in the middle or handler fails, the update is lost
+	// instead of being handled twice, e.g. reminder isn't created twice
+	first, err := l.store.MarkUpdateProcessed(ctx, update.UpdateID)
+	if err != nil {
+		return err
+	}
+	if !first {
+		logging.Printf(ctx, "[DEBUG] update %d has been already processed, skip it", update.UpdateID)
+		return nil
+	}
+
 	switch {
 	case update.Message != nil && update.Message.Text != "":
 		message := transformMessage(update.Message)
@@ -122,8 +205,12 @@ func transformCallbackQuery(callback *tbapi.CallbackQuery) domain.TgCallbackQuer
 		res.UserID = callback.From.ID
 		res.UserName = callback.From.UserName
 
-		if callback.Message != nil && callback.Message.Chat != nil {
-			res.ChatID = callback.Message.Chat.ID
+		if callback.Message != nil {
+			res.MessageID = int64(callback.Message.MessageID)
+
+			if callback.Message.Chat != nil {
+				res.ChatID = callback.Message.Chat.ID
+			}
 		}
 
 		res.Data = strings.TrimSpace(callback.Data)
@@ -3,11 +3,16 @@ package listener
 import (
 	"context"
 	"errors"
+	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	tbapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,6 +23,7 @@ func Test_listener_Listen(t *testing.T) {
 		t.Parallel()
 
 		botAPIMock := BotAPIMock{
+			StopReceivingUpdatesFunc: func() {},
 			GetUpdatesChanFunc: func(config tbapi.UpdateConfig) tbapi.UpdatesChannel {
 				cfg := tbapi.NewUpdate(0)
 				cfg.Timeout = 60
@@ -41,18 +47,23 @@ func Test_listener_Listen(t *testing.T) {
 			},
 		}
 		updateReceiverMock := UpdateReceiverMock{
-			OnMessageFunc: func(_ context.Context, message domain.TgMessage) error {
+			OnMessageFunc: func(ctx context.Context, message domain.TgMessage) error {
 				assert.Equal(t, domain.TgMessage{
 					ChatID:   1,
 					UserID:   2,
 					UserName: "Nirav Martini",
 					Text:     "winds",
 				}, message)
+
+				fields := logging.FieldsFromContext(ctx)
+				assert.Len(t, fields.CorrelationID, 16)
+				assert.EqualValues(t, 2, fields.UserID)
+				assert.EqualValues(t, 1, fields.ChatID)
 				return nil
 			},
 		}
 
-		listenerImpl := New(&botAPIMock, &updateReceiverMock)
+		listenerImpl := New(&botAPIMock, &updateReceiverMock, newStorageMock(), Config{UpdatesTimeout: 60 * time.Second, HandlerTimeout: 5 * time.Second})
 
 		ctx, cancel := context.WithTimeout(context.TODO(), 300*time.Millisecond)
 		defer cancel()
@@ -64,6 +75,7 @@ func Test_listener_Listen(t *testing.T) {
 		t.Parallel()
 
 		botAPIMock := BotAPIMock{
+			StopReceivingUpdatesFunc: func() {},
 			GetUpdatesChanFunc: func(config tbapi.UpdateConfig) tbapi.UpdatesChannel {
 				cfg := tbapi.NewUpdate(0)
 				cfg.Timeout = 60
@@ -91,16 +103,17 @@ func Test_listener_Listen(t *testing.T) {
 		updateReceiverMock := UpdateReceiverMock{
 			OnCallbackQueryFunc: func(_ context.Context, callback domain.TgCallbackQuery) error {
 				assert.Equal(t, domain.TgCallbackQuery{
-					ChatID:   1,
-					UserID:   2,
-					UserName: "Nirav Martini",
-					Data:     "winds",
+					ChatID:    1,
+					UserID:    2,
+					UserName:  "Nirav Martini",
+					MessageID: 13246,
+					Data:      "winds",
 				}, callback)
 				return nil
 			},
 		}
 
-		listenerImpl := New(&botAPIMock, &updateReceiverMock)
+		listenerImpl := New(&botAPIMock, &updateReceiverMock, newStorageMock(), Config{UpdatesTimeout: 60 * time.Second, HandlerTimeout: 5 * time.Second})
 
 		ctx, cancel := context.WithTimeout(context.TODO(), 300*time.Millisecond)
 		defer cancel()
@@ -108,10 +121,46 @@ func Test_listener_Listen(t *testing.T) {
 		listenerImpl.Listen(ctx)
 	})
 
+	t.Run("success: update in progress is finished on shutdown", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+
+		botAPIMock := BotAPIMock{
+			StopReceivingUpdatesFunc: func() {},
+			GetUpdatesChanFunc: func(config tbapi.UpdateConfig) tbapi.UpdatesChannel {
+				ch := make(chan tbapi.Update, 1)
+				ch <- tbapi.Update{Message: &tbapi.Message{Text: "winds", Chat: &tbapi.Chat{ID: 1}}}
+				return ch
+			},
+		}
+
+		var handled bool
+		updateReceiverMock := UpdateReceiverMock{
+			OnMessageFunc: func(handlerCtx context.Context, _ domain.TgMessage) error {
+				cancel()
+				time.Sleep(50 * time.Millisecond)
+				assert.NoError(t, handlerCtx.Err())
+				handled = true
+				return nil
+			},
+		}
+
+		listenerImpl := New(&botAPIMock, &updateReceiverMock, newStorageMock(), Config{UpdatesTimeout: 60 * time.Second, HandlerTimeout: 5 * time.Second})
+
+		err := listenerImpl.Listen(ctx)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.True(t, handled)
+		assert.Len(t, botAPIMock.StopReceivingUpdatesCalls(), 1)
+	})
+
 	t.Run("updates chan is closed", func(t *testing.T) {
 		t.Parallel()
 
 		botAPIMock := BotAPIMock{
+			StopReceivingUpdatesFunc: func() {},
 			GetUpdatesChanFunc: func(config tbapi.UpdateConfig) tbapi.UpdatesChannel {
 				cfg := tbapi.NewUpdate(0)
 				cfg.Timeout = 60
@@ -123,7 +172,7 @@ func Test_listener_Listen(t *testing.T) {
 			},
 		}
 
-		listenerImpl := New(&botAPIMock, nil)
+		listenerImpl := New(&botAPIMock, nil, newStorageMock(), Config{UpdatesTimeout: 60 * time.Second, HandlerTimeout: 5 * time.Second})
 
 		ctx, cancel := context.WithTimeout(context.TODO(), 300*time.Millisecond)
 		defer cancel()
@@ -135,6 +184,7 @@ func Test_listener_Listen(t *testing.T) {
 		t.Parallel()
 
 		botAPIMock := BotAPIMock{
+			StopReceivingUpdatesFunc: func() {},
 			GetUpdatesChanFunc: func(config tbapi.UpdateConfig) tbapi.UpdatesChannel {
 				cfg := tbapi.NewUpdate(0)
 				cfg.Timeout = 60
@@ -163,7 +213,7 @@ func Test_listener_Listen(t *testing.T) {
 			},
 		}
 
-		listenerImpl := New(&botAPIMock, &updateReceiverMock)
+		listenerImpl := New(&botAPIMock, &updateReceiverMock, newStorageMock(), Config{UpdatesTimeout: 60 * time.Second, HandlerTimeout: 5 * time.Second})
 
 		ctx, cancel := context.WithTimeout(context.TODO(), 300*time.Millisecond)
 		defer cancel()
@@ -171,4 +221,385 @@ func Test_listener_Listen(t *testing.T) {
 		listenerImpl.Listen(ctx)
 	})
 
+	t.Run("success: updates of different users are processed concurrently, updates of one user are processed in order", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			users           = 10
+			chats           = 3
+			updatesPerChat  = 50
+			concurrentUsers = 4
+		)
+
+		ch := make(chan tbapi.Update, users*chats*updatesPerChat)
+		// interleave updates of all users, every user writes to private chat and to groups
+		updateID := 0
+		for i := 0; i < updatesPerChat; i++ {
+			for userID := int64(1); userID <= users; userID++ {
+				for chatID := int64(0); chatID < chats; chatID++ {
+					updateID++
+					text := strconv.Itoa(i)
+					if i%2 == 0 {
+						ch <- tbapi.Update{UpdateID: updateID, Message: &tbapi.Message{Text: text, From: &tbapi.User{ID: userID}, Chat: &tbapi.Chat{ID: -chatID}}}
+						continue
+					}
+					ch <- tbapi.Update{UpdateID: updateID, CallbackQuery: &tbapi.CallbackQuery{
+						Data:    text,
+						From:    &tbapi.User{ID: userID},
+						Message: &tbapi.Message{Chat: &tbapi.Chat{ID: -chatID}},
+					}}
+				}
+			}
+		}
+		close(ch)
+
+		type key struct{ userID, chatID int64 }
+
+		var (
+			mu         sync.Mutex
+			processed  = make(map[key][]int)
+			inProgress = make(map[key]bool)
+			running    int
+			maxRunning int
+		)
+		handle := func(userID, chatID int64, text string) error {
+			k := key{userID: userID, chatID: chatID}
+
+			mu.Lock()
+			assert.False(t, inProgress[k], "updates of user %d in chat %d are processed concurrently", userID, chatID)
+			inProgress[k] = true
+			running++
+			maxRunning = max(maxRunning, running)
+			mu.Unlock()
+
+			time.Sleep(time.Duration(rand.Intn(100)) * time.Microsecond)
+
+			n, err := strconv.Atoi(text)
+			assert.NoError(t, err)
+
+			mu.Lock()
+			defer mu.Unlock()
+			processed[k] = append(processed[k], n)
+			inProgress[k] = false
+			running--
+			return nil
+		}
+
+		botAPIMock := BotAPIMock{
+			StopReceivingUpdatesFunc: func() {},
+			GetUpdatesChanFunc: func(config tbapi.UpdateConfig) tbapi.UpdatesChannel {
+				return ch
+			},
+		}
+		updateReceiverMock := UpdateReceiverMock{
+			OnMessageFunc: func(_ context.Context, message domain.TgMessage) error {
+				return handle(message.UserID, message.ChatID, message.Text)
+			},
+			OnCallbackQueryFunc: func(_ context.Context, callback domain.TgCallbackQuery) error {
+				return handle(callback.UserID, callback.ChatID, callback.Data)
+			},
+		}
+
+		storageMock := newStorageMock()
+		listenerImpl := New(&botAPIMock, &updateReceiverMock, storageMock, Config{HandlerTimeout: 5 * time.Second, Workers: concurrentUsers, QueueSize: 2})
+
+		// closed updates chan stops listener after all updates are processed
+		err := listenerImpl.Listen(context.TODO())
+
+		assert.EqualError(t, err, "telegram updates chan closed")
+		assert.Len(t, processed, users*chats)
+		for k, numbers := range processed {
+			assert.Len(t, numbers, updatesPerChat, "user %d, chat %d", k.userID, k.chatID)
+			assert.IsIncreasing(t, numbers, "user %d, chat %d", k.userID, k.chatID)
+		}
+		assert.Greater(t, maxRunning, 1)
+		assert.LessOrEqual(t, maxRunning, concurrentUsers)
+
+		// workers save offsets concurrently, storage keeps the greatest one
+		var lastOffset int
+		for _, call := range storageMock.SaveUpdateOffsetCalls() {
+			lastOffset = max(lastOffset, call.UpdateID)
+		}
+		assert.Equal(t, updateID, lastOffset)
+	})
+
+	t.Run("success: full queue holds back receiving of updates", func(t *testing.T) {
+		t.Parallel()
+
+		ch := make(chan tbapi.Update, 5)
+		for i := 0; i < cap(ch); i++ {
+			ch <- tbapi.Update{UpdateID: i + 1, Message: &tbapi.Message{Text: "winds", From: &tbapi.User{ID: 2}, Chat: &tbapi.Chat{ID: 1}}}
+		}
+
+		botAPIMock := BotAPIMock{
+			StopReceivingUpdatesFunc: func() {},
+			GetUpdatesChanFunc: func(config tbapi.UpdateConfig) tbapi.UpdatesChannel {
+				return ch
+			},
+		}
+
+		release := make(chan struct{})
+		var handled atomic.Int32
+		updateReceiverMock := UpdateReceiverMock{
+			OnMessageFunc: func(_ context.Context, _ domain.TgMessage) error {
+				<-release
+				handled.Add(1)
+				return nil
+			},
+		}
+
+		listenerImpl := New(&botAPIMock, &updateReceiverMock, newStorageMock(), Config{HandlerTimeout: 5 * time.Second, Workers: 1, QueueSize: 1})
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- listenerImpl.Listen(context.TODO())
+		}()
+
+		// one update is processed, one is queued and one is waiting for free space in the queue
+		assert.Eventually(t, func() bool { return len(ch) == 2 }, time.Second, 10*time.Millisecond)
+		time.Sleep(50 * time.Millisecond)
+		assert.Len(t, ch, 2)
+
+		close(release)
+		close(ch)
+
+		assert.EqualError(t, <-errCh, "telegram updates chan closed")
+		assert.EqualValues(t, 5, handled.Load())
+	})
+
+	t.Run("success: listening is resumed from saved offset, offset is moved when all previous updates are processed", func(t *testing.T) {
+		t.Parallel()
+
+		ch := make(chan tbapi.Update, 3)
+		for _, updateID := range []int{42, 43, 44} {
+			ch <- tbapi.Update{UpdateID: updateID, Message: &tbapi.Message{Text: "winds", From: &tbapi.User{ID: 2}, Chat: &tbapi.Chat{ID: 1}}}
+		}
+		close(ch)
+
+		botAPIMock := BotAPIMock{
+			StopReceivingUpdatesFunc: func() {},
+			GetUpdatesChanFunc: func(config tbapi.UpdateConfig) tbapi.UpdatesChannel {
+				cfg := tbapi.NewUpdate(42)
+				cfg.Timeout = 60
+				assert.Equal(t, cfg, config)
+				return ch
+			},
+		}
+		updateReceiverMock := UpdateReceiverMock{
+			OnMessageFunc: func(_ context.Context, _ domain.TgMessage) error {
+				return nil
+			},
+		}
+		storageMock := newStorageMock()
+		storageMock.GetUpdateOffsetFunc = func(_ context.Context) (int, error) {
+			return 41, nil
+		}
+
+		listenerImpl := New(&botAPIMock, &updateReceiverMock, storageMock, Config{UpdatesTimeout: 60 * time.Second, HandlerTimeout: 5 * time.Second})
+
+		err := listenerImpl.Listen(context.TODO())
+
+		assert.EqualError(t, err, "telegram updates chan closed")
+		assert.Len(t, updateReceiverMock.OnMessageCalls(), 3)
+		var saved []int
+		for _, call := range storageMock.SaveUpdateOffsetCalls() {
+			saved = append(saved, call.UpdateID)
+		}
+		assert.Equal(t, []int{42, 43, 44}, saved)
+	})
+
+	t.Run("success: already processed update is skipped", func(t *testing.T) {
+		t.Parallel()
+
+		ch := make(chan tbapi.Update, 2)
+		ch <- tbapi.Update{UpdateID: 42, Message: &tbapi.Message{Text: "/create_reminder", From: &tbapi.User{ID: 2}, Chat: &tbapi.Chat{ID: 1}}}
+		ch <- tbapi.Update{UpdateID: 42, Message: &tbapi.Message{Text: "/create_reminder", From: &tbapi.User{ID: 2}, Chat: &tbapi.Chat{ID: 1}}}
+		close(ch)
+
+		botAPIMock := BotAPIMock{
+			StopReceivingUpdatesFunc: func() {},
+			GetUpdatesChanFunc: func(config tbapi.UpdateConfig) tbapi.UpdatesChannel {
+				return ch
+			},
+		}
+		updateReceiverMock := UpdateReceiverMock{
+			OnMessageFunc: func(_ context.Context, _ domain.TgMessage) error {
+				return nil
+			},
+		}
+		storageMock := newStorageMock()
+
+		listenerImpl := New(&botAPIMock, &updateReceiverMock, storageMock, Config{HandlerTimeout: 5 * time.Second})
+
+		err := listenerImpl.Listen(context.TODO())
+
+		assert.EqualError(t, err, "telegram updates chan closed")
+		assert.Len(t, storageMock.MarkUpdateProcessedCalls(), 2)
+		assert.Len(t, updateReceiverMock.OnMessageCalls(), 1)
+	})
+
+	t.Run("success: failed update isn't handled again", func(t *testing.T) {
+		t.Parallel()
+
+		ch := make(chan tbapi.Update, 2)
+		ch <- tbapi.Update{UpdateID: 42, Message: &tbapi.Message{Text: "/create_reminder", From: &tbapi.User{ID: 2}, Chat: &tbapi.Chat{ID: 1}}}
+		ch <- tbapi.Update{UpdateID: 42, Message: &tbapi.Message{Text: "/create_reminder", From: &tbapi.User{ID: 2}, Chat: &tbapi.Chat{ID: 1}}}
+		close(ch)
+
+		botAPIMock := BotAPIMock{
+			StopReceivingUpdatesFunc: func() {},
+			GetUpdatesChanFunc: func(config tbapi.UpdateConfig) tbapi.UpdatesChannel {
+				return ch
+			},
+		}
+		updateReceiverMock := UpdateReceiverMock{
+			OnMessageFunc: func(_ context.Context, _ domain.TgMessage) error {
+				return errors.New("db is locked")
+			},
+		}
+		storageMock := newStorageMock()
+
+		listenerImpl := New(&botAPIMock, &updateReceiverMock, storageMock, Config{HandlerTimeout: 5 * time.Second})
+
+		err := listenerImpl.Listen(context.TODO())
+
+		assert.EqualError(t, err, "telegram updates chan closed")
+		assert.Len(t, updateReceiverMock.OnMessageCalls(), 1)
+		// offset is moved past failed update, so Telegram doesn't send it again after restart
+		assert.NotEmpty(t, storageMock.SaveUpdateOffsetCalls())
+		for _, call := range storageMock.SaveUpdateOffsetCalls() {
+			assert.Equal(t, 42, call.UpdateID)
+		}
+	})
+
+	t.Run("error: can't get update offset", func(t *testing.T) {
+		t.Parallel()
+
+		storageMock := newStorageMock()
+		storageMock.GetUpdateOffsetFunc = func(_ context.Context) (int, error) {
+			return 0, errors.New("db is locked")
+		}
+
+		listenerImpl := New(&BotAPIMock{}, nil, storageMock, Config{HandlerTimeout: 5 * time.Second})
+
+		err := listenerImpl.Listen(context.TODO())
+
+		assert.EqualError(t, err, "failed to get update offset: db is locked")
+	})
+
+	t.Run("error: can't mark update as processed", func(t *testing.T) {
+		t.Parallel()
+
+		ch := make(chan tbapi.Update, 1)
+		ch <- tbapi.Update{UpdateID: 42, Message: &tbapi.Message{Text: "winds", From: &tbapi.User{ID: 2}, Chat: &tbapi.Chat{ID: 1}}}
+		close(ch)
+
+		botAPIMock := BotAPIMock{
+			StopReceivingUpdatesFunc: func() {},
+			GetUpdatesChanFunc: func(config tbapi.UpdateConfig) tbapi.UpdatesChannel {
+				return ch
+			},
+		}
+		updateReceiverMock := UpdateReceiverMock{}
+		storageMock := newStorageMock()
+		storageMock.MarkUpdateProcessedFunc = func(_ context.Context, _ int) (bool, error) {
+			return false, errors.New("db is locked")
+		}
+
+		listenerImpl := New(&botAPIMock, &updateReceiverMock, storageMock, Config{HandlerTimeout: 5 * time.Second})
+
+		err := listenerImpl.Listen(context.TODO())
+
+		assert.EqualError(t, err, "telegram updates chan closed")
+		assert.Empty(t, updateReceiverMock.OnMessageCalls())
+		// update can't be retried, so offset is moved anyway
+		assert.Len(t, storageMock.SaveUpdateOffsetCalls(), 1)
+	})
+}
+
+func Test_offsetTracker(t *testing.T) {
+	t.Parallel()
+
+	tracker := newOffsetTracker()
+	for _, updateID := range []int{10, 11, 15, 16} {
+		tracker.add(updateID)
+	}
+
+	_, moved := tracker.complete(11)
+	assert.False(t, moved, "update 10 is still in progress")
+
+	offset, moved := tracker.complete(10)
+	assert.True(t, moved)
+	assert.Equal(t, 11, offset)
+
+	offset, moved = tracker.complete(16)
+	assert.False(t, moved, "update 15 is still in progress")
+	assert.Zero(t, offset)
+
+	offset, moved = tracker.complete(15)
+	assert.True(t, moved)
+	assert.Equal(t, 16, offset)
+}
+
+// newStorageMock returns storage mock which keeps processed updates in memory.
+func newStorageMock() *StorageMock {
+	var (
+		mu        sync.Mutex
+		processed = make(map[int]bool)
+	)
+
+	return &StorageMock{
+		GetUpdateOffsetFunc: func(_ context.Context) (int, error) {
+			return 0, nil
+		},
+		SaveUpdateOffsetFunc: func(_ context.Context, _ int) error {
+			return nil
+		},
+		MarkUpdateProcessedFunc: func(_ context.Context, updateID int) (bool, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if processed[updateID] {
+				return false, nil
+			}
+			processed[updateID] = true
+			return true, nil
+		},
+	}
+}
+
+func Test_shard(t *testing.T) {
+	t.Parallel()
+
+	const workers = 4
+
+	used := make(map[int]bool)
+	for userID := int64(1); userID <= 100; userID++ {
+		for _, chatID := range []int64{userID, -100123, -100456} {
+			idx := shard(userID, chatID, workers)
+			assert.GreaterOrEqual(t, idx, 0)
+			assert.Less(t, idx, workers)
+			assert.Equal(t, idx, shard(userID, chatID, workers), "shard must be stable")
+			used[idx] = true
+		}
+	}
+	assert.Len(t, used, workers, "all workers must be used")
+	assert.Zero(t, shard(1, 2, 1))
+}
+
+func Test_updateContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := updateContext(context.TODO(), tbapi.Update{
+		Message: &tbapi.Message{From: &tbapi.User{ID: 2}, Chat: &tbapi.Chat{ID: 1}},
+	})
+
+	fields := logging.FieldsFromContext(ctx)
+	assert.Len(t, fields.CorrelationID, 16)
+	assert.EqualValues(t, 2, fields.UserID)
+	assert.EqualValues(t, 1, fields.ChatID)
+
+	other := updateContext(context.TODO(), tbapi.Update{})
+	assert.NotEqual(t, fields.CorrelationID, logging.CorrelationID(other), "every update has its own correlation id")
+	assert.Zero(t, logging.FieldsFromContext(other).UserID)
 }
@@ -0,0 +1,42 @@
+package listener
+
+import "sync"
+
+// offsetTracker tracks updates processed out of order by concurrent workers and calculates update offset,
+// i.e. ID of the last update which was processed along with all updates received before it.
+type offsetTracker struct {
+	mu      sync.Mutex
+	pending []int            // IDs of received updates in order they were received
+	done    map[int]struct{} // IDs of processed updates which are still in pending
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{done: make(map[int]struct{})}
+}
+
+// add registers received update.
+func (t *offsetTracker) add(updateID int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pending = append(t.pending, updateID)
+}
+
+// complete marks update as processed. Returns new offset if it has been moved.
+func (t *offsetTracker) complete(updateID int) (offset int, moved bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.done[updateID] = struct{}{}
+
+	for len(t.pending) > 0 {
+		if _, ok := t.done[t.pending[0]]; !ok {
+			break
+		}
+		offset, moved = t.pending[0], true
+		delete(t.done, t.pending[0])
+		t.pending = t.pending[1:]
+	}
+
+	return offset, moved
+}
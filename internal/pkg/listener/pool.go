@@ -0,0 +1,95 @@
+package listener
+
+import (
+	"context"
+	"sync"
+
+	tbapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// pool - workers which process updates concurrently.
+// Updates of the same user in the same chat are always processed by the same worker one by one,
+// so dialog state of the user is changed in order updates were received.
+type pool struct {
+	queues []chan tbapi.Update
+	wg     sync.WaitGroup
+}
+
+// newPool starts workers, every worker has its own queue with queueSize capacity.
+func newPool(ctx context.Context, workers, queueSize int, process func(ctx context.Context, update tbapi.Update)) *pool {
+	workers = max(workers, 1)
+	queueSize = max(queueSize, 1)
+
+	p := &pool{queues: make([]chan tbapi.Update, workers)}
+	for i := range p.queues {
+		queue := make(chan tbapi.Update, queueSize)
+		p.queues[i] = queue
+
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for update := range queue {
+				process(ctx, update)
+			}
+		}()
+	}
+
+	return p
+}
+
+// dispatch puts update to the queue of its worker.
+// Blocks while the queue is full, so slow workers hold back receiving of new updates.
+func (p *pool) dispatch(update tbapi.Update) {
+	userID, chatID := updateKey(update)
+	p.queues[shard(userID, chatID, len(p.queues))] <- update
+}
+
+// stop waits for all queued updates to be processed and stops workers.
+func (p *pool) stop() {
+	for _, queue := range p.queues {
+		close(queue)
+	}
+	p.wg.Wait()
+}
+
+// updateKey returns user and chat of update, updates without them get zero values.
+func updateKey(update tbapi.Update) (userID, chatID int64) {
+	switch {
+	case update.Message != nil:
+		if update.Message.From != nil {
+			userID = update.Message.From.ID
+		}
+		if update.Message.Chat != nil {
+			chatID = update.Message.Chat.ID
+		}
+	case update.CallbackQuery != nil:
+		if update.CallbackQuery.From != nil {
+			userID = update.CallbackQuery.From.ID
+		}
+		if update.CallbackQuery.Message != nil && update.CallbackQuery.Message.Chat != nil {
+			chatID = update.CallbackQuery.Message.Chat.ID
+		}
+	}
+
+	return userID, chatID
+}
+
+// shard returns worker index for (user, chat) pair.
+func shard(userID, chatID int64, workers int) int {
+	// FNV-1a over both IDs, chat IDs of groups are negative so unsigned arithmetic is used
+	const (
+		offset = 14695981039346656037
+		prime  = 1099511628211
+	)
+
+	h := uint64(offset)
+	for _, v := range [2]uint64{uint64(userID), uint64(chatID)} {
+		for i := 0; i < 8; i++ {
+			h ^= v & 0xff
+			h *= prime
+			v >>= 8
+		}
+	}
+
+	return int(h % uint64(workers))
+}
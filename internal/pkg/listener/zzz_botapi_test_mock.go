@@ -21,6 +21,9 @@ var _ BotAPI = &BotAPIMock{}
 //			GetUpdatesChanFunc: func(config tbapi.UpdateConfig) tbapi.UpdatesChannel {
 //				panic("mock out the GetUpdatesChan method")
 //			},
+//			StopReceivingUpdatesFunc: func()  {
+//				panic("mock out the StopReceivingUpdates method")
+//			},
 //		}
 //
 //		// use mockedBotAPI in code that requires BotAPI
@@ -31,6 +34,9 @@ type BotAPIMock struct {
 	// GetUpdatesChanFunc mocks the GetUpdatesChan method.
 	GetUpdatesChanFunc func(config tbapi.UpdateConfig) tbapi.UpdatesChannel
 
+	// StopReceivingUpdatesFunc mocks the StopReceivingUpdates method.
+	StopReceivingUpdatesFunc func()
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// GetUpdatesChan holds details about calls to the GetUpdatesChan method.
@@ -38,8 +44,12 @@ type BotAPIMock struct {
 			// Config is the config argument value.
 			Config tbapi.UpdateConfig
 		}
+		// StopReceivingUpdates holds details about calls to the StopReceivingUpdates method.
+		StopReceivingUpdates []struct {
+		}
 	}
-	lockGetUpdatesChan sync.RWMutex
+	lockGetUpdatesChan       sync.RWMutex
+	lockStopReceivingUpdates sync.RWMutex
 }
 
 // GetUpdatesChan calls GetUpdatesChanFunc.
@@ -81,9 +91,47 @@ func (mock *BotAPIMock) ResetGetUpdatesChanCalls() {
 	mock.lockGetUpdatesChan.Unlock()
 }
 
+// StopReceivingUpdates calls StopReceivingUpdatesFunc.
+func (mock *BotAPIMock) StopReceivingUpdates() {
+	if mock.StopReceivingUpdatesFunc == nil {
+		panic("BotAPIMock.StopReceivingUpdatesFunc: method is nil but BotAPI.StopReceivingUpdates was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockStopReceivingUpdates.Lock()
+	mock.calls.StopReceivingUpdates = append(mock.calls.StopReceivingUpdates, callInfo)
+	mock.lockStopReceivingUpdates.Unlock()
+	mock.StopReceivingUpdatesFunc()
+}
+
+// StopReceivingUpdatesCalls gets all the calls that were made to StopReceivingUpdates.
+// Check the length with:
+//
+//	len(mockedBotAPI.StopReceivingUpdatesCalls())
+func (mock *BotAPIMock) StopReceivingUpdatesCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockStopReceivingUpdates.RLock()
+	calls = mock.calls.StopReceivingUpdates
+	mock.lockStopReceivingUpdates.RUnlock()
+	return calls
+}
+
+// ResetStopReceivingUpdatesCalls reset all the calls that were made to StopReceivingUpdates.
+func (mock *BotAPIMock) ResetStopReceivingUpdatesCalls() {
+	mock.lockStopReceivingUpdates.Lock()
+	mock.calls.StopReceivingUpdates = nil
+	mock.lockStopReceivingUpdates.Unlock()
+}
+
 // ResetCalls reset all the calls that were made to all mocked methods.
 func (mock *BotAPIMock) ResetCalls() {
 	mock.lockGetUpdatesChan.Lock()
 	mock.calls.GetUpdatesChan = nil
 	mock.lockGetUpdatesChan.Unlock()
+
+	mock.lockStopReceivingUpdates.Lock()
+	mock.calls.StopReceivingUpdates = nil
+	mock.lockStopReceivingUpdates.Unlock()
 }
@@ -0,0 +1,233 @@
+// Package logging extends lgr logger with fields taken from context: correlation id of Telegram update or
+// notifier batch, user and chat ids. Logs are written as colorized text or as JSON lines for log pipelines.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fatih/color"
+	log "github.com/go-pkgz/lgr"
+)
+
+// Format - format of logs.
+type Format string
+
+const (
+	// FormatText - human readable text, colorized in terminals.
+	FormatText Format = "text"
+	// FormatJSON - one JSON object per line, fields from context are separate keys.
+	FormatJSON Format = "json"
+)
+
+// ParseFormat parses log format. Empty string is parsed as [FormatText].
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unknown log format %q", s)
+	}
+}
+
+// Fields - fields added to every log line written with [Printf].
+type Fields struct {
+	CorrelationID string `json:"correlation_id,omitempty"`
+	UserID        int64  `json:"user_id,omitempty"`
+	ChatID        int64  `json:"chat_id,omitempty"`
+}
+
+func (f Fields) isZero() bool {
+	return f == Fields{}
+}
+
+// String formats fields as space separated key=value pairs for text logs.
+func (f Fields) String() string {
+	parts := make([]string, 0, 3)
+	if f.CorrelationID != "" {
+		parts = append(parts, "correlation_id="+f.CorrelationID)
+	}
+	if f.UserID != 0 {
+		parts = append(parts, "user_id="+strconv.FormatInt(f.UserID, 10))
+	}
+	if f.ChatID != 0 {
+		parts = append(parts, "chat_id="+strconv.FormatInt(f.ChatID, 10))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+type fieldsCtxKey struct{}
+
+// FieldsFromContext returns fields set by [ContextWithCorrelationID] and [ContextWithUser].
+func FieldsFromContext(ctx context.Context) Fields {
+	f, _ := ctx.Value(fieldsCtxKey{}).(Fields)
+	return f
+}
+
+// ContextWithCorrelationID returns copy of ctx with correlation id, user and chat ids are kept.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	f := FieldsFromContext(ctx)
+	f.CorrelationID = id
+	return context.WithValue(ctx, fieldsCtxKey{}, f)
+}
+
+// ContextWithUser returns copy of ctx with user and chat ids, correlation id is kept.
+func ContextWithUser(ctx context.Context, userID, chatID int64) context.Context {
+	f := FieldsFromContext(ctx)
+	f.UserID, f.ChatID = userID, chatID
+	return context.WithValue(ctx, fieldsCtxKey{}, f)
+}
+
+// CorrelationID returns correlation id from ctx, empty string if it isn't set.
+func CorrelationID(ctx context.Context) string {
+	return FieldsFromContext(ctx).CorrelationID
+}
+
+// NewCorrelationID generates random correlation id.
+func NewCorrelationID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b) // never returns an error
+	return hex.EncodeToString(b)
+}
+
+// fieldsSep separates message and JSON encoded fields in lines passed from lgr to jsonWriter.
+const fieldsSep = "\x1f"
+
+var (
+	// logger reports caller of Printf, not Printf itself
+	logger   atomic.Pointer[log.Logger]
+	jsonMode atomic.Bool
+)
+
+func init() {
+	logger.Store(log.New(log.CallerDepth(1)))
+}
+
+// Setup sets up lgr default logger, std logger and the logger of [Printf]. Secrets are masked in all logs.
+// Text logs are colorized, JSON logs are written to stdout only, including errors.
+func Setup(format Format, dbg bool, secrets ...string) {
+	setup(os.Stdout, format, dbg, secrets...)
+}
+
+// SetupOutput is [Setup] with logs written to out, e.g. to stderr when stdout is used for output of a subcommand.
+func SetupOutput(out io.Writer, format Format, dbg bool, secrets ...string) {
+	setup(out, format, dbg, secrets...)
+}
+
+func setup(out io.Writer, format Format, dbg bool, secrets ...string) {
+	var opts []log.Option
+
+	switch format {
+	case FormatJSON:
+		layout := "{{.DT.Format \"2006-01-02T15:04:05.000Z07:00\"}}\t{{.Level}}\t\t{{.Message}}"
+		if dbg {
+			layout = "{{.DT.Format \"2006-01-02T15:04:05.000Z07:00\"}}\t{{.Level}}\t{{.CallerFile}}:{{.CallerLine}}\t{{.Message}}"
+		}
+		opts = []log.Option{log.Format(layout), log.Out(&jsonWriter{out: out}), log.Err(io.Discard)}
+	default:
+		opts = []log.Option{log.Msec, log.LevelBraces, log.StackTraceOnError, log.Out(out)}
+		if dbg {
+			opts = append(opts, log.CallerFile, log.CallerFunc)
+		}
+
+		opts = append(opts, log.Map(log.Mapper{
+			ErrorFunc:  func(s string) string { return color.New(color.FgHiRed).Sprint(s) },
+			WarnFunc:   func(s string) string { return color.New(color.FgRed).Sprint(s) },
+			InfoFunc:   func(s string) string { return color.New(color.FgYellow).Sprint(s) },
+			DebugFunc:  func(s string) string { return color.New(color.FgWhite).Sprint(s) },
+			CallerFunc: func(s string) string { return color.New(color.FgBlue).Sprint(s) },
+			TimeFunc:   func(s string) string { return color.New(color.FgCyan).Sprint(s) },
+		}))
+	}
+
+	if dbg {
+		opts = append(opts, log.Debug)
+	}
+
+	if len(secrets) > 0 {
+		opts = append(opts, log.Secret(secrets...))
+	}
+
+	jsonMode.Store(format == FormatJSON)
+	log.SetupStdLogger(opts...)
+	log.Setup(opts...)
+	logger.Store(log.New(append(opts, log.CallerDepth(1))...))
+}
+
+// Printf logs message with lgr, level is taken from message prefix, e.g. "[INFO]".
+// Fields from ctx are appended to text message or written as separate keys in JSON mode.
+func Printf(ctx context.Context, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+
+	if f := FieldsFromContext(ctx); !f.isZero() {
+		if jsonMode.Load() {
+			b, _ := json.Marshal(f) // fields are always marshaled
+			msg += fieldsSep + string(b)
+		} else {
+			msg += " " + f.String()
+		}
+	}
+
+	logger.Load().Logf("%s", msg)
+}
+
+// jsonWriter converts lines formatted by lgr to JSON.
+type jsonWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+type jsonLine struct {
+	Time   string `json:"time"`
+	Level  string `json:"level"`
+	Caller string `json:"caller,omitempty"`
+	Msg    string `json:"msg"`
+	Fields
+}
+
+// Write implements [io.Writer], p is one line formatted with the layout set in [Setup].
+func (w *jsonWriter) Write(p []byte) (int, error) {
+	parts := strings.SplitN(strings.TrimSuffix(string(p), "\n"), "\t", 4)
+	if len(parts) != 4 {
+		// not a log line, e.g. stack trace, it's written as message
+		parts = []string{"", "", "", string(p)}
+	}
+
+	line := jsonLine{
+		Time:   parts[0],
+		Level:  strings.TrimSpace(parts[1]),
+		Caller: parts[2],
+		Msg:    parts[3],
+	}
+
+	if msg, fields, ok := strings.Cut(line.Msg, fieldsSep); ok {
+		line.Msg = msg
+		_ = json.Unmarshal([]byte(fields), &line.Fields) // fields are marshaled by Printf
+	}
+
+	b, err := json.Marshal(line)
+	if err != nil {
+		return 0, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, err = w.out.Write(append(b, '\n')); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
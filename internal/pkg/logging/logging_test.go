@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		input  string
+		exp    Format
+		expErr string
+	}{
+		{name: "success: empty is text", input: "", exp: FormatText},
+		{name: "success: text", input: "text", exp: FormatText},
+		{name: "success: json", input: "json", exp: FormatJSON},
+		{name: "error: unknown format", input: "xml", expErr: `unknown log format "xml"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			act, err := ParseFormat(tc.input)
+			if tc.expErr != "" {
+				assert.EqualError(t, err, tc.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, act)
+		})
+	}
+}
+
+func TestContextFields(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	assert.Equal(t, Fields{}, FieldsFromContext(ctx))
+	assert.Empty(t, CorrelationID(ctx))
+
+	ctx = ContextWithUser(ContextWithCorrelationID(ctx, "0123456789abcdef"), 2, 1)
+	assert.Equal(t, Fields{CorrelationID: "0123456789abcdef", UserID: 2, ChatID: 1}, FieldsFromContext(ctx))
+	assert.Equal(t, "correlation_id=0123456789abcdef user_id=2 chat_id=1", FieldsFromContext(ctx).String())
+
+	ctx = ContextWithCorrelationID(ctx, "fedcba9876543210")
+	assert.Equal(t, Fields{CorrelationID: "fedcba9876543210", UserID: 2, ChatID: 1}, FieldsFromContext(ctx), "user is kept")
+
+	id := NewCorrelationID()
+	assert.Len(t, id, 16)
+	assert.NotEqual(t, id, NewCorrelationID())
+}
+
+// nolint:paralleltest // test modifies package level logger.
+func TestPrintf(t *testing.T) {
+	t.Cleanup(func() { setup(&bytes.Buffer{}, FormatText, false) })
+
+	ctx := ContextWithUser(ContextWithCorrelationID(context.TODO(), "0123456789abcdef"), 2, 1)
+
+	t.Run("success: json", func(t *testing.T) {
+		var buf bytes.Buffer
+		setup(&buf, FormatJSON, false)
+
+		Printf(ctx, "[INFO] saved reminder %d", 42)
+		Printf(context.TODO(), "[WARN] no fields")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+
+		var act map[string]any
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &act))
+		assert.NotEmpty(t, act["time"])
+		delete(act, "time")
+		assert.Equal(t, map[string]any{
+			"level":          "INFO",
+			"msg":            "saved reminder 42",
+			"correlation_id": "0123456789abcdef",
+			"user_id":        float64(2),
+			"chat_id":        float64(1),
+		}, act)
+
+		act = nil
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &act))
+		delete(act, "time")
+		assert.Equal(t, map[string]any{"level": "WARN", "msg": "no fields"}, act)
+	})
+
+	t.Run("success: json with caller in debug mode", func(t *testing.T) {
+		var buf bytes.Buffer
+		setup(&buf, FormatJSON, true)
+
+		Printf(ctx, "[DEBUG] got user")
+
+		var act jsonLine
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &act))
+		assert.Equal(t, "DEBUG", act.Level)
+		assert.Contains(t, act.Caller, "logging_test.go:")
+	})
+
+	t.Run("success: text", func(t *testing.T) {
+		var buf bytes.Buffer
+		setup(&buf, FormatText, false)
+
+		Printf(ctx, "[INFO] saved reminder %d", 42)
+
+		assert.Contains(t, buf.String(), "saved reminder 42 correlation_id=0123456789abcdef user_id=2 chat_id=1")
+	})
+
+	t.Run("success: secrets are masked", func(t *testing.T) {
+		var buf bytes.Buffer
+		setup(&buf, FormatJSON, false, "token123")
+
+		Printf(ctx, "[INFO] token is token123")
+
+		assert.NotContains(t, buf.String(), "token123")
+	})
+}
@@ -0,0 +1,140 @@
+package notifier
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+	"github.com/mezk/tg-reminder/internal/pkg/sender"
+	"github.com/mezk/tg-reminder/internal/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CatchUpPolicy - describes what to do with reminders missed while the bot was down or user disabled reminders.
+type CatchUpPolicy string
+
+const (
+	// CatchUpAll - missed reminders are sent as usual, all at once.
+	CatchUpAll CatchUpPolicy = "all"
+	// CatchUpSummary - one message with the list of missed reminders and buttons to reschedule them is sent instead.
+	CatchUpSummary CatchUpPolicy = "summary"
+	// CatchUpSkip - missed reminders are not sent.
+	CatchUpSkip CatchUpPolicy = "skip"
+)
+
+// ParseCatchUpPolicy parses catch-up policy. Empty string is parsed as [CatchUpAll].
+func ParseCatchUpPolicy(s string) (CatchUpPolicy, error) {
+	switch CatchUpPolicy(s) {
+	case "", CatchUpAll:
+		return CatchUpAll, nil
+	case CatchUpSummary, CatchUpSkip:
+		return CatchUpPolicy(s), nil
+	default:
+		return "", fmt.Errorf("unknown catch-up policy %q", s)
+	}
+}
+
+// CatchUp applies catch-up policy to reminders which are overdue for more than [Config.CatchUpAge].
+// Reminders of user are caught up if userID is set, otherwise reminders of all active users.
+func (n *Notifier) CatchUp(ctx context.Context, userID int64) (err error) {
+	if n.cfg.CatchUp == "" || n.cfg.CatchUp == CatchUpAll {
+		return nil
+	}
+
+	// reminders are missed by policy, even if user enabled reminders
+	ctx = domain.ContextWithActor(ctx, domain.Actor{Type: domain.ActorNotifier})
+	// catch-up started by user is a part of handling of the update, it already has correlation id
+	if logging.CorrelationID(ctx) == "" {
+		ctx = logging.ContextWithCorrelationID(ctx, logging.NewCorrelationID())
+	}
+
+	ctx, span := tracing.Start(ctx, "notifier.CatchUp", trace.WithAttributes(
+		attribute.String("catch_up.policy", string(n.cfg.CatchUp)),
+		attribute.Int64("telegram.user_id", userID),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	missed, err := n.storage.GetMissedReminders(ctx, userID, timeNowUTC().Add(-n.cfg.CatchUpAge))
+	if err != nil {
+		return err
+	}
+
+	if len(missed) == 0 {
+		return nil
+	}
+
+	if n.cfg.CatchUp == CatchUpSkip {
+		if err = n.storage.MissReminders(ctx, reminderIDs(missed), nil); err != nil {
+			return err
+		}
+
+		logging.Printf(ctx, "[INFO] notifier skipped %d missed reminders", len(missed))
+
+		return nil
+	}
+
+	// summaries are delivered right away, not on the next check of outbox
+	defer n.outbox.Wakeup()
+
+	// reminders are ordered by chat and user, every user gets one summary per chat
+	var errs []error
+	for start := 0; start < len(missed); {
+		end := start + 1
+		for end < len(missed) && missed[end].ChatID == missed[start].ChatID && missed[end].UserID == missed[start].UserID {
+			end++
+		}
+
+		if err = n.sendSummary(ctx, missed[start:end]); err != nil {
+			errs = append(errs, err)
+		}
+
+		start = end
+	}
+
+	return errors.Join(errs...)
+}
+
+// sendSummary marks reminders of one user in one chat as missed and enqueues their summary.
+func (n *Notifier) sendSummary(ctx context.Context, reminders []domain.Reminder) error {
+	chatID, userID := reminders[0].ChatID, reminders[0].UserID
+	ctx = logging.ContextWithUser(ctx, userID, chatID)
+
+	summary := domain.MissedSummary{Key: newSummaryKey()}
+	msg, err := sender.NewOutboxMessage(sender.BotResponse{
+		ChatID: chatID,
+		Text:   domain.FormatMissed(reminders),
+	}, sender.WithCatchUpButtons(summary.Key, n.getSettings(ctx, userID).SnoozeOptions))
+	if err != nil {
+		return fmt.Errorf("failed to render summary of missed reminders of user %d: %w", userID, err)
+	}
+	summary.Message = msg
+
+	if err = n.storage.MissReminders(ctx, reminderIDs(reminders), &summary); err != nil {
+		return err
+	}
+
+	logging.Printf(ctx, "[INFO] notifier enqueued summary of %d missed reminders to user %d in chat %d", len(reminders), userID, chatID)
+
+	return nil
+}
+
+func reminderIDs(reminders []domain.Reminder) []int64 {
+	ids := make([]int64, 0, len(reminders))
+	for _, r := range reminders {
+		ids = append(ids, r.ID)
+	}
+
+	return ids
+}
+
+// newSummaryKey generates random key of summary of missed reminders.
+func newSummaryKey() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b) // never returns an error
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,133 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCatchUpPolicy(t *testing.T) {
+	t.Parallel()
+
+	for s, exp := range map[string]CatchUpPolicy{"": CatchUpAll, "all": CatchUpAll, "summary": CatchUpSummary, "skip": CatchUpSkip} {
+		act, err := ParseCatchUpPolicy(s)
+		require.NoError(t, err)
+		assert.Equal(t, exp, act)
+	}
+
+	_, err := ParseCatchUpPolicy("foo")
+	assert.EqualError(t, err, `unknown catch-up policy "foo"`)
+}
+
+func TestNotifier_CatchUp(t *testing.T) {
+	t.Parallel()
+
+	missed := []domain.Reminder{
+		{ID: 1, ChatID: 10, UserID: 10, Text: "Foo", RemindAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{ID: 2, ChatID: 10, UserID: 10, Text: "Bar", RemindAt: time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)},
+		{ID: 3, ChatID: 20, UserID: 20, Text: "Baz", RemindAt: time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC)},
+	}
+
+	type missCall struct {
+		ids     []int64
+		chatID  int64
+		summary string
+	}
+
+	testCases := []struct {
+		name      string
+		policy    CatchUpPolicy
+		userID    int64
+		getErr    error
+		missErr   error
+		expMissed []missCall
+		expWakeup bool
+		expErr    string
+	}{
+		{
+			name:   "success: all, reminders are sent as usual",
+			policy: CatchUpAll,
+		},
+		{
+			name:      "success: skip",
+			policy:    CatchUpSkip,
+			expMissed: []missCall{{ids: []int64{1, 2, 3}}},
+		},
+		{
+			name:   "success: summary per chat",
+			policy: CatchUpSummary,
+			userID: 10,
+			expMissed: []missCall{
+				{ids: []int64{1, 2}, chatID: 10, summary: "⏰ *Пропущенные напоминания: 2*\n\n\t• *Foo* — 1 янв. 15:00\n\t• *Bar* — 1 янв. 16:00\n\nКогда напомнить о них снова❓"},
+				{ids: []int64{3}, chatID: 20, summary: "⏰ *Пропущенные напоминания: 1*\n\n\t• *Baz* — 1 янв. 17:00\n\nКогда напомнить о них снова❓"},
+			},
+			expWakeup: true,
+		},
+		{
+			name:   "error: can't get missed reminders",
+			policy: CatchUpSummary,
+			getErr: errors.New("database is locked"),
+			expErr: "database is locked",
+		},
+		{
+			name:    "error: can't miss reminders, summaries of other chats are sent",
+			policy:  CatchUpSummary,
+			missErr: errors.New("database is locked"),
+			expMissed: []missCall{
+				{ids: []int64{1, 2}, chatID: 10, summary: "⏰ *Пропущенные напоминания: 2*\n\n\t• *Foo* — 1 янв. 15:00\n\t• *Bar* — 1 янв. 16:00\n\nКогда напомнить о них снова❓"},
+				{ids: []int64{3}, chatID: 20, summary: "⏰ *Пропущенные напоминания: 1*\n\n\t• *Baz* — 1 янв. 17:00\n\nКогда напомнить о них снова❓"},
+			},
+			expWakeup: true,
+			expErr:    "database is locked\ndatabase is locked",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var actMissed []missCall
+
+			outboxMock := OutboxMock{WakeupFunc: func() {}}
+			storageMock := StorageMock{
+				GetMissedRemindersFunc: func(_ context.Context, userID int64, before time.Time) ([]domain.Reminder, error) {
+					assert.Equal(t, tc.userID, userID)
+					assert.WithinDuration(t, timeNowUTC().Add(-time.Hour), before, time.Second)
+					return missed, tc.getErr
+				},
+				GetUserSettingsFunc: func(_ context.Context, userID int64) (domain.UserSettings, error) {
+					return domain.UserSettings{UserID: userID}, nil
+				},
+				MissRemindersFunc: func(ctx context.Context, ids []int64, summary *domain.MissedSummary) error {
+					assert.Equal(t, domain.ActorNotifier, domain.ActorFromContext(ctx).Type)
+					call := missCall{ids: ids}
+					if summary != nil {
+						call.chatID = summary.Message.ChatID
+						call.summary = decodeMessage(t, summary.Message).Text
+						assert.Len(t, summary.Key, 16)
+						assert.Contains(t, summary.Message.Payload, "btn_catch_up/"+summary.Key+"/", "reschedule buttons")
+					}
+					actMissed = append(actMissed, call)
+					return tc.missErr
+				},
+			}
+
+			notifierImpl := New(&outboxMock, &storageMock, Config{CatchUp: tc.policy, CatchUpAge: time.Hour})
+
+			err := notifierImpl.CatchUp(context.TODO(), tc.userID)
+
+			if tc.expErr != "" {
+				assert.EqualError(t, err, tc.expErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expMissed, actMissed)
+			assert.Equal(t, tc.expWakeup, len(outboxMock.WakeupCalls()) == 1)
+		})
+	}
+}
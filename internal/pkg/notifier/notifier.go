@@ -2,11 +2,18 @@ package notifier
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
 	"time"
 
 	log "github.com/go-pkgz/lgr"
 	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
 	"github.com/mezk/tg-reminder/internal/pkg/sender"
+	"github.com/mezk/tg-reminder/internal/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var timeNowUTC = func() time.Time {
@@ -16,32 +23,54 @@ var timeNowUTC = func() time.Time {
 // Storage - storage interface.
 type Storage interface {
 	GetPendingReminders(ctx context.Context, limit int64) ([]domain.Reminder, error)
-	UpdateReminder(ctx context.Context, reminder domain.Reminder) error
+	NotifyReminder(ctx context.Context, reminder domain.Reminder, msgs []domain.OutboxMessage) error
+	GetDuePreNotices(ctx context.Context, limit int64) ([]domain.Reminder, error)
+	NotifyPreNotice(ctx context.Context, id int64, nextPreNoticeAt *time.Time, msgs []domain.OutboxMessage) error
+	GetUserSettings(ctx context.Context, userID int64) (domain.UserSettings, error)
+	GetMissedReminders(ctx context.Context, userID int64, before time.Time) ([]domain.Reminder, error)
+	MissReminders(ctx context.Context, ids []int64, summary *domain.MissedSummary) error
 }
 
-// BotResponseSender - bot's response sender.
-type BotResponseSender interface {
-	SendBotResponse(response sender.BotResponse, opts ...sender.BotResponseOption) error
+// Outbox - delivers enqueued messages.
+type Outbox interface {
+	Wakeup()
 }
 
-// Notifier sends reminders to users.
+// Config - notifier configuration.
+type Config struct {
+	Interval   time.Duration // how often to check for reminders to send
+	BatchSize  int64         // max number of reminders and advance notices sent per check
+	CatchUp    CatchUpPolicy // what to do with missed reminders
+	CatchUpAge time.Duration // reminders overdue for more than this are missed
+	// channels configured to deliver notifications, Telegram is always available
+	Channels domain.Channels
+}
+
+// Notifier sends reminders to users. Notifications are enqueued to outbox along with reminder update,
+// so reminder is never moved forward while its notification is lost.
+// Reminder is fanned out to every its channel, each channel gets its own outbox message and is retried independently.
 type Notifier struct {
-	botResponseSender BotResponseSender
-	storage           Storage
-	interval          time.Duration
+	outbox  Outbox
+	storage Storage
+	cfg     Config
 }
 
 // New creates new Notifier.
-func New(responseSender BotResponseSender, storage Storage, interval time.Duration) *Notifier {
-	return &Notifier{botResponseSender: responseSender, storage: storage, interval: interval}
+func New(outbox Outbox, storage Storage, cfg Config) *Notifier {
+	return &Notifier{outbox: outbox, storage: storage, cfg: cfg}
 }
 
 // Run starts infinite loop to fetch reminders from Storage and send them to users.
 // Breaks infinite loop on context error.
 func (n *Notifier) Run(ctx context.Context) {
-	log.Printf("[INFO] notifier started, tick interval %s", n.interval)
+	log.Printf("[INFO] notifier started, tick interval %s, batch size %d", n.cfg.Interval, n.cfg.BatchSize)
 
-	ticker := time.NewTicker(n.interval)
+	// reminders missed while the bot was down
+	if err := n.CatchUp(context.WithoutCancel(ctx), 0); err != nil {
+		log.Printf("[ERROR] failed to catch up missed reminders: %v", err)
+	}
+
+	ticker := time.NewTicker(n.cfg.Interval)
 
 	for {
 		select {
@@ -51,37 +80,209 @@ func (n *Notifier) Run(ctx context.Context) {
 			return
 
 		case <-ticker.C:
-			log.Printf("[DEBUG] notifier start sending reminders")
+			// batch in progress is finished on shutdown
+			n.sendBatch(context.WithoutCancel(ctx))
+		}
+	}
+}
 
-			const limit = 100
-			reminders, err := n.storage.GetPendingReminders(ctx, limit)
-			if err != nil {
-				log.Printf("[ERROR] failed to fetch reminders: %v", err)
-				continue
-			}
+// sendBatch sends due advance notices and reminders.
+func (n *Notifier) sendBatch(ctx context.Context) {
+	// all logs of the batch, including delivery of notifications, share the correlation id
+	ctx = logging.ContextWithCorrelationID(ctx, logging.NewCorrelationID())
+	ctx = domain.ContextWithActor(ctx, domain.Actor{Type: domain.ActorNotifier})
+
+	ctx, span := tracing.Start(ctx, "notifier.sendBatch", trace.WithAttributes(attribute.String("correlation_id", logging.CorrelationID(ctx))))
+	defer span.End()
+
+	logging.Printf(ctx, "[DEBUG] notifier start sending reminders")
+
+	// notifications are delivered right away, not on the next check of outbox
+	defer n.outbox.Wakeup()
+
+	n.sendPreNotices(ctx)
+
+	reminders, err := n.storage.GetPendingReminders(ctx, n.cfg.BatchSize)
+	if err != nil {
+		logging.Printf(ctx, "[ERROR] failed to fetch reminders: %v", err)
+		return
+	}
+
+	// users can have many reminders at once, settings are fetched once per tick
+	settings := make(map[int64]domain.UserSettings)
+
+	for _, r := range reminders {
+		ctx := logging.ContextWithUser(ctx, r.UserID, r.ChatID)
+
+		s, ok := settings[r.UserID]
+		if !ok {
+			s = n.getSettings(ctx, r.UserID)
+			settings[r.UserID] = s
+		}
+
+		opts := []sender.BotResponseOption{sender.WithReminderDoneButton(r.ID, s.SnoozeOptions)}
+		if len(r.Checklist) > 0 {
+			opts = append(opts, sender.WithChecklistButtons(r.Checklist))
+		}
+		switch r.Priority {
+		case domain.ReminderPriorityLow:
+			opts = append(opts, sender.WithDisableNotification())
+		case domain.ReminderPriorityCritical:
+			opts = append(opts, sender.WithPin())
+		}
+
+		msgs, err := n.newMessages(ctx, r, s, false, sender.BotResponse{
+			ChatID: r.ChatID,
+			Text:   r.FormatNotify(),
+		}, opts...)
+		if err != nil {
+			logging.Printf(ctx, "[ERROR] failed to render reminder %d: %v", r.ID, err)
+			continue
+		}
+
+		// delay reminder to wait for ack from user, reminders which must not be re-sent are exhausted right away
+		interval := r.Priority.RenotifyInterval()
+		r.RemindAt = timeNowUTC().Add(interval)
+		r.AttemptsLeft--
+
+		if r.AttemptsLeft == 0 || interval == 0 {
+			r.AttemptsLeft = 0
+			r.Status = domain.ReminderStatusAttemptsExhausted
+		}
+
+		if err = n.storage.NotifyReminder(ctx, r, msgs); err != nil {
+			logging.Printf(ctx, "[ERROR] failed to notify reminder %s: %v", r, err)
+			continue
+		}
+
+		logging.Printf(ctx, "[INFO] notifier enqueued reminder %d with %s priority to user %d in chat %d by %s",
+			r.ID, r.Priority, r.UserID, r.ChatID, messagesChannels(msgs))
+	}
+}
+
+// sendPreNotices sends advance notices. Advance notice doesn't wait for "done" from user and doesn't consume attempts.
+func (n *Notifier) sendPreNotices(ctx context.Context) {
+	reminders, err := n.storage.GetDuePreNotices(ctx, n.cfg.BatchSize)
+	if err != nil {
+		logging.Printf(ctx, "[ERROR] failed to fetch advance notices: %v", err)
+		return
+	}
 
-			for _, r := range reminders {
-				if err = n.botResponseSender.SendBotResponse(sender.BotResponse{
-					ChatID: r.ChatID,
-					Text:   r.FormatNotify(),
-				}, sender.WithReminderDoneButton(r.ID)); err != nil {
-					log.Printf("[ERROR] failed to send reminder %d: %v", r.ID, err)
-				}
+	settings := make(map[int64]domain.UserSettings)
 
-				log.Printf("[INFO] notifier sent reminder %d to user %d in chat %d", r.ID, r.UserID, r.ChatID)
+	for _, r := range reminders {
+		ctx := logging.ContextWithUser(ctx, r.UserID, r.ChatID)
+		now := timeNowUTC()
 
-				// delay reminder for 15 minutes to wait for ack from user
-				r.RemindAt = timeNowUTC().Add(15 * time.Minute)
-				r.AttemptsLeft--
+		s, ok := settings[r.UserID]
+		if !ok {
+			s = n.getSettings(ctx, r.UserID)
+			settings[r.UserID] = s
+		}
 
-				if r.AttemptsLeft == 0 {
-					r.Status = domain.ReminderStatusAttemptsExhausted
-				}
+		var opts []sender.BotResponseOption
+		if r.Priority == domain.ReminderPriorityLow {
+			opts = append(opts, sender.WithDisableNotification())
+		}
+
+		msgs, err := n.newMessages(ctx, r, s, true, sender.BotResponse{
+			ChatID: r.ChatID,
+			Text:   r.FormatPreNotify(now),
+		}, opts...)
+		if err != nil {
+			logging.Printf(ctx, "[ERROR] failed to render advance notice of reminder %d: %v", r.ID, err)
+			continue
+		}
+
+		var nextPreNoticeAt *time.Time
+		if next, ok := r.LeadTimes.NextNoticeAt(r.RemindAt, now); ok {
+			nextPreNoticeAt = &next
+		}
+
+		if err = n.storage.NotifyPreNotice(ctx, r.ID, nextPreNoticeAt, msgs); err != nil {
+			logging.Printf(ctx, "[ERROR] failed to notify advance notice of reminder %d: %v", r.ID, err)
+			continue
+		}
 
-				if err = n.storage.UpdateReminder(ctx, r); err != nil {
-					log.Printf("[ERROR] failed to update reminder %s: %v", r, err)
-				}
+		logging.Printf(ctx, "[INFO] notifier enqueued advance notice of reminder %d to user %d in chat %d by %s",
+			r.ID, r.UserID, r.ChatID, messagesChannels(msgs))
+	}
+}
+
+// getSettings returns user's settings. Reminder must be sent anyway, so defaults are used on error.
+func (n *Notifier) getSettings(ctx context.Context, userID int64) domain.UserSettings {
+	settings, err := n.storage.GetUserSettings(ctx, userID)
+	if err != nil {
+		logging.Printf(ctx, "[WARN] failed to fetch settings of user %d, default settings are used: %v", userID, err)
+		return domain.UserSettings{UserID: userID}
+	}
+
+	return settings
+}
+
+// newMessages renders notification of reminder for every its channel. Channels which aren't configured
+// or have no user's address are skipped, advance notice falls back to Telegram if no channel is left, so it's never lost.
+// Reminder is always sent to Telegram too: it's done or snoozed with buttons of Telegram message.
+// Telegram message is rendered from response, other channels get [domain.Notification], webhook gets its secret with it.
+func (n *Notifier) newMessages(ctx context.Context, r domain.Reminder, settings domain.UserSettings, preNotice bool,
+	response sender.BotResponse, opts ...sender.BotResponseOption) ([]domain.OutboxMessage, error) {
+	var channels domain.Channels
+	for _, ch := range settings.ReminderChannels(r) {
+		switch {
+		case ch == domain.ChannelTelegram:
+		case !slices.Contains(n.cfg.Channels, ch):
+			logging.Printf(ctx, "[WARN] channel %s of reminder %d is not configured, skipped", ch, r.ID)
+			continue
+		case settings.ChannelAddress(ch) == "":
+			logging.Printf(ctx, "[WARN] user %d has no address of channel %s, skipped", r.UserID, ch)
+			continue
+		}
+		channels = append(channels, ch)
+	}
+
+	// reminder is acknowledged with buttons of Telegram message only, so it's always sent to Telegram
+	if !preNotice && !slices.Contains(channels, domain.ChannelTelegram) {
+		channels = slices.Insert(channels, 0, domain.ChannelTelegram)
+	}
+
+	if len(channels) == 0 {
+		channels = domain.Channels{domain.ChannelTelegram}
+	}
+
+	msgs := make([]domain.OutboxMessage, 0, len(channels))
+	for _, ch := range channels {
+		if ch == domain.ChannelTelegram {
+			msg, err := sender.NewOutboxMessage(response, opts...)
+			if err != nil {
+				return nil, err
 			}
+			msgs = append(msgs, msg)
+			continue
+		}
+
+		n := domain.NewNotification(r, settings.ChannelAddress(ch), preNotice, timeNowUTC())
+		if ch == domain.ChannelWebhook {
+			n.Secret = settings.WebhookSecret
+		}
+
+		payload, err := json.Marshal(n)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s notification: %w", ch, err)
 		}
+		// secret of webhook is erased from outbox if it isn't delivered
+		msgs = append(msgs, domain.OutboxMessage{ChatID: r.ChatID, Channel: ch, Payload: string(payload), Status: domain.OutboxStatusPending,
+			Secret: n.Secret != ""})
+	}
+
+	return msgs, nil
+}
+
+// messagesChannels returns channels of messages for logs.
+func messagesChannels(msgs []domain.OutboxMessage) domain.Channels {
+	channels := make(domain.Channels, 0, len(msgs))
+	for _, msg := range msgs {
+		channels = append(channels, msg.Channel)
 	}
+
+	return channels
 }
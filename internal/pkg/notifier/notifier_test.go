@@ -2,6 +2,7 @@ package notifier
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/mezk/tg-reminder/internal/pkg/domain"
 	"github.com/mezk/tg-reminder/internal/pkg/sender"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNotifier_Run(t *testing.T) {
@@ -23,21 +25,16 @@ func TestNotifier_Run(t *testing.T) {
 			chatID     int64 = 4568
 		)
 
-		senderMock := BotResponseSenderMock{
-			SendBotResponseFunc: func(response sender.BotResponse, opts ...sender.BotResponseOption) error {
-				a := assert.New(t)
-
-				a.Equal(sender.BotResponse{
-					ChatID: chatID,
-					Text:   "‼️*НАПОМИНАНИЕ*‼️\n\n*FOOBAR*\n\nСегодня 02:30\u00a0⏰\n\nЧтобы отложить напоминание используйте кнопки\u00a0🔄, расположенные ниже.",
-				}, response)
-
-				a.Len(opts, 1)
-				return nil
-			},
-		}
+		outboxMock := OutboxMock{WakeupFunc: func() {}}
 		storageMock := StorageMock{
+			GetUserSettingsFunc: func(ctx context.Context, userID int64) (domain.UserSettings, error) {
+				return domain.UserSettings{UserID: userID}, nil
+			},
+			GetDuePreNoticesFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return nil, nil
+			},
 			GetPendingRemindersFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				assert.EqualValues(t, 10, limit)
 				return []domain.Reminder{
 					{
 						ID:           reminderID,
@@ -49,144 +46,399 @@ func TestNotifier_Run(t *testing.T) {
 					},
 				}, nil
 			},
-			UpdateReminderFunc: func(ctx context.Context, reminder domain.Reminder) error {
+			NotifyReminderFunc: func(ctx context.Context, reminder domain.Reminder, msgs []domain.OutboxMessage) error {
+				require.Len(t, msgs, 1)
+				msg := msgs[0]
+				assert.Equal(t, domain.ChannelTelegram, msg.Channel)
 				assert.Equal(t, reminderID, reminder.ID)
 				assert.Equal(t, chatID, reminder.ChatID)
 				assert.Equal(t, userID, reminder.UserID)
 				assert.EqualValues(t, 2, reminder.AttemptsLeft)
 				assert.Equal(t, domain.ReminderStatusPending, reminder.Status)
 				assert.WithinDuration(t, timeNowUTC().Add(15*time.Minute), reminder.RemindAt, 1*time.Second)
+				assert.Equal(t, domain.Actor{Type: domain.ActorNotifier}, domain.ActorFromContext(ctx))
+
+				assert.Equal(t, chatID, msg.ChatID)
+				assert.Equal(t, domain.OutboxStatusPending, msg.Status)
+				sent := decodeMessage(t, msg)
+				assert.Equal(t, "‼️*НАПОМИНАНИЕ*‼️\n\n*FOOBAR*\n\nСегодня 02:30 ⏰\n\nЧтобы отложить напоминание используйте кнопки 🔄, расположенные ниже.", sent.Text)
+				assert.NotNil(t, sent.ReplyMarkup, "snooze and done buttons")
+				assert.False(t, sent.DisableNotification)
+				assert.False(t, sent.Pin)
 				return nil
 			},
 		}
 
-		notifierImpl := New(&senderMock, &storageMock, 300*time.Millisecond)
+		notifierImpl := New(&outboxMock, &storageMock, Config{Interval: 300 * time.Millisecond, BatchSize: 10})
 
 		ctx, cancel := context.WithTimeout(context.TODO(), 400*time.Millisecond)
 		defer cancel()
 
 		notifierImpl.Run(ctx)
+
+		assert.Len(t, storageMock.NotifyReminderCalls(), 1)
+		assert.Len(t, outboxMock.WakeupCalls(), 1)
 	})
 
-	t.Run("error: context canceled", func(t *testing.T) {
+	t.Run("success: low priority", func(t *testing.T) {
 		t.Parallel()
 
+		const reminderID int64 = 436746
+
+		storageMock := StorageMock{
+			GetUserSettingsFunc: func(ctx context.Context, userID int64) (domain.UserSettings, error) {
+				return domain.UserSettings{UserID: userID}, nil
+			},
+			GetDuePreNoticesFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return nil, nil
+			},
+			GetPendingRemindersFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return []domain.Reminder{
+					{
+						ID:           reminderID,
+						Text:         "FooBar",
+						Status:       domain.ReminderStatusPending,
+						AttemptsLeft: 1,
+						Priority:     domain.ReminderPriorityLow,
+					},
+				}, nil
+			},
+			NotifyReminderFunc: func(ctx context.Context, reminder domain.Reminder, msgs []domain.OutboxMessage) error {
+				require.Len(t, msgs, 1)
+				msg := msgs[0]
+				assert.Equal(t, domain.ChannelTelegram, msg.Channel)
+				assert.Equal(t, reminderID, reminder.ID)
+				assert.EqualValues(t, 0, reminder.AttemptsLeft)
+				assert.Equal(t, domain.ReminderStatusAttemptsExhausted, reminder.Status)
+
+				sent := decodeMessage(t, msg)
+				assert.Equal(t, "‼️*НАПОМИНАНИЕ*‼️\n\n*FOOBAR*\n\n🔽 Низкий приоритет\n\nСегодня 02:30 ⏰\n\nЧтобы отложить напоминание используйте кнопки 🔄, расположенные ниже.", sent.Text)
+				assert.True(t, sent.DisableNotification)
+				assert.False(t, sent.Pin)
+				return nil
+			},
+		}
+
+		notifierImpl := New(&OutboxMock{WakeupFunc: func() {}}, &storageMock, Config{Interval: 300 * time.Millisecond, BatchSize: 100})
+
 		ctx, cancel := context.WithTimeout(context.TODO(), 400*time.Millisecond)
-		cancel()
+		defer cancel()
 
-		notifierImpl := New(nil, nil, 300*time.Millisecond)
 		notifierImpl.Run(ctx)
+
+		assert.Len(t, storageMock.NotifyReminderCalls(), 1)
 	})
 
-	t.Run("error: can't get pending reminders", func(t *testing.T) {
+	t.Run("success: critical priority", func(t *testing.T) {
 		t.Parallel()
 
+		const reminderID int64 = 436747
+
 		storageMock := StorageMock{
+			GetUserSettingsFunc: func(ctx context.Context, userID int64) (domain.UserSettings, error) {
+				return domain.UserSettings{UserID: userID}, nil
+			},
+			GetDuePreNoticesFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return nil, nil
+			},
 			GetPendingRemindersFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
-				return nil, errors.New("some error")
+				return []domain.Reminder{
+					{
+						ID:           reminderID,
+						Text:         "FooBar",
+						Status:       domain.ReminderStatusPending,
+						AttemptsLeft: 20,
+						Priority:     domain.ReminderPriorityCritical,
+					},
+				}, nil
+			},
+			NotifyReminderFunc: func(ctx context.Context, reminder domain.Reminder, msgs []domain.OutboxMessage) error {
+				require.Len(t, msgs, 1)
+				msg := msgs[0]
+				assert.Equal(t, domain.ChannelTelegram, msg.Channel)
+				assert.Equal(t, reminderID, reminder.ID)
+				assert.EqualValues(t, 19, reminder.AttemptsLeft)
+				assert.Equal(t, domain.ReminderStatusPending, reminder.Status)
+				assert.WithinDuration(t, timeNowUTC().Add(5*time.Minute), reminder.RemindAt, 1*time.Second)
+
+				sent := decodeMessage(t, msg)
+				assert.Equal(t, "‼️*НАПОМИНАНИЕ*‼️\n\n*FOOBAR*\n\n🚨 Критический приоритет\n\nСегодня 02:30 ⏰\n\nЧтобы отложить напоминание используйте кнопки 🔄, расположенные ниже.", sent.Text)
+				assert.True(t, sent.Pin)
+				assert.False(t, sent.DisableNotification)
+				return nil
 			},
 		}
 
-		notifierImpl := New(nil, &storageMock, 300*time.Millisecond)
+		notifierImpl := New(&OutboxMock{WakeupFunc: func() {}}, &storageMock, Config{Interval: 300 * time.Millisecond, BatchSize: 100})
 
 		ctx, cancel := context.WithTimeout(context.TODO(), 400*time.Millisecond)
 		defer cancel()
 
 		notifierImpl.Run(ctx)
+
+		assert.Len(t, storageMock.NotifyReminderCalls(), 1)
 	})
 
-	t.Run("error: can't send bot response", func(t *testing.T) {
+	t.Run("success: checklist", func(t *testing.T) {
 		t.Parallel()
 
-		const (
-			reminderID int64 = 436745
-			userID     int64 = 4357
-			chatID     int64 = 4568
-		)
+		const reminderID int64 = 436749
 
-		senderMock := BotResponseSenderMock{
-			SendBotResponseFunc: func(response sender.BotResponse, opts ...sender.BotResponseOption) error {
-				return errors.New("some error")
-			},
-		}
 		storageMock := StorageMock{
+			GetUserSettingsFunc: func(ctx context.Context, userID int64) (domain.UserSettings, error) {
+				return domain.UserSettings{UserID: userID}, nil
+			},
+			GetDuePreNoticesFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return nil, nil
+			},
 			GetPendingRemindersFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
 				return []domain.Reminder{
 					{
 						ID:           reminderID,
-						ChatID:       chatID,
-						UserID:       userID,
 						Text:         "FooBar",
 						Status:       domain.ReminderStatusPending,
-						AttemptsLeft: 3,
+						AttemptsLeft: 20,
+						Checklist: domain.Checklist{
+							{ID: 1, ReminderID: reminderID, Text: "Foo", Done: true},
+							{ID: 2, ReminderID: reminderID, Text: "Bar"},
+						},
 					},
 				}, nil
 			},
-			UpdateReminderFunc: func(ctx context.Context, reminder domain.Reminder) error {
+			NotifyReminderFunc: func(ctx context.Context, reminder domain.Reminder, msgs []domain.OutboxMessage) error {
+				require.Len(t, msgs, 1)
+				msg := msgs[0]
+				assert.Equal(t, domain.ChannelTelegram, msg.Channel)
 				assert.Equal(t, reminderID, reminder.ID)
-				assert.Equal(t, chatID, reminder.ChatID)
-				assert.Equal(t, userID, reminder.UserID)
-				assert.EqualValues(t, 2, reminder.AttemptsLeft)
-				assert.Equal(t, domain.ReminderStatusPending, reminder.Status)
-				assert.WithinDuration(t, timeNowUTC().Add(15*time.Minute), reminder.RemindAt, 1*time.Second)
+
+				sent := decodeMessage(t, msg)
+				assert.Equal(t, "‼️*НАПОМИНАНИЕ*‼️\n\n*FOOBAR*\n\n📋 Выполнено 1/2\n\nСегодня 02:30 ⏰\n\nЧтобы отложить напоминание используйте кнопки 🔄, расположенные ниже.", sent.Text)
+				require.NotNil(t, sent.ReplyMarkup)
+				assert.Equal(t, "✅ Foo", sent.ReplyMarkup.InlineKeyboard[0][0].Text)
+				assert.Equal(t, "⬜ Bar", sent.ReplyMarkup.InlineKeyboard[1][0].Text)
 				return nil
 			},
 		}
 
-		notifierImpl := New(&senderMock, &storageMock, 300*time.Millisecond)
+		notifierImpl := New(&OutboxMock{WakeupFunc: func() {}}, &storageMock, Config{Interval: 300 * time.Millisecond, BatchSize: 100})
 
 		ctx, cancel := context.WithTimeout(context.TODO(), 400*time.Millisecond)
 		defer cancel()
 
 		notifierImpl.Run(ctx)
+
+		assert.Len(t, storageMock.NotifyReminderCalls(), 1)
 	})
 
-	t.Run("error: can't update reminder", func(t *testing.T) {
+	t.Run("success: advance notice", func(t *testing.T) {
 		t.Parallel()
 
-		const (
-			reminderID int64 = 436745
-			userID     int64 = 4357
-			chatID     int64 = 4568
-		)
+		const reminderID int64 = 436748
+
+		remindAt := timeNowUTC().Add(30 * time.Minute)
+
+		storageMock := StorageMock{
+			GetUserSettingsFunc: func(ctx context.Context, userID int64) (domain.UserSettings, error) {
+				return domain.UserSettings{UserID: userID}, nil
+			},
+			GetDuePreNoticesFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return []domain.Reminder{
+					{
+						ID:           reminderID,
+						Text:         "Meeting",
+						RemindAt:     remindAt,
+						Status:       domain.ReminderStatusPending,
+						AttemptsLeft: 10,
+						LeadTimes:    domain.LeadTimes{time.Hour, 30 * time.Minute, 10 * time.Minute},
+					},
+				}, nil
+			},
+			NotifyPreNoticeFunc: func(ctx context.Context, id int64, nextPreNoticeAt *time.Time, msgs []domain.OutboxMessage) error {
+				require.Len(t, msgs, 1)
+				msg := msgs[0]
+				assert.Equal(t, domain.ChannelTelegram, msg.Channel)
+				assert.Equal(t, reminderID, id)
+				assert.Equal(t, remindAt.Add(-10*time.Minute), *nextPreNoticeAt)
+
+				sent := decodeMessage(t, msg)
+				assert.Contains(t, sent.Text, "🔔 *Скоро напоминание*\n\n*Meeting*\n\nЧерез 30 мин.")
+				assert.Nil(t, sent.ReplyMarkup)
+				assert.False(t, sent.DisableNotification)
+				return nil
+			},
+			GetPendingRemindersFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return nil, nil
+			},
+		}
+
+		notifierImpl := New(&OutboxMock{WakeupFunc: func() {}}, &storageMock, Config{Interval: 300 * time.Millisecond, BatchSize: 100})
 
-		senderMock := BotResponseSenderMock{
-			SendBotResponseFunc: func(response sender.BotResponse, opts ...sender.BotResponseOption) error {
-				a := assert.New(t)
+		ctx, cancel := context.WithTimeout(context.TODO(), 400*time.Millisecond)
+		defer cancel()
+
+		notifierImpl.Run(ctx)
+
+		assert.Len(t, storageMock.NotifyPreNoticeCalls(), 1)
+		assert.Empty(t, storageMock.NotifyReminderCalls())
+	})
+
+	t.Run("success: user settings are fetched once per tick, can't get user settings", func(t *testing.T) {
+		t.Parallel()
 
-				a.Equal(sender.BotResponse{
-					ChatID: chatID,
-					Text:   "‼️*НАПОМИНАНИЕ*‼️\n\n*FOOBAR*\n\nСегодня 02:30\u00a0⏰\n\nЧтобы отложить напоминание используйте кнопки\u00a0🔄, расположенные ниже.",
-				}, response)
+		const userID int64 = 6583
 
-				a.Len(opts, 1)
+		storageMock := StorageMock{
+			GetUserSettingsFunc: func(ctx context.Context, userID int64) (domain.UserSettings, error) {
+				return domain.UserSettings{}, errors.New("unexpected error")
+			},
+			GetDuePreNoticesFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return nil, nil
+			},
+			GetPendingRemindersFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return []domain.Reminder{
+					{ID: 1, ChatID: 1, UserID: userID, Text: "Foo", Status: domain.ReminderStatusPending, AttemptsLeft: 3},
+					{ID: 2, ChatID: 1, UserID: userID, Text: "Bar", Status: domain.ReminderStatusPending, AttemptsLeft: 3},
+				}, nil
+			},
+			NotifyReminderFunc: func(ctx context.Context, reminder domain.Reminder, msgs []domain.OutboxMessage) error {
 				return nil
 			},
 		}
+
+		notifierImpl := New(&OutboxMock{WakeupFunc: func() {}}, &storageMock, Config{Interval: 300 * time.Millisecond, BatchSize: 100})
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 400*time.Millisecond)
+		defer cancel()
+
+		notifierImpl.Run(ctx)
+
+		// reminders are sent with default snooze options
+		assert.Len(t, storageMock.GetUserSettingsCalls(), 1)
+		assert.Len(t, storageMock.NotifyReminderCalls(), 2)
+	})
+
+	t.Run("error: can't get advance notices", func(t *testing.T) {
+		t.Parallel()
+
 		storageMock := StorageMock{
+			GetUserSettingsFunc: func(ctx context.Context, userID int64) (domain.UserSettings, error) {
+				return domain.UserSettings{UserID: userID}, nil
+			},
+			GetDuePreNoticesFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return nil, errors.New("some error")
+			},
+			GetPendingRemindersFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return nil, nil
+			},
+		}
+
+		notifierImpl := New(&OutboxMock{WakeupFunc: func() {}}, &storageMock, Config{Interval: 300 * time.Millisecond, BatchSize: 100})
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 400*time.Millisecond)
+		defer cancel()
+
+		notifierImpl.Run(ctx)
+
+		assert.Len(t, storageMock.GetPendingRemindersCalls(), 1)
+	})
+
+	t.Run("error: context canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 400*time.Millisecond)
+		cancel()
+
+		notifierImpl := New(nil, nil, Config{Interval: 300 * time.Millisecond, BatchSize: 100})
+		notifierImpl.Run(ctx)
+	})
+
+	t.Run("error: can't get pending reminders", func(t *testing.T) {
+		t.Parallel()
+
+		storageMock := StorageMock{
+			GetUserSettingsFunc: func(ctx context.Context, userID int64) (domain.UserSettings, error) {
+				return domain.UserSettings{UserID: userID}, nil
+			},
+			GetDuePreNoticesFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return nil, nil
+			},
+			GetPendingRemindersFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return nil, errors.New("some error")
+			},
+		}
+
+		notifierImpl := New(&OutboxMock{WakeupFunc: func() {}}, &storageMock, Config{Interval: 300 * time.Millisecond, BatchSize: 100})
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 400*time.Millisecond)
+		defer cancel()
+
+		notifierImpl.Run(ctx)
+	})
+
+	t.Run("error: can't notify reminder, the next reminder is notified", func(t *testing.T) {
+		t.Parallel()
+
+		storageMock := StorageMock{
+			GetUserSettingsFunc: func(ctx context.Context, userID int64) (domain.UserSettings, error) {
+				return domain.UserSettings{UserID: userID}, nil
+			},
+			GetDuePreNoticesFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return nil, nil
+			},
 			GetPendingRemindersFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
 				return []domain.Reminder{
-					{
-						ID:           reminderID,
-						ChatID:       chatID,
-						UserID:       userID,
-						Text:         "FooBar",
-						Status:       domain.ReminderStatusPending,
-						AttemptsLeft: 3,
-					},
+					{ID: 1, ChatID: 1, UserID: 1, Text: "Foo", Status: domain.ReminderStatusPending, AttemptsLeft: 3},
+					{ID: 2, ChatID: 1, UserID: 1, Text: "Bar", Status: domain.ReminderStatusPending, AttemptsLeft: 3},
+				}, nil
+			},
+			NotifyReminderFunc: func(ctx context.Context, reminder domain.Reminder, msgs []domain.OutboxMessage) error {
+				if reminder.ID == 1 {
+					return errors.New("some error")
+				}
+				return nil
+			},
+		}
+
+		notifierImpl := New(&OutboxMock{WakeupFunc: func() {}}, &storageMock, Config{Interval: 300 * time.Millisecond, BatchSize: 100})
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 400*time.Millisecond)
+		defer cancel()
+
+		notifierImpl.Run(ctx)
+
+		assert.Len(t, storageMock.NotifyReminderCalls(), 2)
+	})
+
+	t.Run("error: can't notify advance notice", func(t *testing.T) {
+		t.Parallel()
+
+		storageMock := StorageMock{
+			GetUserSettingsFunc: func(ctx context.Context, userID int64) (domain.UserSettings, error) {
+				return domain.UserSettings{UserID: userID}, nil
+			},
+			GetDuePreNoticesFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return []domain.Reminder{
+					{ID: 1, Text: "Meeting", RemindAt: timeNowUTC().Add(time.Hour), LeadTimes: domain.LeadTimes{time.Hour}},
 				}, nil
 			},
-			UpdateReminderFunc: func(ctx context.Context, reminder domain.Reminder) error {
+			NotifyPreNoticeFunc: func(ctx context.Context, id int64, nextPreNoticeAt *time.Time, msgs []domain.OutboxMessage) error {
+				assert.Nil(t, nextPreNoticeAt)
 				return errors.New("some error")
 			},
+			GetPendingRemindersFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return nil, nil
+			},
 		}
 
-		notifierImpl := New(&senderMock, &storageMock, 300*time.Millisecond)
+		notifierImpl := New(&OutboxMock{WakeupFunc: func() {}}, &storageMock, Config{Interval: 300 * time.Millisecond, BatchSize: 100})
 
 		ctx, cancel := context.WithTimeout(context.TODO(), 400*time.Millisecond)
 		defer cancel()
 
 		notifierImpl.Run(ctx)
+
+		assert.Len(t, storageMock.NotifyPreNoticeCalls(), 1)
+		assert.Len(t, storageMock.GetPendingRemindersCalls(), 1)
 	})
 
 	t.Run("success: attempts exhausted", func(t *testing.T) {
@@ -198,20 +450,13 @@ func TestNotifier_Run(t *testing.T) {
 			chatID     int64 = 4568
 		)
 
-		senderMock := BotResponseSenderMock{
-			SendBotResponseFunc: func(response sender.BotResponse, opts ...sender.BotResponseOption) error {
-				a := assert.New(t)
-
-				a.Equal(sender.BotResponse{
-					ChatID: chatID,
-					Text:   "‼️*НАПОМИНАНИЕ*‼️\n\n*FOOBAR*\n\nСегодня 02:30\u00a0⏰\n\nЧтобы отложить напоминание используйте кнопки\u00a0🔄, расположенные ниже.",
-				}, response)
-
-				a.Len(opts, 1)
-				return nil
-			},
-		}
 		storageMock := StorageMock{
+			GetUserSettingsFunc: func(ctx context.Context, userID int64) (domain.UserSettings, error) {
+				return domain.UserSettings{UserID: userID}, nil
+			},
+			GetDuePreNoticesFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return nil, nil
+			},
 			GetPendingRemindersFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
 				return []domain.Reminder{
 					{
@@ -224,23 +469,179 @@ func TestNotifier_Run(t *testing.T) {
 					},
 				}, nil
 			},
-			UpdateReminderFunc: func(ctx context.Context, reminder domain.Reminder) error {
+			NotifyReminderFunc: func(ctx context.Context, reminder domain.Reminder, msgs []domain.OutboxMessage) error {
+				require.Len(t, msgs, 1)
+				msg := msgs[0]
+				assert.Equal(t, domain.ChannelTelegram, msg.Channel)
 				assert.Equal(t, reminderID, reminder.ID)
 				assert.Equal(t, chatID, reminder.ChatID)
 				assert.Equal(t, userID, reminder.UserID)
 				assert.Zero(t, reminder.AttemptsLeft)
 				assert.Equal(t, domain.ReminderStatusAttemptsExhausted, reminder.Status)
 				assert.WithinDuration(t, timeNowUTC().Add(15*time.Minute), reminder.RemindAt, 1*time.Second)
+				assert.Equal(t, chatID, msg.ChatID)
 				return nil
 			},
 		}
 
-		notifierImpl := New(&senderMock, &storageMock, 300*time.Millisecond)
+		notifierImpl := New(&OutboxMock{WakeupFunc: func() {}}, &storageMock, Config{Interval: 300 * time.Millisecond, BatchSize: 100})
 
 		ctx, cancel := context.WithTimeout(context.TODO(), 400*time.Millisecond)
 		defer cancel()
 
 		notifierImpl.Run(ctx)
+
+		assert.Len(t, storageMock.NotifyReminderCalls(), 1)
+	})
+	t.Run("success: fan out to channels", func(t *testing.T) {
+		t.Parallel()
+
+		storageMock := StorageMock{
+			GetUserSettingsFunc: func(ctx context.Context, userID int64) (domain.UserSettings, error) {
+				return domain.UserSettings{
+					UserID:        userID,
+					Channels:      domain.Channels{domain.ChannelTelegram, domain.ChannelEmail, domain.ChannelPush, domain.ChannelWebhook},
+					Email:         "user@example.com",
+					EmailVerified: true,
+					WebhookURL:    "https://example.com/hook",
+					WebhookSecret: "whsec_test",
+					PushTopic:     "reminders",
+				}, nil
+			},
+			GetDuePreNoticesFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return nil, nil
+			},
+			GetPendingRemindersFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return []domain.Reminder{
+					{ID: 1, ChatID: 2, UserID: 3, Text: "Meeting", Status: domain.ReminderStatusPending, AttemptsLeft: 3, Priority: domain.ReminderPriorityHigh},
+				}, nil
+			},
+			NotifyReminderFunc: func(ctx context.Context, reminder domain.Reminder, msgs []domain.OutboxMessage) error {
+				// push isn't configured
+				require.Len(t, msgs, 3)
+				assert.Equal(t, domain.ChannelTelegram, msgs[0].Channel)
+				assert.Equal(t, domain.ChannelEmail, msgs[1].Channel)
+				assert.Equal(t, int64(2), msgs[1].ChatID)
+				assert.Equal(t, domain.OutboxStatusPending, msgs[1].Status)
+
+				var n domain.Notification
+				require.NoError(t, json.Unmarshal([]byte(msgs[1].Payload), &n))
+				assert.Equal(t, "user@example.com", n.To)
+				assert.Equal(t, int64(1), n.ReminderID)
+				assert.Equal(t, "Напоминание", n.Title)
+				assert.Contains(t, n.Message, "Meeting")
+				assert.Equal(t, domain.ReminderPriorityHigh, n.Priority)
+				assert.False(t, n.PreNotice)
+				assert.Empty(t, n.Secret)
+				assert.False(t, msgs[1].Secret)
+
+				// secret of webhook is sent with its notification only
+				assert.Equal(t, domain.ChannelWebhook, msgs[2].Channel)
+				require.NoError(t, json.Unmarshal([]byte(msgs[2].Payload), &n))
+				assert.Equal(t, "https://example.com/hook", n.To)
+				assert.Equal(t, "whsec_test", n.Secret)
+				assert.True(t, msgs[2].Secret)
+				return nil
+			},
+		}
+
+		notifierImpl := New(&OutboxMock{WakeupFunc: func() {}}, &storageMock, Config{
+			Interval:  300 * time.Millisecond,
+			BatchSize: 100,
+			Channels:  domain.Channels{domain.ChannelTelegram, domain.ChannelEmail, domain.ChannelWebhook},
+		})
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 400*time.Millisecond)
+		defer cancel()
+
+		notifierImpl.Run(ctx)
+
+		assert.Len(t, storageMock.NotifyReminderCalls(), 1)
 	})
 
+	t.Run("success: reminder without telegram channel is sent to telegram too", func(t *testing.T) {
+		t.Parallel()
+
+		storageMock := StorageMock{
+			GetUserSettingsFunc: func(ctx context.Context, userID int64) (domain.UserSettings, error) {
+				return domain.UserSettings{UserID: userID, Channels: domain.Channels{domain.ChannelEmail}, Email: "user@example.com", EmailVerified: true}, nil
+			},
+			GetDuePreNoticesFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return nil, nil
+			},
+			GetPendingRemindersFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return []domain.Reminder{
+					{ID: 1, ChatID: 2, UserID: 3, Text: "Meeting", Status: domain.ReminderStatusPending, AttemptsLeft: 3, Priority: domain.ReminderPriorityHigh},
+				}, nil
+			},
+			NotifyReminderFunc: func(ctx context.Context, reminder domain.Reminder, msgs []domain.OutboxMessage) error {
+				// reminder is done with buttons of telegram message
+				require.Len(t, msgs, 2)
+				assert.Equal(t, domain.ChannelTelegram, msgs[0].Channel)
+				assert.NotNil(t, decodeMessage(t, msgs[0]).ReplyMarkup)
+				assert.Equal(t, domain.ChannelEmail, msgs[1].Channel)
+				return nil
+			},
+		}
+
+		notifierImpl := New(&OutboxMock{WakeupFunc: func() {}}, &storageMock, Config{
+			Interval:  300 * time.Millisecond,
+			BatchSize: 100,
+			Channels:  domain.AllChannels,
+		})
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 400*time.Millisecond)
+		defer cancel()
+
+		notifierImpl.Run(ctx)
+
+		assert.Len(t, storageMock.NotifyReminderCalls(), 1)
+	})
+
+	t.Run("success: reminder channels, fall back to telegram", func(t *testing.T) {
+		t.Parallel()
+
+		storageMock := StorageMock{
+			GetUserSettingsFunc: func(ctx context.Context, userID int64) (domain.UserSettings, error) {
+				return domain.UserSettings{UserID: userID, Channels: domain.Channels{domain.ChannelEmail}, Email: "user@example.com", EmailVerified: true}, nil
+			},
+			GetDuePreNoticesFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return []domain.Reminder{
+					{ID: 1, ChatID: 2, UserID: 3, Text: "Meeting", RemindAt: timeNowUTC().Add(time.Hour), Channels: domain.Channels{domain.ChannelWebhook}},
+				}, nil
+			},
+			NotifyPreNoticeFunc: func(ctx context.Context, id int64, nextPreNoticeAt *time.Time, msgs []domain.OutboxMessage) error {
+				// reminder's own channel overrides user's defaults, but user has no webhook url
+				require.Len(t, msgs, 1)
+				assert.Equal(t, domain.ChannelTelegram, msgs[0].Channel)
+				assert.Contains(t, decodeMessage(t, msgs[0]).Text, "Скоро напоминание")
+				return nil
+			},
+			GetPendingRemindersFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+				return nil, nil
+			},
+		}
+
+		notifierImpl := New(&OutboxMock{WakeupFunc: func() {}}, &storageMock, Config{
+			Interval:  300 * time.Millisecond,
+			BatchSize: 100,
+			Channels:  domain.AllChannels,
+		})
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 400*time.Millisecond)
+		defer cancel()
+
+		notifierImpl.Run(ctx)
+
+		assert.Len(t, storageMock.NotifyPreNoticeCalls(), 1)
+	})
+}
+
+func decodeMessage(t *testing.T, msg domain.OutboxMessage) sender.Message {
+	t.Helper()
+
+	decoded, err := sender.DecodeOutboxMessage(msg)
+	require.NoError(t, err)
+
+	return decoded
 }
@@ -0,0 +1,81 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package notifier
+
+import (
+	"sync"
+)
+
+// Ensure, that OutboxMock does implement Outbox.
+// If this is not the case, regenerate this file with moq.
+var _ Outbox = &OutboxMock{}
+
+// OutboxMock is a mock implementation of Outbox.
+//
+//	func TestSomethingThatUsesOutbox(t *testing.T) {
+//
+//		// make and configure a mocked Outbox
+//		mockedOutbox := &OutboxMock{
+//			WakeupFunc: func()  {
+//				panic("mock out the Wakeup method")
+//			},
+//		}
+//
+//		// use mockedOutbox in code that requires Outbox
+//		// and then make assertions.
+//
+//	}
+type OutboxMock struct {
+	// WakeupFunc mocks the Wakeup method.
+	WakeupFunc func()
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Wakeup holds details about calls to the Wakeup method.
+		Wakeup []struct {
+		}
+	}
+	lockWakeup sync.RWMutex
+}
+
+// Wakeup calls WakeupFunc.
+func (mock *OutboxMock) Wakeup() {
+	if mock.WakeupFunc == nil {
+		panic("OutboxMock.WakeupFunc: method is nil but Outbox.Wakeup was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockWakeup.Lock()
+	mock.calls.Wakeup = append(mock.calls.Wakeup, callInfo)
+	mock.lockWakeup.Unlock()
+	mock.WakeupFunc()
+}
+
+// WakeupCalls gets all the calls that were made to Wakeup.
+// Check the length with:
+//
+//	len(mockedOutbox.WakeupCalls())
+func (mock *OutboxMock) WakeupCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockWakeup.RLock()
+	calls = mock.calls.Wakeup
+	mock.lockWakeup.RUnlock()
+	return calls
+}
+
+// ResetWakeupCalls reset all the calls that were made to Wakeup.
+func (mock *OutboxMock) ResetWakeupCalls() {
+	mock.lockWakeup.Lock()
+	mock.calls.Wakeup = nil
+	mock.lockWakeup.Unlock()
+}
+
+// ResetCalls reset all the calls that were made to all mocked methods.
+func (mock *OutboxMock) ResetCalls() {
+	mock.lockWakeup.Lock()
+	mock.calls.Wakeup = nil
+	mock.lockWakeup.Unlock()
+}
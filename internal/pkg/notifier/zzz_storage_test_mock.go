@@ -7,6 +7,7 @@ import (
 	"context"
 	"github.com/mezk/tg-reminder/internal/pkg/domain"
 	"sync"
+	"time"
 )
 
 // Ensure, that StorageMock does implement Storage.
@@ -19,11 +20,26 @@ var _ Storage = &StorageMock{}
 //
 //		// make and configure a mocked Storage
 //		mockedStorage := &StorageMock{
+//			GetDuePreNoticesFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+//				panic("mock out the GetDuePreNotices method")
+//			},
+//			GetMissedRemindersFunc: func(ctx context.Context, userID int64, before time.Time) ([]domain.Reminder, error) {
+//				panic("mock out the GetMissedReminders method")
+//			},
 //			GetPendingRemindersFunc: func(ctx context.Context, limit int64) ([]domain.Reminder, error) {
 //				panic("mock out the GetPendingReminders method")
 //			},
-//			UpdateReminderFunc: func(ctx context.Context, reminder domain.Reminder) error {
-//				panic("mock out the UpdateReminder method")
+//			GetUserSettingsFunc: func(ctx context.Context, userID int64) (domain.UserSettings, error) {
+//				panic("mock out the GetUserSettings method")
+//			},
+//			MissRemindersFunc: func(ctx context.Context, ids []int64, summary *domain.MissedSummary) error {
+//				panic("mock out the MissReminders method")
+//			},
+//			NotifyPreNoticeFunc: func(ctx context.Context, id int64, nextPreNoticeAt *time.Time, msgs []domain.OutboxMessage) error {
+//				panic("mock out the NotifyPreNotice method")
+//			},
+//			NotifyReminderFunc: func(ctx context.Context, reminder domain.Reminder, msgs []domain.OutboxMessage) error {
+//				panic("mock out the NotifyReminder method")
 //			},
 //		}
 //
@@ -32,14 +48,45 @@ var _ Storage = &StorageMock{}
 //
 //	}
 type StorageMock struct {
+	// GetDuePreNoticesFunc mocks the GetDuePreNotices method.
+	GetDuePreNoticesFunc func(ctx context.Context, limit int64) ([]domain.Reminder, error)
+
+	// GetMissedRemindersFunc mocks the GetMissedReminders method.
+	GetMissedRemindersFunc func(ctx context.Context, userID int64, before time.Time) ([]domain.Reminder, error)
+
 	// GetPendingRemindersFunc mocks the GetPendingReminders method.
 	GetPendingRemindersFunc func(ctx context.Context, limit int64) ([]domain.Reminder, error)
 
-	// UpdateReminderFunc mocks the UpdateReminder method.
-	UpdateReminderFunc func(ctx context.Context, reminder domain.Reminder) error
+	// GetUserSettingsFunc mocks the GetUserSettings method.
+	GetUserSettingsFunc func(ctx context.Context, userID int64) (domain.UserSettings, error)
+
+	// MissRemindersFunc mocks the MissReminders method.
+	MissRemindersFunc func(ctx context.Context, ids []int64, summary *domain.MissedSummary) error
+
+	// NotifyPreNoticeFunc mocks the NotifyPreNotice method.
+	NotifyPreNoticeFunc func(ctx context.Context, id int64, nextPreNoticeAt *time.Time, msgs []domain.OutboxMessage) error
+
+	// NotifyReminderFunc mocks the NotifyReminder method.
+	NotifyReminderFunc func(ctx context.Context, reminder domain.Reminder, msgs []domain.OutboxMessage) error
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// GetDuePreNotices holds details about calls to the GetDuePreNotices method.
+		GetDuePreNotices []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Limit is the limit argument value.
+			Limit int64
+		}
+		// GetMissedReminders holds details about calls to the GetMissedReminders method.
+		GetMissedReminders []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+			// Before is the before argument value.
+			Before time.Time
+		}
 		// GetPendingReminders holds details about calls to the GetPendingReminders method.
 		GetPendingReminders []struct {
 			// Ctx is the ctx argument value.
@@ -47,16 +94,140 @@ type StorageMock struct {
 			// Limit is the limit argument value.
 			Limit int64
 		}
-		// UpdateReminder holds details about calls to the UpdateReminder method.
-		UpdateReminder []struct {
+		// GetUserSettings holds details about calls to the GetUserSettings method.
+		GetUserSettings []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int64
+		}
+		// MissReminders holds details about calls to the MissReminders method.
+		MissReminders []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Ids is the ids argument value.
+			Ids []int64
+			// Summary is the summary argument value.
+			Summary *domain.MissedSummary
+		}
+		// NotifyPreNotice holds details about calls to the NotifyPreNotice method.
+		NotifyPreNotice []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int64
+			// NextPreNoticeAt is the nextPreNoticeAt argument value.
+			NextPreNoticeAt *time.Time
+			// Msgs is the msgs argument value.
+			Msgs []domain.OutboxMessage
+		}
+		// NotifyReminder holds details about calls to the NotifyReminder method.
+		NotifyReminder []struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
 			// Reminder is the reminder argument value.
 			Reminder domain.Reminder
+			// Msgs is the msgs argument value.
+			Msgs []domain.OutboxMessage
 		}
 	}
+	lockGetDuePreNotices    sync.RWMutex
+	lockGetMissedReminders  sync.RWMutex
 	lockGetPendingReminders sync.RWMutex
-	lockUpdateReminder      sync.RWMutex
+	lockGetUserSettings     sync.RWMutex
+	lockMissReminders       sync.RWMutex
+	lockNotifyPreNotice     sync.RWMutex
+	lockNotifyReminder      sync.RWMutex
+}
+
+// GetDuePreNotices calls GetDuePreNoticesFunc.
+func (mock *StorageMock) GetDuePreNotices(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+	if mock.GetDuePreNoticesFunc == nil {
+		panic("StorageMock.GetDuePreNoticesFunc: method is nil but Storage.GetDuePreNotices was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Limit int64
+	}{
+		Ctx:   ctx,
+		Limit: limit,
+	}
+	mock.lockGetDuePreNotices.Lock()
+	mock.calls.GetDuePreNotices = append(mock.calls.GetDuePreNotices, callInfo)
+	mock.lockGetDuePreNotices.Unlock()
+	return mock.GetDuePreNoticesFunc(ctx, limit)
+}
+
+// GetDuePreNoticesCalls gets all the calls that were made to GetDuePreNotices.
+// Check the length with:
+//
+//	len(mockedStorage.GetDuePreNoticesCalls())
+func (mock *StorageMock) GetDuePreNoticesCalls() []struct {
+	Ctx   context.Context
+	Limit int64
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Limit int64
+	}
+	mock.lockGetDuePreNotices.RLock()
+	calls = mock.calls.GetDuePreNotices
+	mock.lockGetDuePreNotices.RUnlock()
+	return calls
+}
+
+// ResetGetDuePreNoticesCalls reset all the calls that were made to GetDuePreNotices.
+func (mock *StorageMock) ResetGetDuePreNoticesCalls() {
+	mock.lockGetDuePreNotices.Lock()
+	mock.calls.GetDuePreNotices = nil
+	mock.lockGetDuePreNotices.Unlock()
+}
+
+// GetMissedReminders calls GetMissedRemindersFunc.
+func (mock *StorageMock) GetMissedReminders(ctx context.Context, userID int64, before time.Time) ([]domain.Reminder, error) {
+	if mock.GetMissedRemindersFunc == nil {
+		panic("StorageMock.GetMissedRemindersFunc: method is nil but Storage.GetMissedReminders was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+		Before time.Time
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Before: before,
+	}
+	mock.lockGetMissedReminders.Lock()
+	mock.calls.GetMissedReminders = append(mock.calls.GetMissedReminders, callInfo)
+	mock.lockGetMissedReminders.Unlock()
+	return mock.GetMissedRemindersFunc(ctx, userID, before)
+}
+
+// GetMissedRemindersCalls gets all the calls that were made to GetMissedReminders.
+// Check the length with:
+//
+//	len(mockedStorage.GetMissedRemindersCalls())
+func (mock *StorageMock) GetMissedRemindersCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+	Before time.Time
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+		Before time.Time
+	}
+	mock.lockGetMissedReminders.RLock()
+	calls = mock.calls.GetMissedReminders
+	mock.lockGetMissedReminders.RUnlock()
+	return calls
+}
+
+// ResetGetMissedRemindersCalls reset all the calls that were made to GetMissedReminders.
+func (mock *StorageMock) ResetGetMissedRemindersCalls() {
+	mock.lockGetMissedReminders.Lock()
+	mock.calls.GetMissedReminders = nil
+	mock.lockGetMissedReminders.Unlock()
 }
 
 // GetPendingReminders calls GetPendingRemindersFunc.
@@ -102,56 +273,221 @@ func (mock *StorageMock) ResetGetPendingRemindersCalls() {
 	mock.lockGetPendingReminders.Unlock()
 }
 
-// UpdateReminder calls UpdateReminderFunc.
-func (mock *StorageMock) UpdateReminder(ctx context.Context, reminder domain.Reminder) error {
-	if mock.UpdateReminderFunc == nil {
-		panic("StorageMock.UpdateReminderFunc: method is nil but Storage.UpdateReminder was just called")
+// GetUserSettings calls GetUserSettingsFunc.
+func (mock *StorageMock) GetUserSettings(ctx context.Context, userID int64) (domain.UserSettings, error) {
+	if mock.GetUserSettingsFunc == nil {
+		panic("StorageMock.GetUserSettingsFunc: method is nil but Storage.GetUserSettings was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int64
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockGetUserSettings.Lock()
+	mock.calls.GetUserSettings = append(mock.calls.GetUserSettings, callInfo)
+	mock.lockGetUserSettings.Unlock()
+	return mock.GetUserSettingsFunc(ctx, userID)
+}
+
+// GetUserSettingsCalls gets all the calls that were made to GetUserSettings.
+// Check the length with:
+//
+//	len(mockedStorage.GetUserSettingsCalls())
+func (mock *StorageMock) GetUserSettingsCalls() []struct {
+	Ctx    context.Context
+	UserID int64
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int64
+	}
+	mock.lockGetUserSettings.RLock()
+	calls = mock.calls.GetUserSettings
+	mock.lockGetUserSettings.RUnlock()
+	return calls
+}
+
+// ResetGetUserSettingsCalls reset all the calls that were made to GetUserSettings.
+func (mock *StorageMock) ResetGetUserSettingsCalls() {
+	mock.lockGetUserSettings.Lock()
+	mock.calls.GetUserSettings = nil
+	mock.lockGetUserSettings.Unlock()
+}
+
+// MissReminders calls MissRemindersFunc.
+func (mock *StorageMock) MissReminders(ctx context.Context, ids []int64, summary *domain.MissedSummary) error {
+	if mock.MissRemindersFunc == nil {
+		panic("StorageMock.MissRemindersFunc: method is nil but Storage.MissReminders was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Ids     []int64
+		Summary *domain.MissedSummary
+	}{
+		Ctx:     ctx,
+		Ids:     ids,
+		Summary: summary,
+	}
+	mock.lockMissReminders.Lock()
+	mock.calls.MissReminders = append(mock.calls.MissReminders, callInfo)
+	mock.lockMissReminders.Unlock()
+	return mock.MissRemindersFunc(ctx, ids, summary)
+}
+
+// MissRemindersCalls gets all the calls that were made to MissReminders.
+// Check the length with:
+//
+//	len(mockedStorage.MissRemindersCalls())
+func (mock *StorageMock) MissRemindersCalls() []struct {
+	Ctx     context.Context
+	Ids     []int64
+	Summary *domain.MissedSummary
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Ids     []int64
+		Summary *domain.MissedSummary
+	}
+	mock.lockMissReminders.RLock()
+	calls = mock.calls.MissReminders
+	mock.lockMissReminders.RUnlock()
+	return calls
+}
+
+// ResetMissRemindersCalls reset all the calls that were made to MissReminders.
+func (mock *StorageMock) ResetMissRemindersCalls() {
+	mock.lockMissReminders.Lock()
+	mock.calls.MissReminders = nil
+	mock.lockMissReminders.Unlock()
+}
+
+// NotifyPreNotice calls NotifyPreNoticeFunc.
+func (mock *StorageMock) NotifyPreNotice(ctx context.Context, id int64, nextPreNoticeAt *time.Time, msgs []domain.OutboxMessage) error {
+	if mock.NotifyPreNoticeFunc == nil {
+		panic("StorageMock.NotifyPreNoticeFunc: method is nil but Storage.NotifyPreNotice was just called")
+	}
+	callInfo := struct {
+		Ctx             context.Context
+		ID              int64
+		NextPreNoticeAt *time.Time
+		Msgs            []domain.OutboxMessage
+	}{
+		Ctx:             ctx,
+		ID:              id,
+		NextPreNoticeAt: nextPreNoticeAt,
+		Msgs:            msgs,
+	}
+	mock.lockNotifyPreNotice.Lock()
+	mock.calls.NotifyPreNotice = append(mock.calls.NotifyPreNotice, callInfo)
+	mock.lockNotifyPreNotice.Unlock()
+	return mock.NotifyPreNoticeFunc(ctx, id, nextPreNoticeAt, msgs)
+}
+
+// NotifyPreNoticeCalls gets all the calls that were made to NotifyPreNotice.
+// Check the length with:
+//
+//	len(mockedStorage.NotifyPreNoticeCalls())
+func (mock *StorageMock) NotifyPreNoticeCalls() []struct {
+	Ctx             context.Context
+	ID              int64
+	NextPreNoticeAt *time.Time
+	Msgs            []domain.OutboxMessage
+} {
+	var calls []struct {
+		Ctx             context.Context
+		ID              int64
+		NextPreNoticeAt *time.Time
+		Msgs            []domain.OutboxMessage
+	}
+	mock.lockNotifyPreNotice.RLock()
+	calls = mock.calls.NotifyPreNotice
+	mock.lockNotifyPreNotice.RUnlock()
+	return calls
+}
+
+// ResetNotifyPreNoticeCalls reset all the calls that were made to NotifyPreNotice.
+func (mock *StorageMock) ResetNotifyPreNoticeCalls() {
+	mock.lockNotifyPreNotice.Lock()
+	mock.calls.NotifyPreNotice = nil
+	mock.lockNotifyPreNotice.Unlock()
+}
+
+// NotifyReminder calls NotifyReminderFunc.
+func (mock *StorageMock) NotifyReminder(ctx context.Context, reminder domain.Reminder, msgs []domain.OutboxMessage) error {
+	if mock.NotifyReminderFunc == nil {
+		panic("StorageMock.NotifyReminderFunc: method is nil but Storage.NotifyReminder was just called")
 	}
 	callInfo := struct {
 		Ctx      context.Context
 		Reminder domain.Reminder
+		Msgs     []domain.OutboxMessage
 	}{
 		Ctx:      ctx,
 		Reminder: reminder,
+		Msgs:     msgs,
 	}
-	mock.lockUpdateReminder.Lock()
-	mock.calls.UpdateReminder = append(mock.calls.UpdateReminder, callInfo)
-	mock.lockUpdateReminder.Unlock()
-	return mock.UpdateReminderFunc(ctx, reminder)
+	mock.lockNotifyReminder.Lock()
+	mock.calls.NotifyReminder = append(mock.calls.NotifyReminder, callInfo)
+	mock.lockNotifyReminder.Unlock()
+	return mock.NotifyReminderFunc(ctx, reminder, msgs)
 }
 
-// UpdateReminderCalls gets all the calls that were made to UpdateReminder.
+// NotifyReminderCalls gets all the calls that were made to NotifyReminder.
 // Check the length with:
 //
-//	len(mockedStorage.UpdateReminderCalls())
-func (mock *StorageMock) UpdateReminderCalls() []struct {
+//	len(mockedStorage.NotifyReminderCalls())
+func (mock *StorageMock) NotifyReminderCalls() []struct {
 	Ctx      context.Context
 	Reminder domain.Reminder
+	Msgs     []domain.OutboxMessage
 } {
 	var calls []struct {
 		Ctx      context.Context
 		Reminder domain.Reminder
+		Msgs     []domain.OutboxMessage
 	}
-	mock.lockUpdateReminder.RLock()
-	calls = mock.calls.UpdateReminder
-	mock.lockUpdateReminder.RUnlock()
+	mock.lockNotifyReminder.RLock()
+	calls = mock.calls.NotifyReminder
+	mock.lockNotifyReminder.RUnlock()
 	return calls
 }
 
-// ResetUpdateReminderCalls reset all the calls that were made to UpdateReminder.
-func (mock *StorageMock) ResetUpdateReminderCalls() {
-	mock.lockUpdateReminder.Lock()
-	mock.calls.UpdateReminder = nil
-	mock.lockUpdateReminder.Unlock()
+// ResetNotifyReminderCalls reset all the calls that were made to NotifyReminder.
+func (mock *StorageMock) ResetNotifyReminderCalls() {
+	mock.lockNotifyReminder.Lock()
+	mock.calls.NotifyReminder = nil
+	mock.lockNotifyReminder.Unlock()
 }
 
 // ResetCalls reset all the calls that were made to all mocked methods.
 func (mock *StorageMock) ResetCalls() {
+	mock.lockGetDuePreNotices.Lock()
+	mock.calls.GetDuePreNotices = nil
+	mock.lockGetDuePreNotices.Unlock()
+
+	mock.lockGetMissedReminders.Lock()
+	mock.calls.GetMissedReminders = nil
+	mock.lockGetMissedReminders.Unlock()
+
 	mock.lockGetPendingReminders.Lock()
 	mock.calls.GetPendingReminders = nil
 	mock.lockGetPendingReminders.Unlock()
 
-	mock.lockUpdateReminder.Lock()
-	mock.calls.UpdateReminder = nil
-	mock.lockUpdateReminder.Unlock()
+	mock.lockGetUserSettings.Lock()
+	mock.calls.GetUserSettings = nil
+	mock.lockGetUserSettings.Unlock()
+
+	mock.lockMissReminders.Lock()
+	mock.calls.MissReminders = nil
+	mock.lockMissReminders.Unlock()
+
+	mock.lockNotifyPreNotice.Lock()
+	mock.calls.NotifyPreNotice = nil
+	mock.lockNotifyPreNotice.Unlock()
+
+	mock.lockNotifyReminder.Lock()
+	mock.calls.NotifyReminder = nil
+	mock.lockNotifyReminder.Unlock()
 }
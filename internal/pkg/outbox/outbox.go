@@ -0,0 +1,214 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	log "github.com/go-pkgz/lgr"
+	"github.com/mezk/tg-reminder/internal/pkg/delivery"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+	"github.com/mezk/tg-reminder/internal/pkg/sender"
+	"github.com/mezk/tg-reminder/internal/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var timeNowUTC = func() time.Time {
+	return time.Now().UTC()
+}
+
+// Storage - storage of outgoing messages.
+type Storage interface {
+	EnqueueOutboxMessage(ctx context.Context, msg domain.OutboxMessage) error
+	GetDueOutboxMessages(ctx context.Context, limit int64) ([]domain.OutboxMessage, error)
+	UpdateOutboxMessage(ctx context.Context, msg domain.OutboxMessage) error
+	RemoveOutboxMessage(ctx context.Context, id int64) error
+	DeactivateUser(ctx context.Context, id int64, reason string) error
+	DeactivateChat(ctx context.Context, chatID int64, reason string) error
+}
+
+// MessageSender - sender of messages to Telegram and other channels.
+type MessageSender interface {
+	SendOutboxMessage(ctx context.Context, msg domain.OutboxMessage) error
+}
+
+// Config - outbox configuration.
+type Config struct {
+	Interval    time.Duration // how often to check for messages to retry
+	BatchSize   int64         // max number of messages delivered per check
+	MaxAttempts int           // number of delivery attempts before message is dead
+	MaxBackoff  time.Duration // max delay between delivery attempts
+}
+
+// Outbox stores bot responses and notifications in the database and delivers them with retries.
+// Messages of one chat are delivered in order they were enqueued, every channel has its own order and retries.
+type Outbox struct {
+	sender MessageSender
+	store  Storage
+	cfg    Config
+	wakeup chan struct{}
+}
+
+// New creates new [Outbox].
+func New(sender MessageSender, store Storage, cfg Config) *Outbox {
+	return &Outbox{sender: sender, store: store, cfg: cfg, wakeup: make(chan struct{}, 1)}
+}
+
+// SendBotResponse - enqueues bot response, it's delivered to Telegram in background.
+// Correlation id from ctx is stored with the message, so delivery logs are tied to the update.
+// It's used for responses which don't change anything, a response to a change of state is enqueued by storage
+// in the transaction of the change and [Outbox.Wakeup] is called to deliver it.
+func (o *Outbox) SendBotResponse(ctx context.Context, resp sender.BotResponse, opts ...sender.BotResponseOption) error {
+	if sender.IsSecret(opts...) {
+		logging.Printf(ctx, "[DEBUG] bot response with secret to chat %d", resp.ChatID)
+	} else {
+		logging.Printf(ctx, "[DEBUG] bot response - %s", resp)
+	}
+
+	msg, err := sender.NewOutboxMessage(resp, opts...)
+	if err != nil {
+		return err
+	}
+
+	// response is a part of update handling which is already finished, so it's enqueued anyway
+	if err = o.store.EnqueueOutboxMessage(context.WithoutCancel(ctx), msg); err != nil {
+		return err
+	}
+
+	o.Wakeup()
+
+	return nil
+}
+
+// Wakeup - starts delivery right away instead of waiting for the next check.
+func (o *Outbox) Wakeup() {
+	select {
+	case o.wakeup <- struct{}{}:
+	default: // delivery is already requested
+	}
+}
+
+// Run starts infinite loop to deliver enqueued messages. Breaks infinite loop on context error.
+// Messages which are not delivered before shutdown are delivered after restart.
+func (o *Outbox) Run(ctx context.Context) {
+	log.Printf("[INFO] outbox started, interval %s, batch size %d, max attempts %d", o.cfg.Interval, o.cfg.BatchSize, o.cfg.MaxAttempts)
+
+	ticker := time.NewTicker(o.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+
+		case <-ctx.Done():
+			log.Printf("[INFO] outbox is shutting down")
+			return
+
+		case <-ticker.C:
+		case <-o.wakeup:
+		}
+
+		// only the oldest message of every chat is delivered per batch, so batches are repeated until nothing is due
+		for ctx.Err() == nil {
+			// message in progress is finished on shutdown, otherwise it's sent twice
+			if delivered := o.deliverBatch(context.WithoutCancel(ctx)); delivered == 0 {
+				break
+			}
+		}
+	}
+}
+
+// deliverBatch delivers due messages. Returns number of delivered messages.
+func (o *Outbox) deliverBatch(ctx context.Context) int {
+	messages, err := o.store.GetDueOutboxMessages(ctx, o.cfg.BatchSize)
+	if err != nil {
+		log.Printf("[ERROR] failed to fetch outbox messages: %v", err)
+		return 0
+	}
+
+	var delivered int
+
+	for _, msg := range messages {
+		ctx := logging.ContextWithUser(logging.ContextWithCorrelationID(ctx, msg.CorrelationID), 0, msg.ChatID)
+
+		if err = o.deliver(ctx, msg); err != nil {
+			o.fail(ctx, msg, err)
+			continue
+		}
+
+		delivered++
+
+		// message is delivered at least once: if it isn't removed, it's sent again
+		if err = o.store.RemoveOutboxMessage(ctx, msg.ID); err != nil {
+			logging.Printf(ctx, "[ERROR] failed to remove delivered outbox message %s: %v", msg, err)
+		}
+	}
+
+	return delivered
+}
+
+// deliver sends message by its channel, delivery is traced.
+func (o *Outbox) deliver(ctx context.Context, msg domain.OutboxMessage) error {
+	ctx, span := tracing.Start(ctx, "outbox.deliver", trace.WithAttributes(
+		attribute.Int64("outbox.message_id", msg.ID),
+		attribute.String("outbox.channel", string(msg.Channel)),
+		attribute.Int("outbox.attempts", msg.Attempts),
+		attribute.String("correlation_id", msg.CorrelationID),
+	))
+
+	err := o.sender.SendOutboxMessage(ctx, msg)
+	tracing.End(span, err)
+
+	return err
+}
+
+// fail handles failed delivery. Messages to users who blocked the bot and to chats which don't exist anymore
+// are never delivered, so the user or the chat is deactivated instead of retrying. Other messages are retried.
+func (o *Outbox) fail(ctx context.Context, msg domain.OutboxMessage, sendErr error) {
+	var err error
+
+	switch {
+	case errors.Is(sendErr, sender.ErrBotBlocked):
+		// bot can be blocked only in private chat, its id is the id of user
+		if err = o.store.DeactivateUser(ctx, msg.ChatID, sendErr.Error()); err == nil {
+			logging.Printf(ctx, "[WARN] user %d blocked the bot, user is deactivated: %v", msg.ChatID, sendErr)
+			return
+		}
+	case errors.Is(sendErr, sender.ErrChatNotFound):
+		if err = o.store.DeactivateChat(ctx, msg.ChatID, sendErr.Error()); err == nil {
+			logging.Printf(ctx, "[WARN] chat %d is not available, chat is deactivated: %v", msg.ChatID, sendErr)
+			return
+		}
+	}
+
+	if err != nil {
+		logging.Printf(ctx, "[ERROR] failed to deactivate chat %d: %v", msg.ChatID, err)
+	}
+
+	o.retry(ctx, msg, sendErr)
+}
+
+// retry schedules the next delivery attempt of message, or marks message dead if attempts are exhausted.
+// Message rejected by Telegram or by its channel is marked dead right away, so it doesn't block the next messages of the chat.
+func (o *Outbox) retry(ctx context.Context, msg domain.OutboxMessage, sendErr error) {
+	msg.Attempts++
+	msg.LastError = sendErr.Error()
+
+	switch {
+	case errors.Is(sendErr, sender.ErrPermanent):
+		msg.Status = domain.OutboxStatusDead
+		logging.Printf(ctx, "[ERROR] outbox message %s is rejected, it's dead: %v", msg, sendErr)
+	case msg.Attempts >= o.cfg.MaxAttempts:
+		msg.Status = domain.OutboxStatusDead
+		logging.Printf(ctx, "[ERROR] outbox message %s is dead after %d attempts: %v", msg, msg.Attempts, sendErr)
+	default:
+		delay := delivery.RetryDelay(msg.Attempts, o.cfg.MaxBackoff, sendErr)
+		msg.NextAttemptAt = timeNowUTC().Add(delay)
+		logging.Printf(ctx, "[WARN] failed to deliver outbox message %s, retry in %s: %v", msg, delay, sendErr)
+	}
+
+	if err := o.store.UpdateOutboxMessage(ctx, msg); err != nil {
+		logging.Printf(ctx, "[ERROR] failed to update outbox message %s: %v", msg, err)
+	}
+}
@@ -0,0 +1,261 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	tbapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+	"github.com/mezk/tg-reminder/internal/pkg/sender"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCfg = Config{Interval: time.Minute, BatchSize: 10, MaxAttempts: 3, MaxBackoff: time.Minute}
+
+func TestOutbox_SendBotResponse(t *testing.T) {
+	t.Parallel()
+
+	storageMock := StorageMock{
+		EnqueueOutboxMessageFunc: func(_ context.Context, msg domain.OutboxMessage) error {
+			a := assert.New(t)
+
+			a.EqualValues(42, msg.ChatID)
+			a.Equal(domain.OutboxStatusPending, msg.Status)
+
+			decoded, err := sender.DecodeOutboxMessage(msg)
+			a.NoError(err)
+			a.Equal("foo", decoded.Text)
+			a.True(decoded.DisableNotification)
+			return nil
+		},
+	}
+
+	outbox := New(nil, &storageMock, testCfg)
+
+	err := outbox.SendBotResponse(context.Background(), sender.BotResponse{ChatID: 42, Text: "foo"}, sender.WithDisableNotification())
+
+	require.NoError(t, err)
+	assert.Len(t, storageMock.EnqueueOutboxMessageCalls(), 1)
+	assert.Len(t, outbox.wakeup, 1, "delivery is requested")
+
+	storageMock.EnqueueOutboxMessageFunc = func(_ context.Context, _ domain.OutboxMessage) error {
+		return errors.New("database is locked")
+	}
+	assert.EqualError(t, outbox.SendBotResponse(context.Background(), sender.BotResponse{ChatID: 42, Text: "foo"}), "database is locked")
+}
+
+func TestOutbox_deliverBatch(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	timeNowUTC = func() time.Time { return now }
+
+	tooManyRequests := fmt.Errorf("can't send message to telegram: %w", &sender.RetryAfterError{
+		After: 120 * time.Second,
+		Err: &tbapi.Error{
+			Code:               429,
+			Message:            "Too Many Requests: retry after 120",
+			ResponseParameters: tbapi.ResponseParameters{RetryAfter: 120},
+		},
+	})
+	botBlocked := fmt.Errorf("can't send message to telegram: %w: Forbidden: bot was blocked by the user", sender.ErrBotBlocked)
+	chatNotFound := fmt.Errorf("can't send message to telegram: %w: Bad Request: chat not found", sender.ErrChatNotFound)
+	rejected := fmt.Errorf("can't send message to telegram: %w: Bad Request: message is too long", sender.ErrPermanent)
+
+	testCases := []struct {
+		name           string
+		message        domain.OutboxMessage
+		sendErr        error
+		deactivateErr  error
+		expDelivered   int
+		expRemoved     bool
+		expUpdated     *domain.OutboxMessage
+		expDeactivated string // "user" or "chat"
+	}{
+		{
+			name:         "success: message is delivered and removed",
+			message:      domain.OutboxMessage{ID: 1, ChatID: 42, Status: domain.OutboxStatusPending, CorrelationID: "0123456789abcdef"},
+			expDelivered: 1,
+			expRemoved:   true,
+		},
+		{
+			name:    "error: the first attempt failed, retry with min backoff",
+			message: domain.OutboxMessage{ID: 1, ChatID: 42, Status: domain.OutboxStatusPending},
+			sendErr: errors.New("connection reset by peer"),
+			expUpdated: &domain.OutboxMessage{
+				ID: 1, ChatID: 42, Status: domain.OutboxStatusPending, Attempts: 1,
+				NextAttemptAt: now.Add(5 * time.Second), LastError: "connection reset by peer",
+			},
+		},
+		{
+			name:    "error: backoff grows exponentially",
+			message: domain.OutboxMessage{ID: 1, ChatID: 42, Status: domain.OutboxStatusPending, Attempts: 1},
+			sendErr: errors.New("connection reset by peer"),
+			expUpdated: &domain.OutboxMessage{
+				ID: 1, ChatID: 42, Status: domain.OutboxStatusPending, Attempts: 2,
+				NextAttemptAt: now.Add(10 * time.Second), LastError: "connection reset by peer",
+			},
+		},
+		{
+			name:    "error: retry after requested by Telegram",
+			message: domain.OutboxMessage{ID: 1, ChatID: 42, Status: domain.OutboxStatusPending},
+			sendErr: tooManyRequests,
+			expUpdated: &domain.OutboxMessage{
+				ID: 1, ChatID: 42, Status: domain.OutboxStatusPending, Attempts: 1,
+				NextAttemptAt: now.Add(120 * time.Second), LastError: "can't send message to telegram: retry after 2m0s: Too Many Requests: retry after 120",
+			},
+		},
+		{
+			name:           "error: bot is blocked, user is deactivated without retries",
+			message:        domain.OutboxMessage{ID: 1, ChatID: 42, Status: domain.OutboxStatusPending},
+			sendErr:        botBlocked,
+			expDeactivated: "user",
+		},
+		{
+			name:           "error: chat not found, chat is deactivated without retries",
+			message:        domain.OutboxMessage{ID: 1, ChatID: -42, Status: domain.OutboxStatusPending},
+			sendErr:        chatNotFound,
+			expDeactivated: "chat",
+		},
+		{
+			name:           "error: bot is blocked, can't deactivate user, retry",
+			message:        domain.OutboxMessage{ID: 1, ChatID: 42, Status: domain.OutboxStatusPending},
+			sendErr:        botBlocked,
+			deactivateErr:  errors.New("database is locked"),
+			expDeactivated: "user",
+			expUpdated: &domain.OutboxMessage{
+				ID: 1, ChatID: 42, Status: domain.OutboxStatusPending, Attempts: 1,
+				NextAttemptAt: now.Add(5 * time.Second), LastError: botBlocked.Error(),
+			},
+		},
+		{
+			name:    "error: message is rejected, it's dead after the first attempt",
+			message: domain.OutboxMessage{ID: 1, ChatID: 42, Status: domain.OutboxStatusPending, NextAttemptAt: now},
+			sendErr: rejected,
+			expUpdated: &domain.OutboxMessage{
+				ID: 1, ChatID: 42, Status: domain.OutboxStatusDead, Attempts: 1,
+				NextAttemptAt: now, LastError: rejected.Error(),
+			},
+		},
+		{
+			name:    "error: attempts exhausted, message is dead",
+			message: domain.OutboxMessage{ID: 1, ChatID: 42, Status: domain.OutboxStatusPending, Attempts: 2, NextAttemptAt: now},
+			sendErr: errors.New("chat not found"),
+			expUpdated: &domain.OutboxMessage{
+				ID: 1, ChatID: 42, Status: domain.OutboxStatusDead, Attempts: 3,
+				NextAttemptAt: now, LastError: "chat not found",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			senderMock := MessageSenderMock{
+				SendOutboxMessageFunc: func(ctx context.Context, msg domain.OutboxMessage) error {
+					assert.Equal(t, tc.message, msg)
+					assert.Equal(t, logging.Fields{CorrelationID: tc.message.CorrelationID, ChatID: tc.message.ChatID}, logging.FieldsFromContext(ctx))
+					return tc.sendErr
+				},
+			}
+			storageMock := StorageMock{
+				GetDueOutboxMessagesFunc: func(_ context.Context, limit int64) ([]domain.OutboxMessage, error) {
+					assert.EqualValues(t, 10, limit)
+					return []domain.OutboxMessage{tc.message}, nil
+				},
+				RemoveOutboxMessageFunc: func(_ context.Context, id int64) error {
+					assert.Equal(t, tc.message.ID, id)
+					return nil
+				},
+				UpdateOutboxMessageFunc: func(_ context.Context, msg domain.OutboxMessage) error {
+					assert.Equal(t, *tc.expUpdated, msg)
+					return nil
+				},
+				DeactivateUserFunc: func(_ context.Context, id int64, reason string) error {
+					assert.Equal(t, tc.message.ChatID, id)
+					assert.Equal(t, tc.sendErr.Error(), reason)
+					return tc.deactivateErr
+				},
+				DeactivateChatFunc: func(_ context.Context, chatID int64, reason string) error {
+					assert.Equal(t, tc.message.ChatID, chatID)
+					assert.Equal(t, tc.sendErr.Error(), reason)
+					return tc.deactivateErr
+				},
+			}
+
+			delivered := New(&senderMock, &storageMock, testCfg).deliverBatch(context.TODO())
+
+			assert.Equal(t, tc.expDelivered, delivered)
+			assert.Equal(t, tc.expRemoved, len(storageMock.RemoveOutboxMessageCalls()) == 1)
+			assert.Equal(t, tc.expUpdated != nil, len(storageMock.UpdateOutboxMessageCalls()) == 1)
+			assert.Equal(t, tc.expDeactivated == "user", len(storageMock.DeactivateUserCalls()) == 1)
+			assert.Equal(t, tc.expDeactivated == "chat", len(storageMock.DeactivateChatCalls()) == 1)
+		})
+	}
+}
+
+func TestOutbox_Run(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success: batches are repeated until nothing is due", func(t *testing.T) {
+		t.Parallel()
+
+		queue := []domain.OutboxMessage{{ID: 1, ChatID: 42}, {ID: 2, ChatID: 42}}
+
+		senderMock := MessageSenderMock{
+			SendOutboxMessageFunc: func(_ context.Context, _ domain.OutboxMessage) error {
+				return nil
+			},
+		}
+		storageMock := StorageMock{
+			GetDueOutboxMessagesFunc: func(_ context.Context, _ int64) ([]domain.OutboxMessage, error) {
+				// only the oldest message of the chat is due
+				if len(queue) == 0 {
+					return nil, nil
+				}
+				return queue[:1], nil
+			},
+			RemoveOutboxMessageFunc: func(_ context.Context, id int64) error {
+				assert.Equal(t, queue[0].ID, id)
+				queue = queue[1:]
+				return nil
+			},
+		}
+
+		outbox := New(&senderMock, &storageMock, testCfg)
+		outbox.Wakeup()
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+		defer cancel()
+
+		outbox.Run(ctx)
+
+		assert.Len(t, senderMock.SendOutboxMessageCalls(), 2)
+		assert.Len(t, storageMock.GetDueOutboxMessagesCalls(), 3)
+	})
+
+	t.Run("error: can't get due messages", func(t *testing.T) {
+		t.Parallel()
+
+		storageMock := StorageMock{
+			GetDueOutboxMessagesFunc: func(_ context.Context, _ int64) ([]domain.OutboxMessage, error) {
+				return nil, errors.New("database is locked")
+			},
+		}
+
+		outbox := New(nil, &storageMock, Config{Interval: 30 * time.Millisecond, BatchSize: 10, MaxAttempts: 3, MaxBackoff: time.Minute})
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+		defer cancel()
+
+		outbox.Run(ctx)
+
+		assert.NotEmpty(t, storageMock.GetDueOutboxMessagesCalls())
+	})
+}
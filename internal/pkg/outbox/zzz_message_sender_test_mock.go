@@ -0,0 +1,96 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package outbox
+
+import (
+	"context"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"sync"
+)
+
+// Ensure, that MessageSenderMock does implement MessageSender.
+// If this is not the case, regenerate this file with moq.
+var _ MessageSender = &MessageSenderMock{}
+
+// MessageSenderMock is a mock implementation of MessageSender.
+//
+//	func TestSomethingThatUsesMessageSender(t *testing.T) {
+//
+//		// make and configure a mocked MessageSender
+//		mockedMessageSender := &MessageSenderMock{
+//			SendOutboxMessageFunc: func(ctx context.Context, msg domain.OutboxMessage) error {
+//				panic("mock out the SendOutboxMessage method")
+//			},
+//		}
+//
+//		// use mockedMessageSender in code that requires MessageSender
+//		// and then make assertions.
+//
+//	}
+type MessageSenderMock struct {
+	// SendOutboxMessageFunc mocks the SendOutboxMessage method.
+	SendOutboxMessageFunc func(ctx context.Context, msg domain.OutboxMessage) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// SendOutboxMessage holds details about calls to the SendOutboxMessage method.
+		SendOutboxMessage []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Msg is the msg argument value.
+			Msg domain.OutboxMessage
+		}
+	}
+	lockSendOutboxMessage sync.RWMutex
+}
+
+// SendOutboxMessage calls SendOutboxMessageFunc.
+func (mock *MessageSenderMock) SendOutboxMessage(ctx context.Context, msg domain.OutboxMessage) error {
+	if mock.SendOutboxMessageFunc == nil {
+		panic("MessageSenderMock.SendOutboxMessageFunc: method is nil but MessageSender.SendOutboxMessage was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Msg domain.OutboxMessage
+	}{
+		Ctx: ctx,
+		Msg: msg,
+	}
+	mock.lockSendOutboxMessage.Lock()
+	mock.calls.SendOutboxMessage = append(mock.calls.SendOutboxMessage, callInfo)
+	mock.lockSendOutboxMessage.Unlock()
+	return mock.SendOutboxMessageFunc(ctx, msg)
+}
+
+// SendOutboxMessageCalls gets all the calls that were made to SendOutboxMessage.
+// Check the length with:
+//
+//	len(mockedMessageSender.SendOutboxMessageCalls())
+func (mock *MessageSenderMock) SendOutboxMessageCalls() []struct {
+	Ctx context.Context
+	Msg domain.OutboxMessage
+} {
+	var calls []struct {
+		Ctx context.Context
+		Msg domain.OutboxMessage
+	}
+	mock.lockSendOutboxMessage.RLock()
+	calls = mock.calls.SendOutboxMessage
+	mock.lockSendOutboxMessage.RUnlock()
+	return calls
+}
+
+// ResetSendOutboxMessageCalls reset all the calls that were made to SendOutboxMessage.
+func (mock *MessageSenderMock) ResetSendOutboxMessageCalls() {
+	mock.lockSendOutboxMessage.Lock()
+	mock.calls.SendOutboxMessage = nil
+	mock.lockSendOutboxMessage.Unlock()
+}
+
+// ResetCalls reset all the calls that were made to all mocked methods.
+func (mock *MessageSenderMock) ResetCalls() {
+	mock.lockSendOutboxMessage.Lock()
+	mock.calls.SendOutboxMessage = nil
+	mock.lockSendOutboxMessage.Unlock()
+}
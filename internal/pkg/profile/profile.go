@@ -0,0 +1,126 @@
+package profile
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/go-pkgz/lgr"
+	tbapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+)
+
+// BotAPI - telegram bot api.
+type BotAPI interface {
+	Request(c tbapi.Chattable) (*tbapi.APIResponse, error)
+	MakeRequest(endpoint string, params tbapi.Params) (*tbapi.APIResponse, error)
+}
+
+// descriptions - bot's description per language, it's shown in an empty chat with the bot.
+var descriptions = map[domain.Language]string{
+	domain.LanguageDefault: "Бот-напоминалка " + domain.EmojiAlarmClock + "\n\n" +
+		"Напишите, о чём и когда напомнить, и я пришлю напоминание в нужное время. " +
+		"Напоминания можно откладывать, выбирать им приоритет и превращать в списки дел.",
+	domain.LanguageEnglish: "Reminder bot " + domain.EmojiAlarmClock + "\n\n" +
+		"Tell me what and when to remind you about and I'll send a reminder on time. " +
+		"Reminders can be snoozed, prioritized and turned into checklists. The bot talks in russian.",
+}
+
+// shortDescriptions - bot's short description per language, it's shown in the bot's profile.
+var shortDescriptions = map[domain.Language]string{
+	domain.LanguageDefault: "Напоминания в Telegram: создавайте, откладывайте и отмечайте выполненными " + domain.EmojiAlarmClock,
+	domain.LanguageEnglish: "Reminders in Telegram: create, snooze and mark them as done " + domain.EmojiAlarmClock,
+}
+
+// Registrar registers the bot's command menu and profile in Telegram.
+type Registrar struct {
+	botAPI   BotAPI
+	ownerID  int64
+	adminIDs []int64
+}
+
+// New creates new [Registrar]. Admins and the owner get their commands in the menu of private chat with the bot.
+func New(botAPI BotAPI, ownerID int64, adminIDs []int64) *Registrar {
+	return &Registrar{botAPI: botAPI, ownerID: ownerID, adminIDs: adminIDs}
+}
+
+// Register sets commands of the menu per scope and language, description and short description of the bot.
+// Commands are taken from [domain.BotCommands]. Failures of admins' scopes are logged and skipped.
+func (r *Registrar) Register() error {
+	for _, lang := range domain.Languages {
+		if err := r.setCommands(tbapi.NewBotCommandScopeAllPrivateChats(), lang, domain.BotCommandsFor(domain.BotCommandAccessUser, true)); err != nil {
+			return err
+		}
+
+		if err := r.setCommands(tbapi.NewBotCommandScopeAllGroupChats(), lang, domain.BotCommandsFor(domain.BotCommandAccessUser, false)); err != nil {
+			return err
+		}
+
+		for _, id := range r.admins() {
+			access := domain.BotCommandAccessAdmin
+			if id == r.ownerID {
+				access = domain.BotCommandAccessOwner
+			}
+
+			// chat id of private chat is the same as user id, admin who hasn't started the bot yet has no chat with it,
+			// so failure for one admin doesn't prevent registration for others
+			if err := r.setCommands(tbapi.NewBotCommandScopeChat(id), lang, domain.BotCommandsFor(access, true)); err != nil {
+				log.Printf("[WARN] failed to register commands of admin %d: %v", id, err)
+			}
+		}
+
+		if err := r.setText("setMyDescription", "description", descriptions[lang], lang); err != nil {
+			return err
+		}
+
+		if err := r.setText("setMyShortDescription", "short_description", shortDescriptions[lang], lang); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[INFO] bot commands and profile are registered, languages %v, admins %v", domain.Languages, r.admins())
+
+	return nil
+}
+
+func (r *Registrar) setCommands(scope tbapi.BotCommandScope, lang domain.Language, infos []domain.BotCommandInfo) error {
+	commands := make([]tbapi.BotCommand, 0, len(infos))
+	for _, info := range infos {
+		commands = append(commands, tbapi.BotCommand{
+			Command:     strings.TrimPrefix(info.Command.String(), "/"),
+			Description: info.Description(lang),
+		})
+	}
+
+	if _, err := r.botAPI.Request(tbapi.NewSetMyCommandsWithScopeAndLanguage(scope, string(lang), commands...)); err != nil {
+		return fmt.Errorf("failed to set commands of scope %s, language %q: %w", scope.Type, lang, err)
+	}
+
+	return nil
+}
+
+// setText calls method to set bot's profile text, the method isn't supported by [tbapi] yet.
+func (r *Registrar) setText(method, key, text string, lang domain.Language) error {
+	params := tbapi.Params{key: text}
+	params.AddNonEmpty("language_code", string(lang))
+
+	if _, err := r.botAPI.MakeRequest(method, params); err != nil {
+		return fmt.Errorf("failed to %s, language %q: %w", method, lang, err)
+	}
+
+	return nil
+}
+
+// admins returns unique ids of admins and the owner.
+func (r *Registrar) admins() []int64 {
+	var ids []int64
+	seen := make(map[int64]bool)
+	for _, id := range append([]int64{r.ownerID}, r.adminIDs...) {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	return ids
+}
@@ -0,0 +1,133 @@
+package profile
+
+import (
+	"errors"
+	"testing"
+
+	tbapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistrar_Register(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		var commands []tbapi.SetMyCommandsConfig
+		var texts []string
+		botAPIMock := BotAPIMock{
+			RequestFunc: func(c tbapi.Chattable) (*tbapi.APIResponse, error) {
+				commands = append(commands, c.(tbapi.SetMyCommandsConfig))
+				return &tbapi.APIResponse{Ok: true}, nil
+			},
+			MakeRequestFunc: func(endpoint string, params tbapi.Params) (*tbapi.APIResponse, error) {
+				texts = append(texts, endpoint+"/"+params["language_code"])
+				return &tbapi.APIResponse{Ok: true}, nil
+			},
+		}
+
+		err := New(&botAPIMock, 1, []int64{2, 1}).Register()
+		assert.NoError(t, err)
+
+		// private and group chats, the owner and an admin, for two languages
+		assert.Len(t, commands, 8)
+
+		private := commands[0]
+		assert.Equal(t, "all_private_chats", private.Scope.Type)
+		assert.Equal(t, "", private.LanguageCode)
+		assert.Equal(t, []tbapi.BotCommand{
+			{Command: "help", Description: "справка"},
+			{Command: "start", Description: "начать работу с ботом"},
+			{Command: "create_reminder", Description: "создать напоминание"},
+			{Command: "enable_reminders", Description: "включить напоминания"},
+			{Command: "disable_reminders", Description: "выключить напоминания"},
+			{Command: "my_reminders", Description: "мои напоминания"},
+			{Command: "settings", Description: "настройки кнопок"},
+			{Command: "api_token", Description: "токен для API"},
+			{Command: "hook_token", Description: "токен входящего вебхука"},
+			{Command: "web", Description: "веб-интерфейс"},
+			{Command: "webhooks", Description: "вебхуки событий"},
+		}, private.Commands)
+
+		group := commands[1]
+		assert.Equal(t, "all_group_chats", group.Scope.Type)
+		assert.Len(t, group.Commands, 6)
+
+		owner := commands[2]
+		assert.Equal(t, tbapi.BotCommandScope{Type: "chat", ChatID: 1}, *owner.Scope)
+		assert.Len(t, owner.Commands, 17)
+		assert.Equal(t, tbapi.BotCommand{Command: "invite", Description: "создать приглашение"}, owner.Commands[11])
+
+		admin := commands[3]
+		assert.Equal(t, tbapi.BotCommandScope{Type: "chat", ChatID: 2}, *admin.Scope)
+		assert.Len(t, admin.Commands, 16)
+		assert.Equal(t, tbapi.BotCommand{Command: "admin_stats", Description: "статистика бота"}, admin.Commands[11])
+
+		english := commands[4]
+		assert.Equal(t, "all_private_chats", english.Scope.Type)
+		assert.Equal(t, "en", english.LanguageCode)
+		assert.Equal(t, tbapi.BotCommand{Command: "help", Description: "help"}, english.Commands[0])
+
+		assert.Equal(t, []string{
+			"setMyDescription/", "setMyShortDescription/",
+			"setMyDescription/en", "setMyShortDescription/en",
+		}, texts)
+	})
+
+	t.Run("success: admin's commands aren't set", func(t *testing.T) {
+		t.Parallel()
+
+		var chatScopes []int64
+		botAPIMock := BotAPIMock{
+			RequestFunc: func(c tbapi.Chattable) (*tbapi.APIResponse, error) {
+				cfg := c.(tbapi.SetMyCommandsConfig)
+				if cfg.Scope.Type != "chat" {
+					return &tbapi.APIResponse{Ok: true}, nil
+				}
+				chatScopes = append(chatScopes, cfg.Scope.ChatID)
+				if cfg.Scope.ChatID == 2 {
+					return nil, errors.New("Bad Request: chat not found")
+				}
+				return &tbapi.APIResponse{Ok: true}, nil
+			},
+			MakeRequestFunc: func(endpoint string, params tbapi.Params) (*tbapi.APIResponse, error) {
+				return &tbapi.APIResponse{Ok: true}, nil
+			},
+		}
+
+		assert.NoError(t, New(&botAPIMock, 1, []int64{2, 3}).Register())
+		assert.Equal(t, []int64{1, 2, 3, 1, 2, 3}, chatScopes)
+		assert.Len(t, botAPIMock.MakeRequestCalls(), 4)
+	})
+
+	t.Run("error: can't set commands", func(t *testing.T) {
+		t.Parallel()
+
+		botAPIMock := BotAPIMock{
+			RequestFunc: func(c tbapi.Chattable) (*tbapi.APIResponse, error) {
+				return nil, errors.New("unexpected error")
+			},
+		}
+
+		err := New(&botAPIMock, 0, nil).Register()
+		assert.EqualError(t, err, `failed to set commands of scope all_private_chats, language "": unexpected error`)
+	})
+
+	t.Run("error: can't set description", func(t *testing.T) {
+		t.Parallel()
+
+		botAPIMock := BotAPIMock{
+			RequestFunc: func(c tbapi.Chattable) (*tbapi.APIResponse, error) {
+				return &tbapi.APIResponse{Ok: true}, nil
+			},
+			MakeRequestFunc: func(endpoint string, params tbapi.Params) (*tbapi.APIResponse, error) {
+				return nil, errors.New("unexpected error")
+			},
+		}
+
+		err := New(&botAPIMock, 0, nil).Register()
+		assert.EqualError(t, err, `failed to setMyDescription, language "": unexpected error`)
+		assert.Len(t, botAPIMock.RequestCalls(), 2)
+	})
+}
@@ -0,0 +1,113 @@
+package sender
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	tbapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+)
+
+var russianMonths = [...]string{"Январь", "Февраль", "Март", "Апрель", "Май", "Июнь", "Июль", "Август", "Сентябрь", "Октябрь", "Ноябрь", "Декабрь"}
+
+var russianWeekdays = [...]string{"Пн", "Вт", "Ср", "Чт", "Пт", "Сб", "Вс"}
+
+const (
+	calendarEmptyDay = " "
+	calendarPastDay  = "·"
+)
+
+func noopButton(text string) tbapi.InlineKeyboardButton {
+	return tbapi.NewInlineKeyboardButtonData(text, domain.ButtonDataNoop)
+}
+
+// calendarRows builds month calendar: navigation row, weekdays row and a row per week starting from Monday.
+// Navigation to months before the current one and days before today are disabled.
+func calendarRows(month, today time.Time) [][]tbapi.InlineKeyboardButton {
+	month = time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, month.Location())
+
+	prev := noopButton(calendarEmptyDay)
+	if month.After(today) {
+		prev = tbapi.NewInlineKeyboardButtonData("«", domain.ButtonDataPrefixRemindAtCalendar+month.AddDate(0, -1, 0).Format(domain.LayoutPickerMonth))
+	}
+
+	rows := [][]tbapi.InlineKeyboardButton{
+		tbapi.NewInlineKeyboardRow(
+			prev,
+			noopButton(fmt.Sprintf("%s %d", russianMonths[month.Month()-1], month.Year())),
+			tbapi.NewInlineKeyboardButtonData("»", domain.ButtonDataPrefixRemindAtCalendar+month.AddDate(0, 1, 0).Format(domain.LayoutPickerMonth)),
+		),
+	}
+
+	weekdays := make([]tbapi.InlineKeyboardButton, 0, len(russianWeekdays))
+	for _, weekday := range russianWeekdays {
+		weekdays = append(weekdays, noopButton(weekday))
+	}
+	rows = append(rows, weekdays)
+
+	// monday is the first day of week
+	week := make([]tbapi.InlineKeyboardButton, 0, 7)
+	for i := 0; i < (int(month.Weekday())+6)%7; i++ {
+		week = append(week, noopButton(calendarEmptyDay))
+	}
+
+	for day := month; day.Month() == month.Month(); day = day.AddDate(0, 0, 1) {
+		if day.Before(today) {
+			week = append(week, noopButton(calendarPastDay))
+		} else {
+			week = append(week, tbapi.NewInlineKeyboardButtonData(strconv.Itoa(day.Day()), domain.ButtonDataPrefixRemindAtDate+day.Format(domain.LayoutPickerDate)))
+		}
+
+		if len(week) == 7 {
+			rows = append(rows, week)
+			week = make([]tbapi.InlineKeyboardButton, 0, 7)
+		}
+	}
+
+	if len(week) > 0 {
+		for len(week) < 7 {
+			week = append(week, noopButton(calendarEmptyDay))
+		}
+		rows = append(rows, week)
+	}
+
+	return rows
+}
+
+// hourPickerRows builds 4 rows of 6 hours of date and a button to go back to calendar.
+func hourPickerRows(date time.Time) [][]tbapi.InlineKeyboardButton {
+	var rows [][]tbapi.InlineKeyboardButton
+
+	for i := 0; i < 4; i++ {
+		row := make([]tbapi.InlineKeyboardButton, 0, 6)
+		for j := 0; j < 6; j++ {
+			hour := time.Date(date.Year(), date.Month(), date.Day(), i*6+j, 0, 0, 0, date.Location())
+			row = append(row, tbapi.NewInlineKeyboardButtonData(hour.Format("15"), domain.ButtonDataPrefixRemindAtHour+hour.Format(domain.LayoutPickerHour)))
+		}
+		rows = append(rows, row)
+	}
+
+	return append(rows, tbapi.NewInlineKeyboardRow(
+		tbapi.NewInlineKeyboardButtonData("« "+buttonTextCalendar, domain.ButtonDataPrefixRemindAtCalendar+date.Format(domain.LayoutPickerMonth)),
+	))
+}
+
+// minutePickerRows builds 2 rows of 6 times of hour with 5 minutes step and a button to go back to hour picker.
+func minutePickerRows(hour time.Time) [][]tbapi.InlineKeyboardButton {
+	var rows [][]tbapi.InlineKeyboardButton
+
+	for i := 0; i < 2; i++ {
+		row := make([]tbapi.InlineKeyboardButton, 0, 6)
+		for j := 0; j < 6; j++ {
+			minute := time.Date(hour.Year(), hour.Month(), hour.Day(), hour.Hour(), (i*6+j)*5, 0, 0, hour.Location())
+			row = append(row, tbapi.NewInlineKeyboardButtonData(minute.Format("15:04"), domain.ButtonDataPrefixRemindAtMinute+minute.Format(domain.LayoutPickerMinute)))
+		}
+		rows = append(rows, row)
+	}
+
+	return append(rows, tbapi.NewInlineKeyboardRow(
+		tbapi.NewInlineKeyboardButtonData("« "+domain.EmojiAlarmClock+" Часы", domain.ButtonDataPrefixRemindAtDate+hour.Format(domain.LayoutPickerDate)),
+	))
+}
@@ -0,0 +1,69 @@
+package sender
+
+import (
+	"testing"
+	"time"
+
+	tbapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_calendarRows(t *testing.T) {
+	t.Parallel()
+
+	var (
+		october = time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+		today   = time.Date(2024, 10, 17, 15, 30, 0, 0, time.UTC)
+	)
+
+	rows := calendarRows(october, today)
+	require.Len(t, rows, 7)
+
+	// navigation, previous month is not available
+	assert.Equal(t, tbapi.NewInlineKeyboardRow(
+		tbapi.NewInlineKeyboardButtonData(" ", "btn_noop"),
+		tbapi.NewInlineKeyboardButtonData("Октябрь 2024", "btn_noop"),
+		tbapi.NewInlineKeyboardButtonData("»", "btn_remind_at/calendar/2024-11"),
+	), rows[0])
+	assert.Equal(t, "Пн", rows[1][0].Text)
+	assert.Equal(t, "Вс", rows[1][6].Text)
+
+	// the 1st of October 2024 is Tuesday and it's in the past
+	assert.Equal(t, tbapi.NewInlineKeyboardButtonData(" ", "btn_noop"), rows[2][0])
+	assert.Equal(t, tbapi.NewInlineKeyboardButtonData("·", "btn_noop"), rows[2][1])
+	assert.Equal(t, tbapi.NewInlineKeyboardButtonData("17", "btn_remind_at/date/2024-10-17"), rows[4][3])
+
+	// last week is padded
+	require.Len(t, rows[6], 7)
+	assert.Equal(t, tbapi.NewInlineKeyboardButtonData("31", "btn_remind_at/date/2024-10-31"), rows[6][3])
+	assert.Equal(t, tbapi.NewInlineKeyboardButtonData(" ", "btn_noop"), rows[6][6])
+
+	rows = calendarRows(october.AddDate(0, 1, 0), today)
+	assert.Equal(t, tbapi.NewInlineKeyboardButtonData("«", "btn_remind_at/calendar/2024-10"), rows[0][0])
+	assert.Equal(t, tbapi.NewInlineKeyboardButtonData("1", "btn_remind_at/date/2024-11-01"), rows[2][4])
+}
+
+func Test_hourPickerRows(t *testing.T) {
+	t.Parallel()
+
+	rows := hourPickerRows(time.Date(2024, 10, 20, 0, 0, 0, 0, time.UTC))
+	require.Len(t, rows, 5)
+	assert.Equal(t, tbapi.NewInlineKeyboardButtonData("00", "btn_remind_at/hour/2024-10-20T00"), rows[0][0])
+	assert.Equal(t, tbapi.NewInlineKeyboardButtonData("23", "btn_remind_at/hour/2024-10-20T23"), rows[3][5])
+	assert.Equal(t, tbapi.NewInlineKeyboardRow(
+		tbapi.NewInlineKeyboardButtonData("« 📅 Календарь", "btn_remind_at/calendar/2024-10"),
+	), rows[4])
+}
+
+func Test_minutePickerRows(t *testing.T) {
+	t.Parallel()
+
+	rows := minutePickerRows(time.Date(2024, 10, 20, 10, 0, 0, 0, time.UTC))
+	require.Len(t, rows, 3)
+	assert.Equal(t, tbapi.NewInlineKeyboardButtonData("10:00", "btn_remind_at/minute/2024-10-20T10:00"), rows[0][0])
+	assert.Equal(t, tbapi.NewInlineKeyboardButtonData("10:55", "btn_remind_at/minute/2024-10-20T10:55"), rows[1][5])
+	assert.Equal(t, tbapi.NewInlineKeyboardRow(
+		tbapi.NewInlineKeyboardButtonData("« ⏰ Часы", "btn_remind_at/date/2024-10-20"),
+	), rows[2])
+}
@@ -0,0 +1,79 @@
+package sender
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	tbapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+var (
+	// ErrBotBlocked - user blocked the bot or deleted the account, messages can't be sent to user anymore.
+	ErrBotBlocked = errors.New("bot is blocked by user")
+	// ErrChatNotFound - chat is deleted or bot is removed from it, messages can't be sent to chat anymore.
+	ErrChatNotFound = errors.New("chat is not found")
+	// ErrPermanent - Telegram rejected the message itself, e.g. it's too long or bot has no rights in the chat,
+	// the message won't be delivered by retry.
+	ErrPermanent = errors.New("message is rejected")
+)
+
+// RetryAfterError - Telegram rejected the request because of flood control, the request can be repeated after a delay.
+type RetryAfterError struct {
+	After time.Duration
+	Err   error
+}
+
+// Error implements error interface.
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("retry after %s: %v", e.After, e.Err)
+}
+
+// Unwrap returns the original error of Telegram API.
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// classifyError wraps error of Telegram API with a typed error, so callers are able to react to it.
+// Errors which are not classified, e.g. server errors of Telegram, are returned as is.
+func classifyError(err error) error {
+	var tgErr *tbapi.Error
+	if !errors.As(err, &tgErr) {
+		return err
+	}
+
+	description := strings.ToLower(tgErr.Message)
+	containsAny := func(substrs ...string) bool {
+		for _, substr := range substrs {
+			if strings.Contains(description, substr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case tgErr.Code == http.StatusTooManyRequests:
+		return &RetryAfterError{After: time.Duration(tgErr.RetryAfter) * time.Second, Err: err}
+	case tgErr.Code == http.StatusForbidden && containsAny("kicked", "not a member"):
+		return fmt.Errorf("%w: %w", ErrChatNotFound, err)
+	case tgErr.Code == http.StatusForbidden && containsAny("blocked by the user", "user is deactivated", "can't initiate conversation"):
+		// bot was blocked by the user, user is deactivated, bot can't initiate conversation with a user
+		return fmt.Errorf("%w: %w", ErrBotBlocked, err)
+	case tgErr.Code == http.StatusBadRequest && containsAny("chat not found"):
+		return fmt.Errorf("%w: %w", ErrChatNotFound, err)
+	case tgErr.Code == http.StatusBadRequest || tgErr.Code == http.StatusForbidden:
+		// e.g. message is too long or bot has no rights to send messages to the chat
+		return fmt.Errorf("%w: %w", ErrPermanent, err)
+	default:
+		return err
+	}
+}
+
+// isParseError reports whether Telegram failed to parse markup of a message.
+func isParseError(err error) bool {
+	var tgErr *tbapi.Error
+	return errors.As(err, &tgErr) && tgErr.Code == http.StatusBadRequest && strings.Contains(strings.ToLower(tgErr.Message), "can't parse entities")
+}
@@ -0,0 +1,102 @@
+package sender
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	tbapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_classifyError(t *testing.T) {
+	t.Parallel()
+
+	someErr := errors.New("connection reset by peer")
+
+	testCases := []struct {
+		name          string
+		err           error
+		expErr        error
+		expRetryAfter time.Duration
+	}{
+		{
+			name:   "bot was blocked by the user",
+			err:    &tbapi.Error{Code: 403, Message: "Forbidden: bot was blocked by the user"},
+			expErr: ErrBotBlocked,
+		},
+		{
+			name:   "user is deactivated",
+			err:    &tbapi.Error{Code: 403, Message: "Forbidden: user is deactivated"},
+			expErr: ErrBotBlocked,
+		},
+		{
+			name:   "chat not found",
+			err:    &tbapi.Error{Code: 400, Message: "Bad Request: chat not found"},
+			expErr: ErrChatNotFound,
+		},
+		{
+			name:   "bot was kicked from the group chat",
+			err:    &tbapi.Error{Code: 403, Message: "Forbidden: bot was kicked from the group chat"},
+			expErr: ErrChatNotFound,
+		},
+		{
+			name:          "too many requests",
+			err:           fmt.Errorf("wrapped: %w", &tbapi.Error{Code: 429, Message: "Too Many Requests: retry after 7", ResponseParameters: tbapi.ResponseParameters{RetryAfter: 7}}),
+			expRetryAfter: 7 * time.Second,
+		},
+		{
+			name:   "bot can't initiate conversation",
+			err:    &tbapi.Error{Code: 403, Message: "Forbidden: bot can't initiate conversation with a user"},
+			expErr: ErrBotBlocked,
+		},
+		{
+			name:   "other bad requests are permanent",
+			err:    &tbapi.Error{Code: 400, Message: "Bad Request: message text is empty"},
+			expErr: ErrPermanent,
+		},
+		{
+			name:   "other forbidden requests are permanent",
+			err:    &tbapi.Error{Code: 403, Message: "Forbidden: not enough rights to send text messages to the chat"},
+			expErr: ErrPermanent,
+		},
+		{
+			name:   "server errors of Telegram are not classified",
+			err:    &tbapi.Error{Code: 502, Message: "Bad Gateway"},
+			expErr: nil,
+		},
+		{
+			name:   "not an error of Telegram",
+			err:    someErr,
+			expErr: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := classifyError(tc.err)
+
+			assert.ErrorIs(t, err, tc.err, "original error is kept")
+			if tc.expErr != nil {
+				assert.ErrorIs(t, err, tc.expErr)
+			}
+			assert.Equal(t, tc.expErr == nil && tc.expRetryAfter == 0, err == tc.err, "error is returned as is")
+
+			var retryErr *RetryAfterError
+			if assert.Equal(t, tc.expRetryAfter != 0, errors.As(err, &retryErr)) && retryErr != nil {
+				assert.Equal(t, tc.expRetryAfter, retryErr.After)
+			}
+		})
+	}
+}
+
+func Test_isParseError(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isParseError(&tbapi.Error{Code: 400, Message: "Bad Request: can't parse entities: Unsupported start tag"}))
+	assert.False(t, isParseError(&tbapi.Error{Code: 400, Message: "Bad Request: chat not found"}))
+	assert.False(t, isParseError(errors.New("can't parse entities")))
+}
@@ -0,0 +1,66 @@
+package sender
+
+import (
+	"encoding/json"
+	"fmt"
+
+	tbapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+)
+
+// Message - bot response rendered with all options applied. Message is serializable, so it can be stored in outbox
+// and sent later.
+type Message struct {
+	ChatID              int64                       `json:"chat_id"`
+	ReplyToMessageID    int64                       `json:"reply_to_message_id,omitempty"`
+	EditMessageID       int64                       `json:"edit_message_id,omitempty"`
+	Text                string                      `json:"text"`
+	DisableNotification bool                        `json:"disable_notification,omitempty"`
+	Pin                 bool                        `json:"pin,omitempty"`
+	ReplyMarkup         *tbapi.InlineKeyboardMarkup `json:"reply_markup,omitempty"` // nil removes buttons from edited message
+}
+
+// Render - applies options to bot response and renders it to message.
+func Render(resp BotResponse, opts ...BotResponseOption) Message {
+	for _, opt := range opts {
+		opt(&resp)
+	}
+
+	return Message{
+		ChatID:              resp.ChatID,
+		ReplyToMessageID:    resp.ReplyToMessageID,
+		EditMessageID:       resp.EditMessageID,
+		Text:                resp.Text,
+		DisableNotification: resp.disableNotification,
+		Pin:                 resp.pin,
+		ReplyMarkup:         replyMarkup(resp),
+	}
+}
+
+// NewOutboxMessage - renders bot response to message, which is stored in outbox until it's delivered.
+func NewOutboxMessage(resp BotResponse, opts ...BotResponseOption) (domain.OutboxMessage, error) {
+	msg := Render(resp, opts...)
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return domain.OutboxMessage{}, fmt.Errorf("failed to marshal message to chat %d: %w", msg.ChatID, err)
+	}
+
+	return domain.OutboxMessage{
+		ChatID:  msg.ChatID,
+		Channel: domain.ChannelTelegram,
+		Payload: string(payload),
+		Status:  domain.OutboxStatusPending,
+		Secret:  IsSecret(opts...),
+	}, nil
+}
+
+// DecodeOutboxMessage - decodes message stored in outbox.
+func DecodeOutboxMessage(outboxMsg domain.OutboxMessage) (Message, error) {
+	var msg Message
+	if err := json.Unmarshal([]byte(outboxMsg.Payload), &msg); err != nil {
+		return Message{}, fmt.Errorf("failed to unmarshal outbox message %d: %w", outboxMsg.ID, err)
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,69 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ResponseSender - sender of bot responses.
+type ResponseSender interface {
+	SendBotResponse(ctx context.Context, response BotResponse, opts ...BotResponseOption) error
+}
+
+// RateLimitedSender - sender which sends bot responses not faster than with the configured rate.
+// It's used for mass sending, e.g. broadcasts, to respect Telegram limits.
+type RateLimitedSender struct {
+	next     ResponseSender
+	interval time.Duration
+
+	mu       sync.Mutex
+	nextSend time.Time
+}
+
+// NewRateLimited - creates a sender which sends at most ratePerSecond responses per second via next sender.
+func NewRateLimited(next ResponseSender, ratePerSecond int) *RateLimitedSender {
+	if ratePerSecond <= 0 {
+		ratePerSecond = 1
+	}
+
+	return &RateLimitedSender{next: next, interval: time.Second / time.Duration(ratePerSecond)}
+}
+
+// SendBotResponse - waits for its turn and sends bot response. If Telegram asks to slow down, all sends are deferred
+// for the requested delay and the response is sent once again.
+func (s *RateLimitedSender) SendBotResponse(ctx context.Context, resp BotResponse, opts ...BotResponseOption) error {
+	s.wait()
+
+	err := s.next.SendBotResponse(ctx, resp, opts...)
+
+	var retryErr *RetryAfterError
+	if !errors.As(err, &retryErr) {
+		return err
+	}
+
+	s.postpone(retryErr.After)
+	s.wait()
+
+	return s.next.SendBotResponse(ctx, resp, opts...)
+}
+
+// wait waits for the turn of the next send.
+func (s *RateLimitedSender) wait() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if wait := time.Until(s.nextSend); wait > 0 {
+		time.Sleep(wait)
+	}
+	s.nextSend = time.Now().Add(s.interval)
+}
+
+// postpone moves the turn of the next send by delay.
+func (s *RateLimitedSender) postpone(delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextSend = time.Now().Add(delay)
+}
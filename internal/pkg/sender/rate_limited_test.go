@@ -0,0 +1,56 @@
+package sender
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tbapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitedSender_SendBotResponse(t *testing.T) {
+	t.Parallel()
+
+	var sentAt []time.Time
+	botAPIMock := &BotAPIMock{
+		SendFunc: func(_ tbapi.Chattable) (tbapi.Message, error) {
+			sentAt = append(sentAt, time.Now())
+			return tbapi.Message{}, nil
+		},
+	}
+
+	rateLimited := NewRateLimited(New(botAPIMock), 20)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, rateLimited.SendBotResponse(context.TODO(), BotResponse{ChatID: int64(i), Text: "foo"}))
+	}
+
+	require.Len(t, sentAt, 3)
+	for i := 1; i < len(sentAt); i++ {
+		assert.GreaterOrEqual(t, sentAt[i].Sub(sentAt[i-1]), 45*time.Millisecond)
+	}
+}
+
+func TestRateLimitedSender_SendBotResponse_retryAfter(t *testing.T) {
+	t.Parallel()
+
+	var sentAt []time.Time
+	botAPIMock := &BotAPIMock{
+		SendFunc: func(_ tbapi.Chattable) (tbapi.Message, error) {
+			sentAt = append(sentAt, time.Now())
+			if len(sentAt) == 1 {
+				return tbapi.Message{}, &tbapi.Error{Code: 429, Message: "Too Many Requests: retry after 1", ResponseParameters: tbapi.ResponseParameters{RetryAfter: 1}}
+			}
+			return tbapi.Message{}, nil
+		},
+	}
+
+	rateLimited := NewRateLimited(New(botAPIMock), 20)
+
+	require.NoError(t, rateLimited.SendBotResponse(context.TODO(), BotResponse{ChatID: 1, Text: "foo"}))
+
+	require.Len(t, sentAt, 2, "response is sent again")
+	assert.GreaterOrEqual(t, sentAt[1].Sub(sentAt[0]), 950*time.Millisecond)
+}
@@ -3,6 +3,9 @@ package sender
 import (
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
 )
 
 // BotResponse describes bot's reaction on particular message in chat.
@@ -10,11 +13,28 @@ type BotResponse struct {
 	ChatID           int64  // telegram chat id
 	ReplyToMessageID int64  // message to reply to, if 0 then no reply but common message
 	Text             string // message text
+	EditMessageID    int64  // message to edit, if 0 then new message is sent
 
 	showMyReminderListEditButtons bool
 	showReminderDatesButtons      bool
+	quickOptions                  domain.QuickOptions
+	showRemindAtConfirmButtons    bool
+	calendarMonth                 time.Time
+	calendarToday                 time.Time
+	hourPickerDate                time.Time
+	minutePickerHour              time.Time
 	showReminderDoneButtons       bool
+	snoozeOptions                 domain.QuickOptions
+	showCatchUpButtons            bool
+	summaryKey                    string
+	showLeadTimesButtons          bool
+	showAddChecklistItemsButton   bool
+	checklist                     domain.Checklist
 	reminderID                    int64
+	adminUsers                    []domain.User
+	disableNotification           bool
+	pin                           bool
+	secret                        bool
 }
 
 // BotResponseOption - describes response option.
@@ -29,20 +49,126 @@ func WithMyRemindersListEditButtons() BotResponseOption {
 }
 
 // WithReminderDatesButtons - shows inline keyboard to set date to fire reminder.
-func WithReminderDatesButtons() BotResponseOption {
+// Quick options are user's buttons with time of reminder, defaults are used if options are empty.
+func WithReminderDatesButtons(options domain.QuickOptions) BotResponseOption {
 	return func(r *BotResponse) {
 		r.showReminderDatesButtons = true
+		r.quickOptions = options.Or(domain.DefaultQuickOptions)
+	}
+}
+
+// WithCalendarButtons - shows inline keyboard with calendar of month to pick date of reminder.
+// Days before today can't be picked.
+func WithCalendarButtons(month, today time.Time) BotResponseOption {
+	return func(r *BotResponse) {
+		r.calendarMonth = month
+		r.calendarToday = today
+	}
+}
+
+// WithHourPickerButtons - shows inline keyboard to pick hour of reminder at date.
+func WithHourPickerButtons(date time.Time) BotResponseOption {
+	return func(r *BotResponse) {
+		r.hourPickerDate = date
+	}
+}
+
+// WithMinutePickerButtons - shows inline keyboard to pick minutes of reminder at hour.
+func WithMinutePickerButtons(hour time.Time) BotResponseOption {
+	return func(r *BotResponse) {
+		r.minutePickerHour = hour
+	}
+}
+
+// WithRemindAtConfirmButtons - shows inline keyboard to confirm, shift or enter again remindAt of reminder being created.
+func WithRemindAtConfirmButtons() BotResponseOption {
+	return func(r *BotResponse) {
+		r.showRemindAtConfirmButtons = true
 	}
 }
 
-// WithReminderDoneButton - shows inline keyboard to allow user to mark reminder with specific id as done.
-func WithReminderDoneButton(reminderID int64) BotResponseOption {
+// WithReminderDoneButton - shows inline keyboard to allow user to mark reminder with specific id as done
+// or to snooze it. Default snooze options are used if options are empty.
+func WithReminderDoneButton(reminderID int64, snooze domain.QuickOptions) BotResponseOption {
 	return func(r *BotResponse) {
 		r.showReminderDoneButtons = true
+		r.snoozeOptions = snooze.Or(domain.DefaultSnoozeOptions)
+		r.reminderID = reminderID
+	}
+}
+
+// WithCatchUpButtons - shows inline keyboard to allow user to reschedule missed reminders of summary with specific key
+// or to skip them. Default snooze options are used if options are empty.
+func WithCatchUpButtons(summaryKey string, snooze domain.QuickOptions) BotResponseOption {
+	return func(r *BotResponse) {
+		r.showCatchUpButtons = true
+		r.snoozeOptions = snooze.Or(domain.DefaultSnoozeOptions)
+		r.summaryKey = summaryKey
+	}
+}
+
+// WithLeadTimesButtons - shows inline keyboard to allow user to toggle advance notices of reminder with specific id.
+func WithLeadTimesButtons(reminderID int64) BotResponseOption {
+	return func(r *BotResponse) {
+		r.showLeadTimesButtons = true
 		r.reminderID = reminderID
 	}
 }
 
+// WithChecklistButtons - shows inline keyboard to allow user to check and uncheck checklist items.
+func WithChecklistButtons(checklist domain.Checklist) BotResponseOption {
+	return func(r *BotResponse) {
+		r.checklist = checklist
+	}
+}
+
+// WithAddChecklistItemsButton - shows inline keyboard to allow user to add checklist items to reminder with specific id.
+func WithAddChecklistItemsButton(reminderID int64) BotResponseOption {
+	return func(r *BotResponse) {
+		r.showAddChecklistItemsButton = true
+		r.reminderID = reminderID
+	}
+}
+
+// WithAdminUsersButtons - shows inline keyboard to allow admin to block or unblock users.
+func WithAdminUsersButtons(users []domain.User) BotResponseOption {
+	return func(r *BotResponse) {
+		r.adminUsers = users
+	}
+}
+
+// WithDisableNotification - sends message silently, user receives a notification with no sound.
+func WithDisableNotification() BotResponseOption {
+	return func(r *BotResponse) {
+		r.disableNotification = true
+	}
+}
+
+// WithPin - pins message in chat after it's sent.
+func WithPin() BotResponseOption {
+	return func(r *BotResponse) {
+		r.pin = true
+	}
+}
+
+// WithSecret - marks response containing secret, e.g. token or login link. Text of secret response isn't logged
+// and is erased from outbox if message can't be delivered.
+func WithSecret() BotResponseOption {
+	return func(r *BotResponse) {
+		r.secret = true
+	}
+}
+
+// IsSecret reports whether options mark response as secret, see [WithSecret].
+func IsSecret(opts ...BotResponseOption) bool {
+	var r BotResponse
+	for _, opt := range opts {
+		opt(&r)
+	}
+
+	return r.secret
+}
+
 func (s BotResponse) String() string {
 	text := strings.ReplaceAll(s.Text, "\n", "\\n")
 
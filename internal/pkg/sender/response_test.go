@@ -51,3 +51,11 @@ func TestBotResponse_String(t *testing.T) {
 		})
 	}
 }
+
+func TestIsSecret(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, IsSecret())
+	assert.False(t, IsSecret(WithPin(), WithDisableNotification()))
+	assert.True(t, IsSecret(WithPin(), WithSecret()))
+}
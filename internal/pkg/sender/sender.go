@@ -1,17 +1,23 @@
 package sender
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+	"time"
 
-	log "github.com/go-pkgz/lgr"
 	tbapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+	"github.com/mezk/tg-reminder/internal/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // BotAPI - subset of Telegram bot API methods.
 type BotAPI interface {
 	Send(c tbapi.Chattable) (tbapi.Message, error)
+	Request(c tbapi.Chattable) (*tbapi.APIResponse, error)
 }
 
 // BotResponseSender - sender which is able to send bot response to user.
@@ -24,108 +30,301 @@ func New(botAPI BotAPI) *BotResponseSender {
 	return &BotResponseSender{botAPI: botAPI}
 }
 
-// SendBotResponse - sends a message to telegram as markdown first and if failed - as plain text.
-func (s *BotResponseSender) SendBotResponse(resp BotResponse, opts ...BotResponseOption) error {
-	log.Printf("[DEBUG] bot response - %s", resp)
+// SendBotResponse - sends a message to telegram as markdown first and if markdown can't be parsed - as plain text.
+func (s *BotResponseSender) SendBotResponse(ctx context.Context, resp BotResponse, opts ...BotResponseOption) error {
+	if IsSecret(opts...) {
+		logging.Printf(ctx, "[DEBUG] bot response with secret to chat %d", resp.ChatID)
+	} else {
+		logging.Printf(ctx, "[DEBUG] bot response - %s", resp)
+	}
+
+	return s.SendMessage(ctx, Render(resp, opts...))
+}
 
-	for _, opt := range opts {
-		opt(&resp)
+// SendOutboxMessage - sends a message stored in outbox.
+func (s *BotResponseSender) SendOutboxMessage(ctx context.Context, outboxMsg domain.OutboxMessage) error {
+	msg, err := DecodeOutboxMessage(outboxMsg)
+	if err != nil {
+		return err
 	}
 
-	tbMsg := tbapi.NewMessage(resp.ChatID, resp.Text)
+	return s.SendMessage(ctx, msg)
+}
+
+// SendMessage - sends a rendered message to telegram as markdown first and if markdown can't be parsed - as plain text.
+// Errors of Telegram are classified, see [ErrBotBlocked], [ErrChatNotFound], [ErrPermanent] and [RetryAfterError].
+func (s *BotResponseSender) SendMessage(ctx context.Context, msg Message) error {
+	if msg.EditMessageID != 0 {
+		tbEdit := tbapi.NewEditMessageText(msg.ChatID, int(msg.EditMessageID), msg.Text)
+		tbEdit.ReplyMarkup = msg.ReplyMarkup // no markup removes buttons from message
+
+		if _, err := s.send(ctx, tbEdit); err != nil {
+			return fmt.Errorf("can't edit message %d in telegram %q: %w", msg.EditMessageID, msg.Text, err)
+		}
+
+		return nil
+	}
+
+	tbMsg := tbapi.NewMessage(msg.ChatID, msg.Text)
 	tbMsg.ParseMode = tbapi.ModeMarkdown
 	tbMsg.DisableWebPagePreview = true
-	tbMsg.ReplyToMessageID = int(resp.ReplyToMessageID)
-	setReplyMarkup(&tbMsg, resp)
+	tbMsg.ReplyToMessageID = int(msg.ReplyToMessageID)
+	tbMsg.DisableNotification = msg.DisableNotification
+	if msg.ReplyMarkup != nil {
+		tbMsg.ReplyMarkup = *msg.ReplyMarkup
+	}
 
-	if err := s.send(tbMsg); err != nil {
-		return fmt.Errorf("can't send message to telegram %q: %w", resp.Text, err)
+	sent, err := s.send(ctx, tbMsg)
+	if err != nil {
+		return fmt.Errorf("can't send message to telegram %q: %w", msg.Text, err)
+	}
+
+	if msg.Pin {
+		// message is already delivered, failed pin is not a reason to send it again
+		if _, err = s.request(ctx, tbapi.PinChatMessageConfig{ChatID: msg.ChatID, MessageID: sent.MessageID}); err != nil {
+			logging.Printf(ctx, "[WARN] failed to pin message %d in chat %d: %v", sent.MessageID, msg.ChatID, err)
+		}
 	}
 
 	return nil
 }
 
-func (s *BotResponseSender) send(tbMsg tbapi.Chattable) error {
+func (s *BotResponseSender) send(ctx context.Context, tbMsg tbapi.Chattable) (tbapi.Message, error) {
 	withParseMode := func(tbMsg tbapi.Chattable, parseMode string) tbapi.Chattable {
 		switch msg := tbMsg.(type) {
 		case tbapi.MessageConfig:
 			msg.ParseMode = parseMode
 			msg.DisableWebPagePreview = true
 			return msg
+		case tbapi.EditMessageTextConfig:
+			msg.ParseMode = parseMode
+			msg.DisableWebPagePreview = true
+			return msg
 		default:
 			return tbMsg // don't touch other types
 		}
 	}
 
 	msg := withParseMode(tbMsg, tbapi.ModeMarkdown) // try markdown first
-	if _, err := s.botAPI.Send(msg); err != nil {
-		log.Printf("[WARN] failed to send message to telegram as markdown, %v", err)
+	sent, err := s.apiSend(ctx, msg)
+	if err != nil && isParseError(err) {
+		logging.Printf(ctx, "[WARN] failed to send message to telegram as markdown, %v", err)
 
-		msg = withParseMode(tbMsg, "") // try plain text
-		if _, err = s.botAPI.Send(msg); err != nil {
-			return err
-		}
+		msg = withParseMode(tbMsg, "") // try plain text, other errors don't depend on markup
+		sent, err = s.apiSend(ctx, msg)
+	}
+	if err != nil {
+		return tbapi.Message{}, classifyError(err)
 	}
 
-	return nil
+	return sent, nil
+}
+
+// apiSend calls Telegram API to send message, the call is traced.
+func (s *BotResponseSender) apiSend(ctx context.Context, c tbapi.Chattable) (tbapi.Message, error) {
+	_, span := startAPISpan(ctx, c)
+
+	sent, err := s.botAPI.Send(c)
+	tracing.End(span, err)
+
+	return sent, err
+}
+
+// request calls Telegram API method which doesn't return a message, the call is traced.
+func (s *BotResponseSender) request(ctx context.Context, c tbapi.Chattable) (*tbapi.APIResponse, error) {
+	_, span := startAPISpan(ctx, c)
+
+	resp, err := s.botAPI.Request(c)
+	tracing.End(span, err)
+
+	return resp, err
+}
+
+// startAPISpan starts span of Telegram API call named after API method.
+func startAPISpan(ctx context.Context, c tbapi.Chattable) (context.Context, trace.Span) {
+	var method string
+	var chatID int64
+
+	switch c := c.(type) {
+	case tbapi.MessageConfig:
+		method, chatID = "sendMessage", c.ChatID
+	case tbapi.EditMessageTextConfig:
+		method, chatID = "editMessageText", c.ChatID
+	case tbapi.PinChatMessageConfig:
+		method, chatID = "pinChatMessage", c.ChatID
+	default:
+		method = fmt.Sprintf("%T", c)
+	}
+
+	return tracing.Start(ctx, "telegram."+method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("telegram.method", method),
+		attribute.Int64("telegram.chat_id", chatID),
+	))
 }
 
 const (
 	buttonTextEditReminder   = domain.EmojiMemo + " Редактировать"
 	buttonTextRemoveReminder = domain.EmojiCrossMark + " Удалить"
+	buttonTextHistory        = domain.EmojiScroll + " История"
 	buttonTextReminderDone   = domain.EmojiWhiteHeavyCheckMark + " Готово"
-	buttonText15Min          = domain.EmojiCounterclockwiseArrowsButton + " 30 мин."
-	buttonText30Min          = domain.EmojiCounterclockwiseArrowsButton + " 80 мин."
-	buttonText1Hour          = domain.EmojiCounterclockwiseArrowsButton + " 3 час."
-	buttonText1Day           = domain.EmojiCounterclockwiseArrowsButton + " 1 ден."
-	buttonText1Week          = domain.EmojiCounterclockwiseArrowsButton + " 1 нед."
-	buttonText1Month         = domain.EmojiCounterclockwiseArrowsButton + " 1 мес."
+	buttonTextBlockUser      = domain.EmojiProhibited + " Заблокировать"
+	buttonTextUnblockUser    = domain.EmojiWhiteHeavyCheckMark + " Разблокировать"
+	buttonTextCatchUpSkip    = domain.EmojiCrossMark + " Пропустить"
+
+	buttonTextAddChecklistItems = domain.EmojiPlus + " Добавить пункты"
+	buttonTextConfirmRemindAt   = domain.EmojiWhiteHeavyCheckMark + " Подтвердить"
+	buttonTextReenterRemindAt   = domain.EmojiMemo + " Ввести заново"
+	buttonTextCalendar          = domain.EmojiCalendar + " Календарь"
 )
 
-func setReplyMarkup(tbMsg *tbapi.MessageConfig, resp BotResponse) {
+// replyMarkup builds inline keyboard from response options. Returns nil if response has no buttons.
+func replyMarkup(resp BotResponse) *tbapi.InlineKeyboardMarkup {
+	var rows [][]tbapi.InlineKeyboardButton
+
+	for _, item := range resp.checklist {
+		text := domain.EmojiWhiteLargeSquare + " " + item.Text
+		if item.Done {
+			text = domain.EmojiWhiteHeavyCheckMark + " " + item.Text
+		}
+
+		rows = append(rows, tbapi.NewInlineKeyboardRow(
+			tbapi.NewInlineKeyboardButtonData(text, domain.ButtonDataPrefixToggleChecklistItem+strconv.FormatInt(item.ID, 10)),
+		))
+	}
+
 	if resp.showMyReminderListEditButtons {
-		tbMsg.ReplyMarkup = tbapi.NewInlineKeyboardMarkup(
+		rows = append(rows,
 			tbapi.NewInlineKeyboardRow(
 				tbapi.NewInlineKeyboardButtonData(buttonTextEditReminder, domain.ButtonDataEditReminder),
 				tbapi.NewInlineKeyboardButtonData(buttonTextRemoveReminder, domain.ButtonDataRemoveReminder),
 			),
+			tbapi.NewInlineKeyboardRow(
+				tbapi.NewInlineKeyboardButtonData(buttonTextHistory, domain.ButtonDataReminderHistory),
+			),
 		)
 	}
 
 	if resp.showReminderDatesButtons {
-		tbMsg.ReplyMarkup = tbapi.NewInlineKeyboardMarkup(
+		rows = append(rows, quickOptionRows(resp.quickOptions, quickOptionsPerRow, func(o domain.QuickOption) tbapi.InlineKeyboardButton {
+			return tbapi.NewInlineKeyboardButtonData(o.Label(), domain.ButtonDataPrefixRemindAtOption+string(o))
+		})...)
+		rows = append(rows,
 			tbapi.NewInlineKeyboardRow(
-				tbapi.NewInlineKeyboardButtonData("11:30", domain.ButtonDataPrefixRemindAtTime+"11:30"),
-				tbapi.NewInlineKeyboardButtonData("14:30", domain.ButtonDataPrefixRemindAtTime+"14:30"),
-				tbapi.NewInlineKeyboardButtonData("19:30", domain.ButtonDataPrefixRemindAtTime+"19:30"),
-				tbapi.NewInlineKeyboardButtonData("20:30", domain.ButtonDataPrefixRemindAtTime+"20:30"),
+				tbapi.NewInlineKeyboardButtonData(buttonTextCalendar, domain.ButtonDataPrefixRemindAtCalendar),
 			),
 			tbapi.NewInlineKeyboardRow(
-				tbapi.NewInlineKeyboardButtonData("30 мин", domain.ButtonDataPrefixRemindAtDuration+"30m"),
-				tbapi.NewInlineKeyboardButtonData("80 мин", domain.ButtonDataPrefixRemindAtDuration+"80m"),
-				tbapi.NewInlineKeyboardButtonData("1 день", domain.ButtonDataPrefixRemindAtDuration+"24h"),
-				tbapi.NewInlineKeyboardButtonData("1 месяц", domain.ButtonDataPrefixRemindAtDuration+"730h"),
+				tbapi.NewInlineKeyboardButtonData(domain.ReminderPriorityLow.Label(), domain.ButtonDataPrefixReminderPriority+string(domain.ReminderPriorityLow)),
+				tbapi.NewInlineKeyboardButtonData(domain.ReminderPriorityHigh.Label(), domain.ButtonDataPrefixReminderPriority+string(domain.ReminderPriorityHigh)),
+				tbapi.NewInlineKeyboardButtonData(domain.ReminderPriorityCritical.Label(), domain.ButtonDataPrefixReminderPriority+string(domain.ReminderPriorityCritical)),
 			),
 		)
 	}
 
-	if resp.showReminderDoneButtons {
-		reminderID := strconv.FormatInt(resp.reminderID, 10)
+	if !resp.calendarMonth.IsZero() {
+		rows = append(rows, calendarRows(resp.calendarMonth, resp.calendarToday)...)
+	}
+
+	if !resp.hourPickerDate.IsZero() {
+		rows = append(rows, hourPickerRows(resp.hourPickerDate)...)
+	}
 
-		tbMsg.ReplyMarkup = tbapi.NewInlineKeyboardMarkup(
+	if !resp.minutePickerHour.IsZero() {
+		rows = append(rows, minutePickerRows(resp.minutePickerHour)...)
+	}
+
+	if resp.showRemindAtConfirmButtons {
+		rows = append(rows,
 			tbapi.NewInlineKeyboardRow(
-				tbapi.NewInlineKeyboardButtonData(buttonText15Min, domain.ButtonDataPrefixDelayReminder+reminderID+"/30m"),
-				tbapi.NewInlineKeyboardButtonData(buttonText30Min, domain.ButtonDataPrefixDelayReminder+reminderID+"/80m"),
-				tbapi.NewInlineKeyboardButtonData(buttonText1Hour, domain.ButtonDataPrefixDelayReminder+reminderID+"/3h"),
+				tbapi.NewInlineKeyboardButtonData(buttonTextConfirmRemindAt, domain.ButtonDataConfirmRemindAt),
 			),
 			tbapi.NewInlineKeyboardRow(
-				tbapi.NewInlineKeyboardButtonData(buttonText1Day, domain.ButtonDataPrefixDelayReminder+reminderID+"/24h"),
-				tbapi.NewInlineKeyboardButtonData(buttonText1Week, domain.ButtonDataPrefixDelayReminder+reminderID+"/168h"),
-				tbapi.NewInlineKeyboardButtonData(buttonText1Month, domain.ButtonDataPrefixDelayReminder+reminderID+"/730h"),
+				tbapi.NewInlineKeyboardButtonData("-1 ч.", domain.ButtonDataPrefixShiftRemindAt+"-1h"),
+				tbapi.NewInlineKeyboardButtonData("+1 ч.", domain.ButtonDataPrefixShiftRemindAt+"1h"),
+				tbapi.NewInlineKeyboardButtonData("-1 дн.", domain.ButtonDataPrefixShiftRemindAt+"-24h"),
+				tbapi.NewInlineKeyboardButtonData("+1 дн.", domain.ButtonDataPrefixShiftRemindAt+"24h"),
 			),
+			tbapi.NewInlineKeyboardRow(
+				tbapi.NewInlineKeyboardButtonData(buttonTextReenterRemindAt, domain.ButtonDataReenterRemindAt),
+			),
+		)
+	}
+
+	if resp.showReminderDoneButtons {
+		reminderID := strconv.FormatInt(resp.reminderID, 10)
+
+		rows = append(rows, quickOptionRows(resp.snoozeOptions, snoozeOptionsPerRow, func(o domain.QuickOption) tbapi.InlineKeyboardButton {
+			return tbapi.NewInlineKeyboardButtonData(domain.EmojiCounterclockwiseArrowsButton+" "+o.Label(), domain.ButtonDataPrefixDelayReminder+reminderID+"/"+string(o))
+		})...)
+		rows = append(rows,
 			tbapi.NewInlineKeyboardRow(
 				tbapi.NewInlineKeyboardButtonData(buttonTextReminderDone, domain.ButtonDataPrefixReminderDone+reminderID),
 			),
 		)
 	}
+
+	if resp.showCatchUpButtons {
+		rows = append(rows, quickOptionRows(resp.snoozeOptions, snoozeOptionsPerRow, func(o domain.QuickOption) tbapi.InlineKeyboardButton {
+			return tbapi.NewInlineKeyboardButtonData(domain.EmojiCounterclockwiseArrowsButton+" "+o.Label(), domain.ButtonDataPrefixCatchUp+resp.summaryKey+"/"+string(o))
+		})...)
+		rows = append(rows,
+			tbapi.NewInlineKeyboardRow(
+				tbapi.NewInlineKeyboardButtonData(buttonTextCatchUpSkip, domain.ButtonDataCatchUpSkip),
+			),
+		)
+	}
+
+	if resp.showLeadTimesButtons {
+		reminderID := strconv.FormatInt(resp.reminderID, 10)
+		button := func(d time.Duration) tbapi.InlineKeyboardButton {
+			return tbapi.NewInlineKeyboardButtonData(domain.EmojiBell+" за "+domain.FormatDuration(d), domain.ButtonDataPrefixLeadTime+reminderID+"/"+d.String())
+		}
+
+		rows = append(rows,
+			tbapi.NewInlineKeyboardRow(button(24*time.Hour), button(3*time.Hour), button(1*time.Hour)),
+			tbapi.NewInlineKeyboardRow(button(30*time.Minute), button(10*time.Minute)),
+		)
+	}
+
+	if resp.showAddChecklistItemsButton {
+		rows = append(rows, tbapi.NewInlineKeyboardRow(
+			tbapi.NewInlineKeyboardButtonData(buttonTextAddChecklistItems, domain.ButtonDataPrefixAddChecklistItems+strconv.FormatInt(resp.reminderID, 10)),
+		))
+	}
+
+	for _, u := range resp.adminUsers {
+		userID := strconv.FormatInt(u.ID, 10)
+
+		button := tbapi.NewInlineKeyboardButtonData(fmt.Sprintf("%s @%s", buttonTextBlockUser, u.Name), domain.ButtonDataPrefixAdminBlockUser+userID)
+		if u.Status == domain.UserStatusBlocked {
+			button = tbapi.NewInlineKeyboardButtonData(fmt.Sprintf("%s @%s", buttonTextUnblockUser, u.Name), domain.ButtonDataPrefixAdminUnblockUser+userID)
+		}
+
+		rows = append(rows, tbapi.NewInlineKeyboardRow(button))
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	markup := tbapi.NewInlineKeyboardMarkup(rows...)
+	return &markup
+}
+
+const (
+	quickOptionsPerRow  = 4
+	snoozeOptionsPerRow = 3
+)
+
+// quickOptionRows splits buttons of quick options into rows of perRow buttons.
+func quickOptionRows(options domain.QuickOptions, perRow int, button func(domain.QuickOption) tbapi.InlineKeyboardButton) [][]tbapi.InlineKeyboardButton {
+	var rows [][]tbapi.InlineKeyboardButton
+	for i := 0; i < len(options); i += perRow {
+		row := make([]tbapi.InlineKeyboardButton, 0, perRow)
+		for _, o := range options[i:min(i+perRow, len(options))] {
+			row = append(row, button(o))
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
 }
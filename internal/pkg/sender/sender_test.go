@@ -1,10 +1,12 @@
 package sender
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	tbapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -52,7 +54,7 @@ func Test_botResponseSender_SendBotResponse(t *testing.T) {
 				botAPIMock.SendFunc = func(c tbapi.Chattable) (tbapi.Message, error) {
 					if firstCall {
 						firstCall = false
-						return tbapi.Message{}, errors.New("some error")
+						return tbapi.Message{}, &tbapi.Error{Code: 400, Message: "Bad Request: can't parse entities: Can't find end of the entity starting at byte offset 12"}
 					}
 
 					a.Equal(tbapi.MessageConfig{
@@ -82,6 +84,19 @@ func Test_botResponseSender_SendBotResponse(t *testing.T) {
 			},
 			expErr: `can't send message to telegram "Pipeline arts speakers realized choose aviation thong, adopt events switching info platforms units specialized, particular pants compatibility determines attachments pee assignment, licking tradition fool synthetic survivors denial alice.": some internal error`,
 		},
+		{
+			name: "error: bot is blocked, message isn't sent again as plain text",
+			resp: BotResponse{ChatID: 2, Text: "foo"},
+			setMocks: func(a *assert.Assertions, botAPIMock *BotAPIMock) {
+				var calls int
+				botAPIMock.SendFunc = func(c tbapi.Chattable) (tbapi.Message, error) {
+					calls++
+					a.Equal(1, calls)
+					return tbapi.Message{}, &tbapi.Error{Code: 403, Message: "Forbidden: bot was blocked by the user"}
+				}
+			},
+			expErr: `can't send message to telegram "foo": bot is blocked by user: Forbidden: bot was blocked by the user`,
+		},
 		{
 			name: "success: WithMyRemindersListEditButtons option",
 			resp: BotResponse{
@@ -101,6 +116,9 @@ func Test_botResponseSender_SendBotResponse(t *testing.T) {
 									tbapi.NewInlineKeyboardButtonData("📝 Редактировать", "btn_edit_reminder"),
 									tbapi.NewInlineKeyboardButtonData("❌ Удалить", "btn_remove_reminder"),
 								),
+								tbapi.NewInlineKeyboardRow(
+									tbapi.NewInlineKeyboardButtonData("📜 История", "btn_reminder_history"),
+								),
 							),
 						},
 						Text:                  "Pipeline arts speakers realized choose aviation thong, adopt events switching info platforms units specialized, particular pants compatibility determines attachments pee assignment, licking tradition fool synthetic survivors denial alice.",
@@ -118,7 +136,7 @@ func Test_botResponseSender_SendBotResponse(t *testing.T) {
 				ReplyToMessageID: 4,
 				Text:             "Pipeline arts speakers realized choose aviation thong, adopt events switching info platforms units specialized, particular pants compatibility determines attachments pee assignment, licking tradition fool synthetic survivors denial alice.",
 			},
-			opts: []BotResponseOption{WithReminderDatesButtons()},
+			opts: []BotResponseOption{WithReminderDatesButtons(nil)},
 			setMocks: func(a *assert.Assertions, botAPIMock *BotAPIMock) {
 				botAPIMock.SendFunc = func(c tbapi.Chattable) (tbapi.Message, error) {
 					a.Equal(tbapi.MessageConfig{
@@ -127,16 +145,24 @@ func Test_botResponseSender_SendBotResponse(t *testing.T) {
 							ReplyToMessageID: 4,
 							ReplyMarkup: tbapi.NewInlineKeyboardMarkup(
 								tbapi.NewInlineKeyboardRow(
-									tbapi.NewInlineKeyboardButtonData("11:30", "btn_remind_at/time/11:30"),
-									tbapi.NewInlineKeyboardButtonData("14:30", "btn_remind_at/time/14:30"),
-									tbapi.NewInlineKeyboardButtonData("19:30", "btn_remind_at/time/19:30"),
-									tbapi.NewInlineKeyboardButtonData("20:30", "btn_remind_at/time/20:30"),
+									tbapi.NewInlineKeyboardButtonData("11:30", "btn_remind_at/option/11:30"),
+									tbapi.NewInlineKeyboardButtonData("14:30", "btn_remind_at/option/14:30"),
+									tbapi.NewInlineKeyboardButtonData("19:30", "btn_remind_at/option/19:30"),
+									tbapi.NewInlineKeyboardButtonData("20:30", "btn_remind_at/option/20:30"),
+								),
+								tbapi.NewInlineKeyboardRow(
+									tbapi.NewInlineKeyboardButtonData("30 мин.", "btn_remind_at/option/30m"),
+									tbapi.NewInlineKeyboardButtonData("1 ч. 20 мин.", "btn_remind_at/option/80m"),
+									tbapi.NewInlineKeyboardButtonData("1 дн.", "btn_remind_at/option/1d"),
+									tbapi.NewInlineKeyboardButtonData("1 мес.", "btn_remind_at/option/730h"),
 								),
 								tbapi.NewInlineKeyboardRow(
-									tbapi.NewInlineKeyboardButtonData("30 мин", "btn_remind_at/duration/30m"),
-									tbapi.NewInlineKeyboardButtonData("80 мин", "btn_remind_at/duration/80m"),
-									tbapi.NewInlineKeyboardButtonData("1 день", "btn_remind_at/duration/24h"),
-									tbapi.NewInlineKeyboardButtonData("1 месяц", "btn_remind_at/duration/730h"),
+									tbapi.NewInlineKeyboardButtonData("📅 Календарь", "btn_remind_at/calendar/"),
+								),
+								tbapi.NewInlineKeyboardRow(
+									tbapi.NewInlineKeyboardButtonData("🔽 Низкий", "btn_reminder_priority/low"),
+									tbapi.NewInlineKeyboardButtonData("🔺 Высокий", "btn_reminder_priority/high"),
+									tbapi.NewInlineKeyboardButtonData("🚨 Критический", "btn_reminder_priority/critical"),
 								),
 							),
 						},
@@ -148,6 +174,81 @@ func Test_botResponseSender_SendBotResponse(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "success: WithReminderDatesButtons option with user's quick options",
+			resp: BotResponse{
+				ChatID: 2,
+				Text:   "When?",
+			},
+			opts: []BotResponseOption{WithReminderDatesButtons(domain.QuickOptions{"09:00", "15m", "2h", "1w", domain.QuickOptionTomorrowMorning})},
+			setMocks: func(a *assert.Assertions, botAPIMock *BotAPIMock) {
+				botAPIMock.SendFunc = func(c tbapi.Chattable) (tbapi.Message, error) {
+					a.Equal(tbapi.MessageConfig{
+						BaseChat: tbapi.BaseChat{
+							ChatID: 2,
+							ReplyMarkup: tbapi.NewInlineKeyboardMarkup(
+								tbapi.NewInlineKeyboardRow(
+									tbapi.NewInlineKeyboardButtonData("09:00", "btn_remind_at/option/09:00"),
+									tbapi.NewInlineKeyboardButtonData("15 мин.", "btn_remind_at/option/15m"),
+									tbapi.NewInlineKeyboardButtonData("2 ч.", "btn_remind_at/option/2h"),
+									tbapi.NewInlineKeyboardButtonData("1 нед.", "btn_remind_at/option/1w"),
+								),
+								tbapi.NewInlineKeyboardRow(
+									tbapi.NewInlineKeyboardButtonData("Завтра утром", "btn_remind_at/option/tomorrow_morning"),
+								),
+								tbapi.NewInlineKeyboardRow(
+									tbapi.NewInlineKeyboardButtonData("📅 Календарь", "btn_remind_at/calendar/"),
+								),
+								tbapi.NewInlineKeyboardRow(
+									tbapi.NewInlineKeyboardButtonData("🔽 Низкий", "btn_reminder_priority/low"),
+									tbapi.NewInlineKeyboardButtonData("🔺 Высокий", "btn_reminder_priority/high"),
+									tbapi.NewInlineKeyboardButtonData("🚨 Критический", "btn_reminder_priority/critical"),
+								),
+							),
+						},
+						Text:                  "When?",
+						ParseMode:             "Markdown",
+						DisableWebPagePreview: true,
+					}, c)
+					return tbapi.Message{}, nil
+				}
+			},
+		},
+		{
+			name: "success: WithRemindAtConfirmButtons option",
+			resp: BotResponse{
+				ChatID: 2,
+				Text:   "Confirm",
+			},
+			opts: []BotResponseOption{WithRemindAtConfirmButtons()},
+			setMocks: func(a *assert.Assertions, botAPIMock *BotAPIMock) {
+				botAPIMock.SendFunc = func(c tbapi.Chattable) (tbapi.Message, error) {
+					a.Equal(tbapi.MessageConfig{
+						BaseChat: tbapi.BaseChat{
+							ChatID: 2,
+							ReplyMarkup: tbapi.NewInlineKeyboardMarkup(
+								tbapi.NewInlineKeyboardRow(
+									tbapi.NewInlineKeyboardButtonData("✅ Подтвердить", "btn_remind_at_confirm"),
+								),
+								tbapi.NewInlineKeyboardRow(
+									tbapi.NewInlineKeyboardButtonData("-1 ч.", "btn_remind_at_shift/-1h"),
+									tbapi.NewInlineKeyboardButtonData("+1 ч.", "btn_remind_at_shift/1h"),
+									tbapi.NewInlineKeyboardButtonData("-1 дн.", "btn_remind_at_shift/-24h"),
+									tbapi.NewInlineKeyboardButtonData("+1 дн.", "btn_remind_at_shift/24h"),
+								),
+								tbapi.NewInlineKeyboardRow(
+									tbapi.NewInlineKeyboardButtonData("📝 Ввести заново", "btn_remind_at_reenter"),
+								),
+							),
+						},
+						Text:                  "Confirm",
+						ParseMode:             "Markdown",
+						DisableWebPagePreview: true,
+					}, c)
+					return tbapi.Message{}, nil
+				}
+			},
+		},
 		{
 			name: "success: WithReminderDoneButton option",
 			resp: BotResponse{
@@ -155,7 +256,7 @@ func Test_botResponseSender_SendBotResponse(t *testing.T) {
 				ReplyToMessageID: 4,
 				Text:             "Pipeline arts speakers realized choose aviation thong, adopt events switching info platforms units specialized, particular pants compatibility determines attachments pee assignment, licking tradition fool synthetic survivors denial alice.",
 			},
-			opts: []BotResponseOption{WithReminderDoneButton(12345)},
+			opts: []BotResponseOption{WithReminderDoneButton(12345, nil)},
 			setMocks: func(a *assert.Assertions, botAPIMock *BotAPIMock) {
 				botAPIMock.SendFunc = func(c tbapi.Chattable) (tbapi.Message, error) {
 					a.Equal(tbapi.MessageConfig{
@@ -165,12 +266,12 @@ func Test_botResponseSender_SendBotResponse(t *testing.T) {
 							ReplyMarkup: tbapi.NewInlineKeyboardMarkup(
 								tbapi.NewInlineKeyboardRow(
 									tbapi.NewInlineKeyboardButtonData("🔄 30 мин.", "btn_delay_reminder/12345/30m"),
-									tbapi.NewInlineKeyboardButtonData("🔄 80 мин.", "btn_delay_reminder/12345/80m"),
-									tbapi.NewInlineKeyboardButtonData("🔄 3 час.", "btn_delay_reminder/12345/3h"),
+									tbapi.NewInlineKeyboardButtonData("🔄 1 ч. 20 мин.", "btn_delay_reminder/12345/80m"),
+									tbapi.NewInlineKeyboardButtonData("🔄 3 ч.", "btn_delay_reminder/12345/3h"),
 								),
 								tbapi.NewInlineKeyboardRow(
-									tbapi.NewInlineKeyboardButtonData("🔄 1 ден.", "btn_delay_reminder/12345/24h"),
-									tbapi.NewInlineKeyboardButtonData("🔄 1 нед.", "btn_delay_reminder/12345/168h"),
+									tbapi.NewInlineKeyboardButtonData("🔄 1 дн.", "btn_delay_reminder/12345/1d"),
+									tbapi.NewInlineKeyboardButtonData("🔄 1 нед.", "btn_delay_reminder/12345/1w"),
 									tbapi.NewInlineKeyboardButtonData("🔄 1 мес.", "btn_delay_reminder/12345/730h"),
 								),
 								tbapi.NewInlineKeyboardRow(
@@ -186,6 +287,265 @@ func Test_botResponseSender_SendBotResponse(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "success: WithCatchUpButtons option",
+			resp: BotResponse{ChatID: 2, Text: "Missed"},
+			opts: []BotResponseOption{WithCatchUpButtons("0123456789abcdef", domain.QuickOptions{"1h", "tomorrow_morning"})},
+			setMocks: func(a *assert.Assertions, botAPIMock *BotAPIMock) {
+				botAPIMock.SendFunc = func(c tbapi.Chattable) (tbapi.Message, error) {
+					a.Equal(tbapi.MessageConfig{
+						BaseChat: tbapi.BaseChat{
+							ChatID: 2,
+							ReplyMarkup: tbapi.NewInlineKeyboardMarkup(
+								tbapi.NewInlineKeyboardRow(
+									tbapi.NewInlineKeyboardButtonData("🔄 1 ч.", "btn_catch_up/0123456789abcdef/1h"),
+									tbapi.NewInlineKeyboardButtonData("🔄 Завтра утром", "btn_catch_up/0123456789abcdef/tomorrow_morning"),
+								),
+								tbapi.NewInlineKeyboardRow(
+									tbapi.NewInlineKeyboardButtonData("❌ Пропустить", "btn_catch_up_skip"),
+								),
+							),
+						},
+						Text:                  "Missed",
+						ParseMode:             "Markdown",
+						DisableWebPagePreview: true,
+					}, c)
+					return tbapi.Message{}, nil
+				}
+			},
+		},
+		{
+			name: "success: WithLeadTimesButtons option",
+			resp: BotResponse{
+				ChatID: 2,
+				Text:   "Created",
+			},
+			opts: []BotResponseOption{WithLeadTimesButtons(12345)},
+			setMocks: func(a *assert.Assertions, botAPIMock *BotAPIMock) {
+				botAPIMock.SendFunc = func(c tbapi.Chattable) (tbapi.Message, error) {
+					a.Equal(tbapi.MessageConfig{
+						BaseChat: tbapi.BaseChat{
+							ChatID: 2,
+							ReplyMarkup: tbapi.NewInlineKeyboardMarkup(
+								tbapi.NewInlineKeyboardRow(
+									tbapi.NewInlineKeyboardButtonData("🔔 за 1 дн.", "btn_lead_time/12345/24h0m0s"),
+									tbapi.NewInlineKeyboardButtonData("🔔 за 3 ч.", "btn_lead_time/12345/3h0m0s"),
+									tbapi.NewInlineKeyboardButtonData("🔔 за 1 ч.", "btn_lead_time/12345/1h0m0s"),
+								),
+								tbapi.NewInlineKeyboardRow(
+									tbapi.NewInlineKeyboardButtonData("🔔 за 30 мин.", "btn_lead_time/12345/30m0s"),
+									tbapi.NewInlineKeyboardButtonData("🔔 за 10 мин.", "btn_lead_time/12345/10m0s"),
+								),
+							),
+						},
+						Text:                  "Created",
+						ParseMode:             "Markdown",
+						DisableWebPagePreview: true,
+					}, c)
+					return tbapi.Message{}, nil
+				}
+			},
+		},
+		{
+			name: "success: WithAddChecklistItemsButton option",
+			resp: BotResponse{
+				ChatID: 2,
+				Text:   "Created",
+			},
+			opts: []BotResponseOption{WithAddChecklistItemsButton(12345)},
+			setMocks: func(a *assert.Assertions, botAPIMock *BotAPIMock) {
+				botAPIMock.SendFunc = func(c tbapi.Chattable) (tbapi.Message, error) {
+					a.Equal(tbapi.MessageConfig{
+						BaseChat: tbapi.BaseChat{
+							ChatID: 2,
+							ReplyMarkup: tbapi.NewInlineKeyboardMarkup(
+								tbapi.NewInlineKeyboardRow(
+									tbapi.NewInlineKeyboardButtonData("➕ Добавить пункты", "btn_checklist_add/12345"),
+								),
+							),
+						},
+						Text:                  "Created",
+						ParseMode:             "Markdown",
+						DisableWebPagePreview: true,
+					}, c)
+					return tbapi.Message{}, nil
+				}
+			},
+		},
+		{
+			name: "success: edit message, WithChecklistButtons and WithReminderDoneButton options",
+			resp: BotResponse{
+				ChatID:        2,
+				EditMessageID: 7,
+				Text:          "Checklist",
+			},
+			opts: []BotResponseOption{
+				WithChecklistButtons(domain.Checklist{
+					{ID: 1, ReminderID: 12345, Text: "Foo", Done: true},
+					{ID: 2, ReminderID: 12345, Text: "Bar"},
+				}),
+				WithReminderDoneButton(12345, nil),
+			},
+			setMocks: func(a *assert.Assertions, botAPIMock *BotAPIMock) {
+				botAPIMock.SendFunc = func(c tbapi.Chattable) (tbapi.Message, error) {
+					markup := tbapi.NewInlineKeyboardMarkup(
+						tbapi.NewInlineKeyboardRow(
+							tbapi.NewInlineKeyboardButtonData("✅ Foo", "btn_checklist_item/1"),
+						),
+						tbapi.NewInlineKeyboardRow(
+							tbapi.NewInlineKeyboardButtonData("⬜ Bar", "btn_checklist_item/2"),
+						),
+						tbapi.NewInlineKeyboardRow(
+							tbapi.NewInlineKeyboardButtonData("🔄 30 мин.", "btn_delay_reminder/12345/30m"),
+							tbapi.NewInlineKeyboardButtonData("🔄 1 ч. 20 мин.", "btn_delay_reminder/12345/80m"),
+							tbapi.NewInlineKeyboardButtonData("🔄 3 ч.", "btn_delay_reminder/12345/3h"),
+						),
+						tbapi.NewInlineKeyboardRow(
+							tbapi.NewInlineKeyboardButtonData("🔄 1 дн.", "btn_delay_reminder/12345/1d"),
+							tbapi.NewInlineKeyboardButtonData("🔄 1 нед.", "btn_delay_reminder/12345/1w"),
+							tbapi.NewInlineKeyboardButtonData("🔄 1 мес.", "btn_delay_reminder/12345/730h"),
+						),
+						tbapi.NewInlineKeyboardRow(
+							tbapi.NewInlineKeyboardButtonData("✅ Готово", "btn_reminder_done/12345"),
+						),
+					)
+					a.Equal(tbapi.EditMessageTextConfig{
+						BaseEdit: tbapi.BaseEdit{
+							ChatID:      2,
+							MessageID:   7,
+							ReplyMarkup: &markup,
+						},
+						Text:                  "Checklist",
+						ParseMode:             "Markdown",
+						DisableWebPagePreview: true,
+					}, c)
+					return tbapi.Message{}, nil
+				}
+			},
+		},
+		{
+			name: "success: edit message without buttons",
+			resp: BotResponse{
+				ChatID:        2,
+				EditMessageID: 7,
+				Text:          "Done",
+			},
+			setMocks: func(a *assert.Assertions, botAPIMock *BotAPIMock) {
+				botAPIMock.SendFunc = func(c tbapi.Chattable) (tbapi.Message, error) {
+					a.Equal(tbapi.EditMessageTextConfig{
+						BaseEdit: tbapi.BaseEdit{
+							ChatID:    2,
+							MessageID: 7,
+						},
+						Text:                  "Done",
+						ParseMode:             "Markdown",
+						DisableWebPagePreview: true,
+					}, c)
+					return tbapi.Message{}, nil
+				}
+			},
+		},
+		{
+			name: "error: edit message, telegram api returns error",
+			resp: BotResponse{
+				ChatID:        2,
+				EditMessageID: 7,
+				Text:          "Done",
+			},
+			setMocks: func(a *assert.Assertions, botAPIMock *BotAPIMock) {
+				botAPIMock.SendFunc = func(c tbapi.Chattable) (tbapi.Message, error) {
+					return tbapi.Message{}, errors.New("message to edit not found")
+				}
+			},
+			expErr: `can't edit message 7 in telegram "Done": message to edit not found`,
+		},
+		{
+			name: "success: WithAdminUsersButtons option",
+			resp: BotResponse{
+				ChatID: 2,
+				Text:   "Users",
+			},
+			opts: []BotResponseOption{WithAdminUsersButtons([]domain.User{
+				{ID: 1, Name: "foo", Status: domain.UserStatusActive},
+				{ID: 2, Name: "bar", Status: domain.UserStatusBlocked},
+			})},
+			setMocks: func(a *assert.Assertions, botAPIMock *BotAPIMock) {
+				botAPIMock.SendFunc = func(c tbapi.Chattable) (tbapi.Message, error) {
+					a.Equal(tbapi.MessageConfig{
+						BaseChat: tbapi.BaseChat{
+							ChatID: 2,
+							ReplyMarkup: tbapi.NewInlineKeyboardMarkup(
+								tbapi.NewInlineKeyboardRow(
+									tbapi.NewInlineKeyboardButtonData("🚫 Заблокировать @foo", "btn_admin_block/1"),
+								),
+								tbapi.NewInlineKeyboardRow(
+									tbapi.NewInlineKeyboardButtonData("✅ Разблокировать @bar", "btn_admin_unblock/2"),
+								),
+							),
+						},
+						Text:                  "Users",
+						ParseMode:             "Markdown",
+						DisableWebPagePreview: true,
+					}, c)
+					return tbapi.Message{}, nil
+				}
+			},
+		},
+		{
+			name: "success: WithDisableNotification option",
+			resp: BotResponse{
+				ChatID: 2,
+				Text:   "Silent",
+			},
+			opts: []BotResponseOption{WithDisableNotification()},
+			setMocks: func(a *assert.Assertions, botAPIMock *BotAPIMock) {
+				botAPIMock.SendFunc = func(c tbapi.Chattable) (tbapi.Message, error) {
+					a.Equal(tbapi.MessageConfig{
+						BaseChat: tbapi.BaseChat{
+							ChatID:              2,
+							DisableNotification: true,
+						},
+						Text:                  "Silent",
+						ParseMode:             "Markdown",
+						DisableWebPagePreview: true,
+					}, c)
+					return tbapi.Message{}, nil
+				}
+			},
+		},
+		{
+			name: "success: WithPin option",
+			resp: BotResponse{
+				ChatID: 2,
+				Text:   "Pinned",
+			},
+			opts: []BotResponseOption{WithPin()},
+			setMocks: func(a *assert.Assertions, botAPIMock *BotAPIMock) {
+				botAPIMock.SendFunc = func(c tbapi.Chattable) (tbapi.Message, error) {
+					return tbapi.Message{MessageID: 42}, nil
+				}
+				botAPIMock.RequestFunc = func(c tbapi.Chattable) (*tbapi.APIResponse, error) {
+					a.Equal(tbapi.PinChatMessageConfig{ChatID: 2, MessageID: 42}, c)
+					return &tbapi.APIResponse{Ok: true}, nil
+				}
+			},
+		},
+		{
+			name: "success: WithPin option, pin failed",
+			resp: BotResponse{
+				ChatID: 2,
+				Text:   "Pinned",
+			},
+			opts: []BotResponseOption{WithPin()},
+			setMocks: func(a *assert.Assertions, botAPIMock *BotAPIMock) {
+				botAPIMock.SendFunc = func(c tbapi.Chattable) (tbapi.Message, error) {
+					return tbapi.Message{MessageID: 42}, nil
+				}
+				botAPIMock.RequestFunc = func(c tbapi.Chattable) (*tbapi.APIResponse, error) {
+					return nil, errors.New("not enough rights to pin a message")
+				}
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -203,7 +563,7 @@ func Test_botResponseSender_SendBotResponse(t *testing.T) {
 			senderImpl := New(&botAPIMock)
 
 			// ACT
-			err := senderImpl.SendBotResponse(tc.resp, tc.opts...)
+			err := senderImpl.SendBotResponse(context.TODO(), tc.resp, tc.opts...)
 
 			// ASSERT
 			if tc.expErr != "" {
@@ -18,6 +18,9 @@ var _ BotAPI = &BotAPIMock{}
 //
 //		// make and configure a mocked BotAPI
 //		mockedBotAPI := &BotAPIMock{
+//			RequestFunc: func(c tbapi.Chattable) (*tbapi.APIResponse, error) {
+//				panic("mock out the Request method")
+//			},
 //			SendFunc: func(c tbapi.Chattable) (tbapi.Message, error) {
 //				panic("mock out the Send method")
 //			},
@@ -28,18 +31,66 @@ var _ BotAPI = &BotAPIMock{}
 //
 //	}
 type BotAPIMock struct {
+	// RequestFunc mocks the Request method.
+	RequestFunc func(c tbapi.Chattable) (*tbapi.APIResponse, error)
+
 	// SendFunc mocks the Send method.
 	SendFunc func(c tbapi.Chattable) (tbapi.Message, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// Request holds details about calls to the Request method.
+		Request []struct {
+			// C is the c argument value.
+			C tbapi.Chattable
+		}
 		// Send holds details about calls to the Send method.
 		Send []struct {
 			// C is the c argument value.
 			C tbapi.Chattable
 		}
 	}
-	lockSend sync.RWMutex
+	lockRequest sync.RWMutex
+	lockSend    sync.RWMutex
+}
+
+// Request calls RequestFunc.
+func (mock *BotAPIMock) Request(c tbapi.Chattable) (*tbapi.APIResponse, error) {
+	if mock.RequestFunc == nil {
+		panic("BotAPIMock.RequestFunc: method is nil but BotAPI.Request was just called")
+	}
+	callInfo := struct {
+		C tbapi.Chattable
+	}{
+		C: c,
+	}
+	mock.lockRequest.Lock()
+	mock.calls.Request = append(mock.calls.Request, callInfo)
+	mock.lockRequest.Unlock()
+	return mock.RequestFunc(c)
+}
+
+// RequestCalls gets all the calls that were made to Request.
+// Check the length with:
+//
+//	len(mockedBotAPI.RequestCalls())
+func (mock *BotAPIMock) RequestCalls() []struct {
+	C tbapi.Chattable
+} {
+	var calls []struct {
+		C tbapi.Chattable
+	}
+	mock.lockRequest.RLock()
+	calls = mock.calls.Request
+	mock.lockRequest.RUnlock()
+	return calls
+}
+
+// ResetRequestCalls reset all the calls that were made to Request.
+func (mock *BotAPIMock) ResetRequestCalls() {
+	mock.lockRequest.Lock()
+	mock.calls.Request = nil
+	mock.lockRequest.Unlock()
 }
 
 // Send calls SendFunc.
@@ -83,6 +134,10 @@ func (mock *BotAPIMock) ResetSendCalls() {
 
 // ResetCalls reset all the calls that were made to all mocked methods.
 func (mock *BotAPIMock) ResetCalls() {
+	mock.lockRequest.Lock()
+	mock.calls.Request = nil
+	mock.lockRequest.Unlock()
+
 	mock.lockSend.Lock()
 	mock.calls.Send = nil
 	mock.lockSend.Unlock()
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+)
+
+// ErrAPITokenNotFound - API token is not found or revoked.
+var ErrAPITokenNotFound = errors.New("api token is not found")
+
+// SaveAPIToken - saves API token, previous tokens of the user of the same kind are revoked.
+// Token without kind is saved as [domain.APITokenKindAPI]. msgs are enqueued to outbox in the same transaction.
+func (s *Storage) SaveAPIToken(ctx context.Context, token domain.APIToken, msgs ...domain.OutboxMessage) error {
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = timeNowUTC()
+	}
+	if token.Kind == "" {
+		token.Kind = domain.APITokenKindAPI
+	}
+
+	err := s.inTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM api_tokens WHERE user_id = $1 AND kind = $2;`, token.UserID, token.Kind); err != nil {
+			return fmt.Errorf("failed to revoke api tokens: %w", err)
+		}
+
+		const query = `INSERT INTO api_tokens(
+            token_hash
+            , kind
+            , user_id
+            , chat_id
+            , created_at
+		) VALUES ($1, $2, $3, $4, $5)`
+
+		if _, err := tx.ExecContext(ctx, query, token.TokenHash, token.Kind, token.UserID, token.ChatID, token.CreatedAt); err != nil {
+			return fmt.Errorf("failed to insert api token: %w", err)
+		}
+
+		return insertOutboxMessages(ctx, tx, msgs)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save api token %s: %w", token, err)
+	}
+
+	logging.Printf(ctx, "[INFO] saved api token %s", token)
+
+	return nil
+}
+
+// GetAPIToken - returns API token by hash of the token, see [domain.HashAPIToken]. Tokens of users who aren't active
+// are not returned.
+func (s *Storage) GetAPIToken(ctx context.Context, tokenHash string) (domain.APIToken, error) {
+	const query = `
+		SELECT
+		    t.token_hash
+			, t.kind
+			, t.user_id
+			, t.chat_id
+			, t.created_at
+		FROM api_tokens t
+		JOIN users u ON u.id = t.user_id
+		WHERE t.token_hash = $1 AND u.status = $2;`
+
+	var token domain.APIToken
+	if err := s.db.GetContext(ctx, &token, query, tokenHash, domain.UserStatusActive); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return domain.APIToken{}, fmt.Errorf("failed to get api token: %w", ErrAPITokenNotFound)
+		default:
+			return domain.APIToken{}, fmt.Errorf("failed to get api token: %w", err)
+		}
+	}
+
+	return token, nil
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+)
+
+func (s *storageTestSuite) Test_storage_APIToken() {
+	s.Run("success: token is saved and found by hash", func() {
+		// ARRANGE
+		s.mustSaveActiveUsers(1)
+		token := domain.APIToken{
+			TokenHash: domain.HashAPIToken("tgr_0123456789abcdef"),
+			Kind:      domain.APITokenKindAPI,
+			UserID:    1,
+			ChatID:    2,
+			CreatedAt: timeNowUTC().Truncate(1 * time.Minute),
+		}
+
+		// ACT
+		s.Require().NoError(s.storage.SaveAPIToken(context.TODO(), token))
+
+		// ASSERT
+		act, err := s.storage.GetAPIToken(context.TODO(), token.TokenHash)
+		s.Require().NoError(err)
+		s.Require().Equal(token, act)
+	})
+
+	s.Run("success: previous token of user is revoked", func() {
+		// ARRANGE
+		s.mustSaveActiveUsers(1, 3)
+		prev := domain.APIToken{TokenHash: domain.HashAPIToken("tgr_prev"), UserID: 1, ChatID: 2}
+		s.Require().NoError(s.storage.SaveAPIToken(context.TODO(), prev))
+
+		other := domain.APIToken{TokenHash: domain.HashAPIToken("tgr_other"), UserID: 3, ChatID: 3}
+		s.Require().NoError(s.storage.SaveAPIToken(context.TODO(), other))
+
+		// ACT
+		next := domain.APIToken{TokenHash: domain.HashAPIToken("tgr_next"), UserID: 1, ChatID: 2}
+		s.Require().NoError(s.storage.SaveAPIToken(context.TODO(), next))
+
+		// ASSERT
+		_, err := s.storage.GetAPIToken(context.TODO(), prev.TokenHash)
+		s.Require().ErrorIs(err, ErrAPITokenNotFound)
+
+		_, err = s.storage.GetAPIToken(context.TODO(), next.TokenHash)
+		s.Require().NoError(err)
+
+		_, err = s.storage.GetAPIToken(context.TODO(), other.TokenHash)
+		s.Require().NoError(err, "tokens of other users are kept")
+	})
+
+	s.Run("success: tokens of different kinds don't revoke each other", func() {
+		// ARRANGE
+		s.mustSaveActiveUsers(1)
+		apiToken := domain.APIToken{TokenHash: domain.HashAPIToken("tgr_api"), UserID: 1, ChatID: 2}
+		s.Require().NoError(s.storage.SaveAPIToken(context.TODO(), apiToken))
+
+		// ACT
+		hookToken := domain.APIToken{TokenHash: domain.HashAPIToken("tgh_hook"), Kind: domain.APITokenKindHook, UserID: 1, ChatID: 2}
+		s.Require().NoError(s.storage.SaveAPIToken(context.TODO(), hookToken))
+
+		// ASSERT
+		act, err := s.storage.GetAPIToken(context.TODO(), apiToken.TokenHash)
+		s.Require().NoError(err)
+		s.Require().Equal(domain.APITokenKindAPI, act.Kind, "kind of API token is set by default")
+
+		act, err = s.storage.GetAPIToken(context.TODO(), hookToken.TokenHash)
+		s.Require().NoError(err)
+		s.Require().Equal(domain.APITokenKindHook, act.Kind)
+	})
+
+	s.Run("error: token of inactive user is not found", func() {
+		// ARRANGE
+		s.Require().NoError(s.storage.SaveUser(context.TODO(), domain.User{ID: 1, Status: domain.UserStatusInactive}))
+		token := domain.APIToken{TokenHash: domain.HashAPIToken("tgr_inactive"), UserID: 1, ChatID: 1}
+		s.Require().NoError(s.storage.SaveAPIToken(context.TODO(), token))
+
+		// ACT
+		_, err := s.storage.GetAPIToken(context.TODO(), token.TokenHash)
+
+		// ASSERT
+		s.Require().ErrorIs(err, ErrAPITokenNotFound)
+	})
+
+	s.Run("error: token of unknown user is not found", func() {
+		// ARRANGE
+		token := domain.APIToken{TokenHash: domain.HashAPIToken("tgr_unknown"), UserID: 1, ChatID: 1}
+		s.Require().NoError(s.storage.SaveAPIToken(context.TODO(), token))
+
+		// ACT
+		_, err := s.storage.GetAPIToken(context.TODO(), token.TokenHash)
+
+		// ASSERT
+		s.Require().ErrorIs(err, ErrAPITokenNotFound)
+	})
+
+	s.Run("error: token does not exist", func() {
+		_, err := s.storage.GetAPIToken(context.TODO(), domain.HashAPIToken("unknown"))
+		s.Require().ErrorIs(err, ErrAPITokenNotFound)
+	})
+}
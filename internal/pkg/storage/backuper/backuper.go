@@ -64,7 +64,8 @@ func (b *Backuper) Run(ctx context.Context) {
 
 			backupFilename := fmt.Sprintf("%s/%s%s", b.backupDir, timeNowUTC().Format(time.RFC3339), backupFileExtension)
 
-			if _, err := b.db.ExecContext(ctx, "VACUUM INTO $1", backupFilename); err != nil {
+			// backup in progress is finished on shutdown, otherwise a partial backup file is left
+			if _, err := b.db.ExecContext(context.WithoutCancel(ctx), "VACUUM INTO $1", backupFilename); err != nil {
 				log.Printf("[ERROR] failed to do backup %s: %v", backupFilename, err)
 				continue
 			}
@@ -6,8 +6,9 @@ import (
 	"errors"
 	"fmt"
 
-	log "github.com/go-pkgz/lgr"
+	"github.com/jmoiron/sqlx"
 	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
 )
 
 var (
@@ -17,8 +18,8 @@ var (
 	ErrBotStateAlreadyExists = errors.New("bot state already exists")
 )
 
-// SaveBotState - saves bot state.
-func (s *Storage) SaveBotState(ctx context.Context, state domain.BotState) error {
+// SaveBotState - saves bot state, msgs are enqueued to outbox in the same transaction.
+func (s *Storage) SaveBotState(ctx context.Context, state domain.BotState, msgs ...domain.OutboxMessage) error {
 	if state.ModifiedAt.IsZero() {
 		state.ModifiedAt = timeNowUTC()
 	}
@@ -34,11 +35,18 @@ func (s *Storage) SaveBotState(ctx context.Context, state domain.BotState) error
 		, context = $3
 		, modified_at = $4;`
 
-	if _, err := s.db.ExecContext(ctx, query, state.UserID, state.Name, state.Context, state.ModifiedAt); err != nil {
+	err := s.inTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, query, state.UserID, state.Name, state.Context, state.ModifiedAt); err != nil {
+			return err
+		}
+
+		return insertOutboxMessages(ctx, tx, msgs)
+	})
+	if err != nil {
 		return fmt.Errorf("failed to save bot state %s: %w", state, err)
 	}
 
-	log.Printf("[INFO] saved bot state %s", state)
+	logging.Printf(ctx, "[INFO] saved bot state %s", state)
 
 	return nil
 }
@@ -64,7 +72,7 @@ func (s *Storage) GetBotState(ctx context.Context, userID int64) (domain.BotStat
 		}
 	}
 
-	log.Printf("[DEBUG] got bot state %s", state)
+	logging.Printf(ctx, "[DEBUG] got bot state %s", state)
 
 	return state, nil
 }
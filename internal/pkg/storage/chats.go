@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+)
+
+// DeactivateChat - deactivates chat which is not available to the bot anymore: pending reminders of chat are stopped
+// with [domain.ReminderStatusChatNotFound] status and pending Telegram messages of chat are dropped.
+func (s *Storage) DeactivateChat(ctx context.Context, chatID int64, reason string) error {
+	return s.inTx(ctx, func(tx *sqlx.Tx) error {
+		const query = `UPDATE reminders SET status = $1, modified_at = $2 WHERE chat_id = $3 AND status = $4;`
+
+		// events are recorded before update while stopped reminders can be selected by status
+		const eventsQuery = `
+			INSERT INTO reminder_events(reminder_id, type, actor, actor_id, created_at)
+			SELECT id, $1, $2, $3, $4
+			FROM reminders
+			WHERE chat_id = $5 AND status = $6;`
+
+		actor, now := domain.ActorFromContext(ctx), timeNowUTC()
+		if _, err := tx.ExecContext(ctx, eventsQuery, domain.ReminderEventChatNotFound, actor.Type, actor.ID, now, chatID, domain.ReminderStatusPending); err != nil {
+			return fmt.Errorf("failed to record stopped reminders of chat %d: %w", chatID, err)
+		}
+
+		res, err := tx.ExecContext(ctx, query, domain.ReminderStatusChatNotFound, now, chatID, domain.ReminderStatusPending)
+		if err != nil {
+			return fmt.Errorf("failed to deactivate chat %d: %w", chatID, err)
+		}
+
+		if err = dropOutboxMessages(ctx, tx, chatID, reason); err != nil {
+			return err
+		}
+
+		affected, _ := res.RowsAffected()
+		logging.Printf(ctx, "[INFO] deactivated chat %d, stopped %d reminders: %s", chatID, affected, reason)
+
+		return nil
+	})
+}
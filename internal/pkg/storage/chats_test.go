@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+)
+
+func (s *storageTestSuite) Test_storage_DeactivateChat() {
+	s.Run("success: pending reminders are stopped, pending Telegram messages are dropped", func() {
+		// ARRANGE
+		const chatID int64 = -100500
+
+		newReminder := func(chatID int64, status domain.ReminderStatus) int64 {
+			id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+				ChatID:       chatID,
+				UserID:       1,
+				Text:         "Standup",
+				RemindAt:     timeNowUTC().Add(time.Hour),
+				Status:       status,
+				AttemptsLeft: 3,
+				Priority:     domain.ReminderPriorityNormal,
+			}, nil)
+			s.Require().NoError(err)
+			return id
+		}
+
+		pendingID := newReminder(chatID, domain.ReminderStatusPending)
+		doneID := newReminder(chatID, domain.ReminderStatusDone)
+		otherChatID := newReminder(1, domain.ReminderStatusPending)
+
+		s.Require().NoError(s.storage.EnqueueOutboxMessage(context.TODO(), domain.OutboxMessage{ChatID: chatID, Payload: "foo"}))
+		s.Require().NoError(s.storage.EnqueueOutboxMessage(context.TODO(), domain.OutboxMessage{ChatID: 1, Payload: "bar"}))
+		s.Require().NoError(s.storage.EnqueueOutboxMessage(context.TODO(), domain.OutboxMessage{ChatID: chatID, Channel: domain.ChannelEmail, Payload: "baz"}))
+
+		// ACT
+		s.Require().NoError(s.storage.DeactivateChat(context.TODO(), chatID, "chat is not found"))
+
+		// ASSERT
+		statuses := map[int64]domain.ReminderStatus{}
+		for _, id := range []int64{pendingID, doneID, otherChatID} {
+			var status domain.ReminderStatus
+			s.Require().NoError(s.storage.db.Get(&status, `SELECT status FROM reminders WHERE id = $1;`, id))
+			statuses[id] = status
+		}
+		s.Equal(map[int64]domain.ReminderStatus{
+			pendingID:   domain.ReminderStatusChatNotFound,
+			doneID:      domain.ReminderStatusDone,
+			otherChatID: domain.ReminderStatusPending,
+		}, statuses)
+
+		var messages []domain.OutboxMessage
+		s.Require().NoError(s.storage.db.Select(&messages, `SELECT * FROM outbox ORDER BY id;`))
+		s.Require().Len(messages, 3)
+		s.Equal(domain.OutboxStatusDead, messages[0].Status)
+		s.Equal("chat is not found", messages[0].LastError)
+		s.Equal(domain.OutboxStatusPending, messages[1].Status, "messages of other chats are kept")
+		s.Equal(domain.OutboxStatusPending, messages[2].Status, "messages of other channels are kept")
+	})
+}
@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+)
+
+// ErrChecklistItemNotFound - checklist item is not found.
+var ErrChecklistItemNotFound = errors.New("checklist item is not found")
+
+// GetChecklist - returns checklist items of reminder ordered by position.
+func (s *Storage) GetChecklist(ctx context.Context, reminderID int64) (domain.Checklist, error) {
+	return getChecklist(ctx, s.db, reminderID)
+}
+
+func getChecklist(ctx context.Context, db sqlx.QueryerContext, reminderID int64) (domain.Checklist, error) {
+	const query = `
+		SELECT
+			id
+			, reminder_id
+			, position
+			, text
+			, done
+		FROM checklist_items
+		WHERE reminder_id = $1
+		ORDER BY position;`
+
+	var checklist domain.Checklist
+	if err := sqlx.SelectContext(ctx, db, &checklist, query, reminderID); err != nil {
+		return nil, fmt.Errorf("failed to get reminder %d checklist: %w", reminderID, err)
+	}
+
+	return checklist, nil
+}
+
+// AddChecklistItems - appends items to the end of pending reminder's checklist, msgs are enqueued to outbox
+// in the same transaction.
+func (s *Storage) AddChecklistItems(ctx context.Context, reminderID int64, items []string, msgs ...domain.OutboxMessage) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint:errcheck // rollback after commit is no-op
+
+	const query = `
+		SELECT COALESCE(MAX(c.position) + 1, 0)
+		FROM reminders r
+		LEFT JOIN checklist_items c ON c.reminder_id = r.id
+		WHERE r.id = $1 AND r.status = 'pending'
+		GROUP BY r.id;`
+
+	var position int64
+	if err = tx.GetContext(ctx, &position, query, reminderID); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("failed to add checklist items to reminder %d: %w", reminderID, ErrReminderNotFound)
+		default:
+			return fmt.Errorf("failed to add checklist items to reminder %d: %w", reminderID, err)
+		}
+	}
+
+	if err = insertChecklistItems(ctx, tx, reminderID, position, items); err != nil {
+		return fmt.Errorf("failed to add checklist items to reminder %d: %w", reminderID, err)
+	}
+
+	if err = insertOutboxMessages(ctx, tx, msgs); err != nil {
+		return fmt.Errorf("failed to add checklist items to reminder %d: %w", reminderID, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	logging.Printf(ctx, "[INFO] added %d checklist items to reminder %d", len(items), reminderID)
+
+	return nil
+}
+
+// GetChecklistItem - returns checklist item by id.
+func (s *Storage) GetChecklistItem(ctx context.Context, id int64) (domain.ChecklistItem, error) {
+	const query = `SELECT id, reminder_id, position, text, done FROM checklist_items WHERE id = $1;`
+
+	var item domain.ChecklistItem
+	if err := s.db.GetContext(ctx, &item, query, id); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return domain.ChecklistItem{}, fmt.Errorf("failed to get checklist item %d: %w", id, ErrChecklistItemNotFound)
+		default:
+			return domain.ChecklistItem{}, fmt.Errorf("failed to get checklist item %d: %w", id, err)
+		}
+	}
+
+	return item, nil
+}
+
+// ToggleChecklistItem - checks unchecked item and unchecks checked one, only items of pending reminders are toggled.
+// Reminder is done in the same transaction when all items of its checklist are checked.
+// Returns reminder of the item with updated checklist and status. Messages built by newMsgs from the reminder
+// are enqueued to outbox in the same transaction, newMsgs can be nil.
+func (s *Storage) ToggleChecklistItem(ctx context.Context, id int64, newMsgs func(reminder domain.Reminder) ([]domain.OutboxMessage, error)) (domain.Reminder, error) {
+	const query = `
+		UPDATE checklist_items
+		SET done = NOT done, modified_at = $1
+		WHERE id = $2 AND reminder_id IN (SELECT id FROM reminders WHERE status = 'pending')
+		RETURNING reminder_id;`
+
+	var reminder domain.Reminder
+	err := s.inTx(ctx, func(tx *sqlx.Tx) error {
+		var reminderID int64
+		if err := tx.GetContext(ctx, &reminderID, query, timeNowUTC(), id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrChecklistItemNotFound
+			}
+			return err
+		}
+
+		var err error
+		if reminder, err = getReminder(ctx, tx, reminderID); err != nil {
+			return err
+		}
+
+		if reminder.Checklist.IsDone() {
+			reminder.Status = domain.ReminderStatusDone
+			if err = setReminderStatus(ctx, tx, reminderID, reminder.Status); err != nil {
+				return err
+			}
+		}
+
+		return insertNewOutboxMessages(ctx, tx, reminder, newMsgs)
+	})
+	if err != nil {
+		return domain.Reminder{}, fmt.Errorf("failed to toggle checklist item %d: %w", id, err)
+	}
+
+	logging.Printf(ctx, "[INFO] toggled checklist item %d of reminder %d, reminder status is %s", id, reminder.ID, reminder.Status)
+
+	return reminder, nil
+}
+
+func insertChecklistItems(ctx context.Context, tx *sqlx.Tx, reminderID, position int64, items []string) error {
+	const query = `INSERT INTO checklist_items(reminder_id, position, text, done) VALUES ($1, $2, $3, FALSE);`
+
+	for i, item := range items {
+		if _, err := tx.ExecContext(ctx, query, reminderID, position+int64(i), item); err != nil {
+			return fmt.Errorf("failed to insert checklist item %q: %w", item, err)
+		}
+	}
+
+	return nil
+}
+
+// loadChecklists loads checklists of reminders with one query.
+func (s *Storage) loadChecklists(ctx context.Context, reminders []domain.Reminder) error {
+	if len(reminders) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(reminders))
+	for _, r := range reminders {
+		ids = append(ids, r.ID)
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT
+			id
+			, reminder_id
+			, position
+			, text
+			, done
+		FROM checklist_items
+		WHERE reminder_id IN (?)
+		ORDER BY reminder_id, position;`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to build checklists query: %w", err)
+	}
+
+	var items []domain.ChecklistItem
+	if err = s.db.SelectContext(ctx, &items, s.db.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to get checklists: %w", err)
+	}
+
+	checklists := make(map[int64]domain.Checklist, len(reminders))
+	for _, item := range items {
+		checklists[item.ReminderID] = append(checklists[item.ReminderID], item)
+	}
+
+	for i := range reminders {
+		reminders[i].Checklist = checklists[reminders[i].ID]
+	}
+
+	return nil
+}
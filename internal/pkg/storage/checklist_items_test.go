@@ -0,0 +1,298 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+)
+
+func (s *storageTestSuite) Test_storage_SaveReminder_Checklist() {
+	s.Run("success: reminder is saved with checklist", func() {
+		// ARRANGE
+		reminder := domain.Reminder{
+			ChatID:   1,
+			UserID:   2,
+			Text:     "Groceries",
+			RemindAt: timeNowUTC().Add(1 * time.Hour),
+			Status:   domain.ReminderStatusPending,
+			Checklist: domain.Checklist{
+				{Text: "milk"},
+				{Text: "bread"},
+			},
+		}
+
+		// ACT
+		id, err := s.storage.SaveReminder(context.TODO(), reminder, nil)
+		s.Require().NoError(err)
+
+		// ASSERT
+		actReminder, err := s.storage.GetReminder(context.TODO(), id)
+		s.Require().NoError(err)
+		s.Require().Len(actReminder.Checklist, 2)
+		s.Equal("milk", actReminder.Checklist[0].Text)
+		s.EqualValues(0, actReminder.Checklist[0].Position)
+		s.Equal("bread", actReminder.Checklist[1].Text)
+		s.EqualValues(1, actReminder.Checklist[1].Position)
+		s.Equal(id, actReminder.Checklist[1].ReminderID)
+		s.False(actReminder.Checklist[1].Done)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_AddChecklistItems() {
+	s.Run("success: items are appended", func() {
+		// ARRANGE
+		id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:    1,
+			UserID:    2,
+			Text:      "Groceries",
+			RemindAt:  timeNowUTC().Add(1 * time.Hour),
+			Status:    domain.ReminderStatusPending,
+			Checklist: domain.Checklist{{Text: "milk"}},
+		}, nil)
+		s.Require().NoError(err)
+
+		// ACT
+		s.Require().NoError(s.storage.AddChecklistItems(context.TODO(), id, []string{"bread", "eggs"}))
+
+		// ASSERT
+		checklist, err := s.storage.GetChecklist(context.TODO(), id)
+		s.Require().NoError(err)
+		s.Equal([]string{"milk", "bread", "eggs"}, checklist.Texts())
+		s.EqualValues(2, checklist[2].Position)
+	})
+
+	s.Run("success: reminder without checklist", func() {
+		// ARRANGE
+		id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:   1,
+			UserID:   2,
+			Text:     "Groceries",
+			RemindAt: timeNowUTC().Add(1 * time.Hour),
+			Status:   domain.ReminderStatusPending,
+		}, nil)
+		s.Require().NoError(err)
+
+		// ACT
+		s.Require().NoError(s.storage.AddChecklistItems(context.TODO(), id, []string{"bread"}))
+
+		// ASSERT
+		checklist, err := s.storage.GetChecklist(context.TODO(), id)
+		s.Require().NoError(err)
+		s.Equal([]string{"bread"}, checklist.Texts())
+		s.EqualValues(0, checklist[0].Position)
+	})
+
+	s.Run("error: reminder is done", func() {
+		// ARRANGE
+		id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:   1,
+			UserID:   2,
+			Text:     "Groceries",
+			RemindAt: timeNowUTC(),
+			Status:   domain.ReminderStatusDone,
+		}, nil)
+		s.Require().NoError(err)
+
+		// ACT & ASSERT
+		s.Require().ErrorIs(s.storage.AddChecklistItems(context.TODO(), id, []string{"bread"}), ErrReminderNotFound)
+	})
+
+	s.Run("error: reminder not found", func() {
+		s.Require().ErrorIs(s.storage.AddChecklistItems(context.TODO(), 5463, []string{"bread"}), ErrReminderNotFound)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_GetChecklistItem() {
+	s.Run("success", func() {
+		// ARRANGE
+		id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:    1,
+			UserID:    2,
+			Text:      "Groceries",
+			RemindAt:  timeNowUTC().Add(1 * time.Hour),
+			Status:    domain.ReminderStatusPending,
+			Checklist: domain.Checklist{{Text: "milk"}, {Text: "bread"}},
+		}, nil)
+		s.Require().NoError(err)
+
+		checklist, err := s.storage.GetChecklist(context.TODO(), id)
+		s.Require().NoError(err)
+
+		// ACT
+		item, err := s.storage.GetChecklistItem(context.TODO(), checklist[1].ID)
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.Equal(checklist[1], item)
+	})
+
+	s.Run("error: not found", func() {
+		_, err := s.storage.GetChecklistItem(context.TODO(), 4356)
+		s.Require().ErrorIs(err, ErrChecklistItemNotFound)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_ToggleChecklistItem() {
+	s.Run("success", func() {
+		// ARRANGE
+		id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:    1,
+			UserID:    2,
+			Text:      "Groceries",
+			RemindAt:  timeNowUTC().Add(1 * time.Hour),
+			Status:    domain.ReminderStatusPending,
+			Checklist: domain.Checklist{{Text: "milk"}, {Text: "bread"}},
+		}, nil)
+		s.Require().NoError(err)
+
+		checklist, err := s.storage.GetChecklist(context.TODO(), id)
+		s.Require().NoError(err)
+
+		// ACT
+		reminder, err := s.storage.ToggleChecklistItem(context.TODO(), checklist[0].ID, nil)
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.Equal(id, reminder.ID)
+		s.Equal(domain.ReminderStatusPending, reminder.Status)
+		s.Require().Len(reminder.Checklist, 2)
+		s.True(reminder.Checklist[0].Done)
+		s.False(reminder.Checklist[1].Done)
+
+		// ACT
+		reminder, err = s.storage.ToggleChecklistItem(context.TODO(), checklist[0].ID, nil)
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.False(reminder.Checklist[0].Done)
+	})
+
+	s.Run("success: reminder is done when all items are checked", func() {
+		// ARRANGE
+		id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:    1,
+			UserID:    2,
+			Text:      "Groceries",
+			RemindAt:  timeNowUTC().Add(1 * time.Hour),
+			Status:    domain.ReminderStatusPending,
+			Checklist: domain.Checklist{{Text: "milk"}},
+		}, nil)
+		s.Require().NoError(err)
+
+		checklist, err := s.storage.GetChecklist(context.TODO(), id)
+		s.Require().NoError(err)
+
+		// ACT
+		reminder, err := s.storage.ToggleChecklistItem(context.TODO(), checklist[0].ID, nil)
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.Equal(domain.ReminderStatusDone, reminder.Status)
+
+		stored, err := s.storage.GetReminder(context.TODO(), id)
+		s.Require().NoError(err)
+		s.Equal(domain.ReminderStatusDone, stored.Status)
+
+		events, err := s.storage.GetReminderEvents(context.TODO(), id)
+		s.Require().NoError(err)
+		s.Require().Len(events, 2)
+		s.Equal(domain.ReminderEventDone, events[1].Type)
+	})
+
+	s.Run("error: reminder isn't pending", func() {
+		// ARRANGE
+		id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:    1,
+			UserID:    2,
+			Text:      "Groceries",
+			RemindAt:  timeNowUTC().Add(1 * time.Hour),
+			Status:    domain.ReminderStatusPending,
+			Checklist: domain.Checklist{{Text: "milk"}, {Text: "bread"}},
+		}, nil)
+		s.Require().NoError(err)
+
+		checklist, err := s.storage.GetChecklist(context.TODO(), id)
+		s.Require().NoError(err)
+
+		_, err = s.storage.ToggleChecklistItem(context.TODO(), checklist[0].ID, nil)
+		s.Require().NoError(err)
+		_, err = s.storage.ToggleChecklistItem(context.TODO(), checklist[1].ID, nil)
+		s.Require().NoError(err)
+
+		// ACT
+		_, err = s.storage.ToggleChecklistItem(context.TODO(), checklist[1].ID, nil)
+
+		// ASSERT
+		s.Require().ErrorIs(err, ErrChecklistItemNotFound)
+
+		checklist, err = s.storage.GetChecklist(context.TODO(), id)
+		s.Require().NoError(err)
+		s.True(checklist.IsDone())
+
+		events, err := s.storage.GetReminderEvents(context.TODO(), id)
+		s.Require().NoError(err)
+		s.Len(events, 2, "done event is recorded once")
+	})
+
+	s.Run("error: not found", func() {
+		_, err := s.storage.ToggleChecklistItem(context.TODO(), 4356, nil)
+		s.Require().ErrorIs(err, ErrChecklistItemNotFound)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_RemoveReminder_Checklist() {
+	s.Run("success: checklist is removed with reminder", func() {
+		// ARRANGE
+		id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:    1,
+			UserID:    2,
+			Text:      "Groceries",
+			RemindAt:  timeNowUTC().Add(1 * time.Hour),
+			Status:    domain.ReminderStatusPending,
+			Checklist: domain.Checklist{{Text: "milk"}},
+		}, nil)
+		s.Require().NoError(err)
+
+		// ACT
+		s.Require().NoError(s.storage.RemoveReminder(context.TODO(), id))
+
+		// ASSERT
+		checklist, err := s.storage.GetChecklist(context.TODO(), id)
+		s.Require().NoError(err)
+		s.Empty(checklist)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_GetMyReminders_Checklist() {
+	s.Run("success: checklists are loaded", func() {
+		// ARRANGE
+		_, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:    1,
+			UserID:    2,
+			Text:      "Groceries",
+			RemindAt:  timeNowUTC().Add(1 * time.Hour),
+			Status:    domain.ReminderStatusPending,
+			Checklist: domain.Checklist{{Text: "milk"}, {Text: "bread"}},
+		}, nil)
+		s.Require().NoError(err)
+
+		_, err = s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:   1,
+			UserID:   2,
+			Text:     "Call mom",
+			RemindAt: timeNowUTC().Add(2 * time.Hour),
+			Status:   domain.ReminderStatusPending,
+		}, nil)
+		s.Require().NoError(err)
+
+		// ACT
+		reminders, err := s.storage.GetMyReminders(context.TODO(), 2, 1)
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.Require().Len(reminders, 2)
+		s.Equal([]string{"milk", "bread"}, reminders[0].Checklist.Texts())
+		s.Empty(reminders[1].Checklist)
+	})
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+)
+
+// ErrInviteCodeNotFound - invite code is not found or already used.
+var ErrInviteCodeNotFound = errors.New("invite code is not found")
+
+// SaveInviteCode - saves invite code.
+func (s *Storage) SaveInviteCode(ctx context.Context, code domain.InviteCode) error {
+	if code.CreatedAt.IsZero() {
+		code.CreatedAt = timeNowUTC()
+	}
+
+	const query = `INSERT INTO invite_codes(
+            code
+            , created_by
+            , created_at
+	) VALUES ($1, $2, $3)`
+
+	if _, err := s.db.ExecContext(ctx, query, code.Code, code.CreatedBy, code.CreatedAt); err != nil {
+		return fmt.Errorf("failed to save invite code %s: %w", code, err)
+	}
+
+	logging.Printf(ctx, "[INFO] saved invite code %s", code)
+
+	return nil
+}
+
+// GetInviteCode - returns invite code which is not used yet.
+func (s *Storage) GetInviteCode(ctx context.Context, code string) (domain.InviteCode, error) {
+	const query = `SELECT code, created_by, created_at FROM invite_codes WHERE code = $1 AND used_by IS NULL;`
+
+	var inviteCode domain.InviteCode
+	if err := s.db.GetContext(ctx, &inviteCode, query, code); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.InviteCode{}, fmt.Errorf("failed to get invite code %s: %w", domain.MaskInviteCode(code), ErrInviteCodeNotFound)
+		}
+		return domain.InviteCode{}, fmt.Errorf("failed to get invite code %s: %w", domain.MaskInviteCode(code), err)
+	}
+
+	return inviteCode, nil
+}
+
+// useInviteCode - marks invite code as used by user. Invite code can be used only once.
+func useInviteCode(ctx context.Context, db sqlx.ExtContext, code string, userID int64) error {
+	const query = `UPDATE invite_codes SET used_by = $1, used_at = $2 WHERE code = $3 AND used_by IS NULL;`
+
+	res, err := db.ExecContext(ctx, query, userID, timeNowUTC(), code)
+	if err != nil {
+		return fmt.Errorf("failed to use invite code %s: %w", domain.MaskInviteCode(code), err)
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("failed to use invite code %s: %w", domain.MaskInviteCode(code), ErrInviteCodeNotFound)
+	}
+
+	logging.Printf(ctx, "[INFO] invite code %s used by user %d", domain.MaskInviteCode(code), userID)
+
+	return nil
+}
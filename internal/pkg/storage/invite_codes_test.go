@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+)
+
+func (s *storageTestSuite) Test_storage_GetInviteCode() {
+	s.Run("success: unused invite code", func() {
+		// ARRANGE
+		code := domain.InviteCode{
+			Code:      "0123456789abcdef",
+			CreatedBy: 1,
+			CreatedAt: timeNowUTC().Truncate(1 * time.Minute),
+		}
+		s.Require().NoError(s.storage.SaveInviteCode(context.TODO(), code))
+
+		// ACT
+		actCode, err := s.storage.GetInviteCode(context.TODO(), code.Code)
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.Equal(code.Code, actCode.Code)
+		s.Equal(code.CreatedBy, actCode.CreatedBy)
+		s.Equal(code.CreatedAt, actCode.CreatedAt.UTC())
+	})
+
+	s.Run("error: invite code does not exist", func() {
+		_, err := s.storage.GetInviteCode(context.TODO(), "unknown")
+		s.Require().ErrorIs(err, ErrInviteCodeNotFound)
+	})
+
+	s.Run("error: invite code already exists", func() {
+		code := domain.InviteCode{Code: "0123456789abcdef", CreatedBy: 1}
+		s.Require().NoError(s.storage.SaveInviteCode(context.TODO(), code))
+		s.Require().Error(s.storage.SaveInviteCode(context.TODO(), code))
+	})
+}
+
+func (s *storageTestSuite) Test_storage_SaveUser_InviteCode() {
+	s.Run("success: invite code is used once by registered user", func() {
+		// ARRANGE
+		code := domain.InviteCode{Code: "0123456789abcdef", CreatedBy: 1}
+		s.Require().NoError(s.storage.SaveInviteCode(context.TODO(), code))
+		ctx := domain.ContextWithInviteCode(context.TODO(), code.Code)
+
+		// ACT
+		s.Require().NoError(s.storage.SaveUser(ctx, domain.User{ID: 2, Name: "user2", Status: domain.UserStatusActive}))
+
+		// ASSERT
+		var usedBy int64
+		s.Require().NoError(s.storage.db.Get(&usedBy, `SELECT used_by FROM invite_codes WHERE code = $1;`, code.Code))
+		s.EqualValues(2, usedBy)
+		_, err := s.storage.GetInviteCode(context.TODO(), code.Code)
+		s.Require().ErrorIs(err, ErrInviteCodeNotFound)
+		s.Require().ErrorIs(s.storage.SaveUser(ctx, domain.User{ID: 3, Name: "user3", Status: domain.UserStatusActive}), ErrInviteCodeNotFound)
+	})
+
+	s.Run("success: invite code isn't used if user isn't saved", func() {
+		// ARRANGE
+		code := domain.InviteCode{Code: "0123456789abcdef", CreatedBy: 1}
+		s.Require().NoError(s.storage.SaveInviteCode(context.TODO(), code))
+		s.Require().NoError(s.storage.SaveUser(context.TODO(), domain.User{ID: 2, Name: "user2", Status: domain.UserStatusActive}))
+
+		// ACT
+		err := s.storage.SaveUser(domain.ContextWithInviteCode(context.TODO(), code.Code), domain.User{ID: 2, Name: "user2", Status: domain.UserStatusActive})
+
+		// ASSERT
+		s.Require().ErrorIs(err, ErrUserAlreadyExists)
+		_, err = s.storage.GetInviteCode(context.TODO(), code.Code)
+		s.Require().NoError(err)
+	})
+
+	s.Run("error: invite code does not exist", func() {
+		err := s.storage.SaveUser(domain.ContextWithInviteCode(context.TODO(), "unknown"), domain.User{ID: 2, Name: "user2", Status: domain.UserStatusActive})
+		s.Require().ErrorIs(err, ErrInviteCodeNotFound)
+		_, err = s.storage.GetUser(context.TODO(), 2)
+		s.Require().ErrorIs(err, ErrUserNotFound)
+	})
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+)
+
+// GetMaintenance - returns true if maintenance mode is enabled. Maintenance mode is disabled if it's never been set.
+func (s *Storage) GetMaintenance(ctx context.Context) (bool, error) {
+	const query = `SELECT enabled FROM maintenance WHERE id = 1;`
+
+	var enabled bool
+	if err := s.db.GetContext(ctx, &enabled, query); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return false, nil
+		default:
+			return false, fmt.Errorf("failed to get maintenance mode: %w", err)
+		}
+	}
+
+	return enabled, nil
+}
+
+// SetMaintenance - enables or disables maintenance mode, it's kept after restart.
+func (s *Storage) SetMaintenance(ctx context.Context, enabled bool) error {
+	const query = `INSERT INTO maintenance(
+            id
+            , enabled
+            , modified_at
+	) VALUES (1, $1, $2)
+	ON CONFLICT DO UPDATE SET
+		enabled = $1
+		, modified_at = $2;`
+
+	if _, err := s.db.ExecContext(ctx, query, enabled, timeNowUTC()); err != nil {
+		return fmt.Errorf("failed to set maintenance mode to %t: %w", enabled, err)
+	}
+
+	logging.Printf(ctx, "[INFO] set maintenance mode to %t", enabled)
+
+	return nil
+}
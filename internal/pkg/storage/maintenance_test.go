@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+)
+
+func (s *storageTestSuite) Test_storage_Maintenance() {
+	s.Run("success: maintenance mode is not set", func() {
+		// ACT
+		enabled, err := s.storage.GetMaintenance(context.TODO())
+
+		// ASSERT
+		s.NoError(err)
+		s.False(enabled)
+	})
+
+	s.Run("success: maintenance mode is enabled and disabled", func() {
+		// ACT
+		s.NoError(s.storage.SetMaintenance(context.TODO(), true))
+		enabled, err := s.storage.GetMaintenance(context.TODO())
+
+		// ASSERT
+		s.NoError(err)
+		s.True(enabled)
+
+		// ACT
+		s.NoError(s.storage.SetMaintenance(context.TODO(), false))
+		enabled, err = s.storage.GetMaintenance(context.TODO())
+
+		// ASSERT
+		s.NoError(err)
+		s.False(enabled)
+	})
+}
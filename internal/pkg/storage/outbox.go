@@ -0,0 +1,232 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+)
+
+// ErrOutboxMessageNotFound - outbox message is not found
+var ErrOutboxMessageNotFound = errors.New("outbox message is not found")
+
+// EnqueueOutboxMessage - puts message to outbox, the message is ready to be delivered right away.
+func (s *Storage) EnqueueOutboxMessage(ctx context.Context, msg domain.OutboxMessage) error {
+	if err := insertOutboxMessage(ctx, s.db, msg); err != nil {
+		return err
+	}
+
+	logging.Printf(ctx, "[DEBUG] enqueued outbox message to chat %d", msg.ChatID)
+
+	return nil
+}
+
+// GetDueOutboxMessages - returns pending messages which are due to be delivered.
+// Messages of one chat are delivered by every channel in order they were enqueued, so only the oldest pending message
+// of every chat and channel is returned, even if it's not due yet and the next ones are.
+// Channels don't wait for each other, e.g. unavailable email server doesn't delay Telegram messages.
+func (s *Storage) GetDueOutboxMessages(ctx context.Context, limit int64) ([]domain.OutboxMessage, error) {
+	const query = `
+		SELECT
+			o.id
+			, o.chat_id
+			, o.channel
+			, o.payload
+			, o.status
+			, o.attempts
+			, o.next_attempt_at
+			, o.last_error
+			, o.correlation_id
+			, o.secret
+			, o.created_at
+			, o.modified_at
+		FROM outbox o
+		WHERE o.status = 'pending'
+			AND o.next_attempt_at <= $1
+			AND NOT EXISTS (
+				SELECT 1
+				FROM outbox p
+				WHERE p.chat_id = o.chat_id AND p.channel = o.channel AND p.status = 'pending' AND p.id < o.id
+			)
+		ORDER BY o.id
+		LIMIT $2;`
+
+	var messages []domain.OutboxMessage
+	if err := s.db.SelectContext(ctx, &messages, query, timeNowUTC(), limit); err != nil {
+		return nil, fmt.Errorf("failed to get due outbox messages: %w", err)
+	}
+
+	logging.Printf(ctx, "[DEBUG] got %d due outbox messages", len(messages))
+
+	return messages, nil
+}
+
+// UpdateOutboxMessage - saves delivery attempt of message: status, number of attempts, time of the next attempt and error.
+// Payload of secret message is erased when message is dead.
+func (s *Storage) UpdateOutboxMessage(ctx context.Context, msg domain.OutboxMessage) error {
+	const query = `
+		UPDATE outbox
+		SET status = $1
+			, attempts = $2
+			, next_attempt_at = $3
+			, last_error = $4
+			, modified_at = $5
+			, payload = CASE WHEN secret AND $1 = 'dead' THEN '' ELSE payload END
+		WHERE id = $6;`
+
+	res, err := s.db.ExecContext(ctx, query, msg.Status, msg.Attempts, msg.NextAttemptAt, msg.LastError, timeNowUTC(), msg.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update outbox message %s: %w", msg, err)
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("failed to update outbox message %s: %w", msg, ErrOutboxMessageNotFound)
+	}
+
+	return nil
+}
+
+// RemoveOutboxMessage - removes delivered message from outbox.
+func (s *Storage) RemoveOutboxMessage(ctx context.Context, id int64) error {
+	const query = `DELETE FROM outbox WHERE id = $1;`
+
+	if _, err := s.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to remove outbox message %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// NotifyReminder - updates notified reminder and enqueues its notifications, one per channel, in one transaction,
+// so reminder isn't moved forward without notifications being sent.
+// Notification is recorded as reminder event with attempt number, exhausted reminder gets one more event.
+func (s *Storage) NotifyReminder(ctx context.Context, reminder domain.Reminder, msgs []domain.OutboxMessage) error {
+	return s.inTx(ctx, func(tx *sqlx.Tx) error {
+		if err := updateReminder(ctx, tx, reminder); err != nil {
+			return err
+		}
+
+		attempt, err := nextNotifyAttempt(ctx, tx, reminder.ID)
+		if err != nil {
+			return err
+		}
+
+		if err = insertReminderEvent(ctx, tx, domain.ReminderEvent{
+			ReminderID: reminder.ID,
+			Type:       domain.ReminderEventNotified,
+			Attempt:    attempt,
+		}); err != nil {
+			return err
+		}
+
+		if reminder.Status == domain.ReminderStatusAttemptsExhausted {
+			if err = insertReminderEvent(ctx, tx, domain.ReminderEvent{ReminderID: reminder.ID, Type: domain.ReminderEventExhausted}); err != nil {
+				return err
+			}
+		}
+
+		return insertOutboxMessages(ctx, tx, msgs)
+	})
+}
+
+// NotifyPreNotice - sets time of the next advance notice of reminder and enqueues the current one, one message per channel,
+// in one transaction.
+func (s *Storage) NotifyPreNotice(ctx context.Context, id int64, nextPreNoticeAt *time.Time, msgs []domain.OutboxMessage) error {
+	return s.inTx(ctx, func(tx *sqlx.Tx) error {
+		if err := setReminderNextPreNotice(ctx, tx, id, nextPreNoticeAt); err != nil {
+			return err
+		}
+
+		return insertOutboxMessages(ctx, tx, msgs)
+	})
+}
+
+// dropOutboxMessages marks pending Telegram messages of chat as dead, they can't be delivered anymore.
+// Messages of other channels, e.g. email and webhook, don't depend on the chat and are delivered.
+func dropOutboxMessages(ctx context.Context, db sqlx.ExecerContext, chatID int64, reason string) error {
+	const query = `
+		UPDATE outbox
+		SET status = 'dead'
+			, last_error = $1
+			, modified_at = $2
+			, payload = CASE WHEN secret THEN '' ELSE payload END
+		WHERE chat_id = $3
+			AND channel = 'telegram'
+			AND status = 'pending';`
+
+	res, err := db.ExecContext(ctx, query, reason, timeNowUTC(), chatID)
+	if err != nil {
+		return fmt.Errorf("failed to drop outbox messages of chat %d: %w", chatID, err)
+	}
+
+	if affected, _ := res.RowsAffected(); affected > 0 {
+		logging.Printf(ctx, "[INFO] dropped %d Telegram outbox messages of chat %d", affected, chatID)
+	}
+
+	return nil
+}
+
+// insertNewOutboxMessages enqueues messages built by newMsgs from result of the change made in the same transaction,
+// e.g. response with id of created reminder. Nothing is enqueued if newMsgs is nil.
+func insertNewOutboxMessages[T any](ctx context.Context, db sqlx.ExecerContext, result T, newMsgs func(T) ([]domain.OutboxMessage, error)) error {
+	if newMsgs == nil {
+		return nil
+	}
+
+	msgs, err := newMsgs(result)
+	if err != nil {
+		return err
+	}
+
+	return insertOutboxMessages(ctx, db, msgs)
+}
+
+func insertOutboxMessages(ctx context.Context, db sqlx.ExecerContext, msgs []domain.OutboxMessage) error {
+	for _, msg := range msgs {
+		if err := insertOutboxMessage(ctx, db, msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func insertOutboxMessage(ctx context.Context, db sqlx.ExecerContext, msg domain.OutboxMessage) error {
+	now := timeNowUTC()
+	if msg.Status == "" {
+		msg.Status = domain.OutboxStatusPending
+	}
+	if msg.NextAttemptAt.IsZero() {
+		msg.NextAttemptAt = now
+	}
+	if msg.CorrelationID == "" {
+		msg.CorrelationID = logging.CorrelationID(ctx)
+	}
+	if msg.Channel == "" {
+		msg.Channel = domain.ChannelTelegram
+	}
+
+	const query = `INSERT INTO outbox(
+            chat_id
+            , channel
+            , payload
+            , status
+            , attempts
+            , next_attempt_at
+            , last_error
+            , correlation_id
+            , secret
+            , created_at
+            , modified_at
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);`
+
+	if _, err := db.ExecContext(ctx, query, msg.ChatID, msg.Channel, msg.Payload, msg.Status, msg.Attempts, msg.NextAttemptAt, msg.LastError, msg.CorrelationID, msg.Secret, now, now); err != nil {
+		return fmt.Errorf("failed to enqueue %s outbox message to chat %d: %w", msg.Channel, msg.ChatID, err)
+	}
+
+	return nil
+}
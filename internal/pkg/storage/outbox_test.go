@@ -0,0 +1,348 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+)
+
+func (s *storageTestSuite) Test_storage_GetDueOutboxMessages() {
+	s.Run("success: the oldest pending message of every chat is returned", func() {
+		// ARRANGE
+		now := timeNowUTC()
+		for _, msg := range []domain.OutboxMessage{
+			{ChatID: 1, Payload: "first"},
+			{ChatID: 1, Payload: "second"},
+			{ChatID: 2, Payload: "retry later", NextAttemptAt: now.Add(time.Minute)},
+			{ChatID: 2, Payload: "waits for previous one"},
+			{ChatID: 3, Payload: "dead", Status: domain.OutboxStatusDead},
+			{ChatID: 3, Payload: "after dead"},
+		} {
+			s.Require().NoError(s.storage.EnqueueOutboxMessage(context.TODO(), msg))
+		}
+
+		// ACT
+		messages, err := s.storage.GetDueOutboxMessages(context.TODO(), 10)
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.Require().Len(messages, 2)
+		s.Equal("first", messages[0].Payload)
+		s.Equal(domain.OutboxStatusPending, messages[0].Status)
+		s.Zero(messages[0].Attempts)
+		s.NotZero(messages[0].CreatedAt)
+		s.Equal("after dead", messages[1].Payload)
+	})
+
+	s.Run("success: channels of one chat don't wait for each other", func() {
+		// ARRANGE
+		now := timeNowUTC()
+		for _, msg := range []domain.OutboxMessage{
+			{ChatID: 1, Channel: domain.ChannelEmail, Payload: "email retry later", NextAttemptAt: now.Add(time.Minute)},
+			{ChatID: 1, Channel: domain.ChannelEmail, Payload: "email waits for previous one"},
+			{ChatID: 1, Payload: "telegram"},
+			{ChatID: 1, Channel: domain.ChannelPush, Payload: "push"},
+		} {
+			s.Require().NoError(s.storage.EnqueueOutboxMessage(context.TODO(), msg))
+		}
+
+		// ACT
+		messages, err := s.storage.GetDueOutboxMessages(context.TODO(), 10)
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.Require().Len(messages, 2)
+		s.Equal("telegram", messages[0].Payload)
+		s.Equal(domain.ChannelTelegram, messages[0].Channel)
+		s.Equal("push", messages[1].Payload)
+		s.Equal(domain.ChannelPush, messages[1].Channel)
+	})
+
+	s.Run("success: limit", func() {
+		// ARRANGE
+		for chatID := int64(1); chatID <= 3; chatID++ {
+			s.Require().NoError(s.storage.EnqueueOutboxMessage(context.TODO(), domain.OutboxMessage{ChatID: chatID, Payload: "foo"}))
+		}
+
+		// ACT
+		messages, err := s.storage.GetDueOutboxMessages(context.TODO(), 2)
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.Len(messages, 2)
+	})
+
+	s.Run("success: correlation id is taken from context", func() {
+		// ARRANGE
+		ctx := logging.ContextWithCorrelationID(context.TODO(), "0123456789abcdef")
+		s.Require().NoError(s.storage.EnqueueOutboxMessage(ctx, domain.OutboxMessage{ChatID: 1, Payload: "foo"}))
+
+		// ACT
+		messages, err := s.storage.GetDueOutboxMessages(context.TODO(), 10)
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.Require().Len(messages, 1)
+		s.Equal("0123456789abcdef", messages[0].CorrelationID)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_UpdateOutboxMessage() {
+	s.Run("success: update and remove", func() {
+		// ARRANGE
+		s.Require().NoError(s.storage.EnqueueOutboxMessage(context.TODO(), domain.OutboxMessage{ChatID: 1, Payload: "foo"}))
+		messages, err := s.storage.GetDueOutboxMessages(context.TODO(), 10)
+		s.Require().NoError(err)
+		s.Require().Len(messages, 1)
+
+		msg := messages[0]
+		msg.Attempts = 1
+		msg.NextAttemptAt = timeNowUTC().Add(time.Minute)
+		msg.LastError = "Too Many Requests: retry after 60"
+
+		// ACT
+		s.Require().NoError(s.storage.UpdateOutboxMessage(context.TODO(), msg))
+
+		// ASSERT
+		messages, err = s.storage.GetDueOutboxMessages(context.TODO(), 10)
+		s.Require().NoError(err)
+		s.Empty(messages, "message isn't due yet")
+
+		var act domain.OutboxMessage
+		s.Require().NoError(s.storage.db.Get(&act, `SELECT * FROM outbox WHERE id = $1;`, msg.ID))
+		s.Equal(1, act.Attempts)
+		s.Equal("Too Many Requests: retry after 60", act.LastError)
+		s.WithinDuration(msg.NextAttemptAt, act.NextAttemptAt, time.Millisecond)
+
+		// ACT
+		s.Require().NoError(s.storage.RemoveOutboxMessage(context.TODO(), msg.ID))
+
+		// ASSERT
+		var count int
+		s.Require().NoError(s.storage.db.Get(&count, `SELECT COUNT(*) FROM outbox;`))
+		s.Zero(count)
+	})
+
+	s.Run("success: payload of dead secret message is erased", func() {
+		// ARRANGE
+		s.Require().NoError(s.storage.EnqueueOutboxMessage(context.TODO(), domain.OutboxMessage{ChatID: 1, Payload: "token"}))
+		s.Require().NoError(s.storage.EnqueueOutboxMessage(context.TODO(), domain.OutboxMessage{ChatID: 2, Payload: "secret", Secret: true}))
+		messages, err := s.storage.GetDueOutboxMessages(context.TODO(), 10)
+		s.Require().NoError(err)
+		s.Require().Len(messages, 2)
+		s.Require().True(messages[1].Secret)
+
+		// ACT
+		for _, msg := range messages {
+			msg.Attempts = 1
+			msg.Status = domain.OutboxStatusDead
+			s.Require().NoError(s.storage.UpdateOutboxMessage(context.TODO(), msg))
+		}
+
+		// ASSERT
+		var payloads []string
+		s.Require().NoError(s.storage.db.Select(&payloads, `SELECT payload FROM outbox ORDER BY id;`))
+		s.Equal([]string{"token", ""}, payloads)
+	})
+
+	s.Run("error: not found", func() {
+		s.Require().ErrorIs(s.storage.UpdateOutboxMessage(context.TODO(), domain.OutboxMessage{ID: 35689}), ErrOutboxMessageNotFound)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_NotifyReminder() {
+	s.Run("success: reminder is updated and notification is enqueued", func() {
+		// ARRANGE
+		reminder := domain.Reminder{
+			ChatID:       1,
+			UserID:       2,
+			Text:         "Meeting",
+			RemindAt:     timeNowUTC().Truncate(time.Minute),
+			Status:       domain.ReminderStatusPending,
+			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
+		}
+		id, err := s.storage.SaveReminder(context.TODO(), reminder, nil)
+		s.Require().NoError(err)
+
+		reminder.ID = id
+		reminder.AttemptsLeft = 2
+
+		// ACT
+		err = s.storage.NotifyReminder(context.TODO(), reminder, []domain.OutboxMessage{
+			{ChatID: 1, Payload: "notification"},
+			{ChatID: 1, Channel: domain.ChannelEmail, Payload: "email"},
+		})
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.EqualValues(2, s.mustGetReminder(id).AttemptsLeft)
+
+		messages, err := s.storage.GetDueOutboxMessages(context.TODO(), 10)
+		s.Require().NoError(err)
+		s.Require().Len(messages, 2)
+		s.Equal("notification", messages[0].Payload)
+		s.Equal(domain.ChannelTelegram, messages[0].Channel)
+		s.Equal("email", messages[1].Payload)
+		s.Equal(domain.ChannelEmail, messages[1].Channel)
+	})
+
+	s.Run("error: reminder is not found, notification isn't enqueued", func() {
+		// ACT
+		err := s.storage.NotifyReminder(context.TODO(), domain.Reminder{ID: 35689}, []domain.OutboxMessage{{ChatID: 1, Payload: "notification"}})
+
+		// ASSERT
+		s.Require().ErrorIs(err, ErrReminderNotFound)
+
+		messages, err := s.storage.GetDueOutboxMessages(context.TODO(), 10)
+		s.Require().NoError(err)
+		s.Empty(messages)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_NotifyPreNotice() {
+	s.Run("success: next advance notice is set and notice is enqueued", func() {
+		// ARRANGE
+		id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:       1,
+			UserID:       2,
+			Text:         "Meeting",
+			RemindAt:     timeNowUTC().Truncate(time.Minute),
+			Status:       domain.ReminderStatusPending,
+			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
+		}, nil)
+		s.Require().NoError(err)
+
+		// ACT
+		err = s.storage.NotifyPreNotice(context.TODO(), id, nil, []domain.OutboxMessage{{ChatID: 1, Payload: "notice"}})
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.Nil(s.mustGetReminder(id).NextPreNoticeAt)
+
+		messages, err := s.storage.GetDueOutboxMessages(context.TODO(), 10)
+		s.Require().NoError(err)
+		s.Require().Len(messages, 1)
+		s.Equal("notice", messages[0].Payload)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_ResponseWithStateChange() {
+	s.Run("success: response is enqueued with state change", func() {
+		// ARRANGE
+		state := domain.BotState{UserID: 2, Name: domain.BotStateNameCreateReminder}
+
+		// ACT
+		err := s.storage.SaveBotState(context.TODO(), state, domain.OutboxMessage{ChatID: 1, Payload: "response"})
+
+		// ASSERT
+		s.Require().NoError(err)
+
+		act, err := s.storage.GetBotState(context.TODO(), 2)
+		s.Require().NoError(err)
+		s.Equal(domain.BotStateNameCreateReminder, act.Name)
+
+		messages, err := s.storage.GetDueOutboxMessages(context.TODO(), 10)
+		s.Require().NoError(err)
+		s.Require().Len(messages, 1)
+		s.Equal("response", messages[0].Payload)
+	})
+
+	s.Run("success: response is built from id of saved reminder", func() {
+		// ARRANGE
+		reminder := domain.Reminder{ChatID: 1, UserID: 2, Text: "Meeting", RemindAt: timeNowUTC().Add(time.Hour), Status: domain.ReminderStatusPending}
+
+		// ACT
+		id, err := s.storage.SaveReminder(context.TODO(), reminder, func(id int64) ([]domain.OutboxMessage, error) {
+			return []domain.OutboxMessage{{ChatID: 1, Payload: fmt.Sprintf("reminder %d", id)}}, nil
+		})
+
+		// ASSERT
+		s.Require().NoError(err)
+
+		messages, err := s.storage.GetDueOutboxMessages(context.TODO(), 10)
+		s.Require().NoError(err)
+		s.Require().Len(messages, 1)
+		s.Equal(fmt.Sprintf("reminder %d", id), messages[0].Payload)
+	})
+
+	s.Run("error: state change fails, response isn't enqueued", func() {
+		// ARRANGE
+		id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID: 1, UserID: 2, Text: "Meeting", RemindAt: timeNowUTC().Add(time.Hour), Status: domain.ReminderStatusDone,
+		}, nil)
+		s.Require().NoError(err)
+
+		// ACT
+		err = s.storage.DelayReminder(context.TODO(), id, timeNowUTC().Add(2*time.Hour), domain.OutboxMessage{ChatID: 1, Payload: "response"})
+
+		// ASSERT
+		s.Require().ErrorIs(err, ErrReminderNotFound)
+
+		messages, err := s.storage.GetDueOutboxMessages(context.TODO(), 10)
+		s.Require().NoError(err)
+		s.Empty(messages)
+	})
+
+	s.Run("error: response isn't built, reminder isn't saved", func() {
+		// ACT
+		_, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID: 1, UserID: 2, Text: "Meeting", RemindAt: timeNowUTC().Add(time.Hour), Status: domain.ReminderStatusPending,
+		}, func(int64) ([]domain.OutboxMessage, error) {
+			return nil, errors.New("render error")
+		})
+
+		// ASSERT
+		s.Require().ErrorContains(err, "render error")
+
+		var count int
+		s.Require().NoError(s.storage.db.Get(&count, `SELECT COUNT(*) FROM reminders;`))
+		s.Zero(count)
+	})
+
+	s.Run("error: response isn't enqueued, state isn't changed", func() {
+		// ARRANGE
+		state := domain.BotState{UserID: 2, Name: domain.BotStateNameStart}
+		s.Require().NoError(s.storage.SaveBotState(context.TODO(), state))
+
+		id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID: 1, UserID: 2, Text: "Meeting", RemindAt: timeNowUTC().Add(time.Hour), Status: domain.ReminderStatusPending,
+		}, nil)
+		s.Require().NoError(err)
+
+		_, err = s.storage.db.Exec(`
+			CREATE TRIGGER outbox_is_full BEFORE INSERT ON outbox
+			BEGIN
+				SELECT RAISE(ABORT, 'outbox is full');
+			END;`)
+		s.Require().NoError(err)
+		defer func() {
+			_, err := s.storage.db.Exec(`DROP TRIGGER outbox_is_full;`)
+			s.Require().NoError(err)
+		}()
+
+		// ACT
+		stateErr := s.storage.SaveBotState(context.TODO(), domain.BotState{UserID: 2, Name: domain.BotStateNameCreateReminder},
+			domain.OutboxMessage{ChatID: 1, Payload: "response"})
+		statusErr := s.storage.SetReminderStatus(context.TODO(), id, domain.ReminderStatusDone, domain.OutboxMessage{ChatID: 1, Payload: "response"})
+
+		// ASSERT
+		s.Require().ErrorContains(stateErr, "outbox is full")
+		s.Require().ErrorContains(statusErr, "outbox is full")
+
+		act, err := s.storage.GetBotState(context.TODO(), 2)
+		s.Require().NoError(err)
+		s.Equal(domain.BotStateNameStart, act.Name)
+
+		s.Equal(domain.ReminderStatusPending, s.mustGetReminder(id).Status)
+
+		events, err := s.storage.GetReminderEvents(context.TODO(), id)
+		s.Require().NoError(err)
+		s.Len(events, 1, "only created event")
+	})
+}
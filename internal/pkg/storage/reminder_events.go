@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+)
+
+// GetReminderEvents - returns state transitions of reminder in order they happened.
+// Events are kept after reminder is removed.
+func (s *Storage) GetReminderEvents(ctx context.Context, reminderID int64) ([]domain.ReminderEvent, error) {
+	const query = `
+		SELECT
+			id
+			, reminder_id
+			, type
+			, actor
+			, actor_id
+			, attempt
+			, remind_at_from
+			, remind_at_to
+			, created_at
+		FROM reminder_events
+		WHERE reminder_id = $1
+		ORDER BY id;`
+
+	var events []domain.ReminderEvent
+	if err := s.db.SelectContext(ctx, &events, query, reminderID); err != nil {
+		return nil, fmt.Errorf("failed to get reminder %d events: %w", reminderID, err)
+	}
+
+	return events, nil
+}
+
+// insertReminderEvent records reminder state transition made by actor from ctx, see [domain.ContextWithActor].
+// Lifecycle events are enqueued to webhooks subscribed to them.
+func insertReminderEvent(ctx context.Context, db sqlx.ExtContext, event domain.ReminderEvent) error {
+	actor := domain.ActorFromContext(ctx)
+	event.ActorType, event.ActorID = actor.Type, actor.ID
+	event.CreatedAt = timeNowUTC()
+
+	const query = `
+		INSERT INTO reminder_events(
+			reminder_id
+			, type
+			, actor
+			, actor_id
+			, attempt
+			, remind_at_from
+			, remind_at_to
+			, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`
+
+	res, err := db.ExecContext(ctx, query,
+		event.ReminderID,
+		event.Type,
+		event.ActorType,
+		event.ActorID,
+		event.Attempt,
+		event.RemindAtFrom,
+		event.RemindAtTo,
+		event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record reminder %d %s event: %w", event.ReminderID, event.Type, err)
+	}
+
+	if event.ID, err = res.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to get id of reminder %d %s event: %w", event.ReminderID, event.Type, err)
+	}
+
+	logging.Printf(ctx, "[DEBUG] recorded reminder %d %s event by %s %d", event.ReminderID, event.Type, actor.Type, actor.ID)
+
+	return enqueueWebhookDeliveries(ctx, db, event)
+}
+
+// nextNotifyAttempt returns number of the next notification of reminder, attempts are counted since reminder was delayed.
+func nextNotifyAttempt(ctx context.Context, db sqlx.QueryerContext, reminderID int64) (int, error) {
+	const query = `
+		SELECT COUNT(*) + 1
+		FROM reminder_events
+		WHERE reminder_id = $1
+			AND type = 'notified'
+			AND id > COALESCE((SELECT MAX(id) FROM reminder_events WHERE reminder_id = $1 AND type = 'delayed'), 0);`
+
+	var attempt int
+	if err := sqlx.GetContext(ctx, db, &attempt, query, reminderID); err != nil {
+		return 0, fmt.Errorf("failed to count reminder %d notifications: %w", reminderID, err)
+	}
+
+	return attempt, nil
+}
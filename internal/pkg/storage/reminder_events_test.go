@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+)
+
+func (s *storageTestSuite) Test_storage_ReminderEvents() {
+	s.Run("success: transitions are recorded with actor", func() {
+		// ARRANGE
+		var (
+			userCtx     = domain.ContextWithActor(context.TODO(), domain.Actor{Type: domain.ActorUser, ID: 1})
+			notifierCtx = domain.ContextWithActor(context.TODO(), domain.Actor{Type: domain.ActorNotifier})
+			adminCtx    = domain.ContextWithActor(context.TODO(), domain.Actor{Type: domain.ActorAdmin, ID: 2})
+			remindAt    = timeNowUTC().Add(time.Hour).Truncate(time.Second)
+			delayedAt   = remindAt.Add(time.Hour)
+		)
+
+		reminder := domain.Reminder{
+			ChatID:       1,
+			UserID:       1,
+			Text:         "Standup",
+			RemindAt:     remindAt,
+			Status:       domain.ReminderStatusPending,
+			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
+		}
+
+		// ACT
+		id, err := s.storage.SaveReminder(userCtx, reminder, nil)
+		s.Require().NoError(err)
+		reminder.ID = id
+
+		for i := 0; i < 2; i++ {
+			reminder.AttemptsLeft--
+			s.Require().NoError(s.storage.NotifyReminder(notifierCtx, reminder, []domain.OutboxMessage{{ChatID: 1, Payload: "foo"}}))
+		}
+
+		s.Require().NoError(s.storage.DelayReminder(userCtx, id, delayedAt))
+
+		reminder.AttemptsLeft, reminder.Status = 0, domain.ReminderStatusAttemptsExhausted
+		s.Require().NoError(s.storage.NotifyReminder(notifierCtx, reminder, []domain.OutboxMessage{{ChatID: 1, Payload: "foo"}}))
+
+		s.Require().NoError(s.storage.SetReminderStatus(userCtx, id, domain.ReminderStatusDone))
+		s.Require().NoError(s.storage.RemoveReminder(adminCtx, id))
+
+		// ASSERT
+		events, err := s.storage.GetReminderEvents(context.TODO(), id)
+		s.Require().NoError(err)
+
+		type event struct {
+			Type      domain.ReminderEventType
+			ActorType domain.ActorType
+			ActorID   int64
+			Attempt   int
+		}
+		act := make([]event, 0, len(events))
+		for _, e := range events {
+			s.Equal(id, e.ReminderID)
+			s.False(e.CreatedAt.IsZero())
+			act = append(act, event{Type: e.Type, ActorType: e.ActorType, ActorID: e.ActorID, Attempt: e.Attempt})
+		}
+
+		s.Equal([]event{
+			{Type: domain.ReminderEventCreated, ActorType: domain.ActorUser, ActorID: 1},
+			{Type: domain.ReminderEventNotified, ActorType: domain.ActorNotifier, Attempt: 1},
+			{Type: domain.ReminderEventNotified, ActorType: domain.ActorNotifier, Attempt: 2},
+			{Type: domain.ReminderEventDelayed, ActorType: domain.ActorUser, ActorID: 1},
+			{Type: domain.ReminderEventNotified, ActorType: domain.ActorNotifier, Attempt: 1},
+			{Type: domain.ReminderEventExhausted, ActorType: domain.ActorNotifier},
+			{Type: domain.ReminderEventDone, ActorType: domain.ActorUser, ActorID: 1},
+			{Type: domain.ReminderEventRemoved, ActorType: domain.ActorAdmin, ActorID: 2},
+		}, act)
+
+		s.Require().NotNil(events[0].RemindAtTo)
+		s.True(remindAt.Equal(*events[0].RemindAtTo))
+		s.Require().NotNil(events[3].RemindAtFrom)
+		s.Require().NotNil(events[3].RemindAtTo)
+		s.True(delayedAt.Equal(*events[3].RemindAtTo))
+	})
+
+	s.Run("success: bulk transitions are recorded per reminder", func() {
+		// ARRANGE
+		newReminder := func(chatID int64) int64 {
+			id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+				ChatID:       chatID,
+				UserID:       1,
+				Text:         "Standup",
+				RemindAt:     timeNowUTC().Add(-time.Hour),
+				Status:       domain.ReminderStatusPending,
+				AttemptsLeft: 3,
+				Priority:     domain.ReminderPriorityNormal,
+			}, nil)
+			s.Require().NoError(err)
+			return id
+		}
+
+		missedID, stoppedID := newReminder(1), newReminder(2)
+
+		// ACT
+		s.Require().NoError(s.storage.MissReminders(context.TODO(), []int64{missedID}, nil))
+		_, err := s.storage.RescheduleMissedReminders(context.TODO(), 1, 1, []int64{missedID}, timeNowUTC().Add(time.Hour), nil)
+		s.Require().NoError(err)
+		s.Require().NoError(s.storage.DeactivateChat(context.TODO(), 2, "chat is not found"))
+
+		// ASSERT
+		eventTypes := func(id int64) []domain.ReminderEventType {
+			events, err := s.storage.GetReminderEvents(context.TODO(), id)
+			s.Require().NoError(err)
+
+			types := make([]domain.ReminderEventType, 0, len(events))
+			for _, e := range events {
+				s.Equal(domain.ActorSystem, e.ActorType, "actor isn't set")
+				types = append(types, e.Type)
+			}
+			return types
+		}
+
+		s.Equal([]domain.ReminderEventType{
+			domain.ReminderEventCreated,
+			domain.ReminderEventMissed,
+			domain.ReminderEventDelayed,
+		}, eventTypes(missedID))
+		s.Equal([]domain.ReminderEventType{
+			domain.ReminderEventCreated,
+			domain.ReminderEventChatNotFound,
+		}, eventTypes(stoppedID))
+	})
+}
@@ -2,12 +2,14 @@ package storage
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"time"
 
-	log "github.com/go-pkgz/lgr"
+	"github.com/jmoiron/sqlx"
 	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
 )
 
 // ErrReminderNotFound - reminder is not found
@@ -26,6 +28,10 @@ func (s *Storage) GetMyReminders(ctx context.Context, userID, chatID int64) ([]d
 			, remind_at
 			, status
 			, attempts_left
+			, priority
+			, lead_times
+			, channels
+			, next_pre_notice_at
 		FROM reminders	
 		WHERE user_id = $1
 			AND chat_id = $2
@@ -37,16 +43,113 @@ func (s *Storage) GetMyReminders(ctx context.Context, userID, chatID int64) ([]d
 		return nil, fmt.Errorf("failed to get my reminders: %w", err)
 	}
 
-	log.Printf("[DEBUG] got %d reminders for user %d", len(reminders), userID)
+	if err := s.loadChecklists(ctx, reminders); err != nil {
+		return nil, fmt.Errorf("failed to get my reminders: %w", err)
+	}
+
+	logging.Printf(ctx, "[DEBUG] got %d reminders for user %d", len(reminders), userID)
+
+	return reminders, nil
+}
+
+// GetUserReminders - returns reminders of user in chat with given status, reminders with any status are
+// returned if status is empty.
+func (s *Storage) GetUserReminders(ctx context.Context, userID, chatID int64, status domain.ReminderStatus) ([]domain.Reminder, error) {
+	const query = `
+		SELECT
+		    id
+			, chat_id
+			, user_id
+			, text
+			, created_at
+			, modified_at
+			, remind_at
+			, status
+			, attempts_left
+			, priority
+			, lead_times
+			, channels
+			, next_pre_notice_at
+		FROM reminders
+		WHERE user_id = $1
+			AND chat_id = $2
+			AND ($3 = '' OR status = $3)
+		ORDER BY remind_at, id`
+
+	var reminders []domain.Reminder
+	if err := s.db.SelectContext(ctx, &reminders, query, userID, chatID, status); err != nil {
+		return nil, fmt.Errorf("failed to get user reminders: %w", err)
+	}
+
+	if err := s.loadChecklists(ctx, reminders); err != nil {
+		return nil, fmt.Errorf("failed to get user reminders: %w", err)
+	}
+
+	logging.Printf(ctx, "[DEBUG] got %d reminders with status %q for user %d", len(reminders), status, userID)
 
 	return reminders, nil
 }
 
-// RemoveReminder - removes reminder by id.
-func (s *Storage) RemoveReminder(ctx context.Context, id int64) error {
-	const query = `DELETE FROM reminders WHERE id = $1;`
+// ReminderFilter - filter of reminders, zero fields match any reminder.
+type ReminderFilter struct {
+	UserID int64
+	ChatID int64
+	Status domain.ReminderStatus
+	Limit  int64
+}
 
-	res, err := s.db.ExecContext(ctx, query, id)
+// FindReminders - returns reminders matching filter, the most recent remind time first. Checklists aren't loaded.
+func (s *Storage) FindReminders(ctx context.Context, filter ReminderFilter) ([]domain.Reminder, error) {
+	const query = `
+		SELECT
+		    id
+			, chat_id
+			, user_id
+			, text
+			, created_at
+			, modified_at
+			, remind_at
+			, status
+			, attempts_left
+			, priority
+			, lead_times
+			, channels
+			, next_pre_notice_at
+		FROM reminders
+		WHERE ($1 = 0 OR user_id = $1)
+			AND ($2 = 0 OR chat_id = $2)
+			AND ($3 = '' OR status = $3)
+		ORDER BY remind_at DESC, id DESC
+		LIMIT $4`
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = -1 // no limit in sqlite
+	}
+
+	var reminders []domain.Reminder
+	if err := s.db.SelectContext(ctx, &reminders, query, filter.UserID, filter.ChatID, filter.Status, limit); err != nil {
+		return nil, fmt.Errorf("failed to find reminders: %w", err)
+	}
+
+	logging.Printf(ctx, "[DEBUG] found %d reminders by filter %+v", len(reminders), filter)
+
+	return reminders, nil
+}
+
+// RemoveReminder - removes reminder by id, msgs are enqueued to outbox in the same transaction.
+func (s *Storage) RemoveReminder(ctx context.Context, id int64, msgs ...domain.OutboxMessage) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint:errcheck // rollback after commit is no-op
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM checklist_items WHERE reminder_id = $1;`, id); err != nil {
+		return fmt.Errorf("failed to remove reminder %d checklist: %w", id, err)
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM reminders WHERE id = $1;`, id)
 	if err != nil {
 		return fmt.Errorf("failed to remove reminder %d: %w", id, err)
 	}
@@ -56,13 +159,26 @@ func (s *Storage) RemoveReminder(ctx context.Context, id int64) error {
 		return fmt.Errorf("failed to remove reminder %d: %w", id, ErrReminderNotFound)
 	}
 
-	log.Printf("[INFO] removed reminder %d", id)
+	if err = insertReminderEvent(ctx, tx, domain.ReminderEvent{ReminderID: id, Type: domain.ReminderEventRemoved}); err != nil {
+		return err
+	}
+
+	if err = insertOutboxMessages(ctx, tx, msgs); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	logging.Printf(ctx, "[INFO] removed reminder %d", id)
 
 	return nil
 }
 
-// SaveReminder - saves reminder.
-func (s *Storage) SaveReminder(ctx context.Context, reminder domain.Reminder) (int64, error) {
+// SaveReminder - saves reminder, returns its id. Messages built by newMsgs from id of reminder, e.g. response with
+// buttons of the reminder, are enqueued to outbox in the same transaction. newMsgs can be nil.
+func (s *Storage) SaveReminder(ctx context.Context, reminder domain.Reminder, newMsgs func(id int64) ([]domain.OutboxMessage, error)) (int64, error) {
 	now := timeNowUTC()
 	if reminder.CreatedAt.IsZero() {
 		reminder.CreatedAt = now
@@ -70,6 +186,9 @@ func (s *Storage) SaveReminder(ctx context.Context, reminder domain.Reminder) (i
 	if reminder.ModifiedAt.IsZero() {
 		reminder.ModifiedAt = now
 	}
+	if reminder.Priority == "" {
+		reminder.Priority = domain.ReminderPriorityNormal
+	}
 
 	const query = `
 		INSERT INTO reminders(
@@ -80,11 +199,21 @@ func (s *Storage) SaveReminder(ctx context.Context, reminder domain.Reminder) (i
 			, modified_at
 			, remind_at
 			, status
-			, attempts_left             
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			, attempts_left
+			, priority
+			, lead_times
+			, channels
+			, next_pre_notice_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING id;`
 
-	if err := s.db.GetContext(ctx, &reminder.ID, query,
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint:errcheck // rollback after commit is no-op
+
+	if err = tx.GetContext(ctx, &reminder.ID, query,
 		reminder.ChatID,
 		reminder.UserID,
 		reminder.Text,
@@ -93,17 +222,70 @@ func (s *Storage) SaveReminder(ctx context.Context, reminder domain.Reminder) (i
 		reminder.RemindAt,
 		reminder.Status,
 		reminder.AttemptsLeft,
+		reminder.Priority,
+		reminder.LeadTimes,
+		reminder.Channels,
+		reminder.NextPreNoticeAt,
 	); err != nil {
 		return 0, fmt.Errorf("failed to save reminder %s: %w", reminder, err)
 	}
 
-	log.Printf("[INFO] saved reminder %s", reminder)
+	if err = insertChecklistItems(ctx, tx, reminder.ID, 0, reminder.Checklist.Texts()); err != nil {
+		return 0, fmt.Errorf("failed to save reminder %s: %w", reminder, err)
+	}
+
+	if err = insertReminderEvent(ctx, tx, domain.ReminderEvent{
+		ReminderID: reminder.ID,
+		Type:       domain.ReminderEventCreated,
+		RemindAtTo: &reminder.RemindAt,
+	}); err != nil {
+		return 0, err
+	}
+
+	if err = insertNewOutboxMessages(ctx, tx, reminder.ID, newMsgs); err != nil {
+		return 0, fmt.Errorf("failed to save reminder %s: %w", reminder, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	logging.Printf(ctx, "[INFO] saved reminder %s", reminder)
 
 	return reminder.ID, nil
 }
 
-// UpdateReminder - updates reminder.
+// UpdateReminder - updates reminder. Change of status or remind time is recorded as reminder event.
 func (s *Storage) UpdateReminder(ctx context.Context, reminder domain.Reminder) error {
+	return s.inTx(ctx, func(tx *sqlx.Tx) error {
+		var prev domain.Reminder
+		if err := tx.GetContext(ctx, &prev, `SELECT status, remind_at FROM reminders WHERE id = $1;`, reminder.ID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrReminderNotFound
+			}
+			return fmt.Errorf("failed to get reminder %d: %w", reminder.ID, err)
+		}
+
+		if err := updateReminder(ctx, tx, reminder); err != nil {
+			return err
+		}
+
+		event := domain.ReminderEvent{ReminderID: reminder.ID}
+		switch eventType, ok := domain.ReminderEventTypeByStatus(reminder.Status); {
+		case ok && reminder.Status != prev.Status:
+			event.Type = eventType
+		case !reminder.RemindAt.Equal(prev.RemindAt):
+			event.Type = domain.ReminderEventDelayed
+			event.RemindAtFrom, event.RemindAtTo = &prev.RemindAt, &reminder.RemindAt
+		default:
+			return nil
+		}
+
+		return insertReminderEvent(ctx, tx, event)
+	})
+}
+
+func updateReminder(ctx context.Context, db sqlx.ExecerContext, reminder domain.Reminder) error {
 	if reminder.ModifiedAt.IsZero() {
 		reminder.ModifiedAt = timeNowUTC()
 	}
@@ -116,7 +298,7 @@ func (s *Storage) UpdateReminder(ctx context.Context, reminder domain.Reminder)
 			, modified_at = $4
 		WHERE id = $5;`
 
-	res, err := s.db.ExecContext(ctx, query,
+	res, err := db.ExecContext(ctx, query,
 		reminder.Status,
 		reminder.AttemptsLeft,
 		reminder.RemindAt,
@@ -131,7 +313,7 @@ func (s *Storage) UpdateReminder(ctx context.Context, reminder domain.Reminder)
 		return ErrReminderNotFound
 	}
 
-	log.Printf("[INFO] updated reminder %s", reminder)
+	logging.Printf(ctx, "[INFO] updated reminder %s", reminder)
 
 	return nil
 }
@@ -149,12 +331,18 @@ func (s *Storage) GetPendingReminders(ctx context.Context, limit int64) ([]domai
 			, r.remind_at
 			, r.status
 			, r.attempts_left
+			, r.priority
+			, r.lead_times
+			, r.channels
+			, r.next_pre_notice_at
 		FROM reminders r
 		JOIN users u ON r.user_id = u.id
 		WHERE r.status = 'pending'
 			AND r.remind_at < $1
 			AND r.attempts_left > 0
-			AND u.status = 'active';`
+			AND u.status = 'active'
+		ORDER BY r.remind_at, r.id
+		LIMIT $2;`
 
 	var reminders []domain.Reminder
 
@@ -162,16 +350,38 @@ func (s *Storage) GetPendingReminders(ctx context.Context, limit int64) ([]domai
 		return nil, err
 	}
 
-	log.Printf("[DEBUG] got %d pending reminders", len(reminders))
+	if err := s.loadChecklists(ctx, reminders); err != nil {
+		return nil, err
+	}
+
+	logging.Printf(ctx, "[DEBUG] got %d pending reminders", len(reminders))
 
 	return reminders, nil
 }
 
-// SetReminderStatus - set's reminder status by id.
-func (s *Storage) SetReminderStatus(ctx context.Context, id int64, status domain.ReminderStatus) error {
+// SetReminderStatus - set's reminder status by id, msgs are enqueued to outbox in the same transaction.
+func (s *Storage) SetReminderStatus(ctx context.Context, id int64, status domain.ReminderStatus, msgs ...domain.OutboxMessage) error {
+	err := s.inTx(ctx, func(tx *sqlx.Tx) error {
+		if err := setReminderStatus(ctx, tx, id, status); err != nil {
+			return err
+		}
+
+		return insertOutboxMessages(ctx, tx, msgs)
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.Printf(ctx, "[INFO] set reminder %d status to %s", id, status)
+
+	return nil
+}
+
+// setReminderStatus sets reminder status and records event of the status change.
+func setReminderStatus(ctx context.Context, tx *sqlx.Tx, id int64, status domain.ReminderStatus) error {
 	const query = `UPDATE reminders SET status = $1, modified_at = $2 WHERE id = $3;`
 
-	res, err := s.db.ExecContext(ctx, query, status, timeNowUTC(), id)
+	res, err := tx.ExecContext(ctx, query, status, timeNowUTC(), id)
 	if err != nil {
 		return fmt.Errorf("failed to reminder %d status to %s: %w", id, status, err)
 	}
@@ -180,25 +390,432 @@ func (s *Storage) SetReminderStatus(ctx context.Context, id int64, status domain
 		return fmt.Errorf("failed to reminder %d status to %s: %w", id, status, ErrReminderNotFound)
 	}
 
-	log.Printf("[INFO] set reminder %d status to %s", id, status)
+	if eventType, ok := domain.ReminderEventTypeByStatus(status); ok {
+		return insertReminderEvent(ctx, tx, domain.ReminderEvent{ReminderID: id, Type: eventType})
+	}
 
 	return nil
 }
 
 // DelayReminder - delays reminder by id. Reminder will be fired at remindAt time.
-func (s *Storage) DelayReminder(ctx context.Context, id int64, remindAt time.Time) error {
-	const query = "UPDATE reminders SET remind_at = $1, attempts_left = $2, modified_at = $3 WHERE id = $4 AND status = 'pending';"
+// Reminder with exhausted attempts becomes pending again, it's the only way to delay reminder which is sent only once.
+// Attempts left are reset to the number of attempts of reminder's priority.
+// Advance notices are not sent for delayed reminder. msgs are enqueued to outbox in the same transaction.
+func (s *Storage) DelayReminder(ctx context.Context, id int64, remindAt time.Time, msgs ...domain.OutboxMessage) error {
+	var attemptsLeft byte
+	err := s.inTx(ctx, func(tx *sqlx.Tx) (err error) {
+		if attemptsLeft, err = delayReminder(ctx, tx, id, remindAt); err != nil {
+			return err
+		}
+
+		return insertOutboxMessages(ctx, tx, msgs)
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.Printf(ctx, "[INFO] delayed reminder [ID: %d, RemindAt: %s, AttemptsLeft: %d]", id, remindAt, attemptsLeft)
 
-	res, err := s.db.ExecContext(ctx, query, remindAt, domain.DefaultAttemptsLeft, timeNowUTC(), id)
+	return nil
+}
+
+// delayReminder delays reminder and records event of the delay, returns attempts left of delayed reminder.
+func delayReminder(ctx context.Context, tx *sqlx.Tx, id int64, remindAt time.Time) (byte, error) {
+	const query = `
+		UPDATE reminders
+		SET remind_at = $1, attempts_left = $2, modified_at = $3, status = 'pending', next_pre_notice_at = NULL
+		WHERE id = $4 AND status IN ('pending', 'attempts_exhausted');`
+
+	var prev struct {
+		RemindAt time.Time               `db:"remind_at"`
+		Priority domain.ReminderPriority `db:"priority"`
+	}
+	if err := tx.GetContext(ctx, &prev, `SELECT remind_at, priority FROM reminders WHERE id = $1;`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("failed to delay reminder %d: %w", id, ErrReminderNotFound)
+		}
+		return 0, fmt.Errorf("failed to delay reminder %d: %w", id, err)
+	}
+	attemptsLeft := prev.Priority.Attempts()
+
+	res, err := tx.ExecContext(ctx, query, remindAt, attemptsLeft, timeNowUTC(), id)
 	if err != nil {
-		return fmt.Errorf("failed to delay reminder %d: %w", id, err)
+		return 0, fmt.Errorf("failed to delay reminder %d: %w", id, err)
 	}
 
 	if affected, _ := res.RowsAffected(); affected == 0 {
-		return fmt.Errorf("failed to delay reminder %d: %w", id, ErrReminderNotFound)
+		return 0, fmt.Errorf("failed to delay reminder %d: %w", id, ErrReminderNotFound)
+	}
+
+	if err = insertReminderEvent(ctx, tx, domain.ReminderEvent{
+		ReminderID:   id,
+		Type:         domain.ReminderEventDelayed,
+		RemindAtFrom: &prev.RemindAt,
+		RemindAtTo:   &remindAt,
+	}); err != nil {
+		return 0, err
 	}
 
-	log.Printf("[INFO] delayed reminder [ID: %d, RemindAt: %s, AttemptsLeft: %d]", id, remindAt, domain.DefaultAttemptsLeft)
+	return attemptsLeft, nil
+}
+
+// UpdateReminderText - changes text of reminder by id, the change is recorded as reminder event.
+func (s *Storage) UpdateReminderText(ctx context.Context, id int64, text string) error {
+	err := s.inTx(ctx, func(tx *sqlx.Tx) error {
+		const query = `UPDATE reminders SET text = $1, modified_at = $2 WHERE id = $3;`
+
+		res, err := tx.ExecContext(ctx, query, text, timeNowUTC(), id)
+		if err != nil {
+			return err
+		}
+
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			return ErrReminderNotFound
+		}
+
+		return insertReminderEvent(ctx, tx, domain.ReminderEvent{ReminderID: id, Type: domain.ReminderEventEdited})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update reminder %d text: %w", id, err)
+	}
+
+	logging.Printf(ctx, "[INFO] updated reminder text [ID: %d]", id)
 
 	return nil
 }
+
+// SetReminderChannels - sets channels of reminder by id, empty channels mean user's default ones.
+func (s *Storage) SetReminderChannels(ctx context.Context, id int64, channels domain.Channels) error {
+	if err := setReminderChannels(ctx, s.db, id, channels); err != nil {
+		return err
+	}
+
+	logging.Printf(ctx, "[INFO] set reminder channels [ID: %d, Channels: %s]", id, channels)
+
+	return nil
+}
+
+func setReminderChannels(ctx context.Context, db sqlx.ExecerContext, id int64, channels domain.Channels) error {
+	const query = `UPDATE reminders SET channels = $1, modified_at = $2 WHERE id = $3;`
+
+	res, err := db.ExecContext(ctx, query, channels, timeNowUTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to set reminder %d channels: %w", id, err)
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("failed to set reminder %d channels: %w", id, ErrReminderNotFound)
+	}
+
+	return nil
+}
+
+// ReminderPatch - changes of reminder applied together, nil fields are not changed.
+// RemindAt and Status are mutually exclusive.
+type ReminderPatch struct {
+	RemindAt *time.Time
+	Status   *domain.ReminderStatus
+	Channels *domain.Channels
+}
+
+// PatchReminder - applies patch to reminder by id in one transaction, either all changes are saved or none.
+// Reminder is delayed with new remind time like in [Storage.DelayReminder].
+func (s *Storage) PatchReminder(ctx context.Context, id int64, patch ReminderPatch) error {
+	if patch.RemindAt != nil && patch.Status != nil {
+		return fmt.Errorf("failed to patch reminder %d: remind time and status can't be changed together", id)
+	}
+
+	err := s.inTx(ctx, func(tx *sqlx.Tx) error {
+		if patch.Channels != nil {
+			if err := setReminderChannels(ctx, tx, id, *patch.Channels); err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case patch.RemindAt != nil:
+			_, err := delayReminder(ctx, tx, id, *patch.RemindAt)
+			return err
+		case patch.Status != nil:
+			return setReminderStatus(ctx, tx, id, *patch.Status)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.Printf(ctx, "[INFO] patched reminder %d", id)
+
+	return nil
+}
+
+// GetReminder - returns reminder by id.
+func (s *Storage) GetReminder(ctx context.Context, id int64) (domain.Reminder, error) {
+	return getReminder(ctx, s.db, id)
+}
+
+func getReminder(ctx context.Context, db sqlx.QueryerContext, id int64) (domain.Reminder, error) {
+	const query = `
+		SELECT
+		    id
+			, chat_id
+			, user_id
+			, text
+			, created_at
+			, modified_at
+			, remind_at
+			, status
+			, attempts_left
+			, priority
+			, lead_times
+			, channels
+			, next_pre_notice_at
+		FROM reminders
+		WHERE id = $1;`
+
+	var reminder domain.Reminder
+	if err := sqlx.GetContext(ctx, db, &reminder, query, id); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return domain.Reminder{}, fmt.Errorf("failed to get reminder %d: %w", id, ErrReminderNotFound)
+		default:
+			return domain.Reminder{}, fmt.Errorf("failed to get reminder %d: %w", id, err)
+		}
+	}
+
+	checklist, err := getChecklist(ctx, db, id)
+	if err != nil {
+		return domain.Reminder{}, fmt.Errorf("failed to get reminder %d: %w", id, err)
+	}
+	reminder.Checklist = checklist
+
+	return reminder, nil
+}
+
+// SetReminderLeadTimes - sets lead times of pending reminder and time to send the next advance notice at.
+// msgs are enqueued to outbox in the same transaction.
+func (s *Storage) SetReminderLeadTimes(ctx context.Context, id int64, leadTimes domain.LeadTimes, nextPreNoticeAt *time.Time, msgs ...domain.OutboxMessage) error {
+	const query = `
+		UPDATE reminders
+		SET lead_times = $1, next_pre_notice_at = $2, modified_at = $3
+		WHERE id = $4 AND status = 'pending';`
+
+	err := s.inTx(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx, query, leadTimes, nextPreNoticeAt, timeNowUTC(), id)
+		if err != nil {
+			return err
+		}
+
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			return ErrReminderNotFound
+		}
+
+		return insertOutboxMessages(ctx, tx, msgs)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set reminder %d lead times: %w", id, err)
+	}
+
+	logging.Printf(ctx, "[INFO] set reminder %d lead times [%s]", id, leadTimes.Format())
+
+	return nil
+}
+
+// GetDuePreNotices - returns pending reminders of active users whose advance notice should be sent now.
+func (s *Storage) GetDuePreNotices(ctx context.Context, limit int64) ([]domain.Reminder, error) {
+	const query = `
+		SELECT
+		    r.id
+			, r.chat_id
+			, r.user_id
+			, r.text
+			, r.created_at
+			, r.modified_at
+			, r.remind_at
+			, r.status
+			, r.attempts_left
+			, r.priority
+			, r.lead_times
+			, r.channels
+			, r.next_pre_notice_at
+		FROM reminders r
+		JOIN users u ON r.user_id = u.id
+		WHERE r.status = 'pending'
+			AND r.next_pre_notice_at IS NOT NULL
+			AND r.next_pre_notice_at <= $1
+			AND r.remind_at > $1
+			AND u.status = 'active'
+		ORDER BY r.next_pre_notice_at
+		LIMIT $2;`
+
+	var reminders []domain.Reminder
+	if err := s.db.SelectContext(ctx, &reminders, query, timeNowUTC(), limit); err != nil {
+		return nil, fmt.Errorf("failed to get due advance notices: %w", err)
+	}
+
+	logging.Printf(ctx, "[DEBUG] got %d due advance notices", len(reminders))
+
+	return reminders, nil
+}
+
+// SetReminderNextPreNotice - sets time to send the next advance notice of reminder at. Nil means no more advance notices.
+func (s *Storage) SetReminderNextPreNotice(ctx context.Context, id int64, nextPreNoticeAt *time.Time) error {
+	return setReminderNextPreNotice(ctx, s.db, id, nextPreNoticeAt)
+}
+
+func setReminderNextPreNotice(ctx context.Context, db sqlx.ExecerContext, id int64, nextPreNoticeAt *time.Time) error {
+	const query = `UPDATE reminders SET next_pre_notice_at = $1 WHERE id = $2;`
+
+	res, err := db.ExecContext(ctx, query, nextPreNoticeAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to set reminder %d next advance notice: %w", id, err)
+	}
+
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("failed to set reminder %d next advance notice: %w", id, ErrReminderNotFound)
+	}
+
+	return nil
+}
+
+// GetMissedReminders - returns pending reminders which were due before "before", ordered by chat and time.
+// Reminders of user are returned regardless of user status if userID is set, otherwise reminders of all active users.
+func (s *Storage) GetMissedReminders(ctx context.Context, userID int64, before time.Time) ([]domain.Reminder, error) {
+	const query = `
+		SELECT
+		    r.id
+			, r.chat_id
+			, r.user_id
+			, r.text
+			, r.created_at
+			, r.modified_at
+			, r.remind_at
+			, r.status
+			, r.attempts_left
+			, r.priority
+			, r.lead_times
+			, r.channels
+			, r.next_pre_notice_at
+		FROM reminders r
+		JOIN users u ON r.user_id = u.id
+		WHERE r.status = 'pending'
+			AND r.remind_at < $1
+			AND r.attempts_left > 0
+			AND (r.user_id = $2 OR ($2 = 0 AND u.status = 'active'))
+		ORDER BY r.chat_id, r.user_id, r.remind_at;`
+
+	var reminders []domain.Reminder
+	if err := s.db.SelectContext(ctx, &reminders, query, before, userID); err != nil {
+		return nil, fmt.Errorf("failed to get missed reminders: %w", err)
+	}
+
+	logging.Printf(ctx, "[DEBUG] got %d missed reminders", len(reminders))
+
+	return reminders, nil
+}
+
+// MissReminders - marks reminders as missed, so they aren't sent. Summary of missed reminders is enqueued
+// in the same transaction if it's set, its key is saved with the reminders.
+func (s *Storage) MissReminders(ctx context.Context, ids []int64, summary *domain.MissedSummary) error {
+	return s.inTx(ctx, func(tx *sqlx.Tx) error {
+		const query = `UPDATE reminders SET status = $1, summary_key = $2, modified_at = $3 WHERE id = $4 AND status = $5;`
+
+		var summaryKey *string
+		if summary != nil {
+			summaryKey = &summary.Key
+		}
+
+		now := timeNowUTC()
+		for _, id := range ids {
+			res, err := tx.ExecContext(ctx, query, domain.ReminderStatusMissed, summaryKey, now, id, domain.ReminderStatusPending)
+			if err != nil {
+				return fmt.Errorf("failed to miss reminder %d: %w", id, err)
+			}
+
+			if affected, _ := res.RowsAffected(); affected == 0 {
+				continue
+			}
+
+			if err = insertReminderEvent(ctx, tx, domain.ReminderEvent{ReminderID: id, Type: domain.ReminderEventMissed}); err != nil {
+				return err
+			}
+		}
+
+		if summary != nil {
+			return insertOutboxMessage(ctx, tx, summary.Message)
+		}
+
+		return nil
+	})
+}
+
+// GetSummaryReminderIDs - returns ids of reminders of user in chat listed in summary with the key, which are still missed.
+func (s *Storage) GetSummaryReminderIDs(ctx context.Context, userID, chatID int64, summaryKey string) ([]int64, error) {
+	const query = `
+		SELECT id
+		FROM reminders
+		WHERE user_id = $1 AND chat_id = $2 AND summary_key = $3 AND status = 'missed'
+		ORDER BY id;`
+
+	var ids []int64
+	if err := s.db.SelectContext(ctx, &ids, query, userID, chatID, summaryKey); err != nil {
+		return nil, fmt.Errorf("failed to get missed reminders of summary of user %d: %w", userID, err)
+	}
+
+	return ids, nil
+}
+
+// RescheduleMissedReminders - makes missed reminders of user in chat with ids pending again with new remindAt.
+// Attempts are reset according to priority of every reminder. Returns number of rescheduled reminders.
+// Messages built by newMsgs from the number are enqueued to outbox in the same transaction, newMsgs can be nil.
+func (s *Storage) RescheduleMissedReminders(ctx context.Context, userID, chatID int64, ids []int64, remindAt time.Time,
+	newMsgs func(rescheduled int64) ([]domain.OutboxMessage, error)) (int64, error) {
+	const selectQuery = `
+		SELECT remind_at, priority
+		FROM reminders
+		WHERE id = $1 AND user_id = $2 AND chat_id = $3 AND status = 'missed';`
+
+	const query = `
+		UPDATE reminders
+		SET remind_at = $1, attempts_left = $2, modified_at = $3, status = 'pending', next_pre_notice_at = NULL, summary_key = NULL
+		WHERE id = $4;`
+
+	var affected int64
+	err := s.inTx(ctx, func(tx *sqlx.Tx) error {
+		affected = 0
+		for _, id := range ids {
+			var prev struct {
+				RemindAt time.Time               `db:"remind_at"`
+				Priority domain.ReminderPriority `db:"priority"`
+			}
+			if err := tx.GetContext(ctx, &prev, selectQuery, id, userID, chatID); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					continue
+				}
+				return fmt.Errorf("failed to get missed reminder %d: %w", id, err)
+			}
+
+			if _, err := tx.ExecContext(ctx, query, remindAt, prev.Priority.Attempts(), timeNowUTC(), id); err != nil {
+				return fmt.Errorf("failed to reschedule missed reminder %d: %w", id, err)
+			}
+
+			event := domain.ReminderEvent{ReminderID: id, Type: domain.ReminderEventDelayed, RemindAtFrom: &prev.RemindAt, RemindAtTo: &remindAt}
+			if err := insertReminderEvent(ctx, tx, event); err != nil {
+				return err
+			}
+
+			affected++
+		}
+
+		return insertNewOutboxMessages(ctx, tx, affected, newMsgs)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	logging.Printf(ctx, "[INFO] rescheduled %d missed reminders of user %d in chat %d to %s", affected, userID, chatID, remindAt)
+
+	return affected, nil
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"slices"
 	"time"
 
@@ -10,6 +11,158 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func (s *storageTestSuite) Test_storage_UpdateReminderText() {
+	s.Run("success", func() {
+		// ARRANGE
+		reminder := domain.Reminder{
+			ChatID:       1,
+			UserID:       1,
+			Text:         "buy milk",
+			CreatedAt:    timeNowUTC().Truncate(1 * time.Minute),
+			ModifiedAt:   timeNowUTC().Truncate(1 * time.Minute),
+			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
+			Status:       domain.ReminderStatusPending,
+			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
+		}
+
+		id, err := s.storage.SaveReminder(context.TODO(), reminder, nil)
+		s.Require().NoError(err)
+
+		// ACT
+		s.Require().NoError(s.storage.UpdateReminderText(context.TODO(), id, "buy bread"))
+
+		// ASSERT
+		actReminder := s.mustGetReminder(id)
+		s.Require().Equal("buy bread", actReminder.Text)
+		s.Require().Equal(reminder.RemindAt, actReminder.RemindAt)
+		s.Require().Greater(actReminder.ModifiedAt, reminder.ModifiedAt)
+
+		events, err := s.storage.GetReminderEvents(context.TODO(), id)
+		s.Require().NoError(err)
+		s.Require().Len(events, 2)
+		s.Equal(domain.ReminderEventCreated, events[0].Type)
+		s.Equal(domain.ReminderEventEdited, events[1].Type)
+	})
+
+	s.Run("error: reminder not found", func() {
+		s.Require().ErrorIs(s.storage.UpdateReminderText(context.TODO(), 100, "buy bread"), ErrReminderNotFound)
+
+		var count int
+		s.Require().NoError(s.storage.db.Get(&count, `SELECT COUNT(*) FROM reminder_events;`))
+		s.Zero(count, "event isn't recorded")
+	})
+}
+
+func (s *storageTestSuite) Test_storage_SetReminderChannels() {
+	s.Run("success", func() {
+		// ARRANGE
+		id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:       1,
+			UserID:       1,
+			Text:         "buy milk",
+			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
+			Status:       domain.ReminderStatusPending,
+			AttemptsLeft: 3,
+			Channels:     domain.Channels{domain.ChannelEmail},
+		}, nil)
+		s.Require().NoError(err)
+		s.Require().Equal(domain.Channels{domain.ChannelEmail}, s.mustGetReminder(id).Channels)
+
+		// ACT
+		s.Require().NoError(s.storage.SetReminderChannels(context.TODO(), id, domain.Channels{domain.ChannelTelegram, domain.ChannelPush}))
+
+		// ASSERT
+		s.Require().Equal(domain.Channels{domain.ChannelTelegram, domain.ChannelPush}, s.mustGetReminder(id).Channels)
+
+		// ACT
+		s.Require().NoError(s.storage.SetReminderChannels(context.TODO(), id, nil))
+
+		// ASSERT
+		s.Require().Empty(s.mustGetReminder(id).Channels)
+	})
+
+	s.Run("error: reminder not found", func() {
+		s.Require().ErrorIs(s.storage.SetReminderChannels(context.TODO(), 100, nil), ErrReminderNotFound)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_PatchReminder() {
+	newReminder := func(status domain.ReminderStatus) int64 {
+		id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:       1,
+			UserID:       1,
+			Text:         "buy milk",
+			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
+			Status:       status,
+			AttemptsLeft: 0,
+			Priority:     domain.ReminderPriorityNormal,
+			Channels:     domain.Channels{domain.ChannelEmail},
+		}, nil)
+		s.Require().NoError(err)
+		return id
+	}
+
+	s.Run("success: channels are changed and reminder is delayed", func() {
+		// ARRANGE
+		id := newReminder(domain.ReminderStatusAttemptsExhausted)
+		remindAt := timeNowUTC().Add(1 * time.Hour).Truncate(1 * time.Minute)
+
+		// ACT
+		s.Require().NoError(s.storage.PatchReminder(context.TODO(), id, ReminderPatch{
+			RemindAt: &remindAt,
+			Channels: &domain.Channels{domain.ChannelTelegram},
+		}))
+
+		// ASSERT
+		act := s.mustGetReminder(id)
+		s.Equal(domain.Channels{domain.ChannelTelegram}, act.Channels)
+		s.Equal(remindAt, act.RemindAt)
+		s.Equal(domain.ReminderStatusPending, act.Status)
+		s.Equal(domain.ReminderPriorityNormal.Attempts(), act.AttemptsLeft)
+	})
+
+	s.Run("success: channels are changed and reminder is done", func() {
+		// ARRANGE
+		id := newReminder(domain.ReminderStatusPending)
+		status := domain.ReminderStatusDone
+
+		// ACT
+		s.Require().NoError(s.storage.PatchReminder(context.TODO(), id, ReminderPatch{
+			Status:   &status,
+			Channels: &domain.Channels{},
+		}))
+
+		// ASSERT
+		act := s.mustGetReminder(id)
+		s.Empty(act.Channels)
+		s.Equal(domain.ReminderStatusDone, act.Status)
+	})
+
+	s.Run("error: done reminder can't be delayed, channels aren't changed", func() {
+		// ARRANGE
+		id := newReminder(domain.ReminderStatusDone)
+		remindAt := timeNowUTC().Add(1 * time.Hour)
+
+		// ACT
+		err := s.storage.PatchReminder(context.TODO(), id, ReminderPatch{
+			RemindAt: &remindAt,
+			Channels: &domain.Channels{domain.ChannelTelegram},
+		})
+
+		// ASSERT
+		s.Require().ErrorIs(err, ErrReminderNotFound)
+		act := s.mustGetReminder(id)
+		s.Equal(domain.Channels{domain.ChannelEmail}, act.Channels)
+		s.Equal(domain.ReminderStatusDone, act.Status)
+	})
+
+	s.Run("error: reminder not found", func() {
+		status := domain.ReminderStatusDone
+		s.Require().ErrorIs(s.storage.PatchReminder(context.TODO(), 100, ReminderPatch{Status: &status}), ErrReminderNotFound)
+	})
+}
+
 func (s *storageTestSuite) Test_storage_DelayReminder() {
 	s.Run("success", func() {
 		// ARRANGE
@@ -22,9 +175,10 @@ func (s *storageTestSuite) Test_storage_DelayReminder() {
 			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
 			Status:       domain.ReminderStatusPending,
 			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
 		}
 
-		id, err := s.storage.SaveReminder(context.TODO(), reminder)
+		id, err := s.storage.SaveReminder(context.TODO(), reminder, nil)
 		s.Require().NoError(err)
 
 		// ACT
@@ -39,6 +193,59 @@ func (s *storageTestSuite) Test_storage_DelayReminder() {
 		s.Require().Greater(actReminder.ModifiedAt, reminder.ModifiedAt)
 	})
 
+	s.Run("success: attempts exhausted", func() {
+		// ARRANGE
+		reminder := domain.Reminder{
+			ChatID:       4,
+			UserID:       4,
+			Text:         "Quiet reminder sent only once.",
+			CreatedAt:    timeNowUTC().Truncate(1 * time.Minute),
+			ModifiedAt:   timeNowUTC().Truncate(1 * time.Minute),
+			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
+			Status:       domain.ReminderStatusAttemptsExhausted,
+			AttemptsLeft: 0,
+			Priority:     domain.ReminderPriorityLow,
+		}
+
+		id, err := s.storage.SaveReminder(context.TODO(), reminder, nil)
+		s.Require().NoError(err)
+
+		// ACT
+		remindAt := timeNowUTC().Add(1 * time.Hour).Truncate(1 * time.Minute)
+		s.Require().NoError(s.storage.DelayReminder(context.TODO(), id, remindAt))
+
+		// ASSERT
+		actReminder := s.mustGetReminder(id)
+		s.Require().EqualValues(1, actReminder.AttemptsLeft)
+		s.Require().Equal(remindAt, actReminder.RemindAt)
+		s.Require().Equal(domain.ReminderStatusPending, actReminder.Status)
+		s.Require().Equal(domain.ReminderPriorityLow, actReminder.Priority)
+	})
+
+	s.Run("success: attempts are reset by priority", func() {
+		// ARRANGE
+		reminder := domain.Reminder{
+			ChatID:       5,
+			UserID:       5,
+			Text:         "Critical reminder.",
+			CreatedAt:    timeNowUTC().Truncate(1 * time.Minute),
+			ModifiedAt:   timeNowUTC().Truncate(1 * time.Minute),
+			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
+			Status:       domain.ReminderStatusPending,
+			AttemptsLeft: 2,
+			Priority:     domain.ReminderPriorityCritical,
+		}
+
+		id, err := s.storage.SaveReminder(context.TODO(), reminder, nil)
+		s.Require().NoError(err)
+
+		// ACT
+		s.Require().NoError(s.storage.DelayReminder(context.TODO(), id, timeNowUTC().Add(1*time.Hour)))
+
+		// ASSERT
+		s.Require().EqualValues(20, s.mustGetReminder(id).AttemptsLeft)
+	})
+
 	s.Run("error: reminder status is done", func() {
 		// ARRANGE
 		reminder := domain.Reminder{
@@ -50,9 +257,10 @@ func (s *storageTestSuite) Test_storage_DelayReminder() {
 			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
 			Status:       domain.ReminderStatusDone,
 			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
 		}
 
-		id, err := s.storage.SaveReminder(context.TODO(), reminder)
+		id, err := s.storage.SaveReminder(context.TODO(), reminder, nil)
 		s.Require().NoError(err)
 
 		// ACT & ASSERT
@@ -81,8 +289,9 @@ func (s *storageTestSuite) Test_storage_GetMyReminders() {
 			RemindAt:     timeNowUTC().Add(1 * time.Hour).Truncate(1 * time.Minute),
 			Status:       domain.ReminderStatusPending,
 			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
 		}
-		_, err := s.storage.SaveReminder(context.TODO(), pendingReminder1)
+		_, err := s.storage.SaveReminder(context.TODO(), pendingReminder1, nil)
 		s.Require().NoError(err)
 
 		pendingReminder2 := domain.Reminder{
@@ -94,8 +303,9 @@ func (s *storageTestSuite) Test_storage_GetMyReminders() {
 			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
 			Status:       domain.ReminderStatusPending,
 			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
 		}
-		_, err = s.storage.SaveReminder(context.TODO(), pendingReminder2)
+		_, err = s.storage.SaveReminder(context.TODO(), pendingReminder2, nil)
 		s.Require().NoError(err)
 
 		doneReminder := domain.Reminder{
@@ -107,8 +317,9 @@ func (s *storageTestSuite) Test_storage_GetMyReminders() {
 			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
 			Status:       domain.ReminderStatusDone,
 			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
 		}
-		_, err = s.storage.SaveReminder(context.TODO(), doneReminder)
+		_, err = s.storage.SaveReminder(context.TODO(), doneReminder, nil)
 		s.Require().NoError(err)
 
 		actRemidners, err := s.storage.GetMyReminders(context.TODO(), userID, chatID)
@@ -118,6 +329,131 @@ func (s *storageTestSuite) Test_storage_GetMyReminders() {
 	})
 }
 
+func (s *storageTestSuite) Test_storage_GetUserReminders() {
+	const (
+		userID = 132436
+		chatID = 3457547
+	)
+
+	saveReminders := func() (pending, done domain.Reminder) {
+		pending = domain.Reminder{
+			ChatID:       chatID,
+			UserID:       userID,
+			Text:         "Mechanisms fatal thought massage here lakes austria, qatar bless japanese consists bonds considerable hero.",
+			CreatedAt:    timeNowUTC().Truncate(1 * time.Minute),
+			ModifiedAt:   timeNowUTC().Truncate(1 * time.Minute),
+			RemindAt:     timeNowUTC().Add(1 * time.Hour).Truncate(1 * time.Minute),
+			Status:       domain.ReminderStatusPending,
+			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
+			Checklist:    []domain.ChecklistItem{{Position: 0, Text: "milk"}},
+		}
+		var err error
+		pending.ID, err = s.storage.SaveReminder(context.TODO(), pending, nil)
+		s.Require().NoError(err)
+
+		done = pending
+		done.Text = "Thin chevy wiring sort imperial recommendations key."
+		done.RemindAt = timeNowUTC().Truncate(1 * time.Minute)
+		done.Status = domain.ReminderStatusDone
+		done.Checklist = nil
+		done.ID, err = s.storage.SaveReminder(context.TODO(), done, nil)
+		s.Require().NoError(err)
+
+		otherChat := pending
+		otherChat.ChatID = chatID + 1
+		otherChat.Checklist = nil
+		_, err = s.storage.SaveReminder(context.TODO(), otherChat, nil)
+		s.Require().NoError(err)
+
+		return pending, done
+	}
+
+	s.Run("success: all statuses", func() {
+		// ARRANGE
+		pending, done := saveReminders()
+
+		// ACT
+		act, err := s.storage.GetUserReminders(context.TODO(), userID, chatID, "")
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.Require().Len(act, 2)
+		s.Require().Equal(done.ID, act[0].ID, "reminders are ordered by remind time")
+		s.Require().Equal(pending.ID, act[1].ID)
+		s.Require().Len(act[1].Checklist, 1, "checklist is loaded")
+		s.Require().Equal("milk", act[1].Checklist[0].Text)
+	})
+
+	s.Run("success: filter by status", func() {
+		// ARRANGE
+		_, done := saveReminders()
+
+		// ACT
+		act, err := s.storage.GetUserReminders(context.TODO(), userID, chatID, domain.ReminderStatusDone)
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.Require().Len(act, 1)
+		s.Require().Equal(done.ID, act[0].ID)
+		s.Require().Equal(domain.ReminderStatusDone, act[0].Status)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_FindReminders() {
+	saveReminder := func(userID, chatID int64, status domain.ReminderStatus, remindAt time.Time) int64 {
+		id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:   chatID,
+			UserID:   userID,
+			Text:     "Mechanisms fatal thought massage here lakes austria.",
+			RemindAt: remindAt,
+			Status:   status,
+		}, nil)
+		s.Require().NoError(err)
+		return id
+	}
+
+	ids := func(reminders []domain.Reminder) []int64 {
+		res := make([]int64, 0, len(reminders))
+		for _, r := range reminders {
+			res = append(res, r.ID)
+		}
+		return res
+	}
+
+	testCases := []struct {
+		name   string
+		filter ReminderFilter
+		exp    func(ids []int64) []int64
+	}{
+		{name: "success: no filter", filter: ReminderFilter{}, exp: func(ids []int64) []int64 { return []int64{ids[3], ids[2], ids[1], ids[0]} }},
+		{name: "success: by user", filter: ReminderFilter{UserID: 1}, exp: func(ids []int64) []int64 { return []int64{ids[2], ids[1], ids[0]} }},
+		{name: "success: by user and chat", filter: ReminderFilter{UserID: 1, ChatID: 10}, exp: func(ids []int64) []int64 { return []int64{ids[1], ids[0]} }},
+		{name: "success: by status", filter: ReminderFilter{Status: domain.ReminderStatusDone}, exp: func(ids []int64) []int64 { return []int64{ids[1]} }},
+		{name: "success: with limit", filter: ReminderFilter{Limit: 1}, exp: func(ids []int64) []int64 { return []int64{ids[3]} }},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			// ARRANGE
+			now := timeNowUTC().Truncate(1 * time.Minute)
+			saved := []int64{
+				saveReminder(1, 10, domain.ReminderStatusPending, now.Add(1*time.Hour)),
+				saveReminder(1, 10, domain.ReminderStatusDone, now.Add(2*time.Hour)),
+				saveReminder(1, 11, domain.ReminderStatusPending, now.Add(3*time.Hour)),
+				saveReminder(2, 20, domain.ReminderStatusPending, now.Add(4*time.Hour)),
+			}
+
+			// ACT
+			act, err := s.storage.FindReminders(context.TODO(), tc.filter)
+
+			// ASSERT
+			s.Require().NoError(err)
+			s.Require().Equal(tc.exp(saved), ids(act))
+		})
+	}
+}
+
 func (s *storageTestSuite) Test_storage_GetPendingReminders() {
 	s.Run("success: user is active", func() {
 		const (
@@ -134,8 +470,9 @@ func (s *storageTestSuite) Test_storage_GetPendingReminders() {
 			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
 			Status:       domain.ReminderStatusPending,
 			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
 		}
-		_, err := s.storage.SaveReminder(context.TODO(), pendingReminder1)
+		_, err := s.storage.SaveReminder(context.TODO(), pendingReminder1, nil)
 		s.Require().NoError(err)
 
 		pendingReminder2 := domain.Reminder{
@@ -147,8 +484,9 @@ func (s *storageTestSuite) Test_storage_GetPendingReminders() {
 			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
 			Status:       domain.ReminderStatusPending,
 			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
 		}
-		_, err = s.storage.SaveReminder(context.TODO(), pendingReminder2)
+		_, err = s.storage.SaveReminder(context.TODO(), pendingReminder2, nil)
 		s.Require().NoError(err)
 
 		doneReminder := domain.Reminder{
@@ -160,8 +498,9 @@ func (s *storageTestSuite) Test_storage_GetPendingReminders() {
 			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
 			Status:       domain.ReminderStatusDone,
 			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
 		}
-		_, err = s.storage.SaveReminder(context.TODO(), doneReminder)
+		_, err = s.storage.SaveReminder(context.TODO(), doneReminder, nil)
 		s.Require().NoError(err)
 
 		s.Require().NoError(s.storage.SaveUser(context.TODO(), domain.User{
@@ -175,6 +514,42 @@ func (s *storageTestSuite) Test_storage_GetPendingReminders() {
 		requireEqualRemindersList(s.Require(), []domain.Reminder{pendingReminder1, pendingReminder2}, reminders)
 	})
 
+	s.Run("success: limit is applied", func() {
+		const (
+			userID = 464358
+			chatID = 745675
+		)
+
+		s.Require().NoError(s.storage.SaveUser(context.TODO(), domain.User{
+			ID:     userID,
+			Name:   "Danay Rodney",
+			Status: domain.UserStatusActive,
+		}))
+
+		for i := 3; i > 0; i-- {
+			reminder := domain.Reminder{
+				ChatID:       chatID,
+				UserID:       userID,
+				Text:         fmt.Sprintf("Reminder %d", i),
+				CreatedAt:    timeNowUTC().Truncate(1 * time.Minute),
+				ModifiedAt:   timeNowUTC().Truncate(1 * time.Minute),
+				RemindAt:     timeNowUTC().Add(-time.Duration(i) * time.Minute).Truncate(1 * time.Minute),
+				Status:       domain.ReminderStatusPending,
+				AttemptsLeft: 3,
+				Priority:     domain.ReminderPriorityNormal,
+			}
+			_, err := s.storage.SaveReminder(context.TODO(), reminder, nil)
+			s.Require().NoError(err)
+		}
+
+		// the most overdue reminders go first
+		reminders, err := s.storage.GetPendingReminders(context.TODO(), 2)
+		s.Require().NoError(err)
+		s.Require().Len(reminders, 2)
+		s.Equal("Reminder 3", reminders[0].Text)
+		s.Equal("Reminder 2", reminders[1].Text)
+	})
+
 	s.Run("success: user is not active", func() {
 		const (
 			userID = 67854687
@@ -190,8 +565,9 @@ func (s *storageTestSuite) Test_storage_GetPendingReminders() {
 			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
 			Status:       domain.ReminderStatusPending,
 			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
 		}
-		_, err := s.storage.SaveReminder(context.TODO(), pendingReminder1)
+		_, err := s.storage.SaveReminder(context.TODO(), pendingReminder1, nil)
 		s.NoError(err)
 
 		s.NoError(s.storage.SaveUser(context.TODO(), domain.User{
@@ -220,8 +596,9 @@ func (s *storageTestSuite) Test_storage_GetPendingReminders() {
 			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
 			Status:       domain.ReminderStatusPending,
 			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
 		}
-		_, err := s.storage.SaveReminder(context.TODO(), pendingReminder1)
+		_, err := s.storage.SaveReminder(context.TODO(), pendingReminder1, nil)
 		s.Require().NoError(err)
 
 		reminders, err := s.storage.GetPendingReminders(context.TODO(), 2)
@@ -246,8 +623,9 @@ func (s *storageTestSuite) Test_storage_RemoveReminder() {
 			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
 			Status:       domain.ReminderStatusPending,
 			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
 		}
-		id, err := s.storage.SaveReminder(context.TODO(), pendingReminder)
+		id, err := s.storage.SaveReminder(context.TODO(), pendingReminder, nil)
 		s.NoError(err)
 
 		s.NoError(s.storage.RemoveReminder(context.TODO(), id))
@@ -272,8 +650,9 @@ func (s *storageTestSuite) Test_storage_SaveReminder() {
 			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
 			Status:       domain.ReminderStatusPending,
 			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
 		}
-		id, err := s.storage.SaveReminder(context.TODO(), reminder)
+		id, err := s.storage.SaveReminder(context.TODO(), reminder, nil)
 		s.Require().NoError(err)
 
 		actRemidner := s.mustGetReminder(id)
@@ -288,8 +667,9 @@ func (s *storageTestSuite) Test_storage_SaveReminder() {
 			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
 			Status:       domain.ReminderStatusPending,
 			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
 		}
-		id, err := s.storage.SaveReminder(context.TODO(), reminder)
+		id, err := s.storage.SaveReminder(context.TODO(), reminder, nil)
 		s.Require().NoError(err)
 
 		actRemidner := s.mustGetReminder(id)
@@ -309,9 +689,10 @@ func (s *storageTestSuite) Test_storage_SetReminderStatus() {
 			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
 			Status:       domain.ReminderStatusPending,
 			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
 		}
 
-		id, err := s.storage.SaveReminder(context.TODO(), reminder)
+		id, err := s.storage.SaveReminder(context.TODO(), reminder, nil)
 		s.NoError(err)
 
 		s.NoError(s.storage.SetReminderStatus(context.TODO(), id, domain.ReminderStatusDone))
@@ -337,9 +718,10 @@ func (s *storageTestSuite) Test_storage_UpdateReminder() {
 			RemindAt:     timeNowUTC().Truncate(1 * time.Minute),
 			Status:       domain.ReminderStatusPending,
 			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
 		}
 
-		id, err := s.storage.SaveReminder(context.TODO(), reminder)
+		id, err := s.storage.SaveReminder(context.TODO(), reminder, nil)
 		s.Require().NoError(err)
 
 		reminder.Status = domain.ReminderStatusDone
@@ -359,6 +741,189 @@ func (s *storageTestSuite) Test_storage_UpdateReminder() {
 	})
 }
 
+func (s *storageTestSuite) Test_storage_GetReminder() {
+	s.Run("success", func() {
+		// ARRANGE
+		reminder := domain.Reminder{
+			ChatID:       1,
+			UserID:       2,
+			Text:         "Meeting",
+			CreatedAt:    timeNowUTC().Truncate(1 * time.Minute),
+			ModifiedAt:   timeNowUTC().Truncate(1 * time.Minute),
+			RemindAt:     timeNowUTC().Add(2 * time.Hour).Truncate(1 * time.Minute),
+			Status:       domain.ReminderStatusPending,
+			AttemptsLeft: 10,
+			Priority:     domain.ReminderPriorityNormal,
+			LeadTimes:    domain.LeadTimes{time.Hour, 30 * time.Minute},
+		}
+		nextPreNoticeAt := reminder.RemindAt.Add(-time.Hour)
+		reminder.NextPreNoticeAt = &nextPreNoticeAt
+
+		id, err := s.storage.SaveReminder(context.TODO(), reminder, nil)
+		s.Require().NoError(err)
+		reminder.ID = id
+
+		// ACT
+		actReminder, err := s.storage.GetReminder(context.TODO(), id)
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.Require().Equal(reminder, actReminder)
+	})
+
+	s.Run("error: not found", func() {
+		_, err := s.storage.GetReminder(context.TODO(), 3465)
+		s.Require().ErrorIs(err, ErrReminderNotFound)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_SetReminderLeadTimes() {
+	s.Run("success", func() {
+		// ARRANGE
+		reminder := domain.Reminder{
+			ChatID:       1,
+			UserID:       2,
+			Text:         "Meeting",
+			RemindAt:     timeNowUTC().Add(2 * time.Hour).Truncate(1 * time.Minute),
+			Status:       domain.ReminderStatusPending,
+			AttemptsLeft: 10,
+		}
+
+		id, err := s.storage.SaveReminder(context.TODO(), reminder, nil)
+		s.Require().NoError(err)
+
+		// ACT
+		nextPreNoticeAt := reminder.RemindAt.Add(-30 * time.Minute)
+		s.Require().NoError(s.storage.SetReminderLeadTimes(context.TODO(), id, domain.LeadTimes{30 * time.Minute}, &nextPreNoticeAt))
+
+		// ASSERT
+		actReminder := s.mustGetReminder(id)
+		s.Require().Equal(domain.LeadTimes{30 * time.Minute}, actReminder.LeadTimes)
+		s.Require().Equal(&nextPreNoticeAt, actReminder.NextPreNoticeAt)
+
+		// ACT: remove all lead times
+		s.Require().NoError(s.storage.SetReminderLeadTimes(context.TODO(), id, nil, nil))
+
+		// ASSERT
+		actReminder = s.mustGetReminder(id)
+		s.Require().Empty(actReminder.LeadTimes)
+		s.Require().Nil(actReminder.NextPreNoticeAt)
+	})
+
+	s.Run("error: reminder is done", func() {
+		// ARRANGE
+		id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:   1,
+			UserID:   2,
+			Text:     "Meeting",
+			RemindAt: timeNowUTC(),
+			Status:   domain.ReminderStatusDone,
+		}, nil)
+		s.Require().NoError(err)
+
+		// ACT & ASSERT
+		s.Require().ErrorIs(s.storage.SetReminderLeadTimes(context.TODO(), id, domain.LeadTimes{time.Hour}, nil), ErrReminderNotFound)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_GetDuePreNotices() {
+	s.Run("success", func() {
+		const (
+			userID = 563456
+			chatID = 674567
+		)
+
+		// ARRANGE
+		s.Require().NoError(s.storage.SaveUser(context.TODO(), domain.User{ID: userID, Name: "Lana", Status: domain.UserStatusActive}))
+
+		dueAt := timeNowUTC().Add(-1 * time.Minute).Truncate(1 * time.Minute)
+		dueReminder := domain.Reminder{
+			ChatID:          chatID,
+			UserID:          userID,
+			Text:            "Due advance notice",
+			CreatedAt:       timeNowUTC().Truncate(1 * time.Minute),
+			ModifiedAt:      timeNowUTC().Truncate(1 * time.Minute),
+			RemindAt:        timeNowUTC().Add(29 * time.Minute).Truncate(1 * time.Minute),
+			Status:          domain.ReminderStatusPending,
+			AttemptsLeft:    10,
+			Priority:        domain.ReminderPriorityNormal,
+			LeadTimes:       domain.LeadTimes{30 * time.Minute},
+			NextPreNoticeAt: &dueAt,
+		}
+		_, err := s.storage.SaveReminder(context.TODO(), dueReminder, nil)
+		s.Require().NoError(err)
+
+		notDueAt := timeNowUTC().Add(1 * time.Hour)
+		_, err = s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:          chatID,
+			UserID:          userID,
+			Text:            "Advance notice in the future",
+			RemindAt:        timeNowUTC().Add(2 * time.Hour),
+			Status:          domain.ReminderStatusPending,
+			AttemptsLeft:    10,
+			LeadTimes:       domain.LeadTimes{time.Hour},
+			NextPreNoticeAt: &notDueAt,
+		}, nil)
+		s.Require().NoError(err)
+
+		_, err = s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:          chatID,
+			UserID:          userID,
+			Text:            "Reminder time has come already",
+			RemindAt:        timeNowUTC().Add(-1 * time.Minute),
+			Status:          domain.ReminderStatusPending,
+			AttemptsLeft:    10,
+			LeadTimes:       domain.LeadTimes{time.Hour},
+			NextPreNoticeAt: &dueAt,
+		}, nil)
+		s.Require().NoError(err)
+
+		_, err = s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:       chatID,
+			UserID:       userID,
+			Text:         "No advance notices",
+			RemindAt:     timeNowUTC().Add(10 * time.Minute),
+			Status:       domain.ReminderStatusPending,
+			AttemptsLeft: 10,
+		}, nil)
+		s.Require().NoError(err)
+
+		// ACT
+		reminders, err := s.storage.GetDuePreNotices(context.TODO(), 10)
+
+		// ASSERT
+		s.Require().NoError(err)
+		requireEqualRemindersList(s.Require(), []domain.Reminder{dueReminder}, reminders)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_SetReminderNextPreNotice() {
+	s.Run("success", func() {
+		// ARRANGE
+		nextPreNoticeAt := timeNowUTC().Truncate(1 * time.Minute)
+		id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:          1,
+			UserID:          2,
+			Text:            "Meeting",
+			RemindAt:        timeNowUTC().Add(1 * time.Hour),
+			Status:          domain.ReminderStatusPending,
+			LeadTimes:       domain.LeadTimes{time.Hour},
+			NextPreNoticeAt: &nextPreNoticeAt,
+		}, nil)
+		s.Require().NoError(err)
+
+		// ACT
+		s.Require().NoError(s.storage.SetReminderNextPreNotice(context.TODO(), id, nil))
+
+		// ASSERT
+		s.Require().Nil(s.mustGetReminder(id).NextPreNoticeAt)
+	})
+
+	s.Run("error: not found", func() {
+		s.Require().ErrorIs(s.storage.SetReminderNextPreNotice(context.TODO(), 5674, nil), ErrReminderNotFound)
+	})
+}
+
 func requireEqualRemindersList(r *require.Assertions, exp, act []domain.Reminder) {
 	slices.SortFunc(exp, func(a, b domain.Reminder) int {
 		return a.ModifiedAt.Compare(b.ModifiedAt)
@@ -386,8 +951,101 @@ func (s *storageTestSuite) mustGetReminder(id int64) domain.Reminder {
 
 func (s *storageTestSuite) getGetReminder(id int64) (domain.Reminder, error) {
 	var reminder domain.Reminder
-	if err := s.storage.db.Get(&reminder, `SELECT * FROM reminders;`, id); err != nil {
+	if err := s.storage.db.Get(&reminder, `SELECT * FROM reminders WHERE id = $1;`, id); err != nil {
 		return domain.Reminder{}, err
 	}
 	return reminder, nil
 }
+
+func (s *storageTestSuite) Test_storage_MissedReminders() {
+	s.Run("success: miss and reschedule", func() {
+		// ARRANGE
+		now := timeNowUTC().Truncate(time.Minute)
+
+		s.Require().NoError(s.storage.SaveUser(context.TODO(), domain.User{ID: 1, Name: "active", Status: domain.UserStatusActive}))
+		s.Require().NoError(s.storage.SaveUser(context.TODO(), domain.User{ID: 2, Name: "inactive", Status: domain.UserStatusInactive}))
+
+		newReminder := func(userID int64, remindAt time.Time, priority domain.ReminderPriority) int64 {
+			id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+				ChatID:       userID,
+				UserID:       userID,
+				Text:         "Standup",
+				RemindAt:     remindAt,
+				Status:       domain.ReminderStatusPending,
+				AttemptsLeft: 3,
+				Priority:     priority,
+			}, nil)
+			s.Require().NoError(err)
+			return id
+		}
+
+		missedID := newReminder(1, now.Add(-3*time.Hour), domain.ReminderPriorityNormal)
+		criticalID := newReminder(1, now.Add(-3*time.Hour), domain.ReminderPriorityCritical)
+		skippedID := newReminder(1, now.Add(-4*time.Hour), domain.ReminderPriorityNormal)
+		recentID := newReminder(1, now.Add(-time.Minute), domain.ReminderPriorityNormal)
+		inactiveUserID := newReminder(2, now.Add(-3*time.Hour), domain.ReminderPriorityNormal)
+
+		// ACT
+		all, err := s.storage.GetMissedReminders(context.TODO(), 0, now.Add(-time.Hour))
+		s.Require().NoError(err)
+		ofInactiveUser, err := s.storage.GetMissedReminders(context.TODO(), 2, now.Add(-time.Hour))
+		s.Require().NoError(err)
+
+		// ASSERT
+		s.Require().Len(all, 3, "reminders of inactive users are missed when they enable reminders")
+		s.Equal([]int64{skippedID, missedID, criticalID}, []int64{all[0].ID, all[1].ID, all[2].ID})
+		s.Require().Len(ofInactiveUser, 1)
+		s.Equal(inactiveUserID, ofInactiveUser[0].ID)
+
+		// ACT
+		s.Require().NoError(s.storage.MissReminders(context.TODO(), []int64{skippedID}, nil))
+		s.Require().NoError(s.storage.MissReminders(context.TODO(), []int64{missedID, criticalID}, &domain.MissedSummary{
+			Key:     "0123456789abcdef",
+			Message: domain.OutboxMessage{ChatID: 1, Payload: "summary"},
+		}))
+
+		// ASSERT
+		s.Equal(domain.ReminderStatusMissed, s.mustGetReminder(missedID).Status)
+		s.Equal(domain.ReminderStatusMissed, s.mustGetReminder(skippedID).Status)
+		s.Equal(domain.ReminderStatusPending, s.mustGetReminder(recentID).Status)
+
+		messages, err := s.storage.GetDueOutboxMessages(context.TODO(), 10)
+		s.Require().NoError(err)
+		s.Require().Len(messages, 1)
+		s.Equal("summary", messages[0].Payload)
+
+		ids, err := s.storage.GetSummaryReminderIDs(context.TODO(), 1, 1, "0123456789abcdef")
+		s.Require().NoError(err)
+		s.Equal([]int64{missedID, criticalID}, ids)
+
+		otherUserIDs, err := s.storage.GetSummaryReminderIDs(context.TODO(), 2, 2, "0123456789abcdef")
+		s.Require().NoError(err)
+		s.Empty(otherUserIDs)
+
+		// ACT
+		remindAt := now.Add(time.Hour)
+		rescheduled, err := s.storage.RescheduleMissedReminders(context.TODO(), 1, 1, ids, remindAt, nil)
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.EqualValues(2, rescheduled)
+
+		actReminder := s.mustGetReminder(missedID)
+		s.Equal(domain.ReminderStatusPending, actReminder.Status)
+		s.Equal(remindAt, actReminder.RemindAt)
+		s.EqualValues(domain.DefaultAttemptsLeft, actReminder.AttemptsLeft)
+		s.EqualValues(domain.ReminderPriorityCritical.Attempts(), s.mustGetReminder(criticalID).AttemptsLeft)
+		s.Equal(domain.ReminderStatusMissed, s.mustGetReminder(skippedID).Status, "skipped reminder isn't listed in summary")
+
+		ids, err = s.storage.GetSummaryReminderIDs(context.TODO(), 1, 1, "0123456789abcdef")
+		s.Require().NoError(err)
+		s.Empty(ids)
+
+		// ACT
+		rescheduled, err = s.storage.RescheduleMissedReminders(context.TODO(), 1, 1, []int64{missedID, criticalID, inactiveUserID}, remindAt, nil)
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.Zero(rescheduled, "there are no missed reminders anymore")
+	})
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+)
+
+// GetStats - returns bot statistics.
+func (s *Storage) GetStats(ctx context.Context) (domain.Stats, error) {
+	type statusCount struct {
+		Status string `db:"status"`
+		Count  int64  `db:"cnt"`
+	}
+
+	var stats domain.Stats
+
+	var users []statusCount
+	if err := s.db.SelectContext(ctx, &users, `SELECT status, COUNT(*) AS cnt FROM users GROUP BY status;`); err != nil {
+		return domain.Stats{}, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	for _, c := range users {
+		switch domain.UserStatus(c.Status) {
+		case domain.UserStatusActive:
+			stats.UsersActive = c.Count
+		case domain.UserStatusInactive:
+			stats.UsersInactive = c.Count
+		case domain.UserStatusBlocked:
+			stats.UsersBlocked = c.Count
+		}
+	}
+
+	var reminders []statusCount
+	if err := s.db.SelectContext(ctx, &reminders, `SELECT status, COUNT(*) AS cnt FROM reminders GROUP BY status;`); err != nil {
+		return domain.Stats{}, fmt.Errorf("failed to count reminders: %w", err)
+	}
+
+	for _, c := range reminders {
+		switch domain.ReminderStatus(c.Status) {
+		case domain.ReminderStatusPending:
+			stats.RemindersPending = c.Count
+		case domain.ReminderStatusDone:
+			stats.RemindersDone = c.Count
+		case domain.ReminderStatusAttemptsExhausted:
+			stats.RemindersExhausted = c.Count
+		}
+	}
+
+	// the same conditions as in GetPendingReminders
+	const oldestOverdueQuery = `
+		SELECT r.remind_at
+		FROM reminders r
+		JOIN users u ON r.user_id = u.id
+		WHERE r.status = 'pending'
+			AND r.remind_at < $1
+			AND r.attempts_left > 0
+			AND u.status = 'active'
+		ORDER BY r.remind_at
+		LIMIT 1;`
+
+	if err := s.db.GetContext(ctx, &stats.OldestOverdueRemindAt, oldestOverdueQuery, timeNowUTC()); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return domain.Stats{}, fmt.Errorf("failed to get oldest overdue reminder: %w", err)
+	}
+
+	logging.Printf(ctx, "[DEBUG] got stats %+v", stats)
+
+	return stats, nil
+}
+
+// GetDBStats - returns size of the database and number of rows in every table.
+func (s *Storage) GetDBStats(ctx context.Context) (domain.DBStats, error) {
+	var stats domain.DBStats
+
+	const sizeQuery = `SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size();`
+	if err := s.db.GetContext(ctx, &stats.SizeBytes, sizeQuery); err != nil {
+		return domain.DBStats{}, fmt.Errorf("failed to get database size: %w", err)
+	}
+
+	var tables []string
+	const tablesQuery = `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name;`
+	if err := s.db.SelectContext(ctx, &tables, tablesQuery); err != nil {
+		return domain.DBStats{}, fmt.Errorf("failed to get tables: %w", err)
+	}
+
+	for _, table := range tables {
+		size := domain.TableSize{Name: table}
+		// table names come from sqlite_master, so they are safe to be put into query
+		if err := s.db.GetContext(ctx, &size.Rows, fmt.Sprintf(`SELECT COUNT(*) FROM "%s";`, table)); err != nil {
+			return domain.DBStats{}, fmt.Errorf("failed to count rows of %s: %w", table, err)
+		}
+		stats.Tables = append(stats.Tables, size)
+	}
+
+	logging.Printf(ctx, "[DEBUG] got database stats %+v", stats)
+
+	return stats, nil
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+)
+
+func (s *storageTestSuite) Test_storage_GetStats() {
+	s.Run("success: empty database", func() {
+		stats, err := s.storage.GetStats(context.TODO())
+		s.Require().NoError(err)
+		s.Equal(domain.Stats{}, stats)
+	})
+
+	s.Run("success", func() {
+		// ARRANGE
+		for i, status := range []domain.UserStatus{domain.UserStatusActive, domain.UserStatusActive, domain.UserStatusInactive, domain.UserStatusBlocked} {
+			s.Require().NoError(s.storage.SaveUser(context.TODO(), domain.User{ID: int64(i + 1), Name: "Angelique Henke", Status: status}))
+		}
+
+		oldestOverdue := timeNowUTC().Add(-1 * time.Hour).Truncate(1 * time.Minute)
+		for _, r := range []domain.Reminder{
+			{UserID: 1, Status: domain.ReminderStatusPending, RemindAt: oldestOverdue, AttemptsLeft: 3},
+			{UserID: 1, Status: domain.ReminderStatusPending, RemindAt: timeNowUTC().Add(1 * time.Hour), AttemptsLeft: 3},
+			// user is inactive, reminder is not overdue for notifier
+			{UserID: 3, Status: domain.ReminderStatusPending, RemindAt: oldestOverdue.Add(-1 * time.Hour), AttemptsLeft: 3},
+			{UserID: 2, Status: domain.ReminderStatusDone, RemindAt: oldestOverdue.Add(-1 * time.Hour), AttemptsLeft: 3},
+			{UserID: 2, Status: domain.ReminderStatusAttemptsExhausted, RemindAt: oldestOverdue.Add(-1 * time.Hour)},
+		} {
+			r.Text = "Wisdom bankruptcy controls smart."
+			_, err := s.storage.SaveReminder(context.TODO(), r, nil)
+			s.Require().NoError(err)
+		}
+
+		// ACT
+		stats, err := s.storage.GetStats(context.TODO())
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.Equal(domain.Stats{
+			UsersActive:           2,
+			UsersInactive:         1,
+			UsersBlocked:          1,
+			RemindersPending:      3,
+			RemindersDone:         1,
+			RemindersExhausted:    1,
+			OldestOverdueRemindAt: oldestOverdue,
+		}, stats)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_GetDBStats() {
+	s.Run("success", func() {
+		// ARRANGE
+		s.Require().NoError(s.storage.SaveUser(context.TODO(), domain.User{ID: 1, Name: "Angelique Henke", Status: domain.UserStatusActive}))
+		for range 2 {
+			_, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{UserID: 1, Text: "Wisdom bankruptcy controls smart.", Status: domain.ReminderStatusPending}, nil)
+			s.Require().NoError(err)
+		}
+
+		// ACT
+		stats, err := s.storage.GetDBStats(context.TODO())
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.Positive(stats.SizeBytes)
+
+		rows := make(map[string]int64, len(stats.Tables))
+		for _, t := range stats.Tables {
+			rows[t.Name] = t.Rows
+		}
+		s.EqualValues(1, rows["users"])
+		s.EqualValues(2, rows["reminders"])
+		s.EqualValues(2, rows["reminder_events"], "creation of reminders is recorded")
+		s.Contains(rows, "goose_db_version")
+		s.NotContains(rows, "sqlite_sequence", "internal tables are skipped")
+	})
+}
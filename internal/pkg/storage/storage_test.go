@@ -53,6 +53,18 @@ func (s *storageTestSuite) TearDownSubTest() {
 		DELETE FROM reminders;
 		DELETE FROM users;
 		DELETE FROM bot_states;
+		DELETE FROM invite_codes;
+		DELETE FROM checklist_items;
+		DELETE FROM user_settings;
+		DELETE FROM update_offset;
+		DELETE FROM processed_updates;
+		DELETE FROM outbox;
+		DELETE FROM reminder_events;
+		DELETE FROM api_tokens;
+		DELETE FROM web_sessions;
+		DELETE FROM webhooks;
+		DELETE FROM webhook_deliveries;
+		DELETE FROM maintenance;
 	`); err != nil {
 		s.FailNow(err.Error())
 	}
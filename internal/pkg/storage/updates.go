@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+)
+
+// GetUpdateOffset - returns ID of the last Telegram update, which was processed along with all updates before it.
+// Returns 0 if no updates were processed.
+func (s *Storage) GetUpdateOffset(ctx context.Context) (int, error) {
+	const query = `SELECT update_id FROM update_offset WHERE id = 1;`
+
+	var updateID int
+	if err := s.db.GetContext(ctx, &updateID, query); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return 0, nil
+		default:
+			return 0, fmt.Errorf("failed to get update offset: %w", err)
+		}
+	}
+
+	return updateID, nil
+}
+
+// SaveUpdateOffset - saves ID of the last Telegram update, which was processed along with all updates before it.
+// Telegram never sends such updates again, so their idempotency records are deleted.
+func (s *Storage) SaveUpdateOffset(ctx context.Context, updateID int) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint:errcheck // rollback after commit is no-op
+
+	const query = `INSERT INTO update_offset(
+            id
+            , update_id
+            , modified_at
+	) VALUES (1, $1, $2)
+	ON CONFLICT DO UPDATE SET
+		update_id = MAX(update_id, $1)
+		, modified_at = $2;`
+
+	if _, err = tx.ExecContext(ctx, query, updateID, timeNowUTC()); err != nil {
+		return fmt.Errorf("failed to save update offset %d: %w", updateID, err)
+	}
+
+	const deleteQuery = `DELETE FROM processed_updates WHERE update_id <= $1;`
+
+	if _, err = tx.ExecContext(ctx, deleteQuery, updateID); err != nil {
+		return fmt.Errorf("failed to delete processed updates up to %d: %w", updateID, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	logging.Printf(ctx, "[DEBUG] saved update offset %d", updateID)
+
+	return nil
+}
+
+// MarkUpdateProcessed - saves idempotency record of Telegram update.
+// Returns false if the update has been already processed.
+func (s *Storage) MarkUpdateProcessed(ctx context.Context, updateID int) (bool, error) {
+	const query = `INSERT INTO processed_updates(
+            update_id
+            , created_at
+	) VALUES ($1, $2)
+	ON CONFLICT DO NOTHING;`
+
+	res, err := s.db.ExecContext(ctx, query, updateID, timeNowUTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to mark update %d as processed: %w", updateID, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to mark update %d as processed: %w", updateID, err)
+	}
+
+	return affected == 1, nil
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+)
+
+func (s *storageTestSuite) Test_storage_UpdateOffset() {
+	s.Run("success: offset is not saved", func() {
+		// ACT
+		offset, err := s.storage.GetUpdateOffset(context.TODO())
+
+		// ASSERT
+		s.NoError(err)
+		s.Zero(offset)
+	})
+
+	s.Run("success: offset only grows, processed updates up to offset are deleted", func() {
+		// ARRANGE
+		for _, updateID := range []int{10, 11, 12} {
+			marked, err := s.storage.MarkUpdateProcessed(context.TODO(), updateID)
+			s.NoError(err)
+			s.True(marked)
+		}
+
+		// ACT
+		s.NoError(s.storage.SaveUpdateOffset(context.TODO(), 11))
+		s.NoError(s.storage.SaveUpdateOffset(context.TODO(), 10))
+
+		// ASSERT
+		offset, err := s.storage.GetUpdateOffset(context.TODO())
+		s.NoError(err)
+		s.Equal(11, offset)
+
+		var updateIDs []int
+		s.NoError(s.storage.db.Select(&updateIDs, `SELECT update_id FROM processed_updates;`))
+		s.Equal([]int{12}, updateIDs)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_MarkUpdateProcessed() {
+	s.Run("success: update is marked once", func() {
+		// ACT
+		first, err := s.storage.MarkUpdateProcessed(context.TODO(), 42)
+		s.NoError(err)
+		second, err := s.storage.MarkUpdateProcessed(context.TODO(), 42)
+		s.NoError(err)
+
+		// ASSERT
+		s.True(first)
+		s.False(second)
+	})
+}
@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+)
+
+// GetUserSettings - returns user settings by user id. Returns empty settings, i.e. defaults, if user hasn't set them.
+func (s *Storage) GetUserSettings(ctx context.Context, userID int64) (domain.UserSettings, error) {
+	const query = `
+		SELECT
+			user_id
+			, quick_options
+			, snooze_options
+			, channels
+			, email
+			, email_verified
+			, email_code
+			, webhook_url
+			, webhook_secret
+			, push_topic
+			, created_at
+			, modified_at
+		FROM user_settings
+		WHERE user_id = $1;`
+
+	var settings domain.UserSettings
+	if err := s.db.GetContext(ctx, &settings, query, userID); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return domain.UserSettings{UserID: userID}, nil
+		default:
+			return domain.UserSettings{}, fmt.Errorf("failed to get settings of user %d: %w", userID, err)
+		}
+	}
+
+	logging.Printf(ctx, "[DEBUG] got user settings %s", settings)
+
+	return settings, nil
+}
+
+// SaveUserSettings - saves user settings, msgs are enqueued to outbox in the same transaction.
+func (s *Storage) SaveUserSettings(ctx context.Context, settings domain.UserSettings, msgs ...domain.OutboxMessage) error {
+	err := s.inTx(ctx, func(tx *sqlx.Tx) error {
+		if err := upsertUserSettings(ctx, tx, settings); err != nil {
+			return err
+		}
+
+		return insertOutboxMessages(ctx, tx, msgs)
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.Printf(ctx, "[INFO] saved user settings %s", settings)
+
+	return nil
+}
+
+// SaveEmailVerification - saves user settings with code of email verification and enqueues email with the code
+// in one transaction, so the code isn't saved without being sent. msgs, e.g. bot response, are enqueued after the email.
+func (s *Storage) SaveEmailVerification(ctx context.Context, settings domain.UserSettings, msg domain.OutboxMessage, msgs ...domain.OutboxMessage) error {
+	err := s.inTx(ctx, func(tx *sqlx.Tx) error {
+		if err := upsertUserSettings(ctx, tx, settings); err != nil {
+			return err
+		}
+
+		if err := insertOutboxMessage(ctx, tx, msg); err != nil {
+			return err
+		}
+
+		return insertOutboxMessages(ctx, tx, msgs)
+	})
+	if err != nil {
+		return err
+	}
+
+	logging.Printf(ctx, "[INFO] saved user settings %s, enqueued email verification code", settings)
+
+	return nil
+}
+
+func upsertUserSettings(ctx context.Context, db sqlx.ExecerContext, settings domain.UserSettings) error {
+	now := timeNowUTC()
+	if settings.CreatedAt.IsZero() {
+		settings.CreatedAt = now
+	}
+	settings.ModifiedAt = now
+
+	const query = `INSERT INTO user_settings(
+            user_id
+            , quick_options
+            , snooze_options
+            , channels
+            , email
+            , email_verified
+            , email_code
+            , webhook_url
+            , webhook_secret
+            , push_topic
+            , created_at
+            , modified_at
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	ON CONFLICT DO UPDATE SET
+		quick_options = $2
+		, snooze_options = $3
+		, channels = $4
+		, email = $5
+		, email_verified = $6
+		, email_code = $7
+		, webhook_url = $8
+		, webhook_secret = $9
+		, push_topic = $10
+		, modified_at = $12;`
+
+	if _, err := db.ExecContext(ctx, query,
+		settings.UserID,
+		settings.QuickOptions,
+		settings.SnoozeOptions,
+		settings.Channels,
+		settings.Email,
+		settings.EmailVerified,
+		settings.EmailCode,
+		settings.WebhookURL,
+		settings.WebhookSecret,
+		settings.PushTopic,
+		settings.CreatedAt,
+		settings.ModifiedAt,
+	); err != nil {
+		return fmt.Errorf("failed to save user settings %s: %w", settings, err)
+	}
+
+	return nil
+}
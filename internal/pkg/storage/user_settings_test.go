@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+)
+
+func (s *storageTestSuite) Test_storage_GetUserSettings() {
+	s.Run("success: settings are not saved", func() {
+		// ACT
+		settings, err := s.storage.GetUserSettings(context.TODO(), 42)
+
+		// ASSERT
+		s.NoError(err)
+		s.Equal(domain.UserSettings{UserID: 42}, settings)
+		s.Equal(domain.DefaultQuickOptions, settings.ReminderQuickOptions())
+	})
+}
+
+func (s *storageTestSuite) Test_storage_SaveUserSettings() {
+	s.Run("success: insert and update", func() {
+		// ARRANGE
+		s.NoError(s.storage.SaveUser(context.TODO(), domain.User{ID: 42, Name: "foo", Status: domain.UserStatusActive}))
+
+		// ACT
+		s.NoError(s.storage.SaveUserSettings(context.TODO(), domain.UserSettings{
+			UserID:       42,
+			QuickOptions: domain.QuickOptions{"11:30", domain.QuickOptionTomorrowMorning},
+		}))
+
+		// ASSERT
+		settings, err := s.storage.GetUserSettings(context.TODO(), 42)
+		s.NoError(err)
+		s.Equal(domain.QuickOptions{"11:30", domain.QuickOptionTomorrowMorning}, settings.QuickOptions)
+		s.Empty(settings.SnoozeOptions)
+		s.NotZero(settings.CreatedAt)
+		s.NotZero(settings.ModifiedAt)
+
+		// ACT
+		settings.SnoozeOptions = domain.QuickOptions{"15m", "1w"}
+		s.NoError(s.storage.SaveUserSettings(context.TODO(), settings))
+
+		// ASSERT
+		updated, err := s.storage.GetUserSettings(context.TODO(), 42)
+		s.NoError(err)
+		s.Equal(domain.QuickOptions{"11:30", domain.QuickOptionTomorrowMorning}, updated.QuickOptions)
+		s.Equal(domain.QuickOptions{"15m", "1w"}, updated.SnoozeOptions)
+		s.Equal(settings.CreatedAt, updated.CreatedAt)
+	})
+
+	s.Run("success: channels and their addresses", func() {
+		// ARRANGE
+		s.NoError(s.storage.SaveUser(context.TODO(), domain.User{ID: 42, Name: "foo", Status: domain.UserStatusActive}))
+
+		// ACT
+		s.NoError(s.storage.SaveUserSettings(context.TODO(), domain.UserSettings{
+			UserID:        42,
+			Channels:      domain.Channels{domain.ChannelTelegram, domain.ChannelEmail},
+			Email:         "user@example.com",
+			EmailVerified: true,
+			WebhookURL:    "https://example.com/hook",
+			WebhookSecret: "whsec_test",
+			PushTopic:     "reminders",
+		}))
+
+		// ASSERT
+		settings, err := s.storage.GetUserSettings(context.TODO(), 42)
+		s.NoError(err)
+		s.Equal(domain.Channels{domain.ChannelTelegram, domain.ChannelEmail}, settings.Channels)
+		s.Equal("user@example.com", settings.Email)
+		s.True(settings.EmailVerified)
+		s.Equal("https://example.com/hook", settings.WebhookURL)
+		s.Equal("whsec_test", settings.WebhookSecret)
+		s.Equal("reminders", settings.PushTopic)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_SaveEmailVerification() {
+	s.Run("success: code is saved and email is enqueued", func() {
+		// ARRANGE
+		settings := domain.UserSettings{UserID: 42, Email: "user@example.com", EmailCode: "123456"}
+		msg := domain.OutboxMessage{ChatID: 42, Channel: domain.ChannelEmail, Payload: `{"to":"user@example.com"}`, Secret: true}
+
+		// ACT
+		s.Require().NoError(s.storage.SaveEmailVerification(context.TODO(), settings, msg))
+
+		// ASSERT
+		act, err := s.storage.GetUserSettings(context.TODO(), 42)
+		s.Require().NoError(err)
+		s.Equal("123456", act.EmailCode)
+		s.False(act.EmailVerified)
+
+		messages, err := s.storage.GetDueOutboxMessages(context.TODO(), 10)
+		s.Require().NoError(err)
+		s.Require().Len(messages, 1)
+		s.Equal(domain.ChannelEmail, messages[0].Channel)
+		s.Equal(msg.Payload, messages[0].Payload)
+		s.True(messages[0].Secret)
+	})
+}
@@ -2,11 +2,13 @@ package storage
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 
-	log "github.com/go-pkgz/lgr"
+	"github.com/jmoiron/sqlx"
 	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
 )
 
 var (
@@ -16,8 +18,9 @@ var (
 	ErrUserNotFound = errors.New("user is not found")
 )
 
-// SaveUser - saves user.
-func (s *Storage) SaveUser(ctx context.Context, user domain.User) error {
+// SaveUser - saves user. If ctx has invite code (see [domain.ContextWithInviteCode]) the code is used by the user
+// in the same transaction, so the code isn't spent if user isn't saved. msgs are enqueued to outbox in the same transaction.
+func (s *Storage) SaveUser(ctx context.Context, user domain.User, msgs ...domain.OutboxMessage) error {
 	now := timeNowUTC()
 	if user.CreatedAt.IsZero() {
 		user.CreatedAt = now
@@ -34,34 +37,159 @@ func (s *Storage) SaveUser(ctx context.Context, user domain.User) error {
             , modified_at      
 	) VALUES ($1, $2, $3, $4, $5)`
 
-	if _, err := s.db.ExecContext(ctx, query, user.ID, user.Name, user.Status, user.CreatedAt, user.ModifiedAt); err != nil {
-		switch {
-		case isAlreadyExistsError(err):
-			return fmt.Errorf("failed to save user %s: %w", user, ErrUserAlreadyExists)
-		default:
-			return fmt.Errorf("failed to save user %s: %w", user, err)
+	err := s.inTx(ctx, func(tx *sqlx.Tx) error {
+		if code, ok := domain.InviteCodeFromContext(ctx); ok {
+			if err := useInviteCode(ctx, tx, code, user.ID); err != nil {
+				return fmt.Errorf("failed to save user %s: %w", user, err)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, query, user.ID, user.Name, user.Status, user.CreatedAt, user.ModifiedAt); err != nil {
+			switch {
+			case isAlreadyExistsError(err):
+				return fmt.Errorf("failed to save user %s: %w", user, ErrUserAlreadyExists)
+			default:
+				return fmt.Errorf("failed to save user %s: %w", user, err)
+			}
 		}
+
+		return insertOutboxMessages(ctx, tx, msgs)
+	})
+	if err != nil {
+		return err
 	}
 
-	log.Printf("[INFO] saved new user %d", user.ID)
+	logging.Printf(ctx, "[INFO] saved new user %d", user.ID)
 
 	return nil
 }
 
-// SetUserStatus - set's user status by user id.
-func (s *Storage) SetUserStatus(ctx context.Context, id int64, status domain.UserStatus) error {
-	const query = `UPDATE users SET status = $1, modified_at = $2 WHERE id = $3;`
+// SetUserStatus - set's user status by user id. API tokens and web sessions of blocked user are removed,
+// so user loses access to API and web interface too. msgs are enqueued to outbox in the same transaction.
+func (s *Storage) SetUserStatus(ctx context.Context, id int64, status domain.UserStatus, msgs ...domain.OutboxMessage) error {
+	err := s.inTx(ctx, func(tx *sqlx.Tx) error {
+		const query = `UPDATE users SET status = $1, modified_at = $2 WHERE id = $3;`
+
+		res, err := tx.ExecContext(ctx, query, status, timeNowUTC(), id)
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected, _ := res.RowsAffected(); rowsAffected == 0 {
+			return ErrUserNotFound
+		}
+
+		if status == domain.UserStatusBlocked {
+			if _, err = tx.ExecContext(ctx, `DELETE FROM api_tokens WHERE user_id = $1;`, id); err != nil {
+				return fmt.Errorf("failed to remove api tokens: %w", err)
+			}
 
-	res, err := s.db.ExecContext(ctx, query, status, timeNowUTC(), id)
+			if _, err = tx.ExecContext(ctx, `DELETE FROM web_sessions WHERE user_id = $1;`, id); err != nil {
+				return fmt.Errorf("failed to remove web sessions: %w", err)
+			}
+		}
+
+		return insertOutboxMessages(ctx, tx, msgs)
+	})
 	if err != nil {
 		return fmt.Errorf("failed to set user status to %s: %w", status, err)
 	}
 
-	if rowsAffected, _ := res.RowsAffected(); rowsAffected == 0 {
-		return fmt.Errorf("failed to set user status to %s: %w", status, ErrUserNotFound)
+	logging.Printf(ctx, "[INFO] set user %d status to %s", id, status)
+
+	return nil
+}
+
+// DeactivateUser - deactivates user who blocked the bot: reminders aren't sent to user anymore and pending Telegram
+// messages of private chat with user are dropped. Users blocked by admin stay blocked.
+func (s *Storage) DeactivateUser(ctx context.Context, id int64, reason string) error {
+	return s.inTx(ctx, func(tx *sqlx.Tx) error {
+		const query = `UPDATE users SET status = $1, modified_at = $2 WHERE id = $3 AND status = $4;`
+
+		if _, err := tx.ExecContext(ctx, query, domain.UserStatusInactive, timeNowUTC(), id, domain.UserStatusActive); err != nil {
+			return fmt.Errorf("failed to deactivate user %d: %w", id, err)
+		}
+
+		// user id is a chat id of private chat with user
+		if err := dropOutboxMessages(ctx, tx, id, reason); err != nil {
+			return err
+		}
+
+		logging.Printf(ctx, "[INFO] deactivated user %d: %s", id, reason)
+
+		return nil
+	})
+}
+
+// GetUser - returns user by id.
+func (s *Storage) GetUser(ctx context.Context, id int64) (domain.User, error) {
+	const query = `
+		SELECT
+			id
+			, name
+			, status
+			, created_at
+			, modified_at
+		FROM users
+		WHERE id = $1;`
+
+	var user domain.User
+	if err := s.db.GetContext(ctx, &user, query, id); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return domain.User{}, fmt.Errorf("failed to get user %d: %w", id, ErrUserNotFound)
+		default:
+			return domain.User{}, fmt.Errorf("failed to get user %d: %w", id, err)
+		}
+	}
+
+	logging.Printf(ctx, "[DEBUG] got user %s", user)
+
+	return user, nil
+}
+
+// GetUsers - returns the most recently registered users.
+func (s *Storage) GetUsers(ctx context.Context, limit int64) ([]domain.User, error) {
+	const query = `
+		SELECT
+			id
+			, name
+			, status
+			, created_at
+			, modified_at
+		FROM users
+		ORDER BY created_at DESC, id DESC
+		LIMIT $1;`
+
+	var users []domain.User
+	if err := s.db.SelectContext(ctx, &users, query, limit); err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
 	}
 
-	log.Printf("[INFO] set user %d status to %s", id, status)
+	logging.Printf(ctx, "[DEBUG] got %d users", len(users))
 
-	return nil
+	return users, nil
+}
+
+// GetUsersByStatus - returns all users with status.
+func (s *Storage) GetUsersByStatus(ctx context.Context, status domain.UserStatus) ([]domain.User, error) {
+	const query = `
+		SELECT
+			id
+			, name
+			, status
+			, created_at
+			, modified_at
+		FROM users
+		WHERE status = $1
+		ORDER BY id;`
+
+	var users []domain.User
+	if err := s.db.SelectContext(ctx, &users, query, status); err != nil {
+		return nil, fmt.Errorf("failed to get users with status %s: %w", status, err)
+	}
+
+	logging.Printf(ctx, "[DEBUG] got %d users with status %s", len(users), status)
+
+	return users, nil
 }
@@ -82,11 +82,157 @@ func (s *storageTestSuite) Test_storage_SetUserStatus() {
 		s.Greater(actUser.ModifiedAt, user.ModifiedAt)
 	})
 
+	s.Run("success: tokens and web sessions of blocked user are removed", func() {
+		// ARRANGE
+		const userID int64 = 5749
+		s.mustSaveActiveUsers(userID, 2)
+		s.Require().NoError(s.storage.SaveAPIToken(context.TODO(), domain.APIToken{TokenHash: "api", UserID: userID, ChatID: userID}))
+		s.Require().NoError(s.storage.SaveAPIToken(context.TODO(), domain.APIToken{TokenHash: "other", UserID: 2, ChatID: 2}))
+		s.Require().NoError(s.storage.SaveWebSession(context.TODO(), domain.WebSession{
+			TokenHash: "browser",
+			Kind:      domain.WebSessionKindBrowser,
+			UserID:    userID,
+			ChatID:    userID,
+			ExpiresAt: timeNowUTC().Add(domain.WebSessionTTL),
+		}))
+
+		// ACT
+		s.Require().NoError(s.storage.SetUserStatus(context.TODO(), userID, domain.UserStatusBlocked))
+
+		// ASSERT
+		_, err := s.storage.GetAPIToken(context.TODO(), "api")
+		s.ErrorIs(err, ErrAPITokenNotFound)
+		_, err = s.storage.GetAPIToken(context.TODO(), "other")
+		s.NoError(err, "tokens of other users are kept")
+
+		var count int
+		s.Require().NoError(s.storage.db.Get(&count, `SELECT COUNT(*) FROM api_tokens WHERE user_id = $1;`, userID))
+		s.Zero(count)
+		s.Require().NoError(s.storage.db.Get(&count, `SELECT COUNT(*) FROM web_sessions WHERE user_id = $1;`, userID))
+		s.Zero(count)
+	})
+
 	s.Run("success: user does not exist", func() {
 		s.ErrorIs(s.storage.SetUserStatus(context.TODO(), 10, domain.UserStatusInactive), ErrUserNotFound)
 	})
 }
 
+func (s *storageTestSuite) Test_storage_DeactivateUser() {
+	s.Run("success: active user is deactivated, pending messages are dropped", func() {
+		// ARRANGE
+		const userID int64 = 5750
+		s.Require().NoError(s.storage.SaveUser(context.TODO(), domain.User{ID: userID, Name: "Angelique Henke", Status: domain.UserStatusActive}))
+		s.Require().NoError(s.storage.EnqueueOutboxMessage(context.TODO(), domain.OutboxMessage{ChatID: userID, Payload: "foo"}))
+
+		// ACT
+		s.Require().NoError(s.storage.DeactivateUser(context.TODO(), userID, "bot is blocked by user"))
+
+		// ASSERT
+		actUser, err := s.storage.GetUser(context.TODO(), userID)
+		s.Require().NoError(err)
+		s.Equal(domain.UserStatusInactive, actUser.Status)
+
+		messages, err := s.storage.GetDueOutboxMessages(context.TODO(), 10)
+		s.Require().NoError(err)
+		s.Empty(messages)
+	})
+
+	s.Run("success: messages of other channels are kept", func() {
+		// ARRANGE
+		const userID int64 = 5751
+		s.Require().NoError(s.storage.SaveUser(context.TODO(), domain.User{ID: userID, Name: "Nova Block", Status: domain.UserStatusActive}))
+		for _, channel := range domain.AllChannels {
+			s.Require().NoError(s.storage.EnqueueOutboxMessage(context.TODO(), domain.OutboxMessage{ChatID: userID, Channel: channel, Payload: "foo"}))
+		}
+
+		// ACT
+		s.Require().NoError(s.storage.DeactivateUser(context.TODO(), userID, "bot is blocked by user"))
+
+		// ASSERT
+		var messages []domain.OutboxMessage
+		s.Require().NoError(s.storage.db.Select(&messages, `SELECT * FROM outbox WHERE chat_id = $1 ORDER BY id;`, userID))
+		statuses := map[domain.Channel]domain.OutboxStatus{}
+		for _, msg := range messages {
+			statuses[msg.Channel] = msg.Status
+		}
+		s.Equal(map[domain.Channel]domain.OutboxStatus{
+			domain.ChannelTelegram: domain.OutboxStatusDead,
+			domain.ChannelEmail:    domain.OutboxStatusPending,
+			domain.ChannelWebhook:  domain.OutboxStatusPending,
+			domain.ChannelPush:     domain.OutboxStatusPending,
+		}, statuses)
+	})
+
+	s.Run("success: user blocked by admin stays blocked", func() {
+		// ARRANGE
+		const userID int64 = 5751
+		s.Require().NoError(s.storage.SaveUser(context.TODO(), domain.User{ID: userID, Name: "Angelique Henke", Status: domain.UserStatusBlocked}))
+
+		// ACT
+		s.Require().NoError(s.storage.DeactivateUser(context.TODO(), userID, "bot is blocked by user"))
+
+		// ASSERT
+		actUser, err := s.storage.GetUser(context.TODO(), userID)
+		s.Require().NoError(err)
+		s.Equal(domain.UserStatusBlocked, actUser.Status)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_GetUser() {
+	s.Run("success: user exists", func() {
+		// ARRANGE
+		user := domain.User{
+			ID:         5749,
+			Name:       "Angelique Henke",
+			Status:     domain.UserStatusActive,
+			CreatedAt:  timeNowUTC(),
+			ModifiedAt: timeNowUTC(),
+		}
+		s.NoError(s.storage.SaveUser(context.TODO(), user))
+
+		// ACT
+		actUser, err := s.storage.GetUser(context.TODO(), user.ID)
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.Equal(user, actUser)
+	})
+
+	s.Run("error: user does not exist", func() {
+		_, err := s.storage.GetUser(context.TODO(), 10)
+		s.ErrorIs(err, ErrUserNotFound)
+	})
+}
+
+func (s *storageTestSuite) Test_storage_GetUsers() {
+	s.Run("success", func() {
+		// ARRANGE
+		for i, status := range []domain.UserStatus{domain.UserStatusActive, domain.UserStatusBlocked, domain.UserStatusActive} {
+			s.Require().NoError(s.storage.SaveUser(context.TODO(), domain.User{
+				ID:        int64(i + 1),
+				Name:      "Angelique Henke",
+				Status:    status,
+				CreatedAt: timeNowUTC().Add(time.Duration(i) * time.Minute),
+			}))
+		}
+
+		// ACT
+		users, err := s.storage.GetUsers(context.TODO(), 2)
+		s.Require().NoError(err)
+		active, err := s.storage.GetUsersByStatus(context.TODO(), domain.UserStatusActive)
+		s.Require().NoError(err)
+
+		// ASSERT
+		s.Require().Len(users, 2)
+		s.EqualValues(3, users[0].ID)
+		s.EqualValues(2, users[1].ID)
+
+		s.Require().Len(active, 2)
+		s.EqualValues(1, active[0].ID)
+		s.EqualValues(3, active[1].ID)
+	})
+}
+
 func (s *storageTestSuite) mustGetUser(id int64) domain.User {
 	var user domain.User
 	if err := s.storage.db.Get(&user, `SELECT * FROM users;`, id); err != nil {
@@ -94,3 +240,11 @@ func (s *storageTestSuite) mustGetUser(id int64) domain.User {
 	}
 	return user
 }
+
+func (s *storageTestSuite) mustSaveActiveUsers(ids ...int64) {
+	for _, id := range ids {
+		if err := s.storage.SaveUser(context.TODO(), domain.User{ID: id, Status: domain.UserStatusActive}); err != nil {
+			s.FailNow(err.Error())
+		}
+	}
+}
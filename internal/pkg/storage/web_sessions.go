@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+)
+
+// ErrWebSessionNotFound - web session or login link is not found, used or expired.
+var ErrWebSessionNotFound = errors.New("web session is not found")
+
+// SaveWebSession - saves web session, expired sessions and login links of all users are removed.
+// msgs are enqueued to outbox in the same transaction.
+func (s *Storage) SaveWebSession(ctx context.Context, session domain.WebSession, msgs ...domain.OutboxMessage) error {
+	err := s.inTx(ctx, func(tx *sqlx.Tx) error {
+		if err := insertWebSession(ctx, tx, session); err != nil {
+			return err
+		}
+
+		return insertOutboxMessages(ctx, tx, msgs)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save web session %s: %w", session, err)
+	}
+
+	logging.Printf(ctx, "[INFO] saved web session %s", session)
+
+	return nil
+}
+
+// UseWebLogin - exchanges one-time login link to browser session, user and chat of session are taken from the link.
+func (s *Storage) UseWebLogin(ctx context.Context, loginHash string, session domain.WebSession) (domain.WebSession, error) {
+	err := s.inTx(ctx, func(tx *sqlx.Tx) error {
+		login, err := getWebSession(ctx, tx, loginHash, domain.WebSessionKindLogin)
+		if err != nil {
+			return err
+		}
+
+		if _, err = tx.ExecContext(ctx, `DELETE FROM web_sessions WHERE token_hash = $1;`, loginHash); err != nil {
+			return fmt.Errorf("failed to remove login link: %w", err)
+		}
+
+		session.UserID, session.ChatID = login.UserID, login.ChatID
+		return insertWebSession(ctx, tx, session)
+	})
+	if err != nil {
+		return domain.WebSession{}, fmt.Errorf("failed to use web login: %w", err)
+	}
+
+	logging.Printf(ctx, "[INFO] web login is used, saved web session %s", session)
+
+	return session, nil
+}
+
+// GetWebSession - returns not expired browser session of active user by hash of its token.
+func (s *Storage) GetWebSession(ctx context.Context, tokenHash string) (domain.WebSession, error) {
+	session, err := getWebSession(ctx, s.db, tokenHash, domain.WebSessionKindBrowser)
+	if err != nil {
+		return domain.WebSession{}, fmt.Errorf("failed to get web session: %w", err)
+	}
+
+	return session, nil
+}
+
+// RemoveWebSession - removes web session, it's used to log out.
+func (s *Storage) RemoveWebSession(ctx context.Context, tokenHash string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM web_sessions WHERE token_hash = $1;`, tokenHash); err != nil {
+		return fmt.Errorf("failed to remove web session: %w", err)
+	}
+
+	return nil
+}
+
+func insertWebSession(ctx context.Context, tx *sqlx.Tx, session domain.WebSession) error {
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = timeNowUTC()
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM web_sessions WHERE expires_at <= $1;`, timeNowUTC()); err != nil {
+		return fmt.Errorf("failed to remove expired web sessions: %w", err)
+	}
+
+	const query = `INSERT INTO web_sessions(
+            token_hash
+            , kind
+            , user_id
+            , chat_id
+            , expires_at
+            , created_at
+		) VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := tx.ExecContext(ctx, query,
+		session.TokenHash,
+		session.Kind,
+		session.UserID,
+		session.ChatID,
+		session.ExpiresAt,
+		session.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert web session: %w", err)
+	}
+
+	return nil
+}
+
+func getWebSession(ctx context.Context, db sqlx.QueryerContext, tokenHash string, kind domain.WebSessionKind) (domain.WebSession, error) {
+	const query = `
+		SELECT
+		    s.token_hash
+			, s.kind
+			, s.user_id
+			, s.chat_id
+			, s.expires_at
+			, s.created_at
+		FROM web_sessions s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.token_hash = $1 AND s.kind = $2 AND s.expires_at > $3 AND u.status = $4;`
+
+	var session domain.WebSession
+	if err := sqlx.GetContext(ctx, db, &session, query, tokenHash, kind, timeNowUTC(), domain.UserStatusActive); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.WebSession{}, ErrWebSessionNotFound
+		}
+		return domain.WebSession{}, err
+	}
+
+	return session, nil
+}
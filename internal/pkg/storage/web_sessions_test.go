@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+)
+
+func (s *storageTestSuite) Test_storage_WebSession() {
+	s.Run("success: login link is exchanged to browser session once", func() {
+		// ARRANGE
+		s.mustSaveActiveUsers(1)
+		login := domain.WebSession{
+			TokenHash: domain.HashAPIToken("login"),
+			Kind:      domain.WebSessionKindLogin,
+			UserID:    1,
+			ChatID:    2,
+			ExpiresAt: timeNowUTC().Add(domain.WebLoginTTL).Truncate(1 * time.Minute),
+		}
+		s.Require().NoError(s.storage.SaveWebSession(context.TODO(), login))
+
+		session := domain.WebSession{
+			TokenHash: domain.HashAPIToken("browser"),
+			Kind:      domain.WebSessionKindBrowser,
+			ExpiresAt: timeNowUTC().Add(domain.WebSessionTTL).Truncate(1 * time.Minute),
+			CreatedAt: timeNowUTC().Truncate(1 * time.Minute),
+		}
+
+		// ACT
+		act, err := s.storage.UseWebLogin(context.TODO(), login.TokenHash, session)
+
+		// ASSERT
+		s.Require().NoError(err)
+		session.UserID, session.ChatID = 1, 2
+		s.Require().Equal(session, act)
+
+		actSession, err := s.storage.GetWebSession(context.TODO(), session.TokenHash)
+		s.Require().NoError(err)
+		s.Require().Equal(session, actSession)
+
+		_, err = s.storage.UseWebLogin(context.TODO(), login.TokenHash, domain.WebSession{TokenHash: "other"})
+		s.Require().ErrorIs(err, ErrWebSessionNotFound, "login link is used")
+
+		_, err = s.storage.GetWebSession(context.TODO(), login.TokenHash)
+		s.Require().ErrorIs(err, ErrWebSessionNotFound, "login link is not a browser session")
+	})
+
+	s.Run("error: expired sessions are not found and removed", func() {
+		// ARRANGE
+		expired := domain.WebSession{
+			TokenHash: domain.HashAPIToken("expired"),
+			Kind:      domain.WebSessionKindBrowser,
+			UserID:    1,
+			ChatID:    1,
+			ExpiresAt: timeNowUTC().Add(-1 * time.Minute),
+		}
+		s.Require().NoError(s.storage.SaveWebSession(context.TODO(), expired))
+
+		// ACT
+		_, err := s.storage.GetWebSession(context.TODO(), expired.TokenHash)
+
+		// ASSERT
+		s.Require().ErrorIs(err, ErrWebSessionNotFound)
+
+		s.Require().NoError(s.storage.SaveWebSession(context.TODO(), domain.WebSession{
+			TokenHash: domain.HashAPIToken("login"),
+			Kind:      domain.WebSessionKindLogin,
+			ExpiresAt: timeNowUTC().Add(domain.WebLoginTTL),
+		}))
+
+		var count int
+		s.Require().NoError(s.storage.db.Get(&count, `SELECT COUNT(*) FROM web_sessions;`))
+		s.Require().Equal(1, count, "expired session is removed")
+	})
+
+	s.Run("error: session of blocked user is not found", func() {
+		// ARRANGE
+		s.Require().NoError(s.storage.SaveUser(context.TODO(), domain.User{ID: 1, Status: domain.UserStatusBlocked}))
+		session := domain.WebSession{
+			TokenHash: domain.HashAPIToken("browser"),
+			Kind:      domain.WebSessionKindBrowser,
+			UserID:    1,
+			ChatID:    1,
+			ExpiresAt: timeNowUTC().Add(domain.WebSessionTTL),
+		}
+		s.Require().NoError(s.storage.SaveWebSession(context.TODO(), session))
+
+		// ACT
+		_, err := s.storage.GetWebSession(context.TODO(), session.TokenHash)
+
+		// ASSERT
+		s.Require().ErrorIs(err, ErrWebSessionNotFound)
+	})
+
+	s.Run("success: session is removed", func() {
+		// ARRANGE
+		session := domain.WebSession{
+			TokenHash: domain.HashAPIToken("browser"),
+			Kind:      domain.WebSessionKindBrowser,
+			UserID:    1,
+			ChatID:    1,
+			ExpiresAt: timeNowUTC().Add(domain.WebSessionTTL),
+		}
+		s.Require().NoError(s.storage.SaveWebSession(context.TODO(), session))
+
+		// ACT
+		s.Require().NoError(s.storage.RemoveWebSession(context.TODO(), session.TokenHash))
+
+		// ASSERT
+		_, err := s.storage.GetWebSession(context.TODO(), session.TokenHash)
+		s.Require().ErrorIs(err, ErrWebSessionNotFound)
+	})
+}
@@ -0,0 +1,314 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+)
+
+// ErrWebhookNotFound - webhook is not found
+var ErrWebhookNotFound = errors.New("webhook is not found")
+
+// SaveWebhook - registers webhook, returns its id. Messages built by newMsgs from id of webhook, e.g. response
+// with the secret, are enqueued to outbox in the same transaction. newMsgs can be nil.
+func (s *Storage) SaveWebhook(ctx context.Context, hook domain.Webhook, newMsgs func(id int64) ([]domain.OutboxMessage, error)) (int64, error) {
+	now := timeNowUTC()
+
+	const query = `INSERT INTO webhooks(
+            user_id
+            , url
+            , secret
+            , events
+            , created_at
+            , modified_at
+	) VALUES ($1, $2, $3, $4, $5, $6);`
+
+	err := s.inTx(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx, query, hook.UserID, hook.URL, hook.Secret, hook.Events, now, now)
+		if err != nil {
+			return err
+		}
+
+		if hook.ID, err = res.LastInsertId(); err != nil {
+			return err
+		}
+
+		return insertNewOutboxMessages(ctx, tx, hook.ID, newMsgs)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to save webhook %s: %w", hook, err)
+	}
+
+	logging.Printf(ctx, "[INFO] saved webhook %s", hook)
+
+	return hook.ID, nil
+}
+
+// GetWebhook - returns webhook by id.
+func (s *Storage) GetWebhook(ctx context.Context, id int64) (domain.Webhook, error) {
+	const query = `
+		SELECT
+			id
+			, user_id
+			, url
+			, secret
+			, events
+			, created_at
+			, modified_at
+		FROM webhooks
+		WHERE id = $1;`
+
+	var hook domain.Webhook
+	if err := s.db.GetContext(ctx, &hook, query, id); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return domain.Webhook{}, fmt.Errorf("failed to get webhook %d: %w", id, ErrWebhookNotFound)
+		default:
+			return domain.Webhook{}, fmt.Errorf("failed to get webhook %d: %w", id, err)
+		}
+	}
+
+	return hook, nil
+}
+
+// GetWebhooks - returns all webhooks in order they were registered.
+func (s *Storage) GetWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	const query = `
+		SELECT
+			id
+			, user_id
+			, url
+			, secret
+			, events
+			, created_at
+			, modified_at
+		FROM webhooks
+		ORDER BY id;`
+
+	var hooks []domain.Webhook
+	if err := s.db.SelectContext(ctx, &hooks, query); err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	return hooks, nil
+}
+
+// GetUserWebhooks - returns webhooks of user in order they were registered.
+// Webhooks of all users, registered by operator, are not returned.
+func (s *Storage) GetUserWebhooks(ctx context.Context, userID int64) ([]domain.Webhook, error) {
+	const query = `
+		SELECT
+			id
+			, user_id
+			, url
+			, secret
+			, events
+			, created_at
+			, modified_at
+		FROM webhooks
+		WHERE user_id = $1
+		ORDER BY id;`
+
+	var hooks []domain.Webhook
+	if err := s.db.SelectContext(ctx, &hooks, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to get webhooks of user %d: %w", userID, err)
+	}
+
+	return hooks, nil
+}
+
+// RemoveWebhook - removes webhook with its delivery log, pending deliveries are not sent anymore.
+// msgs are enqueued to outbox in the same transaction.
+func (s *Storage) RemoveWebhook(ctx context.Context, id int64, msgs ...domain.OutboxMessage) error {
+	return s.inTx(ctx, func(tx *sqlx.Tx) error {
+		res, err := tx.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1;`, id)
+		if err != nil {
+			return fmt.Errorf("failed to remove webhook %d: %w", id, err)
+		}
+
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			return fmt.Errorf("failed to remove webhook %d: %w", id, ErrWebhookNotFound)
+		}
+
+		if _, err = tx.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE webhook_id = $1;`, id); err != nil {
+			return fmt.Errorf("failed to remove deliveries of webhook %d: %w", id, err)
+		}
+
+		if err = insertOutboxMessages(ctx, tx, msgs); err != nil {
+			return err
+		}
+
+		logging.Printf(ctx, "[INFO] removed webhook %d", id)
+
+		return nil
+	})
+}
+
+// GetWebhookDeliveries - returns delivery log of webhook, the latest deliveries first.
+func (s *Storage) GetWebhookDeliveries(ctx context.Context, webhookID int64, limit int64) ([]domain.WebhookDelivery, error) {
+	const query = `
+		SELECT
+			id
+			, webhook_id
+			, event
+			, payload
+			, status
+			, attempts
+			, next_attempt_at
+			, response_status
+			, last_error
+			, correlation_id
+			, created_at
+			, modified_at
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY id DESC
+		LIMIT $2;`
+
+	var deliveries []domain.WebhookDelivery
+	if err := s.db.SelectContext(ctx, &deliveries, query, webhookID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get deliveries of webhook %d: %w", webhookID, err)
+	}
+
+	return deliveries, nil
+}
+
+// GetDueWebhookDeliveries - returns pending deliveries which are due to be sent, with URL and secret of their webhooks.
+// Events are delivered to every webhook in order they happened, so only the oldest pending delivery of every webhook
+// is returned, even if it's not due yet and the next ones are.
+func (s *Storage) GetDueWebhookDeliveries(ctx context.Context, limit int64) ([]domain.WebhookDelivery, error) {
+	const query = `
+		SELECT
+			d.id
+			, d.webhook_id
+			, d.event
+			, d.payload
+			, d.status
+			, d.attempts
+			, d.next_attempt_at
+			, d.response_status
+			, d.last_error
+			, d.correlation_id
+			, d.created_at
+			, d.modified_at
+			, w.url
+			, w.secret
+		FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = 'pending'
+			AND d.next_attempt_at <= $1
+			AND NOT EXISTS (
+				SELECT 1
+				FROM webhook_deliveries p
+				WHERE p.webhook_id = d.webhook_id AND p.status = 'pending' AND p.id < d.id
+			)
+		ORDER BY d.id
+		LIMIT $2;`
+
+	var deliveries []domain.WebhookDelivery
+	if err := s.db.SelectContext(ctx, &deliveries, query, timeNowUTC(), limit); err != nil {
+		return nil, fmt.Errorf("failed to get due webhook deliveries: %w", err)
+	}
+
+	logging.Printf(ctx, "[DEBUG] got %d due webhook deliveries", len(deliveries))
+
+	return deliveries, nil
+}
+
+// UpdateWebhookDelivery - saves delivery attempt: status, number of attempts, time of the next attempt, response status and error.
+func (s *Storage) UpdateWebhookDelivery(ctx context.Context, d domain.WebhookDelivery) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET status = $1
+			, attempts = $2
+			, next_attempt_at = $3
+			, response_status = $4
+			, last_error = $5
+			, modified_at = $6
+		WHERE id = $7;`
+
+	if _, err := s.db.ExecContext(ctx, query, d.Status, d.Attempts, d.NextAttemptAt, d.ResponseStatus, d.LastError, timeNowUTC(), d.ID); err != nil {
+		return fmt.Errorf("failed to update webhook delivery %s: %w", d, err)
+	}
+
+	return nil
+}
+
+// enqueueWebhookDeliveries enqueues reminder event to webhooks of reminder's user and to webhooks of all users
+// subscribed to it. Deliveries are enqueued in transaction of the event, so they're sent only if the change is saved.
+func enqueueWebhookDeliveries(ctx context.Context, db sqlx.ExtContext, event domain.ReminderEvent) error {
+	webhookEvent, ok := domain.WebhookEventByReminderEvent(event.Type)
+	if !ok {
+		return nil
+	}
+
+	reminder, err := getReminder(ctx, db, event.ReminderID)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue %s webhook deliveries: %w", webhookEvent, err)
+	}
+
+	const hooksQuery = `
+		SELECT
+			id
+			, user_id
+			, url
+			, secret
+			, events
+			, created_at
+			, modified_at
+		FROM webhooks
+		WHERE user_id IN (0, $1)
+		ORDER BY id;`
+
+	var hooks []domain.Webhook
+	if err = sqlx.SelectContext(ctx, db, &hooks, hooksQuery, reminder.UserID); err != nil {
+		return fmt.Errorf("failed to get webhooks of user %d: %w", reminder.UserID, err)
+	}
+
+	var payload []byte
+	for _, hook := range hooks {
+		if !hook.SubscribedTo(webhookEvent) {
+			continue
+		}
+
+		if payload == nil {
+			if payload, err = json.Marshal(domain.NewWebhookPayload(webhookEvent, event, reminder)); err != nil {
+				return fmt.Errorf("failed to marshal %s webhook payload: %w", webhookEvent, err)
+			}
+		}
+
+		const query = `INSERT INTO webhook_deliveries(
+				webhook_id
+				, event
+				, payload
+				, status
+				, next_attempt_at
+				, correlation_id
+				, created_at
+				, modified_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`
+
+		if _, err = db.ExecContext(ctx, query,
+			hook.ID,
+			webhookEvent,
+			string(payload),
+			domain.WebhookDeliveryStatusPending,
+			event.CreatedAt,
+			logging.CorrelationID(ctx),
+			event.CreatedAt,
+			event.CreatedAt,
+		); err != nil {
+			return fmt.Errorf("failed to enqueue %s delivery to webhook %d: %w", webhookEvent, hook.ID, err)
+		}
+
+		logging.Printf(ctx, "[DEBUG] enqueued %s of reminder %d to webhook %d", webhookEvent, reminder.ID, hook.ID)
+	}
+
+	return nil
+}
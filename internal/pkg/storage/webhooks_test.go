@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+)
+
+func (s *storageTestSuite) Test_storage_Webhooks() {
+	s.Run("success: save, get and remove", func() {
+		// ARRANGE
+		userHook := domain.Webhook{UserID: 1, URL: "https://example.com/user", Secret: "whsec_user", Events: domain.WebhookEvents{domain.WebhookEventCompleted}}
+		allHook := domain.Webhook{URL: "https://example.com/all", Secret: "whsec_all"}
+
+		// ACT
+		userHookID, err := s.storage.SaveWebhook(context.TODO(), userHook, nil)
+		s.Require().NoError(err)
+		allHookID, err := s.storage.SaveWebhook(context.TODO(), allHook, nil)
+		s.Require().NoError(err)
+
+		// ASSERT
+		hook, err := s.storage.GetWebhook(context.TODO(), userHookID)
+		s.Require().NoError(err)
+		s.Equal(userHook.URL, hook.URL)
+		s.Equal(userHook.Secret, hook.Secret)
+		s.Equal(userHook.Events, hook.Events)
+		s.NotZero(hook.CreatedAt)
+
+		hooks, err := s.storage.GetUserWebhooks(context.TODO(), 1)
+		s.Require().NoError(err)
+		s.Require().Len(hooks, 1)
+		s.Equal(userHookID, hooks[0].ID)
+
+		hooks, err = s.storage.GetWebhooks(context.TODO())
+		s.Require().NoError(err)
+		s.Require().Len(hooks, 2)
+		s.Equal(allHookID, hooks[1].ID)
+		s.Empty(hooks[1].Events)
+
+		// ACT
+		s.Require().NoError(s.storage.RemoveWebhook(context.TODO(), userHookID))
+
+		// ASSERT
+		_, err = s.storage.GetWebhook(context.TODO(), userHookID)
+		s.ErrorIs(err, ErrWebhookNotFound)
+		s.ErrorIs(s.storage.RemoveWebhook(context.TODO(), userHookID), ErrWebhookNotFound)
+	})
+
+	s.Run("success: lifecycle events are enqueued to subscribed webhooks", func() {
+		// ARRANGE
+		userCtx := domain.ContextWithActor(context.TODO(), domain.Actor{Type: domain.ActorUser, ID: 1})
+
+		completedHookID, err := s.storage.SaveWebhook(context.TODO(), domain.Webhook{
+			UserID: 1, URL: "https://example.com/completed", Secret: "foo", Events: domain.WebhookEvents{domain.WebhookEventCompleted},
+		}, nil)
+		s.Require().NoError(err)
+		allHookID, err := s.storage.SaveWebhook(context.TODO(), domain.Webhook{URL: "https://example.com/all", Secret: "bar"}, nil)
+		s.Require().NoError(err)
+		otherUserHookID, err := s.storage.SaveWebhook(context.TODO(), domain.Webhook{UserID: 2, URL: "https://example.com/other", Secret: "baz"}, nil)
+		s.Require().NoError(err)
+
+		// ACT
+		id, err := s.storage.SaveReminder(userCtx, domain.Reminder{
+			ChatID:       1,
+			UserID:       1,
+			Text:         "Deploy",
+			RemindAt:     timeNowUTC().Add(time.Hour),
+			Status:       domain.ReminderStatusPending,
+			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
+			Checklist:    domain.Checklist{{Text: "build"}},
+		}, nil)
+		s.Require().NoError(err)
+		s.Require().NoError(s.storage.SetReminderStatus(userCtx, id, domain.ReminderStatusDone))
+
+		// ASSERT
+		deliveries, err := s.storage.GetWebhookDeliveries(context.TODO(), allHookID, 10)
+		s.Require().NoError(err)
+		s.Require().Len(deliveries, 2)
+		s.Equal(domain.WebhookEventCompleted, deliveries[0].Event, "the latest delivery goes first")
+		s.Equal(domain.WebhookEventCreated, deliveries[1].Event)
+		s.Equal(domain.WebhookDeliveryStatusPending, deliveries[0].Status)
+
+		var payload domain.WebhookPayload
+		s.Require().NoError(json.Unmarshal([]byte(deliveries[0].Payload), &payload))
+		s.NotZero(payload.EventID)
+		s.Equal(domain.WebhookEventCompleted, payload.Event)
+		s.Equal(domain.ActorUser, payload.Actor)
+		s.EqualValues(1, payload.ActorID)
+		s.Equal(id, payload.Reminder.ID)
+		s.Equal("Deploy", payload.Reminder.Text)
+		s.Equal(domain.ReminderStatusDone, payload.Reminder.Status)
+		s.Equal([]domain.WebhookPayloadChecklistItem{{Text: "build"}}, payload.Reminder.Checklist)
+
+		deliveries, err = s.storage.GetWebhookDeliveries(context.TODO(), completedHookID, 10)
+		s.Require().NoError(err)
+		s.Require().Len(deliveries, 1, "webhook gets only events it's subscribed to")
+		s.Equal(domain.WebhookEventCompleted, deliveries[0].Event)
+
+		deliveries, err = s.storage.GetWebhookDeliveries(context.TODO(), otherUserHookID, 10)
+		s.Require().NoError(err)
+		s.Empty(deliveries, "webhook of other user gets nothing")
+	})
+}
+
+func (s *storageTestSuite) Test_storage_GetDueWebhookDeliveries() {
+	s.Run("success: the oldest pending delivery of every webhook", func() {
+		// ARRANGE
+		firstHookID, err := s.storage.SaveWebhook(context.TODO(), domain.Webhook{UserID: 1, URL: "https://example.com/first", Secret: "foo"}, nil)
+		s.Require().NoError(err)
+		secondHookID, err := s.storage.SaveWebhook(context.TODO(), domain.Webhook{UserID: 1, URL: "https://example.com/second", Secret: "bar"}, nil)
+		s.Require().NoError(err)
+
+		id, err := s.storage.SaveReminder(context.TODO(), domain.Reminder{
+			ChatID:       1,
+			UserID:       1,
+			Text:         "Deploy",
+			RemindAt:     timeNowUTC().Add(time.Hour),
+			Status:       domain.ReminderStatusPending,
+			AttemptsLeft: 3,
+			Priority:     domain.ReminderPriorityNormal,
+		}, nil)
+		s.Require().NoError(err)
+		s.Require().NoError(s.storage.SetReminderStatus(context.TODO(), id, domain.ReminderStatusDone))
+
+		first, err := s.storage.GetWebhookDeliveries(context.TODO(), firstHookID, 10)
+		s.Require().NoError(err)
+		s.Require().Len(first, 2)
+
+		// first delivery of the first webhook failed and waits for retry
+		failed := first[1]
+		failed.Attempts, failed.NextAttemptAt, failed.ResponseStatus, failed.LastError = 1, timeNowUTC().Add(time.Hour), 500, "unexpected response status 500"
+		s.Require().NoError(s.storage.UpdateWebhookDelivery(context.TODO(), failed))
+
+		// ACT
+		due, err := s.storage.GetDueWebhookDeliveries(context.TODO(), 10)
+
+		// ASSERT
+		s.Require().NoError(err)
+		s.Require().Len(due, 1, "the next delivery of the first webhook waits for the failed one")
+		s.Equal(secondHookID, due[0].WebhookID)
+		s.Equal(domain.WebhookEventCreated, due[0].Event)
+		s.Equal("https://example.com/second", due[0].URL)
+		s.Equal("bar", due[0].Secret)
+
+		deliveries, err := s.storage.GetWebhookDeliveries(context.TODO(), firstHookID, 10)
+		s.Require().NoError(err)
+		s.Equal(500, deliveries[1].ResponseStatus)
+		s.Equal("unexpected response status 500", deliveries[1].LastError)
+		s.Equal(1, deliveries[1].Attempts)
+	})
+}
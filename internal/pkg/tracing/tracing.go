@@ -0,0 +1,117 @@
+// Package tracing sets up OpenTelemetry tracing. Spans are exported to OTLP collector or to stdout,
+// when tracing is disabled the global no-op tracer provider is kept and spans cost nothing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName - name of the service in traces, it's also the name of the tracer.
+const ServiceName = "tg-reminder"
+
+// Exporter - where spans are exported to.
+type Exporter string
+
+const (
+	// ExporterNone - tracing is disabled.
+	ExporterNone Exporter = "none"
+	// ExporterStdout - spans are written to stdout as JSON, useful for local debugging.
+	ExporterStdout Exporter = "stdout"
+	// ExporterOTLP - spans are sent to OTLP collector over HTTP, e.g. Jaeger or Grafana Tempo.
+	ExporterOTLP Exporter = "otlp"
+)
+
+// ParseExporter parses tracing exporter. Empty string is parsed as [ExporterNone].
+func ParseExporter(s string) (Exporter, error) {
+	switch Exporter(s) {
+	case "", ExporterNone:
+		return ExporterNone, nil
+	case ExporterStdout, ExporterOTLP:
+		return Exporter(s), nil
+	default:
+		return "", fmt.Errorf("unknown tracing exporter %q", s)
+	}
+}
+
+// Config - tracing configuration.
+type Config struct {
+	Exporter    Exporter
+	Endpoint    string  // URL of OTLP collector, e.g. http://localhost:4318
+	SampleRatio float64 // share of traces which are recorded, from 0 to 1
+	Version     string  // version of the service
+}
+
+// Setup sets up global tracer provider. Returned function flushes spans which aren't exported yet and stops exporting,
+// it must be called on shutdown. Nothing is set up if tracing is disabled.
+func Setup(ctx context.Context, cfg Config) (func(ctx context.Context) error, error) {
+	return setup(ctx, cfg, os.Stdout)
+}
+
+func setup(ctx context.Context, cfg Config, stdout io.Writer) (func(ctx context.Context) error, error) {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(stdout))
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s tracing exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(ServiceName),
+		semconv.ServiceVersion(cfg.Version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return provider.Shutdown, nil
+}
+
+// Start starts span with the global tracer, span is a child of span from ctx if there is one.
+// Returned context contains the new span.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(ServiceName).Start(ctx, name, opts...)
+}
+
+// End ends span, span is marked as failed if err is not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
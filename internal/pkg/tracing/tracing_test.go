@@ -0,0 +1,124 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestParseExporter(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		input  string
+		exp    Exporter
+		expErr string
+	}{
+		{name: "success: empty is none", input: "", exp: ExporterNone},
+		{name: "success: none", input: "none", exp: ExporterNone},
+		{name: "success: stdout", input: "stdout", exp: ExporterStdout},
+		{name: "success: otlp", input: "otlp", exp: ExporterOTLP},
+		{name: "error: unknown exporter", input: "jaeger", expErr: `unknown tracing exporter "jaeger"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			act, err := ParseExporter(tc.input)
+			if tc.expErr != "" {
+				assert.EqualError(t, err, tc.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, act)
+		})
+	}
+}
+
+// nolint:paralleltest // test modifies global tracer provider.
+func TestSetup(t *testing.T) {
+	defaultProvider := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(defaultProvider) })
+
+	t.Run("success: none exporter keeps no-op provider", func(t *testing.T) {
+		shutdown, err := setup(context.TODO(), Config{Exporter: ExporterNone}, &bytes.Buffer{})
+		require.NoError(t, err)
+
+		assert.Same(t, defaultProvider, otel.GetTracerProvider())
+
+		_, span := Start(context.TODO(), "foo")
+		assert.False(t, span.IsRecording())
+		End(span, nil)
+
+		assert.NoError(t, shutdown(context.TODO()))
+	})
+
+	t.Run("success: stdout exporter", func(t *testing.T) {
+		var buf bytes.Buffer
+		shutdown, err := setup(context.TODO(), Config{Exporter: ExporterStdout, SampleRatio: 1, Version: "test"}, &buf)
+		require.NoError(t, err)
+
+		_, span := Start(context.TODO(), "listener.processUpdate")
+		assert.True(t, span.IsRecording())
+		End(span, nil)
+
+		require.NoError(t, shutdown(context.TODO()), "spans are flushed on shutdown")
+		assert.Contains(t, buf.String(), `"Name":"listener.processUpdate"`)
+		assert.Contains(t, buf.String(), `"Value":"tg-reminder"`)
+	})
+
+	t.Run("success: sample ratio 0 records nothing", func(t *testing.T) {
+		var buf bytes.Buffer
+		shutdown, err := setup(context.TODO(), Config{Exporter: ExporterStdout, SampleRatio: 0}, &buf)
+		require.NoError(t, err)
+
+		_, span := Start(context.TODO(), "foo")
+		assert.False(t, span.IsRecording())
+		End(span, nil)
+
+		require.NoError(t, shutdown(context.TODO()))
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("error: unknown exporter", func(t *testing.T) {
+		_, err := setup(context.TODO(), Config{Exporter: "jaeger"}, &bytes.Buffer{})
+		assert.EqualError(t, err, `unknown tracing exporter "jaeger"`)
+	})
+}
+
+// nolint:paralleltest // test modifies global tracer provider.
+func TestStartEnd(t *testing.T) {
+	defaultProvider := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(defaultProvider) })
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	ctx, parent := Start(context.TODO(), "listener.processUpdate")
+	_, child := Start(ctx, "bot.onStartCommand")
+	End(child, errors.New("database is locked"))
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "bot.onStartCommand", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID(), "child span is in the same trace")
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "database is locked", spans[0].Status().Description)
+	require.Len(t, spans[0].Events(), 1, "error is recorded")
+
+	assert.Equal(t, "listener.processUpdate", spans[1].Name())
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
@@ -0,0 +1,352 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+)
+
+// layoutDateTimeLocal - layout of value of datetime-local input, time is entered in Moscow.
+const layoutDateTimeLocal = "2006-01-02T15:04"
+
+// statusAll - value of status filter to show reminders in all statuses.
+const statusAll = "all"
+
+// statusFilter - link to list of reminders with status.
+type statusFilter struct {
+	Value  string
+	Label  string
+	Active bool
+}
+
+type remindersPage struct {
+	page
+	Filters   []statusFilter
+	Reminders []domain.Reminder
+}
+
+type reminderPage struct {
+	page
+	Reminder domain.Reminder
+	Text     string
+	RemindAt string // value of datetime-local input
+	CanDelay bool   // remind time can be changed for pending and exhausted reminders only
+	History  []string
+	Error    string
+	Saved    bool
+}
+
+type settingsPage struct {
+	page
+	QuickOptions         string
+	SnoozeOptions        string
+	DefaultQuickOptions  string
+	DefaultSnoozeOptions string
+	Channels             string
+	Email                string
+	EmailUnverified      bool
+	WebhookURL           string
+	// new webhook secret, it's shown once after webhook URL is saved
+	WebhookSecret string
+	PushTopic     string
+	Error         string
+	Saved         bool
+}
+
+// statusLabel returns russian label of reminder status.
+func statusLabel(status domain.ReminderStatus) string {
+	switch status {
+	case domain.ReminderStatusPending:
+		return "активно"
+	case domain.ReminderStatusDone:
+		return "выполнено"
+	case domain.ReminderStatusAttemptsExhausted:
+		return "попытки закончились"
+	case domain.ReminderStatusChatNotFound:
+		return "чат недоступен"
+	case domain.ReminderStatusMissed:
+		return "пропущено"
+	default:
+		return string(status)
+	}
+}
+
+func (s *Server) listReminders(w http.ResponseWriter, r *http.Request, session domain.WebSession) error {
+	filter := r.URL.Query().Get("status")
+	if filter == "" {
+		filter = string(domain.ReminderStatusPending)
+	}
+
+	var status domain.ReminderStatus
+	if filter != statusAll {
+		var err error
+		if status, err = domain.ParseReminderStatus(filter); err != nil {
+			return newError(http.StatusBadRequest, "Неизвестный статус напоминания %q.", filter)
+		}
+	}
+
+	reminders, err := s.store.GetUserReminders(r.Context(), session.UserID, session.ChatID, status)
+	if err != nil {
+		return err
+	}
+
+	data := remindersPage{page: page{Title: "Напоминания", LoggedIn: true}, Reminders: reminders}
+	for _, f := range []statusFilter{
+		{Value: string(domain.ReminderStatusPending), Label: "Активные"},
+		{Value: string(domain.ReminderStatusDone), Label: "Выполненные"},
+		{Value: statusAll, Label: "Все"},
+	} {
+		f.Active = f.Value == filter
+		data.Filters = append(data.Filters, f)
+	}
+
+	s.render(r.Context(), w, http.StatusOK, "reminders", data)
+
+	return nil
+}
+
+func (s *Server) showReminder(w http.ResponseWriter, r *http.Request, session domain.WebSession) error {
+	reminder, err := s.userReminder(r, session)
+	if err != nil {
+		return err
+	}
+
+	data := reminderPage{
+		Text:     reminder.Text,
+		RemindAt: domain.MoscowTime(reminder.RemindAt).Format(layoutDateTimeLocal),
+		Saved:    r.URL.Query().Has("saved"),
+	}
+	return s.renderReminder(w, r, reminder, http.StatusOK, data)
+}
+
+func (s *Server) updateReminder(w http.ResponseWriter, r *http.Request, session domain.WebSession) error {
+	reminder, err := s.userReminder(r, session)
+	if err != nil {
+		return err
+	}
+
+	if err = parseForm(w, r); err != nil {
+		return err
+	}
+
+	data := reminderPage{Text: strings.TrimSpace(r.PostForm.Get("text")), RemindAt: r.PostForm.Get("remind_at")}
+
+	// the form is shown again with entered values and the reason why they're not saved
+	var remindAt time.Time
+	switch remindAt, err = parseRemindAt(data.RemindAt, reminder.RemindAt); {
+	case err != nil:
+		data.Error = "Не удалось разобрать время напоминания."
+	case data.Text == "":
+		data.Error = "Текст напоминания не может быть пустым."
+	case !remindAt.Equal(reminder.RemindAt) && !canDelay(reminder):
+		data.Error = fmt.Sprintf("Время нельзя изменить, напоминание %s.", statusLabel(reminder.Status))
+	case !remindAt.Equal(reminder.RemindAt) && !remindAt.After(timeNowUTC()):
+		data.Error = "Время напоминания должно быть в будущем."
+	}
+	if data.Error != "" {
+		return s.renderReminder(w, r, reminder, http.StatusUnprocessableEntity, data)
+	}
+
+	if data.Text != reminder.Text {
+		if err = s.store.UpdateReminderText(r.Context(), reminder.ID, data.Text); err != nil {
+			return err
+		}
+	}
+
+	if !remindAt.Equal(reminder.RemindAt) {
+		if err = s.store.DelayReminder(r.Context(), reminder.ID, remindAt); err != nil {
+			return err
+		}
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/reminders/%d?saved", reminder.ID), http.StatusSeeOther)
+
+	return nil
+}
+
+// renderReminder renders page of reminder with its history, data contains values of the form.
+func (s *Server) renderReminder(w http.ResponseWriter, r *http.Request, reminder domain.Reminder, status int, data reminderPage) error {
+	events, err := s.store.GetReminderEvents(r.Context(), reminder.ID)
+	if err != nil {
+		return err
+	}
+
+	data.page = page{Title: "Напоминание", LoggedIn: true}
+	data.Reminder = reminder
+	data.CanDelay = canDelay(reminder)
+	for _, e := range events {
+		data.History = append(data.History, e.Format(false))
+	}
+
+	s.render(r.Context(), w, status, "reminder", data)
+
+	return nil
+}
+
+func (s *Server) showSettings(w http.ResponseWriter, r *http.Request, session domain.WebSession) error {
+	settings, err := s.store.GetUserSettings(r.Context(), session.UserID)
+	if err != nil {
+		return err
+	}
+
+	data := newSettingsPage(r.URL.Query().Has("saved"))
+	data.setSettings(settings)
+
+	s.render(r.Context(), w, http.StatusOK, "settings", data)
+
+	return nil
+}
+
+func (s *Server) updateSettings(w http.ResponseWriter, r *http.Request, session domain.WebSession) error {
+	if err := parseForm(w, r); err != nil {
+		return err
+	}
+
+	settings, err := s.store.GetUserSettings(r.Context(), session.UserID)
+	if err != nil {
+		return err
+	}
+
+	data := newSettingsPage(false)
+	data.QuickOptions = r.PostForm.Get("quick_options")
+	data.SnoozeOptions = r.PostForm.Get("snooze_options")
+	data.Channels = r.PostForm.Get("channels")
+	data.Email = strings.TrimSpace(r.PostForm.Get("email"))
+	data.WebhookURL = strings.TrimSpace(r.PostForm.Get("webhook_url"))
+	data.PushTopic = strings.TrimSpace(r.PostForm.Get("push_topic"))
+
+	// empty options and channels mean defaults, empty address removes it
+	if settings.QuickOptions, err = parseQuickOptions(data.QuickOptions); err != nil {
+		data.Error = fmt.Sprintf("Не удалось разобрать кнопки создания: %s.", err)
+	} else if settings.SnoozeOptions, err = parseQuickOptions(data.SnoozeOptions); err != nil {
+		data.Error = fmt.Sprintf("Не удалось разобрать кнопки откладывания: %s.", err)
+	} else if settings.Channels, err = parseChannels(data.Channels); err != nil {
+		data.Error = fmt.Sprintf("Не удалось разобрать каналы: %s.", err)
+	} else {
+		addresses := []struct {
+			channel domain.Channel
+			address string
+		}{
+			{domain.ChannelEmail, data.Email},
+			{domain.ChannelWebhook, data.WebhookURL},
+			{domain.ChannelPush, data.PushTopic},
+		}
+		for _, a := range addresses {
+			if err = settings.SetChannelAddress(a.channel, a.address); err != nil {
+				data.Error = fmt.Sprintf("Неверный адрес канала %s: %s.", a.channel.Label(), err)
+				break
+			}
+		}
+	}
+	if data.Error != "" {
+		s.render(r.Context(), w, http.StatusUnprocessableEntity, "settings", data)
+		return nil
+	}
+
+	// new webhook URL gets new secret, it's shown on the page rendered instead of redirect
+	newSecret := settings.WebhookURL != "" && settings.WebhookSecret == ""
+	if newSecret {
+		if settings.WebhookSecret, err = domain.NewWebhookSecret(); err != nil {
+			return fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+	}
+
+	if err = s.store.SaveUserSettings(r.Context(), settings); err != nil {
+		return err
+	}
+
+	if newSecret {
+		data = newSettingsPage(true)
+		data.setSettings(settings)
+		data.WebhookSecret = settings.WebhookSecret
+		s.render(r.Context(), w, http.StatusOK, "settings", data)
+		return nil
+	}
+
+	http.Redirect(w, r, "/settings?saved", http.StatusSeeOther)
+
+	return nil
+}
+
+// setSettings fills the form of page with saved settings.
+func (p *settingsPage) setSettings(settings domain.UserSettings) {
+	p.QuickOptions = settings.QuickOptions.String()
+	p.SnoozeOptions = settings.SnoozeOptions.String()
+	p.Channels = settings.Channels.String()
+	p.Email = settings.Email
+	p.EmailUnverified = settings.Email != "" && !settings.EmailVerified
+	p.WebhookURL = settings.WebhookURL
+	p.PushTopic = settings.PushTopic
+}
+
+func newSettingsPage(saved bool) settingsPage {
+	return settingsPage{
+		page:                 page{Title: "Настройки", LoggedIn: true},
+		DefaultQuickOptions:  domain.DefaultQuickOptions.String(),
+		DefaultSnoozeOptions: domain.DefaultSnoozeOptions.String(),
+		Saved:                saved,
+	}
+}
+
+// userReminder returns reminder by id from request path. Reminders of other users and chats are not found.
+func (s *Server) userReminder(r *http.Request, session domain.WebSession) (domain.Reminder, error) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return domain.Reminder{}, newError(http.StatusNotFound, "Напоминание не найдено.")
+	}
+
+	reminder, err := s.store.GetReminder(r.Context(), id)
+	if err != nil {
+		return domain.Reminder{}, err
+	}
+
+	if reminder.UserID != session.UserID || reminder.ChatID != session.ChatID {
+		return domain.Reminder{}, newError(http.StatusNotFound, "Напоминание не найдено.")
+	}
+
+	return reminder, nil
+}
+
+// parseRemindAt parses remind time entered in Moscow. The input has minute precision,
+// so current remind time is kept if the entered one is the same minute or isn't sent.
+func parseRemindAt(s string, current time.Time) (time.Time, error) {
+	if s == "" {
+		return current, nil
+	}
+
+	remindAt, err := time.ParseInLocation(layoutDateTimeLocal, s, domain.MoscowTime(current).Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid remind time %q: %w", s, err)
+	}
+
+	if remindAt.Equal(current.Truncate(time.Minute)) {
+		return current, nil
+	}
+
+	return remindAt.UTC(), nil
+}
+
+// parseQuickOptions parses quick options, empty string means default options.
+func parseQuickOptions(s string) (domain.QuickOptions, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	return domain.ParseQuickOptions(s)
+}
+
+func parseChannels(s string) (domain.Channels, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	return domain.ParseChannels(s)
+}
+
+func canDelay(reminder domain.Reminder) bool {
+	return reminder.Status == domain.ReminderStatusPending || reminder.Status == domain.ReminderStatusAttemptsExhausted
+}
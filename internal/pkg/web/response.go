@@ -0,0 +1,87 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+	"github.com/mezk/tg-reminder/internal/pkg/storage"
+)
+
+// maxFormSize - max size of posted form.
+const maxFormSize = 64 << 10
+
+// pageError - error which is shown to user as is.
+type pageError struct {
+	status int
+	msg    string
+}
+
+func newError(status int, format string, args ...any) error {
+	return &pageError{status: status, msg: fmt.Sprintf(format, args...)}
+}
+
+func (e *pageError) Error() string {
+	return e.msg
+}
+
+// errNotLoggedIn - request has no valid session cookie.
+var errNotLoggedIn = newError(http.StatusUnauthorized, "Чтобы войти, отправьте команду %s боту и откройте ссылку из ответа.", domain.BotCommandWeb)
+
+// page - data of the common layout.
+type page struct {
+	Title    string
+	LoggedIn bool // navigation and logout button are shown
+}
+
+// messagePage - page with a message, e.g. an error.
+type messagePage struct {
+	page
+	Message string
+}
+
+// renderError renders error page. Unexpected errors are logged and are hidden from user.
+func (s *Server) renderError(ctx context.Context, w http.ResponseWriter, err error) {
+	var pageErr *pageError
+	switch {
+	case errors.As(err, &pageErr):
+	case errors.Is(err, storage.ErrReminderNotFound):
+		pageErr = &pageError{status: http.StatusNotFound, msg: "Напоминание не найдено."}
+	default:
+		logging.Printf(ctx, "[ERROR] failed to handle web request: %v", err)
+		pageErr = &pageError{status: http.StatusInternalServerError, msg: "Что-то пошло не так, попробуйте позже."}
+	}
+
+	s.render(ctx, w, pageErr.status, "message", messagePage{page: page{Title: "Ошибка"}, Message: pageErr.msg})
+}
+
+// render renders page with data. Page is rendered to buffer first, so a broken template doesn't send half of page.
+func (s *Server) render(ctx context.Context, w http.ResponseWriter, status int, name string, data any) {
+	var buf bytes.Buffer
+	if err := s.pages[name].Execute(&buf, data); err != nil {
+		logging.Printf(ctx, "[ERROR] failed to render web page %s: %v", name, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+
+	if _, err := buf.WriteTo(w); err != nil {
+		logging.Printf(ctx, "[WARN] failed to write web page %s: %v", name, err)
+	}
+}
+
+// parseForm parses posted form, its size is limited.
+func parseForm(w http.ResponseWriter, r *http.Request) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxFormSize)
+	if err := r.ParseForm(); err != nil {
+		return newError(http.StatusBadRequest, "Не удалось прочитать форму: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,281 @@
+// Package web implements web dashboard to view and edit reminders in a browser.
+// Users log in with one-time links which the bot sends on /web command, a link is exchanged
+// to browser session which gives access to reminders of its user in the chat the link was issued in.
+package web
+
+import (
+	"context"
+	"crypto/rand"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"time"
+
+	log "github.com/go-pkgz/lgr"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+	"github.com/mezk/tg-reminder/internal/pkg/storage"
+	"github.com/mezk/tg-reminder/internal/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var timeNowUTC = func() time.Time {
+	return time.Now().UTC()
+}
+
+//go:embed templates static
+var assets embed.FS
+
+const (
+	// readHeaderTimeout - max time to read request headers, protects from slow clients.
+	readHeaderTimeout = 10 * time.Second
+	// sessionCookie - name of cookie with token of browser session.
+	sessionCookie = "tgr_session"
+)
+
+// Storage - storage of reminders, settings and web sessions.
+type Storage interface {
+	UseWebLogin(ctx context.Context, loginHash string, session domain.WebSession) (domain.WebSession, error)
+	GetWebSession(ctx context.Context, tokenHash string) (domain.WebSession, error)
+	RemoveWebSession(ctx context.Context, tokenHash string) error
+
+	GetUserReminders(ctx context.Context, userID, chatID int64, status domain.ReminderStatus) ([]domain.Reminder, error)
+	GetReminder(ctx context.Context, id int64) (domain.Reminder, error)
+	GetReminderEvents(ctx context.Context, reminderID int64) ([]domain.ReminderEvent, error)
+	UpdateReminderText(ctx context.Context, id int64, text string) error
+	DelayReminder(ctx context.Context, id int64, remindAt time.Time, msgs ...domain.OutboxMessage) error
+
+	GetUserSettings(ctx context.Context, userID int64) (domain.UserSettings, error)
+	SaveUserSettings(ctx context.Context, settings domain.UserSettings, msgs ...domain.OutboxMessage) error
+}
+
+// Config - web dashboard configuration.
+type Config struct {
+	Addr   string // address to listen on, e.g. :8081
+	Secure bool   // session cookie is sent over HTTPS only
+}
+
+// Server - web dashboard server.
+type Server struct {
+	store Storage
+	cfg   Config
+	mux   *http.ServeMux
+	pages map[string]*template.Template
+}
+
+// handlerFunc - handler of request of logged in user, session is the browser session of user.
+type handlerFunc func(w http.ResponseWriter, r *http.Request, session domain.WebSession) error
+
+// New creates new [Server].
+func New(store Storage, cfg Config) *Server {
+	s := &Server{store: store, cfg: cfg, mux: http.NewServeMux(), pages: parsePages()}
+
+	s.handlePublic("GET /login", "web.login", s.login)
+	s.handle("POST /logout", "web.logout", s.logout)
+	s.handle("GET /{$}", "web.listReminders", s.listReminders)
+	s.handle("GET /reminders/{id}", "web.showReminder", s.showReminder)
+	s.handle("POST /reminders/{id}", "web.updateReminder", s.updateReminder)
+	s.handle("GET /settings", "web.showSettings", s.showSettings)
+	s.handle("POST /settings", "web.updateSettings", s.updateSettings)
+
+	static, _ := fs.Sub(assets, "static")
+	s.mux.Handle("GET /static/", http.StripPrefix("/static/", http.FileServerFS(static)))
+
+	return s
+}
+
+// parsePages parses templates of pages, every page is rendered inside of the common layout.
+func parsePages() map[string]*template.Template {
+	funcs := template.FuncMap{
+		"remindAt": func(t time.Time) string { return domain.FormatRemindAt(t, timeNowUTC()) },
+		"status":   statusLabel,
+	}
+
+	pages := make(map[string]*template.Template)
+	for _, name := range []string{"message", "reminders", "reminder", "settings"} {
+		pages[name] = template.Must(template.New("layout.html").Funcs(funcs).ParseFS(assets, "templates/layout.html", "templates/"+name+".html"))
+	}
+
+	return pages
+}
+
+// Handler returns HTTP handler of the dashboard.
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// Run serves dashboard until ctx is cancelled, then waits for requests in progress to finish.
+func (s *Server) Run(ctx context.Context) error {
+	srv := &http.Server{Addr: s.cfg.Addr, Handler: s.mux, ReadHeaderTimeout: readHeaderTimeout}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	log.Printf("[INFO] web server listens on %s", s.cfg.Addr)
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("web server stopped: %w", err)
+	case <-ctx.Done():
+	}
+
+	if err := srv.Shutdown(context.Background()); err != nil {
+		return fmt.Errorf("failed to shutdown web server: %w", err)
+	}
+
+	return nil
+}
+
+// handle registers handler of request of logged in user. Changes of reminders are recorded as made by the user.
+func (s *Server) handle(pattern, spanName string, h handlerFunc) {
+	s.handlePublic(pattern, spanName, func(w http.ResponseWriter, r *http.Request) error {
+		session, err := s.authenticate(r)
+		if err != nil {
+			return err
+		}
+
+		trace.SpanFromContext(r.Context()).SetAttributes(
+			attribute.Int64("telegram.user_id", session.UserID),
+			attribute.Int64("telegram.chat_id", session.ChatID),
+		)
+		ctx := logging.ContextWithUser(r.Context(), session.UserID, session.ChatID)
+		ctx = domain.ContextWithActor(ctx, domain.Actor{Type: domain.ActorUser, ID: session.UserID})
+
+		return h(w, r.WithContext(ctx), session)
+	})
+}
+
+// handlePublic registers handler of request which doesn't require login. Every request gets its own correlation id and span.
+func (s *Server) handlePublic(pattern, spanName string, h func(w http.ResponseWriter, r *http.Request) error) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		var err error
+
+		ctx := logging.ContextWithCorrelationID(r.Context(), logging.NewCorrelationID())
+		ctx, span := tracing.Start(ctx, spanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.URLPath(r.URL.Path),
+				attribute.String("correlation_id", logging.CorrelationID(ctx)),
+			),
+		)
+		defer func() { tracing.End(span, err) }()
+
+		w.Header().Set("X-Correlation-ID", logging.CorrelationID(ctx))
+
+		if err = checkOrigin(r); err == nil {
+			err = h(w, r.WithContext(ctx))
+		}
+
+		if err != nil {
+			s.renderError(ctx, w, err)
+			return
+		}
+
+		logging.Printf(ctx, "[DEBUG] web request %s %s is handled", r.Method, r.URL.Path)
+	})
+}
+
+// checkOrigin rejects forms posted from other sites. Session cookie isn't sent with them anyway,
+// see [http.SameSiteLaxMode], the check protects browsers which ignore SameSite attribute.
+func checkOrigin(r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if r.Method == http.MethodGet || origin == "" {
+		return nil
+	}
+
+	if u, err := url.Parse(origin); err != nil || u.Host != r.Host {
+		return newError(http.StatusForbidden, "Запрос отправлен с другого сайта.")
+	}
+
+	return nil
+}
+
+// authenticate returns browser session by token from cookie of request.
+func (s *Server) authenticate(r *http.Request) (domain.WebSession, error) {
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil || cookie.Value == "" {
+		return domain.WebSession{}, errNotLoggedIn
+	}
+
+	session, err := s.store.GetWebSession(r.Context(), domain.HashAPIToken(cookie.Value))
+	if err != nil {
+		if errors.Is(err, storage.ErrWebSessionNotFound) {
+			return domain.WebSession{}, errNotLoggedIn
+		}
+		return domain.WebSession{}, err
+	}
+
+	return session, nil
+}
+
+// login exchanges one-time login link to browser session and redirects to the list of reminders.
+func (s *Server) login(w http.ResponseWriter, r *http.Request) error {
+	loginToken := r.URL.Query().Get("token")
+	if loginToken == "" {
+		return errNotLoggedIn
+	}
+
+	token, err := newSessionToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate web session token: %w", err)
+	}
+
+	session, err := s.store.UseWebLogin(r.Context(), domain.HashAPIToken(loginToken), domain.WebSession{
+		TokenHash: domain.HashAPIToken(token),
+		Kind:      domain.WebSessionKindBrowser,
+		ExpiresAt: timeNowUTC().Add(domain.WebSessionTTL),
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrWebSessionNotFound) {
+			return newError(http.StatusUnauthorized, "Ссылка для входа уже использована или устарела. Получите новую командой %s в боте.", domain.BotCommandWeb)
+		}
+		return err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		HttpOnly: true,
+		Secure:   s.cfg.Secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+
+	return nil
+}
+
+// logout removes browser session.
+func (s *Server) logout(w http.ResponseWriter, r *http.Request, session domain.WebSession) error {
+	if err := s.store.RemoveWebSession(r.Context(), session.TokenHash); err != nil {
+		return err
+	}
+
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Path: "/", MaxAge: -1, HttpOnly: true, Secure: s.cfg.Secure, SameSite: http.SameSiteLaxMode})
+	s.render(r.Context(), w, http.StatusOK, "message", messagePage{
+		page:    page{Title: "Вы вышли"},
+		Message: "Чтобы войти снова, отправьте команду " + string(domain.BotCommandWeb) + " боту.",
+	})
+
+	return nil
+}
+
+var newSessionToken = func() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
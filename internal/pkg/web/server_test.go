@@ -0,0 +1,536 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nolint:paralleltest // test modifies package level functions timeNowUTC and newSessionToken.
+func TestServer(t *testing.T) {
+	const (
+		sessionToken = "session-token"
+		userID       = 1
+		chatID       = 1
+	)
+
+	var (
+		now      = time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC)
+		remindAt = now.Add(1 * time.Hour)
+		session  = domain.WebSession{
+			TokenHash: domain.HashAPIToken(sessionToken),
+			Kind:      domain.WebSessionKindBrowser,
+			UserID:    userID,
+			ChatID:    chatID,
+			ExpiresAt: now.Add(domain.WebSessionTTL),
+		}
+		reminder = domain.Reminder{
+			ID:           42,
+			ChatID:       chatID,
+			UserID:       userID,
+			Text:         "buy milk",
+			CreatedAt:    now,
+			ModifiedAt:   now,
+			RemindAt:     remindAt,
+			Status:       domain.ReminderStatusPending,
+			AttemptsLeft: domain.DefaultAttemptsLeft,
+			Priority:     domain.ReminderPriorityNormal,
+			Checklist:    domain.Checklist{{ID: 7, ReminderID: 42, Text: "2%", Done: true}},
+		}
+	)
+
+	// getReminder returns reminder of the logged in user by id, reminder 43 belongs to another user
+	getReminder := func(_ context.Context, id int64) (domain.Reminder, error) {
+		switch id {
+		case reminder.ID:
+			return reminder, nil
+		case 43:
+			other := reminder
+			other.ID, other.UserID, other.ChatID = 43, 2, 2
+			return other, nil
+		default:
+			return domain.Reminder{}, storage.ErrReminderNotFound
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		method      string
+		path        string
+		loggedIn    bool
+		origin      string
+		form        url.Values
+		setMocks    func(a *assert.Assertions, store *StorageMock)
+		expStatus   int
+		expLocation string
+		expCookie   string
+		expBody     []string
+	}{
+		{
+			name:      "error: not logged in",
+			method:    http.MethodGet,
+			path:      "/",
+			expStatus: http.StatusUnauthorized,
+			expBody:   []string{"Чтобы войти, отправьте команду /web боту"},
+		},
+		{
+			name:   "success: login",
+			method: http.MethodGet,
+			path:   "/login?token=login-token",
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.UseWebLoginFunc = func(_ context.Context, loginHash string, actSession domain.WebSession) (domain.WebSession, error) {
+					a.Equal(domain.HashAPIToken("login-token"), loginHash)
+					a.Equal(domain.WebSession{
+						TokenHash: domain.HashAPIToken(sessionToken),
+						Kind:      domain.WebSessionKindBrowser,
+						ExpiresAt: now.Add(domain.WebSessionTTL),
+					}, actSession)
+					return session, nil
+				}
+			},
+			expStatus:   http.StatusSeeOther,
+			expLocation: "/",
+			expCookie:   "tgr_session=session-token; Path=/; Expires=Fri, 31 May 2024 10:00:00 GMT; HttpOnly; SameSite=Lax",
+		},
+		{
+			name:   "error: login link is used",
+			method: http.MethodGet,
+			path:   "/login?token=login-token",
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.UseWebLoginFunc = func(context.Context, string, domain.WebSession) (domain.WebSession, error) {
+					return domain.WebSession{}, storage.ErrWebSessionNotFound
+				}
+			},
+			expStatus: http.StatusUnauthorized,
+			expBody:   []string{"Ссылка для входа уже использована или устарела"},
+		},
+		{
+			name:      "error: login without token",
+			method:    http.MethodGet,
+			path:      "/login",
+			expStatus: http.StatusUnauthorized,
+		},
+		{
+			name:     "success: logout",
+			method:   http.MethodPost,
+			path:     "/logout",
+			loggedIn: true,
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.RemoveWebSessionFunc = func(_ context.Context, tokenHash string) error {
+					a.Equal(session.TokenHash, tokenHash)
+					return nil
+				}
+			},
+			expStatus: http.StatusOK,
+			expCookie: "tgr_session=; Path=/; Max-Age=0; HttpOnly; SameSite=Lax",
+			expBody:   []string{"Вы вышли"},
+		},
+		{
+			name:     "success: list pending reminders",
+			method:   http.MethodGet,
+			path:     "/",
+			loggedIn: true,
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetUserRemindersFunc = func(_ context.Context, actUserID, actChatID int64, status domain.ReminderStatus) ([]domain.Reminder, error) {
+					a.EqualValues(userID, actUserID)
+					a.EqualValues(chatID, actChatID)
+					a.Equal(domain.ReminderStatusPending, status)
+					return []domain.Reminder{reminder}, nil
+				}
+			},
+			expStatus: http.StatusOK,
+			expBody:   []string{`<a href="/reminders/42">buy milk</a>`, "через 1 час", "<strong>Активные</strong>", "активно"},
+		},
+		{
+			name:     "success: list all reminders",
+			method:   http.MethodGet,
+			path:     "/?status=all",
+			loggedIn: true,
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetUserRemindersFunc = func(_ context.Context, _, _ int64, status domain.ReminderStatus) ([]domain.Reminder, error) {
+					a.Empty(status)
+					return nil, nil
+				}
+			},
+			expStatus: http.StatusOK,
+			expBody:   []string{"<strong>Все</strong>", "Напоминаний нет."},
+		},
+		{
+			name:      "error: list reminders, unknown status",
+			method:    http.MethodGet,
+			path:      "/?status=deleted",
+			loggedIn:  true,
+			expStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "error: list reminders, failed to get reminders",
+			method:   http.MethodGet,
+			path:     "/",
+			loggedIn: true,
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetUserRemindersFunc = func(context.Context, int64, int64, domain.ReminderStatus) ([]domain.Reminder, error) {
+					return nil, errors.New("db error")
+				}
+			},
+			expStatus: http.StatusInternalServerError,
+			expBody:   []string{"Что-то пошло не так"},
+		},
+		{
+			name:     "success: show reminder with history",
+			method:   http.MethodGet,
+			path:     "/reminders/42",
+			loggedIn: true,
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+				store.GetReminderEventsFunc = func(_ context.Context, reminderID int64) ([]domain.ReminderEvent, error) {
+					a.EqualValues(42, reminderID)
+					return []domain.ReminderEvent{{Type: domain.ReminderEventCreated, ActorType: domain.ActorUser, ActorID: userID, RemindAtTo: &remindAt, CreatedAt: now}}, nil
+				}
+			},
+			expStatus: http.StatusOK,
+			expBody: []string{
+				`<textarea id="text" name="text" rows="4" required>buy milk</textarea>`,
+				`value="2024-05-01T14:00">`,
+				"☑ 2%",
+				"<li>1 мая 13:00 — создано на 1 мая 14:00 (пользователь)</li>",
+			},
+		},
+		{
+			name:     "error: show reminder of another user",
+			method:   http.MethodGet,
+			path:     "/reminders/43",
+			loggedIn: true,
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+			},
+			expStatus: http.StatusNotFound,
+			expBody:   []string{"Напоминание не найдено."},
+		},
+		{
+			name:     "error: show reminder, not found",
+			method:   http.MethodGet,
+			path:     "/reminders/1",
+			loggedIn: true,
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+			},
+			expStatus: http.StatusNotFound,
+		},
+		{
+			name:     "success: update reminder text and time",
+			method:   http.MethodPost,
+			path:     "/reminders/42",
+			loggedIn: true,
+			form:     url.Values{"text": {" buy bread "}, "remind_at": {"2024-05-02T09:30"}},
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+				store.UpdateReminderTextFunc = func(ctx context.Context, id int64, text string) error {
+					a.EqualValues(42, id)
+					a.Equal("buy bread", text)
+					a.Equal(domain.Actor{Type: domain.ActorUser, ID: userID}, domain.ActorFromContext(ctx))
+					return nil
+				}
+				store.DelayReminderFunc = func(_ context.Context, id int64, actRemindAt time.Time, _ ...domain.OutboxMessage) error {
+					a.EqualValues(42, id)
+					a.Equal(time.Date(2024, time.May, 2, 6, 30, 0, 0, time.UTC), actRemindAt)
+					return nil
+				}
+			},
+			expStatus:   http.StatusSeeOther,
+			expLocation: "/reminders/42?saved",
+		},
+		{
+			name:     "success: update reminder text only",
+			method:   http.MethodPost,
+			path:     "/reminders/42",
+			loggedIn: true,
+			form:     url.Values{"text": {"buy bread"}, "remind_at": {"2024-05-01T14:00"}},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+				store.UpdateReminderTextFunc = func(context.Context, int64, string) error {
+					return nil
+				}
+			},
+			expStatus:   http.StatusSeeOther,
+			expLocation: "/reminders/42?saved",
+		},
+		{
+			name:     "error: update reminder, empty text",
+			method:   http.MethodPost,
+			path:     "/reminders/42",
+			loggedIn: true,
+			form:     url.Values{"text": {"  "}, "remind_at": {"2024-05-01T14:00"}},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+				store.GetReminderEventsFunc = func(context.Context, int64) ([]domain.ReminderEvent, error) {
+					return nil, nil
+				}
+			},
+			expStatus: http.StatusUnprocessableEntity,
+			expBody:   []string{"Текст напоминания не может быть пустым."},
+		},
+		{
+			name:     "error: update reminder, time in the past",
+			method:   http.MethodPost,
+			path:     "/reminders/42",
+			loggedIn: true,
+			form:     url.Values{"text": {"buy milk"}, "remind_at": {"2024-05-01T12:00"}},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+				store.GetReminderEventsFunc = func(context.Context, int64) ([]domain.ReminderEvent, error) {
+					return nil, nil
+				}
+			},
+			expStatus: http.StatusUnprocessableEntity,
+			expBody:   []string{"Время напоминания должно быть в будущем.", `value="2024-05-01T12:00"`},
+		},
+		{
+			name:     "error: update reminder, time of done reminder",
+			method:   http.MethodPost,
+			path:     "/reminders/42",
+			loggedIn: true,
+			form:     url.Values{"text": {"buy milk"}, "remind_at": {"2024-05-02T12:00"}},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = func(context.Context, int64) (domain.Reminder, error) {
+					done := reminder
+					done.Status = domain.ReminderStatusDone
+					return done, nil
+				}
+				store.GetReminderEventsFunc = func(context.Context, int64) ([]domain.ReminderEvent, error) {
+					return nil, nil
+				}
+			},
+			expStatus: http.StatusUnprocessableEntity,
+			expBody:   []string{"Время нельзя изменить, напоминание выполнено.", "readonly"},
+		},
+		{
+			name:     "error: update reminder, invalid time",
+			method:   http.MethodPost,
+			path:     "/reminders/42",
+			loggedIn: true,
+			form:     url.Values{"text": {"buy milk"}, "remind_at": {"tomorrow"}},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetReminderFunc = getReminder
+				store.GetReminderEventsFunc = func(context.Context, int64) ([]domain.ReminderEvent, error) {
+					return nil, nil
+				}
+			},
+			expStatus: http.StatusUnprocessableEntity,
+			expBody:   []string{"Не удалось разобрать время напоминания."},
+		},
+		{
+			name:      "error: form is posted from another site",
+			method:    http.MethodPost,
+			path:      "/reminders/42",
+			loggedIn:  true,
+			origin:    "https://evil.example.com",
+			form:      url.Values{"text": {"buy milk"}},
+			expStatus: http.StatusForbidden,
+		},
+		{
+			name:     "success: show settings",
+			method:   http.MethodGet,
+			path:     "/settings",
+			loggedIn: true,
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetUserSettingsFunc = func(_ context.Context, actUserID int64) (domain.UserSettings, error) {
+					a.EqualValues(userID, actUserID)
+					return domain.UserSettings{
+						UserID:       userID,
+						QuickOptions: domain.QuickOptions{"10:00", "1h"},
+						Channels:     domain.Channels{domain.ChannelTelegram, domain.ChannelEmail},
+						Email:        "user@example.com",
+					}, nil
+				}
+			},
+			expStatus: http.StatusOK,
+			expBody: []string{
+				`name="quick_options" value="10:00,1h"`,
+				`name="snooze_options" value="" placeholder="30m,80m,3h,1d,1w,730h"`,
+				`name="channels" value="telegram,email"`,
+				`name="email" type="email" value="user@example.com"`,
+				`Адрес не подтверждён`,
+				`name="push_topic" value=""`,
+			},
+		},
+		{
+			name:     "success: update settings",
+			method:   http.MethodPost,
+			path:     "/settings",
+			loggedIn: true,
+			form:     url.Values{"quick_options": {"10:00, 1h"}, "snooze_options": {""}},
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetUserSettingsFunc = func(context.Context, int64) (domain.UserSettings, error) {
+					return domain.UserSettings{UserID: userID, SnoozeOptions: domain.QuickOptions{"1w"}}, nil
+				}
+				store.SaveUserSettingsFunc = func(_ context.Context, settings domain.UserSettings, _ ...domain.OutboxMessage) error {
+					a.Equal(domain.UserSettings{UserID: userID, QuickOptions: domain.QuickOptions{"10:00", "1h"}}, settings)
+					return nil
+				}
+			},
+			expStatus:   http.StatusSeeOther,
+			expLocation: "/settings?saved",
+		},
+		{
+			name:     "error: update settings, invalid options",
+			method:   http.MethodPost,
+			path:     "/settings",
+			loggedIn: true,
+			form:     url.Values{"quick_options": {"soon"}},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetUserSettingsFunc = func(context.Context, int64) (domain.UserSettings, error) {
+					return domain.UserSettings{UserID: userID}, nil
+				}
+			},
+			expStatus: http.StatusUnprocessableEntity,
+			expBody:   []string{"Не удалось разобрать кнопки создания: unknown quick option &#34;soon&#34;."},
+		},
+		{
+			name:     "success: update settings, channels",
+			method:   http.MethodPost,
+			path:     "/settings",
+			loggedIn: true,
+			form: url.Values{
+				"channels":    {"telegram, push"},
+				"email":       {""},
+				"webhook_url": {" https://example.com/hook "},
+				"push_topic":  {"reminders"},
+			},
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetUserSettingsFunc = func(context.Context, int64) (domain.UserSettings, error) {
+					return domain.UserSettings{UserID: userID, Email: "user@example.com"}, nil
+				}
+				store.SaveUserSettingsFunc = func(_ context.Context, settings domain.UserSettings, _ ...domain.OutboxMessage) error {
+					// new webhook url gets new secret
+					a.True(strings.HasPrefix(settings.WebhookSecret, domain.WebhookSecretPrefix))
+					settings.WebhookSecret = ""
+					a.Equal(domain.UserSettings{
+						UserID:     userID,
+						Channels:   domain.Channels{domain.ChannelTelegram, domain.ChannelPush},
+						WebhookURL: "https://example.com/hook",
+						PushTopic:  "reminders",
+					}, settings)
+					return nil
+				}
+			},
+			expStatus: http.StatusOK,
+			expBody: []string{
+				"Настройки сохранены.",
+				`name="webhook_url" type="url" value="https://example.com/hook"`,
+				"Секрет для проверки подписи вебхука: <code>whsec_",
+			},
+		},
+		{
+			name:     "success: update settings, webhook url isn't changed",
+			method:   http.MethodPost,
+			path:     "/settings",
+			loggedIn: true,
+			form:     url.Values{"webhook_url": {"https://example.com/hook"}},
+			setMocks: func(a *assert.Assertions, store *StorageMock) {
+				store.GetUserSettingsFunc = func(context.Context, int64) (domain.UserSettings, error) {
+					return domain.UserSettings{UserID: userID, WebhookURL: "https://example.com/hook", WebhookSecret: "whsec_test"}, nil
+				}
+				store.SaveUserSettingsFunc = func(_ context.Context, settings domain.UserSettings, _ ...domain.OutboxMessage) error {
+					a.Equal(domain.UserSettings{UserID: userID, WebhookURL: "https://example.com/hook", WebhookSecret: "whsec_test"}, settings)
+					return nil
+				}
+			},
+			expStatus:   http.StatusSeeOther,
+			expLocation: "/settings?saved",
+		},
+		{
+			name:     "error: update settings, unknown channel",
+			method:   http.MethodPost,
+			path:     "/settings",
+			loggedIn: true,
+			form:     url.Values{"channels": {"telegram, sms"}},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetUserSettingsFunc = func(context.Context, int64) (domain.UserSettings, error) {
+					return domain.UserSettings{UserID: userID}, nil
+				}
+			},
+			expStatus: http.StatusUnprocessableEntity,
+			expBody:   []string{"Не удалось разобрать каналы: unknown channel &#34;sms&#34;.", `name="channels" value="telegram, sms"`},
+		},
+		{
+			name:     "error: update settings, invalid webhook url",
+			method:   http.MethodPost,
+			path:     "/settings",
+			loggedIn: true,
+			form:     url.Values{"webhook_url": {"example.com/hook"}},
+			setMocks: func(_ *assert.Assertions, store *StorageMock) {
+				store.GetUserSettingsFunc = func(context.Context, int64) (domain.UserSettings, error) {
+					return domain.UserSettings{UserID: userID}, nil
+				}
+			},
+			expStatus: http.StatusUnprocessableEntity,
+			expBody:   []string{"Неверный адрес канала Вебхук:"},
+		},
+	}
+
+	tmpTimeNowUTC, tmpNewSessionToken := timeNowUTC, newSessionToken
+	defer func() {
+		timeNowUTC, newSessionToken = tmpTimeNowUTC, tmpNewSessionToken
+	}()
+	timeNowUTC = func() time.Time { return now }
+	newSessionToken = func() (string, error) { return sessionToken, nil }
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+			store := &StorageMock{
+				GetWebSessionFunc: func(_ context.Context, tokenHash string) (domain.WebSession, error) {
+					if tokenHash != session.TokenHash {
+						return domain.WebSession{}, storage.ErrWebSessionNotFound
+					}
+					return session, nil
+				},
+			}
+			if tc.setMocks != nil {
+				tc.setMocks(a, store)
+			}
+
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.form.Encode()))
+			if tc.form != nil {
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+			if tc.loggedIn {
+				req.AddCookie(&http.Cookie{Name: sessionCookie, Value: sessionToken})
+			}
+			if tc.origin != "" {
+				req.Header.Set("Origin", tc.origin)
+			}
+			rec := httptest.NewRecorder()
+
+			New(store, Config{}).Handler().ServeHTTP(rec, req)
+
+			a.Equal(tc.expStatus, rec.Code)
+			a.Len(rec.Header().Get("X-Correlation-ID"), 16)
+			a.Equal(tc.expLocation, rec.Header().Get("Location"))
+			a.Equal(tc.expCookie, rec.Header().Get("Set-Cookie"))
+			for _, exp := range tc.expBody {
+				a.Contains(rec.Body.String(), exp)
+			}
+		})
+	}
+}
+
+func TestServer_Static(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	New(&StorageMock{}, Config{}).Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/static/style.css", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/css")
+}
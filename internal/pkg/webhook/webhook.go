@@ -0,0 +1,154 @@
+// Package webhook delivers reminder lifecycle events to webhooks registered by users and operators.
+// Events are enqueued by storage in transaction of reminder change, [Dispatcher] sends them with retries
+// and HMAC signature, see [delivery.Post].
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/go-pkgz/lgr"
+	"github.com/mezk/tg-reminder/internal/pkg/delivery"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/mezk/tg-reminder/internal/pkg/logging"
+	"github.com/mezk/tg-reminder/internal/pkg/sender"
+	"github.com/mezk/tg-reminder/internal/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var timeNowUTC = func() time.Time {
+	return time.Now().UTC()
+}
+
+// Headers of webhook request, besides signature headers of [delivery.Post].
+const (
+	HeaderEvent    = "X-Reminder-Event"
+	HeaderDelivery = "X-Reminder-Delivery"
+)
+
+// Storage - storage of webhook deliveries.
+type Storage interface {
+	GetDueWebhookDeliveries(ctx context.Context, limit int64) ([]domain.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, d domain.WebhookDelivery) error
+}
+
+// Config - dispatcher configuration.
+type Config struct {
+	Interval    time.Duration // how often to check for deliveries to send
+	BatchSize   int64         // max number of deliveries sent per check
+	MaxAttempts int           // number of delivery attempts before delivery is dead
+	MaxBackoff  time.Duration // max delay between delivery attempts
+	Timeout     time.Duration // max time of one request to webhook
+}
+
+// Dispatcher sends enqueued webhook deliveries. Events are delivered to every webhook in order they happened,
+// failed delivery is retried with exponential backoff and blocks the next deliveries of its webhook only.
+// Delivery rejected by webhook is dead right away, see [delivery.Post].
+type Dispatcher struct {
+	store  Storage
+	client *http.Client
+	cfg    Config
+}
+
+// New creates new [Dispatcher]. Webhook URLs are set by users, so requests to addresses of the bot's own network
+// are refused, see [delivery.NewClient].
+func New(store Storage, cfg Config) *Dispatcher {
+	return &Dispatcher{store: store, client: delivery.NewClient(cfg.Timeout), cfg: cfg}
+}
+
+// Run starts infinite loop to send enqueued deliveries. Breaks infinite loop on context error.
+// Deliveries which are not sent before shutdown are sent after restart.
+func (d *Dispatcher) Run(ctx context.Context) {
+	log.Printf("[INFO] webhook dispatcher started, interval %s, batch size %d, max attempts %d", d.cfg.Interval, d.cfg.BatchSize, d.cfg.MaxAttempts)
+
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("[INFO] webhook dispatcher is shutting down")
+			return
+		case <-ticker.C:
+		}
+
+		// only the oldest delivery of every webhook is sent per batch, so batches are repeated until nothing is due
+		for ctx.Err() == nil {
+			// delivery in progress is finished on shutdown, otherwise it's sent twice
+			if delivered := d.deliverBatch(context.WithoutCancel(ctx)); delivered == 0 {
+				break
+			}
+		}
+	}
+}
+
+// deliverBatch sends due deliveries. Returns number of delivered ones.
+func (d *Dispatcher) deliverBatch(ctx context.Context) int {
+	deliveries, err := d.store.GetDueWebhookDeliveries(ctx, d.cfg.BatchSize)
+	if err != nil {
+		log.Printf("[ERROR] failed to fetch webhook deliveries: %v", err)
+		return 0
+	}
+
+	var delivered int
+
+	for _, wd := range deliveries {
+		ctx := logging.ContextWithCorrelationID(ctx, wd.CorrelationID)
+
+		wd.Attempts++
+		wd.ResponseStatus, err = d.deliver(ctx, wd)
+
+		switch {
+		case err == nil:
+			delivered++
+			wd.Status, wd.LastError = domain.WebhookDeliveryStatusDelivered, ""
+			logging.Printf(ctx, "[DEBUG] delivered webhook delivery %s", wd)
+		case errors.Is(err, sender.ErrPermanent):
+			wd.Status, wd.LastError = domain.WebhookDeliveryStatusDead, err.Error()
+			logging.Printf(ctx, "[ERROR] webhook delivery %s is rejected, it's dead: %v", wd, err)
+		case wd.Attempts >= d.cfg.MaxAttempts:
+			wd.Status, wd.LastError = domain.WebhookDeliveryStatusDead, err.Error()
+			logging.Printf(ctx, "[ERROR] webhook delivery %s is dead after %d attempts: %v", wd, wd.Attempts, err)
+		default:
+			delay := delivery.RetryDelay(wd.Attempts, d.cfg.MaxBackoff, err)
+			wd.NextAttemptAt, wd.LastError = timeNowUTC().Add(delay), err.Error()
+			logging.Printf(ctx, "[WARN] failed to deliver webhook delivery %s, retry in %s: %v", wd, delay, err)
+		}
+
+		if err = d.store.UpdateWebhookDelivery(ctx, wd); err != nil {
+			logging.Printf(ctx, "[ERROR] failed to update webhook delivery %s: %v", wd, err)
+		}
+	}
+
+	return delivered
+}
+
+// deliver sends signed payload of delivery to its webhook, returns response status, 0 if there is no response.
+func (d *Dispatcher) deliver(ctx context.Context, wd domain.WebhookDelivery) (status int, err error) {
+	ctx, span := tracing.Start(ctx, "webhook.deliver", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.Int64("webhook.id", wd.WebhookID),
+		attribute.Int64("webhook.delivery_id", wd.ID),
+		attribute.String("webhook.event", string(wd.Event)),
+		attribute.Int("webhook.attempts", wd.Attempts),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	status, err = delivery.Post(ctx, d.client, delivery.Request{
+		URL:  wd.URL,
+		Body: []byte(wd.Payload),
+		Header: http.Header{
+			HeaderEvent:    {string(wd.Event)},
+			HeaderDelivery: {strconv.FormatInt(wd.ID, 10)},
+		},
+		Secret: wd.Secret,
+	})
+	if status != 0 {
+		span.SetAttributes(attribute.Int("http.status_code", status))
+	}
+
+	return status, err
+}
@@ -0,0 +1,178 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mezk/tg-reminder/internal/pkg/delivery"
+	"github.com/mezk/tg-reminder/internal/pkg/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcher_deliverBatch(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tmpTimeNowUTC := timeNowUTC
+	defer func() { timeNowUTC = tmpTimeNowUTC }()
+	timeNowUTC = func() time.Time { return now }
+
+	const payload = `{"event":"reminder.completed"}`
+
+	testCases := []struct {
+		name         string
+		delivery     domain.WebhookDelivery
+		respStatus   int
+		respHeader   http.Header
+		respBody     string
+		expDelivered int
+		expUpdated   domain.WebhookDelivery
+	}{
+		{
+			name:         "success: event is delivered",
+			delivery:     domain.WebhookDelivery{ID: 1, WebhookID: 7, Event: domain.WebhookEventCompleted, Payload: payload, Status: domain.WebhookDeliveryStatusPending},
+			respStatus:   http.StatusNoContent,
+			expDelivered: 1,
+			expUpdated: domain.WebhookDelivery{
+				ID: 1, WebhookID: 7, Event: domain.WebhookEventCompleted, Payload: payload,
+				Status: domain.WebhookDeliveryStatusDelivered, Attempts: 1, ResponseStatus: http.StatusNoContent,
+			},
+		},
+		{
+			name:       "error: webhook failed, retry with backoff",
+			delivery:   domain.WebhookDelivery{ID: 1, WebhookID: 7, Event: domain.WebhookEventCompleted, Payload: payload, Status: domain.WebhookDeliveryStatusPending, Attempts: 1},
+			respStatus: http.StatusInternalServerError,
+			respBody:   "oops\n",
+			expUpdated: domain.WebhookDelivery{
+				ID: 1, WebhookID: 7, Event: domain.WebhookEventCompleted, Payload: payload,
+				Status: domain.WebhookDeliveryStatusPending, Attempts: 2, ResponseStatus: http.StatusInternalServerError,
+				NextAttemptAt: now.Add(10 * time.Second), LastError: "unexpected response status 500: oops",
+			},
+		},
+		{
+			name:       "error: retry after requested by webhook",
+			delivery:   domain.WebhookDelivery{ID: 1, WebhookID: 7, Event: domain.WebhookEventCompleted, Payload: payload, Status: domain.WebhookDeliveryStatusPending},
+			respStatus: http.StatusTooManyRequests,
+			respHeader: http.Header{"Retry-After": {"60"}},
+			expUpdated: domain.WebhookDelivery{
+				ID: 1, WebhookID: 7, Event: domain.WebhookEventCompleted, Payload: payload,
+				Status: domain.WebhookDeliveryStatusPending, Attempts: 1, ResponseStatus: http.StatusTooManyRequests,
+				NextAttemptAt: now.Add(time.Minute), LastError: "retry after 1m0s: unexpected response status 429",
+			},
+		},
+		{
+			name:       "error: rejected by webhook, delivery is dead",
+			delivery:   domain.WebhookDelivery{ID: 1, WebhookID: 7, Event: domain.WebhookEventCompleted, Payload: payload, Status: domain.WebhookDeliveryStatusPending},
+			respStatus: http.StatusGone,
+			expUpdated: domain.WebhookDelivery{
+				ID: 1, WebhookID: 7, Event: domain.WebhookEventCompleted, Payload: payload,
+				Status: domain.WebhookDeliveryStatusDead, Attempts: 1, ResponseStatus: http.StatusGone,
+				LastError: "message is rejected: unexpected response status 410",
+			},
+		},
+		{
+			name:       "error: attempts are exhausted, delivery is dead",
+			delivery:   domain.WebhookDelivery{ID: 1, WebhookID: 7, Event: domain.WebhookEventCompleted, Payload: payload, Status: domain.WebhookDeliveryStatusPending, Attempts: 2},
+			respStatus: http.StatusBadGateway,
+			expUpdated: domain.WebhookDelivery{
+				ID: 1, WebhookID: 7, Event: domain.WebhookEventCompleted, Payload: payload,
+				Status: domain.WebhookDeliveryStatusDead, Attempts: 3, ResponseStatus: http.StatusBadGateway,
+				LastError: "unexpected response status 502",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, payload, string(body))
+
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, "reminder.completed", r.Header.Get(HeaderEvent))
+				assert.Equal(t, "1", r.Header.Get(HeaderDelivery))
+
+				ts, err := strconv.ParseInt(r.Header.Get(delivery.HeaderTimestamp), 10, 64)
+				assert.NoError(t, err)
+				expSignature := domain.SignWebhookPayload("whsec_test", time.Unix(ts, 0), []byte(payload))
+				assert.Equal(t, "sha256="+expSignature, r.Header.Get(delivery.HeaderSignature))
+
+				for k, v := range tc.respHeader {
+					w.Header()[k] = v
+				}
+				w.WriteHeader(tc.respStatus)
+				_, _ = w.Write([]byte(tc.respBody))
+			}))
+			defer srv.Close()
+
+			wd := tc.delivery
+			wd.URL, wd.Secret = srv.URL, "whsec_test"
+
+			store := &StorageMock{
+				GetDueWebhookDeliveriesFunc: func(_ context.Context, limit int64) ([]domain.WebhookDelivery, error) {
+					assert.EqualValues(t, 10, limit)
+					return []domain.WebhookDelivery{wd}, nil
+				},
+				UpdateWebhookDeliveryFunc: func(context.Context, domain.WebhookDelivery) error {
+					return nil
+				},
+			}
+
+			d := New(store, Config{BatchSize: 10, MaxAttempts: 3, MaxBackoff: time.Hour, Timeout: time.Second})
+			d.client = srv.Client() // test server listens on loopback
+
+			assert.Equal(t, tc.expDelivered, d.deliverBatch(context.Background()))
+
+			require.Len(t, store.UpdateWebhookDeliveryCalls(), 1)
+			updated := store.UpdateWebhookDeliveryCalls()[0].D
+			updated.URL, updated.Secret = "", ""
+			assert.Equal(t, tc.expUpdated, updated)
+		})
+	}
+}
+
+func TestDispatcher_deliverBatch_storageError(t *testing.T) {
+	t.Parallel()
+
+	store := &StorageMock{
+		GetDueWebhookDeliveriesFunc: func(context.Context, int64) ([]domain.WebhookDelivery, error) {
+			return nil, errors.New("database is locked")
+		},
+	}
+
+	assert.Zero(t, New(store, Config{BatchSize: 10}).deliverBatch(context.Background()))
+}
+
+func TestDispatcher_deliverBatch_localAddress(t *testing.T) {
+	t.Parallel()
+
+	var called bool
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	store := &StorageMock{
+		GetDueWebhookDeliveriesFunc: func(context.Context, int64) ([]domain.WebhookDelivery, error) {
+			return []domain.WebhookDelivery{{ID: 1, WebhookID: 7, URL: srv.URL, Status: domain.WebhookDeliveryStatusPending}}, nil
+		},
+		UpdateWebhookDeliveryFunc: func(context.Context, domain.WebhookDelivery) error {
+			return nil
+		},
+	}
+
+	assert.Zero(t, New(store, Config{BatchSize: 10, MaxAttempts: 3, Timeout: time.Second}).deliverBatch(context.Background()))
+
+	require.Len(t, store.UpdateWebhookDeliveryCalls(), 1)
+	updated := store.UpdateWebhookDeliveryCalls()[0].D
+	assert.Equal(t, domain.WebhookDeliveryStatusDead, updated.Status)
+	assert.Contains(t, updated.LastError, "local address is not allowed")
+	assert.False(t, called)
+}
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS invite_codes
+(
+    code       TEXT PRIMARY KEY,
+    created_by INTEGER   NOT NULL,
+    created_at TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+    used_by    INTEGER   NULL,
+    used_at    TIMESTAMP NULL
+);
+
+-- +goose Down
+DROP TABLE invite_codes;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE reminders ADD COLUMN priority TEXT NOT NULL DEFAULT 'normal';
+
+-- +goose Down
+ALTER TABLE reminders DROP COLUMN priority;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE reminders ADD COLUMN lead_times TEXT NOT NULL DEFAULT '';
+ALTER TABLE reminders ADD COLUMN next_pre_notice_at TIMESTAMP NULL;
+
+-- +goose Down
+ALTER TABLE reminders DROP COLUMN next_pre_notice_at;
+ALTER TABLE reminders DROP COLUMN lead_times;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS checklist_items
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    reminder_id INTEGER   NOT NULL REFERENCES reminders (id) ON DELETE CASCADE,
+    position    INTEGER   NOT NULL,
+    text        TEXT      NOT NULL,
+    done        BOOLEAN   NOT NULL DEFAULT FALSE,
+    created_at  TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS checklist_items_reminder_id_idx ON checklist_items (reminder_id);
+
+-- +goose Down
+DROP TABLE checklist_items;
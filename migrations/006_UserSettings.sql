@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_settings
+(
+    user_id        INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    quick_options  TEXT      NOT NULL DEFAULT '',
+    snooze_options TEXT      NOT NULL DEFAULT '',
+    created_at     TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at    TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE user_settings;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS update_offset
+(
+    id          INTEGER PRIMARY KEY CHECK (id = 1),
+    update_id   INTEGER   NOT NULL,
+    modified_at TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS processed_updates
+(
+    update_id  INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE processed_updates;
+DROP TABLE update_offset;
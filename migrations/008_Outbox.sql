@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    chat_id         INTEGER   NOT NULL,
+    payload         TEXT      NOT NULL,
+    status          TEXT      NOT NULL DEFAULT 'pending',
+    attempts        INTEGER   NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error      TEXT      NOT NULL DEFAULT '',
+    created_at      TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at     TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS outbox_status_chat_id ON outbox (status, chat_id, id);
+
+-- +goose Down
+DROP INDEX outbox_status_chat_id;
+DROP TABLE outbox;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS reminder_events
+(
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    reminder_id    INTEGER   NOT NULL,
+    type           TEXT      NOT NULL,
+    actor          TEXT      NOT NULL,
+    actor_id       INTEGER   NOT NULL DEFAULT 0,
+    attempt        INTEGER   NOT NULL DEFAULT 0,
+    remind_at_from TIMESTAMP NULL,
+    remind_at_to   TIMESTAMP NULL,
+    created_at     TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS reminder_events_reminder_id ON reminder_events (reminder_id, id);
+
+-- +goose Down
+DROP INDEX reminder_events_reminder_id;
+DROP TABLE reminder_events;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS api_tokens
+(
+    token_hash TEXT PRIMARY KEY,
+    user_id    INTEGER   NOT NULL,
+    chat_id    INTEGER   NOT NULL,
+    created_at TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS api_tokens_user_id ON api_tokens (user_id);
+
+-- +goose Down
+DROP INDEX api_tokens_user_id;
+DROP TABLE api_tokens;
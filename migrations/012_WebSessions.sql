@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS web_sessions
+(
+    token_hash TEXT PRIMARY KEY,
+    kind       TEXT      NOT NULL,
+    user_id    INTEGER   NOT NULL,
+    chat_id    INTEGER   NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS web_sessions_expires_at ON web_sessions (expires_at);
+
+-- +goose Down
+DROP INDEX web_sessions_expires_at;
+DROP TABLE web_sessions;
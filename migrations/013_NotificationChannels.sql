@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE outbox ADD COLUMN channel TEXT NOT NULL DEFAULT 'telegram';
+DROP INDEX outbox_status_chat_id;
+CREATE INDEX IF NOT EXISTS outbox_status_chat_id_channel ON outbox (status, chat_id, channel, id);
+
+ALTER TABLE reminders ADD COLUMN channels TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE user_settings ADD COLUMN channels TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_settings ADD COLUMN email TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_settings ADD COLUMN webhook_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_settings ADD COLUMN push_topic TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE user_settings DROP COLUMN push_topic;
+ALTER TABLE user_settings DROP COLUMN webhook_url;
+ALTER TABLE user_settings DROP COLUMN email;
+ALTER TABLE user_settings DROP COLUMN channels;
+
+ALTER TABLE reminders DROP COLUMN channels;
+
+DROP INDEX outbox_status_chat_id_channel;
+CREATE INDEX IF NOT EXISTS outbox_status_chat_id ON outbox (status, chat_id, id);
+ALTER TABLE outbox DROP COLUMN channel;
@@ -0,0 +1,39 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS webhooks
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id     INTEGER   NOT NULL DEFAULT 0,
+    url         TEXT      NOT NULL,
+    secret      TEXT      NOT NULL,
+    events      TEXT      NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS webhooks_user_id ON webhooks (user_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id      INTEGER   NOT NULL,
+    event           TEXT      NOT NULL,
+    payload         TEXT      NOT NULL,
+    status          TEXT      NOT NULL DEFAULT 'pending',
+    attempts        INTEGER   NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    response_status INTEGER   NOT NULL DEFAULT 0,
+    last_error      TEXT      NOT NULL DEFAULT '',
+    correlation_id  TEXT      NOT NULL DEFAULT '',
+    created_at      TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at     TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_status_webhook_id ON webhook_deliveries (status, webhook_id, id);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, id);
+
+-- +goose Down
+DROP INDEX webhook_deliveries_webhook_id;
+DROP INDEX webhook_deliveries_status_webhook_id;
+DROP TABLE webhook_deliveries;
+DROP INDEX webhooks_user_id;
+DROP TABLE webhooks;
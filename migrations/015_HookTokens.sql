@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE api_tokens ADD COLUMN kind TEXT NOT NULL DEFAULT 'api';
+
+-- +goose Down
+ALTER TABLE api_tokens DROP COLUMN kind;
@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE reminders ADD COLUMN summary_key TEXT NULL;
+
+CREATE INDEX IF NOT EXISTS reminders_summary_key ON reminders (summary_key);
+
+-- +goose Down
+DROP INDEX reminders_summary_key;
+ALTER TABLE reminders DROP COLUMN summary_key;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE outbox ADD COLUMN secret BOOLEAN NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE outbox DROP COLUMN secret;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE user_settings ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE user_settings ADD COLUMN email_code TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE user_settings DROP COLUMN email_code;
+ALTER TABLE user_settings DROP COLUMN email_verified;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE user_settings ADD COLUMN webhook_secret TEXT NOT NULL DEFAULT '';
+-- webhooks set before requests were signed get secret, user gets it by setting webhook URL again
+UPDATE user_settings SET webhook_secret = 'whsec_' || lower(hex(randomblob(24))) WHERE webhook_url != '';
+
+-- +goose Down
+ALTER TABLE user_settings DROP COLUMN webhook_secret;
@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS maintenance
+(
+    id          INTEGER PRIMARY KEY CHECK (id = 1),
+    enabled     BOOLEAN   NOT NULL,
+    modified_at TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE maintenance;